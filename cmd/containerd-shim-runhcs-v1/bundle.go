@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// bundleSpec is the `config.json` of a task bundle as read at create time.
+type bundleSpec struct {
+	// bundle is the on disk path to the folder containing the `config.json`.
+	bundle string
+	// digest is the sha256 of the `config.json` contents.
+	digest [sha256.Size]byte
+	// uvmScratch is the folder holding the scratch of the UVM created for
+	// this task, if any. It is removed when the task is deleted.
+	uvmScratch string
+}
+
+// readBundleSpec reads and decodes the `config.json` in `bundle` and records
+// its digest so that it can later be verified by `verify`.
+func readBundleSpec(bundle string) (specs.Spec, *bundleSpec, error) {
+	var spec specs.Spec
+	b, err := ioutil.ReadFile(filepath.Join(bundle, "config.json"))
+	if err != nil {
+		return spec, nil, err
+	}
+	if err := json.NewDecoder(bytes.NewReader(b)).Decode(&spec); err != nil {
+		return spec, nil, err
+	}
+	return spec, &bundleSpec{bundle: bundle, digest: sha256.Sum256(b)}, nil
+}
+
+// verify returns a `*bundleModifiedError` if the `config.json` in the bundle
+// no longer matches the contents read at create time.
+//
+// Exec processes are not verified because their spec is passed by value in
+// the `Exec` request rather than read from the bundle.
+func (bs *bundleSpec) verify(tid string) error {
+	path := filepath.Join(bs.bundle, "config.json")
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if sha256.Sum256(b) != bs.digest {
+		return &bundleModifiedError{tid: tid, path: path}
+	}
+	return nil
+}
+
+// bundleModifiedError is returned from `Start` if the task bundle was
+// modified after the task was created.
+type bundleModifiedError struct {
+	tid  string
+	path string
+}
+
+func (e *bundleModifiedError) Error() string {
+	return fmt.Sprintf("task: '%s' bundle '%s' was modified between create and start: %s", e.tid, e.path, errdefs.ErrFailedPrecondition)
+}
+
+// Cause returns `errdefs.ErrFailedPrecondition` so that the error is
+// translated to the correct gRPC status.
+func (e *bundleModifiedError) Cause() error {
+	return errdefs.ErrFailedPrecondition
+}
+
+// execStateDir is the folder in the bundle that the exit state of each exec is
+// persisted to.
+const execStateDir = "state"
+
+// execState is the state of an exec as persisted to the bundle. It allows a
+// `delete` issued after the shim is gone to report the real exit status of the
+// init exec, and a later shim to reattach to a running init exec. `ExitedAt`
+// is zero until the exec exits.
+type execState struct {
+	Pid        int       `json:"pid"`
+	ExitStatus uint32    `json:"exitStatus"`
+	ExitedAt   time.Time `json:"exitedAt"`
+}
+
+func execStatePath(bundle, eid string) string {
+	return filepath.Join(bundle, execStateDir, eid+".json")
+}
+
+// writeExecState persists `state` for the exec `eid` to `bundle`. The file is
+// replaced atomically so a reader never observes a partial write.
+func writeExecState(bundle, eid string, state *execState) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	path := execStatePath(bundle, eid)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// readExecState reads the state of the exec `eid` persisted to `bundle`. If
+// no state was persisted for the exec the returned error satisfies
+// `os.IsNotExist`.
+func readExecState(bundle, eid string) (*execState, error) {
+	b, err := ioutil.ReadFile(execStatePath(bundle, eid))
+	if err != nil {
+		return nil, err
+	}
+	state := &execState{}
+	if err := json.Unmarshal(b, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/pkg/errors"
+)
+
+func writeTestBundle(t *testing.T, config string) string {
+	bundle, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(bundle, "config.json"), []byte(config), 0644); err != nil {
+		os.RemoveAll(bundle)
+		t.Fatal(err)
+	}
+	return bundle
+}
+
+func Test_BundleSpec_Unmodified(t *testing.T) {
+	bundle := writeTestBundle(t, `{"ociVersion":"1.0.1"}`)
+	defer os.RemoveAll(bundle)
+
+	spec, bs, err := readBundleSpec(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.Version != "1.0.1" {
+		t.Fatalf("expected version 1.0.1, got %s", spec.Version)
+	}
+	if err := bs.verify(t.Name()); err != nil {
+		t.Fatalf("expected unmodified bundle to verify, got %v", err)
+	}
+}
+
+func Test_BundleSpec_Modified(t *testing.T) {
+	bundle := writeTestBundle(t, `{"ociVersion":"1.0.1"}`)
+	defer os.RemoveAll(bundle)
+
+	_, bs, err := readBundleSpec(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(bundle, "config.json"), []byte(`{"ociVersion":"1.0.2"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = bs.verify(t.Name())
+	if _, ok := err.(*bundleModifiedError); !ok {
+		t.Fatalf("expected *bundleModifiedError, got %v", err)
+	}
+	if !errdefs.IsFailedPrecondition(errors.Cause(err)) {
+		t.Fatalf("expected failed precondition cause, got %v", errors.Cause(err))
+	}
+}
+
+func Test_ExecState_RoundTrip(t *testing.T) {
+	bundle := writeTestBundle(t, `{"ociVersion":"1.0.1"}`)
+	defer os.RemoveAll(bundle)
+
+	if _, err := readExecState(bundle, t.Name()); !os.IsNotExist(err) {
+		t.Fatalf("expected not exist error, got %v", err)
+	}
+	exitedAt := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := writeExecState(bundle, t.Name(), &execState{Pid: 10, ExitStatus: 3, ExitedAt: exitedAt}); err != nil {
+		t.Fatal(err)
+	}
+	state, err := readExecState(bundle, t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Pid != 10 || state.ExitStatus != 3 || !state.ExitedAt.Equal(exitedAt) {
+		t.Fatalf("unexpected exec state: %+v", state)
+	}
+}
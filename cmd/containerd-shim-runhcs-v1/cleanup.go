@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/hcserror"
+	"github.com/Microsoft/hcsshim/internal/hcsoci"
+	"github.com/Microsoft/hcsshim/internal/oci"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/Microsoft/hcsshim/internal/wclayer"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// removeRetries is the number of times removal of a path is attempted
+	// when it fails because a file is still in use.
+	removeRetries = 6
+	// removeBackoff is the time waited after the first failed removal. It
+	// doubles on each retry.
+	removeBackoff = time.Millisecond * 100
+)
+
+// uvmScratchPath returns the path of the folder holding the scratch
+// `sandbox.vhdx` of the WCOW UVM created to host the container in `s`. The
+// folder is placed in the container scratch layer so that it does not collide
+// with the container's own `sandbox.vhdx`.
+//
+// Returns "" if `s` does not describe a hypervisor isolated WCOW container.
+func uvmScratchPath(s *specs.Spec) string {
+	if !oci.IsWCOW(s) || !oci.IsIsolated(s) || len(s.Windows.LayerFolders) == 0 {
+		return ""
+	}
+	return filepath.Join(s.Windows.LayerFolders[len(s.Windows.LayerFolders)-1], "vm")
+}
+
+// isRetryableRemoveError returns `true` if removal of a path failed because a
+// file in it is still open, which is common while the HCS or vmwp is still
+// releasing a just closed VHDX.
+func isRetryableRemoveError(err error) bool {
+	switch e := err.(type) {
+	case *os.PathError:
+		err = e.Err
+	case *hcserror.HcsError:
+		err = e.Err
+	}
+	switch err {
+	case windows.ERROR_SHARING_VIOLATION, windows.ERROR_LOCK_VIOLATION, windows.ERROR_ACCESS_DENIED:
+		return true
+	}
+	return false
+}
+
+// retryInUse calls `op` until it succeeds. If it fails because a file is in
+// use it is retried with backoff. `what` describes the operation in the
+// returned error.
+func retryInUse(what string, op func() error) error {
+	backoff := removeBackoff
+	var err error
+	attempt := 1
+	for ; attempt <= removeRetries; attempt++ {
+		err = op()
+		if err == nil {
+			return nil
+		}
+		if !isRetryableRemoveError(err) {
+			break
+		}
+		logrus.WithFields(logrus.Fields{
+			"operation":     what,
+			"attempt":       attempt,
+			logrus.ErrorKey: err,
+		}).Debug("retryInUse - in use, retrying")
+		if attempt < removeRetries {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	if attempt > removeRetries {
+		attempt = removeRetries
+	}
+	return fmt.Errorf("failed to %s after %d attempt(s): %s", what, attempt, err)
+}
+
+// removeAllWithRetry removes `path` and everything it contains. If removal
+// fails because a file is in use it is retried with backoff. If `path` does
+// not exist it returns `nil`.
+func removeAllWithRetry(path string) error {
+	return retryInUse(fmt.Sprintf("remove '%s'", path), func() error {
+		err := os.RemoveAll(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	})
+}
+
+// unmountLayersWithRetry unmounts the layers of the process isolated WCOW
+// container in `s` that were mounted on the host when it was created. Each
+// step is retried with backoff while the layer is still in use. It returns
+// `nil` without doing anything for any other container.
+func unmountLayersWithRetry(s *specs.Spec) error {
+	if !oci.IsWCOW(s) || oci.IsIsolated(s) || len(s.Windows.LayerFolders) < 2 {
+		return nil
+	}
+	path := s.Windows.LayerFolders[len(s.Windows.LayerFolders)-1]
+	if err := retryInUse(fmt.Sprintf("unprepare layer '%s'", path), func() error {
+		return wclayer.UnprepareLayer(path)
+	}); err != nil {
+		return err
+	}
+	return retryInUse(fmt.Sprintf("deactivate layer '%s'", path), func() error {
+		return wclayer.DeactivateLayer(path)
+	})
+}
+
+// releaseResourcesWithRetry releases the resources `r` of a container in
+// `host`, or on the host if `nil`. Resources that were released are cleared
+// from `r` so a retry after a file was in use, typically a host mounted layer,
+// only releases what remains.
+func releaseResourcesWithRetry(r *hcsoci.Resources, host *uvm.UtilityVM) error {
+	return retryInUse("release container resources", func() error {
+		return hcsoci.ReleaseResources(r, host, true)
+	})
+}
+
+// removeUVMScratch removes the UVM scratch folder `path` of the task `t` once
+// its UVM has been closed. Failure is logged rather than returned as the task
+// itself has already been deleted.
+func removeUVMScratch(ctx context.Context, tid string, t shimTask, path string) {
+	log := logrus.WithFields(logrus.Fields{
+		"tid":  tid,
+		"path": path,
+	})
+	// The scratch is held open by the UVM until it is closed which happens
+	// before `Wait` returns.
+	ch := make(chan struct{})
+	go func() {
+		t.Wait(ctx)
+		close(ch)
+	}()
+	select {
+	case <-ch:
+	case <-ctx.Done():
+		log.WithError(ctx.Err()).Error("removeUVMScratch - timed out waiting for UVM to close, scratch leaked")
+		return
+	}
+	if err := removeAllWithRetry(path); err != nil {
+		log.WithError(err).Error("removeUVMScratch - failed to remove UVM scratch, scratch leaked")
+		return
+	}
+	log.Debug("removeUVMScratch - removed UVM scratch")
+}
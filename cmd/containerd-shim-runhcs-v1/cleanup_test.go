@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/hcserror"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/sys/windows"
+)
+
+func Test_uvmScratchPath_Argon(t *testing.T) {
+	s := &specs.Spec{
+		Windows: &specs.Windows{
+			LayerFolders: []string{"C:\\layer", "C:\\scratch"},
+		},
+	}
+	if p := uvmScratchPath(s); p != "" {
+		t.Fatalf("expected no path for argon, got: '%s'", p)
+	}
+}
+
+func Test_uvmScratchPath_Xenon(t *testing.T) {
+	s := &specs.Spec{
+		Windows: &specs.Windows{
+			LayerFolders: []string{"C:\\layer", "C:\\scratch"},
+			HyperV:       &specs.WindowsHyperV{},
+		},
+	}
+	expected := filepath.Join("C:\\scratch", "vm")
+	if p := uvmScratchPath(s); p != expected {
+		t.Fatalf("expected: '%s', got: '%s'", expected, p)
+	}
+}
+
+func Test_isRetryableRemoveError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{&os.PathError{Op: "remove", Path: "x", Err: windows.ERROR_SHARING_VIOLATION}, true},
+		{&os.PathError{Op: "remove", Path: "x", Err: windows.ERROR_LOCK_VIOLATION}, true},
+		{windows.ERROR_ACCESS_DENIED, true},
+		{hcserror.New(windows.ERROR_SHARING_VIOLATION, "UnprepareLayer", ""), true},
+		{&os.PathError{Op: "remove", Path: "x", Err: windows.ERROR_PATH_NOT_FOUND}, false},
+		{errors.New("test"), false},
+	}
+	for _, test := range tests {
+		if actual := isRetryableRemoveError(test.err); actual != test.expected {
+			t.Errorf("%v: expected: %v, got: %v", test.err, test.expected, actual)
+		}
+	}
+}
+
+func Test_removeAllWithRetry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "removeAllWithRetry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "sandbox.vhdx"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := removeAllWithRetry(dir); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("expected '%s' to be removed, got: %v", dir, err)
+	}
+	// Removing an already removed path is not an error.
+	if err := removeAllWithRetry(dir); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+}
+
+func Test_retryInUse_RetriesInUse(t *testing.T) {
+	attempts := 0
+	err := retryInUse("test", func() error {
+		attempts++
+		if attempts < 3 {
+			return windows.ERROR_SHARING_VIOLATION
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got: %d", attempts)
+	}
+}
+
+func Test_retryInUse_OtherError_NotRetried(t *testing.T) {
+	attempts := 0
+	err := retryInUse("test", func() error {
+		attempts++
+		return errors.New("test")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if attempts != 1 {
+		t.Fatalf("expected 1 attempt, got: %d", attempts)
+	}
+}
+
+func Test_unmountLayersWithRetry_Xenon_Noop(t *testing.T) {
+	s := &specs.Spec{
+		Windows: &specs.Windows{
+			LayerFolders: []string{"C:\\layer", "C:\\scratch"},
+			HyperV:       &specs.WindowsHyperV{},
+		},
+	}
+	if err := unmountLayersWithRetry(s); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+}
@@ -0,0 +1,445 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/oci"
+	"github.com/Microsoft/hcsshim/internal/reservation"
+	"github.com/Microsoft/hcsshim/internal/timeout"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/containerd/containerd/errdefs"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// featureOOMEvents gates publishing `TaskOOM` events for LCOW processes
+	// killed by the utility VM OOM killer.
+	featureOOMEvents = "OOMEvents"
+	// featureOrderedPodShutdown gates waiting for the workload tasks of a pod
+	// to exit before the sandbox task is signalled on a kill all of the pod.
+	// If disabled every task is signalled at once.
+	featureOrderedPodShutdown = "OrderedPodShutdown"
+
+	// defaultPodWorkloadStopTimeout is the time waited for the workload tasks
+	// of a pod to exit before the sandbox task is signalled regardless.
+	defaultPodWorkloadStopTimeout = time.Second * 30
+)
+
+// defaultFeatureGates is the state of every known feature gate when it is not
+// set in the config file.
+var defaultFeatureGates = map[string]bool{
+	featureOOMEvents:          true,
+	featureOrderedPodShutdown: true,
+}
+
+// shimConfig is the optional config file of shim tunables whose path is passed
+// in the `ConfigPath` shim option. It is reloaded when the
+// `Global\reloadconfig-<pid>` event is signalled or via shimdiag.
+type shimConfig struct {
+	// LogLevel is the logrus level to log at. If empty the level is left
+	// unchanged.
+	LogLevel string `json:"logLevel,omitempty"`
+	// Timeouts overrides the platform timeouts.
+	Timeouts configTimeouts `json:"timeouts,omitempty"`
+	// IOReconnectBufferSize is the maximum number of bytes of output buffered
+	// while an upstream stdio connection is disconnected. If `0` the default
+	// `reconnectBufferSize` is used.
+	IOReconnectBufferSize int `json:"ioReconnectBufferSize,omitempty"`
+	// TTYScrollbackSize is the number of bytes of the most recent output of a
+	// terminal replayed to a client that reconnects to its stdout. If `0` the
+	// default `ttyScrollbackSize` is used. If `-1` no output is replayed
+	// beyond that buffered while disconnected.
+	TTYScrollbackSize int `json:"ttyScrollbackSize,omitempty"`
+	// FeatureGates enables or disables features by name.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+	// Reservation, if set, records the memory and processors of each utility
+	// VM the shim creates in reservations shared by every shim on the host. A
+	// create that would exceed the limits fails rather than oversubscribing
+	// the host.
+	Reservation *configReservation `json:"reservation,omitempty"`
+	// UVMPool, if set, claims the utility VM of an LCOW pod from the pools of
+	// pre-booted utility VMs of a uvmpool sidecar before cold-booting one.
+	UVMPool *configUVMPool `json:"uvmPool,omitempty"`
+	// ScratchPool, if set, keeps scratch disks formatted by the utility VM of
+	// each LCOW pod ready so that a workload task whose rootfs has no
+	// `sandbox.vhdx` does not wait for one to be formatted when it is
+	// created.
+	ScratchPool *configScratchPool `json:"scratchPool,omitempty"`
+	// UVMConsoleLogDirectory, if set, captures the serial console of every
+	// utility VM the shim creates to `<utility VM ID>.log` in this directory
+	// so that kernel panics and early boot failures of the guest can be
+	// diagnosed. The log of a utility VM is removed when its pod is deleted
+	// unless the utility VM failed to start or exited on its own. Utility VMs
+	// whose console is attached for debugging via `oci.annotationConsole` are
+	// not captured.
+	UVMConsoleLogDirectory string `json:"uvmConsoleLogDirectory,omitempty"`
+	// UVMGuestCrashDumps, if `true`, writes a dump of the guest kernel to the
+	// bundle directory of the task or pod if its utility VM crashes, as
+	// `guest-crash.dmp` for WCOW or `guest-crash.log` for LCOW. The serial
+	// console of LCOW utility VMs is captured to write the dump unless it is
+	// attached for debugging via `oci.annotationConsole`.
+	UVMGuestCrashDumps bool `json:"uvmGuestCrashDumps,omitempty"`
+	// StdinFileDirectories are the host directories that the stdin of a task
+	// or exec may be read from with a `file://` URI. Files outside of them are
+	// rejected. If empty `file://` stdin is not allowed.
+	StdinFileDirectories []string `json:"stdinFileDirectories,omitempty"`
+}
+
+// configUVMPool is the connection to the uvmpool sidecar.
+type configUVMPool struct {
+	// Address is the named pipe the uvmpool sidecar serves claims on.
+	Address string `json:"address,omitempty"`
+}
+
+// configScratchPool is the pool of scratch disks of an LCOW pod.
+type configScratchPool struct {
+	// Directory is the directory the ready disks of each pod are kept in, in
+	// a subdirectory named after its utility VM that is removed with it.
+	Directory string `json:"directory,omitempty"`
+	// Count is the number of disks kept ready for each pod.
+	Count int `json:"count,omitempty"`
+}
+
+// configReservation are the host-wide limits on the memory and processors
+// reserved by utility VMs.
+type configReservation struct {
+	// MemoryInMB is the total memory that may be reserved. If `0` it is the
+	// physical memory of the host less `HostMemoryReserveInMB`.
+	MemoryInMB uint64 `json:"memoryInMB,omitempty"`
+	// HostMemoryReserveInMB is the memory kept for the host when `MemoryInMB`
+	// is `0`.
+	HostMemoryReserveInMB uint64 `json:"hostMemoryReserveInMB,omitempty"`
+	// ProcessorCount is the total number of processors that may be reserved.
+	// If `0` processors are not limited.
+	ProcessorCount uint32 `json:"processorCount,omitempty"`
+}
+
+// configTimeouts are timeout overrides in seconds. If a timeout is `0` the
+// value in effect when the shim started is used.
+type configTimeouts struct {
+	SystemCreate              int `json:"systemCreate,omitempty"`
+	SystemStart               int `json:"systemStart,omitempty"`
+	SystemPause               int `json:"systemPause,omitempty"`
+	SystemResume              int `json:"systemResume,omitempty"`
+	SyscallWatcher            int `json:"syscallWatcher,omitempty"`
+	ExternalCommandToStart    int `json:"externalCommandToStart,omitempty"`
+	ExternalCommandToComplete int `json:"externalCommandToComplete,omitempty"`
+	// ProcessStop is the time an exec is given to exit after a stop signal
+	// before it is forcibly terminated, for tasks that do not set
+	// `oci.AnnotationKillSignalTimeoutInMs` or
+	// `oci.AnnotationProcessStopTimeout`. If `0` the signal is delivered once
+	// and the exec is not terminated.
+	ProcessStop int `json:"processStop,omitempty"`
+	// PodWorkloadStop is the time the workload tasks of a pod are given to
+	// exit on a kill all of the pod before the sandbox task is signalled. If
+	// `0` `defaultPodWorkloadStopTimeout` is used.
+	PodWorkloadStop int `json:"podWorkloadStop,omitempty"`
+}
+
+// startupTimeouts are the timeouts in effect when the shim started. These are
+// restored when an override is removed from the config file.
+var startupTimeouts = struct {
+	SystemCreate, SystemStart, SystemPause, SystemResume, SyscallWatcher,
+	ExternalCommandToStart, ExternalCommandToComplete time.Duration
+}{
+	timeout.SystemCreate,
+	timeout.SystemStart,
+	timeout.SystemPause,
+	timeout.SystemResume,
+	timeout.SyscallWatcher,
+	timeout.ExternalCommandToStart,
+	timeout.ExternalCommandToComplete,
+}
+
+var (
+	// configM MUST be held to safely read/write `configPath` or `config`.
+	configM sync.Mutex
+	// configPath is the path to the config file. It is "" if no config file
+	// was passed.
+	configPath string
+	// config is the last successfully loaded config.
+	config = &shimConfig{}
+)
+
+// getConfig returns the config currently in effect. The returned config MUST
+// be treated as readonly.
+func getConfig() *shimConfig {
+	configM.Lock()
+	defer configM.Unlock()
+	return config
+}
+
+// setConfigPath loads and applies the config file at `path`. Later calls to
+// `reloadConfig` reload it from the same path.
+func setConfigPath(path string) error {
+	configM.Lock()
+	defer configM.Unlock()
+	if err := loadConfigL(path); err != nil {
+		return err
+	}
+	configPath = path
+	return nil
+}
+
+// reloadConfig reloads and applies the config file. If the file fails to load
+// the config in effect is unchanged. Returns the path of the file reloaded.
+func reloadConfig() (string, error) {
+	configM.Lock()
+	defer configM.Unlock()
+	if configPath == "" {
+		return "", errors.Wrap(errdefs.ErrFailedPrecondition, "no config file was passed in the shim options")
+	}
+	return configPath, loadConfigL(configPath)
+}
+
+// loadConfigL reads, validates and applies the config file at `path`. It is
+// the callers responsibility to hold `configM`.
+func loadConfigL(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read config file '%s'", path)
+	}
+	c, err := parseConfig(b)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load config file '%s'", path)
+	}
+	c.apply()
+	config = c
+	logrus.WithField("path", path).Info("loaded shim config file")
+	return nil
+}
+
+// parseConfig decodes and validates the config file contents `b`.
+func parseConfig(b []byte) (*shimConfig, error) {
+	c := &shimConfig{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, errors.Wrap(errdefs.ErrInvalidArgument, err.Error())
+	}
+	if c.LogLevel != "" {
+		if _, err := logrus.ParseLevel(c.LogLevel); err != nil {
+			return nil, errors.Wrap(errdefs.ErrInvalidArgument, err.Error())
+		}
+	}
+	if c.IOReconnectBufferSize < 0 {
+		return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "ioReconnectBufferSize must not be negative: %d", c.IOReconnectBufferSize)
+	}
+	if c.TTYScrollbackSize < -1 {
+		return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "ttyScrollbackSize must be -1 or greater: %d", c.TTYScrollbackSize)
+	}
+	if c.UVMPool != nil && c.UVMPool.Address == "" {
+		return nil, errors.Wrap(errdefs.ErrInvalidArgument, "uvmPool.address must be set")
+	}
+	if c.ScratchPool != nil {
+		if !filepath.IsAbs(c.ScratchPool.Directory) {
+			return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "scratchPool.directory must be an absolute path: '%s'", c.ScratchPool.Directory)
+		}
+		if c.ScratchPool.Count <= 0 {
+			return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "scratchPool.count must be positive: %d", c.ScratchPool.Count)
+		}
+	}
+	for _, dir := range c.StdinFileDirectories {
+		if !filepath.IsAbs(dir) {
+			return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "stdinFileDirectories must be absolute paths: '%s'", dir)
+		}
+	}
+	for name := range c.FeatureGates {
+		if _, ok := defaultFeatureGates[name]; !ok {
+			return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "unknown feature gate '%s'", name)
+		}
+	}
+	return c, nil
+}
+
+// apply applies the log level and timeouts in `c`. The timeouts are stored
+// atomically as they are read concurrently by in flight operations. The
+// remaining settings are read on use.
+func (c *shimConfig) apply() {
+	if c.LogLevel != "" {
+		// Validated in `parseConfig`.
+		lvl, _ := logrus.ParseLevel(c.LogLevel)
+		logrus.SetLevel(lvl)
+	}
+	timeout.Store(&timeout.SystemCreate, configTimeout(c.Timeouts.SystemCreate, startupTimeouts.SystemCreate))
+	timeout.Store(&timeout.SystemStart, configTimeout(c.Timeouts.SystemStart, startupTimeouts.SystemStart))
+	timeout.Store(&timeout.SystemPause, configTimeout(c.Timeouts.SystemPause, startupTimeouts.SystemPause))
+	timeout.Store(&timeout.SystemResume, configTimeout(c.Timeouts.SystemResume, startupTimeouts.SystemResume))
+	timeout.Store(&timeout.SyscallWatcher, configTimeout(c.Timeouts.SyscallWatcher, startupTimeouts.SyscallWatcher))
+	timeout.Store(&timeout.ExternalCommandToStart, configTimeout(c.Timeouts.ExternalCommandToStart, startupTimeouts.ExternalCommandToStart))
+	timeout.Store(&timeout.ExternalCommandToComplete, configTimeout(c.Timeouts.ExternalCommandToComplete, startupTimeouts.ExternalCommandToComplete))
+}
+
+func configTimeout(seconds int, def time.Duration) time.Duration {
+	if seconds > 0 {
+		return time.Second * time.Duration(seconds)
+	}
+	return def
+}
+
+// killPolicy returns the kill escalation policy for a task created from `s`.
+// The policy in the annotations of `s` takes precedence over the process stop
+// timeout, which is the one in the annotations of `s` or else the shim-wide
+// one. A process stop timeout only applies to the signals that stop a process.
+func (c *shimConfig) killPolicy(s *specs.Spec) oci.KillPolicy {
+	kp := oci.ParseAnnotationsKillPolicy(s)
+	if kp.Enabled() {
+		return kp
+	}
+	stop := oci.ParseAnnotationsProcessStopTimeout(s)
+	if stop == 0 {
+		stop = time.Second * time.Duration(c.Timeouts.ProcessStop)
+	}
+	if stop > 0 {
+		kp.SignalTimeout = stop
+		kp.StopSignalsOnly = true
+	}
+	return kp
+}
+
+// podWorkloadStopTimeout returns the time the workload tasks of a pod are given
+// to exit before the sandbox task is signalled.
+func (c *shimConfig) podWorkloadStopTimeout() time.Duration {
+	return configTimeout(c.Timeouts.PodWorkloadStop, defaultPodWorkloadStopTimeout)
+}
+
+// reservationLimits returns the host-wide utility VM reservation limits. If
+// reservations are not configured returns `nil`.
+func (c *shimConfig) reservationLimits() (*reservation.Limits, error) {
+	if c.Reservation == nil {
+		return nil, nil
+	}
+	l := &reservation.Limits{
+		MemoryInMB:     c.Reservation.MemoryInMB,
+		ProcessorCount: c.Reservation.ProcessorCount,
+	}
+	if l.MemoryInMB == 0 {
+		total, err := reservation.TotalPhysicalMemoryInMB()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get host physical memory")
+		}
+		if total <= c.Reservation.HostMemoryReserveInMB {
+			return nil, errors.Wrapf(errdefs.ErrFailedPrecondition, "hostMemoryReserveInMB %d must be less than the host physical memory %d MB", c.Reservation.HostMemoryReserveInMB, total)
+		}
+		l.MemoryInMB = total - c.Reservation.HostMemoryReserveInMB
+	}
+	return l, nil
+}
+
+// wrapReservationError wraps a utility VM reservation failure in `err` as
+// `errdefs.ErrUnavailable` so that the caller may retry the create or update
+// later. Any other error is returned unchanged.
+func wrapReservationError(err error) error {
+	if _, ok := err.(*reservation.ExhaustedError); ok {
+		return errors.Wrap(errdefs.ErrUnavailable, err.Error())
+	}
+	return err
+}
+
+// featureEnabled returns `true` if the feature gate `name` is enabled.
+func (c *shimConfig) featureEnabled(name string) bool {
+	if enabled, ok := c.FeatureGates[name]; ok {
+		return enabled
+	}
+	return defaultFeatureGates[name]
+}
+
+// ioReconnectBufferSize returns the maximum number of bytes of output buffered
+// while an upstream stdio connection is disconnected.
+func (c *shimConfig) ioReconnectBufferSize() int {
+	if c.IOReconnectBufferSize > 0 {
+		return c.IOReconnectBufferSize
+	}
+	return reconnectBufferSize
+}
+
+// ttyScrollbackSize returns the number of bytes of the most recent output of
+// a terminal replayed on reconnect. If `0` scrollback is disabled.
+func (c *shimConfig) ttyScrollbackSize() int {
+	switch {
+	case c.TTYScrollbackSize > 0:
+		return c.TTYScrollbackSize
+	case c.TTYScrollbackSize < 0:
+		return 0
+	}
+	return ttyScrollbackSize
+}
+
+// setupReloadConfig listens for an event which when signalled reloads the
+// config file.
+func setupReloadConfig() {
+	event := "Global\\reloadconfig-" + fmt.Sprint(os.Getpid())
+	handle, err := createEvent(event)
+	if err != nil {
+		return
+	}
+	go func() {
+		for {
+			windows.WaitForSingleObject(handle, windows.INFINITE)
+			if path, err := reloadConfig(); err != nil {
+				logrus.WithFields(logrus.Fields{
+					"path":          path,
+					logrus.ErrorKey: err,
+				}).Error("failed to reload shim config file")
+			}
+		}
+	}()
+}
+
+// setUVMConsoleLog sets the utility VM options `opts` to capture the serial
+// console to `UVMConsoleLogDirectory` if configured, creating the directory.
+func (c *shimConfig) setUVMConsoleLog(opts interface{}) error {
+	if c.UVMConsoleLogDirectory == "" {
+		return nil
+	}
+	var (
+		id          string
+		consolePipe *string
+		options     *uvm.Options
+	)
+	switch o := opts.(type) {
+	case *uvm.OptionsLCOW:
+		id, consolePipe, options = o.ID, &o.ConsolePipe, o.Options
+	case *uvm.OptionsWCOW:
+		id, consolePipe, options = o.ID, &o.ConsolePipe, o.Options
+	default:
+		return nil
+	}
+	if *consolePipe != "" {
+		return nil
+	}
+	if err := os.MkdirAll(c.UVMConsoleLogDirectory, 0700); err != nil {
+		return errors.Wrap(err, "failed to create utility VM console log directory")
+	}
+	*consolePipe = uvm.ConsolePipePath(id)
+	options.ConsoleLogPath = filepath.Join(c.UVMConsoleLogDirectory, id+".log")
+	return nil
+}
+
+// setUVMGuestCrashDump sets the utility VM options `opts` to write the guest
+// crash dump to `bundle` if `UVMGuestCrashDumps` is configured.
+func (c *shimConfig) setUVMGuestCrashDump(opts interface{}, bundle string) {
+	if !c.UVMGuestCrashDumps {
+		return
+	}
+	switch o := opts.(type) {
+	case *uvm.OptionsLCOW:
+		if o.ConsolePipe != "" && o.ConsoleLogPath == "" {
+			// The console is attached for debugging.
+			return
+		}
+		if o.ConsolePipe == "" {
+			o.ConsolePipe = uvm.ConsolePipePath(o.ID)
+		}
+		o.GuestCrashDumpPath = filepath.Join(bundle, "guest-crash.log")
+	case *uvm.OptionsWCOW:
+		o.GuestCrashDumpPath = filepath.Join(bundle, "guest-crash.dmp")
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/oci"
+	"github.com/Microsoft/hcsshim/internal/reservation"
+	"github.com/Microsoft/hcsshim/internal/timeout"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/containerd/containerd/errdefs"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+)
+
+func Test_parseConfig_Valid(t *testing.T) {
+	c, err := parseConfig([]byte(`{"logLevel":"debug","timeouts":{"systemCreate":10},"ioReconnectBufferSize":100,"featureGates":{"OOMEvents":false}}`))
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if c.LogLevel != "debug" || c.Timeouts.SystemCreate != 10 {
+		t.Fatalf("unexpected config: %+v", c)
+	}
+	if c.ioReconnectBufferSize() != 100 {
+		t.Fatalf("expected buffer size 100, got: %d", c.ioReconnectBufferSize())
+	}
+	if c.featureEnabled(featureOOMEvents) {
+		t.Fatal("expected OOMEvents to be disabled")
+	}
+}
+
+func Test_parseConfig_Invalid(t *testing.T) {
+	tests := []string{
+		`{`,
+		`{"logLevel":"loud"}`,
+		`{"ioReconnectBufferSize":-1}`,
+		`{"ttyScrollbackSize":-2}`,
+		`{"featureGates":{"NotAFeature":true}}`,
+		`{"uvmPool":{}}`,
+		`{"scratchPool":{"directory":"relative","count":1}}`,
+		`{"scratchPool":{"directory":"c:\\pool"}}`,
+		`{"stdinFileDirectories":["relative"]}`,
+	}
+	for _, test := range tests {
+		if _, err := parseConfig([]byte(test)); errors.Cause(err) != errdefs.ErrInvalidArgument {
+			t.Errorf("%s: expected ErrInvalidArgument, got: %v", test, err)
+		}
+	}
+}
+
+func Test_shimConfig_Defaults(t *testing.T) {
+	c := &shimConfig{}
+	if !c.featureEnabled(featureOOMEvents) {
+		t.Fatal("expected OOMEvents to be enabled by default")
+	}
+	if c.ioReconnectBufferSize() != reconnectBufferSize {
+		t.Fatalf("expected default buffer size, got: %d", c.ioReconnectBufferSize())
+	}
+	if c.ttyScrollbackSize() != ttyScrollbackSize {
+		t.Fatalf("expected default scrollback size, got: %d", c.ttyScrollbackSize())
+	}
+	if c := (&shimConfig{TTYScrollbackSize: -1}); c.ttyScrollbackSize() != 0 {
+		t.Fatalf("expected scrollback to be disabled, got: %d", c.ttyScrollbackSize())
+	}
+}
+
+func Test_shimConfig_Apply_RestoresTimeouts(t *testing.T) {
+	defer (&shimConfig{}).apply()
+
+	(&shimConfig{Timeouts: configTimeouts{SystemStart: 7}}).apply()
+	if timeout.Load(&timeout.SystemStart).Seconds() != 7 {
+		t.Fatalf("expected 7s, got: %v", timeout.Load(&timeout.SystemStart))
+	}
+	(&shimConfig{}).apply()
+	if timeout.Load(&timeout.SystemStart) != startupTimeouts.SystemStart {
+		t.Fatalf("expected startup timeout %v, got: %v", startupTimeouts.SystemStart, timeout.Load(&timeout.SystemStart))
+	}
+}
+
+func Test_shimConfig_KillPolicy(t *testing.T) {
+	c := &shimConfig{}
+	if kp := c.killPolicy(&specs.Spec{}); kp.Enabled() {
+		t.Fatalf("expected disabled policy, got: %+v", kp)
+	}
+
+	c.Timeouts.ProcessStop = 30
+	kp := c.killPolicy(&specs.Spec{})
+	if kp.SignalTimeout != 30*time.Second {
+		t.Fatalf("expected 30s signal timeout, got: %v", kp.SignalTimeout)
+	}
+	if !kp.Applies(15, false) || !kp.Applies(6, true) {
+		t.Fatalf("expected the process stop timeout to apply to stop signals, got: %+v", kp)
+	}
+	if kp.Applies(1, false) || kp.Applies(10, false) {
+		t.Fatalf("expected the process stop timeout not to apply to SIGHUP or SIGUSR1, got: %+v", kp)
+	}
+
+	kp = c.killPolicy(&specs.Spec{
+		Annotations: map[string]string{
+			oci.AnnotationProcessStopTimeout: "60",
+		},
+	})
+	if kp.SignalTimeout != 60*time.Second || !kp.StopSignalsOnly {
+		t.Fatalf("expected the task process stop timeout to take precedence, got: %+v", kp)
+	}
+
+	kp = c.killPolicy(&specs.Spec{
+		Annotations: map[string]string{
+			oci.AnnotationKillSignalTimeoutInMs: "1000",
+		},
+	})
+	if kp.SignalTimeout != time.Second {
+		t.Fatalf("expected annotation to take precedence, got: %v", kp.SignalTimeout)
+	}
+}
+
+func Test_shimConfig_ReservationLimits(t *testing.T) {
+	c := &shimConfig{}
+	if l, err := c.reservationLimits(); l != nil || err != nil {
+		t.Fatalf("expected no limits by default, got: %+v, %v", l, err)
+	}
+	c, err := parseConfig([]byte(`{"reservation":{"memoryInMB":4096,"processorCount":8}}`))
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	l, err := c.reservationLimits()
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if *l != (reservation.Limits{MemoryInMB: 4096, ProcessorCount: 8}) {
+		t.Fatalf("unexpected limits: %+v", *l)
+	}
+}
+
+func Test_wrapReservationError(t *testing.T) {
+	err := wrapReservationError(&reservation.ExhaustedError{})
+	if errors.Cause(err) != errdefs.ErrUnavailable {
+		t.Fatalf("expected error: %v, got: %v", errdefs.ErrUnavailable, err)
+	}
+	other := errors.New("other")
+	if wrapReservationError(other) != other {
+		t.Fatal("expected other errors to be returned unchanged")
+	}
+}
+
+func Test_shimConfig_SetUVMConsoleLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "console")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	c := &shimConfig{UVMConsoleLogDirectory: filepath.Join(dir, "logs")}
+
+	lopts := uvm.NewDefaultOptionsLCOW("pod@vm", "")
+	if err := c.setUVMConsoleLog(lopts); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if lopts.ConsolePipe != uvm.ConsolePipePath("pod@vm") {
+		t.Fatalf("expected the default console pipe, got: '%s'", lopts.ConsolePipe)
+	}
+	if lopts.ConsoleLogPath != filepath.Join(dir, "logs", "pod@vm.log") {
+		t.Fatalf("unexpected console log path: '%s'", lopts.ConsoleLogPath)
+	}
+	if _, err := os.Stat(c.UVMConsoleLogDirectory); err != nil {
+		t.Fatalf("expected the console log directory to be created: %v", err)
+	}
+
+	wopts := uvm.NewDefaultOptionsWCOW("pod@vm", "")
+	if err := c.setUVMConsoleLog(wopts); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if wopts.ConsolePipe == "" || wopts.ConsoleLogPath == "" {
+		t.Fatal("expected the WCOW console to be captured")
+	}
+
+	// A console attached for debugging is not captured.
+	debug := uvm.NewDefaultOptionsLCOW("debug@vm", "")
+	debug.ConsolePipe = `\\.\pipe\debug`
+	if err := c.setUVMConsoleLog(debug); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if debug.ConsoleLogPath != "" {
+		t.Fatalf("expected the debug console not to be captured, got: '%s'", debug.ConsoleLogPath)
+	}
+}
+
+func Test_shimConfig_SetUVMGuestCrashDump(t *testing.T) {
+	c := &shimConfig{UVMGuestCrashDumps: true}
+
+	lopts := uvm.NewDefaultOptionsLCOW("pod@vm", "")
+	c.setUVMGuestCrashDump(lopts, `c:\bundle`)
+	if lopts.ConsolePipe != uvm.ConsolePipePath("pod@vm") {
+		t.Fatalf("expected the default console pipe, got: '%s'", lopts.ConsolePipe)
+	}
+	if lopts.GuestCrashDumpPath != filepath.Join(`c:\bundle`, "guest-crash.log") {
+		t.Fatalf("unexpected LCOW guest crash dump path: '%s'", lopts.GuestCrashDumpPath)
+	}
+
+	wopts := uvm.NewDefaultOptionsWCOW("pod@vm", "")
+	c.setUVMGuestCrashDump(wopts, `c:\bundle`)
+	if wopts.GuestCrashDumpPath != filepath.Join(`c:\bundle`, "guest-crash.dmp") {
+		t.Fatalf("unexpected WCOW guest crash dump path: '%s'", wopts.GuestCrashDumpPath)
+	}
+
+	// A console attached for debugging is not used for crash dumps.
+	debug := uvm.NewDefaultOptionsLCOW("debug@vm", "")
+	debug.ConsolePipe = `\\.\pipe\debug`
+	c.setUVMGuestCrashDump(debug, `c:\bundle`)
+	if debug.GuestCrashDumpPath != "" {
+		t.Fatalf("expected no guest crash dump for a debug console, got: '%s'", debug.GuestCrashDumpPath)
+	}
+
+	disabled := uvm.NewDefaultOptionsWCOW("pod@vm", "")
+	(&shimConfig{}).setUVMGuestCrashDump(disabled, `c:\bundle`)
+	if disabled.GuestCrashDumpPath != "" {
+		t.Fatalf("expected no guest crash dump when disabled, got: '%s'", disabled.GuestCrashDumpPath)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// crashReportFile is the name of the file in the bundle that the report
+	// of the last recovered panic is written to.
+	crashReportFile = "crash.log"
+	// recentLogLines is the number of most recent log lines included in a
+	// crash report.
+	recentLogLines = 100
+)
+
+// crashReportPath is the path crash reports are written to. It is "" if the
+// shim is not serving a bundle.
+//
+// This MUST be treated as readonly once the shim is serving.
+var crashReportPath string
+
+// recentLogs holds the most recent log lines for crash reports.
+var recentLogs = newRecentLogHook(recentLogLines)
+
+var _ = (logrus.Hook)(&recentLogHook{})
+
+// recentLogHook is a logrus hook that keeps the last `size` formatted log
+// lines in memory.
+type recentLogHook struct {
+	formatter logrus.Formatter
+
+	// m MUST be held to safely read/write `lines` or `next`.
+	m sync.Mutex
+	// lines is a ring buffer of formatted log lines. `next` is the index of
+	// the oldest line once the buffer is full.
+	lines []string
+	next  int
+	size  int
+}
+
+func newRecentLogHook(size int) *recentLogHook {
+	return &recentLogHook{
+		formatter: &logrus.TextFormatter{
+			DisableColors:   true,
+			FullTimestamp:   true,
+			TimestampFormat: time.RFC3339Nano,
+		},
+		size: size,
+	}
+}
+
+// Levels returns all levels so that every line is kept.
+func (h *recentLogHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire formats and stores `entry`.
+func (h *recentLogHook) Fire(entry *logrus.Entry) error {
+	b, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	line := string(bytes.TrimRight(b, "\n"))
+
+	h.m.Lock()
+	defer h.m.Unlock()
+	if len(h.lines) < h.size {
+		h.lines = append(h.lines, line)
+		return nil
+	}
+	h.lines[h.next] = line
+	h.next = (h.next + 1) % h.size
+	return nil
+}
+
+// Lines returns the stored log lines oldest first.
+func (h *recentLogHook) Lines() []string {
+	h.m.Lock()
+	defer h.m.Unlock()
+	lines := make([]string, 0, len(h.lines))
+	lines = append(lines, h.lines[h.next:]...)
+	return append(lines, h.lines[:h.next]...)
+}
+
+// formatCrashReport returns the crash report for the panic `r` recovered in
+// `activity`.
+func formatCrashReport(at time.Time, activity string, r interface{}, stack []byte, logs []string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "time: %s\n", at.Format(time.RFC3339Nano))
+	fmt.Fprintf(&b, "pid: %d\n", os.Getpid())
+	fmt.Fprintf(&b, "activity: %s\n", activity)
+	fmt.Fprintf(&b, "panic: %v\n", r)
+	fmt.Fprintf(&b, "\nstack:\n%s\n", stack)
+	fmt.Fprintf(&b, "\nrecent logs:\n")
+	for _, l := range logs {
+		fmt.Fprintln(&b, l)
+	}
+	return b.Bytes()
+}
+
+// writeCrashReport writes the crash report for the panic `r` recovered in
+// `activity` to `crashReportPath` replacing any previous report.
+func writeCrashReport(activity string, r interface{}, stack []byte, logs []string) {
+	if crashReportPath == "" {
+		return
+	}
+	report := formatCrashReport(time.Now(), activity, r, stack, logs)
+	if err := ioutil.WriteFile(crashReportPath, report, 0600); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"path":          crashReportPath,
+			logrus.ErrorKey: err,
+		}).Error("containerd-shim-runhcs-v1: failed to write crash report")
+	}
+}
+
+// readCrashReport returns the last crash report or `errdefs.ErrNotFound` if
+// the shim has not panicked.
+func readCrashReport() (string, string, error) {
+	if crashReportPath == "" {
+		return "", "", errors.Wrap(errdefs.ErrNotFound, "shim is not serving a bundle")
+	}
+	b, err := ioutil.ReadFile(crashReportPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", "", errors.Wrapf(errdefs.ErrNotFound, "no crash report at '%s'", crashReportPath)
+		}
+		return "", "", err
+	}
+	return crashReportPath, string(b), nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+func Test_recentLogHook_KeepsMostRecent(t *testing.T) {
+	h := newRecentLogHook(3)
+	for i := 0; i < 5; i++ {
+		if err := h.Fire(&logrus.Entry{Message: fmt.Sprintf("line%d", i), Data: logrus.Fields{}}); err != nil {
+			t.Fatalf("expected nil error, got: %v", err)
+		}
+	}
+	lines := h.Lines()
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got: %v", lines)
+	}
+	for i, l := range lines {
+		if !strings.Contains(l, fmt.Sprintf("line%d", i+2)) {
+			t.Fatalf("expected line %d to contain 'line%d', got: '%s'", i, i+2, l)
+		}
+	}
+}
+
+func Test_formatCrashReport(t *testing.T) {
+	report := string(formatCrashReport(time.Now(), "Kill", "boom", []byte("goroutine 1"), []string{"log1"}))
+	for _, expected := range []string{"activity: Kill", "panic: boom", "goroutine 1", "log1"} {
+		if !strings.Contains(report, expected) {
+			t.Fatalf("expected report to contain '%s', got: '%s'", expected, report)
+		}
+	}
+}
+
+func Test_CrashReport_WriteRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	crashReportPath = filepath.Join(dir, crashReportFile)
+	defer func() { crashReportPath = "" }()
+
+	if _, _, err := readCrashReport(); errors.Cause(err) != errdefs.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got: %v", err)
+	}
+	func() {
+		defer panicRecover("Test")
+		panic("boom")
+	}()
+	path, report, err := readCrashReport()
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if path != crashReportPath || !strings.Contains(report, "activity: Test") || !strings.Contains(report, "panic: boom") {
+		t.Fatalf("unexpected report at '%s': '%s'", path, report)
+	}
+}
@@ -75,15 +75,43 @@ The delete command will be executed in the container's bundle as its cwd.
 			}
 		}
 
-		// Remove the bundle on disk
-		if err := os.RemoveAll(bundleFlag); err != nil && !os.IsNotExist(err) {
-			return err
+		// Unmount the layers the shim mounted on the host for a process
+		// isolated container and remove the UVM scratch if its path is known
+		// from the bundle. The layer folders themselves are owned by the
+		// snapshotter.
+		if spec, _, err := readBundleSpec(bundleFlag); err == nil {
+			if err := unmountLayersWithRetry(&spec); err != nil {
+				fmt.Fprintf(os.Stderr, "%v", err)
+			}
+			if path := uvmScratchPath(&spec); path != "" {
+				if err := removeAllWithRetry(path); err != nil {
+					fmt.Fprintf(os.Stderr, "%v", err)
+				}
+			}
 		}
 
-		if data, err := proto.Marshal(&task.DeleteResponse{
+		// If the init exec exited before the shim went away report its real
+		// exit state rather than a generic failure.
+		resp := &task.DeleteResponse{
 			ExitedAt:   time.Now(),
 			ExitStatus: 255,
-		}); err != nil {
+		}
+		if state, err := readExecState(bundleFlag, idFlag); err == nil {
+			resp.Pid = uint32(state.Pid)
+			if !state.ExitedAt.IsZero() {
+				resp.ExitStatus = state.ExitStatus
+				resp.ExitedAt = state.ExitedAt
+			}
+		} else if !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "failed to read exit state of '%s': %v", idFlag, err)
+		}
+
+		// Remove the bundle on disk
+		if err := removeAllWithRetry(bundleFlag); err != nil {
+			return err
+		}
+
+		if data, err := proto.Marshal(resp); err != nil {
 			return err
 		} else {
 			if _, err := os.Stdout.Write(data); err != nil {
@@ -2,12 +2,21 @@ package main
 
 import (
 	"context"
-	"errors"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"sync/atomic"
 
 	"github.com/Microsoft/hcsshim/internal/hcsoci"
+	"github.com/Microsoft/hcsshim/internal/hns"
+	"github.com/Microsoft/hcsshim/internal/lcow"
 	"github.com/Microsoft/hcsshim/internal/logfields"
 	"github.com/Microsoft/hcsshim/internal/shimdiag"
 	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/runtime/v2/task"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
@@ -35,3 +44,114 @@ func execInUvm(ctx context.Context, vm *uvm.UtilityVM, req *shimdiag.ExecProcess
 	err = cmd.Run()
 	return cmd.ExitState.ExitCode(), err
 }
+
+// attachUvmConsole relays the serial console of `vm` to the named pipes in
+// `req` until the client closes stdin or `ctx` is done.
+//
+// The serial console is a named pipe served by the VM worker process so this
+// works even if the GCS in `vm` is unresponsive.
+func attachUvmConsole(ctx context.Context, vm *uvm.UtilityVM, req *shimdiag.ConsoleRequest) error {
+	if vm.ConsolePipe() == "" {
+		return errors.Wrapf(errdefs.ErrFailedPrecondition, "utility VM '%s' has no serial console", vm.ID())
+	}
+	np, err := newNpipeIO(ctx, "", "", req.Stdin, req.Stdout, "", true)
+	if err != nil {
+		return err
+	}
+	defer np.Close()
+	var stdin io.Reader
+	if np.StdinPath() != "" {
+		stdin = np.Stdin()
+	}
+	if err := vm.AttachConsole(ctx, stdin, np.Stdout()); err != nil {
+		return errors.Wrapf(err, "failed to attach to the serial console of utility VM '%s'", vm.ID())
+	}
+	return nil
+}
+
+// exportUvmRootfs writes the root file system at `rootfsPath` in the LCOW
+// utility VM `vm` to the stdout named pipe in `req` as a tar stream.
+func exportUvmRootfs(ctx context.Context, vm *uvm.UtilityVM, rootfsPath string, req *shimdiag.ExportRootfsRequest) error {
+	np, err := newNpipeIO(ctx, req.TaskID, "", "", req.Stdout, "", false)
+	if err != nil {
+		return err
+	}
+	defer np.Close()
+	if err := lcow.ExportRootfs(ctx, vm, rootfsPath, np.Stdout()); err != nil {
+		return errors.Wrapf(err, "failed to export the root file system of task '%s'", req.TaskID)
+	}
+	return nil
+}
+
+// diagShareCount is the number of single files shared by `shareInUvm`. It
+// names the private directory each file is mounted at.
+var diagShareCount uint64
+
+// shareInUvm shares the host path in `req` into `vm`. A single file shared into
+// a Linux utility VM is mounted at a private directory and bind mounted to the
+// requested path. Mounting it at the parent of the requested path would hide
+// everything else in that directory.
+func shareInUvm(ctx context.Context, vm *uvm.UtilityVM, req *shimdiag.ShareRequest) (err error) {
+	if vm.OS() != "linux" {
+		return vm.Share(req.HostPath, req.UvmPath, req.ReadOnly)
+	}
+	if fi, err := os.Stat(req.HostPath); err != nil || fi.IsDir() {
+		return vm.Share(req.HostPath, req.UvmPath, req.ReadOnly)
+	}
+	uvmDir := path.Join("/run/diagshare", strconv.FormatUint(atomic.AddUint64(&diagShareCount, 1), 10))
+	share, err := vm.AddPlan9File(req.HostPath, uvmDir, req.ReadOnly, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			vm.RemovePlan9(share)
+		}
+	}()
+	cmd := hcsoci.CommandContext(ctx, vm,
+		"sh", "-c", `mkdir -p "$(dirname "$1")" && touch "$1" && mount --bind "$2" "$1"`,
+		"sh", req.UvmPath, share.UVMFilePath())
+	cmd.Log = logrus.WithField(logfields.UVMID, vm.ID())
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "failed to bind mount '%s' to '%s' in utility VM '%s'", share.UVMFilePath(), req.UvmPath, vm.ID())
+	}
+	return nil
+}
+
+// updateUvmEndpoint hot adds the HNS endpoint in `req` to, or removes it from,
+// the network namespace `nsid` in `vm`.
+func updateUvmEndpoint(vm *uvm.UtilityVM, nsid string, req *shimdiag.EndpointRequest) error {
+	if nsid == "" {
+		return errors.Wrapf(errdefs.ErrFailedPrecondition, "task: '%s' has no network namespace", req.TaskID)
+	}
+	var err error
+	if req.Remove {
+		err = vm.RemoveEndpoint(nsid, req.EndpointID)
+	} else {
+		var endpoint *hns.HNSEndpoint
+		endpoint, err = hns.GetHNSEndpointByID(req.EndpointID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get endpoint '%s'", req.EndpointID)
+		}
+		err = vm.AddEndpoint(nsid, endpoint)
+	}
+	switch err {
+	case uvm.ErrNetNSNotFound, uvm.ErrEndpointNotFound:
+		return errors.Wrapf(errdefs.ErrNotFound, "endpoint '%s' in network namespace '%s': %s", req.EndpointID, nsid, err)
+	case uvm.ErrEndpointAlreadyAttached:
+		return errors.Wrapf(errdefs.ErrAlreadyExists, "endpoint '%s' in network namespace '%s': %s", req.EndpointID, nsid, err)
+	}
+	return err
+}
+
+// newDiagStateResponse returns a `*shimdiag.TaskStateResponse` with the fields
+// of `s` filled in.
+func newDiagStateResponse(s *task.StateResponse) *shimdiag.TaskStateResponse {
+	return &shimdiag.TaskStateResponse{
+		TaskID:     s.ID,
+		ExecID:     s.ExecID,
+		Pid:        s.Pid,
+		Status:     s.Status.String(),
+		ExitStatus: s.ExitStatus,
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/cow"
+	"github.com/Microsoft/hcsshim/internal/hcsoci"
+	"github.com/sirupsen/logrus"
+)
+
+// eventLogPollInterval is the time between queries of each forwarded event log
+// channel for new records.
+const eventLogPollInterval = 2 * time.Second
+
+// eventLogEntries are the records written by `wevtutil qe /f:RenderedXml
+// /e:Events`.
+type eventLogEntries struct {
+	Events []eventLogEntry `xml:"Event"`
+}
+
+// eventLogEntry is a single rendered event log record.
+type eventLogEntry struct {
+	System struct {
+		Provider struct {
+			Name string `xml:"Name,attr"`
+		} `xml:"Provider"`
+		EventID     uint32 `xml:"EventID"`
+		Level       uint8  `xml:"Level"`
+		TimeCreated struct {
+			SystemTime string `xml:"SystemTime,attr"`
+		} `xml:"TimeCreated"`
+		RecordID uint64 `xml:"EventRecordID"`
+	} `xml:"System"`
+	RenderingInfo struct {
+		Message string `xml:"Message"`
+	} `xml:"RenderingInfo"`
+}
+
+// eventLogQueryCommand returns the arguments that query the event log `channel`
+// for the records after `lastRecordID`. `wevtutil` is used as it is part of
+// every Windows base image, unlike PowerShell.
+func eventLogQueryCommand(channel string, lastRecordID uint64) []string {
+	return []string{
+		"wevtutil.exe",
+		"qe",
+		channel,
+		fmt.Sprintf("/q:*[System[EventRecordID>%d]]", lastRecordID),
+		"/f:RenderedXml",
+		"/e:Events",
+	}
+}
+
+// parseEventLogEntries parses the records written by the query of
+// `eventLogQueryCommand`.
+func parseEventLogEntries(b []byte) ([]eventLogEntry, error) {
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil, nil
+	}
+	var entries eventLogEntries
+	if err := xml.Unmarshal(b, &entries); err != nil {
+		return nil, err
+	}
+	return entries.Events, nil
+}
+
+// eventLogLevel maps the Windows event level of an entry to the logrus level
+// it is forwarded at.
+func eventLogLevel(level uint8) logrus.Level {
+	switch level {
+	case 1, 2: // Critical, Error
+		return logrus.ErrorLevel
+	case 3: // Warning
+		return logrus.WarnLevel
+	case 5: // Verbose
+		return logrus.DebugLevel
+	default: // LogAlways, Informational
+		return logrus.InfoLevel
+	}
+}
+
+// logEventLogEntry logs the record `e` of the event log `channel` to `log`.
+func logEventLogEntry(log *logrus.Entry, channel string, e *eventLogEntry) {
+	log.WithFields(logrus.Fields{
+		"channel":     channel,
+		"recordId":    e.System.RecordID,
+		"provider":    e.System.Provider.Name,
+		"eventId":     e.System.EventID,
+		"timeCreated": e.System.TimeCreated.SystemTime,
+	}).Log(eventLogLevel(e.System.Level), strings.TrimSpace(e.RenderingInfo.Message))
+}
+
+// pollEventLog logs the records of the event log `channel` in `c` after
+// `lastRecordID` and returns the id of the last record logged.
+func pollEventLog(ctx context.Context, log *logrus.Entry, c cow.ProcessHost, channel string, lastRecordID uint64) uint64 {
+	args := eventLogQueryCommand(channel, lastRecordID)
+	cmd := hcsoci.CommandContext(ctx, c, args[0], args[1:]...)
+	out, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() == nil {
+			log.WithError(err).WithField("channel", channel).Debug("failed to query event log")
+		}
+		return lastRecordID
+	}
+	entries, err := parseEventLogEntries(out)
+	if err != nil {
+		log.WithError(err).WithField("channel", channel).Debug("failed to parse event log entries")
+		return lastRecordID
+	}
+	for i := range entries {
+		logEventLogEntry(log, channel, &entries[i])
+		if id := entries[i].System.RecordID; id > lastRecordID {
+			lastRecordID = id
+		}
+	}
+	return lastRecordID
+}
+
+// forwardEventLogs polls the event log `channels` in `c` and forwards their
+// entries to the shim log until `ctx` is done. The first poll forwards every
+// record already in each channel.
+func forwardEventLogs(ctx context.Context, tid string, c cow.ProcessHost, channels []string) {
+	log := logrus.WithFields(logrus.Fields{
+		"tid":      tid,
+		"channels": strings.Join(channels, ","),
+	})
+	last := make(map[string]uint64, len(channels))
+	t := time.NewTicker(eventLogPollInterval)
+	defer t.Stop()
+	for {
+		for _, channel := range channels {
+			if strings.HasPrefix(channel, "/") {
+				// Would be parsed as an option by `wevtutil`.
+				continue
+			}
+			last[channel] = pollEventLog(ctx, log, c, channel, last[channel])
+		}
+		select {
+		case <-ctx.Done():
+			log.Debug("event log forwarder exited")
+			return
+		case <-t.C:
+		}
+	}
+}
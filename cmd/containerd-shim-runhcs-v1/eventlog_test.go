@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+const testEventLogXML = `<Events>
+<Event xmlns='http://schemas.microsoft.com/win/2004/08/events/event'><System><Provider Name='app'/><EventID Qualifiers='0'>1000</EventID><Level>2</Level><TimeCreated SystemTime='2019-01-01T00:00:00.000000000Z'/><EventRecordID>7</EventRecordID><Channel>Application</Channel></System><RenderingInfo Culture='en-US'><Message>failed</Message><Level>Error</Level></RenderingInfo></Event>
+<Event xmlns='http://schemas.microsoft.com/win/2004/08/events/event'><System><Provider Name='app'/><EventID>1</EventID><Level>5</Level><EventRecordID>8</EventRecordID><Channel>Application</Channel></System><RenderingInfo Culture='en-US'><Message>verbose</Message></RenderingInfo></Event>
+</Events>`
+
+func Test_EventLogQueryCommand(t *testing.T) {
+	args := eventLogQueryCommand("Application", 7)
+	expected := []string{"wevtutil.exe", "qe", "Application", "/q:*[System[EventRecordID>7]]", "/f:RenderedXml", "/e:Events"}
+	if strings.Join(args, " ") != strings.Join(expected, " ") {
+		t.Fatalf("expected %v, got: %v", expected, args)
+	}
+}
+
+func Test_ParseEventLogEntries(t *testing.T) {
+	entries, err := parseEventLogEntries([]byte(testEventLogXML))
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got: %d", len(entries))
+	}
+	e := entries[0]
+	if e.System.Provider.Name != "app" || e.System.EventID != 1000 || e.System.Level != 2 || e.System.RecordID != 7 || e.RenderingInfo.Message != "failed" {
+		t.Fatalf("unexpected entry: %+v", e)
+	}
+	if entries, err := parseEventLogEntries([]byte("\r\n")); err != nil || len(entries) != 0 {
+		t.Fatalf("expected no entries for empty output, got: %v, %v", entries, err)
+	}
+	if _, err := parseEventLogEntries([]byte("not xml")); err == nil {
+		t.Fatal("expected error for invalid xml")
+	}
+}
+
+func Test_EventLogLevel(t *testing.T) {
+	expected := map[uint8]logrus.Level{
+		0: logrus.InfoLevel,
+		1: logrus.ErrorLevel,
+		2: logrus.ErrorLevel,
+		3: logrus.WarnLevel,
+		4: logrus.InfoLevel,
+		5: logrus.DebugLevel,
+	}
+	for level, l := range expected {
+		if actual := eventLogLevel(level); actual != l {
+			t.Fatalf("level %d: expected %v, got %v", level, l, actual)
+		}
+	}
+}
+
+func Test_LogEventLogEntry(t *testing.T) {
+	var out bytes.Buffer
+	logger := logrus.New()
+	logger.Out = &out
+	logger.Level = logrus.InfoLevel
+	logger.Formatter = &logrus.JSONFormatter{}
+
+	entries, err := parseEventLogEntries([]byte(testEventLogXML))
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	for i := range entries {
+		logEventLogEntry(logrus.NewEntry(logger), "Application", &entries[i])
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 entry logged at info or above, got: %v", lines)
+	}
+	var e map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &e); err != nil {
+		t.Fatalf("failed to parse log line: %v", err)
+	}
+	if e["msg"] != "failed" || e["level"] != "error" || e["channel"] != "Application" || e["eventId"] != float64(1000) {
+		t.Fatalf("unexpected log entry: %v", e)
+	}
+}
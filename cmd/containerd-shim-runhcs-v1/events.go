@@ -1,24 +1,94 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
 	"os/exec"
 	"sync"
+	"time"
 
+	"github.com/containerd/containerd/runtime"
 	"github.com/containerd/typeurl"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
 type publisher func(topic string, event interface{})
 
-var _ = (publisher)(publishEvent)
+const (
+	// eventJournalFile is the name of the file in the bundle that events that
+	// could not be published are spilled to.
+	eventJournalFile = "events.journal"
+	// publishRetries is the number of times an event is published before it
+	// is spilled to the journal.
+	publishRetries = 3
+	// publishBackoff is the time waited after the first failed publish. It
+	// doubles on each retry.
+	publishBackoff = time.Millisecond * 100
+	// replayInterval is the time between background attempts to replay the
+	// journal while it is not empty.
+	replayInterval = time.Second * 5
+	// maxJournalEntries is the maximum number of events held in the journal.
+	// Once full an event is evicted for each event journaled.
+	maxJournalEntries = 1024
+)
+
+// lifecycleTopics are the topics containerd requires to track task state. When
+// the journal is full other events are evicted before these.
+var lifecycleTopics = map[string]bool{
+	runtime.TaskCreateEventTopic: true,
+	runtime.TaskStartEventTopic:  true,
+	runtime.TaskExitEventTopic:   true,
+	runtime.TaskDeleteEventTopic: true,
+}
+
+// publishRawFunc publishes the already marshaled event `data` on `topic`.
+type publishRawFunc func(topic string, data []byte) error
 
-var publishLock sync.Mutex
+// publishContainerd publishes `data` on `topic` via the containerd binary.
+func publishContainerd(topic string, data []byte) error {
+	cmd := exec.Command(containerdBinaryFlag, "--address", addressFlag, "publish", "--topic", topic, "--namespace", namespaceFlag)
+	cmd.Stdin = bytes.NewReader(data)
+	return cmd.Run()
+}
 
-func publishEvent(topic string, event interface{}) {
-	publishLock.Lock()
-	defer publishLock.Unlock()
+// journalEntry is a single event in the event journal.
+type journalEntry struct {
+	Topic string `json:"topic"`
+	Data  []byte `json:"data"`
+}
 
+// newJournaledPublisher returns a publisher that publishes events via
+// `publish`. If an event cannot be published after `publishRetries` attempts
+// it is appended to the journal at `journalPath` and replayed, in order,
+// before the next event is published or on the next call to `Replay`. The
+// journal holds at most `maxJournalEntries` events.
+func newJournaledPublisher(publish publishRawFunc, journalPath string) *journaledPublisher {
+	return &journaledPublisher{
+		publish:     publish,
+		journalPath: journalPath,
+		maxEntries:  maxJournalEntries,
+	}
+}
+
+type journaledPublisher struct {
+	// publish and journalPath MUST be treated as readonly in the lifetime of
+	// the publisher.
+	publish     publishRawFunc
+	journalPath string
+	maxEntries  int
+
+	// m serializes publishing so that events are delivered in order.
+	m sync.Mutex
+	// replaying is `true` while the background replay goroutine is running.
+	replaying bool
+}
+
+// Publish encodes and publishes `event` on `topic`.
+func (jp *journaledPublisher) Publish(topic string, event interface{}) {
 	encoded, err := typeurl.MarshalAny(event)
 	if err != nil {
 		logrus.WithError(err).Error("publishEvent - Failed to encode event")
@@ -29,10 +99,203 @@ func publishEvent(topic string, event interface{}) {
 		logrus.WithError(err).Error("publishEvent - Failed to marshal event")
 		return
 	}
-	cmd := exec.Command(containerdBinaryFlag, "--address", addressFlag, "publish", "--topic", topic, "--namespace", namespaceFlag)
-	cmd.Stdin = bytes.NewReader(data)
-	err = cmd.Run()
+
+	jp.m.Lock()
+	defer jp.m.Unlock()
+	// Any journaled events MUST be delivered before this one. If they cannot
+	// be, journal this one behind them.
+	if err := jp.replayL(); err == nil {
+		if err = jp.publishWithRetryL(topic, data); err == nil {
+			return
+		}
+	}
+	if err := jp.appendL(topic, data); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"topic":         topic,
+			logrus.ErrorKey: err,
+		}).Error("publishEvent - Failed to journal event, event lost")
+		return
+	}
+	if !jp.replaying {
+		jp.replaying = true
+		go jp.replayUntilEmpty()
+	}
+}
+
+// replayUntilEmpty replays the journal every `replayInterval` until it
+// succeeds so that journaled events are delivered even if no further events
+// are published.
+//
+// This MUST be called via a goroutine.
+func (jp *journaledPublisher) replayUntilEmpty() {
+	for {
+		time.Sleep(replayInterval)
+		jp.m.Lock()
+		err := jp.replayL()
+		if err == nil {
+			jp.replaying = false
+			jp.m.Unlock()
+			return
+		}
+		jp.m.Unlock()
+		logrus.WithError(err).Debug("publishEvent - Failed to replay journal")
+	}
+}
+
+// Replay publishes any journaled events. This is called when the shim starts
+// to deliver events a previous instance of the shim could not.
+func (jp *journaledPublisher) Replay() error {
+	jp.m.Lock()
+	defer jp.m.Unlock()
+	return jp.replayL()
+}
+
+// publishWithRetryL publishes `data` retrying with backoff. It is the callers
+// responsibility to hold `jp.m`.
+func (jp *journaledPublisher) publishWithRetryL(topic string, data []byte) (err error) {
+	backoff := publishBackoff
+	for attempt := 1; attempt <= publishRetries; attempt++ {
+		if err = jp.publish(topic, data); err == nil {
+			return nil
+		}
+		logrus.WithFields(logrus.Fields{
+			"topic":         topic,
+			"attempt":       attempt,
+			logrus.ErrorKey: err,
+		}).Warning("publishEvent - Failed to publish event")
+		if attempt < publishRetries {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	return err
+}
+
+// appendL appends the event to the journal. If the journal is full an event
+// is evicted first, see `evict`. It is the callers responsibility to hold
+// `jp.m`.
+func (jp *journaledPublisher) appendL(topic string, data []byte) error {
+	entries, err := jp.readL()
+	if err != nil {
+		return err
+	}
+	if len(entries) >= jp.maxEntries {
+		for len(entries) >= jp.maxEntries {
+			entries = evict(entries)
+		}
+		entries = append(entries, journalEntry{Topic: topic, Data: data})
+		return jp.rewriteL(entries)
+	}
+	f, err := os.OpenFile(jp.journalPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
 	if err != nil {
-		logrus.WithError(err).Error("publishEvent - Failed to publish event")
+		return err
+	}
+	defer f.Close()
+	if err := json.NewEncoder(f).Encode(&journalEntry{Topic: topic, Data: data}); err != nil {
+		return err
+	}
+	logrus.WithFields(logrus.Fields{
+		"topic":   topic,
+		"journal": jp.journalPath,
+	}).Warning("publishEvent - Event journaled for replay")
+	return nil
+}
+
+// evict removes the oldest event from `entries` whose topic is not one of
+// `lifecycleTopics`. If every event is a lifecycle event the oldest is removed.
+func evict(entries []journalEntry) []journalEntry {
+	i := 0
+	for j := range entries {
+		if !lifecycleTopics[entries[j].Topic] {
+			i = j
+			break
+		}
+	}
+	logrus.WithFields(logrus.Fields{
+		"topic": entries[i].Topic,
+	}).Error("publishEvent - Event journal full, event lost")
+	return append(entries[:i], entries[i+1:]...)
+}
+
+// readL returns the events in the journal in order. It is the callers
+// responsibility to hold `jp.m`.
+func (jp *journaledPublisher) readL() ([]journalEntry, error) {
+	b, err := readFileIfExists(jp.journalPath)
+	if err != nil || len(b) == 0 {
+		return nil, err
+	}
+
+	var entries []journalEntry
+	s := bufio.NewScanner(bytes.NewReader(b))
+	s.Buffer(nil, len(b)+1)
+	for s.Scan() {
+		var e journalEntry
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			// A torn write from a crash. Skip it rather than block every
+			// event behind it.
+			logrus.WithError(err).Error("publishEvent - Skipping corrupt journal entry")
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, s.Err()
+}
+
+// replayL publishes all events in the journal in order. On success the
+// journal is removed. If an event fails to publish the remaining events,
+// including the failed one, are left in the journal. It is the callers
+// responsibility to hold `jp.m`.
+func (jp *journaledPublisher) replayL() error {
+	entries, err := jp.readL()
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+
+	for i, e := range entries {
+		if err := jp.publish(e.Topic, e.Data); err != nil {
+			if werr := jp.rewriteL(entries[i:]); werr != nil {
+				return werr
+			}
+			return errors.Wrap(err, "failed to replay journaled event")
+		}
+	}
+	logrus.WithFields(logrus.Fields{
+		"count":   len(entries),
+		"journal": jp.journalPath,
+	}).Info("publishEvent - Replayed journaled events")
+	return os.Remove(jp.journalPath)
+}
+
+// rewriteL replaces the journal with `entries`. It is the callers
+// responsibility to hold `jp.m`.
+func (jp *journaledPublisher) rewriteL(entries []journalEntry) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for i := range entries {
+		if err := enc.Encode(&entries[i]); err != nil {
+			return err
+		}
+	}
+	tmp := jp.journalPath + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, jp.journalPath)
+}
+
+// readFileIfExists reads `path` returning no data if it does not exist.
+func readFileIfExists(path string) ([]byte, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil && os.IsNotExist(err) {
+		return nil, nil
 	}
+	return b, err
 }
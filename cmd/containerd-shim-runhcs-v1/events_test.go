@@ -1,7 +1,122 @@
 package main
 
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	eventstypes "github.com/containerd/containerd/api/events"
+	"github.com/containerd/containerd/runtime"
+)
+
 var _ = (publisher)(fakePublisher)
 
 func fakePublisher(topic string, event interface{}) {
 	// Do nothing
 }
+
+type testRawPublisher struct {
+	fail   bool
+	topics []string
+}
+
+func (trp *testRawPublisher) publish(topic string, data []byte) error {
+	if trp.fail {
+		return errors.New("publish failed")
+	}
+	trp.topics = append(trp.topics, topic)
+	return nil
+}
+
+func Test_JournaledPublisher_SpillAndReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	journal := filepath.Join(dir, eventJournalFile)
+
+	trp := &testRawPublisher{fail: true}
+	jp := newJournaledPublisher(trp.publish, journal)
+	// Prevent the background replay from racing the test.
+	jp.replaying = true
+
+	jp.Publish(runtime.TaskStartEventTopic, &eventstypes.TaskStart{ContainerID: t.Name()})
+	jp.Publish(runtime.TaskExitEventTopic, &eventstypes.TaskExit{ContainerID: t.Name()})
+	if _, err := os.Stat(journal); err != nil {
+		t.Fatalf("expected journal to exist: %v", err)
+	}
+
+	// Once publishing succeeds the journaled events are delivered in order
+	// before the new event.
+	trp.fail = false
+	jp.Publish(runtime.TaskDeleteEventTopic, &eventstypes.TaskDelete{ContainerID: t.Name()})
+	expected := []string{runtime.TaskStartEventTopic, runtime.TaskExitEventTopic, runtime.TaskDeleteEventTopic}
+	if len(trp.topics) != len(expected) {
+		t.Fatalf("expected topics %v, got %v", expected, trp.topics)
+	}
+	for i := range expected {
+		if trp.topics[i] != expected[i] {
+			t.Fatalf("expected topics %v, got %v", expected, trp.topics)
+		}
+	}
+	if _, err := os.Stat(journal); !os.IsNotExist(err) {
+		t.Fatalf("expected journal to be removed, got %v", err)
+	}
+}
+
+func Test_JournaledPublisher_ReplayOnStart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	journal := filepath.Join(dir, eventJournalFile)
+
+	failed := newJournaledPublisher((&testRawPublisher{fail: true}).publish, journal)
+	failed.replaying = true
+	failed.Publish(runtime.TaskExitEventTopic, &eventstypes.TaskExit{ContainerID: t.Name()})
+
+	// A new shim instance replays the journal of the previous one.
+	trp := &testRawPublisher{}
+	if err := newJournaledPublisher(trp.publish, journal).Replay(); err != nil {
+		t.Fatal(err)
+	}
+	if len(trp.topics) != 1 || trp.topics[0] != runtime.TaskExitEventTopic {
+		t.Fatalf("expected replayed TaskExit, got %v", trp.topics)
+	}
+}
+
+func Test_JournaledPublisher_Full_EvictsNonLifecycle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	journal := filepath.Join(dir, eventJournalFile)
+
+	trp := &testRawPublisher{fail: true}
+	jp := newJournaledPublisher(trp.publish, journal)
+	jp.replaying = true
+	jp.maxEntries = 2
+
+	jp.Publish(runtime.TaskStartEventTopic, &eventstypes.TaskStart{ContainerID: t.Name()})
+	jp.Publish(runtime.TaskOOMEventTopic, &eventstypes.TaskOOM{ContainerID: t.Name()})
+	jp.Publish(runtime.TaskExitEventTopic, &eventstypes.TaskExit{ContainerID: t.Name()})
+
+	trp.fail = false
+	if err := jp.Replay(); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{runtime.TaskStartEventTopic, runtime.TaskExitEventTopic}
+	if len(trp.topics) != len(expected) {
+		t.Fatalf("expected topics %v, got %v", expected, trp.topics)
+	}
+	for i := range expected {
+		if trp.topics[i] != expected[i] {
+			t.Fatalf("expected topics %v, got %v", expected, trp.topics)
+		}
+	}
+}
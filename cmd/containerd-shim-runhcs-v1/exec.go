@@ -58,7 +58,10 @@ type shimExec interface {
 	// If this exec is not a tty this exec MUST return
 	// `errdefs.ErrFailedPrecondition`.
 	//
-	// If `State() != shimExecStateRunning` this exec MUST return
+	// If `State() == shimExecStateCreated` the size is recorded and applied
+	// when the exec is started.
+	//
+	// If `State() == shimExecStateExited` this exec MUST return
 	// `errdefs.ErrFailedPrecondition`.
 	ResizePty(ctx context.Context, width, height uint32) error
 	// CloseIO closes `stdin` if open.
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/containerd/containerd/errdefs"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+)
+
+// detachedScheme is the `stdout` URI scheme that creates a detached exec. A
+// detached exec has no upstream IO and its lifetime is not tied to any client
+// connection.
+//
+// `detached://` discards all output of the exec.
+//
+// `detached:///<path>` appends the stdout and stderr of the exec to the file
+// `<path>` in the container. This is only supported for LCOW.
+const detachedScheme = "detached"
+
+// parseDetachedStdout returns `true` if `stdout` requests a detached exec and
+// the path of the file in the container to send the output to. If the output
+// is discarded the path is "".
+func parseDetachedStdout(stdout string) (bool, string, error) {
+	if !strings.HasPrefix(stdout, detachedScheme+"://") {
+		return false, "", nil
+	}
+	u, err := url.Parse(stdout)
+	if err != nil {
+		return false, "", errors.Wrapf(errdefs.ErrInvalidArgument, "invalid detached stdout '%s': %s", stdout, err)
+	}
+	if u.Host != "" {
+		return false, "", errors.Wrapf(errdefs.ErrInvalidArgument, "detached stdout '%s' must not have a host", stdout)
+	}
+	return true, u.Path, nil
+}
+
+// detachedProcessSpec returns a copy of the LCOW process `spec` that appends
+// its stdout and stderr to the file `path` in the container.
+func detachedProcessSpec(spec *specs.Process, path string) *specs.Process {
+	p := *spec
+	// `sh -c` sets `$0` to the first argument following the script and `$@` to
+	// the rest so neither `path` nor the args need to be quoted.
+	p.Args = append([]string{"/bin/sh", "-c", `exec "$@" >>"$0" 2>&1`, path}, spec.Args...)
+	return &p
+}
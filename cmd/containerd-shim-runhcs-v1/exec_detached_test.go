@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/containerd/containerd/errdefs"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+)
+
+func Test_parseDetachedStdout_NotDetached(t *testing.T) {
+	detached, path, err := parseDetachedStdout(`\\.\pipe\stdout`)
+	if err != nil || detached || path != "" {
+		t.Fatalf("expected not detached, got: %t, '%s', %v", detached, path, err)
+	}
+}
+
+func Test_parseDetachedStdout_Discard(t *testing.T) {
+	detached, path, err := parseDetachedStdout("detached://")
+	if err != nil || !detached || path != "" {
+		t.Fatalf("expected detached discard, got: %t, '%s', %v", detached, path, err)
+	}
+}
+
+func Test_parseDetachedStdout_File(t *testing.T) {
+	detached, path, err := parseDetachedStdout("detached:///var/log/daemon.log")
+	if err != nil || !detached || path != "/var/log/daemon.log" {
+		t.Fatalf("expected detached file, got: %t, '%s', %v", detached, path, err)
+	}
+}
+
+func Test_parseDetachedStdout_Host_Error(t *testing.T) {
+	_, _, err := parseDetachedStdout("detached://host/var/log/daemon.log")
+	if errors.Cause(err) != errdefs.ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument, got: %v", err)
+	}
+}
+
+func Test_detachedProcessSpec(t *testing.T) {
+	spec := &specs.Process{Args: []string{"daemon", "--flag"}}
+	p := detachedProcessSpec(spec, "/var/log/daemon.log")
+	expected := []string{"/bin/sh", "-c", `exec "$@" >>"$0" 2>&1`, "/var/log/daemon.log", "daemon", "--flag"}
+	if len(p.Args) != len(expected) {
+		t.Fatalf("expected args %v, got: %v", expected, p.Args)
+	}
+	for i := range expected {
+		if p.Args[i] != expected[i] {
+			t.Fatalf("expected args %v, got: %v", expected, p.Args)
+		}
+	}
+	if len(spec.Args) != 2 {
+		t.Fatalf("original spec must not be modified, got: %v", spec.Args)
+	}
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -10,6 +12,9 @@ import (
 	"github.com/Microsoft/hcsshim/internal/cow"
 	"github.com/Microsoft/hcsshim/internal/guestrequest"
 	"github.com/Microsoft/hcsshim/internal/hcsoci"
+	"github.com/Microsoft/hcsshim/internal/jobobject"
+	"github.com/Microsoft/hcsshim/internal/lcow"
+	"github.com/Microsoft/hcsshim/internal/oci"
 	"github.com/Microsoft/hcsshim/internal/signals"
 	"github.com/Microsoft/hcsshim/internal/uvm"
 	"github.com/Microsoft/hcsshim/osversion"
@@ -25,14 +30,19 @@ import (
 )
 
 const (
-	// processStopTimeout is the amount of time after signaling the process with
-	// a signal expected to kill the process that the exec must wait before
-	// forcibly terminating the process.
-	//
-	// For example, sending a SIGKILL is expected to kill a process. If the
-	// process does not stop within `processStopTimeout` we will forcibly
-	// terminate the process without a signal.
-	processStopTimeout = time.Second * 5
+	// forceTerminateRetries is the number of times a forced terminate of a
+	// process is attempted before escalating further.
+	forceTerminateRetries = 3
+	// forceTerminateBackoff is the amount of time waited for the process to
+	// exit after the first forced terminate. It doubles on each retry.
+	forceTerminateBackoff = time.Millisecond * 500
+	// forceTerminateTimeout is the amount of time allowed for the UVM-level
+	// kill of a process that could not be terminated, and for the process to
+	// exit after it.
+	forceTerminateTimeout = time.Second * 5
+	// oomExitCode is the exit code of a Linux process killed by SIGKILL, which
+	// is how the OOM killer kills a process.
+	oomExitCode = 128 + 9
 )
 
 // newHcsExec creates an exec to track the lifetime of `spec` in `c` which is
@@ -48,7 +58,9 @@ func newHcsExec(
 	id, bundle string,
 	isWCOW bool,
 	spec *specs.Process,
-	io upstreamIO) shimExec {
+	io upstreamIO,
+	killPolicy oci.KillPolicy,
+	limits jobobject.Limits) shimExec {
 	logrus.WithFields(logrus.Fields{
 		"tid": tid,
 		"eid": id,
@@ -64,6 +76,8 @@ func newHcsExec(
 		isWCOW:      isWCOW,
 		spec:        spec,
 		io:          io,
+		killPolicy:  killPolicy,
+		limits:      limits,
 		processDone: make(chan struct{}),
 		state:       shimExecStateCreated,
 		exitStatus:  255, // By design for non-exited process status.
@@ -73,8 +87,57 @@ func newHcsExec(
 	return he
 }
 
+// newAttachedHcsExec returns the exec tracking the running process `p` with
+// `pid` in `c`, which was started by an earlier instance of the shim. The exec
+// is in the `shimExecStateRunning` state and `Start()` only reports the start.
+// The stdio of `p` cannot be reopened so `io` is never relayed.
+func newAttachedHcsExec(
+	events publisher,
+	tid string,
+	c cow.Container,
+	id, bundle string,
+	isWCOW bool,
+	spec *specs.Process,
+	io upstreamIO,
+	killPolicy oci.KillPolicy,
+	pid int,
+	p cow.Process) shimExec {
+	logrus.WithFields(logrus.Fields{
+		"tid": tid,
+		"eid": id,
+		"pid": pid,
+	}).Debug("newAttachedHcsExec")
+
+	he := &hcsExec{
+		events:      events,
+		tid:         tid,
+		c:           c,
+		id:          id,
+		bundle:      bundle,
+		isWCOW:      isWCOW,
+		spec:        spec,
+		io:          io,
+		killPolicy:  killPolicy,
+		processDone: make(chan struct{}),
+		state:       shimExecStateRunning,
+		pid:         pid,
+		p:           hcsoci.Attach(c, p),
+		attached:    true,
+		exitStatus:  255, // By design for non-exited process status.
+		exited:      make(chan struct{}),
+	}
+	go he.waitForContainerExit()
+	go he.waitForExit()
+	return he
+}
+
 var _ = (shimExec)(&hcsExec{})
 
+// consoleSize is the width and height of a tty in characters.
+type consoleSize struct {
+	width, height uint32
+}
+
 type hcsExec struct {
 	events publisher
 	// tid is the task id of the container hosting this process.
@@ -115,7 +178,18 @@ type hcsExec struct {
 	// create time in order to be valid.
 	//
 	// This MUST be treated as read only in the lifetime of the exec.
-	io              upstreamIO
+	io upstreamIO
+	// killPolicy is the escalation policy applied when `Kill` delivers a
+	// signal that does not cause the process to exit.
+	//
+	// This MUST be treated as read only in the lifetime of the exec.
+	killPolicy oci.KillPolicy
+	// limits are applied to the process tree of this exec via a job object
+	// once the process is created. Only set for a process isolated WCOW
+	// exec.
+	//
+	// This MUST be treated as read only in the lifetime of the exec.
+	limits          jobobject.Limits
 	processDone     chan struct{}
 	processDoneOnce sync.Once
 
@@ -127,6 +201,18 @@ type hcsExec struct {
 	exitStatus uint32
 	exitedAt   time.Time
 	p          *hcsoci.Cmd
+	// killed is `true` if the shim has signaled or terminated the process. A
+	// killed process is never reported as OOM killed.
+	killed bool
+	// attached is `true` until the first `Start()` of an exec created by
+	// `newAttachedHcsExec`, whose process was already running.
+	attached bool
+	// pendingResize is the console size requested via `ResizePty` before the
+	// process was started. It is applied once the process is created.
+	pendingResize *consoleSize
+	// job is the job object enforcing `limits`. It is closed when the process
+	// exits.
+	job *jobobject.JobObject
 
 	// exited is a wait block which waits async for the process to exit.
 	exited     chan struct{}
@@ -197,6 +283,13 @@ func (he *hcsExec) Start(ctx context.Context) (err error) {
 
 	he.sl.Lock()
 	defer he.sl.Unlock()
+	if he.attached && he.state == shimExecStateRunning {
+		// The process was started by an earlier shim. Report the start so
+		// that the caller tracks it as running.
+		he.attached = false
+		he.publishStartL()
+		return nil
+	}
 	if he.state != shimExecStateCreated {
 		return newExecInvalidStateError(he.tid, he.id, he.state, "start")
 	}
@@ -205,9 +298,13 @@ func (he *hcsExec) Start(ctx context.Context) (err error) {
 			he.exitFromCreatedL(1)
 		}
 	}()
+	log := logrus.WithFields(logrus.Fields{
+		"tid": he.tid,
+		"eid": he.id,
+	})
 	if he.id == he.tid {
 		// This is the init exec. We need to start the container itself
-		err = he.c.Start()
+		err = retryTransient(ctx, log, "hcsExec::Start::StartContainer", he.c.Start)
 		if err != nil {
 			return err
 		}
@@ -218,23 +315,24 @@ func (he *hcsExec) Start(ctx context.Context) (err error) {
 			}
 		}()
 	}
-	cmd := &hcsoci.Cmd{
-		Host:   he.c,
-		Stdin:  he.io.Stdin(),
-		Stdout: he.io.Stdout(),
-		Stderr: he.io.Stderr(),
-		Log: logrus.WithFields(logrus.Fields{
-			"tid": he.tid,
-			"eid": he.id,
-		}),
-		CopyAfterExitTimeout: time.Second * 1,
-	}
-	if he.isWCOW || he.id != he.tid {
-		// An init exec passes the process as part of the config. We only pass
-		// the spec if this is a true exec.
-		cmd.Spec = he.spec
-	}
-	err = cmd.Start()
+	var cmd *hcsoci.Cmd
+	err = retryTransient(ctx, log, "hcsExec::Start::StartProcess", func() error {
+		// A Cmd can only be started once so each attempt uses a new one.
+		cmd = &hcsoci.Cmd{
+			Host:                 he.c,
+			Stdin:                he.io.Stdin(),
+			Stdout:               he.io.Stdout(),
+			Stderr:               he.io.Stderr(),
+			Log:                  log,
+			CopyAfterExitTimeout: time.Second * 1,
+		}
+		if he.isWCOW || he.id != he.tid {
+			// An init exec passes the process as part of the config. We only
+			// pass the spec if this is a true exec.
+			cmd.Spec = he.spec
+		}
+		return cmd.Start()
+	})
 	if err != nil {
 		return err
 	}
@@ -242,10 +340,45 @@ func (he *hcsExec) Start(ctx context.Context) (err error) {
 
 	// Assign the PID and transition the state.
 	he.pid = he.p.Process.Pid()
+	if he.limits != (jobobject.Limits{}) {
+		if err = he.applyLimitsL(); err != nil {
+			// The limits are enforced so the process cannot keep running
+			// without them.
+			he.p.Process.Kill()
+			he.p.Wait()
+			return err
+		}
+	}
 	he.state = shimExecStateRunning
+	he.persistStateL()
+
+	// Apply any resize requested before the process existed. The process is
+	// already running so a failure is not fatal.
+	if r := he.pendingResize; r != nil {
+		he.pendingResize = nil
+		if err := he.p.Process.ResizeConsole(uint16(r.width), uint16(r.height)); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tid":           he.tid,
+				"eid":           he.id,
+				"width":         r.width,
+				"height":        r.height,
+				logrus.ErrorKey: err,
+			}).Warning("hcsExec::Start - failed to apply pending console resize")
+		}
+	}
 
 	// Publish the task/exec start event. This MUST happen before waitForExit to
 	// avoid publishing the exit previous to the start.
+	he.publishStartL()
+
+	// wait in the background for the exit.
+	go he.waitForExit()
+	return nil
+}
+
+// publishStartL publishes the task or exec start event. It is the callers
+// responsibility to hold `he.sl`.
+func (he *hcsExec) publishStartL() {
 	if he.id != he.tid {
 		he.events(
 			runtime.TaskExecStartedEventTopic,
@@ -262,9 +395,27 @@ func (he *hcsExec) Start(ctx context.Context) (err error) {
 				Pid:         uint32(he.pid),
 			})
 	}
+}
 
-	// wait in the background for the exit.
-	go he.waitForExit()
+// applyLimitsL places the process of this exec in a new job object with
+// `he.limits`. Processes created by the exec before it is placed in the job
+// object are not limited.
+//
+// The caller MUST hold `he.sl`.
+func (he *hcsExec) applyLimitsL() error {
+	job, err := jobobject.Create()
+	if err != nil {
+		return errors.Wrapf(err, "exec: '%s' in task: '%s'", he.id, he.tid)
+	}
+	if err := job.Assign(uint32(he.pid)); err != nil {
+		job.Close()
+		return errors.Wrapf(err, "exec: '%s' in task: '%s'", he.id, he.tid)
+	}
+	if err := job.SetLimits(he.limits); err != nil {
+		job.Close()
+		return errors.Wrapf(err, "exec: '%s' in task: '%s'", he.id, he.tid)
+	}
+	he.job = job
 	return nil
 }
 
@@ -282,42 +433,24 @@ func (he *hcsExec) Kill(ctx context.Context, signal uint32) error {
 		he.exitFromCreatedL(1)
 		return nil
 	case shimExecStateRunning:
-		supported := false
-		if osversion.Get().Build >= osversion.RS5 {
-			supported = he.host == nil || he.host.SignalProcessSupported()
-		}
-		var options interface{}
-		var err error
-		if he.isWCOW {
-			var opt *guestrequest.SignalProcessOptionsWCOW
-			opt, err = signals.ValidateWCOW(int(signal), supported)
-			if opt != nil {
-				options = opt
-			}
-		} else {
-			var opt *guestrequest.SignalProcessOptionsLCOW
-			opt, err = signals.ValidateLCOW(int(signal), supported)
-			if opt != nil {
-				options = opt
-			}
-		}
-		if err != nil {
-			return errors.Wrapf(errdefs.ErrFailedPrecondition, "signal %d: %v", signal, err)
-		}
-		var delivered bool
-		if supported && options != nil {
-			delivered, err = he.p.Process.Signal(options)
-		} else {
-			// legacy path before signals support OR if WCOW with signals
-			// support needs to issue a terminate.
-			delivered, err = he.p.Process.Kill()
-		}
+		he.killed = true
+		delivered, err := he.signalL(signal)
 		if err != nil {
 			return err
 		}
 		if !delivered {
+			if !signals.IsKill(int(signal)) && !he.killPolicy.Applies(signal, he.isWCOW) {
+				return errors.Wrapf(errdefs.ErrNotFound, "exec: '%s' in task: '%s' not found", he.id, he.tid)
+			}
+			// The signal was expected to stop the process. Rather than leave
+			// a running exec that can no longer be killed, escalate in the
+			// background but still report that the process was not found.
+			go he.forceTerminate(fmt.Sprintf("signal %d was not delivered", signal))
 			return errors.Wrapf(errdefs.ErrNotFound, "exec: '%s' in task: '%s' not found", he.id, he.tid)
 		}
+		if he.killPolicy.Applies(signal, he.isWCOW) {
+			go he.escalateKill(signal)
+		}
 		return nil
 	case shimExecStateExited:
 		return errors.Wrapf(errdefs.ErrNotFound, "exec: '%s' in task: '%s' not found", he.id, he.tid)
@@ -326,6 +459,155 @@ func (he *hcsExec) Kill(ctx context.Context, signal uint32) error {
 	}
 }
 
+// signalL delivers `signal` to the running process. It is the callers
+// responsibility to hold `he.sl` and to verify that `he.state` is
+// `shimExecStateRunning`.
+func (he *hcsExec) signalL(signal uint32) (bool, error) {
+	return signalProcess(he.p.Process, signal, he.isWCOW, he.host)
+}
+
+// signalProcess delivers `signal` to `p` which runs in a Windows container if
+// `isWCOW` and in `host` if hypervisor isolated. Returns `false` if `p` had
+// already exited.
+func signalProcess(p cow.Process, signal uint32, isWCOW bool, host *uvm.UtilityVM) (bool, error) {
+	supported := false
+	if osversion.Get().Build >= osversion.RS5 {
+		supported = host == nil || host.SignalProcessSupported()
+	}
+	var options interface{}
+	var err error
+	if isWCOW {
+		var opt *guestrequest.SignalProcessOptionsWCOW
+		opt, err = signals.ValidateWCOW(int(signal), supported)
+		if opt != nil {
+			options = opt
+		}
+	} else {
+		var opt *guestrequest.SignalProcessOptionsLCOW
+		opt, err = signals.ValidateLCOW(int(signal), supported)
+		if opt != nil {
+			options = opt
+		}
+	}
+	if err != nil {
+		return false, errors.Wrapf(errdefs.ErrFailedPrecondition, "signal %d: %v", signal, err)
+	}
+	if supported && options != nil {
+		return p.Signal(options)
+	}
+	// legacy path before signals support OR if WCOW with signals support needs
+	// to issue a terminate.
+	return p.Kill()
+}
+
+// escalateKill applies `he.killPolicy` after `signal` was delivered to the
+// process. If the process does not exit within the policy timeouts it is sent
+// the escalation signal (if any) and finally forcibly terminated.
+//
+// This MUST be called via a goroutine.
+func (he *hcsExec) escalateKill(signal uint32) {
+	log := logrus.WithFields(logrus.Fields{
+		"tid":    he.tid,
+		"eid":    he.id,
+		"signal": signal,
+	})
+	if he.waitForExitTimeout(he.killPolicy.SignalTimeout) {
+		return
+	}
+	if es := he.killPolicy.EscalationSignal; es != 0 {
+		log.WithFields(logrus.Fields{
+			"escalationSignal": es,
+			"timeout":          he.killPolicy.SignalTimeout,
+		}).Warning("hcsExec::escalateKill - process did not exit after signal, escalating")
+		he.sl.Lock()
+		var err error
+		if he.state == shimExecStateRunning {
+			_, err = he.signalL(es)
+		}
+		he.sl.Unlock()
+		if err != nil {
+			log.WithError(err).Warning("hcsExec::escalateKill - failed to deliver escalation signal")
+		}
+		if he.waitForExitTimeout(he.killPolicy.EscalationTimeout) {
+			return
+		}
+	}
+	he.forceTerminate(fmt.Sprintf("process did not exit after signal %d", signal))
+}
+
+// forceTerminate forcibly terminates the process because of `reason`.
+//
+// The terminate is retried with backoff up to `forceTerminateRetries` times.
+// If the process still has not exited and it is running in an LCOW UVM it is
+// killed directly in the UVM. Each step is logged so that a process that
+// cannot be stopped is diagnosable.
+//
+// This MUST be called via a goroutine.
+func (he *hcsExec) forceTerminate(reason string) {
+	log := logrus.WithFields(logrus.Fields{
+		"tid":    he.tid,
+		"eid":    he.id,
+		"reason": reason,
+	})
+	log.Warning("hcsExec::forceTerminate - forcibly terminating process")
+
+	backoff := forceTerminateBackoff
+	for attempt := 1; attempt <= forceTerminateRetries; attempt++ {
+		he.sl.Lock()
+		running := he.state == shimExecStateRunning
+		var delivered bool
+		var err error
+		if running {
+			he.killed = true
+			delivered, err = he.p.Process.Kill()
+		}
+		he.sl.Unlock()
+		if !running {
+			return
+		}
+		if err != nil || !delivered {
+			log.WithFields(logrus.Fields{
+				"attempt":       attempt,
+				"delivered":     delivered,
+				logrus.ErrorKey: err,
+			}).Warning("hcsExec::forceTerminate - terminate failed")
+		}
+		if he.waitForExitTimeout(backoff) {
+			return
+		}
+		backoff *= 2
+	}
+
+	if he.host != nil && !he.isWCOW {
+		// The GCS reports the pid of the process in the UVM namespace so we
+		// can kill it directly.
+		pid := he.Pid()
+		log.WithField("pid", pid).Warning("hcsExec::forceTerminate - process did not exit after terminate, killing in UVM")
+		ctx, cancel := context.WithTimeout(context.Background(), forceTerminateTimeout)
+		defer cancel()
+		cmd := hcsoci.CommandContext(ctx, he.host, "kill", "-9", strconv.Itoa(pid))
+		if err := cmd.Run(); err != nil {
+			log.WithError(err).Error("hcsExec::forceTerminate - failed to kill process in UVM")
+		} else if he.waitForExitTimeout(forceTerminateTimeout) {
+			return
+		}
+	}
+	log.Error("hcsExec::forceTerminate - process did not exit")
+}
+
+// waitForExitTimeout waits up to `timeout` for the exec to exit and returns
+// `true` if it did.
+func (he *hcsExec) waitForExitTimeout(timeout time.Duration) bool {
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	select {
+	case <-he.processDone:
+		return true
+	case <-t.C:
+		return false
+	}
+}
+
 func (he *hcsExec) ResizePty(ctx context.Context, width, height uint32) error {
 	logrus.WithFields(logrus.Fields{
 		"tid":    he.tid,
@@ -336,12 +618,18 @@ func (he *hcsExec) ResizePty(ctx context.Context, width, height uint32) error {
 
 	he.sl.Lock()
 	defer he.sl.Unlock()
-	if he.state != shimExecStateRunning {
+	if he.state != shimExecStateCreated && he.state != shimExecStateRunning {
 		return newExecInvalidStateError(he.tid, he.id, he.state, "resizepty")
 	}
 	if !he.io.Terminal() {
 		return errors.Wrapf(errdefs.ErrFailedPrecondition, "exec: '%s' in task: '%s' is not a tty", he.id, he.tid)
 	}
+	if he.state == shimExecStateCreated {
+		// containerd may resize the tty before starting the exec. Apply the
+		// size once the process exists.
+		he.pendingResize = &consoleSize{width: width, height: height}
+		return nil
+	}
 
 	return he.p.Process.ResizeConsole(uint16(width), uint16(height))
 }
@@ -386,6 +674,7 @@ func (he *hcsExec) ForceExit(status int) {
 			he.exitFromCreatedL(status)
 		case shimExecStateRunning:
 			// Kill the process to unblock `he.waitForExit`
+			he.killed = true
 			he.p.Process.Kill()
 		}
 	}
@@ -420,6 +709,7 @@ func (he *hcsExec) exitFromCreatedL(status int) {
 		he.state = shimExecStateExited
 		he.exitStatus = uint32(status)
 		he.exitedAt = time.Now()
+		he.persistStateL()
 		// Release all upstream IO connections (if any)
 		he.io.Close()
 		// Free any waiters
@@ -429,6 +719,28 @@ func (he *hcsExec) exitFromCreatedL(status int) {
 	}
 }
 
+// persistStateL writes the state of this exec to the bundle: its pid once
+// running and its exit state once exited. Failures are logged as the in memory
+// state remains authoritative while the shim runs. It is the callers
+// responsibility to hold `he.sl`.
+func (he *hcsExec) persistStateL() {
+	if he.bundle == "" {
+		return
+	}
+	err := writeExecState(he.bundle, he.id, &execState{
+		Pid:        he.pid,
+		ExitStatus: he.exitStatus,
+		ExitedAt:   he.exitedAt,
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tid":           he.tid,
+			"eid":           he.id,
+			logrus.ErrorKey: err,
+		}).Warning("hcsExec::persistStateL - failed to persist state")
+	}
+}
+
 // waitForExit waits for the `he.p` to exit. This MUST only be called after a
 // successful call to `Create` and MUST not be called more than once.
 //
@@ -481,10 +793,24 @@ func (he *hcsExec) waitForExit() {
 		}).Debug("hcsExec::waitForExit - Exited")
 	}
 
+	he.sl.Lock()
+	killed := he.killed
+	he.sl.Unlock()
+	// Containers whose guest reports OOM kills publish `TaskOOM` from the
+	// task, otherwise the UVM kernel log is inspected.
+	if guestOOMNotifier(he.c, he.host) == nil && code == oomExitCode && !killed && !he.isWCOW && he.host != nil {
+		he.checkOOMKilled()
+	}
+
 	he.sl.Lock()
 	he.state = shimExecStateExited
 	he.exitStatus = uint32(code)
 	he.exitedAt = time.Now()
+	he.persistStateL()
+	if he.job != nil {
+		he.job.Close()
+		he.job = nil
+	}
 	he.sl.Unlock()
 
 	// Wait for all IO copies to complete and free the resources.
@@ -512,6 +838,36 @@ func (he *hcsExec) waitForExit() {
 	})
 }
 
+// checkOOMKilled inspects the hosting UVM to determine if the process was
+// killed by the OOM killer and if so publishes the `TaskOOM` event.
+func (he *hcsExec) checkOOMKilled() {
+	if !getConfig().featureEnabled(featureOOMEvents) {
+		return
+	}
+	log := logrus.WithFields(logrus.Fields{
+		"tid": he.tid,
+		"eid": he.id,
+		"pid": he.pid,
+	})
+	kind, err := lcow.OOMKilled(context.Background(), he.host, lcow.ContainerCgroup(he.tid), he.pid)
+	if err != nil {
+		log.WithError(err).Warning("hcsExec::checkOOMKilled - failed to inspect UVM")
+		return
+	}
+	if kind == lcow.OOMKindNone {
+		return
+	}
+	log.WithFields(logrus.Fields{
+		"reason":  "OOMKilled",
+		"oomKind": kind,
+	}).Warning("hcsExec::checkOOMKilled - process was killed by the OOM killer")
+	he.events(
+		runtime.TaskOOMEventTopic,
+		&eventstypes.TaskOOM{
+			ContainerID: he.tid,
+		})
+}
+
 // waitForContainerExit waits for `he.c` to exit. Depending on the exec's state
 // will forcibly transition this exec to the exited state and unblock any
 // waiters.
@@ -533,6 +889,7 @@ func (he *hcsExec) waitForContainerExit() {
 			he.exitFromCreatedL(1)
 		case shimExecStateRunning:
 			// Kill the process to unblock `he.waitForExit`.
+			he.killed = true
 			he.p.Process.Kill()
 		}
 		he.sl.Unlock()
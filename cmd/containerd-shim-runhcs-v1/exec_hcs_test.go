@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/cow/cowtest"
+	"github.com/Microsoft/hcsshim/internal/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/hcsoci"
+	"github.com/Microsoft/hcsshim/internal/oci"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/runtime"
+	"github.com/pkg/errors"
+)
+
+func Test_hcsExec_ResizePty_Created_Pending(t *testing.T) {
+	he := &hcsExec{
+		tid:   t.Name(),
+		id:    t.Name(),
+		io:    &npipeio{terminal: true},
+		state: shimExecStateCreated,
+	}
+
+	if err := he.ResizePty(context.TODO(), 80, 24); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if r := he.pendingResize; r == nil || r.width != 80 || r.height != 24 {
+		t.Fatalf("expected pending resize 80x24, got: %+v", r)
+	}
+}
+
+func Test_hcsExec_ResizePty_NotTerminal_Error(t *testing.T) {
+	he := &hcsExec{
+		tid:   t.Name(),
+		id:    t.Name(),
+		io:    &npipeio{},
+		state: shimExecStateCreated,
+	}
+
+	err := he.ResizePty(context.TODO(), 80, 24)
+	if errors.Cause(err) != errdefs.ErrFailedPrecondition {
+		t.Fatalf("expected ErrFailedPrecondition, got: %v", err)
+	}
+	if he.pendingResize != nil {
+		t.Fatal("expected no pending resize")
+	}
+}
+
+func Test_hcsExec_ResizePty_Exited_Error(t *testing.T) {
+	he := &hcsExec{
+		tid:   t.Name(),
+		id:    t.Name(),
+		io:    &npipeio{terminal: true},
+		state: shimExecStateExited,
+	}
+
+	err := he.ResizePty(context.TODO(), 80, 24)
+	if errors.Cause(err) != errdefs.ErrFailedPrecondition {
+		t.Fatalf("expected ErrFailedPrecondition, got: %v", err)
+	}
+}
+
+func Test_hcsExec_Kill_NonStopSignal_NotEscalated(t *testing.T) {
+	p := cowtest.NewProcess(10)
+	he := &hcsExec{
+		tid:         t.Name(),
+		id:          t.Name(),
+		p:           &hcsoci.Cmd{Process: p},
+		killPolicy:  oci.KillPolicy{SignalTimeout: time.Millisecond, StopSignalsOnly: true},
+		processDone: make(chan struct{}),
+		state:       shimExecStateRunning,
+	}
+
+	// SIGHUP
+	if err := he.Kill(context.TODO(), 0x1); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if len(p.Signals()) != 1 {
+		t.Fatalf("expected the signal to be delivered once, got: %d", len(p.Signals()))
+	}
+	if p.Kills() != 0 {
+		t.Fatal("expected the process not to be terminated after a non-stop signal")
+	}
+}
+
+// newEscalationTestExec returns a running exec of `p` with `policy` whose
+// `processDone` is closed when `p` exits.
+func newEscalationTestExec(t *testing.T, p *cowtest.Process, policy oci.KillPolicy) *hcsExec {
+	he := &hcsExec{
+		tid:         t.Name(),
+		id:          t.Name(),
+		p:           &hcsoci.Cmd{Process: p},
+		killPolicy:  policy,
+		processDone: make(chan struct{}),
+		state:       shimExecStateRunning,
+	}
+	go func() {
+		<-p.Exited()
+		close(he.processDone)
+	}()
+	return he
+}
+
+func waitForFakeExit(t *testing.T, p *cowtest.Process) {
+	select {
+	case <-p.Exited():
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the process to exit")
+	}
+}
+
+func Test_hcsExec_Kill_Escalates_EscalationSignal(t *testing.T) {
+	p := cowtest.NewProcess(10)
+	p.OnSignal = func(p *cowtest.Process, options interface{}) (bool, error) {
+		// Ignore everything but SIGKILL.
+		if o, ok := options.(*guestrequest.SignalProcessOptionsLCOW); ok && o.Signal == 0x9 {
+			p.Exit(137, nil)
+		}
+		return true, nil
+	}
+	he := newEscalationTestExec(t, p, oci.KillPolicy{
+		SignalTimeout:     10 * time.Millisecond,
+		EscalationSignal:  0x9,
+		EscalationTimeout: 5 * time.Second,
+	})
+
+	// SIGTERM
+	if err := he.Kill(context.TODO(), 0xf); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	waitForFakeExit(t, p)
+	if len(p.Signals()) != 2 {
+		t.Fatalf("expected the signal and the escalation signal, got: %d signals", len(p.Signals()))
+	}
+	if p.Kills() != 0 {
+		t.Fatal("expected the process not to be terminated after the escalation signal")
+	}
+}
+
+func Test_hcsExec_Kill_Escalates_Terminate(t *testing.T) {
+	p := cowtest.NewProcess(10)
+	he := newEscalationTestExec(t, p, oci.KillPolicy{
+		SignalTimeout: 10 * time.Millisecond,
+	})
+
+	// SIGTERM, which the fake process ignores.
+	if err := he.Kill(context.TODO(), 0xf); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	waitForFakeExit(t, p)
+	if len(p.Signals()) != 1 {
+		t.Fatalf("expected the signal to be delivered once, got: %d", len(p.Signals()))
+	}
+	if p.Kills() != 1 {
+		t.Fatalf("expected the process to be terminated once, got: %d", p.Kills())
+	}
+}
+
+func Test_hcsExec_Kill_NotDelivered_NotFound(t *testing.T) {
+	p := cowtest.NewProcess(10)
+	p.OnSignal = func(p *cowtest.Process, options interface{}) (bool, error) {
+		return false, nil
+	}
+	he := newEscalationTestExec(t, p, oci.KillPolicy{})
+
+	// SIGKILL
+	err := he.Kill(context.TODO(), 0x9)
+	if errors.Cause(err) != errdefs.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got: %v", err)
+	}
+	// The exec is still forcibly terminated in the background.
+	waitForFakeExit(t, p)
+	if p.Kills() != 1 {
+		t.Fatalf("expected the process to be terminated once, got: %d", p.Kills())
+	}
+}
+
+func Test_hcsExec_Attached_StartReportsRunning(t *testing.T) {
+	c := cowtest.NewContainer(t.Name(), "windows", false)
+	p := cowtest.NewProcess(10)
+	var topics []string
+	events := func(topic string, event interface{}) {
+		topics = append(topics, topic)
+	}
+	he := newAttachedHcsExec(events, t.Name(), c, t.Name(), "", true, nil, &npipeio{}, oci.KillPolicy{}, 10, p)
+	if he.State() != shimExecStateRunning || he.Pid() != 10 {
+		t.Fatalf("expected running exec with pid 10, got: %s %d", he.State(), he.Pid())
+	}
+
+	if err := he.Start(context.TODO()); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if len(topics) != 1 || topics[0] != runtime.TaskStartEventTopic {
+		t.Fatalf("expected the start event, got: %v", topics)
+	}
+	err := he.Start(context.TODO())
+	if errors.Cause(err) != errdefs.ErrFailedPrecondition {
+		t.Fatalf("expected ErrFailedPrecondition on a second start, got: %v", err)
+	}
+
+	p.Exit(3, nil)
+	status := he.Wait(context.TODO())
+	if status.ExitStatus != 3 {
+		t.Fatalf("expected exit status 3, got: %d", status.ExitStatus)
+	}
+}
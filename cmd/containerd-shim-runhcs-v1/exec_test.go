@@ -27,6 +27,12 @@ type testShimExec struct {
 	at     time.Time
 
 	state shimExecState
+	// signals records every signal sent to `Kill` in order.
+	signals []uint32
+
+	// waiting and exited, if set, make `Wait` signal `waiting` and then block
+	// until `exited` is closed.
+	waiting, exited chan struct{}
 }
 
 func (tse *testShimExec) ID() string {
@@ -53,6 +59,7 @@ func (tse *testShimExec) Start(ctx context.Context) error {
 	return nil
 }
 func (tse *testShimExec) Kill(ctx context.Context, signal uint32) error {
+	tse.signals = append(tse.signals, signal)
 	tse.state = shimExecStateExited
 	tse.status = 0
 	tse.at = time.Now()
@@ -65,6 +72,10 @@ func (tse *testShimExec) CloseIO(ctx context.Context, stdin bool) error {
 	return nil
 }
 func (tse *testShimExec) Wait(ctx context.Context) *task.StateResponse {
+	if tse.exited != nil {
+		close(tse.waiting)
+		<-tse.exited
+	}
 	return tse.Status()
 }
 func (tse *testShimExec) ForceExit(status int) {
@@ -189,7 +189,7 @@ func (wpse *wcowPodSandboxExec) ResizePty(ctx context.Context, width, height uin
 
 	wpse.sl.Lock()
 	defer wpse.sl.Unlock()
-	if wpse.state != shimExecStateRunning {
+	if wpse.state != shimExecStateCreated && wpse.state != shimExecStateRunning {
 		return newExecInvalidStateError(wpse.tid, wpse.tid, wpse.state, "resizepty")
 	}
 	// We will never have IO for a sandbox container so we wont have a tty
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -224,9 +225,12 @@ func Test_newWcowPodSandboxExec_Kill_Started(t *testing.T) {
 func Test_newWcowPodSandboxExec_ResizePty(t *testing.T) {
 	wpse := newWcowPodSandboxExec(context.TODO(), fakePublisher, t.Name(), t.Name())
 
-	// Resize in created state
+	// Resize in created state fails because the sandbox is never a tty
 	err := wpse.ResizePty(context.TODO(), 10, 10)
 	verifyExpectedError(t, nil, err, errdefs.ErrFailedPrecondition)
+	if !strings.Contains(err.Error(), "is not a tty") {
+		t.Fatalf("expected not a tty error in created state, got: %v", err)
+	}
 
 	// Start it
 	err = wpse.Start(context.TODO())
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	eventstypes "github.com/containerd/containerd/api/events"
+	"github.com/sirupsen/logrus"
+)
+
+// exitSequencerTimeout is the maximum time the init `TaskExit` is held back
+// waiting for the exits of running execs to be published.
+const exitSequencerTimeout = time.Second * 10
+
+// newExitSequencer returns a sequencer that publishes the events of task `tid`
+// via `events`.
+//
+// When a task is killed its init exec and all of its execs exit at nearly the
+// same time and each publishes its `TaskExit` from its own goroutine. The
+// sequencer guarantees that each `TaskExit` is published exactly once and that
+// the exit of every started exec is published before the exit of the init
+// exec.
+func newExitSequencer(tid string, events publisher) *exitSequencer {
+	return &exitSequencer{
+		tid:       tid,
+		events:    events,
+		running:   make(map[string]struct{}),
+		exited:    make(map[string]struct{}),
+		execsDone: make(chan struct{}),
+	}
+}
+
+type exitSequencer struct {
+	// tid and events MUST be treated as readonly in the lifetime of the
+	// sequencer.
+	tid    string
+	events publisher
+
+	// m MUST be held to safely read/write any of the following members.
+	m sync.Mutex
+	// running is the set of exec ids that have started but whose exit has
+	// not been published.
+	running map[string]struct{}
+	// exited is the set of exec ids whose exit has been published and that
+	// have not yet been deleted.
+	exited map[string]struct{}
+	// initExiting is `true` once the init exit has been received. No
+	// further execs can start in the task.
+	initExiting bool
+	// execsDone is closed once `initExiting` and `running` is empty.
+	execsDone     chan struct{}
+	execsDoneOnce sync.Once
+}
+
+// Publish is a `publisher` that forwards all events to the task publisher
+// ordering and deduplicating `TaskExit` events.
+func (es *exitSequencer) Publish(topic string, event interface{}) {
+	switch e := event.(type) {
+	case *eventstypes.TaskExecStarted:
+		es.m.Lock()
+		es.running[e.ExecID] = struct{}{}
+		es.m.Unlock()
+	case *eventstypes.TaskExit:
+		if e.ID == es.tid {
+			es.publishInitExit(topic, e)
+		} else {
+			es.publishExecExit(topic, e)
+		}
+		return
+	case *eventstypes.TaskDelete:
+		if e.ID != "" {
+			// The exec id may be reused by a later exec whose exit must not
+			// be dropped as a duplicate.
+			es.m.Lock()
+			delete(es.exited, e.ID)
+			delete(es.running, e.ID)
+			es.checkExecsDoneL()
+			es.m.Unlock()
+		}
+	}
+	es.events(topic, event)
+}
+
+// publishExecExit publishes the exit of an exec unless it was already
+// published.
+func (es *exitSequencer) publishExecExit(topic string, e *eventstypes.TaskExit) {
+	es.m.Lock()
+	if _, ok := es.exited[e.ID]; ok {
+		es.m.Unlock()
+		return
+	}
+	es.exited[e.ID] = struct{}{}
+	es.m.Unlock()
+
+	es.events(topic, e)
+
+	// Only release the init exit once this exit has been published.
+	es.m.Lock()
+	delete(es.running, e.ID)
+	es.checkExecsDoneL()
+	es.m.Unlock()
+}
+
+// publishInitExit publishes the exit of the init exec once the exits of all
+// running execs have been published or after `exitSequencerTimeout`.
+func (es *exitSequencer) publishInitExit(topic string, e *eventstypes.TaskExit) {
+	es.m.Lock()
+	if es.initExiting {
+		es.m.Unlock()
+		return
+	}
+	es.initExiting = true
+	es.checkExecsDoneL()
+	es.m.Unlock()
+
+	t := time.NewTimer(exitSequencerTimeout)
+	select {
+	case <-es.execsDone:
+		t.Stop()
+	case <-t.C:
+		es.m.Lock()
+		pending := make([]string, 0, len(es.running))
+		for eid := range es.running {
+			pending = append(pending, eid)
+		}
+		es.m.Unlock()
+		logrus.WithFields(logrus.Fields{
+			"tid":     es.tid,
+			"pending": pending,
+		}).Warning("exitSequencer::publishInitExit - timed out waiting for exec exits")
+	}
+	es.events(topic, e)
+}
+
+// checkExecsDoneL closes `execsDone` if the init exec is exiting and no execs
+// are running. It is the callers responsibility to hold `es.m`.
+func (es *exitSequencer) checkExecsDoneL() {
+	if es.initExiting && len(es.running) == 0 {
+		es.execsDoneOnce.Do(func() { close(es.execsDone) })
+	}
+}
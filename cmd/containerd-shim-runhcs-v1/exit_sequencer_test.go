@@ -0,0 +1,96 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	eventstypes "github.com/containerd/containerd/api/events"
+	"github.com/containerd/containerd/runtime"
+)
+
+type recordingPublisher struct {
+	m      sync.Mutex
+	events []interface{}
+}
+
+func (rp *recordingPublisher) publish(topic string, event interface{}) {
+	rp.m.Lock()
+	defer rp.m.Unlock()
+	rp.events = append(rp.events, event)
+}
+
+func (rp *recordingPublisher) exits() []string {
+	rp.m.Lock()
+	defer rp.m.Unlock()
+	var ids []string
+	for _, e := range rp.events {
+		if te, ok := e.(*eventstypes.TaskExit); ok {
+			ids = append(ids, te.ID)
+		}
+	}
+	return ids
+}
+
+func Test_exitSequencer_InitExitAfterExecExits(t *testing.T) {
+	rp := &recordingPublisher{}
+	es := newExitSequencer(t.Name(), rp.publish)
+
+	es.Publish(runtime.TaskExecStartedEventTopic, &eventstypes.TaskExecStarted{ContainerID: t.Name(), ExecID: "exec1"})
+	es.Publish(runtime.TaskExecStartedEventTopic, &eventstypes.TaskExecStarted{ContainerID: t.Name(), ExecID: "exec2"})
+
+	done := make(chan struct{})
+	go func() {
+		es.Publish(runtime.TaskExitEventTopic, &eventstypes.TaskExit{ContainerID: t.Name(), ID: t.Name()})
+		close(done)
+	}()
+	es.Publish(runtime.TaskExitEventTopic, &eventstypes.TaskExit{ContainerID: t.Name(), ID: "exec1"})
+	// Duplicate exits are dropped.
+	es.Publish(runtime.TaskExitEventTopic, &eventstypes.TaskExit{ContainerID: t.Name(), ID: "exec1"})
+	select {
+	case <-done:
+		t.Fatal("init exit published before all exec exits")
+	case <-time.After(10 * time.Millisecond):
+	}
+	es.Publish(runtime.TaskExitEventTopic, &eventstypes.TaskExit{ContainerID: t.Name(), ID: "exec2"})
+	<-done
+	// Duplicate init exits are dropped.
+	es.Publish(runtime.TaskExitEventTopic, &eventstypes.TaskExit{ContainerID: t.Name(), ID: t.Name()})
+
+	exits := rp.exits()
+	if len(exits) != 3 || exits[0] != "exec1" || exits[1] != "exec2" || exits[2] != t.Name() {
+		t.Fatalf("unexpected exit order: %v", exits)
+	}
+}
+
+func Test_exitSequencer_InitExitNoExecs(t *testing.T) {
+	rp := &recordingPublisher{}
+	es := newExitSequencer(t.Name(), rp.publish)
+
+	es.Publish(runtime.TaskCreateEventTopic, &eventstypes.TaskCreate{ContainerID: t.Name()})
+	es.Publish(runtime.TaskExitEventTopic, &eventstypes.TaskExit{ContainerID: t.Name(), ID: t.Name()})
+
+	if len(rp.events) != 2 {
+		t.Fatalf("expected 2 events, got: %d", len(rp.events))
+	}
+	if exits := rp.exits(); len(exits) != 1 || exits[0] != t.Name() {
+		t.Fatalf("unexpected exits: %v", exits)
+	}
+}
+
+func Test_exitSequencer_ExecIDReusedAfterDelete(t *testing.T) {
+	rp := &recordingPublisher{}
+	es := newExitSequencer(t.Name(), rp.publish)
+
+	es.Publish(runtime.TaskExecStartedEventTopic, &eventstypes.TaskExecStarted{ContainerID: t.Name(), ExecID: "exec1"})
+	es.Publish(runtime.TaskExitEventTopic, &eventstypes.TaskExit{ContainerID: t.Name(), ID: "exec1", Pid: 10})
+	es.Publish(runtime.TaskDeleteEventTopic, &eventstypes.TaskDelete{ContainerID: t.Name(), ID: "exec1"})
+
+	// A later exec with the same id publishes its own exit.
+	es.Publish(runtime.TaskExecStartedEventTopic, &eventstypes.TaskExecStarted{ContainerID: t.Name(), ExecID: "exec1"})
+	es.Publish(runtime.TaskExitEventTopic, &eventstypes.TaskExit{ContainerID: t.Name(), ID: "exec1", Pid: 20})
+
+	if exits := rp.exits(); len(exits) != 2 {
+		t.Fatalf("expected 2 exits, got: %v", exits)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"sort"
+	"sync"
+	"time"
+
+	winio "github.com/Microsoft/go-winio"
+	"github.com/Microsoft/go-winio/pkg/guid"
+	"github.com/Microsoft/hcsshim/internal/timeout"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// binaryIOExitTimeout is the time the logging binary is given to exit once its
+// output is closed before it is killed.
+const binaryIOExitTimeout = time.Second * 10
+
+// newBinaryIO starts the logging binary of the `binary://` log URI `u` for
+// task/exec `tid,eid` and returns the upstream `stdout` and, if `stderr ==
+// true`, `stderr` connected to it.
+//
+// The binary is started with the query of `u` as its arguments and is passed
+// the named pipes to dial in the environment as expected by the containerd
+// `runtime/v2/logging` package. It signals that it is ready by closing its
+// connection to `CONTAINER_WAIT`.
+func newBinaryIO(ctx context.Context, tid, eid string, u *url.URL, stderr bool) (_, _ io.WriteCloser, err error) {
+	g, err := guid.NewV4()
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		names = []string{"stdout", "stderr", "wait"}
+		paths = make([]string, len(names))
+		ls    = make([]net.Listener, len(names))
+	)
+	defer func() {
+		for _, l := range ls {
+			if l != nil {
+				l.Close()
+			}
+		}
+	}()
+	for i, name := range names {
+		paths[i] = fmt.Sprintf(`\\.\pipe\binary-%s-%s-%s-%s`, tid, eid, g, name)
+		ls[i], err = winio.ListenPipe(paths[i], nil)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to listen on log pipe '%s'", paths[i])
+		}
+	}
+
+	path := uriHostPath(u)
+	cmd := exec.Command(path, binaryArgs(u)...)
+	cmd.Env = append(os.Environ(),
+		"CONTAINER_ID="+tid,
+		"CONTAINER_NAMESPACE="+namespaceFlag,
+		"CONTAINER_STDOUT="+paths[0],
+		"CONTAINER_STDERR="+paths[1],
+		"CONTAINER_WAIT="+paths[2],
+	)
+	if err := cmd.Start(); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to start logging binary '%s'", path)
+	}
+	b := &binaryIO{
+		tid:    tid,
+		eid:    eid,
+		cmd:    cmd,
+		exited: make(chan struct{}),
+	}
+	go func() {
+		cmd.Wait()
+		close(b.exited)
+	}()
+	defer func() {
+		if err != nil {
+			cmd.Process.Kill()
+			b.Close()
+		}
+	}()
+
+	conns, err := b.acceptAll(ctx, ls)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "logging binary '%s' failed to connect", path)
+	}
+	b.sout, b.serr = conns[0], conns[1]
+	if !stderr {
+		// The binary always dials `stderr`. Close it so it reads EOF.
+		b.serr.Close()
+	}
+	var serr io.WriteCloser
+	if stderr {
+		serr = &binaryStream{Writer: b.serr, b: b}
+	}
+	return &binaryStream{Writer: b.sout, b: b}, serr, nil
+}
+
+// binaryArgs returns the arguments of the logging binary in the query of the
+// `binary://` log URI `u`. Each key is followed by its first value, if any.
+func binaryArgs(u *url.URL) []string {
+	q := u.Query()
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var args []string
+	for _, k := range keys {
+		args = append(args, k)
+		if vs := q[k]; len(vs) > 0 && vs[0] != "" {
+			args = append(args, vs[0])
+		}
+	}
+	return args
+}
+
+// binaryIO is a running logging binary.
+type binaryIO struct {
+	tid, eid string
+	cmd      *exec.Cmd
+	// exited is closed once the binary has exited.
+	exited chan struct{}
+
+	// sout and serr are the connections from the binary. They MUST be
+	// treated as readonly once `newBinaryIO` returns.
+	sout, serr net.Conn
+	closeOnce  sync.Once
+}
+
+// acceptAll accepts a connection from the binary on each of `ls` and waits
+// for the binary to close the last, the `wait` connection, to signal that it
+// is ready. It fails if the binary exits or is not ready in
+// `timeout.ExternalCommandToStart`.
+func (b *binaryIO) acceptAll(ctx context.Context, ls []net.Listener) ([]net.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout.Load(&timeout.ExternalCommandToStart))
+	defer cancel()
+
+	type result struct {
+		i   int
+		c   net.Conn
+		err error
+	}
+	results := make(chan result, len(ls))
+	for i, l := range ls {
+		go func(i int, l net.Listener) {
+			c, err := l.Accept()
+			if err == nil && i == len(ls)-1 {
+				// The binary closes `wait` once it is ready.
+				_, err = io.Copy(ioutil.Discard, c)
+				c.Close()
+			}
+			results <- result{i, c, err}
+		}(i, l)
+	}
+
+	conns := make([]net.Conn, len(ls))
+	var err error
+	for range ls {
+		select {
+		case r := <-results:
+			conns[r.i] = r.c
+			if r.err != nil && err == nil {
+				err = r.err
+			}
+			continue
+		case <-b.exited:
+			err = errors.New("logging binary exited")
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		break
+	}
+	if err != nil {
+		// Unblock any pending accepts and release the accepted connections.
+		for _, l := range ls {
+			l.Close()
+		}
+		for _, c := range conns {
+			if c != nil {
+				c.Close()
+			}
+		}
+		return nil, err
+	}
+	return conns, nil
+}
+
+// Close closes the output to the binary and waits for it to exit. If the
+// binary does not exit in `binaryIOExitTimeout` it is killed.
+func (b *binaryIO) Close() error {
+	b.closeOnce.Do(func() {
+		if b.sout != nil {
+			b.sout.Close()
+		}
+		if b.serr != nil {
+			b.serr.Close()
+		}
+		select {
+		case <-b.exited:
+		case <-time.After(binaryIOExitTimeout):
+			logrus.WithFields(logrus.Fields{
+				"tid": b.tid,
+				"eid": b.eid,
+			}).Warning("binaryIO::Close - logging binary did not exit, killing")
+			b.cmd.Process.Kill()
+			<-b.exited
+		}
+	})
+	return nil
+}
+
+// binaryStream is the upstream `stdout` or `stderr` of a logging binary.
+// Closing either closes the binary.
+type binaryStream struct {
+	io.Writer
+	b *binaryIO
+}
+
+func (bs *binaryStream) Close() error {
+	return bs.b.Close()
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// isLogURI returns `true` if the upstream `stdout` or `stderr` at `path` is a
+// containerd log URI rather than a named pipe.
+func isLogURI(path string) bool {
+	return strings.HasPrefix(path, "binary://") || strings.HasPrefix(path, "file://")
+}
+
+// openLogURI opens the containerd log URI `uri` as the upstream `stdout` and,
+// if `stderr == true`, the upstream `stderr` of task/exec `tid,eid`. This is
+// one of:
+//
+// `binary:///<host path>[?<args>]` which starts the logging binary. See
+// `newBinaryIO`.
+//
+// `file:///<host path>[?maxSize=<bytes>&maxFiles=<count>]` which appends to the
+// file on the host. If `maxSize` is set the file is rotated once it would
+// exceed `maxSize` bytes keeping at most `maxFiles` rotated files named
+// `<host path>.1`, `<host path>.2`, ... If `maxFiles` is `0` the file is
+// truncated instead.
+func openLogURI(ctx context.Context, tid, eid, uri string, stderr bool) (sout, serr io.WriteCloser, err error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, nil, errors.Wrapf(errdefs.ErrInvalidArgument, "invalid log uri '%s': %s", uri, err)
+	}
+	if u.Host != "" {
+		return nil, nil, errors.Wrapf(errdefs.ErrInvalidArgument, "log uri '%s' must not have a host", uri)
+	}
+	if uriHostPath(u) == "" {
+		return nil, nil, errors.Wrapf(errdefs.ErrInvalidArgument, "log uri '%s' has no path", uri)
+	}
+	switch u.Scheme {
+	case "binary":
+		return newBinaryIO(ctx, tid, eid, u, stderr)
+	case "file":
+		f, err := newRotatingFile(u)
+		if err != nil {
+			return nil, nil, err
+		}
+		if stderr {
+			// Both streams are written to the same file which is closed with
+			// `stdout`.
+			serr = nopCloseWriter{f}
+		}
+		return f, serr, nil
+	default:
+		return nil, nil, errors.Wrapf(errdefs.ErrInvalidArgument, "unsupported log uri scheme '%s'", u.Scheme)
+	}
+}
+
+// nopCloseWriter is a writer whose `Close` has no effect. It is used when the
+// underlying writer is shared and closed elsewhere.
+type nopCloseWriter struct {
+	io.Writer
+}
+
+func (nopCloseWriter) Close() error {
+	return nil
+}
+
+// rotatingFile is the upstream output for a `file://` log URI.
+type rotatingFile struct {
+	// path, maxSize and maxFiles MUST be treated as readonly in the lifetime
+	// of the file.
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	// m MUST be held to safely read/write `f` and `size`.
+	m    sync.Mutex
+	f    *os.File
+	size int64
+}
+
+// newRotatingFile opens the file of the `file://` log URI `u` for append.
+func newRotatingFile(u *url.URL) (*rotatingFile, error) {
+	rf := &rotatingFile{
+		path: uriHostPath(u),
+	}
+	q := u.Query()
+	if v := q.Get("maxSize"); v != "" {
+		size, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || size < 0 {
+			return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "invalid log uri maxSize '%s'", v)
+		}
+		rf.maxSize = size
+	}
+	if v := q.Get("maxFiles"); v != "" {
+		files, err := strconv.Atoi(v)
+		if err != nil || files < 0 {
+			return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "invalid log uri maxFiles '%s'", v)
+		}
+		rf.maxFiles = files
+	}
+	if err := os.MkdirAll(filepath.Dir(rf.path), 0); err != nil {
+		return nil, errors.Wrapf(err, "failed to create log directory for '%s'", rf.path)
+	}
+	if err := rf.openL(os.O_APPEND); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// openL opens `rf.path` with the additional `flag`. It is the callers
+// responsibility to hold `rf.m`.
+func (rf *rotatingFile) openL(flag int) error {
+	f, err := os.OpenFile(rf.path, os.O_WRONLY|os.O_CREATE|flag, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open log file '%s'", rf.path)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	rf.f = f
+	rf.size = fi.Size()
+	return nil
+}
+
+func (rf *rotatingFile) Write(b []byte) (int, error) {
+	rf.m.Lock()
+	defer rf.m.Unlock()
+	if rf.f == nil {
+		return 0, os.ErrClosed
+	}
+	if rf.maxSize > 0 && rf.size > 0 && rf.size+int64(len(b)) > rf.maxSize {
+		if err := rf.rotateL(); err != nil {
+			// Keep writing to the current file rather than fail the output
+			// of the process.
+			logrus.WithFields(logrus.Fields{
+				"path":          rf.path,
+				logrus.ErrorKey: err,
+			}).Warning("rotatingFile::Write - failed to rotate log file")
+			if rf.f == nil {
+				if err := rf.openL(os.O_APPEND); err != nil {
+					return 0, err
+				}
+			}
+		}
+	}
+	n, err := rf.f.Write(b)
+	rf.size += int64(n)
+	return n, err
+}
+
+// rotateL shifts each rotated file up by one, dropping the oldest, and starts
+// a new file. It is the callers responsibility to hold `rf.m`.
+func (rf *rotatingFile) rotateL() error {
+	rf.f.Close()
+	rf.f = nil
+	if rf.maxFiles > 0 {
+		for i := rf.maxFiles - 1; i > 0; i-- {
+			from := rf.path + "." + strconv.Itoa(i)
+			if err := os.Rename(from, rf.path+"."+strconv.Itoa(i+1)); err != nil && !os.IsNotExist(err) {
+				return errors.Wrapf(err, "failed to rotate log file '%s'", from)
+			}
+		}
+		if err := os.Rename(rf.path, rf.path+".1"); err != nil {
+			return errors.Wrapf(err, "failed to rotate log file '%s'", rf.path)
+		}
+	}
+	return rf.openL(os.O_TRUNC)
+}
+
+func (rf *rotatingFile) Close() error {
+	rf.m.Lock()
+	defer rf.m.Unlock()
+	if rf.f == nil {
+		return nil
+	}
+	err := rf.f.Close()
+	rf.f = nil
+	return err
+}
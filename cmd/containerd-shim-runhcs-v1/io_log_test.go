@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/pkg/errors"
+)
+
+func Test_openLogURI_Invalid(t *testing.T) {
+	for _, uri := range []string{
+		"file://host/C:/logs/out.log",
+		"file://",
+		"file:///C:/logs/out.log?maxSize=-1",
+		"file:///C:/logs/out.log?maxFiles=abc",
+		"ftp:///C:/logs/out.log",
+	} {
+		if _, _, err := openLogURI(context.TODO(), t.Name(), t.Name(), uri, true); errors.Cause(err) != errdefs.ErrInvalidArgument {
+			t.Fatalf("expected ErrInvalidArgument for '%s', got: %v", uri, err)
+		}
+	}
+}
+
+func Test_openLogURI_File_SharedStdoutStderr(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "nested", "out.log")
+
+	sout, serr, err := openLogURI(context.TODO(), t.Name(), t.Name(), "file:///"+filepath.ToSlash(path), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sout.Write([]byte("out\n"))
+	serr.Write([]byte("err\n"))
+	serr.Close()
+	sout.Close()
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "out\nerr\n" {
+		t.Fatalf("expected both streams in the file, got: %q", string(b))
+	}
+}
+
+func Test_rotatingFile_Rotate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "out.log")
+
+	u, _ := url.Parse("file:///" + filepath.ToSlash(path) + "?maxSize=4&maxFiles=2")
+	rf, err := newRotatingFile(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"aaaa", "bbbb", "cccc", "dddd"} {
+		if _, err := rf.Write([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rf.Close()
+
+	expected := map[string]string{
+		path:        "dddd",
+		path + ".1": "cccc",
+		path + ".2": "bbbb",
+	}
+	for p, content := range expected {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != content {
+			t.Fatalf("expected '%s' in '%s', got: '%s'", content, p, string(b))
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Fatalf("expected at most 2 rotated files, got: %v", err)
+	}
+}
+
+func Test_binaryArgs(t *testing.T) {
+	u, _ := url.Parse("binary:///C:/bin/logger.exe?id=abc&verbose&address=localhost")
+	expected := []string{"address", "localhost", "id", "abc", "verbose"}
+	if args := binaryArgs(u); !reflect.DeepEqual(args, expected) {
+		t.Fatalf("expected args %v, got %v", expected, args)
+	}
+}
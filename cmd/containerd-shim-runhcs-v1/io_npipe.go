@@ -5,13 +5,23 @@ import (
 	"io"
 	"sync"
 
-	winio "github.com/Microsoft/go-winio"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
 // newNpipeIO creates connected upstream io for task/exec `tid,eid`. It is the
 // callers responsibility to validate that `if terminal == true`, `stderr ==
 // ""`.
+//
+// `stdin` may also be a `file://` URI of a file in the
+// `stdinFileDirectories` of the shim config or a `data:` URI. See `openStdin`.
+//
+// `stdin`, `stdout` and `stderr` may be relayed over any of the
+// `upstreamTransports` rather than a named pipe.
+//
+// `stdout` may also be a containerd `binary://` or `file://` log URI in which
+// case `stderr` MUST be `""` or the same URI. See `openLogURI`.
 func newNpipeIO(ctx context.Context, tid, eid string, stdin, stdout, stderr string, terminal bool) (_ upstreamIO, err error) {
 	logrus.WithFields(logrus.Fields{
 		"tid":      tid,
@@ -36,25 +46,35 @@ func newNpipeIO(ctx context.Context, tid, eid string, stdin, stdout, stderr stri
 		}
 	}()
 	if stdin != "" {
-		c, err := winio.DialPipe(stdin, nil)
+		c, err := openStdin(stdin, getConfig().StdinFileDirectories)
 		if err != nil {
 			return nil, err
 		}
 		nio.sin = c
 	}
+	if isLogURI(stdout) || isLogURI(stderr) {
+		if stderr != "" && stderr != stdout {
+			return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "stderr '%s' must be the same log uri as stdout '%s'", stderr, stdout)
+		}
+		nio.sout, nio.serr, err = openLogURI(ctx, tid, eid, stdout, stderr != "")
+		if err != nil {
+			return nil, err
+		}
+		return nio, nil
+	}
 	if stdout != "" {
-		c, err := winio.DialPipe(stdout, nil)
+		c, err := dialUpstream(stdout)
 		if err != nil {
 			return nil, err
 		}
-		nio.sout = c
+		nio.sout = newReconnectingWriter(tid, eid, stdout, c, dialUpstreamWriter, terminal)
 	}
 	if stderr != "" {
-		c, err := winio.DialPipe(stderr, nil)
+		c, err := dialUpstream(stderr)
 		if err != nil {
 			return nil, err
 		}
-		nio.serr = c
+		nio.serr = newReconnectingWriter(tid, eid, stderr, c, dialUpstreamWriter, false)
 	}
 	return nio, nil
 }
@@ -80,7 +100,9 @@ type npipeio struct {
 	sin       io.ReadCloser
 	sinCloser sync.Once
 
-	// sout and serr are the upstream `stdout` and `stderr` connections.
+	// sout and serr are the upstream `stdout` and `stderr` connections. If
+	// the upstream disconnects they are redialed and output is buffered in
+	// the meantime.
 	//
 	// `sout` and `serr` MUST be treated as readonly in the lifetime of the pipe
 	// io after the return from `newNpipeIO`.
@@ -0,0 +1,419 @@
+package main
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// reconnectBufferSize is the default maximum number of bytes of output
+	// buffered while an upstream writer is disconnected. Once full the oldest
+	// output is dropped. It can be overridden in the shim config file.
+	reconnectBufferSize = 1024 * 1024
+	// ttyScrollbackSize is the default number of bytes of the most recent
+	// output of a terminal replayed to a reconnected upstream writer. It can
+	// be overridden in the shim config file.
+	ttyScrollbackSize = 256 * 1024
+	// reconnectMinBackoff and reconnectMaxBackoff bound the time between
+	// attempts to redial a disconnected upstream writer.
+	reconnectMinBackoff = time.Millisecond * 100
+	reconnectMaxBackoff = time.Second * 5
+)
+
+// dialWriterFunc dials the upstream connection at `path`.
+type dialWriterFunc func(path string) (io.WriteCloser, error)
+
+// newReconnectingWriter wraps the already connected upstream writer `w` dialed
+// at `path`. If a write to `w` fails the connection is redialed in the
+// background using `dial` and any output written in the meantime is buffered,
+// up to `shimConfig.ioReconnectBufferSize` bytes, and flushed on reconnect.
+//
+// If `scrollback` the most recent output, up to
+// `shimConfig.ttyScrollbackSize` bytes, is kept even while connected and is
+// replayed on reconnect so that a client that attaches late to a terminal sees
+// its recent output rather than a blank console. The output written while
+// disconnected is then kept in the scrollback rather than buffered again, and
+// is replayed once.
+//
+// A client that goes away is noticed when the upstream connection is closed,
+// not only when a write fails, so a client that attaches to an idle terminal
+// is sent the scrollback straight away.
+func newReconnectingWriter(tid, eid, path string, w io.WriteCloser, dial dialWriterFunc, scrollback bool) *reconnectingWriter {
+	rw := &reconnectingWriter{
+		path: path,
+		dial: dial,
+		log: logrus.WithFields(logrus.Fields{
+			"tid":  tid,
+			"eid":  eid,
+			"path": path,
+		}),
+		w:    w,
+		done: make(chan struct{}),
+	}
+	if scrollback {
+		rw.scrollback = &ringBuffer{}
+	}
+	if w != nil {
+		rw.watch(w)
+	}
+	return rw
+}
+
+var _ = (io.WriteCloser)(&reconnectingWriter{})
+
+type reconnectingWriter struct {
+	// path and dial are used to reconnect the upstream writer.
+	//
+	// They MUST be treated as readonly in the lifetime of the writer.
+	path string
+	dial dialWriterFunc
+	log  *logrus.Entry
+
+	// m MUST be held to safely read/write any of the following members.
+	m sync.Mutex
+	// w is the upstream connection. It is `nil` while disconnected.
+	w io.WriteCloser
+	// buf is the output written while disconnected. It is not used if
+	// scrollback is enabled.
+	buf []byte
+	// dropped is the number of bytes dropped from `buf`, or from
+	// `scrollback` before they were written upstream, since the last
+	// reconnect because it was full.
+	dropped int
+	// scrollback is the most recent output whether or not it was written
+	// upstream. It is `nil` if scrollback is disabled.
+	scrollback *ringBuffer
+	// undelivered is the number of bytes at the end of `scrollback` that
+	// have not been written upstream.
+	undelivered int
+	// flushing is the newly dialed upstream connection while the output
+	// buffered during the disconnect is written to it.
+	flushing io.WriteCloser
+	// reconnecting is `true` while the reconnect goroutine is running.
+	reconnecting bool
+	closed       bool
+	done         chan struct{}
+}
+
+// Write writes `p` upstream. If the upstream connection is broken `p` is
+// buffered and the write succeeds so that the relay from the process keeps
+// draining its output.
+func (rw *reconnectingWriter) Write(p []byte) (int, error) {
+	rw.m.Lock()
+	if rw.closed {
+		rw.m.Unlock()
+		return 0, io.ErrClosedPipe
+	}
+	w := rw.w
+	if rw.scrollback != nil {
+		rw.scrollback.Write(p, rw.scrollbackSizeL())
+	}
+	if w == nil {
+		rw.pendL(p)
+		rw.m.Unlock()
+		return len(p), nil
+	}
+	rw.m.Unlock()
+
+	// Write without holding the lock so that `Close` can unblock a write to
+	// a stalled connection.
+	n, err := w.Write(p)
+	if err == nil {
+		return n, nil
+	}
+
+	rw.m.Lock()
+	defer rw.m.Unlock()
+	if rw.closed {
+		return n, err
+	}
+	rw.disconnectL(w, err)
+	rw.pendL(p[n:])
+	return len(p), nil
+}
+
+// disconnectL closes the upstream connection `w`, that failed with `err`, and
+// starts reconnecting if `w` is still the upstream connection. It is the
+// callers responsibility to hold `rw.m`.
+func (rw *reconnectingWriter) disconnectL(w io.WriteCloser, err error) {
+	if rw.closed || rw.w != w {
+		return
+	}
+	rw.log.WithError(err).Warning("reconnectingWriter - upstream disconnected, buffering output")
+	w.Close()
+	rw.w = nil
+	if !rw.reconnecting {
+		rw.reconnecting = true
+		go rw.reconnect()
+	}
+}
+
+// watch disconnects the upstream connection `w` once it is closed by the
+// client, if `w` can be read from. Nothing is expected to be read.
+func (rw *reconnectingWriter) watch(w io.WriteCloser) {
+	r, ok := w.(io.Reader)
+	if !ok {
+		return
+	}
+	go func() {
+		b := make([]byte, 512)
+		for {
+			if _, err := r.Read(b); err != nil {
+				rw.m.Lock()
+				rw.disconnectL(w, err)
+				rw.m.Unlock()
+				return
+			}
+		}
+	}()
+}
+
+// scrollbackSizeL returns the size of the scrollback. While disconnected it is
+// grown to also hold the output buffered for the reconnect. It is the callers
+// responsibility to hold `rw.m`.
+func (rw *reconnectingWriter) scrollbackSizeL() int {
+	size := getConfig().ttyScrollbackSize()
+	if rw.w == nil {
+		if bsize := getConfig().ioReconnectBufferSize(); bsize > size {
+			size = bsize
+		}
+	}
+	return size
+}
+
+// pendL records that `p` was not written upstream. If scrollback is enabled
+// `p` MUST already be at the end of the scrollback. It is the callers
+// responsibility to hold `rw.m`.
+func (rw *reconnectingWriter) pendL(p []byte) {
+	if rw.scrollback == nil {
+		rw.bufferL(p)
+		return
+	}
+	rw.undelivered += len(p)
+}
+
+// takeL returns the output to write upstream and clears it. `pending` is the
+// number of bytes at the end of `p` that have not been written upstream. If
+// `replay` and scrollback is enabled `p` also holds the most recent output up
+// to the scrollback size. It is the callers responsibility to hold `rw.m`.
+func (rw *reconnectingWriter) takeL(replay bool) (p []byte, pending int) {
+	if rw.scrollback == nil {
+		p, rw.buf = rw.buf, nil
+		return p, len(p)
+	}
+	if held := rw.scrollback.Len(); rw.undelivered > held {
+		rw.dropped += rw.undelivered - held
+		rw.undelivered = held
+	}
+	pending, rw.undelivered = rw.undelivered, 0
+	n := pending
+	if replay {
+		if size := getConfig().ttyScrollbackSize(); size > n {
+			n = size
+		}
+	}
+	return rw.scrollback.Tail(n), pending
+}
+
+// hasPendingL returns `true` if there is output not yet written upstream. It
+// is the callers responsibility to hold `rw.m`.
+func (rw *reconnectingWriter) hasPendingL() bool {
+	return len(rw.buf) > 0 || rw.undelivered > 0
+}
+
+// bufferL appends `p` to the pending output dropping the oldest output if it
+// exceeds the configured buffer size. It is the callers responsibility to
+// hold `rw.m`.
+func (rw *reconnectingWriter) bufferL(p []byte) {
+	rw.buf = append(rw.buf, p...)
+	if over := len(rw.buf) - getConfig().ioReconnectBufferSize(); over > 0 {
+		rw.dropped += over
+		rw.buf = append(rw.buf[:0], rw.buf[over:]...)
+	}
+}
+
+// reconnect redials the upstream connection with backoff until it succeeds or
+// the writer is closed.
+//
+// This MUST be called via a goroutine.
+func (rw *reconnectingWriter) reconnect() {
+	backoff := reconnectMinBackoff
+	for {
+		t := time.NewTimer(backoff)
+		select {
+		case <-rw.done:
+			t.Stop()
+			return
+		case <-t.C:
+		}
+		if backoff *= 2; backoff > reconnectMaxBackoff {
+			backoff = reconnectMaxBackoff
+		}
+
+		w, err := rw.dial(rw.path)
+		if err != nil {
+			rw.log.WithError(err).Debug("reconnectingWriter::reconnect - dial failed")
+			continue
+		}
+
+		rw.m.Lock()
+		if rw.closed {
+			rw.m.Unlock()
+			w.Close()
+			return
+		}
+		replay, pending := rw.takeL(true)
+		rw.flushing = w
+		rw.m.Unlock()
+
+		if err := rw.flush(w, replay, pending); err != nil {
+			w.Close()
+			if err == io.ErrClosedPipe {
+				return
+			}
+			rw.log.WithError(err).Debug("reconnectingWriter::reconnect - flush failed")
+			continue
+		}
+		return
+	}
+}
+
+// flush writes `replay` to the newly dialed upstream writer `w` followed by
+// any output buffered while it was written, and then makes `w` the upstream
+// writer. The writes are made without holding `rw.m` so that a client that
+// stops reading cannot block `Write` or `Close`. If a write fails the output
+// not yet written, the last `pending` bytes of the failed write and anything
+// written since, is kept to be written on the next reconnect.
+func (rw *reconnectingWriter) flush(w io.WriteCloser, replay []byte, pending int) error {
+	p := replay
+	for {
+		if len(p) > 0 {
+			if _, err := w.Write(p); err != nil {
+				rw.m.Lock()
+				defer rw.m.Unlock()
+				rw.flushing = nil
+				if rw.closed {
+					return io.ErrClosedPipe
+				}
+				if rw.scrollback == nil {
+					buf := rw.buf
+					rw.buf = nil
+					rw.bufferL(p[len(p)-pending:])
+					rw.bufferL(buf)
+				} else {
+					rw.undelivered += pending
+				}
+				return err
+			}
+		}
+
+		rw.m.Lock()
+		if rw.closed {
+			rw.m.Unlock()
+			return io.ErrClosedPipe
+		}
+		if !rw.hasPendingL() {
+			rw.log.WithField("dropped", rw.dropped).Info("reconnectingWriter::reconnect - upstream reconnected")
+			rw.w = w
+			rw.flushing = nil
+			rw.dropped = 0
+			rw.reconnecting = false
+			rw.watch(w)
+			rw.m.Unlock()
+			return nil
+		}
+		p, pending = rw.takeL(false)
+		rw.m.Unlock()
+	}
+}
+
+// Close closes the upstream connection and stops any reconnect. Any buffered
+// output is discarded.
+//
+// This call is idempotent and safe to call multiple times.
+func (rw *reconnectingWriter) Close() error {
+	rw.m.Lock()
+	defer rw.m.Unlock()
+	if rw.closed {
+		return nil
+	}
+	rw.closed = true
+	close(rw.done)
+	rw.buf = nil
+	rw.undelivered = 0
+	if rw.flushing != nil {
+		// Unblock a flush to a client that stopped reading.
+		rw.flushing.Close()
+	}
+	if rw.w != nil {
+		return rw.w.Close()
+	}
+	return nil
+}
+
+// ringBuffer holds the most recent bytes written to it.
+type ringBuffer struct {
+	buf []byte
+	// start is the index in `buf` of the oldest byte and n the number of
+	// bytes held.
+	start, n int
+}
+
+// Write appends `p` keeping only the most recent `size` bytes. If `size`
+// differs from the previous write the most recent bytes are kept. If `size <=
+// 0` nothing is kept.
+func (rb *ringBuffer) Write(p []byte, size int) {
+	if size <= 0 {
+		rb.buf, rb.start, rb.n = nil, 0, 0
+		return
+	}
+	if size != len(rb.buf) {
+		old := rb.Bytes()
+		if len(old) > size {
+			old = old[len(old)-size:]
+		}
+		rb.buf = make([]byte, size)
+		rb.start, rb.n = 0, copy(rb.buf, old)
+	}
+	if len(p) >= size {
+		copy(rb.buf, p[len(p)-size:])
+		rb.start, rb.n = 0, size
+		return
+	}
+	end := (rb.start + rb.n) % size
+	copied := copy(rb.buf[end:], p)
+	copy(rb.buf, p[copied:])
+	rb.n += len(p)
+	if rb.n > size {
+		rb.start = (rb.start + rb.n - size) % size
+		rb.n = size
+	}
+}
+
+// Len returns the number of bytes held.
+func (rb *ringBuffer) Len() int {
+	return rb.n
+}
+
+// Tail returns a copy of the most recent `n` bytes held, oldest first. If `n`
+// is more than the bytes held all of them are returned.
+func (rb *ringBuffer) Tail(n int) []byte {
+	b := rb.Bytes()
+	if n < len(b) {
+		b = b[len(b)-n:]
+	}
+	return b
+}
+
+// Bytes returns a copy of the bytes held, oldest first.
+func (rb *ringBuffer) Bytes() []byte {
+	b := make([]byte, 0, rb.n)
+	if end := rb.start + rb.n; end <= len(rb.buf) {
+		return append(b, rb.buf[rb.start:end]...)
+	}
+	b = append(b, rb.buf[rb.start:]...)
+	return append(b, rb.buf[:rb.start+rb.n-len(rb.buf)]...)
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testPipeWriter struct {
+	m      sync.Mutex
+	buf    bytes.Buffer
+	broken bool
+	closed bool
+}
+
+func (tpw *testPipeWriter) Write(p []byte) (int, error) {
+	tpw.m.Lock()
+	defer tpw.m.Unlock()
+	if tpw.broken || tpw.closed {
+		return 0, errors.New("pipe broken")
+	}
+	return tpw.buf.Write(p)
+}
+
+func (tpw *testPipeWriter) Close() error {
+	tpw.m.Lock()
+	defer tpw.m.Unlock()
+	tpw.closed = true
+	return nil
+}
+
+func (tpw *testPipeWriter) String() string {
+	tpw.m.Lock()
+	defer tpw.m.Unlock()
+	return tpw.buf.String()
+}
+
+func Test_ReconnectingWriter_BuffersAndFlushes(t *testing.T) {
+	first := &testPipeWriter{}
+	second := &testPipeWriter{}
+	dialed := make(chan struct{})
+	dial := func(path string) (io.WriteCloser, error) {
+		defer close(dialed)
+		return second, nil
+	}
+	rw := newReconnectingWriter(t.Name(), t.Name(), "pipe", first, dial, false)
+	defer rw.Close()
+
+	if _, err := rw.Write([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	first.m.Lock()
+	first.broken = true
+	first.m.Unlock()
+	if n, err := rw.Write([]byte("b")); err != nil || n != 1 {
+		t.Fatalf("expected buffered write to succeed, got %d, %v", n, err)
+	}
+	select {
+	case <-dialed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for reconnect")
+	}
+	// Once reconnected new writes go to the new connection after the flush.
+	deadline := time.Now().Add(5 * time.Second)
+	for second.String() != "b" {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected flushed output 'b', got '%s'", second.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := rw.Write([]byte("c")); err != nil {
+		t.Fatal(err)
+	}
+	if first.String() != "a" || second.String() != "bc" {
+		t.Fatalf("unexpected output '%s', '%s'", first.String(), second.String())
+	}
+}
+
+// stalledPipeWriter blocks every write until it is closed, like a client that
+// has stopped reading.
+type stalledPipeWriter struct {
+	once    sync.Once
+	writing chan struct{}
+	closed  chan struct{}
+}
+
+func (spw *stalledPipeWriter) Write(p []byte) (int, error) {
+	spw.once.Do(func() { close(spw.writing) })
+	<-spw.closed
+	return 0, errors.New("pipe closed")
+}
+
+func (spw *stalledPipeWriter) Close() error {
+	select {
+	case <-spw.closed:
+	default:
+		close(spw.closed)
+	}
+	return nil
+}
+
+func Test_ReconnectingWriter_StalledFlush_DoesNotBlock(t *testing.T) {
+	first := &testPipeWriter{broken: true}
+	second := &stalledPipeWriter{
+		writing: make(chan struct{}),
+		closed:  make(chan struct{}),
+	}
+	dial := func(path string) (io.WriteCloser, error) {
+		return second, nil
+	}
+	rw := newReconnectingWriter(t.Name(), t.Name(), "pipe", first, dial, false)
+
+	if _, err := rw.Write([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-second.writing:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the flush")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := rw.Write([]byte("b")); err != nil {
+			t.Error(err)
+		}
+		rw.Close()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Write and Close blocked on a stalled flush")
+	}
+}
+
+func Test_ReconnectingWriter_BufferBounded(t *testing.T) {
+	rw := newReconnectingWriter(t.Name(), t.Name(), "pipe", nil, nil, false)
+	rw.bufferL(make([]byte, reconnectBufferSize))
+	rw.bufferL([]byte("x"))
+	if len(rw.buf) != reconnectBufferSize || rw.dropped != 1 {
+		t.Fatalf("expected bounded buffer, got len %d dropped %d", len(rw.buf), rw.dropped)
+	}
+	if rw.buf[len(rw.buf)-1] != 'x' {
+		t.Fatal("expected newest output to be retained")
+	}
+}
+
+func Test_ReconnectingWriter_Close(t *testing.T) {
+	w := &testPipeWriter{}
+	rw := newReconnectingWriter(t.Name(), t.Name(), "pipe", w, nil, false)
+	if err := rw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !w.closed {
+		t.Fatal("expected upstream to be closed")
+	}
+	if _, err := rw.Write([]byte("a")); err != io.ErrClosedPipe {
+		t.Fatalf("expected io.ErrClosedPipe, got %v", err)
+	}
+}
+
+func Test_ReconnectingWriter_Scrollback_Replayed(t *testing.T) {
+	first := &testPipeWriter{}
+	second := &testPipeWriter{}
+	dialed := make(chan struct{})
+	dial := func(path string) (io.WriteCloser, error) {
+		defer close(dialed)
+		return second, nil
+	}
+	rw := newReconnectingWriter(t.Name(), t.Name(), "pipe", first, dial, true)
+	defer rw.Close()
+
+	if _, err := rw.Write([]byte("prompt> ")); err != nil {
+		t.Fatal(err)
+	}
+	first.m.Lock()
+	first.broken = true
+	first.m.Unlock()
+	if _, err := rw.Write([]byte("ls")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-dialed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for reconnect")
+	}
+	// The reconnected client sees the output sent before the disconnect too.
+	deadline := time.Now().Add(5 * time.Second)
+	for second.String() != "prompt> ls" {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected replayed output 'prompt> ls', got '%s'", second.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func Test_ReconnectingWriter_Scrollback_NotDuplicated(t *testing.T) {
+	first := &testPipeWriter{}
+	second := &testPipeWriter{}
+	dial := func(path string) (io.WriteCloser, error) {
+		return second, nil
+	}
+	rw := newReconnectingWriter(t.Name(), t.Name(), "pipe", first, dial, true)
+	defer rw.Close()
+
+	if _, err := rw.Write([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	first.m.Lock()
+	first.broken = true
+	first.m.Unlock()
+	for _, p := range []string{"b", "c"} {
+		if _, err := rw.Write([]byte(p)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for second.String() != "abc" {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected replayed output 'abc', got '%s'", second.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	rw.m.Lock()
+	defer rw.m.Unlock()
+	if len(rw.buf) != 0 || rw.undelivered != 0 {
+		t.Fatalf("expected no pending output, got %d buffered %d undelivered", len(rw.buf), rw.undelivered)
+	}
+}
+
+// testReadablePipeWriter is a testPipeWriter whose reads block until the
+// client closes it, like an upstream named pipe.
+type testReadablePipeWriter struct {
+	testPipeWriter
+	clientClosed chan struct{}
+}
+
+func (trpw *testReadablePipeWriter) Read(p []byte) (int, error) {
+	<-trpw.clientClosed
+	return 0, io.EOF
+}
+
+func Test_ReconnectingWriter_Scrollback_ServedOnAttach(t *testing.T) {
+	first := &testReadablePipeWriter{clientClosed: make(chan struct{})}
+	second := &testPipeWriter{}
+	dial := func(path string) (io.WriteCloser, error) {
+		return second, nil
+	}
+	rw := newReconnectingWriter(t.Name(), t.Name(), "pipe", first, dial, true)
+	defer rw.Close()
+
+	if _, err := rw.Write([]byte("prompt> ")); err != nil {
+		t.Fatal(err)
+	}
+	// The client goes away while the terminal is idle and a new one
+	// attaches. It is sent the scrollback without any further output.
+	close(first.clientClosed)
+	deadline := time.Now().Add(5 * time.Second)
+	for second.String() != "prompt> " {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected replayed output 'prompt> ', got '%s'", second.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	first.m.Lock()
+	defer first.m.Unlock()
+	if !first.closed {
+		t.Fatal("expected the closed upstream to be closed")
+	}
+}
+
+func Test_ringBuffer(t *testing.T) {
+	rb := &ringBuffer{}
+	rb.Write([]byte("abc"), 4)
+	rb.Write([]byte("de"), 4)
+	if got := string(rb.Bytes()); got != "bcde" {
+		t.Fatalf("expected 'bcde', got '%s'", got)
+	}
+	rb.Write([]byte("fghij"), 4)
+	if got := string(rb.Bytes()); got != "ghij" {
+		t.Fatalf("expected 'ghij', got '%s'", got)
+	}
+	// Shrinking keeps the most recent bytes.
+	rb.Write([]byte("k"), 2)
+	if got := string(rb.Bytes()); got != "jk" {
+		t.Fatalf("expected 'jk', got '%s'", got)
+	}
+	if got := string(rb.Tail(1)); got != "k" {
+		t.Fatalf("expected 'k', got '%s'", got)
+	}
+	if got := string(rb.Tail(3)); got != "jk" {
+		t.Fatalf("expected 'jk', got '%s'", got)
+	}
+	rb.Write([]byte("l"), 0)
+	if rb.Len() != 0 {
+		t.Fatalf("expected empty buffer, got %d bytes", rb.Len())
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/pkg/errors"
+)
+
+// openStdin opens the upstream `stdin` at `path`. This is one of:
+//
+// `file:///<host path>` which reads the file on the host. The file MUST be in
+// one of `allowedDirs`.
+//
+// `data:[<mediatype>][;base64],<data>` which reads the inline (RFC 2397) data.
+//
+// Otherwise `path` is dialed, see `dialUpstream`.
+func openStdin(path string, allowedDirs []string) (io.ReadCloser, error) {
+	switch {
+	case strings.HasPrefix(path, "file://"):
+		p, err := stdinFilePath(path)
+		if err != nil {
+			return nil, err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkStdinFileAllowed(f.Name(), allowedDirs); err != nil {
+			f.Close()
+			return nil, err
+		}
+		return f, nil
+	case strings.HasPrefix(path, "data:"):
+		b, err := decodeDataURI(path)
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.NopCloser(bytes.NewReader(b)), nil
+	default:
+		return dialUpstream(path)
+	}
+}
+
+// checkStdinFileAllowed returns `errdefs.ErrInvalidArgument` unless the file
+// at `p` is in one of `allowedDirs` once any links in either are resolved.
+func checkStdinFileAllowed(p string, allowedDirs []string) error {
+	resolved, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		return err
+	}
+	resolved, err = filepath.Abs(resolved)
+	if err != nil {
+		return err
+	}
+	for _, dir := range allowedDirs {
+		d, err := filepath.EvalSymlinks(dir)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(d, resolved)
+		if err != nil {
+			continue
+		}
+		if rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel) {
+			return nil
+		}
+	}
+	return errors.Wrapf(errdefs.ErrInvalidArgument, "stdin file '%s' is not in an allowed directory", p)
+}
+
+// stdinFilePath returns the host path of the `file://` URI `uri`.
+func stdinFilePath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", errors.Wrapf(errdefs.ErrInvalidArgument, "invalid stdin uri '%s': %s", uri, err)
+	}
+	if u.Host != "" {
+		return "", errors.Wrapf(errdefs.ErrInvalidArgument, "stdin uri '%s' must not have a host", uri)
+	}
+	p := uriHostPath(u)
+	if p == "" {
+		return "", errors.Wrapf(errdefs.ErrInvalidArgument, "stdin uri '%s' has no path", uri)
+	}
+	return p, nil
+}
+
+// uriHostPath returns the host path of the `file://` or `binary://` URI `u`.
+func uriHostPath(u *url.URL) string {
+	p := u.Path
+	// `file:///C:/path` parses to `/C:/path`.
+	if len(p) >= 3 && p[0] == '/' && p[2] == ':' {
+		p = p[1:]
+	}
+	return p
+}
+
+// decodeDataURI returns the data of the RFC 2397 `data:` URI `uri`.
+func decodeDataURI(uri string) ([]byte, error) {
+	i := strings.IndexByte(uri, ',')
+	if i < 0 {
+		return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "stdin data uri is missing ','")
+	}
+	header, data := uri[len("data:"):i], uri[i+1:]
+	if strings.HasSuffix(header, ";base64") {
+		b, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "invalid base64 stdin data uri: %s", err)
+		}
+		return b, nil
+	}
+	s, err := url.PathUnescape(data)
+	if err != nil {
+		return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "invalid stdin data uri: %s", err)
+	}
+	return []byte(s), nil
+}
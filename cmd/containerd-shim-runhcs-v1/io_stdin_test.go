@@ -0,0 +1,102 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/pkg/errors"
+)
+
+func Test_decodeDataURI(t *testing.T) {
+	tests := map[string]string{
+		"data:,hello%20world":                     "hello world",
+		"data:text/plain,hello":                   "hello",
+		"data:text/plain;base64,aGVsbG8gd29ybGQ=": "hello world",
+		"data:;base64,":                           "",
+		"data:application/json,%7B%22a%22%3A1%7D": `{"a":1}`,
+	}
+	for uri, expected := range tests {
+		b, err := decodeDataURI(uri)
+		if err != nil {
+			t.Fatalf("expected nil error for '%s', got: %v", uri, err)
+		}
+		if string(b) != expected {
+			t.Fatalf("expected '%s' for '%s', got: '%s'", expected, uri, string(b))
+		}
+	}
+}
+
+func Test_decodeDataURI_Invalid(t *testing.T) {
+	for _, uri := range []string{"data:hello", "data:;base64,!!!"} {
+		if _, err := decodeDataURI(uri); errors.Cause(err) != errdefs.ErrInvalidArgument {
+			t.Fatalf("expected ErrInvalidArgument for '%s', got: %v", uri, err)
+		}
+	}
+}
+
+func Test_stdinFilePath(t *testing.T) {
+	tests := map[string]string{
+		"file:///C:/scripts/run.sh": "C:/scripts/run.sh",
+		"file:///tmp/manifest":      "/tmp/manifest",
+	}
+	for uri, expected := range tests {
+		p, err := stdinFilePath(uri)
+		if err != nil {
+			t.Fatalf("expected nil error for '%s', got: %v", uri, err)
+		}
+		if p != expected {
+			t.Fatalf("expected '%s' for '%s', got: '%s'", expected, uri, p)
+		}
+	}
+	if _, err := stdinFilePath("file://host/share/file"); errors.Cause(err) != errdefs.ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument, got: %v", err)
+	}
+}
+
+func Test_openStdin_File(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	p := filepath.Join(dir, "stdin")
+	if err := ioutil.WriteFile(p, []byte("input"), 0600); err != nil {
+		t.Fatalf("failed to write stdin file: %v", err)
+	}
+
+	r, err := openStdin("file:///"+filepath.ToSlash(p), []string{dir})
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	defer r.Close()
+	b, err := ioutil.ReadAll(r)
+	if err != nil || string(b) != "input" {
+		t.Fatalf("expected 'input', got: '%s', %v", string(b), err)
+	}
+}
+
+func Test_openStdin_File_NotAllowed(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	allowed := filepath.Join(dir, "allowed")
+	if err := os.Mkdir(allowed, 0700); err != nil {
+		t.Fatalf("failed to create allowed dir: %v", err)
+	}
+	p := filepath.Join(dir, "stdin")
+	if err := ioutil.WriteFile(p, []byte("input"), 0600); err != nil {
+		t.Fatalf("failed to write stdin file: %v", err)
+	}
+
+	uri := "file:///" + filepath.ToSlash(p)
+	for _, dirs := range [][]string{nil, {allowed}} {
+		if _, err := openStdin(uri, dirs); errors.Cause(err) != errdefs.ErrInvalidArgument {
+			t.Fatalf("expected ErrInvalidArgument for %v, got: %v", dirs, err)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	winio "github.com/Microsoft/go-winio"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/pkg/errors"
+)
+
+// upstreamDialTimeout is the time allowed to connect an upstream stdio stream
+// over a network transport.
+const upstreamDialTimeout = time.Second * 10
+
+// upstreamDialer connects the upstream stdio stream at `address`.
+type upstreamDialer func(address string) (io.ReadWriteCloser, error)
+
+// upstreamTransports are the transports an upstream stdio stream may be
+// relayed over keyed by the scheme of its address. An address without a
+// registered scheme is a named pipe.
+//
+// `tcp://<host>:<port>` connects to the TCP listener at `<host>:<port>`. The
+// stream is not authenticated so `<host>` must be a loopback address or
+// `localhost`.
+var upstreamTransports = map[string]upstreamDialer{
+	"tcp": dialTCP,
+}
+
+// dialUpstream connects the upstream stdio stream at `address` using the
+// transport registered for its scheme. Otherwise `address` is the named pipe
+// to dial.
+func dialUpstream(address string) (io.ReadWriteCloser, error) {
+	if i := strings.Index(address, "://"); i > 0 {
+		if dial, ok := upstreamTransports[address[:i]]; ok {
+			return dial(address)
+		}
+	}
+	return winio.DialPipe(address, nil)
+}
+
+// dialUpstreamWriter redials an upstream `stdout` or `stderr`.
+func dialUpstreamWriter(address string) (io.WriteCloser, error) {
+	return dialUpstream(address)
+}
+
+func dialTCP(address string) (io.ReadWriteCloser, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "invalid tcp address '%s': %s", address, err)
+	}
+	if u.Port() == "" {
+		return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "tcp address '%s' has no port", address)
+	}
+	if !isLoopbackHost(u.Hostname()) {
+		return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "tcp address '%s' is not a loopback address", address)
+	}
+	return net.DialTimeout("tcp", u.Host, upstreamDialTimeout)
+}
+
+// isLoopbackHost returns `true` if `host` is `localhost` or a loopback IP.
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
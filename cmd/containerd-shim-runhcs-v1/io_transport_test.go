@@ -0,0 +1,83 @@
+package main
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/pkg/errors"
+)
+
+func Test_dialUpstream_TCP(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	received := make(chan string)
+	go func() {
+		c, err := l.Accept()
+		if err != nil {
+			close(received)
+			return
+		}
+		defer c.Close()
+		b, _ := ioutil.ReadAll(c)
+		received <- string(b)
+	}()
+
+	c, err := dialUpstream("tcp://" + l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(c, "hello")
+	c.Close()
+	if s := <-received; s != "hello" {
+		t.Fatalf("expected 'hello', got: '%s'", s)
+	}
+}
+
+func Test_dialUpstream_TCP_NoPort_Error(t *testing.T) {
+	if _, err := dialUpstream("tcp://127.0.0.1"); errors.Cause(err) != errdefs.ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument, got: %v", err)
+	}
+}
+
+func Test_dialUpstream_TCP_NotLoopback_Error(t *testing.T) {
+	for _, address := range []string{"tcp://10.0.0.1:8080", "tcp://0.0.0.0:8080", "tcp://example.com:8080"} {
+		if _, err := dialUpstream(address); errors.Cause(err) != errdefs.ErrInvalidArgument {
+			t.Fatalf("expected ErrInvalidArgument for '%s', got: %v", address, err)
+		}
+	}
+}
+
+func Test_isLoopbackHost(t *testing.T) {
+	for host, expected := range map[string]bool{
+		"localhost": true,
+		"127.0.0.1": true,
+		"::1":       true,
+		"10.0.0.1":  false,
+		"":          false,
+	} {
+		if actual := isLoopbackHost(host); actual != expected {
+			t.Fatalf("isLoopbackHost('%s') expected: %v, got: %v", host, expected, actual)
+		}
+	}
+}
+
+func Test_dialUpstream_RegisteredTransport(t *testing.T) {
+	var dialed string
+	upstreamTransports[t.Name()] = func(address string) (io.ReadWriteCloser, error) {
+		dialed = address
+		return nil, errors.New("dial")
+	}
+	defer delete(upstreamTransports, t.Name())
+
+	address := t.Name() + "://upstream"
+	dialUpstream(address)
+	if dialed != address {
+		t.Fatalf("expected transport to dial '%s', got: '%s'", address, dialed)
+	}
+}
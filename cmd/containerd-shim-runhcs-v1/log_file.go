@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = (logrus.Hook)(&fileLogHook{})
+
+// fileLogHook is a logrus hook that writes every log entry to a file in
+// addition to the log pipe.
+type fileLogHook struct {
+	formatter logrus.Formatter
+
+	// m MUST be held to safely write to `f`.
+	m sync.Mutex
+	f *os.File
+}
+
+// Levels returns all levels so that every entry is written.
+func (h *fileLogHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire formats and writes `entry` to the file.
+func (h *fileLogHook) Fire(entry *logrus.Entry) error {
+	b, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	h.m.Lock()
+	defer h.m.Unlock()
+	_, err = h.f.Write(b)
+	return err
+}
+
+var (
+	// logFileM MUST be held to safely read/write `logFilePath`.
+	logFileM sync.Mutex
+	// logFilePath is the file the shim log is written to. It is "" if the log
+	// is not written to a file.
+	logFilePath string
+)
+
+// setLogFile writes the shim log to the file at `path` from now on. A pod shim
+// receives the options with every Create so later calls with the same path
+// have no effect.
+func setLogFile(path string) error {
+	logFileM.Lock()
+	defer logFileM.Unlock()
+	if logFilePath != "" {
+		if logFilePath != path {
+			logrus.WithFields(logrus.Fields{
+				"path":    path,
+				"current": logFilePath,
+			}).Warning("ignoring log file path, the shim log is already written to a file")
+		}
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open log file '%s'", path)
+	}
+	logrus.AddHook(&fileLogHook{
+		formatter: &logrus.TextFormatter{
+			DisableColors:   true,
+			FullTimestamp:   true,
+			TimestampFormat: time.RFC3339Nano,
+		},
+		f: f,
+	})
+	logFilePath = path
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func Test_FileLogHook_Fire(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	f, err := os.Create(filepath.Join(dir, "shim.log"))
+	if err != nil {
+		t.Fatalf("failed to create log file: %v", err)
+	}
+	h := &fileLogHook{formatter: &logrus.TextFormatter{DisableColors: true}, f: f}
+	logger := logrus.New()
+	logger.Out = ioutil.Discard
+	logger.AddHook(h)
+	logger.WithField("tid", "t1").Info("hello")
+	f.Close()
+
+	b, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(b), "msg=hello tid=t1") {
+		t.Fatalf("expected entry in log file, got: %q", string(b))
+	}
+}
@@ -50,12 +50,18 @@ func stack() []byte {
 	}
 }
 
-func panicRecover() {
+// panicRecover recovers a panic in `activity`, logs it and writes a crash
+// report to the bundle.
+func panicRecover(activity string) {
 	if r := recover(); r != nil {
+		st := stack()
+		logs := recentLogs.Lines()
 		logrus.WithFields(logrus.Fields{
-			"panic": r,
-			"stack": string(stack()),
+			"activity": activity,
+			"panic":    r,
+			"stack":    string(st),
 		}).Error("containerd-shim-runhcs-v1: panic")
+		writeCrashReport(activity, r, st, logs)
 	}
 }
 
@@ -79,7 +85,8 @@ func main() {
 		}
 	}
 
-	defer panicRecover()
+	logrus.AddHook(recentLogs)
+	defer panicRecover("main")
 
 	provider.WriteEvent(
 		"ShimLaunched",
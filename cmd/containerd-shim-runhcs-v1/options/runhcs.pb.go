@@ -93,15 +93,43 @@ type Options struct {
 	SandboxImage string `protobuf:"bytes,4,opt,name=sandbox_image,json=sandboxImage,proto3" json:"sandbox_image,omitempty"`
 	// sandbox_platform is a CRI setting that specifies the platform
 	// architecture for all sandbox's in this runtime. Values are
-	// 'windows/amd64' and 'linux/amd64'.
+	// 'windows/amd64', 'linux/amd64' and 'linux/arm64'.
 	SandboxPlatform string `protobuf:"bytes,5,opt,name=sandbox_platform,json=sandboxPlatform,proto3" json:"sandbox_platform,omitempty"`
 	// sandbox_isolation is a CRI setting that specifies the isolation level of
 	// the sandbox. For Windows runtime PROCESS and HYPERVISOR are valid. For
-	// LCOW only HYPERVISOR is valid and default if omitted.
+	// LCOW only HYPERVISOR is valid and default if omitted. If HYPERVISOR the
+	// shim also isolates WCOW specs that do not set `Windows.HyperV`.
 	SandboxIsolation Options_SandboxIsolation `protobuf:"varint,6,opt,name=sandbox_isolation,json=sandboxIsolation,proto3,enum=containerd.runhcs.v1.Options_SandboxIsolation" json:"sandbox_isolation,omitempty"`
 	// boot_files_root_path is the path to the directory containing the LCOW
 	// kernel and root FS files.
-	BootFilesRootPath    string   `protobuf:"bytes,7,opt,name=boot_files_root_path,json=bootFilesRootPath,proto3" json:"boot_files_root_path,omitempty"`
+	BootFilesRootPath string `protobuf:"bytes,7,opt,name=boot_files_root_path,json=bootFilesRootPath,proto3" json:"boot_files_root_path,omitempty"`
+	// vm_backend is the name of the registered virtualization backend that
+	// compute systems are created on. If omitted defaults to the local
+	// vmcompute service.
+	VmBackend string `protobuf:"bytes,8,opt,name=vm_backend,json=vmBackend,proto3" json:"vm_backend,omitempty"`
+	// max_execs_per_task is the maximum number of concurrently running execs
+	// in each task, not including the init process. Further Exec calls are
+	// rejected until an exec exits. If omitted (or 0) there is no limit.
+	MaxExecsPerTask int32 `protobuf:"varint,9,opt,name=max_execs_per_task,json=maxExecsPerTask,proto3" json:"max_execs_per_task,omitempty"`
+	// config_path is the path to an optional JSON file of shim tunables such
+	// as timeouts, buffer sizes, log level and feature gates. It is reloaded
+	// when the Global\reloadconfig-<pid> event is signalled or via shimdiag.
+	ConfigPath string `protobuf:"bytes,10,opt,name=config_path,json=configPath,proto3" json:"config_path,omitempty"`
+	// pipe_security_descriptor is the SDDL security descriptor the named pipe
+	// the shim serves the task and shimdiag APIs on is created with. It is only
+	// honored in the options containerd passes to `shim start`. A create whose
+	// options set a different descriptor fails. If omitted the default named
+	// pipe DACL is used.
+	PipeSecurityDescriptor string `protobuf:"bytes,11,opt,name=pipe_security_descriptor,json=pipeSecurityDescriptor,proto3" json:"pipe_security_descriptor,omitempty"`
+	// log_file_path is the file the shim log is also written to when
+	// debug_type is FILE.
+	LogFilePath string `protobuf:"bytes,12,opt,name=log_file_path,json=logFilePath,proto3" json:"log_file_path,omitempty"`
+	// allowed_annotations restricts the io.microsoft.* annotations honored on
+	// the OCI spec. If not empty any such annotation not in the list is removed
+	// before the spec is used. An entry ending in '*' matches every annotation
+	// with that prefix. Annotations set by the shim from these options are
+	// always honored.
+	AllowedAnnotations   []string `protobuf:"bytes,13,rep,name=allowed_annotations,json=allowedAnnotations,proto3" json:"allowed_annotations,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -200,51 +228,60 @@ func init() {
 }
 
 var fileDescriptor_b643df6839c75082 = []byte{
-	// 704 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0x4d, 0x6f, 0xda, 0x48,
-	0x18, 0xc6, 0xe1, 0xd3, 0x6f, 0x96, 0xc4, 0x99, 0xe5, 0x80, 0xb2, 0xbb, 0x80, 0xc8, 0x21, 0x89,
-	0x76, 0x63, 0x43, 0xf6, 0xd8, 0x53, 0x09, 0xa0, 0xba, 0x6a, 0x83, 0x65, 0xa2, 0xa6, 0x1f, 0x07,
-	0xcb, 0xd8, 0x83, 0xb1, 0x82, 0x3d, 0xd6, 0xcc, 0x90, 0x86, 0x5b, 0x7f, 0x42, 0x7f, 0x55, 0x95,
-	0x63, 0x8f, 0x95, 0x2a, 0xa5, 0x0d, 0xbf, 0xa4, 0x9a, 0xb1, 0x49, 0xd4, 0x28, 0xea, 0xa5, 0x27,
-	0xc6, 0xcf, 0xf3, 0xbc, 0xcf, 0xfb, 0x29, 0x60, 0x14, 0x84, 0x7c, 0xb6, 0x98, 0xe8, 0x1e, 0x89,
-	0x8c, 0x97, 0xa1, 0x47, 0x09, 0x23, 0x53, 0x6e, 0xcc, 0x3c, 0xc6, 0x66, 0x61, 0x64, 0x78, 0x91,
-	0x6f, 0x78, 0x24, 0xe6, 0x6e, 0x18, 0x63, 0xea, 0x1f, 0x09, 0xec, 0x88, 0x2e, 0xe2, 0x99, 0xc7,
-	0x8e, 0x2e, 0xbb, 0x06, 0x49, 0x78, 0x48, 0x62, 0x66, 0xa4, 0x88, 0x9e, 0x50, 0xc2, 0x09, 0xaa,
-	0xdd, 0xeb, 0xf5, 0x8c, 0xb8, 0xec, 0xee, 0xd6, 0x02, 0x12, 0x10, 0x29, 0x30, 0xc4, 0x2b, 0xd5,
-	0xee, 0x36, 0x03, 0x42, 0x82, 0x39, 0x36, 0xe4, 0xd7, 0x64, 0x31, 0x35, 0x78, 0x18, 0x61, 0xc6,
-	0xdd, 0x28, 0x49, 0x05, 0xed, 0x4f, 0x79, 0x28, 0x8f, 0xd2, 0x2c, 0xa8, 0x06, 0x45, 0x1f, 0x4f,
-	0x16, 0x41, 0x5d, 0x69, 0x29, 0x07, 0x15, 0x3b, 0xfd, 0x40, 0x43, 0x00, 0xf9, 0x70, 0xf8, 0x32,
-	0xc1, 0xf5, 0x8d, 0x96, 0x72, 0xb0, 0x75, 0xbc, 0xaf, 0x3f, 0x56, 0x83, 0x9e, 0x19, 0xe9, 0x7d,
-	0xa1, 0x3f, 0x5b, 0x26, 0xd8, 0x56, 0xfd, 0xf5, 0x13, 0xed, 0x41, 0x95, 0xe2, 0x20, 0x64, 0x9c,
-	0x2e, 0x1d, 0x4a, 0x08, 0xaf, 0xe7, 0x5b, 0xca, 0x81, 0x6a, 0xff, 0xb1, 0x06, 0x6d, 0x42, 0xb8,
-	0x10, 0x31, 0x37, 0xf6, 0x27, 0xe4, 0xca, 0x09, 0x23, 0x37, 0xc0, 0xf5, 0x42, 0x2a, 0xca, 0x40,
-	0x53, 0x60, 0xe8, 0x10, 0xb4, 0xb5, 0x28, 0x99, 0xbb, 0x7c, 0x4a, 0x68, 0x54, 0x2f, 0x4a, 0xdd,
-	0x76, 0x86, 0x5b, 0x19, 0x8c, 0xde, 0xc1, 0xce, 0x9d, 0x1f, 0x23, 0x73, 0x57, 0xd4, 0x57, 0x2f,
-	0xc9, 0x1e, 0xf4, 0x5f, 0xf7, 0x30, 0xce, 0x32, 0xae, 0xa3, 0xec, 0x75, 0xce, 0x3b, 0x04, 0x19,
-	0x50, 0x9b, 0x10, 0xc2, 0x9d, 0x69, 0x38, 0xc7, 0x4c, 0xf6, 0xe4, 0x24, 0x2e, 0x9f, 0xd5, 0xcb,
-	0xb2, 0x96, 0x1d, 0xc1, 0x0d, 0x05, 0x25, 0x3a, 0xb3, 0x5c, 0x3e, 0x6b, 0x1f, 0x82, 0x7a, 0x37,
-	0x1a, 0xa4, 0x42, 0xf1, 0xd4, 0x32, 0xad, 0x81, 0x96, 0x43, 0x15, 0x28, 0x0c, 0xcd, 0x17, 0x03,
-	0x4d, 0x41, 0x65, 0xc8, 0x0f, 0xce, 0xce, 0xb5, 0x8d, 0xb6, 0x01, 0xda, 0xc3, 0x0a, 0xd0, 0x26,
-	0x94, 0x2d, 0x7b, 0x74, 0x32, 0x18, 0x8f, 0xb5, 0x1c, 0xda, 0x02, 0x78, 0xf6, 0xc6, 0x1a, 0xd8,
-	0xaf, 0xcc, 0xf1, 0xc8, 0xd6, 0x94, 0xf6, 0xd7, 0x3c, 0x6c, 0x59, 0x94, 0x78, 0x98, 0xb1, 0x3e,
-	0xe6, 0x6e, 0x38, 0x67, 0xe8, 0x1f, 0x00, 0x39, 0x44, 0x27, 0x76, 0x23, 0x2c, 0x97, 0xaa, 0xda,
-	0xaa, 0x44, 0x4e, 0xdd, 0x08, 0xa3, 0x13, 0x00, 0x8f, 0x62, 0x97, 0x63, 0xdf, 0x71, 0xb9, 0x5c,
-	0xec, 0xe6, 0xf1, 0xae, 0x9e, 0x1e, 0x8c, 0xbe, 0x3e, 0x18, 0xfd, 0x6c, 0x7d, 0x30, 0xbd, 0xca,
-	0xf5, 0x4d, 0x33, 0xf7, 0xf1, 0x5b, 0x53, 0xb1, 0xd5, 0x2c, 0xee, 0x29, 0x47, 0xff, 0x02, 0xba,
-	0xc0, 0x34, 0xc6, 0x73, 0x47, 0x5c, 0x96, 0xd3, 0xed, 0x74, 0x9c, 0x98, 0xc9, 0xd5, 0x16, 0xec,
-	0xed, 0x94, 0x11, 0x0e, 0xdd, 0x4e, 0xe7, 0x94, 0x21, 0x1d, 0xfe, 0x8c, 0x70, 0x44, 0xe8, 0xd2,
-	0xf1, 0x48, 0x14, 0x85, 0xdc, 0x99, 0x2c, 0x39, 0x66, 0x72, 0xc7, 0x05, 0x7b, 0x27, 0xa5, 0x4e,
-	0x24, 0xd3, 0x13, 0x04, 0x1a, 0x42, 0x2b, 0xd3, 0xbf, 0x27, 0xf4, 0x22, 0x8c, 0x03, 0x87, 0x61,
-	0xee, 0x24, 0x34, 0xbc, 0x74, 0x39, 0xce, 0x82, 0x8b, 0x32, 0xf8, 0xef, 0x54, 0x77, 0x9e, 0xca,
-	0xc6, 0x98, 0x5b, 0xa9, 0x28, 0xf5, 0xe9, 0x43, 0xf3, 0x11, 0x1f, 0x36, 0x73, 0x29, 0xf6, 0x33,
-	0x9b, 0x92, 0xb4, 0xf9, 0xeb, 0xa1, 0xcd, 0x58, 0x6a, 0x52, 0x97, 0xff, 0x00, 0x92, 0x74, 0xc0,
-	0x4e, 0xe8, 0xcb, 0x25, 0x57, 0x7b, 0xd5, 0xd5, 0x4d, 0x53, 0xcd, 0xc6, 0x6e, 0xf6, 0x6d, 0x35,
-	0x13, 0x98, 0x3e, 0xda, 0x07, 0x6d, 0xc1, 0x30, 0xfd, 0x69, 0x2c, 0x15, 0x99, 0xa4, 0x2a, 0xf0,
-	0xfb, 0xa1, 0xec, 0x41, 0x19, 0x5f, 0x61, 0x4f, 0x78, 0xaa, 0x62, 0x45, 0x3d, 0x58, 0xdd, 0x34,
-	0x4b, 0x83, 0x2b, 0xec, 0x99, 0x7d, 0xbb, 0x24, 0x28, 0xd3, 0xef, 0xf9, 0xd7, 0xb7, 0x8d, 0xdc,
-	0x97, 0xdb, 0x46, 0xee, 0xc3, 0xaa, 0xa1, 0x5c, 0xaf, 0x1a, 0xca, 0xe7, 0x55, 0x43, 0xf9, 0xbe,
-	0x6a, 0x28, 0x6f, 0x9f, 0xff, 0xfe, 0xdf, 0xcb, 0x93, 0xec, 0xf7, 0x75, 0x6e, 0x52, 0x92, 0x7b,
-	0xff, 0xff, 0x47, 0x00, 0x00, 0x00, 0xff, 0xff, 0xa3, 0x9a, 0x54, 0x17, 0xb5, 0x04, 0x00, 0x00,
+	// 842 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0x4d, 0x6f, 0xe3, 0x36,
+	0x14, 0xb4, 0x36, 0xf1, 0x87, 0x9e, 0xd7, 0x1b, 0x87, 0x1b, 0x14, 0x42, 0xda, 0xda, 0x86, 0xf7,
+	0xb0, 0x5e, 0xb4, 0x91, 0x92, 0xed, 0xa5, 0x40, 0x4f, 0xeb, 0xd8, 0x41, 0x5d, 0xb4, 0x89, 0x20,
+	0x07, 0xdd, 0x7e, 0x1c, 0x08, 0x5a, 0xa2, 0x65, 0xc1, 0x92, 0x28, 0x90, 0xb4, 0x37, 0xbe, 0xf5,
+	0x27, 0xf4, 0x37, 0xf5, 0x94, 0x63, 0x8f, 0x05, 0x0a, 0xa4, 0x5d, 0xff, 0x92, 0x82, 0xa4, 0x9c,
+	0xa0, 0xc1, 0xa2, 0x97, 0x9e, 0x4c, 0xcd, 0x0c, 0xe7, 0xf1, 0x3d, 0x0e, 0x0d, 0x57, 0x71, 0x22,
+	0x17, 0xab, 0x99, 0x1b, 0xb2, 0xcc, 0xfb, 0x2e, 0x09, 0x39, 0x13, 0x6c, 0x2e, 0xbd, 0x45, 0x28,
+	0xc4, 0x22, 0xc9, 0xbc, 0x30, 0x8b, 0xbc, 0x90, 0xe5, 0x92, 0x24, 0x39, 0xe5, 0xd1, 0x89, 0xc2,
+	0x4e, 0xf8, 0x2a, 0x5f, 0x84, 0xe2, 0x64, 0x7d, 0xe6, 0xb1, 0x42, 0x26, 0x2c, 0x17, 0x9e, 0x41,
+	0xdc, 0x82, 0x33, 0xc9, 0xd0, 0xd1, 0x83, 0xde, 0x2d, 0x89, 0xf5, 0xd9, 0xf1, 0x51, 0xcc, 0x62,
+	0xa6, 0x05, 0x9e, 0x5a, 0x19, 0xed, 0x71, 0x37, 0x66, 0x2c, 0x4e, 0xa9, 0xa7, 0xbf, 0x66, 0xab,
+	0xb9, 0x27, 0x93, 0x8c, 0x0a, 0x49, 0xb2, 0xc2, 0x08, 0xfa, 0xbf, 0x55, 0xa1, 0x7e, 0x65, 0xaa,
+	0xa0, 0x23, 0xa8, 0x46, 0x74, 0xb6, 0x8a, 0x1d, 0xab, 0x67, 0x0d, 0x1a, 0x81, 0xf9, 0x40, 0x17,
+	0x00, 0x7a, 0x81, 0xe5, 0xa6, 0xa0, 0xce, 0x93, 0x9e, 0x35, 0x78, 0xf6, 0xfa, 0xa5, 0xfb, 0xa1,
+	0x33, 0xb8, 0xa5, 0x91, 0x3b, 0x52, 0xfa, 0xeb, 0x4d, 0x41, 0x03, 0x3b, 0xda, 0x2d, 0xd1, 0x0b,
+	0x68, 0x71, 0x1a, 0x27, 0x42, 0xf2, 0x0d, 0xe6, 0x8c, 0x49, 0x67, 0xaf, 0x67, 0x0d, 0xec, 0xe0,
+	0xe9, 0x0e, 0x0c, 0x18, 0x93, 0x4a, 0x24, 0x48, 0x1e, 0xcd, 0xd8, 0x0d, 0x4e, 0x32, 0x12, 0x53,
+	0x67, 0xdf, 0x88, 0x4a, 0x70, 0xa2, 0x30, 0xf4, 0x0a, 0xda, 0x3b, 0x51, 0x91, 0x12, 0x39, 0x67,
+	0x3c, 0x73, 0xaa, 0x5a, 0x77, 0x50, 0xe2, 0x7e, 0x09, 0xa3, 0x9f, 0xe1, 0xf0, 0xde, 0x4f, 0xb0,
+	0x94, 0xa8, 0xf3, 0x39, 0x35, 0xdd, 0x83, 0xfb, 0xdf, 0x3d, 0x4c, 0xcb, 0x8a, 0xbb, 0x5d, 0x41,
+	0x5b, 0x3c, 0x42, 0x90, 0x07, 0x47, 0x33, 0xc6, 0x24, 0x9e, 0x27, 0x29, 0x15, 0xba, 0x27, 0x5c,
+	0x10, 0xb9, 0x70, 0xea, 0xfa, 0x2c, 0x87, 0x8a, 0xbb, 0x50, 0x94, 0xea, 0xcc, 0x27, 0x72, 0x81,
+	0x3e, 0x05, 0x58, 0x67, 0x78, 0x46, 0xc2, 0x25, 0xcd, 0x23, 0xa7, 0xa1, 0x65, 0xf6, 0x3a, 0x1b,
+	0x1a, 0x00, 0x7d, 0x06, 0x28, 0x23, 0x37, 0x98, 0xde, 0xd0, 0x50, 0xe0, 0x82, 0x72, 0x2c, 0x89,
+	0x58, 0x3a, 0x76, 0xcf, 0x1a, 0x54, 0x83, 0x83, 0x8c, 0xdc, 0x8c, 0x15, 0xe1, 0x53, 0x7e, 0x4d,
+	0xc4, 0x12, 0x75, 0xa1, 0x19, 0xb2, 0x7c, 0x9e, 0xc4, 0xa6, 0x26, 0x68, 0x33, 0x30, 0x90, 0x2e,
+	0xf6, 0x25, 0x38, 0x45, 0x52, 0x50, 0x2c, 0x68, 0xb8, 0xe2, 0x89, 0xdc, 0xe0, 0x88, 0x8a, 0x90,
+	0x27, 0x85, 0x64, 0xdc, 0x69, 0x6a, 0xf5, 0x47, 0x8a, 0x9f, 0x96, 0xf4, 0xe8, 0x9e, 0x45, 0x7d,
+	0x68, 0xa5, 0x2c, 0xd6, 0x6d, 0x19, 0xf3, 0xa7, 0x5a, 0xde, 0x4c, 0x59, 0xac, 0xfa, 0xd1, 0xee,
+	0x1e, 0x3c, 0x27, 0x69, 0xca, 0xde, 0xd1, 0x08, 0x93, 0x3c, 0x67, 0x52, 0x4f, 0x44, 0x38, 0xad,
+	0xde, 0xde, 0xc0, 0x0e, 0x50, 0x49, 0xbd, 0x79, 0x60, 0xfa, 0xaf, 0xc0, 0xbe, 0x8f, 0x05, 0xb2,
+	0xa1, 0x7a, 0xe9, 0x4f, 0xfc, 0x71, 0xbb, 0x82, 0x1a, 0xb0, 0x7f, 0x31, 0xf9, 0x76, 0xdc, 0xb6,
+	0x50, 0x1d, 0xf6, 0xc6, 0xd7, 0x6f, 0xdb, 0x4f, 0xfa, 0x1e, 0xb4, 0x1f, 0x4f, 0x1f, 0x35, 0xa1,
+	0xee, 0x07, 0x57, 0xe7, 0xe3, 0xe9, 0xb4, 0x5d, 0x41, 0xcf, 0x00, 0xbe, 0xfe, 0xd1, 0x1f, 0x07,
+	0xdf, 0x4f, 0xa6, 0x57, 0x41, 0xdb, 0xea, 0xff, 0xb9, 0x07, 0xcf, 0x7c, 0xce, 0x42, 0x2a, 0xc4,
+	0x88, 0x4a, 0x92, 0xa4, 0x42, 0x8d, 0x5a, 0x07, 0x08, 0xe7, 0x24, 0xa3, 0x3a, 0xd0, 0x76, 0x60,
+	0x6b, 0xe4, 0x92, 0x64, 0x14, 0x9d, 0x03, 0x84, 0x9c, 0x12, 0xa9, 0x8e, 0x2f, 0x75, 0xa8, 0x9b,
+	0xaf, 0x8f, 0x5d, 0xf3, 0x58, 0xdc, 0xdd, 0x63, 0x71, 0xaf, 0x77, 0x8f, 0x65, 0xd8, 0xb8, 0xbd,
+	0xeb, 0x56, 0x7e, 0xfd, 0xab, 0x6b, 0x05, 0x76, 0xb9, 0xef, 0x8d, 0x54, 0xf7, 0xb5, 0xa4, 0x3c,
+	0xa7, 0x29, 0x56, 0xaf, 0x0a, 0x9f, 0x9d, 0x9e, 0xe2, 0x5c, 0xe8, 0x58, 0xef, 0x07, 0x07, 0x86,
+	0x51, 0x0e, 0x67, 0xa7, 0xa7, 0x97, 0x02, 0xb9, 0xf0, 0x3c, 0xa3, 0x19, 0xe3, 0x1b, 0x1c, 0xb2,
+	0x2c, 0x4b, 0x24, 0x9e, 0x6d, 0x24, 0x15, 0x3a, 0xdf, 0xfb, 0xc1, 0xa1, 0xa1, 0xce, 0x35, 0x33,
+	0x54, 0x04, 0xba, 0x80, 0x5e, 0xa9, 0x7f, 0xc7, 0xf8, 0x32, 0xc9, 0x63, 0x2c, 0xa8, 0xc4, 0x05,
+	0x4f, 0xd6, 0x44, 0xd2, 0x72, 0x73, 0x55, 0x6f, 0xfe, 0xc4, 0xe8, 0xde, 0x1a, 0xd9, 0x94, 0x4a,
+	0xdf, 0x88, 0x8c, 0xcf, 0x08, 0xba, 0x1f, 0xf0, 0x11, 0x0b, 0xc2, 0x69, 0x54, 0xda, 0xd4, 0xb4,
+	0xcd, 0xc7, 0x8f, 0x6d, 0xa6, 0x5a, 0x63, 0x5c, 0x3e, 0x07, 0x28, 0xcc, 0x80, 0x71, 0x12, 0xe9,
+	0x80, 0xb7, 0x86, 0xad, 0xed, 0x5d, 0xd7, 0x2e, 0xc7, 0x3e, 0x19, 0x05, 0x76, 0x29, 0x98, 0x44,
+	0xe8, 0x25, 0xb4, 0x57, 0x82, 0xf2, 0x7f, 0x8d, 0xa5, 0xa1, 0x8b, 0xb4, 0x14, 0xfe, 0x30, 0x94,
+	0x17, 0x50, 0x57, 0x69, 0x57, 0x9e, 0x2a, 0xe6, 0xf6, 0x10, 0xb6, 0x77, 0xdd, 0x9a, 0xca, 0xf9,
+	0x64, 0x14, 0xd4, 0x14, 0x35, 0x89, 0x86, 0xd1, 0xed, 0xfb, 0x4e, 0xe5, 0x8f, 0xf7, 0x9d, 0xca,
+	0x2f, 0xdb, 0x8e, 0x75, 0xbb, 0xed, 0x58, 0xbf, 0x6f, 0x3b, 0xd6, 0xdf, 0xdb, 0x8e, 0xf5, 0xd3,
+	0x37, 0xff, 0xff, 0xaf, 0xf5, 0xab, 0xf2, 0xf7, 0x87, 0xca, 0xac, 0xa6, 0xef, 0xfd, 0x8b, 0x7f,
+	0x06, 0x00, 0xed, 0xdf, 0x92, 0x13, 0xb1, 0x05, 0x00, 0x00,
 }
 
 func (m *Options) Marshal() (dAtA []byte, err error) {
@@ -306,6 +343,50 @@ func (m *Options) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintRunhcs(dAtA, i, uint64(len(m.BootFilesRootPath)))
 		i += copy(dAtA[i:], m.BootFilesRootPath)
 	}
+	if len(m.VmBackend) > 0 {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintRunhcs(dAtA, i, uint64(len(m.VmBackend)))
+		i += copy(dAtA[i:], m.VmBackend)
+	}
+	if m.MaxExecsPerTask != 0 {
+		dAtA[i] = 0x48
+		i++
+		i = encodeVarintRunhcs(dAtA, i, uint64(m.MaxExecsPerTask))
+	}
+	if len(m.ConfigPath) > 0 {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintRunhcs(dAtA, i, uint64(len(m.ConfigPath)))
+		i += copy(dAtA[i:], m.ConfigPath)
+	}
+	if len(m.PipeSecurityDescriptor) > 0 {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintRunhcs(dAtA, i, uint64(len(m.PipeSecurityDescriptor)))
+		i += copy(dAtA[i:], m.PipeSecurityDescriptor)
+	}
+	if len(m.LogFilePath) > 0 {
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintRunhcs(dAtA, i, uint64(len(m.LogFilePath)))
+		i += copy(dAtA[i:], m.LogFilePath)
+	}
+	if len(m.AllowedAnnotations) > 0 {
+		for _, s := range m.AllowedAnnotations {
+			dAtA[i] = 0x6a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -423,6 +504,31 @@ func (m *Options) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovRunhcs(uint64(l))
 	}
+	l = len(m.VmBackend)
+	if l > 0 {
+		n += 1 + l + sovRunhcs(uint64(l))
+	}
+	if m.MaxExecsPerTask != 0 {
+		n += 1 + sovRunhcs(uint64(m.MaxExecsPerTask))
+	}
+	l = len(m.ConfigPath)
+	if l > 0 {
+		n += 1 + l + sovRunhcs(uint64(l))
+	}
+	l = len(m.PipeSecurityDescriptor)
+	if l > 0 {
+		n += 1 + l + sovRunhcs(uint64(l))
+	}
+	l = len(m.LogFilePath)
+	if l > 0 {
+		n += 1 + l + sovRunhcs(uint64(l))
+	}
+	if len(m.AllowedAnnotations) > 0 {
+		for _, s := range m.AllowedAnnotations {
+			l = len(s)
+			n += 1 + l + sovRunhcs(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -494,6 +600,12 @@ func (this *Options) String() string {
 		`SandboxPlatform:` + fmt.Sprintf("%v", this.SandboxPlatform) + `,`,
 		`SandboxIsolation:` + fmt.Sprintf("%v", this.SandboxIsolation) + `,`,
 		`BootFilesRootPath:` + fmt.Sprintf("%v", this.BootFilesRootPath) + `,`,
+		`VmBackend:` + fmt.Sprintf("%v", this.VmBackend) + `,`,
+		`MaxExecsPerTask:` + fmt.Sprintf("%v", this.MaxExecsPerTask) + `,`,
+		`ConfigPath:` + fmt.Sprintf("%v", this.ConfigPath) + `,`,
+		`PipeSecurityDescriptor:` + fmt.Sprintf("%v", this.PipeSecurityDescriptor) + `,`,
+		`LogFilePath:` + fmt.Sprintf("%v", this.LogFilePath) + `,`,
+		`AllowedAnnotations:` + fmt.Sprintf("%v", this.AllowedAnnotations) + `,`,
 		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
 		`}`,
 	}, "")
@@ -741,6 +853,185 @@ func (m *Options) Unmarshal(dAtA []byte) error {
 			}
 			m.BootFilesRootPath = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field VmBackend", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRunhcs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRunhcs
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRunhcs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.VmBackend = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxExecsPerTask", wireType)
+			}
+			m.MaxExecsPerTask = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRunhcs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxExecsPerTask |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConfigPath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRunhcs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRunhcs
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRunhcs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ConfigPath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PipeSecurityDescriptor", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRunhcs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRunhcs
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRunhcs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PipeSecurityDescriptor = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LogFilePath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRunhcs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRunhcs
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRunhcs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LogFilePath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AllowedAnnotations", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRunhcs
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRunhcs
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRunhcs
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AllowedAnnotations = append(m.AllowedAnnotations, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRunhcs(dAtA[iNdEx:])
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"io"
+	"io/ioutil"
+
+	"github.com/Microsoft/go-winio"
+	runhcsopts "github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/options"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/typeurl"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+)
+
+// readStartOptions reads the shim options that containerd writes to the stdin
+// of `shim start`, which forwards them to the stdin of `shim serve`. If
+// containerd did not pass any options returns `nil`.
+func readStartOptions(r io.Reader) (*runhcsopts.Options, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read shim options")
+	}
+	if len(b) == 0 {
+		return nil, nil
+	}
+	var any types.Any
+	if err := proto.Unmarshal(b, &any); err != nil {
+		return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "failed to unmarshal shim options: %s", err)
+	}
+	v, err := typeurl.UnmarshalAny(&any)
+	if err != nil {
+		return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "failed to unmarshal shim options: %s", err)
+	}
+	opts, ok := v.(*runhcsopts.Options)
+	if !ok {
+		return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "unexpected shim options type '%T'", v)
+	}
+	return opts, nil
+}
+
+// servePipeConfig returns the config of the named pipe the shim serves the task
+// and shimdiag APIs on. The pipe is created with the SDDL security descriptor
+// `sddl` so that no account outside of it can connect at any point. If `sddl`
+// is "" the default named pipe security descriptor is used.
+func servePipeConfig(sddl string) (*winio.PipeConfig, error) {
+	if sddl == "" {
+		return nil, nil
+	}
+	if _, err := winio.SddlToSecurityDescriptor(sddl); err != nil {
+		return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "invalid pipe security descriptor '%s': %s", sddl, err)
+	}
+	return &winio.PipeConfig{SecurityDescriptor: sddl}, nil
+}
+
+// checkPipeSecurityDescriptor returns an error if the security descriptor
+// `requested` in the options of a create differs from `served`, the one the
+// served pipe was created with. The descriptor cannot be changed once the pipe
+// exists.
+func checkPipeSecurityDescriptor(served, requested string) error {
+	if requested != "" && requested != served {
+		return errors.Wrapf(errdefs.ErrFailedPrecondition, "pipe security descriptor '%s' must be passed to shim start, the pipe was created with '%s'", requested, served)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/go-winio"
+	runhcsopts "github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/options"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/typeurl"
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+func Test_readStartOptions_None(t *testing.T) {
+	opts, err := readStartOptions(&bytes.Buffer{})
+	if err != nil || opts != nil {
+		t.Fatalf("expected no options, got: %+v, %v", opts, err)
+	}
+}
+
+func Test_readStartOptions(t *testing.T) {
+	any, err := typeurl.MarshalAny(&runhcsopts.Options{PipeSecurityDescriptor: "D:P(A;;GA;;;SY)"})
+	if err != nil {
+		t.Fatalf("failed to marshal options: %v", err)
+	}
+	b, err := proto.Marshal(any)
+	if err != nil {
+		t.Fatalf("failed to marshal any: %v", err)
+	}
+	opts, err := readStartOptions(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if opts == nil || opts.PipeSecurityDescriptor != "D:P(A;;GA;;;SY)" {
+		t.Fatalf("unexpected options: %+v", opts)
+	}
+}
+
+func Test_readStartOptions_Invalid(t *testing.T) {
+	_, err := readStartOptions(bytes.NewReader([]byte("not-protobuf")))
+	if errors.Cause(err) != errdefs.ErrInvalidArgument {
+		t.Fatalf("expected error: %v, got: %v", errdefs.ErrInvalidArgument, err)
+	}
+}
+
+func Test_servePipeConfig_InvalidSDDL(t *testing.T) {
+	_, err := servePipeConfig("not-sddl")
+	if errors.Cause(err) != errdefs.ErrInvalidArgument {
+		t.Fatalf("expected error: %v, got: %v", errdefs.ErrInvalidArgument, err)
+	}
+}
+
+func Test_servePipeConfig_Default(t *testing.T) {
+	c, err := servePipeConfig("")
+	if err != nil || c != nil {
+		t.Fatalf("expected the default config, got: %+v, %v", c, err)
+	}
+}
+
+func Test_servePipeConfig_Success(t *testing.T) {
+	p := `\\.\pipe\Test_servePipeConfig_Success`
+	c, err := servePipeConfig("D:P(A;;GA;;;WD)")
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	l, err := winio.ListenPipe(p, c)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			c.Close()
+		}
+	}()
+
+	timeout := 5 * time.Second
+	conn, err := winio.DialPipe(p, &timeout)
+	if err != nil {
+		t.Fatalf("failed to dial pipe created with security descriptor: %v", err)
+	}
+	conn.Close()
+}
+
+func Test_checkPipeSecurityDescriptor(t *testing.T) {
+	if err := checkPipeSecurityDescriptor("", ""); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if err := checkPipeSecurityDescriptor("D:P(A;;GA;;;SY)", "D:P(A;;GA;;;SY)"); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if err := checkPipeSecurityDescriptor("", "D:P(A;;GA;;;SY)"); errors.Cause(err) != errdefs.ErrFailedPrecondition {
+		t.Fatalf("expected error: %v, got: %v", errdefs.ErrFailedPrecondition, err)
+	}
+}
@@ -4,8 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/Microsoft/hcsshim/internal/hcsoci"
 	"github.com/Microsoft/hcsshim/internal/oci"
@@ -37,12 +38,18 @@ type shimPod interface {
 	//
 	// If `tid` is not found, this pod MUST return `errdefs.ErrNotFound`.
 	GetTask(tid string) (shimTask, error)
+	// ListTasks returns all tasks in this pod. The sandbox task is always
+	// first.
+	ListTasks() []shimTask
 	// KillTask sends `signal` to task that matches `tid`.
 	//
 	// If `tid` is not found, this pod MUST return `errdefs.ErrNotFound`.
 	//
 	// If `tid==ID() && eid == "" && all == true` this pod will send `signal` to
-	// all tasks in the pod and lastly send `signal` to the sandbox itself.
+	// all tasks in the pod and lastly send `signal` to the sandbox itself. If
+	// the `OrderedPodShutdown` feature is enabled the sandbox is only signalled
+	// once the workload tasks have exited or the pod workload stop timeout
+	// has elapsed.
 	//
 	// If `all == true && eid != ""` this pod MUST return
 	// `errdefs.ErrFailedPrecondition`.
@@ -83,25 +90,41 @@ func createPod(ctx context.Context, events publisher, req *task.CreateTaskReques
 			sid)
 	}
 
-	owner := filepath.Base(os.Args[0])
+	owner := shimOwner()
 	isWCOW := oci.IsWCOW(s)
 
 	var parent *uvm.UtilityVM
 	if oci.IsIsolated(s) {
+		// started is true if `parent` was claimed already running.
+		started := false
 		// Create the UVM parent
 		opts, err := oci.SpecToUVMCreateOpts(s, fmt.Sprintf("%s@vm", req.ID), owner)
 		if err != nil {
 			return nil, err
 		}
+		if err := getConfig().setUVMConsoleLog(opts); err != nil {
+			return nil, err
+		}
+		getConfig().setUVMGuestCrashDump(opts, req.Bundle)
+		limits, err := getConfig().reservationLimits()
+		if err != nil {
+			return nil, err
+		}
 		switch opts.(type) {
 		case *uvm.OptionsLCOW:
 			lopts := (opts).(*uvm.OptionsLCOW)
+			lopts.ReservationLimits = limits
+			if parent = claimPooledLCOW(lopts); parent != nil {
+				started = true
+				break
+			}
 			parent, err = uvm.CreateLCOW(lopts)
 			if err != nil {
-				return nil, err
+				return nil, wrapReservationError(err)
 			}
 		case *uvm.OptionsWCOW:
 			wopts := (opts).(*uvm.OptionsWCOW)
+			wopts.ReservationLimits = limits
 
 			// In order for the UVM sandbox.vhdx not to collide with the actual
 			// nested Argon sandbox.vhdx we append the \vm folder to the last
@@ -110,7 +133,7 @@ func createPod(ctx context.Context, events publisher, req *task.CreateTaskReques
 			layers := make([]string, layersLen)
 			copy(layers, s.Windows.LayerFolders)
 
-			vmPath := filepath.Join(layers[layersLen-1], "vm")
+			vmPath := uvmScratchPath(s)
 			err := os.MkdirAll(vmPath, 0)
 			if err != nil {
 				return nil, err
@@ -120,13 +143,27 @@ func createPod(ctx context.Context, events publisher, req *task.CreateTaskReques
 
 			parent, err = uvm.CreateWCOW(wopts)
 			if err != nil {
+				return nil, wrapReservationError(err)
+			}
+		}
+		if !started {
+			err = parent.StartContext(ctx)
+			if err != nil {
+				parent.Close()
 				return nil, err
 			}
 		}
-		err = parent.Start()
-		if err != nil {
+		// Creating the UVM is not cancellable. If the caller has given up
+		// in the meantime release it rather than create the sandbox.
+		if err = ctx.Err(); err != nil {
 			parent.Close()
-			return nil, err
+			return nil, errors.Wrapf(err, "pod: '%s' create cancelled", req.ID)
+		}
+		if oci.ParseAnnotationsSaveAsTemplate(s) {
+			if err = parent.SaveAsTemplate(); err != nil {
+				parent.Close()
+				return nil, errors.Wrapf(err, "pod: '%s' failed to save UVM as template", req.ID)
+			}
 		}
 	} else if !isWCOW {
 		return nil, errors.Wrap(errdefs.ErrFailedPrecondition, "oci spec does not contain WCOW or LCOW spec")
@@ -143,6 +180,14 @@ func createPod(ctx context.Context, events publisher, req *task.CreateTaskReques
 		id:     req.ID,
 		host:   parent,
 	}
+	if !isWCOW {
+		p.scratchPool = newPodScratchPool(parent)
+		defer func() {
+			if err != nil {
+				p.scratchPool.close()
+			}
+		}()
+	}
 	// TOOD: JTERRY75 - There is a bug in the compartment activation for Windows
 	// Process isolated that requires us to create the real pause container to
 	// hold the network compartment open. This is not required for Windows
@@ -153,13 +198,15 @@ func createPod(ctx context.Context, events publisher, req *task.CreateTaskReques
 		// need to provision the guest network namespace if this is hypervisor
 		// isolated. Process isolated WCOW gets the namespace endpoints
 		// automatically.
+		nsid := ""
 		if parent != nil {
-			nsid := ""
 			if s.Windows != nil && s.Windows.Network != nil {
 				nsid = s.Windows.Network.NetworkNamespace
 			}
 
-			if nsid != "" {
+			// A template has no network as its clones would otherwise share
+			// the network adapters of the template.
+			if nsid != "" && !parent.IsTemplate() {
 				endpoints, err := hcsoci.GetNamespaceEndpoints(nsid)
 				if err != nil {
 					return nil, err
@@ -174,7 +221,11 @@ func createPod(ctx context.Context, events publisher, req *task.CreateTaskReques
 				}
 			}
 		}
-		p.sandboxTask = newWcowPodSandboxTask(ctx, events, req.ID, req.Bundle, parent)
+		var overhead int32
+		if parent != nil {
+			overhead = hostMemoryOverhead(parent, s)
+		}
+		p.sandboxTask = newWcowPodSandboxTask(ctx, events, req.ID, req.Bundle, parent, nsid, overhead)
 		// Publish the created event. We only do this for a fake WCOW task. A
 		// HCS Task will event itself based on actual process lifetime.
 		events(
@@ -207,6 +258,13 @@ func createPod(ctx context.Context, events publisher, req *task.CreateTaskReques
 		}
 		p.sandboxTask = lt
 	}
+	if p.scratchPool != nil {
+		// The disks are formatted by the UVM so the pool is closed with it.
+		go func() {
+			p.sandboxTask.Wait(context.Background())
+			p.scratchPool.close()
+		}()
+	}
 
 	return &p, nil
 }
@@ -230,6 +288,11 @@ type pod struct {
 	//
 	// It MUST be treated as read only in the lifetime of the pod.
 	host *uvm.UtilityVM
+	// scratchPool creates the scratch disks of the workload tasks missing
+	// one if `host` is an LCOW UVM and a pool is configured. nil otherwise.
+	//
+	// It MUST be treated as read only in the lifetime of the pod.
+	scratchPool *podScratchPool
 
 	// wcl is the worload create mutex. All calls to CreateTask must hold this
 	// lock while the ID reservation takes place. Once the ID is held it is safe
@@ -251,6 +314,9 @@ func (p *pod) CreateTask(ctx context.Context, req *task.CreateTaskRequest, s *sp
 	if req.ID == p.id {
 		return nil, errors.Wrapf(errdefs.ErrAlreadyExists, "task with id: '%s' already exists", req.ID)
 	}
+	if p.host != nil && p.host.IsTemplate() {
+		return nil, errors.Wrapf(errdefs.ErrFailedPrecondition, "task with id: '%s' cannot be created in pod: '%s' which is a template", req.ID, p.id)
+	}
 	e, _ := p.sandboxTask.GetExec("")
 	if e.State() != shimExecStateRunning {
 		return nil, errors.Wrapf(errdefs.ErrFailedPrecondition, "task with id: '%s' cannot be created in pod: '%s' which is not running", req.ID, p.id)
@@ -290,10 +356,17 @@ func (p *pod) CreateTask(ctx context.Context, req *task.CreateTaskRequest, s *sp
 			sid)
 	}
 
+	if err = p.scratchPool.createScratch(s); err != nil {
+		return nil, err
+	}
 	st, err := newHcsTask(ctx, p.events, p.host, false, req, s)
 	if err != nil {
 		return nil, err
 	}
+	if err = ctx.Err(); err != nil {
+		abandonTask(st)
+		return nil, errors.Wrapf(err, "task: '%s' create cancelled", req.ID)
+	}
 
 	p.workloadTasks.Store(req.ID, st)
 	return st, nil
@@ -310,6 +383,10 @@ func (p *pod) GetTask(tid string) (shimTask, error) {
 	return raw.(shimTask), nil
 }
 
+func (p *pod) ListTasks() []shimTask {
+	return append([]shimTask{p.sandboxTask}, p.workloads()...)
+}
+
 func (p *pod) KillTask(ctx context.Context, tid, eid string, signal uint32, all bool) error {
 	logrus.WithFields(logrus.Fields{
 		"pod-id": p.id,
@@ -326,21 +403,139 @@ func (p *pod) KillTask(ctx context.Context, tid, eid string, signal uint32, all
 	if all && eid != "" {
 		return errors.Wrapf(errdefs.ErrFailedPrecondition, "cannot signal all with non empty ExecID: '%s'", eid)
 	}
-	eg := errgroup.Group{}
 	if all && tid == p.id {
-		// We are in a kill all on the sandbox task. Signal everything.
-		p.workloadTasks.Range(func(key, value interface{}) bool {
-			wt := value.(shimTask)
+		// We are in a kill all on the sandbox task. Signal the workload tasks
+		// before the sandbox so their state is not stranded by the teardown
+		// of the sandbox and its UVM.
+		if getConfig().featureEnabled(featureOrderedPodShutdown) {
+			werr := p.killWorkloadTasks(ctx, signal, getConfig().podWorkloadStopTimeout())
+			if err := t.KillExec(ctx, eid, signal, all); err != nil {
+				return err
+			}
+			return werr
+		}
+		eg := errgroup.Group{}
+		for _, wt := range p.workloads() {
+			wt := wt
 			eg.Go(func() error {
 				return wt.KillExec(ctx, eid, signal, all)
 			})
-
-			// iterate all
-			return false
+		}
+		eg.Go(func() error {
+			return t.KillExec(ctx, eid, signal, all)
 		})
+		return eg.Wait()
 	}
-	eg.Go(func() error {
-		return t.KillExec(ctx, eid, signal, all)
+	return t.KillExec(ctx, eid, signal, all)
+}
+
+// workloads returns the workload tasks in the pod. Tasks that are still being
+// created are not returned.
+func (p *pod) workloads() []shimTask {
+	var tasks []shimTask
+	p.workloadTasks.Range(func(key, value interface{}) bool {
+		// A `nil` value is an ID reserved by an in progress `CreateTask`.
+		if wt, ok := value.(shimTask); ok {
+			tasks = append(tasks, wt)
+		}
+		return true
 	})
-	return eg.Wait()
+	return tasks
+}
+
+// killWorkloadTasks sends `signal` to every process of every workload task in
+// the pod and waits up to `timeout` for the init exec of each to exit. The
+// first error signalling a task is returned once the wait completes.
+func (p *pod) killWorkloadTasks(ctx context.Context, signal uint32, timeout time.Duration) error {
+	tasks := p.workloads()
+	eg := errgroup.Group{}
+	for _, wt := range tasks {
+		wt := wt
+		eg.Go(func() error {
+			return wt.KillExec(ctx, "", signal, true)
+		})
+	}
+	err := eg.Wait()
+
+	wctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	for _, wt := range tasks {
+		e, eerr := wt.GetExec("")
+		if eerr != nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.Wait(wctx)
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-wctx.Done():
+		logrus.WithFields(logrus.Fields{
+			"pod-id":  p.id,
+			"timeout": timeout,
+		}).Warning("pod::killWorkloadTasks - workload tasks did not exit before timeout, signalling sandbox")
+	}
+	return err
+}
+
+// shutdownPod stops every task in `p`, in the order of a kill all of the pod.
+// The tasks are sent SIGTERM and given up to the pod workload stop timeout to
+// exit before they are sent SIGKILL, after which the sandbox task and its UVM
+// are again given up to the timeout to be torn down. If the sandbox task is
+// not running this is a no-op.
+func shutdownPod(ctx context.Context, p shimPod) error {
+	st, err := p.GetTask(p.ID())
+	if err != nil {
+		return err
+	}
+	e, err := st.GetExec("")
+	if err != nil {
+		return err
+	}
+	if e.State() != shimExecStateRunning {
+		return nil
+	}
+	timeout := getConfig().podWorkloadStopTimeout()
+	if err := p.KillTask(ctx, p.ID(), "", uint32(syscall.SIGTERM), true); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"pod-id":        p.ID(),
+			logrus.ErrorKey: err,
+		}).Warning("shutdownPod - failed to signal pod, killing")
+	} else if waitForTaskExit(ctx, st, timeout) {
+		return nil
+	}
+	if err := p.KillTask(ctx, p.ID(), "", uint32(syscall.SIGKILL), true); err != nil {
+		return err
+	}
+	if !waitForTaskExit(ctx, st, timeout) {
+		return errors.Wrapf(context.DeadlineExceeded, "timed out waiting for pod: '%s' to exit", p.ID())
+	}
+	return nil
+}
+
+// waitForTaskExit waits up to `timeout` for the task `t` to exit and returns
+// `true` if it did.
+func waitForTaskExit(ctx context.Context, t shimTask, timeout time.Duration) bool {
+	wctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		t.Wait(wctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-wctx.Done():
+		return false
+	}
 }
@@ -5,6 +5,7 @@ import (
 	"math/rand"
 	"strconv"
 	"sync"
+	"syscall"
 	"testing"
 
 	"github.com/containerd/containerd/errdefs"
@@ -36,6 +37,20 @@ func (tsp *testShimPod) GetTask(tid string) (shimTask, error) {
 	return nil, errdefs.ErrNotFound
 }
 
+func (tsp *testShimPod) ListTasks() []shimTask {
+	var tasks []shimTask
+	tsp.tasks.Range(func(key, value interface{}) bool {
+		t := value.(shimTask)
+		if t.ID() == tsp.id {
+			tasks = append([]shimTask{t}, tasks...)
+		} else {
+			tasks = append(tasks, t)
+		}
+		return true
+	})
+	return tasks
+}
+
 func (tsp *testShimPod) KillTask(ctx context.Context, tid, eid string, signal uint32, all bool) error {
 	s, err := tsp.GetTask(tid)
 	if err != nil {
@@ -144,6 +159,39 @@ func Test_pod_KillTask_SandboxID_InitExecID_All_Success(t *testing.T) {
 	}
 }
 
+func Test_pod_KillTask_SandboxID_InitExecID_All_WorkloadsFirst(t *testing.T) {
+	p, st := setupTestPodWithFakes(t)
+	wt := setupTestTaskInPod(t, p)
+	wt.exec.exited = make(chan struct{})
+	wt.exec.waiting = make(chan struct{})
+
+	done := make(chan error)
+	go func() {
+		done <- p.KillTask(context.TODO(), t.Name(), "", 0xf, true)
+	}()
+	<-wt.exec.waiting
+	if st.exec.State() == shimExecStateExited {
+		t.Fatal("sandbox should not be signalled before the workload task exits")
+	}
+	close(wt.exec.exited)
+	if err := <-done; err != nil {
+		t.Fatalf("should not have failed, got: %v", err)
+	}
+	if st.exec.State() != shimExecStateExited {
+		t.Fatal("sandbox should be signalled once the workload task exits")
+	}
+}
+
+func Test_pod_KillTask_SandboxID_InitExecID_All_CreatingTask_Success(t *testing.T) {
+	p, _ := setupTestPodWithFakes(t)
+	// A workload task whose create is in progress only has its ID reserved.
+	p.workloadTasks.Store("creating", nil)
+	err := p.KillTask(context.TODO(), t.Name(), "", 0xf, true)
+	if err != nil {
+		t.Fatalf("should not have failed, got: %v", err)
+	}
+}
+
 func Test_pod_KillTask_SandboxID_2ndExecID_Success(t *testing.T) {
 	p, t1 := setupTestPodWithFakes(t)
 	for k := range t1.execs {
@@ -205,3 +253,28 @@ func Test_pod_KillTask_WorkloadID_2ndExecID_All_Error(t *testing.T) {
 		verifyExpectedError(t, nil, err, errdefs.ErrFailedPrecondition)
 	}
 }
+
+func Test_shutdownPod_SIGTERM_Exits_NoSIGKILL(t *testing.T) {
+	p, st := setupTestPodWithFakes(t)
+	st.exec.state = shimExecStateRunning
+
+	err := shutdownPod(context.TODO(), p)
+	if err != nil {
+		t.Fatalf("should not have failed, got: %v", err)
+	}
+	if len(st.exec.signals) != 1 || st.exec.signals[0] != uint32(syscall.SIGTERM) {
+		t.Fatalf("expected only SIGTERM to be sent, got: %v", st.exec.signals)
+	}
+}
+
+func Test_shutdownPod_NotRunning_NoSignal(t *testing.T) {
+	p, st := setupTestPodWithFakes(t)
+
+	err := shutdownPod(context.TODO(), p)
+	if err != nil {
+		t.Fatalf("should not have failed, got: %v", err)
+	}
+	if len(st.exec.signals) != 0 {
+		t.Fatalf("expected no signal to be sent, got: %v", st.exec.signals)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultPprofDuration is the duration of sampled profiles if the caller
+	// does not specify one.
+	defaultPprofDuration = time.Second * 30
+	// pprofMutexFraction is the mutex profile fraction used while sampling a
+	// mutex profile. On average 1 in `pprofMutexFraction` contention events is
+	// reported.
+	pprofMutexFraction = 5
+)
+
+// collectProfile returns the pprof profile `name` of the shim in the protobuf
+// format understood by `go tool pprof`.
+//
+// `heap` and `goroutine` are snapshots. `cpu` and `mutex` are sampled for
+// `duration`, or `defaultPprofDuration` if `duration == 0`, and return
+// `ctx.Err()` if `ctx` is done first.
+func collectProfile(ctx context.Context, name string, duration time.Duration) ([]byte, error) {
+	if duration == 0 {
+		duration = defaultPprofDuration
+	}
+	var buf bytes.Buffer
+	switch name {
+	case "heap", "goroutine":
+		if err := pprof.Lookup(name).WriteTo(&buf, 0); err != nil {
+			return nil, err
+		}
+	case "cpu":
+		if err := pprof.StartCPUProfile(&buf); err != nil {
+			return nil, errors.Wrap(errdefs.ErrFailedPrecondition, err.Error())
+		}
+		err := sleepContext(ctx, duration)
+		pprof.StopCPUProfile()
+		if err != nil {
+			return nil, err
+		}
+	case "mutex":
+		prev := runtime.SetMutexProfileFraction(pprofMutexFraction)
+		err := sleepContext(ctx, duration)
+		runtime.SetMutexProfileFraction(prev)
+		if err != nil {
+			return nil, err
+		}
+		if err := pprof.Lookup(name).WriteTo(&buf, 0); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "unknown profile '%s'", name)
+	}
+	return buf.Bytes(), nil
+}
+
+// sleepContext waits for `d` or until `ctx` is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
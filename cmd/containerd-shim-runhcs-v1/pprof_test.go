@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/pkg/errors"
+)
+
+func Test_collectProfile_Snapshots(t *testing.T) {
+	for _, name := range []string{"heap", "goroutine"} {
+		b, err := collectProfile(context.TODO(), name, 0)
+		if err != nil {
+			t.Fatalf("%s: should not have failed with error, got: %v", name, err)
+		}
+		if len(b) == 0 {
+			t.Fatalf("%s: expected a non-empty profile", name)
+		}
+	}
+}
+
+func Test_collectProfile_Sampled(t *testing.T) {
+	for _, name := range []string{"cpu", "mutex"} {
+		b, err := collectProfile(context.TODO(), name, time.Millisecond*10)
+		if err != nil {
+			t.Fatalf("%s: should not have failed with error, got: %v", name, err)
+		}
+		if len(b) == 0 {
+			t.Fatalf("%s: expected a non-empty profile", name)
+		}
+	}
+}
+
+func Test_collectProfile_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := collectProfile(ctx, "cpu", time.Minute)
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got: %v", err)
+	}
+}
+
+func Test_collectProfile_Unknown_Error(t *testing.T) {
+	_, err := collectProfile(context.TODO(), "block", 0)
+	if errors.Cause(err) != errdefs.ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument, got: %v", err)
+	}
+}
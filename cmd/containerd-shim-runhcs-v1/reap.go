@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/hcs"
+	"github.com/Microsoft/hcsshim/internal/schema1"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/windows"
+)
+
+//go:generate go run ../../mksyscall_windows.go -output zsyscall_windows.go reap.go
+
+//sys queryFullProcessImageName(process windows.Handle, flags uint32, exeName *uint16, size *uint32) (err error) = kernel32.QueryFullProcessImageNameW
+
+const (
+	// reapTimeout is the time waited for a stale compute system to terminate.
+	reapTimeout = time.Second * 30
+
+	processQueryLimitedInformation = 0x1000
+	stillActive                    = 259
+)
+
+// shimOwner returns the owner of the compute systems created by this shim. It
+// names the shim executable and the pid of this shim so that a later shim can
+// tell whether the creator of a compute system is still running.
+func shimOwner() string {
+	return fmt.Sprintf("%s:%d", filepath.Base(os.Args[0]), os.Getpid())
+}
+
+// parseShimOwner returns the executable and pid of the shim that created a
+// compute system with `owner`. Returns `false` if `owner` was not set by
+// `shimOwner`.
+func parseShimOwner(owner string) (string, int, bool) {
+	i := strings.LastIndex(owner, ":")
+	if i < 0 {
+		return "", 0, false
+	}
+	pid, err := strconv.Atoi(owner[i+1:])
+	if err != nil || pid <= 0 {
+		return "", 0, false
+	}
+	return owner[:i], pid, true
+}
+
+// staleComputeSystemIDs returns the ids of the compute systems a shim would
+// create for task `tid`. This is the container itself and, if hypervisor
+// isolated, its UVM.
+func staleComputeSystemIDs(tid string) []string {
+	return []string{tid, fmt.Sprintf("%s@vm", tid)}
+}
+
+// shimRunningFunc returns `true` if the shim executable `exe` with `pid` is
+// still running.
+type shimRunningFunc func(exe string, pid int) (bool, error)
+
+// orphanedComputeSystems returns the ids in `systems` whose creator, a shim
+// executable `exe`, is provably no longer running. If any system is owned by
+// someone else, by a shim that is still running, or by a shim whose state
+// cannot be determined it is not safe to reap and `errdefs.ErrAlreadyExists`
+// is returned.
+func orphanedComputeSystems(systems []schema1.ContainerProperties, exe string, running shimRunningFunc) ([]string, error) {
+	var ids []string
+	for _, p := range systems {
+		ownerExe, pid, ok := parseShimOwner(p.Owner)
+		if !ok || !strings.EqualFold(ownerExe, exe) {
+			return nil, errors.Wrapf(errdefs.ErrAlreadyExists, "compute system '%s' exists and is owned by '%s'", p.ID, p.Owner)
+		}
+		if pid == os.Getpid() {
+			return nil, errors.Wrapf(errdefs.ErrAlreadyExists, "compute system '%s' exists and is owned by this shim", p.ID)
+		}
+		alive, err := running(exe, pid)
+		if err != nil {
+			return nil, errors.Wrapf(errdefs.ErrAlreadyExists, "compute system '%s' exists and the state of its owner '%s' is unknown: %s", p.ID, p.Owner, err)
+		}
+		if alive {
+			return nil, errors.Wrapf(errdefs.ErrAlreadyExists, "compute system '%s' exists and its owner '%s' is still running", p.ID, p.Owner)
+		}
+		ids = append(ids, p.ID)
+	}
+	return ids, nil
+}
+
+// shimRunning returns `true` if the process `pid` is still running and is an
+// instance of the shim executable `exe`. A pid that has been reused by any
+// other executable is not running the shim.
+func shimRunning(exe string, pid int) (bool, error) {
+	h, err := windows.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		if err == windows.ERROR_INVALID_PARAMETER {
+			// There is no process with `pid`.
+			return false, nil
+		}
+		return false, err
+	}
+	defer windows.CloseHandle(h)
+	var code uint32
+	if err := windows.GetExitCodeProcess(h, &code); err != nil {
+		return false, err
+	}
+	if code != stillActive {
+		return false, nil
+	}
+	buf := make([]uint16, windows.MAX_LONG_PATH)
+	n := uint32(len(buf))
+	if err := queryFullProcessImageName(h, 0, &buf[0], &n); err != nil {
+		return false, err
+	}
+	return strings.EqualFold(filepath.Base(windows.UTF16ToString(buf[:n])), exe), nil
+}
+
+// reapStaleComputeSystems terminates any compute systems for task `tid` left
+// behind by a prior instance of this shim that crashed. The caller MUST have
+// verified that this shim is not tracking `tid`. A compute system is only
+// reaped if the shim that created it is no longer running, as it can then
+// never be managed again. Otherwise the create fails with
+// `errdefs.ErrAlreadyExists`.
+func reapStaleComputeSystems(ctx context.Context, tid string) error {
+	systems, err := hcs.GetComputeSystems(schema1.ComputeSystemQuery{IDs: staleComputeSystemIDs(tid)})
+	if err != nil {
+		return err
+	}
+	ids, err := orphanedComputeSystems(systems, filepath.Base(os.Args[0]), shimRunning)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		logrus.WithFields(logrus.Fields{
+			"tid": tid,
+			"id":  id,
+		}).Warning("reapStaleComputeSystems - terminating orphaned compute system")
+		if err := terminateComputeSystem(ctx, id); err != nil {
+			return errors.Wrapf(err, "failed to reap orphaned compute system '%s'", id)
+		}
+	}
+	return nil
+}
+
+// terminateComputeSystem terminates the compute system `id` and waits up to
+// `reapTimeout` for it to exit.
+func terminateComputeSystem(ctx context.Context, id string) error {
+	sys, err := hcs.OpenComputeSystem(id)
+	if err != nil {
+		if hcs.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer sys.Close()
+	if err := sys.Terminate(); err != nil {
+		return err
+	}
+	ch := make(chan error, 1)
+	go func() { ch <- sys.Wait() }()
+	t := time.NewTimer(reapTimeout)
+	defer t.Stop()
+	select {
+	case err := <-ch:
+		return err
+	case <-t.C:
+		return errors.Wrapf(errdefs.ErrFailedPrecondition, "timed out waiting for '%s' to terminate", id)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
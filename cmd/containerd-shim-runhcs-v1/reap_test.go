@@ -0,0 +1,153 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/schema1"
+	"github.com/containerd/containerd/errdefs"
+	pkgerrors "github.com/pkg/errors"
+)
+
+const testShimExe = "containerd-shim-runhcs-v1.exe"
+
+func runningPids(pids ...int) shimRunningFunc {
+	return func(exe string, pid int) (bool, error) {
+		for _, p := range pids {
+			if p == pid {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+func Test_staleComputeSystemIDs(t *testing.T) {
+	ids := staleComputeSystemIDs(t.Name())
+	if len(ids) != 2 || ids[0] != t.Name() || ids[1] != t.Name()+"@vm" {
+		t.Fatalf("unexpected ids: %v", ids)
+	}
+}
+
+func Test_parseShimOwner(t *testing.T) {
+	tests := []struct {
+		owner string
+		exe   string
+		pid   int
+		ok    bool
+	}{
+		{testShimExe + ":1234", testShimExe, 1234, true},
+		{testShimExe, "", 0, false},
+		{testShimExe + ":x", "", 0, false},
+		{testShimExe + ":0", "", 0, false},
+		{"docker", "", 0, false},
+	}
+	for _, test := range tests {
+		exe, pid, ok := parseShimOwner(test.owner)
+		if exe != test.exe || pid != test.pid || ok != test.ok {
+			t.Errorf("%s: expected %s, %d, %v got %s, %d, %v", test.owner, test.exe, test.pid, test.ok, exe, pid, ok)
+		}
+	}
+	if exe, pid, ok := parseShimOwner(shimOwner()); !ok || pid != os.Getpid() || exe == "" {
+		t.Fatalf("failed to parse own owner '%s'", shimOwner())
+	}
+}
+
+func Test_orphanedComputeSystems_None(t *testing.T) {
+	ids, err := orphanedComputeSystems(nil, testShimExe, runningPids())
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if len(ids) != 0 {
+		t.Fatalf("expected no ids, got: %v", ids)
+	}
+}
+
+func Test_orphanedComputeSystems_OwnerExited(t *testing.T) {
+	systems := []schema1.ContainerProperties{
+		{ID: t.Name(), Owner: testShimExe + ":1234"},
+		{ID: t.Name() + "@vm", Owner: testShimExe + ":1234"},
+	}
+	ids, err := orphanedComputeSystems(systems, testShimExe, runningPids(5678))
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if len(ids) != 2 {
+		t.Fatalf("expected 2 ids, got: %v", ids)
+	}
+}
+
+func Test_orphanedComputeSystems_OwnerRunning(t *testing.T) {
+	systems := []schema1.ContainerProperties{
+		{ID: t.Name(), Owner: testShimExe + ":1234"},
+	}
+	_, err := orphanedComputeSystems(systems, testShimExe, runningPids(1234))
+	if pkgerrors.Cause(err) != errdefs.ErrAlreadyExists {
+		t.Fatalf("expected ErrAlreadyExists, got: %v", err)
+	}
+}
+
+func Test_orphanedComputeSystems_OwnerThisShim(t *testing.T) {
+	systems := []schema1.ContainerProperties{
+		{ID: t.Name(), Owner: fmt.Sprintf("%s:%d", testShimExe, os.Getpid())},
+	}
+	_, err := orphanedComputeSystems(systems, testShimExe, runningPids())
+	if pkgerrors.Cause(err) != errdefs.ErrAlreadyExists {
+		t.Fatalf("expected ErrAlreadyExists, got: %v", err)
+	}
+}
+
+func Test_orphanedComputeSystems_OwnerUnknown(t *testing.T) {
+	systems := []schema1.ContainerProperties{
+		{ID: t.Name(), Owner: testShimExe + ":1234"},
+	}
+	failed := func(exe string, pid int) (bool, error) {
+		return false, errors.New("access denied")
+	}
+	_, err := orphanedComputeSystems(systems, testShimExe, failed)
+	if pkgerrors.Cause(err) != errdefs.ErrAlreadyExists {
+		t.Fatalf("expected ErrAlreadyExists, got: %v", err)
+	}
+}
+
+func Test_orphanedComputeSystems_LegacyOwner(t *testing.T) {
+	// A compute system owned by a shim that did not record its pid cannot be
+	// proven to be orphaned.
+	systems := []schema1.ContainerProperties{
+		{ID: t.Name(), Owner: testShimExe},
+	}
+	_, err := orphanedComputeSystems(systems, testShimExe, runningPids())
+	if pkgerrors.Cause(err) != errdefs.ErrAlreadyExists {
+		t.Fatalf("expected ErrAlreadyExists, got: %v", err)
+	}
+}
+
+func Test_orphanedComputeSystems_OtherOwner(t *testing.T) {
+	systems := []schema1.ContainerProperties{
+		{ID: t.Name(), Owner: "docker"},
+	}
+	_, err := orphanedComputeSystems(systems, testShimExe, runningPids())
+	if pkgerrors.Cause(err) != errdefs.ErrAlreadyExists {
+		t.Fatalf("expected ErrAlreadyExists, got: %v", err)
+	}
+}
+
+func Test_shimRunning_Self(t *testing.T) {
+	exe, _, _ := parseShimOwner(shimOwner())
+	running, err := shimRunning(exe, os.Getpid())
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if !running {
+		t.Fatal("expected this process to be running")
+	}
+	running, err = shimRunning("other.exe", os.Getpid())
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if running {
+		t.Fatal("expected a different executable not to be reported as running")
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/Microsoft/hcsshim/internal/hcs"
+	"github.com/Microsoft/hcsshim/internal/hcsoci"
+	"github.com/Microsoft/hcsshim/internal/oci"
+	"github.com/Microsoft/hcsshim/internal/schema1"
+	eventstypes "github.com/containerd/containerd/api/events"
+	"github.com/containerd/containerd/runtime"
+	"github.com/containerd/containerd/runtime/v2/task"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+)
+
+// reattachHcsTask returns the task for the container `req.ID` left running by
+// a prior instance of this shim that crashed, or `nil` if it cannot be
+// reattached. A container that cannot be reattached is reaped by
+// `reapStaleComputeSystems` instead.
+//
+// Only process isolated WCOW containers are reattached. The devices a shim adds
+// to a UVM are not persisted so a hypervisor isolated task cannot be rebuilt.
+// The init exec is reopened by the pid persisted to the bundle when it
+// started. HCS only hands out the stdio of a process once so the IO of the
+// reattached init exec is not relayed.
+func reattachHcsTask(ctx context.Context, events publisher, req *task.CreateTaskRequest, s *specs.Spec) (shimTask, error) {
+	if !oci.IsWCOW(s) || oci.IsIsolated(s) {
+		return nil, nil
+	}
+	state, err := readExecState(req.Bundle, req.ID)
+	if err != nil || state.Pid == 0 || !state.ExitedAt.IsZero() {
+		return nil, nil
+	}
+	systems, err := hcs.GetComputeSystems(schema1.ComputeSystemQuery{IDs: []string{req.ID}})
+	if err != nil || len(systems) != 1 {
+		return nil, nil
+	}
+	if _, err := orphanedComputeSystems(systems, filepath.Base(os.Args[0]), shimRunning); err != nil {
+		return nil, nil
+	}
+	log := logrus.WithFields(logrus.Fields{
+		"tid": req.ID,
+		"pid": state.Pid,
+	})
+	system, err := hcs.OpenComputeSystem(req.ID)
+	if err != nil {
+		log.WithError(err).Warning("reattachHcsTask - failed to open compute system")
+		return nil, nil
+	}
+	p, err := system.OpenProcess(state.Pid)
+	if err != nil {
+		log.WithError(err).Warning("reattachHcsTask - failed to open init process")
+		system.Close()
+		return nil, nil
+	}
+	io, err := newNpipeIO(ctx, req.ID, req.ID, req.Stdin, req.Stdout, req.Stderr, req.Terminal)
+	if err != nil {
+		p.Close()
+		system.Close()
+		return nil, err
+	}
+	log.Warning("reattachHcsTask - reattached to compute system left by a prior shim")
+
+	events = newExitSequencer(req.ID, events).Publish
+	ht := &hcsTask{
+		events: events,
+		id:     req.ID,
+		isWCOW: true,
+		c:      system,
+		cr:     hcsoci.HostResources(s),
+		closed: make(chan struct{}),
+
+		killPolicy:       getConfig().killPolicy(s),
+		maxExecs:         oci.ParseAnnotationsMaxExecs(s),
+		execEnv:          hcsoci.ExecEnv(s),
+		execLimits:       oci.ParseAnnotationsExecLimits(s),
+		execDrainTimeout: oci.ParseAnnotationsExecDrainTimeout(s),
+	}
+	ht.init = newAttachedHcsExec(
+		events,
+		req.ID,
+		system,
+		req.ID,
+		req.Bundle,
+		ht.isWCOW,
+		s.Process,
+		io,
+		ht.killPolicy,
+		state.Pid,
+		p)
+	go func() {
+		ht.init.Wait(context.Background())
+		ht.drainExecs()
+		ht.close()
+	}()
+
+	ht.events(
+		runtime.TaskCreateEventTopic,
+		&eventstypes.TaskCreate{
+			ContainerID: req.ID,
+			Bundle:      req.Bundle,
+			Rootfs:      req.Rootfs,
+			IO: &eventstypes.TaskIO{
+				Stdin:    req.Stdin,
+				Stdout:   req.Stdout,
+				Stderr:   req.Stderr,
+				Terminal: req.Terminal,
+			},
+			Checkpoint: "",
+			Pid:        uint32(state.Pid),
+		})
+	return ht, nil
+}
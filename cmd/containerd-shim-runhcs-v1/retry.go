@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/hcs"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// transientRetryAttempts is the number of times an operation that fails
+	// with a transient HCS error is attempted in total.
+	transientRetryAttempts = 5
+	// transientRetryBackoff is the delay before the first retry. It doubles
+	// on each retry up to `transientRetryMaxBackoff`.
+	transientRetryBackoff = time.Millisecond * 100
+	// transientRetryMaxBackoff is the maximum delay between two attempts.
+	transientRetryMaxBackoff = time.Second * 2
+)
+
+// retryTransient calls `f` until it succeeds, fails with an error that is not
+// transient (see `hcs.IsTransient`), `transientRetryAttempts` is exhausted or
+// `ctx` is done. It returns the last error of `f`.
+//
+// `f` MUST clean up any partial state on failure so that it is safe to call
+// again.
+func retryTransient(ctx context.Context, log *logrus.Entry, op string, f func() error) error {
+	backoff := transientRetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil || !hcs.IsTransient(err) || attempt == transientRetryAttempts {
+			return err
+		}
+		log.WithFields(logrus.Fields{
+			"attempt":       attempt,
+			"backoff":       backoff,
+			logrus.ErrorKey: err,
+		}).Warning(op + " - retrying after transient error")
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+		backoff *= 2
+		if backoff > transientRetryMaxBackoff {
+			backoff = transientRetryMaxBackoff
+		}
+	}
+}
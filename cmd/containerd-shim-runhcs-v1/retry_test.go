@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/backend"
+	"github.com/Microsoft/hcsshim/internal/cow"
+	"github.com/Microsoft/hcsshim/internal/cow/cowtest"
+	"github.com/Microsoft/hcsshim/internal/hcs"
+	"github.com/containerd/containerd/runtime/v2/task"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+func Test_retryTransient_SucceedsAfterTransient(t *testing.T) {
+	calls := 0
+	err := retryTransient(context.TODO(), logrus.NewEntry(logrus.StandardLogger()), t.Name(), func() error {
+		calls++
+		if calls < 3 {
+			return errors.Wrap(&hcs.HcsError{Op: t.Name(), Err: hcs.ErrRPCServerTooBusy}, "create")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got: %d", calls)
+	}
+}
+
+func Test_retryTransient_NotTransient(t *testing.T) {
+	calls := 0
+	expected := errors.New("permanent")
+	err := retryTransient(context.TODO(), logrus.NewEntry(logrus.StandardLogger()), t.Name(), func() error {
+		calls++
+		return expected
+	})
+	if err != expected {
+		t.Fatalf("expected permanent error, got: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got: %d", calls)
+	}
+}
+
+func Test_retryTransient_ContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := 0
+	err := retryTransient(ctx, logrus.NewEntry(logrus.StandardLogger()), t.Name(), func() error {
+		calls++
+		return hcs.ErrRPCServerUnavailable
+	})
+	if err != hcs.ErrRPCServerUnavailable {
+		t.Fatalf("expected ErrRPCServerUnavailable, got: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got: %d", calls)
+	}
+}
+
+// testUpstreamConn is an upstream stdio connection that counts the open
+// connections in `open`.
+type testUpstreamConn struct {
+	open      *int32
+	closeOnce sync.Once
+}
+
+func (c *testUpstreamConn) Read(b []byte) (int, error) {
+	return 0, io.EOF
+}
+
+func (c *testUpstreamConn) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (c *testUpstreamConn) Close() error {
+	c.closeOnce.Do(func() { atomic.AddInt32(c.open, -1) })
+	return nil
+}
+
+func Test_retryTransient_CreateTask_ReleasesUpstreamIO(t *testing.T) {
+	var open int32
+	upstreamTransports[t.Name()] = func(address string) (io.ReadWriteCloser, error) {
+		atomic.AddInt32(&open, 1)
+		return &testUpstreamConn{open: &open}, nil
+	}
+	defer delete(upstreamTransports, t.Name())
+
+	// Every create of the container fails, the first two with a transient
+	// error that is retried.
+	attempts := 0
+	b := cowtest.NewBackend(t.Name(), "windows")
+	b.OnCreateComputeSystem = func(b *cowtest.Backend, id string, document interface{}) (cow.ComputeSystem, error) {
+		attempts++
+		if n := atomic.LoadInt32(&open); n != 1 {
+			t.Errorf("attempt %d: expected only the upstream IO of this attempt to be open, got: %d", attempts, n)
+		}
+		if attempts < 3 {
+			return nil, &hcs.HcsError{Op: t.Name(), Err: hcs.ErrRPCServerTooBusy}
+		}
+		return nil, errors.New("permanent")
+	}
+	backend.Register(b)
+
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	layer, scratch := filepath.Join(dir, "layer"), filepath.Join(dir, "scratch")
+	for _, d := range []string{layer, scratch} {
+		if err := os.Mkdir(d, 0700); err != nil {
+			t.Fatalf("failed to create layer folder: %v", err)
+		}
+	}
+	// An existing sandbox.vhdx and root volume skip creating and mounting the
+	// container storage.
+	if err := ioutil.WriteFile(filepath.Join(scratch, "sandbox.vhdx"), nil, 0600); err != nil {
+		t.Fatalf("failed to create sandbox.vhdx: %v", err)
+	}
+	s := &specs.Spec{
+		Annotations: map[string]string{
+			"io.microsoft.virtualmachine.backend": t.Name(),
+		},
+		Root: &specs.Root{
+			Path: `\\?\Volume{0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0}\`,
+		},
+		Windows: &specs.Windows{
+			LayerFolders: []string{layer, scratch},
+		},
+	}
+	req := &task.CreateTaskRequest{
+		ID:     t.Name(),
+		Bundle: dir,
+		Stdout: t.Name() + "://stdout",
+	}
+
+	err = retryTransient(context.TODO(), logrus.NewEntry(logrus.StandardLogger()), t.Name(), func() error {
+		_, err := newHcsStandaloneTask(context.TODO(), fakePublisher, req, s)
+		return err
+	})
+	if err == nil || err.Error() != "permanent" {
+		t.Fatalf("expected permanent error, got: %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got: %d", attempts)
+	}
+	if n := atomic.LoadInt32(&open); n != 0 {
+		t.Fatalf("expected the upstream IO to be released, got %d open", n)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/Microsoft/hcsshim/internal/lcow"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+)
+
+// podScratchPool keeps scratch disks formatted by the utility VM of an LCOW
+// pod ready for the workload tasks of the pod.
+type podScratchPool struct {
+	dir  string
+	opts *lcow.FormatOptions
+	p    *lcow.ScratchPool
+}
+
+// newPodScratchPool returns the scratch pool in the shim config of the pod
+// hosted by the LCOW utility VM `vm` and starts filling it. Returns nil if no
+// pool is configured. Failures are logged rather than returned so that the
+// pod is created without a pool.
+func newPodScratchPool(vm *uvm.UtilityVM) *podScratchPool {
+	c := getConfig().ScratchPool
+	if c == nil || vm == nil || vm.OS() != "linux" {
+		return nil
+	}
+	// The disks are formatted by `vm` and discarded with it, so the directory
+	// is not shared with other pods.
+	dir := filepath.Join(c.Directory, vm.ID())
+	log := logrus.WithFields(logrus.Fields{
+		"uvm-id": vm.ID(),
+		"dir":    dir,
+	})
+	p, err := lcow.NewScratchPool(vm, dir, c.Count)
+	if err != nil {
+		log.WithError(err).Warning("newPodScratchPool - failed to create scratch pool")
+		os.RemoveAll(dir)
+		return nil
+	}
+	sp := &podScratchPool{
+		dir:  dir,
+		opts: &lcow.FormatOptions{Filesystem: lcow.Filesystem(vm.ScratchFilesystem())},
+		p:    p,
+	}
+	if err := p.Prime(lcow.DefaultScratchSizeGB, sp.opts); err != nil {
+		log.WithError(err).Warning("newPodScratchPool - failed to prime scratch pool")
+		sp.close()
+		return nil
+	}
+	return sp
+}
+
+// createScratch creates the `sandbox.vhdx` of the rootfs of the LCOW task `s`
+// from the pool if it does not have one. It is a no-op on a nil pool.
+func (sp *podScratchPool) createScratch(s *specs.Spec) error {
+	if sp == nil || s.Windows == nil || len(s.Windows.LayerFolders) == 0 {
+		return nil
+	}
+	scratch := filepath.Join(s.Windows.LayerFolders[len(s.Windows.LayerFolders)-1], "sandbox.vhdx")
+	if _, err := os.Stat(scratch); !os.IsNotExist(err) {
+		return nil
+	}
+	return sp.p.CreateScratch(scratch, lcow.DefaultScratchSizeGB, sp.opts)
+}
+
+// close stops filling the pool and removes the disks left ready in it. It is
+// a no-op on a nil pool.
+func (sp *podScratchPool) close() {
+	if sp == nil {
+		return
+	}
+	sp.p.Close()
+	if err := os.RemoveAll(sp.dir); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"dir":           sp.dir,
+			logrus.ErrorKey: err,
+		}).Warning("podScratchPool::close - failed to remove scratch pool")
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func Test_podScratchPool_Nil(t *testing.T) {
+	var sp *podScratchPool
+	s := &specs.Spec{Windows: &specs.Windows{LayerFolders: []string{t.Name()}}}
+	if err := sp.createScratch(s); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	sp.close()
+}
+
+func Test_podScratchPool_ExistingScratch(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "sandbox.vhdx"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	// The pool is not used, so it is not needed.
+	sp := &podScratchPool{}
+	s := &specs.Spec{Windows: &specs.Windows{LayerFolders: []string{"c:\\layer", dir}}}
+	if err := sp.createScratch(s); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+}
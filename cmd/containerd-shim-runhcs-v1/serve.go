@@ -57,7 +57,21 @@ var serveCommand = cli.Command{
 		// the upstream caller by listening for a log connection and streaming
 		// the events.
 
+		// The shim options containerd passed to `shim start` are forwarded on
+		// stdin. They are needed before the pipe is served.
+		shimOpts, err := readStartOptions(os.Stdin)
 		os.Stdin.Close()
+		if err != nil {
+			return err
+		}
+		var sddl string
+		if shimOpts != nil {
+			sddl = shimOpts.PipeSecurityDescriptor
+		}
+		pipeConfig, err := servePipeConfig(sddl)
+		if err != nil {
+			return err
+		}
 
 		// Force the cli.ErrWriter to be os.Stdout for this. We use stderr for
 		// the panic.log attached via start.
@@ -101,11 +115,29 @@ var serveCommand = cli.Command{
 			logrus.SetOutput(a)
 		}()
 
+		// Setup the event publisher. The shim is served from the bundle
+		// directory so the journal is stored there. Deliver any events a
+		// previous instance of the shim failed to publish.
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		jp := newJournaledPublisher(publishContainerd, filepath.Join(cwd, eventJournalFile))
+		if err := jp.Replay(); err != nil {
+			logrus.WithError(err).Warning("containerd-shim: failed to replay event journal")
+		}
+		// Crash reports are also stored in the bundle.
+		crashReportPath = filepath.Join(cwd, crashReportFile)
+
+		// Setup the event to reload the shim config file
+		setupReloadConfig()
+
 		// Setup the ttrpc server
 		svc := &service{
-			events:    publishEvent,
-			tid:       idFlag,
-			isSandbox: ctx.Bool("is-sandbox"),
+			events:                 jp.Publish,
+			tid:                    idFlag,
+			isSandbox:              ctx.Bool("is-sandbox"),
+			pipeSecurityDescriptor: sddl,
 		}
 		s, err := ttrpc.NewServer()
 		if err != nil {
@@ -115,7 +147,7 @@ var serveCommand = cli.Command{
 		task.RegisterTaskService(s, svc)
 		shimdiag.RegisterShimDiagService(s, svc)
 
-		sl, err := winio.ListenPipe(socket, nil)
+		sl, err := winio.ListenPipe(socket, pipeConfig)
 		if err != nil {
 			return err
 		}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"runtime"
 	"sync"
 	"sync/atomic"
 
@@ -50,6 +49,12 @@ type service struct {
 	//
 	// This MUST be treated as readonly for the lifetime of the shim.
 	isSandbox bool
+	// pipeSecurityDescriptor is the SDDL security descriptor the named pipe
+	// the shim serves the ttrpc API on was created with. It is "" if the pipe
+	// has the default security descriptor.
+	//
+	// This MUST be treated as readonly for the lifetime of the shim.
+	pipeSecurityDescriptor string
 
 	// taskOrPod is either the `pod` this shim is tracking if `isSandbox ==
 	// true` or it is the `task` this shim is tracking. If no call to `Create`
@@ -61,11 +66,15 @@ type service struct {
 	// taken when creating tasks in a POD sandbox as they can happen
 	// concurrently.
 	cl sync.Mutex
+
+	// bundleSpecs maps the task id to the `*bundleSpec` read at create time
+	// so that the bundle can be verified as unmodified at start time.
+	bundleSpecs sync.Map
 }
 
 func (s *service) State(ctx context.Context, req *task.StateRequest) (resp *task.StateResponse, err error) {
-	defer panicRecover()
 	const activity = "State"
+	defer panicRecover(activity)
 	af := logrus.Fields{
 		"tid": req.ID,
 		"eid": req.ExecID,
@@ -83,8 +92,8 @@ func (s *service) State(ctx context.Context, req *task.StateRequest) (resp *task
 }
 
 func (s *service) Create(ctx context.Context, req *task.CreateTaskRequest) (resp *task.CreateTaskResponse, err error) {
-	defer panicRecover()
 	const activity = "Create"
+	defer panicRecover(activity)
 	log := beginActivity(activity, logrus.Fields{
 		"tid":              req.ID,
 		"bundle":           req.Bundle,
@@ -106,8 +115,8 @@ func (s *service) Create(ctx context.Context, req *task.CreateTaskRequest) (resp
 }
 
 func (s *service) Start(ctx context.Context, req *task.StartRequest) (resp *task.StartResponse, err error) {
-	defer panicRecover()
 	const activity = "Start"
+	defer panicRecover(activity)
 	af := logrus.Fields{
 		"tid": req.ID,
 		"eid": req.ExecID,
@@ -123,8 +132,8 @@ func (s *service) Start(ctx context.Context, req *task.StartRequest) (resp *task
 }
 
 func (s *service) Delete(ctx context.Context, req *task.DeleteRequest) (resp *task.DeleteResponse, err error) {
-	defer panicRecover()
 	const activity = "Delete"
+	defer panicRecover(activity)
 	af := logrus.Fields{
 		"tid": req.ID,
 		"eid": req.ExecID,
@@ -142,8 +151,8 @@ func (s *service) Delete(ctx context.Context, req *task.DeleteRequest) (resp *ta
 }
 
 func (s *service) Pids(ctx context.Context, req *task.PidsRequest) (_ *task.PidsResponse, err error) {
-	defer panicRecover()
 	const activity = "Pids"
+	defer panicRecover(activity)
 	af := logrus.Fields{
 		"tid": req.ID,
 	}
@@ -155,8 +164,8 @@ func (s *service) Pids(ctx context.Context, req *task.PidsRequest) (_ *task.Pids
 }
 
 func (s *service) Pause(ctx context.Context, req *task.PauseRequest) (_ *google_protobuf1.Empty, err error) {
-	defer panicRecover()
 	const activity = "Pause"
+	defer panicRecover(activity)
 	af := logrus.Fields{
 		"tid": req.ID,
 	}
@@ -168,8 +177,8 @@ func (s *service) Pause(ctx context.Context, req *task.PauseRequest) (_ *google_
 }
 
 func (s *service) Resume(ctx context.Context, req *task.ResumeRequest) (_ *google_protobuf1.Empty, err error) {
-	defer panicRecover()
 	const activity = "Resume"
+	defer panicRecover(activity)
 	af := logrus.Fields{
 		"tid": req.ID,
 	}
@@ -181,8 +190,8 @@ func (s *service) Resume(ctx context.Context, req *task.ResumeRequest) (_ *googl
 }
 
 func (s *service) Checkpoint(ctx context.Context, req *task.CheckpointTaskRequest) (_ *google_protobuf1.Empty, err error) {
-	defer panicRecover()
 	const activity = "Checkpoint"
+	defer panicRecover(activity)
 	af := logrus.Fields{
 		"tid":  req.ID,
 		"path": req.Path,
@@ -195,8 +204,8 @@ func (s *service) Checkpoint(ctx context.Context, req *task.CheckpointTaskReques
 }
 
 func (s *service) Kill(ctx context.Context, req *task.KillRequest) (_ *google_protobuf1.Empty, err error) {
-	defer panicRecover()
 	const activity = "Kill"
+	defer panicRecover(activity)
 	af := logrus.Fields{
 		"tid":    req.ID,
 		"eid":    req.ExecID,
@@ -211,8 +220,8 @@ func (s *service) Kill(ctx context.Context, req *task.KillRequest) (_ *google_pr
 }
 
 func (s *service) Exec(ctx context.Context, req *task.ExecProcessRequest) (_ *google_protobuf1.Empty, err error) {
-	defer panicRecover()
 	const activity = "Exec"
+	defer panicRecover(activity)
 	af := logrus.Fields{
 		"tid":      req.ID,
 		"eid":      req.ExecID,
@@ -229,8 +238,8 @@ func (s *service) Exec(ctx context.Context, req *task.ExecProcessRequest) (_ *go
 }
 
 func (s *service) DiagExecInHost(ctx context.Context, req *shimdiag.ExecProcessRequest) (_ *shimdiag.ExecProcessResponse, err error) {
-	defer panicRecover()
 	const activity = "DiagExecInHost"
+	defer panicRecover(activity)
 	af := logrus.Fields{
 		"args":     req.Args,
 		"workdir":  req.Workdir,
@@ -246,9 +255,162 @@ func (s *service) DiagExecInHost(ctx context.Context, req *shimdiag.ExecProcessR
 	return r, errdefs.ToGRPC(e)
 }
 
+func (s *service) DiagConsole(ctx context.Context, req *shimdiag.ConsoleRequest) (_ *shimdiag.ConsoleResponse, err error) {
+	const activity = "DiagConsole"
+	defer panicRecover(activity)
+	af := logrus.Fields{
+		"stdin":  req.Stdin,
+		"stdout": req.Stdout,
+	}
+	log := beginActivity(activity, af)
+	defer func() { endActivity(log, activity, err) }()
+
+	r, e := s.diagConsoleInternal(ctx, req)
+	return r, errdefs.ToGRPC(e)
+}
+
+func (s *service) DiagTaskState(ctx context.Context, req *shimdiag.TaskStateRequest) (_ *shimdiag.TaskStateResponse, err error) {
+	const activity = "DiagTaskState"
+	defer panicRecover(activity)
+	af := logrus.Fields{
+		"tid": req.TaskID,
+		"eid": req.ExecID,
+	}
+	log := beginActivity(activity, af)
+	defer func() { endActivity(log, activity, err) }()
+
+	r, e := s.diagTaskStateInternal(ctx, req)
+	return r, errdefs.ToGRPC(e)
+}
+
+func (s *service) DiagTasks(ctx context.Context, req *shimdiag.TasksRequest) (_ *shimdiag.TasksResponse, err error) {
+	const activity = "DiagTasks"
+	defer panicRecover(activity)
+	af := logrus.Fields{}
+	log := beginActivity(activity, af)
+	defer func() { endActivity(log, activity, err) }()
+
+	r, e := s.diagTasksInternal(ctx, req)
+	return r, errdefs.ToGRPC(e)
+}
+
+func (s *service) DiagShare(ctx context.Context, req *shimdiag.ShareRequest) (_ *shimdiag.ShareResponse, err error) {
+	const activity = "DiagShare"
+	defer panicRecover(activity)
+	af := logrus.Fields{
+		"hostPath": req.HostPath,
+		"uvmPath":  req.UvmPath,
+		"readOnly": req.ReadOnly,
+	}
+	log := beginActivity(activity, af)
+	defer func() { endActivity(log, activity, err) }()
+
+	r, e := s.diagShareInternal(ctx, req)
+	return r, errdefs.ToGRPC(e)
+}
+
+func (s *service) DiagPprof(ctx context.Context, req *shimdiag.PprofRequest) (_ *shimdiag.PprofResponse, err error) {
+	const activity = "DiagPprof"
+	defer panicRecover(activity)
+	af := logrus.Fields{
+		"profile":         req.Profile,
+		"durationSeconds": req.DurationSeconds,
+	}
+	log := beginActivity(activity, af)
+	defer func() { endActivity(log, activity, err) }()
+
+	r, e := s.diagPprofInternal(ctx, req)
+	return r, errdefs.ToGRPC(e)
+}
+
+func (s *service) DiagEndpoint(ctx context.Context, req *shimdiag.EndpointRequest) (_ *shimdiag.EndpointResponse, err error) {
+	const activity = "DiagEndpoint"
+	defer panicRecover(activity)
+	af := logrus.Fields{
+		"tid":         req.TaskID,
+		"endpoint-id": req.EndpointID,
+		"remove":      req.Remove,
+	}
+	log := beginActivity(activity, af)
+	defer func() { endActivity(log, activity, err) }()
+
+	r, e := s.diagEndpointInternal(ctx, req)
+	return r, errdefs.ToGRPC(e)
+}
+
+func (s *service) DiagCPUGroup(ctx context.Context, req *shimdiag.CPUGroupRequest) (_ *shimdiag.CPUGroupResponse, err error) {
+	const activity = "DiagCPUGroup"
+	defer panicRecover(activity)
+	af := logrus.Fields{
+		"tid":        req.TaskID,
+		"cpuGroupID": req.CPUGroupID,
+	}
+	log := beginActivity(activity, af)
+	defer func() { endActivity(log, activity, err) }()
+
+	r, e := s.diagCPUGroupInternal(ctx, req)
+	return r, errdefs.ToGRPC(e)
+}
+
+func (s *service) DiagSaveUVM(ctx context.Context, req *shimdiag.SaveUVMRequest) (_ *shimdiag.SaveUVMResponse, err error) {
+	const activity = "DiagSaveUVM"
+	defer panicRecover(activity)
+	af := logrus.Fields{
+		"tid":  req.TaskID,
+		"path": req.Path,
+	}
+	log := beginActivity(activity, af)
+	defer func() { endActivity(log, activity, err) }()
+
+	r, e := s.diagSaveUVMInternal(ctx, req)
+	return r, errdefs.ToGRPC(e)
+}
+
+func (s *service) DiagExportRootfs(ctx context.Context, req *shimdiag.ExportRootfsRequest) (_ *shimdiag.ExportRootfsResponse, err error) {
+	const activity = "DiagExportRootfs"
+	defer panicRecover(activity)
+	af := logrus.Fields{
+		"tid":    req.TaskID,
+		"stdout": req.Stdout,
+	}
+	log := beginActivity(activity, af)
+	defer func() { endActivity(log, activity, err) }()
+
+	r, e := s.diagExportRootfsInternal(ctx, req)
+	return r, errdefs.ToGRPC(e)
+}
+
+func (s *service) DiagReloadConfig(ctx context.Context, req *shimdiag.ReloadConfigRequest) (_ *shimdiag.ReloadConfigResponse, err error) {
+	const activity = "DiagReloadConfig"
+	defer panicRecover(activity)
+	af := logrus.Fields{}
+	log := beginActivity(activity, af)
+	defer func() { endActivity(log, activity, err) }()
+
+	path, e := reloadConfig()
+	if e != nil {
+		return nil, errdefs.ToGRPC(e)
+	}
+	return &shimdiag.ReloadConfigResponse{ConfigPath: path}, nil
+}
+
+func (s *service) DiagLastCrash(ctx context.Context, req *shimdiag.LastCrashRequest) (_ *shimdiag.LastCrashResponse, err error) {
+	const activity = "DiagLastCrash"
+	defer panicRecover(activity)
+	af := logrus.Fields{}
+	log := beginActivity(activity, af)
+	defer func() { endActivity(log, activity, err) }()
+
+	path, report, e := readCrashReport()
+	if e != nil {
+		return nil, errdefs.ToGRPC(e)
+	}
+	return &shimdiag.LastCrashResponse{Path: path, Report: report}, nil
+}
+
 func (s *service) ResizePty(ctx context.Context, req *task.ResizePtyRequest) (_ *google_protobuf1.Empty, err error) {
-	defer panicRecover()
 	const activity = "ResizePty"
+	defer panicRecover(activity)
 	af := logrus.Fields{
 		"tid":    req.ID,
 		"eid":    req.ExecID,
@@ -263,8 +425,8 @@ func (s *service) ResizePty(ctx context.Context, req *task.ResizePtyRequest) (_
 }
 
 func (s *service) CloseIO(ctx context.Context, req *task.CloseIORequest) (_ *google_protobuf1.Empty, err error) {
-	defer panicRecover()
 	const activity = "CloseIO"
+	defer panicRecover(activity)
 	af := logrus.Fields{
 		"tid":   req.ID,
 		"eid":   req.ExecID,
@@ -278,8 +440,8 @@ func (s *service) CloseIO(ctx context.Context, req *task.CloseIORequest) (_ *goo
 }
 
 func (s *service) Update(ctx context.Context, req *task.UpdateTaskRequest) (_ *google_protobuf1.Empty, err error) {
-	defer panicRecover()
 	const activity = "Update"
+	defer panicRecover(activity)
 	af := logrus.Fields{
 		"tid": req.ID,
 	}
@@ -291,8 +453,8 @@ func (s *service) Update(ctx context.Context, req *task.UpdateTaskRequest) (_ *g
 }
 
 func (s *service) Wait(ctx context.Context, req *task.WaitRequest) (resp *task.WaitResponse, err error) {
-	defer panicRecover()
 	const activity = "Wait"
+	defer panicRecover(activity)
 	af := logrus.Fields{
 		"tid": req.ID,
 		"eid": req.ExecID,
@@ -309,8 +471,8 @@ func (s *service) Wait(ctx context.Context, req *task.WaitRequest) (resp *task.W
 }
 
 func (s *service) Stats(ctx context.Context, req *task.StatsRequest) (_ *task.StatsResponse, err error) {
-	defer panicRecover()
 	const activity = "Stats"
+	defer panicRecover(activity)
 	af := logrus.Fields{
 		"tid": req.ID,
 	}
@@ -322,8 +484,8 @@ func (s *service) Stats(ctx context.Context, req *task.StatsRequest) (_ *task.St
 }
 
 func (s *service) Connect(ctx context.Context, req *task.ConnectRequest) (resp *task.ConnectResponse, err error) {
-	defer panicRecover()
 	const activity = "Connect"
+	defer panicRecover(activity)
 	af := logrus.Fields{
 		"tid": req.ID,
 	}
@@ -340,8 +502,8 @@ func (s *service) Connect(ctx context.Context, req *task.ConnectRequest) (resp *
 }
 
 func (s *service) Shutdown(ctx context.Context, req *task.ShutdownRequest) (_ *google_protobuf1.Empty, err error) {
-	defer panicRecover()
 	const activity = "Shutdown"
+	defer panicRecover(activity)
 	af := logrus.Fields{
 		"tid": req.ID,
 		"now": req.Now,
@@ -354,19 +516,12 @@ func (s *service) Shutdown(ctx context.Context, req *task.ShutdownRequest) (_ *g
 }
 
 func (s *service) DiagStacks(ctx context.Context, req *shimdiag.StacksRequest) (_ *shimdiag.StacksResponse, err error) {
-	defer panicRecover()
 	const activity = "DiagStacks"
+	defer panicRecover(activity)
 	af := logrus.Fields{}
 	log := beginActivity(activity, af)
 	defer func() { endActivity(log, activity, err) }()
 
-	buf := make([]byte, 4096)
-	for {
-		buf = buf[:runtime.Stack(buf, true)]
-		if len(buf) < cap(buf) {
-			break
-		}
-		buf = make([]byte, 2*len(buf))
-	}
-	return &shimdiag.StacksResponse{Stacks: string(buf)}, nil
+	r, e := s.diagStacksInternal(ctx, req)
+	return r, errdefs.ToGRPC(e)
 }
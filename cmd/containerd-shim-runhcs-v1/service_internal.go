@@ -4,12 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"os"
-	"path/filepath"
+	"runtime"
 	"strings"
+	"syscall"
+	"time"
 
 	runhcsopts "github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/options"
 	"github.com/Microsoft/hcsshim/internal/oci"
 	"github.com/Microsoft/hcsshim/internal/shimdiag"
+	"github.com/Microsoft/hcsshim/osversion"
 	containerd_v1_types "github.com/containerd/containerd/api/types/task"
 	"github.com/containerd/containerd/errdefs"
 	"github.com/containerd/containerd/mount"
@@ -66,7 +69,12 @@ func (s *service) stateInternal(ctx context.Context, req *task.StateRequest) (*t
 	if err != nil {
 		return nil, err
 	}
-	return e.Status(), nil
+	resp := e.Status()
+	// A paused task suspends all of its execs.
+	if resp.Status == containerd_v1_types.StatusRunning && t.Paused() {
+		resp.Status = containerd_v1_types.StatusPaused
+	}
+	return resp, nil
 }
 
 func (s *service) createInternal(ctx context.Context, req *task.CreateTaskRequest) (*task.CreateTaskResponse, error) {
@@ -84,17 +92,28 @@ func (s *service) createInternal(ctx context.Context, req *task.CreateTaskReques
 	if shimOpts != nil && shimOpts.Debug {
 		logrus.SetLevel(logrus.DebugLevel)
 	}
+	if shimOpts != nil &&
+		shimOpts.DebugType == runhcsopts.Options_FILE &&
+		shimOpts.LogFilePath != "" {
+		if err := setLogFile(shimOpts.LogFilePath); err != nil {
+			return nil, err
+		}
+	}
+	if shimOpts != nil && shimOpts.ConfigPath != "" {
+		if err := setConfigPath(shimOpts.ConfigPath); err != nil {
+			return nil, err
+		}
+	}
+	if shimOpts != nil {
+		if err := checkPipeSecurityDescriptor(s.pipeSecurityDescriptor, shimOpts.PipeSecurityDescriptor); err != nil {
+			return nil, err
+		}
+	}
 
-	var spec specs.Spec
-	f, err := os.Open(filepath.Join(req.Bundle, "config.json"))
+	spec, bs, err := readBundleSpec(req.Bundle)
 	if err != nil {
 		return nil, err
 	}
-	if err := json.NewDecoder(f).Decode(&spec); err != nil {
-		f.Close()
-		return nil, err
-	}
-	f.Close()
 
 	spec = oci.UpdateSpecFromOptions(spec, shimOpts)
 
@@ -153,6 +172,32 @@ func (s *service) createInternal(ctx context.Context, req *task.CreateTaskReques
 		return nil, errors.Wrap(errdefs.ErrFailedPrecondition, "if using terminal, stderr must be empty")
 	}
 
+	// If this shim is not tracking `req.ID` any compute system with the same
+	// id was left behind by a prior shim that crashed, or is owned by another
+	// shim that is still running. In the first case a standalone process
+	// isolated task is reattached and any other compute system is reaped,
+	// otherwise the create fails with AlreadyExists.
+	if _, err := s.getTask(req.ID); errdefs.IsNotFound(err) {
+		if !s.isSandbox {
+			t, err := reattachHcsTask(ctx, s.events, req, &spec)
+			if err != nil {
+				return nil, err
+			}
+			if t != nil {
+				e, _ := t.GetExec("")
+				s.cl.Lock()
+				s.taskOrPod.Store(t)
+				s.cl.Unlock()
+				s.bundleSpecs.Store(req.ID, bs)
+				return &task.CreateTaskResponse{Pid: uint32(e.Pid())}, nil
+			}
+		}
+		if err := reapStaleComputeSystems(ctx, req.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	log := logrus.WithField("tid", req.ID)
 	resp := &task.CreateTaskResponse{}
 	s.cl.Lock()
 	if s.isSandbox {
@@ -160,37 +205,73 @@ func (s *service) createInternal(ctx context.Context, req *task.CreateTaskReques
 		if err == nil {
 			// The POD sandbox was previously created. Unlock and forward to the POD
 			s.cl.Unlock()
-			t, err := pod.CreateTask(ctx, req, &spec)
+			var t shimTask
+			err := retryTransient(ctx, log, "createInternal::CreateTask", func() (err error) {
+				t, err = pod.CreateTask(ctx, req, &spec)
+				return err
+			})
 			if err != nil {
 				return nil, err
 			}
 			e, _ := t.GetExec("")
 			resp.Pid = uint32(e.Pid())
+			s.bundleSpecs.Store(req.ID, bs)
 			return resp, nil
 		}
-		pod, err = createPod(ctx, s.events, req, &spec)
+		err = retryTransient(ctx, log, "createInternal::createPod", func() (err error) {
+			pod, err = createPod(ctx, s.events, req, &spec)
+			return err
+		})
 		if err != nil {
 			s.cl.Unlock()
 			return nil, err
 		}
 		t, _ := pod.GetTask(req.ID)
+		if err := ctx.Err(); err != nil {
+			s.cl.Unlock()
+			abandonTask(t)
+			return nil, errors.Wrapf(err, "pod: '%s' create cancelled", req.ID)
+		}
+		bs.uvmScratch = uvmScratchPath(&spec)
 		e, _ := t.GetExec("")
 		resp.Pid = uint32(e.Pid())
 		s.taskOrPod.Store(pod)
 	} else {
-		t, err := newHcsStandaloneTask(ctx, s.events, req, &spec)
+		var t shimTask
+		err := retryTransient(ctx, log, "createInternal::newHcsStandaloneTask", func() (err error) {
+			t, err = newHcsStandaloneTask(ctx, s.events, req, &spec)
+			return err
+		})
 		if err != nil {
 			s.cl.Unlock()
 			return nil, err
 		}
+		if err := ctx.Err(); err != nil {
+			s.cl.Unlock()
+			abandonTask(t)
+			return nil, errors.Wrapf(err, "task: '%s' create cancelled", req.ID)
+		}
+		bs.uvmScratch = uvmScratchPath(&spec)
 		e, _ := t.GetExec("")
 		resp.Pid = uint32(e.Pid())
 		s.taskOrPod.Store(t)
 	}
 	s.cl.Unlock()
+	s.bundleSpecs.Store(req.ID, bs)
 	return resp, nil
 }
 
+// abandonTask tears down the created task `t` of a caller that has given up
+// waiting for the create. Killing the init exec in the created state releases
+// the container and any UVM owned by `t`. Returns once the teardown completes.
+func abandonTask(t shimTask) {
+	logrus.WithField("tid", t.ID()).Warning("abandoning task, create was cancelled")
+	if e, err := t.GetExec(""); err == nil {
+		e.Kill(context.Background(), uint32(syscall.SIGKILL))
+	}
+	t.Wait(context.Background())
+}
+
 func (s *service) startInternal(ctx context.Context, req *task.StartRequest) (*task.StartResponse, error) {
 	t, err := s.getTask(req.ID)
 	if err != nil {
@@ -200,6 +281,18 @@ func (s *service) startInternal(ctx context.Context, req *task.StartRequest) (*t
 	if err != nil {
 		return nil, err
 	}
+	if req.ExecID == "" {
+		if bs, ok := s.bundleSpecs.Load(req.ID); ok {
+			if err := bs.(*bundleSpec).verify(req.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// Once started the exec is not rolled back so don't start it if the
+	// caller has already given up.
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrapf(err, "exec: '%s' in task: '%s' start cancelled", req.ExecID, req.ID)
+	}
 	err = e.Start(ctx)
 	if err != nil {
 		return nil, err
@@ -216,10 +309,21 @@ func (s *service) deleteInternal(ctx context.Context, req *task.DeleteRequest) (
 	if err != nil {
 		return nil, err
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrapf(err, "exec: '%s' in task: '%s' delete cancelled", req.ExecID, req.ID)
+	}
 	pid, exitStatus, exitedAt, err := t.DeleteExec(ctx, req.ExecID)
 	if err != nil {
 		return nil, err
 	}
+	if req.ExecID == "" {
+		if bs, ok := s.bundleSpecs.Load(req.ID); ok {
+			if path := bs.(*bundleSpec).uvmScratch; path != "" {
+				removeUVMScratch(ctx, req.ID, t, path)
+			}
+		}
+		s.bundleSpecs.Delete(req.ID)
+	}
 	// TODO: We should be removing the task after this right?
 	return &task.DeleteResponse{
 		Pid:        uint32(pid),
@@ -255,27 +359,25 @@ func (s *service) pidsInternal(ctx context.Context, req *task.PidsRequest) (*tas
 }
 
 func (s *service) pauseInternal(ctx context.Context, req *task.PauseRequest) (*google_protobuf1.Empty, error) {
-	/*
-		s.events <- cdevent{
-			topic: runtime.TaskPausedEventTopic,
-			event: &eventstypes.TaskPaused{
-				req.ID,
-			},
-		}
-	*/
-	return nil, errdefs.ErrNotImplemented
+	t, err := s.getTask(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Pause(ctx); err != nil {
+		return nil, err
+	}
+	return empty, nil
 }
 
 func (s *service) resumeInternal(ctx context.Context, req *task.ResumeRequest) (*google_protobuf1.Empty, error) {
-	/*
-		s.events <- cdevent{
-			topic: runtime.TaskResumedEventTopic,
-			event: &eventstypes.TaskResumed{
-				req.ID,
-			},
-		}
-	*/
-	return nil, errdefs.ErrNotImplemented
+	t, err := s.getTask(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Resume(ctx); err != nil {
+		return nil, err
+	}
+	return empty, nil
 }
 
 func (s *service) checkpointInternal(ctx context.Context, req *task.CheckpointTaskRequest) (*google_protobuf1.Empty, error) {
@@ -341,6 +443,173 @@ func (s *service) diagExecInHostInternal(ctx context.Context, req *shimdiag.Exec
 	return &shimdiag.ExecProcessResponse{ExitCode: int32(ec)}, nil
 }
 
+func (s *service) diagStacksInternal(ctx context.Context, req *shimdiag.StacksRequest) (*shimdiag.StacksResponse, error) {
+	buf := make([]byte, 4096)
+	for {
+		buf = buf[:runtime.Stack(buf, true)]
+		if len(buf) < cap(buf) {
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	resp := &shimdiag.StacksResponse{Stacks: string(buf)}
+
+	// The guest stacks are best effort. The host stacks are still useful if
+	// there is no task yet or the guest does not respond.
+	t, err := s.getTask(s.tid)
+	if err != nil {
+		return resp, nil
+	}
+	stacks, err := t.DumpGuestStacks(ctx)
+	if err != nil {
+		if err != errTaskNotIsolated {
+			logrus.WithError(err).WithField("tid", s.tid).Warning("failed to dump guest stacks")
+		}
+		return resp, nil
+	}
+	resp.GuestStacks = stacks
+	return resp, nil
+}
+
+func (s *service) diagPprofInternal(ctx context.Context, req *shimdiag.PprofRequest) (*shimdiag.PprofResponse, error) {
+	b, err := collectProfile(ctx, req.Profile, time.Duration(req.DurationSeconds)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	return &shimdiag.PprofResponse{Profile: b}, nil
+}
+
+func (s *service) diagShareInternal(ctx context.Context, req *shimdiag.ShareRequest) (*shimdiag.ShareResponse, error) {
+	if req.HostPath == "" || req.UvmPath == "" {
+		return nil, errors.Wrap(errdefs.ErrInvalidArgument, "host path and uvm path must be set to share")
+	}
+	t, err := s.getTask(s.tid)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Share(ctx, req); err != nil {
+		return nil, err
+	}
+	return &shimdiag.ShareResponse{}, nil
+}
+
+func (s *service) diagEndpointInternal(ctx context.Context, req *shimdiag.EndpointRequest) (*shimdiag.EndpointResponse, error) {
+	if req.EndpointID == "" {
+		return nil, errors.Wrap(errdefs.ErrInvalidArgument, "endpoint id must be set")
+	}
+	tid := req.TaskID
+	if tid == "" {
+		tid = s.tid
+	}
+	t, err := s.getTask(tid)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.UpdateEndpoint(ctx, req); err != nil {
+		return nil, err
+	}
+	return &shimdiag.EndpointResponse{}, nil
+}
+
+func (s *service) diagCPUGroupInternal(ctx context.Context, req *shimdiag.CPUGroupRequest) (*shimdiag.CPUGroupResponse, error) {
+	if req.CPUGroupID == "" {
+		return nil, errors.Wrap(errdefs.ErrInvalidArgument, "cpu group id must be set")
+	}
+	tid := req.TaskID
+	if tid == "" {
+		tid = s.tid
+	}
+	t, err := s.getTask(tid)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.SetCPUGroup(ctx, req.CPUGroupID); err != nil {
+		return nil, err
+	}
+	return &shimdiag.CPUGroupResponse{}, nil
+}
+
+func (s *service) diagSaveUVMInternal(ctx context.Context, req *shimdiag.SaveUVMRequest) (*shimdiag.SaveUVMResponse, error) {
+	if req.Path == "" {
+		return nil, errors.Wrap(errdefs.ErrInvalidArgument, "path must be set to save the UVM")
+	}
+	tid := req.TaskID
+	if tid == "" {
+		tid = s.tid
+	}
+	t, err := s.getTask(tid)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.SaveUVM(ctx, req.Path); err != nil {
+		return nil, err
+	}
+	return &shimdiag.SaveUVMResponse{}, nil
+}
+
+func (s *service) diagExportRootfsInternal(ctx context.Context, req *shimdiag.ExportRootfsRequest) (*shimdiag.ExportRootfsResponse, error) {
+	if req.Stdout == "" {
+		return nil, errors.Wrap(errdefs.ErrInvalidArgument, "stdout must be set to export the root file system")
+	}
+	t, err := s.getTask(req.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.ExportRootfs(ctx, req); err != nil {
+		return nil, err
+	}
+	return &shimdiag.ExportRootfsResponse{}, nil
+}
+
+func (s *service) diagConsoleInternal(ctx context.Context, req *shimdiag.ConsoleRequest) (*shimdiag.ConsoleResponse, error) {
+	if req.Stdout == "" {
+		return nil, errors.Wrap(errdefs.ErrInvalidArgument, "stdout must be set to attach to the console")
+	}
+	t, err := s.getTask(s.tid)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.AttachHostConsole(ctx, req); err != nil {
+		return nil, err
+	}
+	return &shimdiag.ConsoleResponse{}, nil
+}
+
+func (s *service) diagTaskStateInternal(ctx context.Context, req *shimdiag.TaskStateRequest) (*shimdiag.TaskStateResponse, error) {
+	t, err := s.getTask(req.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	return t.DiagState(ctx, req.ExecID)
+}
+
+func (s *service) diagTasksInternal(ctx context.Context, req *shimdiag.TasksRequest) (*shimdiag.TasksResponse, error) {
+	var tasks []shimTask
+	switch raw := s.taskOrPod.Load().(type) {
+	case shimPod:
+		tasks = raw.ListTasks()
+	case shimTask:
+		tasks = []shimTask{raw}
+	}
+	resp := &shimdiag.TasksResponse{}
+	for _, t := range tasks {
+		for _, e := range t.ListExecs() {
+			eid := e.ID()
+			if eid == t.ID() {
+				// The init exec is addressed by the empty id.
+				eid = ""
+			}
+			state, err := t.DiagState(ctx, eid)
+			if err != nil {
+				// The exec was deleted while listing.
+				continue
+			}
+			resp.Execs = append(resp.Execs, state)
+		}
+	}
+	return resp, nil
+}
+
 func (s *service) resizePtyInternal(ctx context.Context, req *task.ResizePtyRequest) (*google_protobuf1.Empty, error) {
 	t, err := s.getTask(req.ID)
 	if err != nil {
@@ -374,7 +643,26 @@ func (s *service) closeIOInternal(ctx context.Context, req *task.CloseIORequest)
 }
 
 func (s *service) updateInternal(ctx context.Context, req *task.UpdateTaskRequest) (*google_protobuf1.Empty, error) {
-	return nil, errdefs.ErrNotImplemented
+	if req.Resources == nil {
+		return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "task: '%s' no resources in update request", req.ID)
+	}
+	t, err := s.getTask(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	v, err := typeurl.UnmarshalAny(req.Resources)
+	if err != nil {
+		return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "task: '%s' failed to unmarshal resources: %v", req.ID, err)
+	}
+	switch v.(type) {
+	case *specs.WindowsResources, *specs.LinuxResources:
+	default:
+		return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "task: '%s' unsupported resources type '%T'", req.ID, v)
+	}
+	if err := t.Update(ctx, v); err != nil {
+		return nil, err
+	}
+	return empty, nil
 }
 
 func (s *service) waitInternal(ctx context.Context, req *task.WaitRequest) (*task.WaitResponse, error) {
@@ -382,15 +670,38 @@ func (s *service) waitInternal(ctx context.Context, req *task.WaitRequest) (*tas
 	if err != nil {
 		return nil, err
 	}
-	var state *task.StateResponse
+	var (
+		wait func(context.Context) *task.StateResponse
+		e    shimExec
+	)
 	if req.ExecID != "" {
-		e, err := t.GetExec(req.ExecID)
+		e, err = t.GetExec(req.ExecID)
 		if err != nil {
 			return nil, err
 		}
-		state = e.Wait(ctx)
+		wait = e.Wait
 	} else {
-		state = t.Wait(ctx)
+		wait = t.Wait
+	}
+	// Do not block the RPC past the callers context. The exec wait itself
+	// is not cancellable.
+	waitDone := make(chan *task.StateResponse, 1)
+	go func() {
+		waitDone <- wait(ctx)
+	}()
+	var state *task.StateResponse
+	select {
+	case state = <-waitDone:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if e != nil && e.Pid() == 0 {
+		// An exec is only deleted before it exits if it was never started,
+		// in which case the wait completed because `Delete` forced it to
+		// exit and there is no exit status to report.
+		if _, err := t.GetExec(req.ExecID); err != nil {
+			return nil, err
+		}
 	}
 	return &task.WaitResponse{
 		ExitStatus: state.ExitStatus,
@@ -399,15 +710,43 @@ func (s *service) waitInternal(ctx context.Context, req *task.WaitRequest) (*tas
 }
 
 func (s *service) statsInternal(ctx context.Context, req *task.StatsRequest) (*task.StatsResponse, error) {
-	return nil, errdefs.ErrNotImplemented
+	t, err := s.getTask(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := t.Stats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a, err := typeurl.MarshalAny(stats)
+	if err != nil {
+		return nil, err
+	}
+	return &task.StatsResponse{Stats: a}, nil
 }
 
 func (s *service) connectInternal(ctx context.Context, req *task.ConnectRequest) (*task.ConnectResponse, error) {
+	// The containerd `ConnectResponse` has no extension point so the
+	// capabilities of the shim and the task are returned JSON encoded as its
+	// `Version`.
+	caps := &shimdiag.ShimCapabilities{
+		Version:   version,
+		GitCommit: gitCommit,
+		OsBuild:   uint32(osversion.Get().Build),
+	}
+	if t, err := s.getTask(req.ID); err == nil {
+		caps.Task = t.Capabilities()
+	}
+	b, err := json.Marshal(caps)
+	if err != nil {
+		return nil, err
+	}
 	// We treat the shim/task as the same pid on the Windows host.
 	pid := uint32(os.Getpid())
 	return &task.ConnectResponse{
 		ShimPid: pid,
 		TaskPid: pid,
+		Version: string(b),
 	}, nil
 }
 
@@ -421,6 +760,15 @@ func (s *service) shutdownInternal(ctx context.Context, req *task.ShutdownReques
 	if req.Now {
 		os.Exit(0)
 	}
+	if s.isSandbox {
+		// Tear down any remaining workload tasks before the sandbox and its
+		// UVM rather than leave them to exit with the shim.
+		if p, err := s.getPod(); err == nil {
+			if err := shutdownPod(ctx, p); err != nil {
+				logrus.WithError(err).Warning("shutdown: failed to tear down pod")
+			}
+		}
+	}
 	// TODO: JTERRY75 if we dont use `now` issue a Shutdown to the ttrpc
 	// connection to drain any active requests.
 	os.Exit(0)
@@ -7,9 +7,12 @@ import (
 	"testing"
 
 	"github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/options"
+	"github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/stats"
+	"github.com/Microsoft/hcsshim/internal/shimdiag"
 	"github.com/containerd/containerd/errdefs"
 	"github.com/containerd/containerd/runtime/v2/task"
 	"github.com/containerd/typeurl"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
 func setupPodServiceWithFakes(t *testing.T) (*service, *testShimTask, *testShimTask, *testShimExec) {
@@ -381,7 +384,7 @@ func Test_PodShim_pidsInternal_2ndTaskID_Success(t *testing.T) {
 	}
 }
 
-func Test_PodShim_pauseInternal_Error(t *testing.T) {
+func Test_PodShim_pauseInternal_NoTask_Error(t *testing.T) {
 	s := service{
 		tid:       t.Name(),
 		isSandbox: true,
@@ -389,10 +392,22 @@ func Test_PodShim_pauseInternal_Error(t *testing.T) {
 
 	resp, err := s.pauseInternal(context.TODO(), &task.PauseRequest{ID: t.Name()})
 
-	verifyExpectedError(t, resp, err, errdefs.ErrNotImplemented)
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
+}
+
+func Test_PodShim_pauseInternal_InitTaskID_Success(t *testing.T) {
+	s, t1, _, _ := setupPodServiceWithFakes(t)
+
+	resp, err := s.pauseInternal(context.TODO(), &task.PauseRequest{ID: t1.ID()})
+	if err != nil {
+		t.Fatalf("should not have failed with error got: %v", err)
+	}
+	if resp == nil {
+		t.Fatal("should have returned Empty")
+	}
 }
 
-func Test_PodShim_resumeInternal_Error(t *testing.T) {
+func Test_PodShim_resumeInternal_NoTask_Error(t *testing.T) {
 	s := service{
 		tid:       t.Name(),
 		isSandbox: true,
@@ -400,7 +415,19 @@ func Test_PodShim_resumeInternal_Error(t *testing.T) {
 
 	resp, err := s.resumeInternal(context.TODO(), &task.ResumeRequest{ID: t.Name()})
 
-	verifyExpectedError(t, resp, err, errdefs.ErrNotImplemented)
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
+}
+
+func Test_PodShim_resumeInternal_InitTaskID_Success(t *testing.T) {
+	s, t1, _, _ := setupPodServiceWithFakes(t)
+
+	resp, err := s.resumeInternal(context.TODO(), &task.ResumeRequest{ID: t1.ID()})
+	if err != nil {
+		t.Fatalf("should not have failed with error got: %v", err)
+	}
+	if resp == nil {
+		t.Fatal("should have returned Empty")
+	}
 }
 
 func Test_PodShim_checkpointInternal_Error(t *testing.T) {
@@ -572,7 +599,7 @@ func Test_PodShim_closeIOInternal_2ndTaskID_2ndExecID_Success(t *testing.T) {
 	}
 }
 
-func Test_PodShim_updateInternal_Error(t *testing.T) {
+func Test_PodShim_updateInternal_NoResources_Error(t *testing.T) {
 	s := service{
 		tid:       t.Name(),
 		isSandbox: true,
@@ -580,7 +607,22 @@ func Test_PodShim_updateInternal_Error(t *testing.T) {
 
 	resp, err := s.updateInternal(context.TODO(), &task.UpdateTaskRequest{ID: t.Name()})
 
-	verifyExpectedError(t, resp, err, errdefs.ErrNotImplemented)
+	verifyExpectedError(t, resp, err, errdefs.ErrInvalidArgument)
+}
+
+func Test_PodShim_updateInternal_NoTask_Error(t *testing.T) {
+	s := service{
+		tid:       t.Name(),
+		isSandbox: true,
+	}
+
+	resources, err := typeurl.MarshalAny(&specs.WindowsResources{})
+	if err != nil {
+		t.Fatalf("failed to marshal resources: %v", err)
+	}
+	resp, err := s.updateInternal(context.TODO(), &task.UpdateTaskRequest{ID: t.Name(), Resources: resources})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
 }
 
 func Test_PodShim_waitInternal_NoTask_Error(t *testing.T) {
@@ -641,7 +683,7 @@ func Test_PodShim_waitInternal_2ndTaskID_2ndExecID_Success(t *testing.T) {
 	}
 }
 
-func Test_PodShim_statsInternal_Error(t *testing.T) {
+func Test_PodShim_statsInternal_NoTask_Error(t *testing.T) {
 	s := service{
 		tid:       t.Name(),
 		isSandbox: true,
@@ -649,5 +691,48 @@ func Test_PodShim_statsInternal_Error(t *testing.T) {
 
 	resp, err := s.statsInternal(context.TODO(), &task.StatsRequest{ID: t.Name()})
 
-	verifyExpectedError(t, resp, err, errdefs.ErrNotImplemented)
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
+}
+
+func Test_PodShim_statsInternal_Success(t *testing.T) {
+	s, t1, _, _ := setupPodServiceWithFakes(t)
+
+	resp, err := s.statsInternal(context.TODO(), &task.StatsRequest{ID: t1.ID()})
+	if err != nil {
+		t.Fatalf("should not have failed with error, got: %v", err)
+	}
+	v, err := typeurl.UnmarshalAny(resp.Stats)
+	if err != nil {
+		t.Fatalf("failed to unmarshal stats: %v", err)
+	}
+	if _, ok := v.(*stats.Statistics); !ok {
+		t.Fatalf("expected *stats.Statistics, got: %T", v)
+	}
+}
+
+func Test_PodShim_diagTasksInternal_Success(t *testing.T) {
+	s, t1, t2, t2e2 := setupPodServiceWithFakes(t)
+
+	resp, err := s.diagTasksInternal(context.TODO(), &shimdiag.TasksRequest{})
+	if err != nil {
+		t.Fatalf("should not have failed with error, got: %v", err)
+	}
+	if len(resp.Execs) != 3 {
+		t.Fatalf("expected 3 execs, got: %d", len(resp.Execs))
+	}
+	if resp.Execs[0].TaskID != t1.ID() {
+		t.Fatalf("expected sandbox task first, got: %+v", resp.Execs[0])
+	}
+	found := false
+	for _, e := range resp.Execs[1:] {
+		if e.TaskID != t2.ID() {
+			t.Fatalf("expected workload task '%s', got '%s'", t2.ID(), e.TaskID)
+		}
+		if e.ExecID == t2e2.ID() {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected workload exec '%s' in response", t2e2.ID())
+	}
 }
@@ -2,14 +2,21 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"math/rand"
+	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/options"
+	"github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/stats"
+	"github.com/Microsoft/hcsshim/internal/cow/cowtest"
+	"github.com/Microsoft/hcsshim/internal/shimdiag"
 	"github.com/containerd/containerd/errdefs"
 	"github.com/containerd/containerd/runtime/v2/task"
 	"github.com/containerd/typeurl"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
 func setupTaskServiceWithFakes(t *testing.T) (*service, *testShimTask, *testShimExec) {
@@ -191,6 +198,42 @@ func Test_TaskShim_startInternal_InitTaskID_2ndExecID_Success(t *testing.T) {
 	}
 }
 
+func Test_TaskShim_startInternal_Cancelled_Error(t *testing.T) {
+	s, t1, _ := setupTaskServiceWithFakes(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	resp, err := s.startInternal(ctx, &task.StartRequest{
+		ID:     t1.ID(),
+		ExecID: "",
+	})
+
+	verifyExpectedError(t, resp, err, context.Canceled)
+	if t1.exec.State() == shimExecStateRunning {
+		t.Fatal("exec should not have been started")
+	}
+}
+
+func Test_TaskShim_deleteInternal_Cancelled_Error(t *testing.T) {
+	s, t1, _ := setupTaskServiceWithFakes(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	resp, err := s.deleteInternal(ctx, &task.DeleteRequest{ID: t1.ID()})
+
+	verifyExpectedError(t, resp, err, context.Canceled)
+}
+
+func Test_abandonTask_KillsInit(t *testing.T) {
+	_, t1, _ := setupTaskServiceWithFakes(t)
+
+	abandonTask(t1)
+
+	if t1.exec.State() != shimExecStateExited {
+		t.Fatal("init exec should have been killed")
+	}
+}
+
 func Test_TaskShim_deleteInternal_NoTask_Error(t *testing.T) {
 	s := service{
 		tid:       t.Name(),
@@ -301,7 +344,7 @@ func Test_TaskShim_pidsInternal_InitTaskID_Success(t *testing.T) {
 	}
 }
 
-func Test_TaskShim_pauseInternal_Error(t *testing.T) {
+func Test_TaskShim_pauseInternal_NoTask_Error(t *testing.T) {
 	s := service{
 		tid:       t.Name(),
 		isSandbox: true,
@@ -309,10 +352,22 @@ func Test_TaskShim_pauseInternal_Error(t *testing.T) {
 
 	resp, err := s.pauseInternal(context.TODO(), &task.PauseRequest{ID: t.Name()})
 
-	verifyExpectedError(t, resp, err, errdefs.ErrNotImplemented)
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
 }
 
-func Test_TaskShim_resumeInternal_Error(t *testing.T) {
+func Test_TaskShim_pauseInternal_InitTaskID_Success(t *testing.T) {
+	s, t1, _ := setupTaskServiceWithFakes(t)
+
+	resp, err := s.pauseInternal(context.TODO(), &task.PauseRequest{ID: t1.ID()})
+	if err != nil {
+		t.Fatalf("should not have failed with error got: %v", err)
+	}
+	if resp == nil {
+		t.Fatal("should have returned Empty")
+	}
+}
+
+func Test_TaskShim_resumeInternal_NoTask_Error(t *testing.T) {
 	s := service{
 		tid:       t.Name(),
 		isSandbox: true,
@@ -320,7 +375,19 @@ func Test_TaskShim_resumeInternal_Error(t *testing.T) {
 
 	resp, err := s.resumeInternal(context.TODO(), &task.ResumeRequest{ID: t.Name()})
 
-	verifyExpectedError(t, resp, err, errdefs.ErrNotImplemented)
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
+}
+
+func Test_TaskShim_resumeInternal_InitTaskID_Success(t *testing.T) {
+	s, t1, _ := setupTaskServiceWithFakes(t)
+
+	resp, err := s.resumeInternal(context.TODO(), &task.ResumeRequest{ID: t1.ID()})
+	if err != nil {
+		t.Fatalf("should not have failed with error got: %v", err)
+	}
+	if resp == nil {
+		t.Fatal("should have returned Empty")
+	}
 }
 
 func Test_TaskShim_checkpointInternal_Error(t *testing.T) {
@@ -386,6 +453,52 @@ func Test_TaskShim_killInternal_InitTaskID_2ndExecID_Success(t *testing.T) {
 	}
 }
 
+func Test_TaskShim_killInternal_InitTaskID_All_SignalsUntracked(t *testing.T) {
+	c := cowtest.NewContainer(t.Name(), "linux", true)
+	var procs []*cowtest.Process
+	for i := 0; i < 4; i++ {
+		p, err := c.CreateProcess(nil)
+		if err != nil {
+			t.Fatalf("failed to create process: %v", err)
+		}
+		procs = append(procs, p.(*cowtest.Process))
+	}
+	// The first two processes are the init and 2nd exec of the task. The
+	// others were started by them so are not tracked by the shim.
+	ht := &hcsTask{
+		events: fakePublisher,
+		id:     t.Name(),
+		c:      c,
+		init:   newTestShimExec(t.Name(), t.Name(), procs[0].Pid()),
+		closed: make(chan struct{}),
+	}
+	ht.execs.Store("2nd", newTestShimExec(t.Name(), "2nd", procs[1].Pid()))
+	s := service{
+		tid:       t.Name(),
+		isSandbox: false,
+	}
+	s.taskOrPod.Store(ht)
+
+	_, err := s.killInternal(context.TODO(), &task.KillRequest{
+		ID:     t.Name(),
+		ExecID: "",
+		Signal: 0x9,
+		All:    true,
+	})
+	if err != nil {
+		t.Fatalf("should not have failed with error got: %v", err)
+	}
+	for i, p := range procs {
+		delivered := len(p.Signals()) + p.Kills()
+		if i < 2 && delivered != 0 {
+			t.Fatalf("process %d should only be signalled through its exec, got %d signals", p.Pid(), delivered)
+		}
+		if i >= 2 && delivered != 1 {
+			t.Fatalf("untracked process %d should have been signalled once, got %d signals", p.Pid(), delivered)
+		}
+	}
+}
+
 // TODO: Test_TaskShim_execInternal_*
 
 func Test_TaskShim_resizePtyInternal_NoTask_Error(t *testing.T) {
@@ -492,7 +605,7 @@ func Test_TaskShim_closeIOInternal_InitTaskID_2ndExecID_Success(t *testing.T) {
 	}
 }
 
-func Test_TaskShim_updateInternal_Error(t *testing.T) {
+func Test_TaskShim_updateInternal_NoResources_Error(t *testing.T) {
 	s := service{
 		tid:       t.Name(),
 		isSandbox: true,
@@ -500,7 +613,22 @@ func Test_TaskShim_updateInternal_Error(t *testing.T) {
 
 	resp, err := s.updateInternal(context.TODO(), &task.UpdateTaskRequest{ID: t.Name()})
 
-	verifyExpectedError(t, resp, err, errdefs.ErrNotImplemented)
+	verifyExpectedError(t, resp, err, errdefs.ErrInvalidArgument)
+}
+
+func Test_TaskShim_updateInternal_NoTask_Error(t *testing.T) {
+	s := service{
+		tid:       t.Name(),
+		isSandbox: true,
+	}
+
+	resources, err := typeurl.MarshalAny(&specs.WindowsResources{})
+	if err != nil {
+		t.Fatalf("failed to marshal resources: %v", err)
+	}
+	resp, err := s.updateInternal(context.TODO(), &task.UpdateTaskRequest{ID: t.Name(), Resources: resources})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
 }
 
 func Test_TaskShim_waitInternal_NoTask_Error(t *testing.T) {
@@ -561,7 +689,89 @@ func Test_TaskShim_waitInternal_InitTaskID_2ndExecID_Success(t *testing.T) {
 	}
 }
 
-func Test_TaskShim_statsInternal_Error(t *testing.T) {
+func Test_TaskShim_waitInternal_2ndExecID_ExitedThenDeleted_Success(t *testing.T) {
+	s, t1, e2 := setupTaskServiceWithFakes(t)
+	e2.state = shimExecStateRunning
+	e2.waiting = make(chan struct{})
+	e2.exited = make(chan struct{})
+
+	type result struct {
+		resp *task.WaitResponse
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		resp, err := s.waitInternal(context.TODO(), &task.WaitRequest{
+			ID:     t1.ID(),
+			ExecID: e2.ID(),
+		})
+		done <- result{resp, err}
+	}()
+	<-e2.waiting
+	// The exec exits and is deleted before the waiter observes the exit.
+	e2.state = shimExecStateExited
+	e2.status = 3
+	if _, err := s.deleteInternal(context.TODO(), &task.DeleteRequest{ID: t1.ID(), ExecID: e2.ID()}); err != nil {
+		t.Fatalf("should not have failed with error got: %v", err)
+	}
+	close(e2.exited)
+
+	r := <-done
+	if r.err != nil {
+		t.Fatalf("should not have failed with error got: %v", r.err)
+	}
+	if r.resp.ExitStatus != 3 {
+		t.Fatalf("expected exit status 3, got: %d", r.resp.ExitStatus)
+	}
+}
+
+func Test_TaskShim_waitInternal_2ndExecID_DeletedBeforeStart_Error(t *testing.T) {
+	s, t1, e2 := setupTaskServiceWithFakes(t)
+	// An exec that was never started has no pid.
+	e2.pid = 0
+	e2.waiting = make(chan struct{})
+	e2.exited = make(chan struct{})
+
+	type result struct {
+		resp *task.WaitResponse
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		resp, err := s.waitInternal(context.TODO(), &task.WaitRequest{
+			ID:     t1.ID(),
+			ExecID: e2.ID(),
+		})
+		done <- result{resp, err}
+	}()
+	<-e2.waiting
+	if _, err := s.deleteInternal(context.TODO(), &task.DeleteRequest{ID: t1.ID(), ExecID: e2.ID()}); err != nil {
+		t.Fatalf("should not have failed with error got: %v", err)
+	}
+	close(e2.exited)
+
+	r := <-done
+	verifyExpectedError(t, r.resp, r.err, errdefs.ErrNotFound)
+}
+
+func Test_TaskShim_waitInternal_ContextCancelled_Error(t *testing.T) {
+	s, t1, e2 := setupTaskServiceWithFakes(t)
+	e2.waiting = make(chan struct{})
+	e2.exited = make(chan struct{})
+	defer close(e2.exited)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	_, err := s.waitInternal(ctx, &task.WaitRequest{
+		ID:     t1.ID(),
+		ExecID: e2.ID(),
+	})
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got: %v", err)
+	}
+}
+
+func Test_TaskShim_statsInternal_NoTask_Error(t *testing.T) {
 	s := service{
 		tid:       t.Name(),
 		isSandbox: true,
@@ -569,5 +779,277 @@ func Test_TaskShim_statsInternal_Error(t *testing.T) {
 
 	resp, err := s.statsInternal(context.TODO(), &task.StatsRequest{ID: t.Name()})
 
-	verifyExpectedError(t, resp, err, errdefs.ErrNotImplemented)
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
+}
+
+func Test_TaskShim_statsInternal_Success(t *testing.T) {
+	s, t1, _ := setupTaskServiceWithFakes(t)
+
+	resp, err := s.statsInternal(context.TODO(), &task.StatsRequest{ID: t1.ID()})
+	if err != nil {
+		t.Fatalf("should not have failed with error, got: %v", err)
+	}
+	v, err := typeurl.UnmarshalAny(resp.Stats)
+	if err != nil {
+		t.Fatalf("failed to unmarshal stats: %v", err)
+	}
+	if _, ok := v.(*stats.Statistics); !ok {
+		t.Fatalf("expected *stats.Statistics, got: %T", v)
+	}
+}
+
+func Test_TaskShim_diagTaskStateInternal_NoTask_Error(t *testing.T) {
+	s := service{
+		tid:       t.Name(),
+		isSandbox: false,
+	}
+
+	resp, err := s.diagTaskStateInternal(context.TODO(), &shimdiag.TaskStateRequest{TaskID: t.Name()})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
+}
+
+func Test_TaskShim_diagTaskStateInternal_InitTaskID_2ndExecID_Success(t *testing.T) {
+	s, t1, e2 := setupTaskServiceWithFakes(t)
+
+	resp, err := s.diagTaskStateInternal(context.TODO(), &shimdiag.TaskStateRequest{
+		TaskID: t1.ID(),
+		ExecID: e2.ID(),
+	})
+	if err != nil {
+		t.Fatalf("should not have failed with error got: %v", err)
+	}
+	if resp == nil {
+		t.Fatal("should have returned TaskStateResponse")
+	}
+	if resp.TaskID != t1.ID() {
+		t.Fatalf("TaskStateResponse.TaskID expected '%s' got '%s'", t1.ID(), resp.TaskID)
+	}
+	if resp.ExecID != e2.ID() {
+		t.Fatalf("TaskStateResponse.ExecID expected '%s' got '%s'", e2.ID(), resp.ExecID)
+	}
+	if resp.Pid != uint32(t1.execs[e2.ID()].pid) {
+		t.Fatalf("should have returned 2nd exec pid, got: %v", resp.Pid)
+	}
+}
+
+func Test_TaskShim_diagConsoleInternal_NoStdout_Error(t *testing.T) {
+	s, _, _ := setupTaskServiceWithFakes(t)
+
+	resp, err := s.diagConsoleInternal(context.TODO(), &shimdiag.ConsoleRequest{})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrInvalidArgument)
+}
+
+func Test_TaskShim_diagConsoleInternal_NoTask_Error(t *testing.T) {
+	s := service{
+		tid:       t.Name(),
+		isSandbox: false,
+	}
+
+	resp, err := s.diagConsoleInternal(context.TODO(), &shimdiag.ConsoleRequest{Stdout: `\\.\pipe\stdout`})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
+}
+
+func Test_TaskShim_diagExportRootfsInternal_NoStdout_Error(t *testing.T) {
+	s, t1, _ := setupTaskServiceWithFakes(t)
+
+	resp, err := s.diagExportRootfsInternal(context.TODO(), &shimdiag.ExportRootfsRequest{TaskID: t1.ID()})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrInvalidArgument)
+}
+
+func Test_TaskShim_diagExportRootfsInternal_NoTask_Error(t *testing.T) {
+	s := service{
+		tid:       t.Name(),
+		isSandbox: false,
+	}
+
+	resp, err := s.diagExportRootfsInternal(context.TODO(), &shimdiag.ExportRootfsRequest{TaskID: t.Name(), Stdout: `\\.\pipe\stdout`})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
+}
+
+func Test_TaskShim_diagTasksInternal_NoTask_Empty(t *testing.T) {
+	s := service{
+		tid:       t.Name(),
+		isSandbox: false,
+	}
+
+	resp, err := s.diagTasksInternal(context.TODO(), &shimdiag.TasksRequest{})
+	if err != nil {
+		t.Fatalf("should not have failed with error, got: %v", err)
+	}
+	if len(resp.Execs) != 0 {
+		t.Fatalf("expected no execs, got: %v", resp.Execs)
+	}
+}
+
+func Test_TaskShim_diagTasksInternal_Success(t *testing.T) {
+	s, t1, e2 := setupTaskServiceWithFakes(t)
+
+	resp, err := s.diagTasksInternal(context.TODO(), &shimdiag.TasksRequest{})
+	if err != nil {
+		t.Fatalf("should not have failed with error, got: %v", err)
+	}
+	if len(resp.Execs) != 2 {
+		t.Fatalf("expected 2 execs, got: %d", len(resp.Execs))
+	}
+	if resp.Execs[0].TaskID != t1.ID() || resp.Execs[0].ExecID != t1.ID() {
+		t.Fatalf("expected init exec first, got: %+v", resp.Execs[0])
+	}
+	if resp.Execs[1].ExecID != e2.ID() {
+		t.Fatalf("expected 2nd exec '%s', got '%s'", e2.ID(), resp.Execs[1].ExecID)
+	}
+}
+
+func Test_TaskShim_diagShareInternal_NoPaths_Error(t *testing.T) {
+	s, _, _ := setupTaskServiceWithFakes(t)
+
+	resp, err := s.diagShareInternal(context.TODO(), &shimdiag.ShareRequest{HostPath: `C:\tools`})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrInvalidArgument)
+}
+
+func Test_TaskShim_diagShareInternal_NoTask_Error(t *testing.T) {
+	s := service{
+		tid:       t.Name(),
+		isSandbox: false,
+	}
+
+	resp, err := s.diagShareInternal(context.TODO(), &shimdiag.ShareRequest{HostPath: `C:\tools`, UvmPath: `C:\tools`})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
+}
+
+func Test_TaskShim_diagEndpointInternal_NoEndpointID_Error(t *testing.T) {
+	s, _, _ := setupTaskServiceWithFakes(t)
+
+	resp, err := s.diagEndpointInternal(context.TODO(), &shimdiag.EndpointRequest{})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrInvalidArgument)
+}
+
+func Test_TaskShim_diagEndpointInternal_NoTask_Error(t *testing.T) {
+	s := service{
+		tid:       t.Name(),
+		isSandbox: false,
+	}
+
+	resp, err := s.diagEndpointInternal(context.TODO(), &shimdiag.EndpointRequest{EndpointID: t.Name()})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
+}
+
+func Test_TaskShim_diagCPUGroupInternal_NoCPUGroupID_Error(t *testing.T) {
+	s, _, _ := setupTaskServiceWithFakes(t)
+
+	resp, err := s.diagCPUGroupInternal(context.TODO(), &shimdiag.CPUGroupRequest{})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrInvalidArgument)
+}
+
+func Test_TaskShim_diagCPUGroupInternal_NoTask_Error(t *testing.T) {
+	s := service{
+		tid:       t.Name(),
+		isSandbox: false,
+	}
+
+	resp, err := s.diagCPUGroupInternal(context.TODO(), &shimdiag.CPUGroupRequest{CPUGroupID: t.Name()})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
+}
+
+func Test_TaskShim_diagCPUGroupInternal_Success(t *testing.T) {
+	s, _, _ := setupTaskServiceWithFakes(t)
+
+	resp, err := s.diagCPUGroupInternal(context.TODO(), &shimdiag.CPUGroupRequest{CPUGroupID: "4e7b7c7a-0a3c-4d5e-9f4a-1b2c3d4e5f60"})
+	if err != nil {
+		t.Fatalf("should not have failed with error, got: %v", err)
+	}
+	if resp == nil {
+		t.Fatal("should have returned CPUGroupResponse")
+	}
+}
+
+func Test_TaskShim_diagSaveUVMInternal_NoPath_Error(t *testing.T) {
+	s, _, _ := setupTaskServiceWithFakes(t)
+
+	resp, err := s.diagSaveUVMInternal(context.TODO(), &shimdiag.SaveUVMRequest{})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrInvalidArgument)
+}
+
+func Test_TaskShim_diagSaveUVMInternal_NoTask_Error(t *testing.T) {
+	s := service{
+		tid:       t.Name(),
+		isSandbox: false,
+	}
+
+	resp, err := s.diagSaveUVMInternal(context.TODO(), &shimdiag.SaveUVMRequest{Path: `c:\saved\uvm.vmrs`})
+
+	verifyExpectedError(t, resp, err, errdefs.ErrNotFound)
+}
+
+func Test_TaskShim_diagSaveUVMInternal_Success(t *testing.T) {
+	s, _, _ := setupTaskServiceWithFakes(t)
+
+	resp, err := s.diagSaveUVMInternal(context.TODO(), &shimdiag.SaveUVMRequest{Path: `c:\saved\uvm.vmrs`})
+	if err != nil {
+		t.Fatalf("should not have failed with error, got: %v", err)
+	}
+	if resp == nil {
+		t.Fatal("should have returned SaveUVMResponse")
+	}
+}
+
+func Test_TaskShim_diagStacksInternal_NotIsolated_HostStacksOnly(t *testing.T) {
+	s, _, _ := setupTaskServiceWithFakes(t)
+
+	resp, err := s.diagStacksInternal(context.TODO(), &shimdiag.StacksRequest{})
+	if err != nil {
+		t.Fatalf("should not have failed with error, got: %v", err)
+	}
+	if !strings.Contains(resp.Stacks, "diagStacksInternal") {
+		t.Fatalf("expected host stacks to include the caller, got: %s", resp.Stacks)
+	}
+	if resp.GuestStacks != "" {
+		t.Fatalf("expected no guest stacks, got: %s", resp.GuestStacks)
+	}
+}
+
+func Test_TaskShim_connectInternal_NoTask_NoTaskCapabilities(t *testing.T) {
+	s := service{
+		tid:       t.Name(),
+		isSandbox: false,
+	}
+
+	resp, err := s.connectInternal(context.TODO(), &task.ConnectRequest{ID: t.Name()})
+	if err != nil {
+		t.Fatalf("should not have failed with error, got: %v", err)
+	}
+	var caps shimdiag.ShimCapabilities
+	if err := json.Unmarshal([]byte(resp.Version), &caps); err != nil {
+		t.Fatalf("expected capabilities in version, got: %v", err)
+	}
+	if caps.Task != nil {
+		t.Fatalf("expected no task capabilities, got: %+v", caps.Task)
+	}
+}
+
+func Test_TaskShim_connectInternal_Success(t *testing.T) {
+	s, t1, _ := setupTaskServiceWithFakes(t)
+
+	resp, err := s.connectInternal(context.TODO(), &task.ConnectRequest{ID: t1.ID()})
+	if err != nil {
+		t.Fatalf("should not have failed with error, got: %v", err)
+	}
+	var caps shimdiag.ShimCapabilities
+	if err := json.Unmarshal([]byte(resp.Version), &caps); err != nil {
+		t.Fatalf("expected capabilities in version, got: %v", err)
+	}
+	if caps.Task == nil || !reflect.DeepEqual(caps.Task, t1.Capabilities()) {
+		t.Fatalf("expected task capabilities %+v, got: %+v", t1.Capabilities(), caps.Task)
+	}
 }
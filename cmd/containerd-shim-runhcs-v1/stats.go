@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/stats"
+	"github.com/Microsoft/hcsshim/internal/schema1"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/pkg/errors"
+)
+
+// containerStatistics converts the HCS statistics of a container `s` into
+// the statistics returned by the `Stats` RPC. HCS reports times in 100ns
+// units.
+func containerStatistics(s *schema1.Statistics) *stats.Statistics_Windows {
+	return &stats.Statistics_Windows{
+		Windows: &stats.WindowsContainerStatistics{
+			Timestamp:          s.Timestamp,
+			ContainerStartTime: s.ContainerStartTime,
+			UptimeNs:           s.Uptime100ns * 100,
+			Processor: &stats.WindowsContainerProcessorStatistics{
+				TotalRuntimeNs:  s.Processor.TotalRuntime100ns * 100,
+				RuntimeUserNs:   s.Processor.RuntimeUser100ns * 100,
+				RuntimeKernelNs: s.Processor.RuntimeKernel100ns * 100,
+			},
+			Memory: &stats.WindowsContainerMemoryStatistics{
+				MemoryUsageCommitBytes:            s.Memory.UsageCommitBytes,
+				MemoryUsageCommitPeakBytes:        s.Memory.UsageCommitPeakBytes,
+				MemoryUsagePrivateWorkingSetBytes: s.Memory.UsagePrivateWorkingSetBytes,
+			},
+			Storage: &stats.WindowsContainerStorageStatistics{
+				ReadCountNormalized:  s.Storage.ReadCountNormalized,
+				ReadSizeBytes:        s.Storage.ReadSizeBytes,
+				WriteCountNormalized: s.Storage.WriteCountNormalized,
+				WriteSizeBytes:       s.Storage.WriteSizeBytes,
+			},
+		},
+	}
+}
+
+// linuxStatistics converts the cgroup metrics reported by the guest for an LCOW
+// container into the statistics returned by the `Stats` RPC. The guest encodes
+// them as the containerd cgroups v1 Metrics, which `stats.LinuxMetrics`
+// mirrors.
+func linuxStatistics(p *schema1.ContainerProperties) (*stats.Statistics_Linux, error) {
+	if p.CgroupMetrics == nil {
+		return nil, errors.New("guest did not return cgroup metrics")
+	}
+	m := &stats.LinuxMetrics{}
+	if err := json.Unmarshal(*p.CgroupMetrics, m); err != nil {
+		return nil, errors.Wrap(err, "failed to decode cgroup metrics")
+	}
+	return &stats.Statistics_Linux{Linux: m}, nil
+}
+
+// vmStatistics converts the HCS statistics of a utility VM `s` into the
+// statistics returned by the `Stats` RPC.
+func vmStatistics(s *schema1.Statistics) *stats.VirtualMachineStatistics {
+	return &stats.VirtualMachineStatistics{
+		Processor: &stats.VirtualMachineProcessorStatistics{
+			TotalRuntimeNs: s.Processor.TotalRuntime100ns * 100,
+		},
+		Memory: &stats.VirtualMachineMemoryStatistics{
+			WorkingSetBytes: s.Memory.UsagePrivateWorkingSetBytes,
+			CommitBytes:     s.Memory.UsageCommitBytes,
+		},
+	}
+}
+
+// vmResourceStatistics adds the resources assigned to a utility VM to its
+// statistics `vm`, so that the overhead of the utility VM can be compared to
+// what it was given.
+func vmResourceStatistics(vm *stats.VirtualMachineStatistics, processorCount, memorySizeInMB int32, vpmemCount, scsiCount int) {
+	vm.Processor.Count = uint32(processorCount)
+	vm.Memory.AssignedBytes = uint64(memorySizeInMB) * 1024 * 1024
+	vm.Devices = &stats.VirtualMachineDeviceStatistics{
+		VpmemCount: uint32(vpmemCount),
+		ScsiCount:  uint32(scsiCount),
+	}
+}
+
+// hostStatistics returns the statistics of the utility VM `host`.
+func hostStatistics(host *uvm.UtilityVM) (*stats.VirtualMachineStatistics, error) {
+	s, err := host.Statistics()
+	if err != nil {
+		return nil, err
+	}
+	vm := vmStatistics(s)
+	vmResourceStatistics(vm, host.ProcessorCount(), host.MemorySizeInMB(), host.VPMEMDevicesInUse(), len(host.SCSIAttachments()))
+	return vm, nil
+}
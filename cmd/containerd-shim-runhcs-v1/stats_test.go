@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/schema1"
+)
+
+func Test_containerStatistics(t *testing.T) {
+	s := &schema1.Statistics{
+		Uptime100ns: 10,
+		Processor:   schema1.ProcessorStats{TotalRuntime100ns: 3, RuntimeUser100ns: 2, RuntimeKernel100ns: 1},
+		Memory:      schema1.MemoryStats{UsageCommitBytes: 1024, UsagePrivateWorkingSetBytes: 512},
+		Storage:     schema1.StorageStats{ReadCountNormalized: 4, WriteSizeBytes: 4096},
+	}
+
+	w := containerStatistics(s).Windows
+	if w.UptimeNs != 1000 {
+		t.Fatalf("expected uptime 1000ns, got: %d", w.UptimeNs)
+	}
+	if w.Processor.TotalRuntimeNs != 300 || w.Processor.RuntimeUserNs != 200 || w.Processor.RuntimeKernelNs != 100 {
+		t.Fatalf("unexpected processor statistics: %+v", w.Processor)
+	}
+	if w.Memory.MemoryUsageCommitBytes != 1024 || w.Memory.MemoryUsagePrivateWorkingSetBytes != 512 {
+		t.Fatalf("unexpected memory statistics: %+v", w.Memory)
+	}
+	if w.Storage.ReadCountNormalized != 4 || w.Storage.WriteSizeBytes != 4096 {
+		t.Fatalf("unexpected storage statistics: %+v", w.Storage)
+	}
+}
+
+func Test_linuxStatistics(t *testing.T) {
+	raw := json.RawMessage(`{"pids":{"current":3,"limit":10},"cpu":{"usage":{"total":300,"kernel":100,"user":200,"per_cpu":[150,150]}},"memory":{"cache":4096,"usage":{"usage":8192,"limit":16384}},"blkio":{"io_service_bytes_recursive":[{"op":"Read","major":8,"value":512}]}}`)
+
+	l, err := linuxStatistics(&schema1.ContainerProperties{CgroupMetrics: &raw})
+	if err != nil {
+		t.Fatalf("should not have failed with error, got: %v", err)
+	}
+	m := l.Linux
+	if m.Pids.Current != 3 || m.Pids.Limit != 10 {
+		t.Fatalf("unexpected pids statistics: %+v", m.Pids)
+	}
+	if m.Cpu.Usage.Total != 300 || m.Cpu.Usage.User != 200 || len(m.Cpu.Usage.PerCpu) != 2 {
+		t.Fatalf("unexpected cpu statistics: %+v", m.Cpu.Usage)
+	}
+	if m.Memory.Cache != 4096 || m.Memory.Usage.Usage != 8192 || m.Memory.Usage.Limit != 16384 {
+		t.Fatalf("unexpected memory statistics: %+v", m.Memory)
+	}
+	if len(m.Blkio.IoServiceBytesRecursive) != 1 || m.Blkio.IoServiceBytesRecursive[0].Value != 512 {
+		t.Fatalf("unexpected blkio statistics: %+v", m.Blkio)
+	}
+}
+
+func Test_linuxStatistics_Missing_Error(t *testing.T) {
+	if _, err := linuxStatistics(&schema1.ContainerProperties{}); err == nil {
+		t.Fatal("expected error for missing cgroup metrics")
+	}
+}
+
+func Test_vmStatistics(t *testing.T) {
+	vm := vmStatistics(&schema1.Statistics{
+		Processor: schema1.ProcessorStats{TotalRuntime100ns: 5},
+		Memory:    schema1.MemoryStats{UsageCommitBytes: 2048, UsagePrivateWorkingSetBytes: 1024},
+	})
+	if vm.Processor.TotalRuntimeNs != 500 {
+		t.Fatalf("expected runtime 500ns, got: %d", vm.Processor.TotalRuntimeNs)
+	}
+	if vm.Memory.WorkingSetBytes != 1024 || vm.Memory.CommitBytes != 2048 {
+		t.Fatalf("unexpected memory statistics: %+v", vm.Memory)
+	}
+}
+
+func Test_vmResourceStatistics(t *testing.T) {
+	vm := vmStatistics(&schema1.Statistics{})
+	vmResourceStatistics(vm, 2, 1024, 3, 1)
+	if vm.Processor.Count != 2 {
+		t.Fatalf("expected 2 processors, got: %d", vm.Processor.Count)
+	}
+	if vm.Memory.AssignedBytes != 1024*1024*1024 {
+		t.Fatalf("expected 1GB assigned, got: %d", vm.Memory.AssignedBytes)
+	}
+	if vm.Devices.VpmemCount != 3 || vm.Devices.ScsiCount != 1 {
+		t.Fatalf("unexpected device statistics: %+v", vm.Devices)
+	}
+}
@@ -5,11 +5,17 @@ import (
 	"time"
 
 	"github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/options"
+	"github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/stats"
 	"github.com/Microsoft/hcsshim/internal/shimdiag"
 	"github.com/containerd/containerd/runtime/v2/task"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
 )
 
+// errTaskNotIsolated is returned by the diagnostics of the host UVM of a task
+// that is not hypervisor isolated.
+var errTaskNotIsolated = errors.New("task is not isolated")
+
 // shimTaskPidPair groups a process pid to its execID if it was user generated.
 type shimTaskPidPair struct {
 	// Pid is the pid of the container process.
@@ -35,6 +41,9 @@ type shimTask interface {
 	//
 	// If `eid` is not found this task MUST return `errdefs.ErrNotFound`.
 	GetExec(eid string) (shimExec, error)
+	// ListExecs returns all execs in this task. The init exec is always
+	// first.
+	ListExecs() []shimExec
 	// KillExec sends `signal` to the exec that matches `eid`. If `all==true`
 	// `eid` MUST be empty and this task will send `signal` to all exec's in the
 	// task and lastly send `signal` to the init exec.
@@ -73,6 +82,87 @@ type shimTask interface {
 	// ExecInHost execs a process in the host UVM. It is not tracked in the
 	// other lifetimes of the task and is used only for diagnostics.
 	//
-	// If the host is not hypervisor isolated returns error.
+	// If the host is not hypervisor isolated returns `errTaskNotIsolated`.
 	ExecInHost(ctx context.Context, req *shimdiag.ExecProcessRequest) (int, error)
+	// AttachHostConsole relays the serial console of the host UVM to the
+	// named pipes in `req` until the client closes stdin or `ctx` is done. It
+	// does not depend on the guest connection and is used only for
+	// diagnostics.
+	//
+	// If the host is not hypervisor isolated returns `errTaskNotIsolated`. If
+	// the host has no serial console returns `errdefs.ErrFailedPrecondition`.
+	AttachHostConsole(ctx context.Context, req *shimdiag.ConsoleRequest) error
+	// Share shares the host path in `req` into the host UVM at the UVM path
+	// in `req`. It is not tracked in the other lifetimes of the task and is
+	// used only for diagnostics.
+	//
+	// If the host is not hypervisor isolated returns `errTaskNotIsolated`.
+	Share(ctx context.Context, req *shimdiag.ShareRequest) error
+	// UpdateEndpoint hot adds the HNS endpoint in `req` to, or removes it
+	// from, the network namespace of the task in its host UVM. It is used to
+	// attach secondary interfaces to a live pod.
+	//
+	// If the host is not hypervisor isolated returns `errTaskNotIsolated`.
+	UpdateEndpoint(ctx context.Context, req *shimdiag.EndpointRequest) error
+	// Capabilities returns the features supported by this task so callers
+	// can adapt rather than probe with failing calls.
+	Capabilities() *shimdiag.TaskCapabilities
+	// DumpGuestStacks returns the goroutine stacks of the GCS in the host
+	// UVM. It is used only for diagnostics.
+	//
+	// If the host is not hypervisor isolated returns `errTaskNotIsolated`.
+	DumpGuestStacks(ctx context.Context) (string, error)
+	// DiagState returns the state of the exec `eid` augmented with a live
+	// snapshot of the resources used by the task. It is used only for
+	// diagnostics.
+	//
+	// If `eid == ""` the state of the init exec is returned.
+	DiagState(ctx context.Context, eid string) (*shimdiag.TaskStateResponse, error)
+	// ExportRootfs writes the merged root file system of the task to the
+	// named pipe in `req` as a tar stream. It is used only for diagnostics
+	// and forensics.
+	//
+	// If the host is not hypervisor isolated returns `errTaskNotIsolated`. If
+	// the task is not an LCOW container returns `errdefs.ErrNotImplemented`.
+	ExportRootfs(ctx context.Context, req *shimdiag.ExportRootfsRequest) error
+	// Update updates the resource limits of the task to `resources` which is
+	// either a `*specs.WindowsResources` or a `*specs.LinuxResources`. Only
+	// limits that are set in `resources` are changed.
+	//
+	// If `resources` does not match the platform of the task returns
+	// `errdefs.ErrInvalidArgument`. If the task does not support updating the
+	// limits returns `errdefs.ErrNotImplemented`.
+	Update(ctx context.Context, resources interface{}) error
+	// SetCPUGroup assigns the UVM hosting the task to the existing host CPU
+	// group `id`.
+	//
+	// If the host is not hypervisor isolated returns `errTaskNotIsolated`. If
+	// the task does not own its host returns `errdefs.ErrFailedPrecondition`.
+	SetCPUGroup(ctx context.Context, id string) error
+	// SaveUVM pauses the UVM hosting the task and saves its state to the file
+	// `path` so that a pod can later be created from it. The UVM is left paused
+	// and the task is expected to be deleted afterwards.
+	//
+	// If the host is not hypervisor isolated returns `errTaskNotIsolated`. If
+	// the task does not own its host returns `errdefs.ErrFailedPrecondition`.
+	// If the host cannot be saved returns `errdefs.ErrNotImplemented`.
+	SaveUVM(ctx context.Context, path string) error
+	// Pause suspends all processes in the task.
+	//
+	// If the task does not support pausing returns
+	// `errdefs.ErrNotImplemented`. If the task is not running or is already
+	// paused returns `errdefs.ErrFailedPrecondition`.
+	Pause(ctx context.Context) error
+	// Resume resumes all processes in a task suspended by `Pause`.
+	//
+	// If the task does not support pausing returns
+	// `errdefs.ErrNotImplemented`. If the task is not paused returns
+	// `errdefs.ErrFailedPrecondition`.
+	Resume(ctx context.Context) error
+	// Stats returns the runtime statistics of the task and, if hypervisor
+	// isolated, of its hosting UVM.
+	Stats(ctx context.Context) (*stats.Statistics, error)
+	// Paused returns `true` if the task was suspended by `Pause` and has not
+	// since been resumed.
+	Paused() bool
 }
@@ -3,17 +3,26 @@ package main
 import (
 	"context"
 	"fmt"
+	"math"
 	"os"
-	"path/filepath"
+	goruntime "runtime"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/options"
+	"github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/stats"
 	"github.com/Microsoft/hcsshim/internal/cow"
+	"github.com/Microsoft/hcsshim/internal/cpugroup"
+	"github.com/Microsoft/hcsshim/internal/guestrequest"
 	"github.com/Microsoft/hcsshim/internal/hcs"
 	"github.com/Microsoft/hcsshim/internal/hcsoci"
+	"github.com/Microsoft/hcsshim/internal/hns"
+	"github.com/Microsoft/hcsshim/internal/jobobject"
 	"github.com/Microsoft/hcsshim/internal/oci"
+	"github.com/Microsoft/hcsshim/internal/requesttype"
 	"github.com/Microsoft/hcsshim/internal/schema1"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
 	"github.com/Microsoft/hcsshim/internal/shimdiag"
 	"github.com/Microsoft/hcsshim/internal/uvm"
 	"github.com/Microsoft/hcsshim/osversion"
@@ -25,6 +34,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func newHcsStandaloneTask(ctx context.Context, events publisher, req *task.CreateTaskRequest, s *specs.Spec) (shimTask, error) {
@@ -44,7 +55,7 @@ func newHcsStandaloneTask(ctx context.Context, events publisher, req *task.Creat
 			ct)
 	}
 
-	owner := filepath.Base(os.Args[0])
+	owner := shimOwner()
 
 	var parent *uvm.UtilityVM
 	if osversion.Get().Build >= osversion.RS5 && oci.IsIsolated(s) {
@@ -53,15 +64,25 @@ func newHcsStandaloneTask(ctx context.Context, events publisher, req *task.Creat
 		if err != nil {
 			return nil, err
 		}
+		if err := getConfig().setUVMConsoleLog(opts); err != nil {
+			return nil, err
+		}
+		getConfig().setUVMGuestCrashDump(opts, req.Bundle)
+		limits, err := getConfig().reservationLimits()
+		if err != nil {
+			return nil, err
+		}
 		switch opts.(type) {
 		case *uvm.OptionsLCOW:
 			lopts := (opts).(*uvm.OptionsLCOW)
+			lopts.ReservationLimits = limits
 			parent, err = uvm.CreateLCOW(lopts)
 			if err != nil {
-				return nil, err
+				return nil, wrapReservationError(err)
 			}
 		case *uvm.OptionsWCOW:
 			wopts := (opts).(*uvm.OptionsWCOW)
+			wopts.ReservationLimits = limits
 
 			// In order for the UVM sandbox.vhdx not to collide with the actual
 			// nested Argon sandbox.vhdx we append the \vm folder to the last
@@ -70,7 +91,7 @@ func newHcsStandaloneTask(ctx context.Context, events publisher, req *task.Creat
 			layers := make([]string, layersLen)
 			copy(layers, s.Windows.LayerFolders)
 
-			vmPath := filepath.Join(layers[layersLen-1], "vm")
+			vmPath := uvmScratchPath(s)
 			err := os.MkdirAll(vmPath, 0)
 			if err != nil {
 				return nil, err
@@ -80,12 +101,13 @@ func newHcsStandaloneTask(ctx context.Context, events publisher, req *task.Creat
 
 			parent, err = uvm.CreateWCOW(wopts)
 			if err != nil {
-				return nil, err
+				return nil, wrapReservationError(err)
 			}
 		}
-		err = parent.Start()
+		err = parent.StartContext(ctx)
 		if err != nil {
 			parent.Close()
+			return nil, err
 		}
 	} else if !oci.IsWCOW(s) {
 		return nil, errors.Wrap(errdefs.ErrFailedPrecondition, "oci spec does not contain WCOW or LCOW spec")
@@ -117,7 +139,11 @@ func newHcsTask(
 		"ownsParent": ownsParent,
 	}).Debug("newHcsTask")
 
-	owner := filepath.Base(os.Args[0])
+	// All events for this task and its execs are published through the
+	// sequencer so that exec exits are published before the init exit.
+	events = newExitSequencer(req.ID, events).Publish
+
+	owner := shimOwner()
 
 	io, err := newNpipeIO(ctx, req.ID, req.ID, req.Stdin, req.Stdout, req.Stderr, req.Terminal)
 	if err != nil {
@@ -136,8 +162,15 @@ func newHcsTask(
 		HostingSystem:    parent,
 		NetworkNamespace: netNS,
 	}
-	system, resources, err := hcsoci.CreateContainer(&opts)
+	// Don't mount the layers of a container the caller has already given up
+	// on.
+	if err := ctx.Err(); err != nil {
+		io.Close()
+		return nil, errors.Wrapf(err, "task: '%s' create cancelled", req.ID)
+	}
+	system, resources, err := hcsoci.CreateContainerContext(ctx, &opts)
 	if err != nil {
+		io.Close()
 		return nil, err
 	}
 
@@ -150,10 +183,50 @@ func newHcsTask(
 		ownsHost: ownsParent,
 		host:     parent,
 		closed:   make(chan struct{}),
+
+		killPolicy: getConfig().killPolicy(s),
+		maxExecs:   oci.ParseAnnotationsMaxExecs(s),
+		execEnv:    hcsoci.ExecEnv(s),
+	}
+	if ownsParent && parent != nil {
+		ht.hostMemoryOverheadInMB = hostMemoryOverhead(parent, s)
+	}
+	if limits := oci.ParseAnnotationsExecLimits(s); limits != (jobobject.Limits{}) {
+		// HCS only limits the container as a whole. The shim can only limit
+		// the process tree of an exec if the processes run on the host.
+		if ht.isWCOW && parent == nil {
+			ht.execLimits = limits
+		} else {
+			logrus.WithField("tid", req.ID).Warning("exec limits are only supported for process isolated WCOW tasks")
+		}
+	}
+	if timeout := oci.ParseAnnotationsExecDrainTimeout(s); timeout > 0 {
+		// The guest kills all processes of a Linux container once its init
+		// process exits so there is nothing to drain.
+		if ht.isWCOW {
+			ht.execDrainTimeout = timeout
+		} else {
+			logrus.WithField("tid", req.ID).Warning("exec draining is only supported for WCOW tasks")
+		}
+	}
+	// If event logs are forwarded the forwarder is started once the init exec
+	// has started the container.
+	initEvents := events
+	if channels := oci.ParseAnnotationsEventLogChannels(s); len(channels) > 0 {
+		if ht.isWCOW {
+			initEvents = func(topic string, event interface{}) {
+				events(topic, event)
+				if topic == runtime.TaskStartEventTopic {
+					go ht.forwardEventLogs(channels)
+				}
+			}
+		} else {
+			logrus.WithField("tid", req.ID).Warning("event log forwarding is only supported for WCOW tasks")
+		}
 	}
 	ht.init = newHcsExec(
 		ctx,
-		events,
+		initEvents,
 		req.ID,
 		parent,
 		system,
@@ -161,7 +234,9 @@ func newHcsTask(
 		req.Bundle,
 		ht.isWCOW,
 		s.Process,
-		io)
+		io,
+		ht.killPolicy,
+		jobobject.Limits{})
 
 	if parent != nil {
 		// We have a parent UVM. Listen for its exit and forcibly close this
@@ -174,6 +249,7 @@ func newHcsTask(
 	go func() {
 		// Wait for our init process to exit.
 		ht.init.Wait(context.Background())
+		ht.drainExecs()
 		// Release all container resources for this task.
 		ht.close()
 	}()
@@ -194,6 +270,9 @@ func newHcsTask(
 			Checkpoint: "",
 			Pid:        uint32(ht.init.Pid()),
 		})
+	if n := guestOOMNotifier(system, parent); n != nil && !ht.isWCOW {
+		go ht.waitForOOMs(n)
+	}
 	return ht, nil
 }
 
@@ -241,6 +320,44 @@ type hcsTask struct {
 	// NOTE: if `osversion.Get().Build < osversion.RS5` this will always be
 	// `nil`.
 	host *uvm.UtilityVM
+	// hostMemoryOverheadInMB is the memory an owned `host` was created with
+	// beyond the memory limit of the task. A memory limit update resizes
+	// `host` to the new limit plus this overhead as the UVM needs memory
+	// beyond the container limit for itself.
+	//
+	// It MUST be treated as read only in the lifetime of the task.
+	hostMemoryOverheadInMB int32
+	// killPolicy is the escalation policy applied to all execs in this task.
+	//
+	// It MUST be treated as read only in the lifetime of the task.
+	killPolicy oci.KillPolicy
+	// maxExecs is the maximum number of concurrently running execs, not
+	// including the init exec. If `0` there is no limit.
+	//
+	// It MUST be treated as read only in the lifetime of the task.
+	maxExecs uint32
+	// execLimits are the limits applied to the process tree of each exec,
+	// not including the init exec. They are only set for a process isolated
+	// WCOW task.
+	//
+	// It MUST be treated as read only in the lifetime of the task.
+	execLimits jobobject.Limits
+	// execEnv is the proxy and CA certificate environment set in each exec
+	// unless the exec already sets it.
+	//
+	// It MUST be treated as read only in the lifetime of the task.
+	execEnv []string
+	// execDrainTimeout is the time execs that are still running when the
+	// init exec exits are given to exit before the container is shut down.
+	// If `0` they are killed immediately.
+	//
+	// It MUST be treated as read only in the lifetime of the task.
+	execDrainTimeout time.Duration
+
+	// pm MUST be held to safely read/write `paused`.
+	pm sync.Mutex
+	// paused is `true` if the task was paused via `Pause`.
+	paused bool
 
 	// ecl is the exec create lock for all non-init execs and MUST be held
 	// durring create to prevent ID duplication.
@@ -277,11 +394,51 @@ func (ht *hcsTask) CreateExec(ctx context.Context, req *task.ExecProcessRequest,
 		return errors.Wrapf(errdefs.ErrFailedPrecondition, "exec: '' in task: '%s' must be running to create additional execs", ht.id)
 	}
 
-	io, err := newNpipeIO(ctx, ht.id, req.ExecID, req.Stdin, req.Stdout, req.Stderr, req.Terminal)
+	if ht.Paused() {
+		return errors.Wrapf(errdefs.ErrFailedPrecondition, "task: '%s' must be resumed to create additional execs", ht.id)
+	}
+
+	if ht.maxExecs > 0 {
+		var live uint32
+		ht.execs.Range(func(key, value interface{}) bool {
+			if value.(shimExec).State() != shimExecStateExited {
+				live++
+			}
+			return true
+		})
+		if live >= ht.maxExecs {
+			return status.Errorf(codes.ResourceExhausted, "task: '%s' already has the maximum of %d running execs", ht.id, ht.maxExecs)
+		}
+	}
+
+	stdout := req.Stdout
+	detached, outputPath, err := parseDetachedStdout(stdout)
+	if err != nil {
+		return err
+	}
+	if detached {
+		if req.Stdin != "" || req.Stderr != "" || req.Terminal {
+			return errors.Wrapf(errdefs.ErrInvalidArgument, "exec: '%s' in task: '%s' is detached and cannot have stdin, stderr or a terminal", req.ExecID, ht.id)
+		}
+		if outputPath != "" {
+			if ht.isWCOW {
+				return errors.Wrapf(errdefs.ErrNotImplemented, "exec: '%s' in task: '%s' detached output to a file is only supported for LCOW", req.ExecID, ht.id)
+			}
+			spec = detachedProcessSpec(spec, outputPath)
+		}
+		stdout = ""
+	}
+	if len(ht.execEnv) > 0 {
+		p := *spec
+		p.Env = append([]string(nil), spec.Env...)
+		hcsoci.AddEnv(&p, !ht.isWCOW, ht.execEnv)
+		spec = &p
+	}
+	io, err := newNpipeIO(ctx, ht.id, req.ExecID, req.Stdin, stdout, req.Stderr, req.Terminal)
 	if err != nil {
 		return err
 	}
-	he := newHcsExec(ctx, ht.events, ht.id, ht.host, ht.c, req.ExecID, ht.init.Status().Bundle, ht.isWCOW, spec, io)
+	he := newHcsExec(ctx, ht.events, ht.id, ht.host, ht.c, req.ExecID, ht.init.Status().Bundle, ht.isWCOW, spec, io, ht.killPolicy, ht.execLimits)
 	ht.execs.Store(req.ExecID, he)
 
 	// Publish the created event
@@ -306,6 +463,15 @@ func (ht *hcsTask) GetExec(eid string) (shimExec, error) {
 	return raw.(shimExec), nil
 }
 
+func (ht *hcsTask) ListExecs() []shimExec {
+	execs := []shimExec{ht.init}
+	ht.execs.Range(func(key, value interface{}) bool {
+		execs = append(execs, value.(shimExec))
+		return true
+	})
+	return execs
+}
+
 func (ht *hcsTask) KillExec(ctx context.Context, eid string, signal uint32, all bool) error {
 	logrus.WithFields(logrus.Fields{
 		"tid":    ht.id,
@@ -323,15 +489,27 @@ func (ht *hcsTask) KillExec(ctx context.Context, eid string, signal uint32, all
 	}
 	eg := errgroup.Group{}
 	if all {
-		// We are in a kill all on the init task. Signal everything.
+		// We are in a kill all on the init task. Signal everything that has
+		// not already exited. An exec that exits before it is signaled is
+		// not found which is not a failure of the kill all.
 		ht.execs.Range(func(key, value interface{}) bool {
 			ex := value.(shimExec)
+			if ex.State() == shimExecStateExited {
+				return true
+			}
 			eg.Go(func() error {
-				return ex.Kill(ctx, signal)
+				if err := ex.Kill(ctx, signal); err != nil && !errdefs.IsNotFound(err) {
+					return err
+				}
+				return nil
 			})
 
 			// iterate all
-			return false
+			return true
+		})
+		eg.Go(func() error {
+			ht.signalUntracked(ctx, signal)
+			return nil
 		})
 	} else if eid == "" {
 		// We are in a kill of the init task. Verify all exec's are in the
@@ -342,10 +520,10 @@ func (ht *hcsTask) KillExec(ctx context.Context, eid string, signal uint32, all
 			if ex.State() != shimExecStateExited {
 				invalid = true
 				// we have an invalid state. Stop iteration.
-				return true
+				return false
 			}
 			// iterate next valid
-			return false
+			return true
 		})
 		if invalid {
 			return errors.Wrap(errdefs.ErrFailedPrecondition, "cannot signal init exec with un-exited additional exec's")
@@ -371,6 +549,106 @@ func (ht *hcsTask) KillExec(ctx context.Context, eid string, signal uint32, all
 	return eg.Wait()
 }
 
+// processOpener is implemented by containers that can open a process in the
+// container by pid, including processes that were not started by the shim.
+type processOpener interface {
+	OpenProcess(pid int) (cow.Process, error)
+}
+
+// hcsProcessOpener opens the processes of an HCS compute system.
+type hcsProcessOpener struct {
+	s *hcs.System
+}
+
+func (o hcsProcessOpener) OpenProcess(pid int) (cow.Process, error) {
+	p, err := o.s.OpenProcess(pid)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// processOpenerOf returns the processOpener of `c` or `nil` if `c` cannot open
+// processes it did not start. This is the case for containers in the GCS,
+// which can only signal the processes it started. Their other processes are
+// signalled with killInHost.
+func processOpenerOf(c cow.Container) processOpener {
+	switch c := c.(type) {
+	case *hcs.System:
+		return hcsProcessOpener{c}
+	case processOpener:
+		return c
+	}
+	return nil
+}
+
+// killInHost delivers the Linux `signal` to `pids` by running kill in the
+// utility VM `host`. The GCS reports the pids of LCOW container processes in
+// the utility VM namespace so they can be signalled there.
+func killInHost(ctx context.Context, host cow.ProcessHost, pids []int, signal uint32) error {
+	args := []string{"-" + strconv.FormatUint(uint64(signal), 10)}
+	for _, pid := range pids {
+		args = append(args, strconv.Itoa(pid))
+	}
+	return hcsoci.CommandContext(ctx, host, "kill", args...).Run()
+}
+
+// signalUntracked delivers `signal` to every process in the container that is
+// not an exec of this task, such as the children of the execs. The pids are
+// host pids for process isolated and guest pids for hypervisor isolated
+// containers. Failures are logged as processes may exit concurrently.
+func (ht *hcsTask) signalUntracked(ctx context.Context, signal uint32) {
+	log := logrus.WithFields(logrus.Fields{
+		"tid":    ht.id,
+		"signal": signal,
+	})
+	if ht.c == nil {
+		return
+	}
+	tracked := map[int]struct{}{ht.init.Pid(): {}}
+	ht.execs.Range(func(key, value interface{}) bool {
+		tracked[value.(shimExec).Pid()] = struct{}{}
+		return true
+	})
+	props, err := ht.c.Properties(schema1.PropertyTypeProcessList)
+	if err != nil {
+		log.WithError(err).Warning("hcsTask::signalUntracked - failed to list processes")
+		return
+	}
+	var untracked []int
+	for _, p := range props.ProcessList {
+		if _, ok := tracked[int(p.ProcessId)]; !ok {
+			untracked = append(untracked, int(p.ProcessId))
+		}
+	}
+	if len(untracked) == 0 {
+		return
+	}
+	opener := processOpenerOf(ht.c)
+	if opener == nil {
+		if ht.isWCOW || ht.host == nil {
+			log.WithField("pids", untracked).Warning("hcsTask::signalUntracked - signalling processes not started by the shim is not supported by the container")
+			return
+		}
+		if err := killInHost(ctx, ht.host, untracked, signal); err != nil {
+			log.WithError(err).WithField("pids", untracked).Warning("hcsTask::signalUntracked - failed to signal processes in UVM")
+		}
+		return
+	}
+	for _, pid := range untracked {
+		plog := log.WithField("pid", pid)
+		proc, err := opener.OpenProcess(pid)
+		if err != nil {
+			plog.WithError(err).Debug("hcsTask::signalUntracked - failed to open process")
+			continue
+		}
+		if _, err := signalProcess(proc, signal, ht.isWCOW, ht.host); err != nil {
+			plog.WithError(err).Warning("hcsTask::signalUntracked - failed to signal process")
+		}
+		proc.Close()
+	}
+}
+
 func (ht *hcsTask) DeleteExec(ctx context.Context, eid string) (int, uint32, time.Time, error) {
 	logrus.WithFields(logrus.Fields{
 		"tid": ht.id,
@@ -393,10 +671,10 @@ func (ht *hcsTask) DeleteExec(ctx context.Context, eid string) (int, uint32, tim
 			case shimExecStateRunning:
 				invalid = true
 				// we have a running additional exec. Stop iteration.
-				return true
+				return false
 			}
 			// iterate next valid
-			return false
+			return true
 		})
 		if invalid {
 			return 0, 0, time.Time{}, errors.Wrap(errdefs.ErrFailedPrecondition, "cannot delete init exec with un-exited additional exec's")
@@ -439,7 +717,7 @@ func (ht *hcsTask) Pids(ctx context.Context) ([]options.ProcessDetails, error) {
 		pidMap[ex.Pid()] = ex.ID()
 
 		// Iterate all
-		return false
+		return true
 	})
 	pidMap[ht.init.Pid()] = ht.init.ID()
 
@@ -473,6 +751,45 @@ func (ht *hcsTask) Wait(ctx context.Context) *task.StateResponse {
 	return ht.init.Wait(ctx)
 }
 
+// oomNotifier is implemented by containers whose guest notifies the shim when
+// the OOM killer kills a process in the container.
+type oomNotifier interface {
+	OOMs() <-chan struct{}
+}
+
+// guestOOMNotifier returns the oomNotifier of the LCOW container `c` if the
+// guest of `host` sends OOM notifications. Otherwise `nil` is returned and
+// OOM kills are detected from the kernel log of `host` instead.
+func guestOOMNotifier(c cow.Container, host *uvm.UtilityVM) oomNotifier {
+	if host == nil || !host.OOMNotificationsSupported() {
+		return nil
+	}
+	n, _ := c.(oomNotifier)
+	return n
+}
+
+// waitForOOMs publishes a `TaskOOM` event each time `n` reports that a process
+// in this task was killed by the OOM killer. It returns once the container
+// terminates.
+//
+// This MUST be called via a goroutine.
+func (ht *hcsTask) waitForOOMs(n oomNotifier) {
+	for range n.OOMs() {
+		if !getConfig().featureEnabled(featureOOMEvents) {
+			continue
+		}
+		logrus.WithFields(logrus.Fields{
+			"tid":    ht.id,
+			"reason": "OOMKilled",
+		}).Warning("hcsTask::waitForOOMs - process was killed by the OOM killer")
+		ht.events(
+			runtime.TaskOOMEventTopic,
+			&eventstypes.TaskOOM{
+				ContainerID: ht.id,
+			})
+	}
+}
+
 // waitForHostExit waits for the host virtual machine to exit. Once exited
 // forcibly exits all additional exec's in this task.
 //
@@ -498,12 +815,52 @@ func (ht *hcsTask) waitForHostExit() {
 		ex.ForceExit(1)
 
 		// iterate all
-		return false
+		return true
 	})
 	ht.init.ForceExit(1)
 	ht.closeHost()
 }
 
+// drainExecs waits up to `ht.execDrainTimeout` for the execs that are running
+// after the init exec exited to exit, so that for example a debug shell can
+// complete rather than be killed by the container shutdown.
+func (ht *hcsTask) drainExecs() {
+	if ht.execDrainTimeout == 0 {
+		return
+	}
+	var running []shimExec
+	ht.execs.Range(func(key, value interface{}) bool {
+		ex := value.(shimExec)
+		if ex.State() == shimExecStateRunning {
+			running = append(running, ex)
+		}
+		return true
+	})
+	if len(running) == 0 {
+		return
+	}
+	log := logrus.WithFields(logrus.Fields{
+		"tid":     ht.id,
+		"execs":   len(running),
+		"timeout": ht.execDrainTimeout,
+	})
+	log.Debug("hcsTask::drainExecs - waiting for execs to exit")
+	done := make(chan struct{})
+	go func() {
+		for _, ex := range running {
+			ex.Wait(context.Background())
+		}
+		close(done)
+	}()
+	t := time.NewTimer(ht.execDrainTimeout)
+	defer t.Stop()
+	select {
+	case <-done:
+	case <-t.C:
+		log.Warning("hcsTask::drainExecs - timed out waiting for execs to exit, shutting down container")
+	}
+}
+
 // close shuts down the container that is owned by this task and if
 // `ht.ownsHost` will shutdown the hosting VM the container was placed in.
 //
@@ -582,7 +939,7 @@ func (ht *hcsTask) close() {
 			}
 
 			// Release any resources associated with the container.
-			if err := hcsoci.ReleaseResources(ht.cr, ht.host, true); err != nil {
+			if err := releaseResourcesWithRetry(ht.cr, ht.host); err != nil {
 				logrus.WithFields(logrus.Fields{
 					"tid":           ht.id,
 					logrus.ErrorKey: err,
@@ -633,9 +990,626 @@ func (ht *hcsTask) closeHost() {
 	})
 }
 
+// forwardEventLogs forwards the entries of the event log `channels` in the
+// container to the shim log until the init exec exits or the task is closed.
+func (ht *hcsTask) forwardEventLogs(channels []string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-ht.closed:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	go func() {
+		ht.init.Wait(ctx)
+		cancel()
+	}()
+	forwardEventLogs(ctx, ht.id, ht.c, channels)
+}
+
 func (ht *hcsTask) ExecInHost(ctx context.Context, req *shimdiag.ExecProcessRequest) (int, error) {
 	if ht.host == nil {
-		return 0, errors.New("task is not isolated")
+		return 0, errTaskNotIsolated
 	}
 	return execInUvm(ctx, ht.host, req)
 }
+
+func (ht *hcsTask) Share(ctx context.Context, req *shimdiag.ShareRequest) error {
+	if ht.host == nil {
+		return errTaskNotIsolated
+	}
+	return shareInUvm(ctx, ht.host, req)
+}
+
+func (ht *hcsTask) UpdateEndpoint(ctx context.Context, req *shimdiag.EndpointRequest) error {
+	if ht.host == nil {
+		return errTaskNotIsolated
+	}
+	nsid := ""
+	if ht.cr != nil {
+		nsid = ht.cr.NetworkNamespace()
+	}
+	return updateUvmEndpoint(ht.host, nsid, req)
+}
+
+func (ht *hcsTask) Capabilities() *shimdiag.TaskCapabilities {
+	caps := &shimdiag.TaskCapabilities{
+		Os:       "linux",
+		Isolated: ht.host != nil,
+		Signals:  osversion.Get().Build >= osversion.RS5,
+		Pause:    (ht.host != nil && ht.ownsHost) || (ht.host == nil && ht.isWCOW),
+		Stats:    true,
+		Update:   true,
+	}
+	if ht.isWCOW {
+		caps.Os = "windows"
+	}
+	if ht.host != nil {
+		caps.Signals = caps.Signals && ht.host.SignalProcessSupported()
+		caps.GcsProtocol = ht.host.Protocol()
+	}
+	return caps
+}
+
+func (ht *hcsTask) DumpGuestStacks(ctx context.Context) (string, error) {
+	if ht.host == nil {
+		return "", errTaskNotIsolated
+	}
+	return ht.host.DumpStacks(ctx)
+}
+
+func (ht *hcsTask) AttachHostConsole(ctx context.Context, req *shimdiag.ConsoleRequest) error {
+	if ht.host == nil {
+		return errTaskNotIsolated
+	}
+	return attachUvmConsole(ctx, ht.host, req)
+}
+
+func (ht *hcsTask) ExportRootfs(ctx context.Context, req *shimdiag.ExportRootfsRequest) error {
+	if ht.host == nil {
+		return errTaskNotIsolated
+	}
+	if ht.isWCOW {
+		return errors.Wrap(errdefs.ErrNotImplemented, "exporting the root file system is only supported for LCOW")
+	}
+	return exportUvmRootfs(ctx, ht.host, ht.cr.LCOWRootfsPathInUVM(), req)
+}
+
+func (ht *hcsTask) DiagState(ctx context.Context, eid string) (*shimdiag.TaskStateResponse, error) {
+	e, err := ht.GetExec(eid)
+	if err != nil {
+		return nil, err
+	}
+	resp := newDiagStateResponse(e.Status())
+	if ht.host != nil {
+		resp.HostID = ht.host.ID()
+	}
+	log := logrus.WithFields(logrus.Fields{
+		"tid": ht.id,
+		"eid": eid,
+	})
+
+	props, err := ht.c.Properties(schema1.PropertyTypeStatistics)
+	if err != nil {
+		// The snapshot is best effort. The container may have exited.
+		log.WithError(err).Warning("hcsTask::DiagState - failed to query statistics")
+	} else {
+		resp.MemoryUsageCommitBytes = props.Statistics.Memory.UsageCommitBytes
+		resp.MemoryUsagePrivateWorkingSetBytes = props.Statistics.Memory.UsagePrivateWorkingSetBytes
+		resp.ProcessorTotalRuntimeNs = props.Statistics.Processor.TotalRuntime100ns * 100
+	}
+
+	if ht.cr != nil {
+		for _, d := range ht.cr.AttachedDevices() {
+			resp.Devices = append(resp.Devices, &shimdiag.AttachedDevice{
+				Type: d.Type,
+				Path: d.Path,
+			})
+		}
+		resp.NetworkNamespace = ht.cr.NetworkNamespace()
+		resp.EndpointIDs = ht.cr.NetworkEndpoints()
+		if len(resp.EndpointIDs) == 0 && resp.NetworkNamespace != "" {
+			// The endpoints were added to the namespace by the caller rather
+			// than by the shim.
+			endpoints, err := hns.GetNamespaceEndpoints(resp.NetworkNamespace)
+			if err != nil {
+				log.WithError(err).Warning("hcsTask::DiagState - failed to query namespace endpoints")
+			}
+			resp.EndpointIDs = endpoints
+		}
+	}
+	return resp, nil
+}
+
+func (ht *hcsTask) Update(ctx context.Context, resources interface{}) error {
+	logrus.WithFields(logrus.Fields{
+		"tid": ht.id,
+	}).Debug("hcsTask::Update")
+
+	switch r := resources.(type) {
+	case *specs.WindowsResources:
+		if !ht.isWCOW {
+			return errors.Wrapf(errdefs.ErrInvalidArgument, "task: '%s' Windows resources cannot be applied to a Linux container", ht.id)
+		}
+		if ht.host == nil {
+			return ht.updateSilo(r)
+		}
+		return ht.updateIsolatedWCOW(r)
+	case *specs.LinuxResources:
+		if ht.isWCOW {
+			return errors.Wrapf(errdefs.ErrInvalidArgument, "task: '%s' Linux resources cannot be applied to a Windows container", ht.id)
+		}
+		if err := ht.modifyContainer(&hcsschema.ModifySettingRequest{
+			GuestRequest: guestrequest.GuestRequest{
+				ResourceType: guestrequest.ResourceTypeContainerConstraints,
+				RequestType:  requesttype.Update,
+				Settings: guestrequest.LCOWContainerConstraints{
+					Linux: *r,
+				},
+			},
+		}); err != nil {
+			return err
+		}
+		if !ht.ownsHost || ht.host == nil {
+			return nil
+		}
+		if mem := r.Memory; mem != nil && mem.Limit != nil && *mem.Limit > 0 {
+			if err := updateHostMemory(ht.host, uint64(*mem.Limit), ht.hostMemoryOverheadInMB); err != nil {
+				return err
+			}
+		}
+		return ht.updateHostProcessorCount(r.CPU)
+	}
+	return errors.Wrapf(errdefs.ErrInvalidArgument, "task: '%s' unsupported resources type '%T'", ht.id, resources)
+}
+
+// updateSilo applies `resources` to the silo job object of a process isolated
+// Windows container.
+func (ht *hcsTask) updateSilo(resources *specs.WindowsResources) error {
+	limits, err := jobLimitsFromResources(resources, goruntime.NumCPU())
+	if err != nil {
+		return errors.Wrapf(err, "task: '%s'", ht.id)
+	}
+	job, err := jobobject.Open(jobobject.SiloName(ht.id))
+	if err != nil {
+		return err
+	}
+	defer job.Close()
+	return job.SetLimits(limits)
+}
+
+// updateIsolatedWCOW applies `resources` to a hypervisor isolated Windows
+// container. If the task owns its UVM the vCPU limit and weight and the memory
+// of the UVM are updated as well so that the container can make use of them.
+func (ht *hcsTask) updateIsolatedWCOW(resources *specs.WindowsResources) error {
+	requests, err := containerUpdatesFromResources(resources)
+	if err != nil {
+		return errors.Wrapf(err, "task: '%s'", ht.id)
+	}
+	for _, r := range requests {
+		if err := ht.modifyContainer(r); err != nil {
+			return err
+		}
+	}
+	if cpu := resources.CPU; ht.ownsHost && cpu != nil && cpu.Count == nil {
+		// The values were range checked by `containerUpdatesFromResources`.
+		var limit, weight int32
+		if cpu.Maximum != nil {
+			limit = int32(*cpu.Maximum)
+		}
+		if cpu.Shares != nil {
+			weight = int32(*cpu.Shares)
+		}
+		if err := ht.host.UpdateProcessor(limit, weight); err != nil {
+			return err
+		}
+	}
+	if mem := resources.Memory; ht.ownsHost && mem != nil && mem.Limit != nil {
+		return updateHostMemory(ht.host, *mem.Limit, ht.hostMemoryOverheadInMB)
+	}
+	return nil
+}
+
+// updateHostProcessorCount hot adds or removes vCPUs of the UVM owned by the
+// task to match the CPU quota of `cpu`. If the UVM does not support it the vCPU
+// count is left as is.
+func (ht *hcsTask) updateHostProcessorCount(cpu *specs.LinuxCPU) error {
+	count := processorCountFromLinuxCPU(cpu, goruntime.NumCPU())
+	if count == 0 || count == ht.host.ProcessorCount() {
+		return nil
+	}
+	if !ht.host.ProcessorHotAddSupported() {
+		logrus.WithFields(logrus.Fields{
+			"tid":   ht.id,
+			"count": count,
+		}).Warning("hcsTask::Update - UVM does not support changing the processor count")
+		return nil
+	}
+	return wrapReservationError(ht.host.UpdateProcessorCount(count))
+}
+
+// modifyContainer sends the modify request `r` to the container of the task.
+func (ht *hcsTask) modifyContainer(r *hcsschema.ModifySettingRequest) error {
+	cs, ok := ht.c.(cow.ComputeSystem)
+	if !ok {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "task: '%s' container cannot be modified", ht.id)
+	}
+	return cs.Modify(r)
+}
+
+func (ht *hcsTask) Stats(ctx context.Context) (*stats.Statistics, error) {
+	logrus.WithFields(logrus.Fields{
+		"tid": ht.id,
+	}).Debug("hcsTask::Stats")
+
+	s := &stats.Statistics{}
+	if !ht.isWCOW && ht.host != nil && ht.host.CgroupMetricsSupported() {
+		props, err := ht.c.Properties(schema1.PropertyTypeCgroupMetrics)
+		if err != nil {
+			return nil, err
+		}
+		if s.Container, err = linuxStatistics(props); err != nil {
+			return nil, err
+		}
+	} else {
+		props, err := ht.c.Properties(schema1.PropertyTypeStatistics)
+		if err != nil {
+			return nil, err
+		}
+		s.Container = containerStatistics(&props.Statistics)
+	}
+	if ht.host != nil {
+		vm, err := hostStatistics(ht.host)
+		if err != nil {
+			return nil, err
+		}
+		s.Vm = vm
+	}
+	return s, nil
+}
+
+func (ht *hcsTask) Pause(ctx context.Context) error {
+	logrus.WithFields(logrus.Fields{
+		"tid": ht.id,
+	}).Debug("hcsTask::Pause")
+
+	if err := ht.setPaused(true); err != nil {
+		return err
+	}
+	ht.events(
+		runtime.TaskPausedEventTopic,
+		&eventstypes.TaskPaused{
+			ContainerID: ht.id,
+		})
+	return nil
+}
+
+func (ht *hcsTask) Resume(ctx context.Context) error {
+	logrus.WithFields(logrus.Fields{
+		"tid": ht.id,
+	}).Debug("hcsTask::Resume")
+
+	if err := ht.setPaused(false); err != nil {
+		return err
+	}
+	ht.events(
+		runtime.TaskResumedEventTopic,
+		&eventstypes.TaskResumed{
+			ContainerID: ht.id,
+		})
+	return nil
+}
+
+// setPaused suspends or resumes all processes in the task.
+//
+// Process isolated Windows containers are paused by freezing their silo job
+// object. Hypervisor isolated tasks are paused by pausing the hosting UVM
+// which is only possible if the task owns it, a UVM shared with other tasks
+// cannot be paused on behalf of one of them.
+func (ht *hcsTask) setPaused(pause bool) error {
+	if ht.host != nil && !ht.ownsHost {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "task: '%s' cannot be paused as it does not own its hosting UVM", ht.id)
+	}
+	if ht.host == nil && !ht.isWCOW {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "task: '%s' cannot be paused without a hosting UVM", ht.id)
+	}
+
+	ht.pm.Lock()
+	defer ht.pm.Unlock()
+	if state := ht.init.State(); state != shimExecStateRunning {
+		return newExecInvalidStateError(ht.id, "", state, "pause/resume")
+	}
+	if ht.paused == pause {
+		return errors.Wrapf(errdefs.ErrFailedPrecondition, "task: '%s' paused state is already: %t", ht.id, pause)
+	}
+	var err error
+	if ht.host != nil {
+		if pause {
+			err = ht.host.Pause()
+		} else {
+			err = ht.host.Resume()
+		}
+	} else {
+		err = setSiloPaused(ht.id, pause)
+	}
+	if err != nil {
+		return err
+	}
+	ht.paused = pause
+	return nil
+}
+
+// setSiloPaused freezes or thaws the silo job object of the process isolated
+// Windows container `id`. `errdefs.ErrNotImplemented` is returned if the host
+// does not support freezing job objects.
+func setSiloPaused(id string, pause bool) error {
+	job, err := jobobject.Open(jobobject.SiloName(id))
+	if err != nil {
+		return err
+	}
+	defer job.Close()
+	if pause {
+		err = job.Freeze()
+	} else {
+		err = job.Thaw()
+	}
+	if err == jobobject.ErrNotSupported {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "task: '%s' cannot be paused: %s", id, err)
+	}
+	return err
+}
+
+func (ht *hcsTask) Paused() bool {
+	ht.pm.Lock()
+	defer ht.pm.Unlock()
+	return ht.paused
+}
+
+const (
+	containerMemoryResourcePath     = "Container/Memory/SizeInMB"
+	containerProcessorResourcePath  = "Container/Processor"
+	containerStorageQoSResourcePath = "Container/Storage/QoS"
+)
+
+// maxProcessorMaximum and maxProcessorWeight are the largest processor
+// maximum and weight of a Windows container, in 1/100ths of a percent and
+// relative to other containers respectively.
+const (
+	maxProcessorMaximum = 10000
+	maxProcessorWeight  = 10000
+)
+
+// resourceInt32 returns the value `v` of the OCI resource `name` as the 32 bit
+// signed integer HCS expects. `errdefs.ErrInvalidArgument` is returned if `v`
+// is greater than `max`.
+func resourceInt32(name string, v, max uint64) (int32, error) {
+	if v > max {
+		return 0, errors.Wrapf(errdefs.ErrInvalidArgument, "%s %d exceeds maximum %d", name, v, max)
+	}
+	return int32(v), nil
+}
+
+// containerUpdatesFromResources converts the OCI Windows `resources` into the
+// HCS modify requests that apply them to a running hypervisor isolated
+// Windows container.
+func containerUpdatesFromResources(resources *specs.WindowsResources) ([]*hcsschema.ModifySettingRequest, error) {
+	var (
+		requests []*hcsschema.ModifySettingRequest
+		err      error
+	)
+	if cpu := resources.CPU; cpu != nil {
+		var p hcsschema.Processor
+		if cpu.Count != nil {
+			if p.Count, err = resourceInt32("cpu count", *cpu.Count, math.MaxInt32); err != nil {
+				return nil, err
+			}
+		}
+		if cpu.Maximum != nil {
+			if p.Maximum, err = resourceInt32("cpu maximum", uint64(*cpu.Maximum), maxProcessorMaximum); err != nil {
+				return nil, err
+			}
+		}
+		if cpu.Shares != nil {
+			if p.Weight, err = resourceInt32("cpu shares", uint64(*cpu.Shares), maxProcessorWeight); err != nil {
+				return nil, err
+			}
+		}
+		if p != (hcsschema.Processor{}) {
+			requests = append(requests, &hcsschema.ModifySettingRequest{
+				ResourcePath: containerProcessorResourcePath,
+				RequestType:  requesttype.Update,
+				Settings:     p,
+			})
+		}
+	}
+	if mem := resources.Memory; mem != nil && mem.Limit != nil {
+		sizeInMB := *mem.Limit / 1024 / 1024
+		if sizeInMB == 0 {
+			return nil, errors.Wrapf(errdefs.ErrInvalidArgument, "memory limit %d is less than 1MB", *mem.Limit)
+		}
+		requests = append(requests, &hcsschema.ModifySettingRequest{
+			ResourcePath: containerMemoryResourcePath,
+			RequestType:  requesttype.Update,
+			Settings:     sizeInMB,
+		})
+	}
+	if storage := resources.Storage; storage != nil {
+		if storage.SandboxSize != nil {
+			return nil, errors.Wrap(errdefs.ErrNotImplemented, "sandbox size cannot be updated")
+		}
+		var qos hcsschema.StorageQoS
+		if storage.Iops != nil {
+			if qos.IopsMaximum, err = resourceInt32("storage iops", *storage.Iops, math.MaxInt32); err != nil {
+				return nil, err
+			}
+		}
+		if storage.Bps != nil {
+			if qos.BandwidthMaximum, err = resourceInt32("storage bps", *storage.Bps, math.MaxInt32); err != nil {
+				return nil, err
+			}
+		}
+		if qos != (hcsschema.StorageQoS{}) {
+			requests = append(requests, &hcsschema.ModifySettingRequest{
+				ResourcePath: containerStorageQoSResourcePath,
+				RequestType:  requesttype.Update,
+				Settings:     qos,
+			})
+		}
+	}
+	return requests, nil
+}
+
+func (ht *hcsTask) SetCPUGroup(ctx context.Context, id string) error {
+	if ht.host == nil {
+		return errTaskNotIsolated
+	}
+	if !ht.ownsHost {
+		return errors.Wrapf(errdefs.ErrFailedPrecondition, "task: '%s' does not own its UVM", ht.id)
+	}
+	return setHostCPUGroup(ht.host, id)
+}
+
+func (ht *hcsTask) SaveUVM(ctx context.Context, path string) error {
+	if ht.host == nil {
+		return errTaskNotIsolated
+	}
+	if !ht.ownsHost {
+		return errors.Wrapf(errdefs.ErrFailedPrecondition, "task: '%s' does not own its UVM", ht.id)
+	}
+	return saveHost(ht.host, path)
+}
+
+// saveHost saves the state of `host` to the file `path`, failing with
+// `errdefs.ErrNotImplemented` if `host` is not a Windows UVM.
+func saveHost(host *uvm.UtilityVM, path string) error {
+	if host.OS() != "windows" {
+		return errors.Wrap(errdefs.ErrNotImplemented, "only Windows UVMs can be saved")
+	}
+	return host.Save(path)
+}
+
+// setHostCPUGroup assigns `host` to the CPU group `id`, failing with
+// `errdefs.ErrNotImplemented` if the host does not support CPU groups.
+func setHostCPUGroup(host *uvm.UtilityVM, id string) error {
+	err := host.SetCPUGroup(id)
+	if err == cpugroup.ErrNotSupported {
+		return errors.Wrap(errdefs.ErrNotImplemented, err.Error())
+	}
+	return err
+}
+
+// memoryLimitInMB returns the memory limit in `s` rounded up to the next MB,
+// or 0 if it has none.
+func memoryLimitInMB(s *specs.Spec) uint64 {
+	const mb = 1024 * 1024
+	var limit uint64
+	switch {
+	case s == nil:
+	case s.Windows != nil && s.Windows.Resources != nil && s.Windows.Resources.Memory != nil && s.Windows.Resources.Memory.Limit != nil:
+		limit = *s.Windows.Resources.Memory.Limit
+	case s.Linux != nil && s.Linux.Resources != nil && s.Linux.Resources.Memory != nil && s.Linux.Resources.Memory.Limit != nil && *s.Linux.Resources.Memory.Limit > 0:
+		limit = uint64(*s.Linux.Resources.Memory.Limit)
+	}
+	return (limit + mb - 1) / mb
+}
+
+// hostMemoryOverhead returns the memory in MB `host` was created with beyond
+// the memory limit in `s`, the spec it was created for. If `s` has no memory
+// limit all of the memory of `host` is overhead so that an update never
+// shrinks it below its size at create.
+func hostMemoryOverhead(host *uvm.UtilityVM, s *specs.Spec) int32 {
+	size := host.MemorySizeInMB()
+	limit := memoryLimitInMB(s)
+	if limit >= uint64(size) {
+		return 0
+	}
+	return size - int32(limit)
+}
+
+// hostMemoryFromLimit converts the memory limit `limitInBytes` of a task into
+// the size in MB of its hosting UVM, rounded up to the next MB plus the
+// `overheadInMB` the UVM needs for itself.
+func hostMemoryFromLimit(limitInBytes uint64, overheadInMB int32) (int32, error) {
+	const mb = 1024 * 1024
+	sizeInMB := (limitInBytes + mb - 1) / mb
+	if sizeInMB == 0 || sizeInMB+uint64(overheadInMB) > math.MaxInt32 {
+		return 0, errors.Wrapf(errdefs.ErrInvalidArgument, "invalid memory limit %d", limitInBytes)
+	}
+	return int32(sizeInMB) + overheadInMB, nil
+}
+
+// updateHostMemory resizes the memory of `host` to `limitInBytes` plus
+// `overheadInMB`. This lets a pod grow or shrink without recreating its UVM.
+func updateHostMemory(host *uvm.UtilityVM, limitInBytes uint64, overheadInMB int32) error {
+	sizeInMB, err := hostMemoryFromLimit(limitInBytes, overheadInMB)
+	if err != nil {
+		return err
+	}
+	return wrapReservationError(host.UpdateMemory(sizeInMB))
+}
+
+// processorCountFromLinuxCPU returns the number of processors needed to
+// satisfy the CPU quota of `cpu` on a host with `numCPU` processors. If `cpu`
+// has no quota returns `0`.
+func processorCountFromLinuxCPU(cpu *specs.LinuxCPU, numCPU int) int32 {
+	if cpu == nil || cpu.Quota == nil || cpu.Period == nil || *cpu.Quota <= 0 || *cpu.Period == 0 {
+		return 0
+	}
+	quota, period := uint64(*cpu.Quota), *cpu.Period
+	count := (quota + period - 1) / period
+	if count > uint64(numCPU) {
+		count = uint64(numCPU)
+	}
+	return int32(count)
+}
+
+// jobLimitsFromResources converts the OCI Windows `resources` into job object
+// limits on a host with `numCPU` processors.
+func jobLimitsFromResources(resources *specs.WindowsResources, numCPU int) (jobobject.Limits, error) {
+	var limits jobobject.Limits
+	if cpu := resources.CPU; cpu != nil {
+		switch {
+		case cpu.Maximum != nil:
+			limits.CPURate = uint32(*cpu.Maximum)
+		case cpu.Count != nil && *cpu.Count >= uint64(numCPU):
+			limits.CPURate = jobobject.CPURateMax
+		case cpu.Count != nil:
+			// Convert the processor count to a hard cap of the same share of
+			// all host processors.
+			limits.CPURate = uint32(*cpu.Count * jobobject.CPURateMax / uint64(numCPU))
+		case cpu.Shares != nil:
+			// Shares are in the range 0 to 10000 and weights 1 to 9.
+			limits.CPUWeight = 1 + uint32(*cpu.Shares)*8/10000
+		}
+		if limits.CPURate > jobobject.CPURateMax {
+			limits.CPURate = jobobject.CPURateMax
+		} else if (cpu.Maximum != nil || cpu.Count != nil) && limits.CPURate == 0 {
+			limits.CPURate = 1
+		}
+	}
+	if mem := resources.Memory; mem != nil && mem.Limit != nil {
+		// The container memory limit is the commit of all processes in the
+		// silo, not the working set of each.
+		limits.MaxCommit = *mem.Limit
+	}
+	if storage := resources.Storage; storage != nil {
+		if storage.SandboxSize != nil {
+			return limits, errors.Wrap(errdefs.ErrNotImplemented, "sandbox size cannot be updated")
+		}
+		if storage.Iops != nil {
+			if *storage.Iops > math.MaxInt64 {
+				return limits, errors.Wrapf(errdefs.ErrInvalidArgument, "storage iops %d exceeds maximum %d", *storage.Iops, int64(math.MaxInt64))
+			}
+			limits.MaxIops = int64(*storage.Iops)
+		}
+		if storage.Bps != nil {
+			if *storage.Bps > math.MaxInt64 {
+				return limits, errors.Wrapf(errdefs.ErrInvalidArgument, "storage bps %d exceeds maximum %d", *storage.Bps, int64(math.MaxInt64))
+			}
+			limits.MaxBandwidth = int64(*storage.Bps)
+		}
+	}
+	return limits, nil
+}
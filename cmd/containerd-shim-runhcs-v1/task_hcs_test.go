@@ -2,12 +2,28 @@ package main
 
 import (
 	"context"
+	"math"
 	"math/rand"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/Microsoft/hcsshim/internal/cow"
+	"github.com/Microsoft/hcsshim/internal/cow/cowtest"
+	"github.com/Microsoft/hcsshim/internal/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/jobobject"
+	"github.com/Microsoft/hcsshim/internal/requesttype"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	eventstypes "github.com/containerd/containerd/api/events"
 	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/runtime"
+	"github.com/containerd/containerd/runtime/v2/task"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func setupTestHcsTask(t *testing.T) (*hcsTask, *testShimExec, *testShimExec) {
@@ -95,6 +111,45 @@ func Test_hcsTask_KillExec_InitExecID_All_Success(t *testing.T) {
 	}
 }
 
+// notFoundShimExec is an exec that is not found when it is signaled, as an
+// `hcsExec` that exited is.
+type notFoundShimExec struct {
+	*testShimExec
+}
+
+func (e notFoundShimExec) Kill(ctx context.Context, signal uint32) error {
+	e.signals = append(e.signals, signal)
+	return errors.Wrapf(errdefs.ErrNotFound, "exec: '%s' in task: '%s' not found", e.id, e.tid)
+}
+
+func Test_hcsTask_KillExec_InitExecID_All_ExitedExec_Success(t *testing.T) {
+	lt, init, second := setupTestHcsTask(t)
+	exited := newTestShimExec(t.Name(), "exited", int(rand.Int31()))
+	exited.state = shimExecStateExited
+	lt.execs.Store(exited.id, notFoundShimExec{exited})
+	// An exec that exits after it was checked is also not found.
+	exiting := newTestShimExec(t.Name(), "exiting", int(rand.Int31()))
+	exiting.state = shimExecStateRunning
+	lt.execs.Store(exiting.id, notFoundShimExec{exiting})
+
+	err := lt.KillExec(context.TODO(), "", 0xf, true)
+	if err != nil {
+		t.Fatalf("should not have failed, got: %v", err)
+	}
+	if init.state != shimExecStateExited {
+		t.Fatalf("init should be in exited state got: %v", init.state)
+	}
+	if second.state != shimExecStateExited {
+		t.Fatalf("2nd exec should be in exited state got: %v", second.state)
+	}
+	if len(exited.signals) != 0 {
+		t.Fatalf("exited exec should not have been signaled, got: %v", exited.signals)
+	}
+	if len(exiting.signals) != 1 {
+		t.Fatalf("exiting exec should have been signaled, got: %v", exiting.signals)
+	}
+}
+
 func Test_hcsTask_KillExec_2ndExecID_Success(t *testing.T) {
 	lt, _, second := setupTestHcsTask(t)
 
@@ -286,3 +341,411 @@ func Test_hcsTask_DeleteExec_2ndExecID_ExitedState_Success(t *testing.T) {
 	}
 	verifyDeleteSuccessValues(t, pid, status, at, second)
 }
+
+func Test_hcsTask_CreateExec_MaxExecs_Error(t *testing.T) {
+	lt, init, second := setupTestHcsTask(t)
+	init.state = shimExecStateRunning
+	second.state = shimExecStateRunning
+	lt.maxExecs = 1
+
+	err := lt.CreateExec(context.TODO(), &task.ExecProcessRequest{ExecID: "third"}, nil)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got: %v", err)
+	}
+}
+
+func Test_hcsTask_CreateExec_Paused_Error(t *testing.T) {
+	lt, init, _ := setupTestHcsTask(t)
+	init.state = shimExecStateRunning
+	lt.paused = true
+
+	err := lt.CreateExec(context.TODO(), &task.ExecProcessRequest{ExecID: "third"}, nil)
+	if errors.Cause(err) != errdefs.ErrFailedPrecondition {
+		t.Fatalf("expected ErrFailedPrecondition, got: %v", err)
+	}
+}
+
+func Test_hcsTask_CreateExec_Detached_Stdin_Error(t *testing.T) {
+	lt, init, _ := setupTestHcsTask(t)
+	init.state = shimExecStateRunning
+
+	err := lt.CreateExec(context.TODO(), &task.ExecProcessRequest{ExecID: "third", Stdin: `\\.\pipe\stdin`, Stdout: "detached://"}, &specs.Process{})
+	if errors.Cause(err) != errdefs.ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument, got: %v", err)
+	}
+}
+
+func Test_hcsTask_Update_LCOW_WindowsResources_Error(t *testing.T) {
+	lt, _, _ := setupTestHcsTask(t)
+	// Test tasks are not WCOW so Windows resources do not apply.
+	err := lt.Update(context.TODO(), &specs.WindowsResources{})
+	if errors.Cause(err) != errdefs.ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument, got: %v", err)
+	}
+}
+
+func Test_hcsTask_Update_LCOW_Success(t *testing.T) {
+	lt, _, _ := setupTestHcsTask(t)
+	c := cowtest.NewContainer(t.Name(), "linux", true)
+	lt.c = c
+	limit := int64(1024 * 1024 * 512)
+
+	if err := lt.Update(context.TODO(), &specs.LinuxResources{Memory: &specs.LinuxMemory{Limit: &limit}}); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	m := c.Modifies()
+	if len(m) != 1 {
+		t.Fatalf("expected 1 modify request, got: %d", len(m))
+	}
+	gr := m[0].(*hcsschema.ModifySettingRequest).GuestRequest.(guestrequest.GuestRequest)
+	if gr.ResourceType != guestrequest.ResourceTypeContainerConstraints || gr.RequestType != requesttype.Update {
+		t.Fatalf("unexpected guest request: %+v", gr)
+	}
+	if l := gr.Settings.(guestrequest.LCOWContainerConstraints).Linux.Memory.Limit; *l != limit {
+		t.Fatalf("expected memory limit %d, got: %d", limit, *l)
+	}
+}
+
+func Test_hcsTask_Pause_NoHost_NotImplemented(t *testing.T) {
+	lt, _, _ := setupTestHcsTask(t)
+	// Test tasks are not WCOW and have no hosting UVM so there is nothing to
+	// pause.
+
+	if err := lt.Pause(context.TODO()); errors.Cause(err) != errdefs.ErrNotImplemented {
+		t.Fatalf("expected ErrNotImplemented, got: %v", err)
+	}
+	if err := lt.Resume(context.TODO()); errors.Cause(err) != errdefs.ErrNotImplemented {
+		t.Fatalf("expected ErrNotImplemented, got: %v", err)
+	}
+}
+
+func Test_hcsTask_Pause_SharedHost_NotImplemented(t *testing.T) {
+	lt, init, _ := setupTestHcsTask(t)
+	init.state = shimExecStateRunning
+	lt.host = &uvm.UtilityVM{}
+
+	if err := lt.Pause(context.TODO()); errors.Cause(err) != errdefs.ErrNotImplemented {
+		t.Fatalf("expected ErrNotImplemented, got: %v", err)
+	}
+	if lt.Paused() {
+		t.Fatal("task should not be paused")
+	}
+}
+
+func Test_hcsTask_Resume_NotPaused_Error(t *testing.T) {
+	lt, init, _ := setupTestHcsTask(t)
+	init.state = shimExecStateRunning
+	lt.isWCOW = true
+
+	if err := lt.Resume(context.TODO()); errors.Cause(err) != errdefs.ErrFailedPrecondition {
+		t.Fatalf("expected ErrFailedPrecondition, got: %v", err)
+	}
+}
+
+type testOOMNotifier chan struct{}
+
+func (n testOOMNotifier) OOMs() <-chan struct{} {
+	return n
+}
+
+func Test_hcsTask_waitForOOMs_PublishesTaskOOM(t *testing.T) {
+	lt, _, _ := setupTestHcsTask(t)
+	var topics []string
+	lt.events = func(topic string, event interface{}) {
+		oom, ok := event.(*eventstypes.TaskOOM)
+		if !ok || oom.ContainerID != t.Name() {
+			t.Errorf("unexpected event: %+v", event)
+		}
+		topics = append(topics, topic)
+	}
+	n := make(testOOMNotifier, 1)
+	done := make(chan struct{})
+	go func() {
+		lt.waitForOOMs(n)
+		close(done)
+	}()
+	n <- struct{}{}
+	n <- struct{}{}
+	close(n)
+	<-done
+
+	if len(topics) != 2 {
+		t.Fatalf("expected 2 events, got: %d", len(topics))
+	}
+	for _, topic := range topics {
+		if topic != runtime.TaskOOMEventTopic {
+			t.Fatalf("expected topic %s, got: %s", runtime.TaskOOMEventTopic, topic)
+		}
+	}
+}
+
+func Test_jobLimitsFromResources(t *testing.T) {
+	max := uint16(2500)
+	count := uint64(2)
+	shares := uint16(10000)
+	limit := uint64(1024 * 1024 * 512)
+	iops := uint64(100)
+	bps := uint64(1024 * 1024)
+
+	l, err := jobLimitsFromResources(&specs.WindowsResources{
+		CPU:     &specs.WindowsCPUResources{Maximum: &max},
+		Memory:  &specs.WindowsMemoryResources{Limit: &limit},
+		Storage: &specs.WindowsStorageResources{Iops: &iops, Bps: &bps},
+	}, 4)
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if l.CPURate != 2500 || l.MaxCommit != limit || l.MaxWorkingSet != 0 || l.MaxIops != 100 || l.MaxBandwidth != int64(bps) {
+		t.Fatalf("unexpected limits: %+v", l)
+	}
+
+	l, err = jobLimitsFromResources(&specs.WindowsResources{CPU: &specs.WindowsCPUResources{Count: &count}}, 4)
+	if err != nil || l.CPURate != 5000 {
+		t.Fatalf("expected rate 5000, got: %+v, %v", l, err)
+	}
+
+	l, err = jobLimitsFromResources(&specs.WindowsResources{CPU: &specs.WindowsCPUResources{Shares: &shares}}, 4)
+	if err != nil || l.CPUWeight != 9 || l.CPURate != 0 {
+		t.Fatalf("expected weight 9, got: %+v, %v", l, err)
+	}
+}
+
+func Test_containerUpdatesFromResources(t *testing.T) {
+	max := uint16(2500)
+	limit := uint64(1024 * 1024 * 512)
+	iops := uint64(100)
+
+	requests, err := containerUpdatesFromResources(&specs.WindowsResources{
+		CPU:     &specs.WindowsCPUResources{Maximum: &max},
+		Memory:  &specs.WindowsMemoryResources{Limit: &limit},
+		Storage: &specs.WindowsStorageResources{Iops: &iops},
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, got: %d", len(requests))
+	}
+	if r := requests[0]; r.ResourcePath != containerProcessorResourcePath || r.Settings.(hcsschema.Processor) != (hcsschema.Processor{Maximum: 2500}) {
+		t.Fatalf("unexpected processor request: %+v", r)
+	}
+	if r := requests[1]; r.ResourcePath != containerMemoryResourcePath || r.Settings.(uint64) != 512 {
+		t.Fatalf("unexpected memory request: %+v", r)
+	}
+	if r := requests[2]; r.ResourcePath != containerStorageQoSResourcePath || r.Settings.(hcsschema.StorageQoS) != (hcsschema.StorageQoS{IopsMaximum: 100}) {
+		t.Fatalf("unexpected storage request: %+v", r)
+	}
+
+	requests, err = containerUpdatesFromResources(&specs.WindowsResources{CPU: &specs.WindowsCPUResources{}})
+	if err != nil || len(requests) != 0 {
+		t.Fatalf("expected no requests, got: %+v, %v", requests, err)
+	}
+}
+
+func Test_containerUpdatesFromResources_Unsupported(t *testing.T) {
+	size := uint64(1)
+	_, err := containerUpdatesFromResources(&specs.WindowsResources{Storage: &specs.WindowsStorageResources{SandboxSize: &size}})
+	if errors.Cause(err) != errdefs.ErrNotImplemented {
+		t.Fatalf("expected ErrNotImplemented, got: %v", err)
+	}
+	limit := uint64(1024)
+	_, err = containerUpdatesFromResources(&specs.WindowsResources{Memory: &specs.WindowsMemoryResources{Limit: &limit}})
+	if errors.Cause(err) != errdefs.ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument, got: %v", err)
+	}
+}
+
+func Test_containerUpdatesFromResources_OutOfRange(t *testing.T) {
+	count := uint64(math.MaxInt32 + 1)
+	max := uint16(maxProcessorMaximum + 1)
+	shares := uint16(maxProcessorWeight + 1)
+	large := uint64(math.MaxUint64)
+	for _, resources := range []*specs.WindowsResources{
+		{CPU: &specs.WindowsCPUResources{Count: &count}},
+		{CPU: &specs.WindowsCPUResources{Maximum: &max}},
+		{CPU: &specs.WindowsCPUResources{Shares: &shares}},
+		{Storage: &specs.WindowsStorageResources{Iops: &large}},
+		{Storage: &specs.WindowsStorageResources{Bps: &large}},
+	} {
+		requests, err := containerUpdatesFromResources(resources)
+		if errors.Cause(err) != errdefs.ErrInvalidArgument {
+			t.Fatalf("expected ErrInvalidArgument, got: %+v, %v", requests, err)
+		}
+	}
+}
+
+func Test_jobLimitsFromResources_OutOfRange(t *testing.T) {
+	count := uint64(math.MaxUint64)
+	l, err := jobLimitsFromResources(&specs.WindowsResources{CPU: &specs.WindowsCPUResources{Count: &count}}, 4)
+	if err != nil || l.CPURate != jobobject.CPURateMax {
+		t.Fatalf("expected the maximum cpu rate, got: %+v, %v", l, err)
+	}
+	large := uint64(math.MaxUint64)
+	_, err = jobLimitsFromResources(&specs.WindowsResources{Storage: &specs.WindowsStorageResources{Iops: &large}}, 4)
+	if errors.Cause(err) != errdefs.ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument, got: %v", err)
+	}
+	_, err = jobLimitsFromResources(&specs.WindowsResources{Storage: &specs.WindowsStorageResources{Bps: &large}}, 4)
+	if errors.Cause(err) != errdefs.ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument, got: %v", err)
+	}
+}
+
+func Test_jobLimitsFromResources_Unsupported(t *testing.T) {
+	size := uint64(1)
+	_, err := jobLimitsFromResources(&specs.WindowsResources{Storage: &specs.WindowsStorageResources{SandboxSize: &size}}, 4)
+	if errors.Cause(err) != errdefs.ErrNotImplemented {
+		t.Fatalf("expected ErrNotImplemented, got: %v", err)
+	}
+}
+
+func Test_hostMemoryFromLimit(t *testing.T) {
+	const mb = 1024 * 1024
+	tests := []struct {
+		limit    uint64
+		overhead int32
+		expected int32
+	}{
+		{limit: 1, expected: 1},
+		{limit: mb, expected: 1},
+		{limit: mb + 1, expected: 2},
+		{limit: 2048 * mb, expected: 2048},
+		// The UVM keeps the memory it was created with beyond the limit.
+		{limit: 512 * mb, overhead: 256, expected: 768},
+		{limit: 2048 * mb, overhead: 256, expected: 2304},
+	}
+	for _, test := range tests {
+		sizeInMB, err := hostMemoryFromLimit(test.limit, test.overhead)
+		if err != nil {
+			t.Fatalf("limit %d: expected nil error, got: %v", test.limit, err)
+		}
+		if sizeInMB != test.expected {
+			t.Fatalf("limit %d: expected %dMB, got: %dMB", test.limit, test.expected, sizeInMB)
+		}
+	}
+	for _, limit := range []uint64{0, math.MaxUint64} {
+		if _, err := hostMemoryFromLimit(limit, 0); errors.Cause(err) != errdefs.ErrInvalidArgument {
+			t.Fatalf("limit %d: expected ErrInvalidArgument, got: %v", limit, err)
+		}
+	}
+	if _, err := hostMemoryFromLimit(uint64(math.MaxInt32)*mb, 1); errors.Cause(err) != errdefs.ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument for limit plus overhead overflow, got: %v", err)
+	}
+}
+
+func Test_memoryLimitInMB(t *testing.T) {
+	const mb = 1024 * 1024
+	u := func(v uint64) *uint64 { return &v }
+	i := func(v int64) *int64 { return &v }
+	tests := []struct {
+		s        *specs.Spec
+		expected uint64
+	}{
+		{s: nil, expected: 0},
+		{s: &specs.Spec{}, expected: 0},
+		{s: &specs.Spec{Windows: &specs.Windows{Resources: &specs.WindowsResources{Memory: &specs.WindowsMemoryResources{Limit: u(512*mb + 1)}}}}, expected: 513},
+		{s: &specs.Spec{Linux: &specs.Linux{Resources: &specs.LinuxResources{Memory: &specs.LinuxMemory{Limit: i(256 * mb)}}}}, expected: 256},
+		{s: &specs.Spec{Linux: &specs.Linux{Resources: &specs.LinuxResources{Memory: &specs.LinuxMemory{Limit: i(-1)}}}}, expected: 0},
+	}
+	for i, test := range tests {
+		if limit := memoryLimitInMB(test.s); limit != test.expected {
+			t.Fatalf("%d: expected %dMB, got: %dMB", i, test.expected, limit)
+		}
+	}
+}
+
+func Test_processorCountFromLinuxCPU(t *testing.T) {
+	i := func(v int64) *int64 { return &v }
+	u := func(v uint64) *uint64 { return &v }
+	tests := []struct {
+		cpu      *specs.LinuxCPU
+		expected int32
+	}{
+		{cpu: nil, expected: 0},
+		{cpu: &specs.LinuxCPU{Shares: u(1024)}, expected: 0},
+		{cpu: &specs.LinuxCPU{Quota: i(-1), Period: u(100000)}, expected: 0},
+		{cpu: &specs.LinuxCPU{Quota: i(50000), Period: u(100000)}, expected: 1},
+		{cpu: &specs.LinuxCPU{Quota: i(200000), Period: u(100000)}, expected: 2},
+		{cpu: &specs.LinuxCPU{Quota: i(250000), Period: u(100000)}, expected: 3},
+		{cpu: &specs.LinuxCPU{Quota: i(1000000), Period: u(100000)}, expected: 4},
+	}
+	for _, test := range tests {
+		if count := processorCountFromLinuxCPU(test.cpu, 4); count != test.expected {
+			t.Fatalf("cpu %+v: expected %d processors, got: %d", test.cpu, test.expected, count)
+		}
+	}
+}
+
+func Test_hcsTask_drainExecs_WaitsForRunningExecs(t *testing.T) {
+	lt, _, second := setupTestHcsTask(t)
+	lt.execDrainTimeout = time.Minute
+	second.state = shimExecStateRunning
+	second.waiting = make(chan struct{})
+	second.exited = make(chan struct{})
+
+	done := make(chan struct{})
+	go func() {
+		lt.drainExecs()
+		close(done)
+	}()
+	<-second.waiting
+	select {
+	case <-done:
+		t.Fatal("drainExecs should wait for the running exec")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(second.exited)
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("drainExecs should return once the exec exits")
+	}
+}
+
+func Test_hcsTask_drainExecs_Timeout(t *testing.T) {
+	lt, _, second := setupTestHcsTask(t)
+	lt.execDrainTimeout = 10 * time.Millisecond
+	second.state = shimExecStateRunning
+	second.waiting = make(chan struct{})
+	second.exited = make(chan struct{})
+	defer close(second.exited)
+
+	done := make(chan struct{})
+	go func() {
+		lt.drainExecs()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("drainExecs should return once the timeout expires")
+	}
+}
+
+func Test_killInHost(t *testing.T) {
+	var args []string
+	host := cowtest.NewContainer("uvm", "linux", false)
+	host.OnCreateProcess = func(c *cowtest.Container, config interface{}) (cow.Process, error) {
+		args = config.(*hcsschema.ProcessParameters).CommandArgs
+		p := cowtest.NewProcess(1)
+		p.Exit(0, nil)
+		return p, nil
+	}
+
+	if err := killInHost(context.TODO(), host, []int{7, 8}, 15); err != nil {
+		t.Fatalf("should not have failed with error, got: %v", err)
+	}
+	expected := []string{"kill", "-15", "7", "8"}
+	if strings.Join(args, " ") != strings.Join(expected, " ") {
+		t.Fatalf("expected args %v, got: %v", expected, args)
+	}
+}
+
+func Test_guestOOMNotifier_NoHost(t *testing.T) {
+	// Process isolated containers have no guest to notify OOMs even if the
+	// container implements oomNotifier.
+	c := cowtest.NewContainer(t.Name(), "linux", true)
+	if n := guestOOMNotifier(c, nil); n != nil {
+		t.Fatalf("expected no notifier without a host, got: %v", n)
+	}
+}
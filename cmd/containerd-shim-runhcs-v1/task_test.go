@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/options"
+	"github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/stats"
 	"github.com/Microsoft/hcsshim/internal/shimdiag"
 	"github.com/containerd/containerd/errdefs"
 	"github.com/containerd/containerd/runtime/v2/task"
@@ -40,6 +41,14 @@ func (tst *testShimTask) GetExec(eid string) (shimExec, error) {
 	return nil, errdefs.ErrNotFound
 }
 
+func (tst *testShimTask) ListExecs() []shimExec {
+	execs := []shimExec{tst.exec}
+	for _, e := range tst.execs {
+		execs = append(execs, e)
+	}
+	return execs
+}
+
 func (tst *testShimTask) KillExec(ctx context.Context, eid string, signal uint32, all bool) error {
 	e, err := tst.GetExec(eid)
 	if err != nil {
@@ -83,3 +92,68 @@ func (tst *testShimTask) Wait(ctx context.Context) *task.StateResponse {
 func (tst *testShimTask) ExecInHost(ctx context.Context, req *shimdiag.ExecProcessRequest) (int, error) {
 	return 0, errors.New("not implemented")
 }
+
+func (tst *testShimTask) Share(ctx context.Context, req *shimdiag.ShareRequest) error {
+	return errors.New("not implemented")
+}
+
+func (tst *testShimTask) UpdateEndpoint(ctx context.Context, req *shimdiag.EndpointRequest) error {
+	return errors.New("not implemented")
+}
+
+func (tst *testShimTask) Capabilities() *shimdiag.TaskCapabilities {
+	return &shimdiag.TaskCapabilities{
+		Os:     "windows",
+		Pause:  true,
+		Stats:  true,
+		Update: true,
+	}
+}
+
+func (tst *testShimTask) DumpGuestStacks(ctx context.Context) (string, error) {
+	return "", errTaskNotIsolated
+}
+
+func (tst *testShimTask) AttachHostConsole(ctx context.Context, req *shimdiag.ConsoleRequest) error {
+	return errors.New("not implemented")
+}
+
+func (tst *testShimTask) ExportRootfs(ctx context.Context, req *shimdiag.ExportRootfsRequest) error {
+	return errors.New("not implemented")
+}
+
+func (tst *testShimTask) DiagState(ctx context.Context, eid string) (*shimdiag.TaskStateResponse, error) {
+	e, err := tst.GetExec(eid)
+	if err != nil {
+		return nil, err
+	}
+	return newDiagStateResponse(e.Status()), nil
+}
+
+func (tst *testShimTask) Update(ctx context.Context, resources interface{}) error {
+	return nil
+}
+
+func (tst *testShimTask) SetCPUGroup(ctx context.Context, id string) error {
+	return nil
+}
+
+func (tst *testShimTask) SaveUVM(ctx context.Context, path string) error {
+	return nil
+}
+
+func (tst *testShimTask) Stats(ctx context.Context) (*stats.Statistics, error) {
+	return &stats.Statistics{}, nil
+}
+
+func (tst *testShimTask) Pause(ctx context.Context) error {
+	return nil
+}
+
+func (tst *testShimTask) Resume(ctx context.Context) error {
+	return nil
+}
+
+func (tst *testShimTask) Paused() bool {
+	return false
+}
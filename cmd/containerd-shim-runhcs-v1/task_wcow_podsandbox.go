@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/options"
+	"github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/stats"
 	"github.com/Microsoft/hcsshim/internal/shimdiag"
 	"github.com/Microsoft/hcsshim/internal/uvm"
 	eventstypes "github.com/containerd/containerd/api/events"
@@ -25,7 +26,7 @@ import (
 // It is assumed that this is the only fake WCOW task and that this task owns
 // `parent`. When the fake WCOW `init` process exits via `Signal` `parent` will
 // be forcibly closed by this task.
-func newWcowPodSandboxTask(ctx context.Context, events publisher, id, bundle string, parent *uvm.UtilityVM) shimTask {
+func newWcowPodSandboxTask(ctx context.Context, events publisher, id, bundle string, parent *uvm.UtilityVM, nsid string, hostMemoryOverheadInMB int32) shimTask {
 	logrus.WithFields(logrus.Fields{
 		"tid": id,
 	}).Debug("newWcowPodSandboxTask")
@@ -35,7 +36,10 @@ func newWcowPodSandboxTask(ctx context.Context, events publisher, id, bundle str
 		id:     id,
 		init:   newWcowPodSandboxExec(ctx, events, id, bundle),
 		host:   parent,
+		nsid:   nsid,
 		closed: make(chan struct{}),
+
+		hostMemoryOverheadInMB: hostMemoryOverheadInMB,
 	}
 	if parent != nil {
 		// We have (and own) a parent UVM. Listen for its exit and forcibly
@@ -98,6 +102,17 @@ type wcowPodSandboxTask struct {
 	// host is the hosting VM for this task if hypervisor isolated. If
 	// `host==nil` this is an Argon task so no UVM cleanup is required.
 	host *uvm.UtilityVM
+	// nsid is the network namespace of the pod in `host`. It is empty if the
+	// pod has no network.
+	//
+	// It MUST be treated as read only in the lifetime of the task.
+	nsid string
+	// hostMemoryOverheadInMB is the memory `host` was created with beyond the
+	// memory limit of the pod. A memory limit update resizes `host` to the
+	// new limit plus this overhead.
+	//
+	// It MUST be treated as read only in the lifetime of the task.
+	hostMemoryOverheadInMB int32
 
 	closed    chan struct{}
 	closeOnce sync.Once
@@ -124,6 +139,10 @@ func (wpst *wcowPodSandboxTask) GetExec(eid string) (shimExec, error) {
 	return nil, errors.Wrapf(errdefs.ErrNotFound, "exec: '%s' in task: '%s' not found", eid, wpst.id)
 }
 
+func (wpst *wcowPodSandboxTask) ListExecs() []shimExec {
+	return []shimExec{wpst.init}
+}
+
 func (wpst *wcowPodSandboxTask) KillExec(ctx context.Context, eid string, signal uint32, all bool) error {
 	logrus.WithFields(logrus.Fields{
 		"tid":    wpst.id,
@@ -233,7 +252,128 @@ func (wpst *wcowPodSandboxTask) close() {
 
 func (wpst *wcowPodSandboxTask) ExecInHost(ctx context.Context, req *shimdiag.ExecProcessRequest) (int, error) {
 	if wpst.host == nil {
-		return 0, errors.New("task is not isolated")
+		return 0, errTaskNotIsolated
 	}
 	return execInUvm(ctx, wpst.host, req)
 }
+
+func (wpst *wcowPodSandboxTask) Share(ctx context.Context, req *shimdiag.ShareRequest) error {
+	if wpst.host == nil {
+		return errTaskNotIsolated
+	}
+	return shareInUvm(ctx, wpst.host, req)
+}
+
+func (wpst *wcowPodSandboxTask) UpdateEndpoint(ctx context.Context, req *shimdiag.EndpointRequest) error {
+	if wpst.host == nil {
+		return errTaskNotIsolated
+	}
+	return updateUvmEndpoint(wpst.host, wpst.nsid, req)
+}
+
+func (wpst *wcowPodSandboxTask) Capabilities() *shimdiag.TaskCapabilities {
+	// The sandbox task has no container so it cannot be paused, updated or
+	// signaled beyond being killed.
+	caps := &shimdiag.TaskCapabilities{
+		Os:       "windows",
+		Isolated: wpst.host != nil,
+		Stats:    true,
+	}
+	if wpst.host != nil {
+		caps.GcsProtocol = wpst.host.Protocol()
+	}
+	return caps
+}
+
+func (wpst *wcowPodSandboxTask) DumpGuestStacks(ctx context.Context) (string, error) {
+	if wpst.host == nil {
+		return "", errTaskNotIsolated
+	}
+	return wpst.host.DumpStacks(ctx)
+}
+
+func (wpst *wcowPodSandboxTask) ExportRootfs(ctx context.Context, req *shimdiag.ExportRootfsRequest) error {
+	// The pod sandbox task has no root file system of its own.
+	return errors.Wrap(errdefs.ErrNotImplemented, "exporting the root file system is only supported for LCOW")
+}
+
+func (wpst *wcowPodSandboxTask) AttachHostConsole(ctx context.Context, req *shimdiag.ConsoleRequest) error {
+	if wpst.host == nil {
+		return errTaskNotIsolated
+	}
+	return attachUvmConsole(ctx, wpst.host, req)
+}
+
+func (wpst *wcowPodSandboxTask) DiagState(ctx context.Context, eid string) (*shimdiag.TaskStateResponse, error) {
+	e, err := wpst.GetExec(eid)
+	if err != nil {
+		return nil, err
+	}
+	// The sandbox task has no container so there are no resources to
+	// report.
+	resp := newDiagStateResponse(e.Status())
+	if wpst.host != nil {
+		resp.HostID = wpst.host.ID()
+	}
+	return resp, nil
+}
+
+// Update resizes the memory of the UVM hosting the pod to the memory limit in
+// `resources`. All other resources apply to the workload containers and are
+// not supported on the sandbox task.
+func (wpst *wcowPodSandboxTask) Update(ctx context.Context, resources interface{}) error {
+	logrus.WithFields(logrus.Fields{
+		"tid": wpst.id,
+	}).Debug("wcowPodSandboxTask::Update")
+
+	r, ok := resources.(*specs.WindowsResources)
+	if !ok {
+		return errors.Wrapf(errdefs.ErrInvalidArgument, "task: '%s' unsupported resources type '%T'", wpst.id, resources)
+	}
+	if r.CPU != nil || r.Storage != nil || r.Memory == nil || r.Memory.Limit == nil {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "task: '%s' sandbox task only supports memory limit updates", wpst.id)
+	}
+	if wpst.host == nil {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "task: '%s' sandbox task has no UVM to resize", wpst.id)
+	}
+	return updateHostMemory(wpst.host, *r.Memory.Limit, wpst.hostMemoryOverheadInMB)
+}
+
+func (wpst *wcowPodSandboxTask) SetCPUGroup(ctx context.Context, id string) error {
+	if wpst.host == nil {
+		return errTaskNotIsolated
+	}
+	return setHostCPUGroup(wpst.host, id)
+}
+
+func (wpst *wcowPodSandboxTask) SaveUVM(ctx context.Context, path string) error {
+	if wpst.host == nil {
+		return errTaskNotIsolated
+	}
+	return saveHost(wpst.host, path)
+}
+
+func (wpst *wcowPodSandboxTask) Stats(ctx context.Context) (*stats.Statistics, error) {
+	// The sandbox task has no container so only the UVM has statistics.
+	s := &stats.Statistics{}
+	if wpst.host != nil {
+		vm, err := hostStatistics(wpst.host)
+		if err != nil {
+			return nil, err
+		}
+		s.Vm = vm
+	}
+	return s, nil
+}
+
+func (wpst *wcowPodSandboxTask) Pause(ctx context.Context) error {
+	return errors.Wrapf(errdefs.ErrNotImplemented, "task: '%s' sandbox task cannot be paused", wpst.id)
+}
+
+func (wpst *wcowPodSandboxTask) Resume(ctx context.Context) error {
+	return errors.Wrapf(errdefs.ErrNotImplemented, "task: '%s' sandbox task cannot be resumed", wpst.id)
+}
+
+func (wpst *wcowPodSandboxTask) Paused() bool {
+	return false
+}
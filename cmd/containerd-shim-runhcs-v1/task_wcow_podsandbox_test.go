@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containerd/containerd/errdefs"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+)
+
+func Test_wcowPodSandboxTask_Update_LinuxResources_Error(t *testing.T) {
+	wpst := newWcowPodSandboxTask(context.TODO(), fakePublisher, t.Name(), t.Name(), nil, "", 0)
+
+	err := wpst.Update(context.TODO(), &specs.LinuxResources{})
+	if errors.Cause(err) != errdefs.ErrInvalidArgument {
+		t.Fatalf("expected ErrInvalidArgument, got: %v", err)
+	}
+}
+
+func Test_wcowPodSandboxTask_Update_CPU_NotImplemented(t *testing.T) {
+	wpst := newWcowPodSandboxTask(context.TODO(), fakePublisher, t.Name(), t.Name(), nil, "", 0)
+	count := uint64(2)
+
+	err := wpst.Update(context.TODO(), &specs.WindowsResources{CPU: &specs.WindowsCPUResources{Count: &count}})
+	if errors.Cause(err) != errdefs.ErrNotImplemented {
+		t.Fatalf("expected ErrNotImplemented, got: %v", err)
+	}
+}
+
+func Test_wcowPodSandboxTask_Update_NoHost_NotImplemented(t *testing.T) {
+	// Process isolated pods have no UVM whose memory could be resized.
+	wpst := newWcowPodSandboxTask(context.TODO(), fakePublisher, t.Name(), t.Name(), nil, "", 0)
+	limit := uint64(1024 * 1024 * 1024)
+
+	err := wpst.Update(context.TODO(), &specs.WindowsResources{Memory: &specs.WindowsMemoryResources{Limit: &limit}})
+	if errors.Cause(err) != errdefs.ErrNotImplemented {
+		t.Fatalf("expected ErrNotImplemented, got: %v", err)
+	}
+}
+
+func Test_wcowPodSandboxTask_SetCPUGroup_NoUVM_Error(t *testing.T) {
+	wpst := newWcowPodSandboxTask(context.TODO(), fakePublisher, t.Name(), t.Name(), nil, "", 0)
+
+	if err := wpst.SetCPUGroup(context.TODO(), "4e7b7c7a-0a3c-4d5e-9f4a-1b2c3d4e5f60"); err != errTaskNotIsolated {
+		t.Fatalf("expected: %v, got: %v", errTaskNotIsolated, err)
+	}
+}
+
+func Test_wcowPodSandboxTask_SaveUVM_NoUVM_Error(t *testing.T) {
+	wpst := newWcowPodSandboxTask(context.TODO(), fakePublisher, t.Name(), t.Name(), nil, "", 0)
+
+	if err := wpst.SaveUVM(context.TODO(), `c:\saved\uvm.vmrs`); err != errTaskNotIsolated {
+		t.Fatalf("expected: %v, got: %v", errTaskNotIsolated, err)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"time"
+
+	"github.com/Microsoft/go-winio"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/Microsoft/hcsshim/internal/uvmpool"
+	"github.com/sirupsen/logrus"
+)
+
+// uvmPoolDialTimeout is the time waited to connect to the uvmpool sidecar.
+const uvmPoolDialTimeout = time.Second * 5
+
+// claimPooledLCOW claims a running LCOW utility VM created with `opts` from
+// the uvmpool sidecar in the shim config. Returns nil if no sidecar is
+// configured or it has no matching utility VM ready, in which case the caller
+// cold-boots the utility VM. Failures to claim are logged rather than returned
+// so that an unavailable sidecar does not fail the pod. The output of the guest
+// of a claimed utility VM is not forwarded.
+func claimPooledLCOW(opts *uvm.OptionsLCOW) *uvm.UtilityVM {
+	c := getConfig().UVMPool
+	if c == nil {
+		return nil
+	}
+	log := logrus.WithField("address", c.Address)
+	// The output of the guest is forwarded to the uvmpool sidecar that booted
+	// the utility VM, so it is not forwarded for a pooled utility VM.
+	lo := *opts
+	lo.ForwardStdout = false
+	lo.ForwardStderr = false
+	opts = &lo
+	// A pooled utility VM is not reserved so it cannot be claimed if the host
+	// resources are.
+	if opts.ReservationLimits != nil {
+		log.Debug("claimPooledLCOW - skipped as utility VMs are reserved")
+		return nil
+	}
+	if err := uvmpool.ValidateLCOW(opts); err != nil {
+		log.WithError(err).Debug("claimPooledLCOW - skipped")
+		return nil
+	}
+	key, err := uvmpool.LCOWKey(opts)
+	if err != nil {
+		log.WithError(err).Warning("claimPooledLCOW - failed to compute pool key")
+		return nil
+	}
+	log = log.WithField("key", key)
+
+	timeout := uvmPoolDialTimeout
+	conn, err := winio.DialPipe(c.Address, &timeout)
+	if err != nil {
+		log.WithError(err).Warning("claimPooledLCOW - failed to connect to uvmpool")
+		return nil
+	}
+	var vm *uvm.UtilityVM
+	claimed, err := uvmpool.Claim(conn, key, func(id string, memorySizeInMB int32) error {
+		o := *opts
+		oo := *opts.Options
+		oo.ID = id
+		oo.MemorySizeInMB = memorySizeInMB
+		o.Options = &oo
+		var err error
+		vm, err = uvm.OpenLCOW(&o)
+		return err
+	})
+	if err != nil {
+		log.WithError(err).Warning("claimPooledLCOW - failed to claim utility VM")
+		if vm != nil {
+			vm.Close()
+		}
+		return nil
+	}
+	if !claimed {
+		log.Debug("claimPooledLCOW - no utility VM ready")
+		return nil
+	}
+	log.WithField("uvm-id", vm.ID()).Info("claimPooledLCOW - claimed utility VM")
+	return vm
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/Microsoft/hcsshim/internal/appargs"
+	"github.com/urfave/cli"
+)
+
+var catCommand = cli.Command{
+	Name:      "cat",
+	Usage:     "Writes the contents of a file of an image to stdout",
+	ArgsUsage: "<image> <path>",
+	Before:    appargs.Validate(appargs.NonEmptyString, appargs.String),
+	Action: func(c *cli.Context) error {
+		r, f, err := openImage(c.Args()[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		sr, err := r.Open(c.Args()[1])
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(os.Stdout, sr)
+		return err
+	},
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Microsoft/hcsshim/ext4/ext4reader"
+	"github.com/Microsoft/hcsshim/internal/appargs"
+	"github.com/urfave/cli"
+)
+
+var diffCommand = cli.Command{
+	Name:  "diff",
+	Usage: "Lists the files added (A), deleted (D) and modified (M) in a directory of the second image compared to the first",
+	Description: `Modified files are followed by the metadata that differs, or "content" if
+   the contents of a regular file differ. Access and change times are ignored.`,
+	ArgsUsage: "<image 1> <image 2> [path]",
+	Before:    appargs.Validate(appargs.NonEmptyString, appargs.NonEmptyString, appargs.Optional(appargs.String)),
+	Action: func(c *cli.Context) error {
+		r1, f1, err := openImage(c.Args()[0])
+		if err != nil {
+			return err
+		}
+		defer f1.Close()
+		r2, f2, err := openImage(c.Args()[1])
+		if err != nil {
+			return err
+		}
+		defer f2.Close()
+
+		root := pathArg(c, 2)
+		files1, err := listFiles(r1, root)
+		if err != nil {
+			return err
+		}
+		files2, err := listFiles(r2, root)
+		if err != nil {
+			return err
+		}
+		var names []string
+		for name := range files1 {
+			names = append(names, name)
+		}
+		for name := range files2 {
+			if _, ok := files1[name]; !ok {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			a, b := files1[name], files2[name]
+			switch {
+			case a == nil:
+				fmt.Printf("A %s\n", name)
+			case b == nil:
+				fmt.Printf("D %s\n", name)
+			default:
+				changes, err := fileChanges(r1, r2, name, a, b)
+				if err != nil {
+					return err
+				}
+				if len(changes) != 0 {
+					fmt.Printf("M %s (%s)\n", name, strings.Join(changes, ", "))
+				}
+			}
+		}
+		return nil
+	},
+}
+
+func listFiles(r *ext4reader.Reader, root string) (map[string]*ext4reader.File, error) {
+	files := make(map[string]*ext4reader.File)
+	err := r.Walk(root, func(name string, f *ext4reader.File) error {
+		files[name] = f
+		return nil
+	})
+	return files, err
+}
+
+// fileChanges returns the metadata of the file `name` that differs between
+// `a` in `r1` and `b` in `r2`, and "content" if its contents differ.
+func fileChanges(r1, r2 *ext4reader.Reader, name string, a, b *ext4reader.File) ([]string, error) {
+	var changes []string
+	if a.Mode != b.Mode {
+		changes = append(changes, "mode")
+	}
+	if a.Uid != b.Uid || a.Gid != b.Gid {
+		changes = append(changes, "owner")
+	}
+	if !a.Mtime.Equal(b.Mtime) {
+		changes = append(changes, "mtime")
+	}
+	if a.Linkname != b.Linkname {
+		changes = append(changes, "link")
+	}
+	if a.Devmajor != b.Devmajor || a.Devminor != b.Devminor {
+		changes = append(changes, "device")
+	}
+	if !(len(a.Xattrs) == 0 && len(b.Xattrs) == 0) && !reflect.DeepEqual(a.Xattrs, b.Xattrs) {
+		changes = append(changes, "xattrs")
+	}
+	if a.Mode&ext4reader.TypeMask == ext4reader.S_IFREG && b.Mode&ext4reader.TypeMask == ext4reader.S_IFREG {
+		if a.Size != b.Size {
+			changes = append(changes, "size", "content")
+		} else {
+			h1, err := hashFile(r1, name)
+			if err != nil {
+				return nil, err
+			}
+			h2, err := hashFile(r2, name)
+			if err != nil {
+				return nil, err
+			}
+			if !bytes.Equal(h1, h2) {
+				changes = append(changes, "content")
+			}
+		}
+	}
+	return changes, nil
+}
+
+func hashFile(r *ext4reader.Reader, name string) ([]byte, error) {
+	sr, err := r.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, sr); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
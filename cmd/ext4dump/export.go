@@ -0,0 +1,46 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/Microsoft/hcsshim/internal/appargs"
+	"github.com/urfave/cli"
+)
+
+var exportOutput string
+
+var exportCommand = cli.Command{
+	Name:      "export",
+	Usage:     "Writes a directory of an image as a tar stream",
+	ArgsUsage: "<image> [path]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:        "output,o",
+			Usage:       "the file to write the tar stream to instead of stdout",
+			Destination: &exportOutput},
+	},
+	Before: appargs.Validate(appargs.NonEmptyString, appargs.Optional(appargs.String)),
+	Action: func(c *cli.Context) (err error) {
+		r, f, err := openImage(c.Args()[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		var out io.Writer = os.Stdout
+		if exportOutput != "" {
+			o, err := os.Create(exportOutput)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if cerr := o.Close(); err == nil {
+					err = cerr
+				}
+			}()
+			out = o
+		}
+		return r.WriteTar(out, pathArg(c, 1))
+	},
+}
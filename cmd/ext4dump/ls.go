@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/Microsoft/hcsshim/ext4/ext4reader"
+	"github.com/Microsoft/hcsshim/internal/appargs"
+	"github.com/urfave/cli"
+)
+
+var (
+	lsLong      bool
+	lsRecursive bool
+)
+
+var lsCommand = cli.Command{
+	Name:      "ls",
+	Usage:     "Lists a directory of an image",
+	ArgsUsage: "<image> [path]",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:        "l",
+			Usage:       "list the metadata of each file",
+			Destination: &lsLong},
+		cli.BoolFlag{
+			Name:        "r",
+			Usage:       "list the subdirectories recursively",
+			Destination: &lsRecursive},
+	},
+	Before: appargs.Validate(appargs.NonEmptyString, appargs.Optional(appargs.String)),
+	Action: func(c *cli.Context) error {
+		r, f, err := openImage(c.Args()[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		root := path.Join("/", pathArg(c, 1))
+		list := func(name string, f *ext4reader.File) {
+			if lsLong {
+				fmt.Println(fileString(name, f))
+			} else {
+				fmt.Println(name)
+			}
+		}
+		if lsRecursive {
+			return r.Walk(root, func(name string, f *ext4reader.File) error {
+				if name != root || !f.IsDir() {
+					list(name, f)
+				}
+				return nil
+			})
+		}
+
+		names, err := r.ReadDir(root)
+		if err != nil {
+			// List a file that is not a directory by itself.
+			f, serr := r.Stat(root)
+			if serr != nil || f.IsDir() {
+				return err
+			}
+			list(root, f)
+			return nil
+		}
+		for _, name := range names {
+			name = path.Join(root, name)
+			f, err := r.Stat(name)
+			if err != nil {
+				return err
+			}
+			list(name, f)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Microsoft/hcsshim/ext4/ext4reader"
+	"github.com/urfave/cli"
+)
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "ext4dump"
+	app.Usage = "inspects the files of LCOW layer and scratch disks without a utility VM"
+	app.Commands = []cli.Command{
+		lsCommand,
+		statCommand,
+		catCommand,
+		exportCommand,
+		diffCommand,
+	}
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// openImage opens the ext4 image, VHD or VHDX at `path`.
+func openImage(path string) (*ext4reader.Reader, *os.File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := ext4reader.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return r, f, nil
+}
+
+// pathArg returns the argument `i`, or "/" if there are not enough arguments.
+func pathArg(c *cli.Context, i int) string {
+	if c.NArg() <= i {
+		return "/"
+	}
+	return c.Args()[i]
+}
+
+// modeString returns the mode `mode` in the format of `ls -l`.
+func modeString(mode uint16) string {
+	b := []byte("?rwxrwxrwx")
+	switch mode & ext4reader.TypeMask {
+	case ext4reader.S_IFREG:
+		b[0] = '-'
+	case ext4reader.S_IFDIR:
+		b[0] = 'd'
+	case ext4reader.S_IFLNK:
+		b[0] = 'l'
+	case ext4reader.S_IFCHR:
+		b[0] = 'c'
+	case ext4reader.S_IFBLK:
+		b[0] = 'b'
+	case ext4reader.S_IFIFO:
+		b[0] = 'p'
+	case ext4reader.S_IFSOCK:
+		b[0] = 's'
+	}
+	for i := uint(0); i < 9; i++ {
+		if mode&(1<<(8-i)) == 0 {
+			b[i+1] = '-'
+		}
+	}
+	special := []struct {
+		bit   uint16
+		index int
+		set   byte
+	}{
+		{04000, 3, 's'},
+		{02000, 6, 's'},
+		{01000, 9, 't'},
+	}
+	for _, s := range special {
+		if mode&s.bit != 0 {
+			if b[s.index] == '-' {
+				b[s.index] = s.set - 'a' + 'A'
+			} else {
+				b[s.index] = s.set
+			}
+		}
+	}
+	return string(b)
+}
+
+// fileString returns a line describing `f` in the format of `ls -l`.
+func fileString(name string, f *ext4reader.File) string {
+	size := fmt.Sprint(f.Size)
+	if t := f.Mode & ext4reader.TypeMask; t == ext4reader.S_IFCHR || t == ext4reader.S_IFBLK {
+		size = fmt.Sprintf("%d, %d", f.Devmajor, f.Devminor)
+	}
+	s := fmt.Sprintf("%s %5d %5d %10s %s %s", modeString(f.Mode), f.Uid, f.Gid, size, f.Mtime.UTC().Format("2006-01-02 15:04:05"), name)
+	if f.Linkname != "" {
+		s += " -> " + f.Linkname
+	}
+	return s
+}
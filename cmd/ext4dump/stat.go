@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/appargs"
+	"github.com/urfave/cli"
+)
+
+var statCommand = cli.Command{
+	Name:      "stat",
+	Usage:     "Prints the metadata of a file of an image",
+	ArgsUsage: "<image> <path>",
+	Before:    appargs.Validate(appargs.NonEmptyString, appargs.String),
+	Action: func(c *cli.Context) error {
+		r, f, err := openImage(c.Args()[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		file, err := r.Stat(c.Args()[1])
+		if err != nil {
+			return err
+		}
+		fmt.Println(fileString(c.Args()[1], file))
+		fmt.Printf("Inode: %d\n", file.Inode)
+		for _, t := range []struct {
+			name string
+			time time.Time
+		}{
+			{"Access", file.Atime},
+			{"Modify", file.Mtime},
+			{"Change", file.Ctime},
+			{"Birth", file.Crtime},
+		} {
+			fmt.Printf("%s: %s\n", t.name, t.time.UTC().Format(time.RFC3339Nano))
+		}
+		var names []string
+		for name := range file.Xattrs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("Xattr: %s=%q\n", name, file.Xattrs[name])
+		}
+		return nil
+	},
+}
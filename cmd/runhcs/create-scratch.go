@@ -27,6 +27,10 @@ var createScratchCommand = cli.Command{
 			Name:  "cache-path",
 			Usage: "optional: The path to an existing scratch.vhdx to copy instead of create.",
 		},
+		cli.StringFlag{
+			Name:  "cache-dir",
+			Usage: "optional: The directory of cached scratch vhdx's of every size to copy instead of create.",
+		},
 	},
 	Before: appargs.Validate(),
 	Action: func(context *cli.Context) error {
@@ -35,6 +39,10 @@ var createScratchCommand = cli.Command{
 			return errors.New("'destpath' is required")
 		}
 
+		if context.String("cache-path") != "" && context.String("cache-dir") != "" {
+			return errors.New("'cache-path' and 'cache-dir' cannot both be set")
+		}
+
 		if osversion.Get().Build < osversion.RS5 {
 			return errors.New("LCOW is not supported pre-RS5")
 		}
@@ -59,7 +67,12 @@ var createScratchCommand = cli.Command{
 			return errors.Wrapf(err, "failed to start '%s'", opts.ID)
 		}
 
-		if err := lcow.CreateScratch(convertUVM, dest, sizeGB, context.String("cache-path")); err != nil {
+		if cacheDir := context.String("cache-dir"); cacheDir != "" {
+			err = lcow.CreateScratchWithOptions(convertUVM, dest, sizeGB, "", &lcow.FormatOptions{CacheDir: cacheDir})
+		} else {
+			err = lcow.CreateScratch(convertUVM, dest, sizeGB, context.String("cache-path"))
+		}
+		if err != nil {
 			return errors.Wrapf(err, "failed to create ext4vhdx for '%s'", opts.ID)
 		}
 
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/Microsoft/hcsshim/internal/appargs"
+	"github.com/Microsoft/hcsshim/internal/shimdiag"
+	"github.com/containerd/containerd/runtime/v2/task"
+	"github.com/urfave/cli"
+)
+
+var capabilitiesCommand = cli.Command{
+	Name:      "capabilities",
+	Usage:     "Shows the features supported by a shim and optionally one of its tasks",
+	ArgsUsage: "<shim name> [task id]",
+	Before:    appargs.Validate(appargs.String, appargs.Optional(appargs.String)),
+	Action: func(c *cli.Context) error {
+		args := c.Args()
+		shim, err := getShim(args[0])
+		if err != nil {
+			return err
+		}
+		// The capabilities are returned to orchestrators by `Connect`.
+		svc := task.NewTaskClient(shim)
+		resp, err := svc.Connect(context.Background(), &task.ConnectRequest{
+			ID: args.Get(1),
+		})
+		if err != nil {
+			return err
+		}
+		var caps shimdiag.ShimCapabilities
+		if err := json.Unmarshal([]byte(resp.Version), &caps); err != nil {
+			return err
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(&caps)
+	},
+}
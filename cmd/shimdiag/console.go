@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Microsoft/hcsshim/internal/appargs"
+	"github.com/Microsoft/hcsshim/internal/shimdiag"
+	"github.com/containerd/console"
+	"github.com/urfave/cli"
+)
+
+// consoleDetachKey is Ctrl-] which detaches from the console.
+const consoleDetachKey = 0x1d
+
+// detachReader reads from `r` until `consoleDetachKey` is read and then
+// returns io.EOF.
+type detachReader struct {
+	r io.Reader
+}
+
+func (d detachReader) Read(b []byte) (int, error) {
+	n, err := d.r.Read(b)
+	if i := bytes.IndexByte(b[:n], consoleDetachKey); i >= 0 {
+		return i, io.EOF
+	}
+	return n, err
+}
+
+var consoleCommand = cli.Command{
+	Name:      "console",
+	Usage:     "Attaches to the serial console of a shim's hosting utility VM",
+	ArgsUsage: "<shim name>",
+	Before:    appargs.Validate(appargs.String),
+	Action: func(c *cli.Context) error {
+		shim, err := getShim(c.Args()[0])
+		if err != nil {
+			return err
+		}
+
+		var osStdin io.Reader = os.Stdin
+		con, err := console.ConsoleFromFile(os.Stdin)
+		if err == nil {
+			if err := con.SetRaw(); err != nil {
+				return err
+			}
+			defer con.Reset()
+			osStdin = rawConReader{os.Stdin}
+		}
+		stdin, err := makePipe(detachReader{osStdin}, true)
+		if err != nil {
+			return err
+		}
+		stdout, err := makePipe(os.Stdout, false)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(os.Stderr, "Attached to the utility VM console. Press Ctrl-] to detach.\r\n")
+		svc := shimdiag.NewShimDiagClient(shim)
+		_, err = svc.DiagConsole(context.Background(), &shimdiag.ConsoleRequest{
+			Stdin:  stdin,
+			Stdout: stdout,
+		})
+		return err
+	},
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+
+	"github.com/Microsoft/hcsshim/internal/appargs"
+	"github.com/Microsoft/hcsshim/internal/shimdiag"
+	"github.com/urfave/cli"
+)
+
+var cpuGroupCommand = cli.Command{
+	Name:      "cpugroup",
+	Usage:     "Assigns the utility VM of a shim to an existing host CPU group",
+	ArgsUsage: "<shim name> <cpu group id>",
+	Before:    appargs.Validate(appargs.String, appargs.NonEmptyString),
+	Action: func(c *cli.Context) error {
+		args := c.Args()
+		shim, err := getShim(args[0])
+		if err != nil {
+			return err
+		}
+		svc := shimdiag.NewShimDiagClient(shim)
+		_, err = svc.DiagCPUGroup(context.Background(), &shimdiag.CPUGroupRequest{
+			CPUGroupID: args[1],
+		})
+		return err
+	},
+}
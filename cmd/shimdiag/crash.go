@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Microsoft/hcsshim/internal/appargs"
+	"github.com/Microsoft/hcsshim/internal/shimdiag"
+	"github.com/urfave/cli"
+)
+
+var crashCommand = cli.Command{
+	Name:      "crash",
+	Usage:     "Show the report of the shim's last recovered panic",
+	ArgsUsage: "<shim name>",
+	Before:    appargs.Validate(appargs.String),
+	Action: func(c *cli.Context) error {
+		shim, err := getShim(c.Args()[0])
+		if err != nil {
+			return err
+		}
+		svc := shimdiag.NewShimDiagClient(shim)
+		resp, err := svc.DiagLastCrash(context.Background(), &shimdiag.LastCrashRequest{})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s:\n%s", resp.Path, resp.Report)
+		return nil
+	},
+}
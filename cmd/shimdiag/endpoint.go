@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+
+	"github.com/Microsoft/hcsshim/internal/appargs"
+	"github.com/Microsoft/hcsshim/internal/shimdiag"
+	"github.com/urfave/cli"
+)
+
+var endpointRemove bool
+var endpointCommand = cli.Command{
+	Name:      "endpoint",
+	Usage:     "Hot adds an HNS endpoint to a running pod, or removes it",
+	ArgsUsage: "<shim name> <endpoint id>",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:        "remove",
+			Usage:       "remove the endpoint instead of adding it",
+			Destination: &endpointRemove},
+	},
+	Before: appargs.Validate(appargs.String, appargs.NonEmptyString),
+	Action: func(c *cli.Context) error {
+		args := c.Args()
+		shim, err := getShim(args[0])
+		if err != nil {
+			return err
+		}
+		svc := shimdiag.NewShimDiagClient(shim)
+		_, err = svc.DiagEndpoint(context.Background(), &shimdiag.EndpointRequest{
+			EndpointID: args[1],
+			Remove:     endpointRemove,
+		})
+		return err
+	},
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/Microsoft/go-winio"
+	"github.com/Microsoft/go-winio/pkg/guid"
+	"github.com/Microsoft/hcsshim/internal/appargs"
+	"github.com/Microsoft/hcsshim/internal/shimdiag"
+	"github.com/urfave/cli"
+)
+
+var exportOutput string
+
+var exportCommand = cli.Command{
+	Name:      "export",
+	Usage:     "Exports the root file system of a running LCOW task in a shim as a tar stream",
+	ArgsUsage: "<shim name> <task id>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:        "output,o",
+			Usage:       "the file to write the tar stream to instead of stdout",
+			Destination: &exportOutput},
+	},
+	Before: appargs.Validate(appargs.String, appargs.NonEmptyString),
+	Action: func(c *cli.Context) (err error) {
+		args := c.Args()
+		shim, err := getShim(args[0])
+		if err != nil {
+			return err
+		}
+
+		var out io.Writer = os.Stdout
+		if exportOutput != "" {
+			f, err := os.Create(exportOutput)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if cerr := f.Close(); err == nil {
+					err = cerr
+				}
+			}()
+			out = f
+		}
+
+		r, err := guid.NewV4()
+		if err != nil {
+			return err
+		}
+		p := `\\.\pipe\` + r.String()
+		l, err := winio.ListenPipe(p, nil)
+		if err != nil {
+			return err
+		}
+		defer l.Close()
+		// Unlike `makePipe` wait for the whole stream to be written before
+		// returning.
+		copied := make(chan error, 1)
+		go func() {
+			c, err := l.Accept()
+			if err != nil {
+				copied <- err
+				return
+			}
+			defer c.Close()
+			_, err = io.Copy(out, c)
+			copied <- err
+		}()
+
+		svc := shimdiag.NewShimDiagClient(shim)
+		if _, err := svc.DiagExportRootfs(context.Background(), &shimdiag.ExportRootfsRequest{
+			TaskID: args[1],
+			Stdout: p,
+		}); err != nil {
+			return err
+		}
+		return <-copied
+	},
+}
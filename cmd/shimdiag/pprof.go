@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+
+	"github.com/Microsoft/hcsshim/internal/appargs"
+	"github.com/Microsoft/hcsshim/internal/shimdiag"
+	"github.com/urfave/cli"
+)
+
+var (
+	pprofSeconds uint
+	pprofOutput  string
+)
+
+var pprofCommand = cli.Command{
+	Name:      "pprof",
+	Usage:     "Collects a pprof profile (heap, goroutine, mutex or cpu) of the shim",
+	ArgsUsage: "<shim name> <profile>",
+	Flags: []cli.Flag{
+		cli.UintFlag{
+			Name:        "seconds",
+			Usage:       "the sampling duration of the mutex and cpu profiles (default 30)",
+			Destination: &pprofSeconds},
+		cli.StringFlag{
+			Name:        "output,o",
+			Usage:       "the file to write the profile to instead of stdout",
+			Destination: &pprofOutput},
+	},
+	Before: appargs.Validate(appargs.String, appargs.NonEmptyString),
+	Action: func(c *cli.Context) error {
+		args := c.Args()
+		shim, err := getShim(args[0])
+		if err != nil {
+			return err
+		}
+		svc := shimdiag.NewShimDiagClient(shim)
+		resp, err := svc.DiagPprof(context.Background(), &shimdiag.PprofRequest{
+			Profile:         args[1],
+			DurationSeconds: uint32(pprofSeconds),
+		})
+		if err != nil {
+			return err
+		}
+		if pprofOutput == "" {
+			_, err = os.Stdout.Write(resp.Profile)
+			return err
+		}
+		return ioutil.WriteFile(pprofOutput, resp.Profile, 0644)
+	},
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Microsoft/hcsshim/internal/appargs"
+	"github.com/Microsoft/hcsshim/internal/shimdiag"
+	"github.com/urfave/cli"
+)
+
+var reloadCommand = cli.Command{
+	Name:      "reload",
+	Usage:     "Reload the shim's config file",
+	ArgsUsage: "<shim name>",
+	Before:    appargs.Validate(appargs.String),
+	Action: func(c *cli.Context) error {
+		shim, err := getShim(c.Args()[0])
+		if err != nil {
+			return err
+		}
+		svc := shimdiag.NewShimDiagClient(shim)
+		resp, err := svc.DiagReloadConfig(context.Background(), &shimdiag.ReloadConfigRequest{})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("reloaded %s\n", resp.ConfigPath)
+		return nil
+	},
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+
+	"github.com/Microsoft/hcsshim/internal/appargs"
+	"github.com/Microsoft/hcsshim/internal/shimdiag"
+	"github.com/urfave/cli"
+)
+
+var saveCommand = cli.Command{
+	Name:      "save",
+	Usage:     "Saves the Windows utility VM of a shim to a file it can be restored from, leaving it paused",
+	ArgsUsage: "<shim name> <path>",
+	Before:    appargs.Validate(appargs.String, appargs.NonEmptyString),
+	Action: func(c *cli.Context) error {
+		args := c.Args()
+		shim, err := getShim(args[0])
+		if err != nil {
+			return err
+		}
+		svc := shimdiag.NewShimDiagClient(shim)
+		_, err = svc.DiagSaveUVM(context.Background(), &shimdiag.SaveUVMRequest{
+			Path: args[1],
+		})
+		return err
+	},
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+
+	"github.com/Microsoft/hcsshim/internal/appargs"
+	"github.com/Microsoft/hcsshim/internal/shimdiag"
+	"github.com/urfave/cli"
+)
+
+var shareReadOnly bool
+var shareCommand = cli.Command{
+	Name:      "share",
+	Usage:     "Shares a host path into a shim's hosting utility VM",
+	ArgsUsage: "<shim name> <host path> <uvm path>",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:        "readonly,ro",
+			Usage:       "share the host path read-only",
+			Destination: &shareReadOnly},
+	},
+	Before: appargs.Validate(appargs.String, appargs.NonEmptyString, appargs.NonEmptyString),
+	Action: func(c *cli.Context) error {
+		args := c.Args()
+		shim, err := getShim(args[0])
+		if err != nil {
+			return err
+		}
+		svc := shimdiag.NewShimDiagClient(shim)
+		_, err = svc.DiagShare(context.Background(), &shimdiag.ShareRequest{
+			HostPath: args[1],
+			UvmPath:  args[2],
+			ReadOnly: shareReadOnly,
+		})
+		return err
+	},
+}
@@ -26,6 +26,18 @@ func main() {
 		listCommand,
 		execCommand,
 		stacksCommand,
+		stateCommand,
+		tasksCommand,
+		shareCommand,
+		endpointCommand,
+		cpuGroupCommand,
+		saveCommand,
+		pprofCommand,
+		reloadCommand,
+		crashCommand,
+		consoleCommand,
+		exportCommand,
+		capabilitiesCommand,
 	}
 	if err := app.Run(os.Args); err != nil {
 		fmt.Fprintln(os.Stderr, err)
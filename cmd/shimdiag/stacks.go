@@ -11,7 +11,7 @@ import (
 
 var stacksCommand = cli.Command{
 	Name:      "stacks",
-	Usage:     "Dump the shim's goroutine stacks",
+	Usage:     "Dump the shim's goroutine stacks and those of the GCS in its hosting utility VM",
 	ArgsUsage: "<shim name>",
 	Before:    appargs.Validate(appargs.String),
 	Action: func(c *cli.Context) error {
@@ -25,6 +25,10 @@ var stacksCommand = cli.Command{
 			return err
 		}
 		fmt.Print(resp.Stacks)
+		if resp.GuestStacks != "" {
+			fmt.Print("\n--- GUEST STACKS ---\n\n")
+			fmt.Print(resp.GuestStacks)
+		}
 		return nil
 	},
 }
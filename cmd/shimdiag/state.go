@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/Microsoft/hcsshim/internal/appargs"
+	"github.com/Microsoft/hcsshim/internal/shimdiag"
+	"github.com/urfave/cli"
+)
+
+var stateCommand = cli.Command{
+	Name:      "state",
+	Usage:     "Shows the state and resource usage of a task in a shim",
+	ArgsUsage: "<shim name> <task id> [exec id]",
+	Before:    appargs.Validate(appargs.String, appargs.String, appargs.Optional(appargs.String)),
+	Action: func(c *cli.Context) error {
+		args := c.Args()
+		shim, err := getShim(args[0])
+		if err != nil {
+			return err
+		}
+		svc := shimdiag.NewShimDiagClient(shim)
+		resp, err := svc.DiagTaskState(context.Background(), &shimdiag.TaskStateRequest{
+			TaskID: args[1],
+			ExecID: args.Get(2),
+		})
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(resp)
+	},
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+const _FSCTL_SET_SPARSE = 0x000900c4
+
+// setSparse marks `f` as sparse so that the ranges skipped while writing it
+// are not allocated.
+func setSparse(f *os.File) error {
+	var n uint32
+	return windows.DeviceIoControl(windows.Handle(f.Fd()), _FSCTL_SET_SPARSE, nil, 0, nil, 0, &n, nil)
+}
@@ -16,6 +16,9 @@ var (
 	overlay    = flag.Bool("overlay", false, "produce overlayfs-compatible layer image")
 	vhd        = flag.Bool("vhd", false, "add a VHD footer to the end of the image")
 	inlineData = flag.Bool("inline", false, "write small file data into the inode; not compatible with DAX")
+	verity     = flag.Bool("verity", false, "add a dm-verity hash device to the image for integrity verification and print its root digest")
+	sparse     = flag.Bool("sparse", false, "leave the unused space of the image as holes in a sparse output file")
+	maxSize    = flag.Int64("maxsize", 0, "maximum size of the image in bytes; 16GB if 0, 16TB if negative")
 )
 
 func main() {
@@ -38,7 +41,9 @@ func main() {
 			return err
 		}
 
-		var opts []tar2ext4.Option
+		defer out.Close()
+
+		opts := []tar2ext4.Option{tar2ext4.MaximumDiskSize(*maxSize)}
 		if *overlay {
 			opts = append(opts, tar2ext4.ConvertWhiteout)
 		}
@@ -48,10 +53,23 @@ func main() {
 		if *inlineData {
 			opts = append(opts, tar2ext4.InlineData)
 		}
+		var rootDigest string
+		if *verity {
+			opts = append(opts, tar2ext4.AppendDMVerity, tar2ext4.DMVerityRootDigest(&rootDigest))
+		}
+		if *sparse {
+			if err := setSparse(out); err != nil {
+				return err
+			}
+			opts = append(opts, tar2ext4.Sparse)
+		}
 		err = tar2ext4.Convert(in, out, opts...)
 		if err != nil {
 			return err
 		}
+		if rootDigest != "" {
+			fmt.Println(rootDigest)
+		}
 
 		// Exhaust the tar stream.
 		io.Copy(ioutil.Discard, in)
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/oci"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/Microsoft/hcsshim/internal/uvmpool"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// config is the config file of the pools to keep.
+type config struct {
+	Pools []poolConfig `json:"pools"`
+}
+
+// poolConfig is the config of a pool of LCOW utility VMs.
+type poolConfig struct {
+	// Size is the number of utility VMs kept ready to be claimed.
+	Size int `json:"size"`
+	// IdleTimeoutInSeconds is the time after the last claim after which the
+	// ready utility VMs are terminated until the next claim. If `0` they are
+	// kept forever.
+	IdleTimeoutInSeconds int `json:"idleTimeoutInSeconds,omitempty"`
+	// Annotations are the `io.microsoft.virtualmachine.*` annotations of the
+	// pods that claim from the pool. The utility VMs are created from them
+	// exactly as the shim would, except that the output of the guest is not
+	// forwarded.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// loadConfig reads and validates the config file at `path`.
+func loadConfig(path string) (*config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file '%s': %s", path, err)
+	}
+	c := &config{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("failed to parse config file '%s': %s", path, err)
+	}
+	return c, nil
+}
+
+// options returns the options of the utility VMs of the pool.
+func (c *poolConfig) options(owner string) (*uvm.OptionsLCOW, error) {
+	if c.Size <= 0 {
+		return nil, fmt.Errorf("pool size must be greater than 0: %d", c.Size)
+	}
+	if c.IdleTimeoutInSeconds < 0 {
+		return nil, fmt.Errorf("pool idleTimeoutInSeconds must not be negative: %d", c.IdleTimeoutInSeconds)
+	}
+	s := &specs.Spec{
+		Linux:       &specs.Linux{},
+		Annotations: c.Annotations,
+	}
+	if s.Annotations == nil {
+		s.Annotations = make(map[string]string)
+	}
+	opts, err := oci.SpecToUVMCreateOpts(s, "", owner)
+	if err != nil {
+		return nil, err
+	}
+	lopts := opts.(*uvm.OptionsLCOW)
+	// The output of the guest cannot be forwarded to the shim that claims the
+	// utility VM.
+	lopts.ForwardStdout = false
+	lopts.ForwardStderr = false
+	if err := uvmpool.ValidateLCOW(lopts); err != nil {
+		return nil, err
+	}
+	return lopts, nil
+}
+
+// idleTimeout returns the idle timeout of the pool.
+func (c *poolConfig) idleTimeout() time.Duration {
+	return time.Duration(c.IdleTimeoutInSeconds) * time.Second
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+)
+
+func Test_poolConfig_options(t *testing.T) {
+	c := &poolConfig{
+		Size: 1,
+		Annotations: map[string]string{
+			"io.microsoft.virtualmachine.computetopology.memory.sizeinmb": "2048",
+		},
+	}
+	opts, err := c.options("uvmpool")
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if opts.MemorySizeInMB != 2048 || opts.Owner != "uvmpool" {
+		t.Fatalf("unexpected options: %+v", opts.Options)
+	}
+}
+
+func Test_poolConfig_options_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		c    *poolConfig
+	}{
+		{name: "NoSize", c: &poolConfig{}},
+		{name: "NegativeIdleTimeout", c: &poolConfig{Size: 1, IdleTimeoutInSeconds: -1}},
+		{name: "Console", c: &poolConfig{Size: 1, Annotations: map[string]string{"io.microsoft.virtualmachine.lcow.console": "true"}}},
+	}
+	for _, test := range tests {
+		if _, err := test.c.options("uvmpool"); err == nil {
+			t.Fatalf("%s: expected error", test.name)
+		}
+	}
+}
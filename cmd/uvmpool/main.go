@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/Microsoft/go-winio"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/Microsoft/hcsshim/internal/uvmpool"
+	"github.com/sirupsen/logrus"
+)
+
+// pipeSecurityDescriptor restricts claims to SYSTEM and administrators.
+const pipeSecurityDescriptor = "D:P(A;;GA;;;SY)(A;;GA;;;BA)"
+
+var (
+	configPath = flag.String("config", "", "path to the config file of the pools")
+	address    = flag.String("address", `\\.\pipe\uvmpool`, "named pipe to serve claims on")
+	logLevel   = flag.String("log-level", "info", "logrus level to log at")
+)
+
+func main() {
+	flag.Parse()
+	if flag.NArg() != 0 || *configPath == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	level, err := logrus.ParseLevel(*logLevel)
+	if err != nil {
+		return err
+	}
+	logrus.SetLevel(level)
+
+	c, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	owner := filepath.Base(os.Args[0])
+	pools := make(map[string]*uvmpool.Pool)
+	defer func() {
+		for _, p := range pools {
+			p.Close()
+		}
+	}()
+	for i := range c.Pools {
+		opts, err := c.Pools[i].options(owner)
+		if err != nil {
+			return fmt.Errorf("pool %d: %s", i, err)
+		}
+		// Options that differ only in their ID and owner share a key.
+		key, err := uvmpool.LCOWKey(opts)
+		if err != nil {
+			return fmt.Errorf("pool %d: %s", i, err)
+		}
+		if _, ok := pools[key]; ok {
+			return fmt.Errorf("pool %d: duplicates the utility VM configuration of another pool", i)
+		}
+		logrus.WithFields(logrus.Fields{
+			"key":  key,
+			"size": c.Pools[i].Size,
+		}).Info("uvmpool: starting pool")
+		pools[key] = uvmpool.New(uvmpool.Config{
+			Size:        c.Pools[i].Size,
+			IdleTimeout: c.Pools[i].idleTimeout(),
+			Create:      createLCOW(opts),
+		})
+	}
+
+	l, err := winio.ListenPipe(*address, &winio.PipeConfig{SecurityDescriptor: pipeSecurityDescriptor})
+	if err != nil {
+		return err
+	}
+	s := uvmpool.NewServer(pools)
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt)
+		<-sig
+		logrus.Info("uvmpool: shutting down")
+		s.Close()
+	}()
+	return s.Serve(l)
+}
+
+// createLCOW returns a function that creates and starts a new LCOW utility VM
+// with `opts`.
+func createLCOW(opts *uvm.OptionsLCOW) func() (uvmpool.Item, error) {
+	return func() (uvmpool.Item, error) {
+		o := *opts
+		oo := *opts.Options
+		o.Options = &oo
+		// Generate a new ID.
+		oo.ID = ""
+		vm, err := uvm.CreateLCOW(&o)
+		if err != nil {
+			return nil, err
+		}
+		if err := vm.Start(); err != nil {
+			vm.Close()
+			return nil, err
+		}
+		return vm, nil
+	}
+}
@@ -0,0 +1,56 @@
+package hcsshim
+
+import (
+	"github.com/Microsoft/hcsshim/internal/hcs"
+	"github.com/Microsoft/hcsshim/internal/schema1"
+)
+
+// PropertyType is the type of property that can be queried on a compute
+// system.
+type PropertyType = schema1.PropertyType
+
+// PropertyType const
+const (
+	PropertyTypeStatistics        PropertyType = schema1.PropertyTypeStatistics
+	PropertyTypeProcessList       PropertyType = schema1.PropertyTypeProcessList
+	PropertyTypeMappedVirtualDisk PropertyType = schema1.PropertyTypeMappedVirtualDisk
+)
+
+// GetComputeSystemProperties opens the existing compute system `id` and
+// queries the requested property `types`. The basic properties such as ID,
+// State and Owner are always returned. The compute system is not modified and
+// the handle opened to query it is closed before returning.
+func GetComputeSystemProperties(id string, types ...PropertyType) (*ContainerProperties, error) {
+	system, err := hcs.OpenComputeSystem(id)
+	if err != nil {
+		return nil, err
+	}
+	c := &container{system: system}
+	defer c.Close()
+
+	properties, err := system.Properties(types...)
+	if err != nil {
+		return nil, convertSystemError(err, c)
+	}
+	return properties, nil
+}
+
+// GetComputeSystemStatistics opens the existing compute system `id` and
+// returns its memory, processor, storage and network statistics.
+func GetComputeSystemStatistics(id string) (Statistics, error) {
+	properties, err := GetComputeSystemProperties(id, PropertyTypeStatistics)
+	if err != nil {
+		return Statistics{}, err
+	}
+	return properties.Statistics, nil
+}
+
+// GetComputeSystemProcessList opens the existing compute system `id` and
+// returns details for the processes running in it.
+func GetComputeSystemProcessList(id string) ([]ProcessListItem, error) {
+	properties, err := GetComputeSystemProperties(id, PropertyTypeProcessList)
+	if err != nil {
+		return nil, err
+	}
+	return properties.ProcessList, nil
+}
@@ -0,0 +1,337 @@
+// Package dmverity builds and reads dm-verity hash devices appended to ext4
+// images so that a Linux guest can verify the integrity of the image as it is
+// read.
+//
+// The hash device uses the format of `veritysetup format` version 1 with
+// SHA-256, 4096 byte data and hash blocks and an all zero salt: a superblock
+// followed by the hash tree with its levels ordered from the root down.
+package dmverity
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Microsoft/hcsshim/ext4/internal/format"
+)
+
+const (
+	// BlockSize is the size of the data and hash blocks.
+	BlockSize = 4096
+	// Algorithm is the hash algorithm of the hash tree.
+	Algorithm = "sha256"
+	// Version is the dm-verity hash format version.
+	Version = 1
+
+	signature = "verity"
+	saltSize  = sha256.Size
+
+	// hashesPerBlock is the number of hashes in a hash block and so the
+	// number of blocks of a level hashed into a single block of the level
+	// above it.
+	hashesPerBlock = BlockSize / sha256.Size
+)
+
+// ErrNoHashDevice is returned by `ReadInfo` if the image has no hash device.
+var ErrNoHashDevice = errors.New("dmverity: image has no hash device")
+
+// salt is the salt of every hash. It is fixed so that converting the same
+// layer always produces the same image.
+var salt [saltSize]byte
+
+// superblock is the on-disk superblock of a dm-verity hash device.
+type superblock struct {
+	Signature     [8]byte
+	Version       uint32
+	HashType      uint32
+	UUID          [16]byte
+	Algorithm     [32]byte
+	DataBlockSize uint32
+	HashBlockSize uint32
+	DataBlocks    uint64
+	SaltSize      uint16
+	_             [6]byte
+	Salt          [256]byte
+	_             [168]byte
+}
+
+// Info is the information the guest needs to verify an image against the hash
+// device appended to it.
+type Info struct {
+	// DataSize is the size of the image in bytes, which is also the offset of
+	// the hash device.
+	DataSize int64
+	// BlockSize is the size of the data and hash blocks.
+	BlockSize uint32
+	// Version is the dm-verity hash format version.
+	Version uint32
+	// Algorithm is the hash algorithm.
+	Algorithm string
+	// Salt is the hex encoded salt of every hash.
+	Salt string
+}
+
+// hashBlock returns the hash of the block `b`.
+func hashBlock(b []byte) []byte {
+	h := sha256.New()
+	h.Write(salt[:])
+	h.Write(b)
+	return h.Sum(nil)
+}
+
+// hashLevel returns the hashes of the blocks read from `r` packed into hash
+// blocks, and the number of blocks read.
+func hashLevel(r io.Reader) ([]byte, uint64, error) {
+	var (
+		level  bytes.Buffer
+		blocks uint64
+		b      = make([]byte, BlockSize)
+	)
+	for {
+		if _, err := io.ReadFull(r, b); err == io.EOF {
+			break
+		} else if err == io.ErrUnexpectedEOF {
+			return nil, 0, fmt.Errorf("dmverity: data size is not a multiple of %d bytes", BlockSize)
+		} else if err != nil {
+			return nil, 0, err
+		}
+		level.Write(hashBlock(b))
+		blocks++
+	}
+	if blocks == 0 {
+		return nil, 0, errors.New("dmverity: no data")
+	}
+	if n := level.Len() % BlockSize; n != 0 {
+		level.Write(make([]byte, BlockSize-n))
+	}
+	return level.Bytes(), blocks, nil
+}
+
+// merkleTree returns the hash tree of the data read from `r` and the number of
+// data blocks read.
+func merkleTree(r io.Reader) ([]byte, uint64, error) {
+	level, dataBlocks, err := hashLevel(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	levels := [][]byte{level}
+	for len(level) > BlockSize {
+		if level, _, err = hashLevel(bytes.NewReader(level)); err != nil {
+			return nil, 0, err
+		}
+		levels = append(levels, level)
+	}
+	var tree bytes.Buffer
+	for i := len(levels) - 1; i >= 0; i-- {
+		tree.Write(levels[i])
+	}
+	return tree.Bytes(), dataBlocks, nil
+}
+
+// MerkleTree returns the hash tree of the data read from `r` with its levels
+// ordered from the root down. The size of the data MUST be a multiple of
+// `BlockSize`.
+func MerkleTree(r io.Reader) ([]byte, error) {
+	tree, _, err := merkleTree(r)
+	return tree, err
+}
+
+// RootHash returns the root hash of the hash tree `tree`.
+func RootHash(tree []byte) []byte {
+	return hashBlock(tree[:BlockSize])
+}
+
+// newSuperblock returns the superblock of a hash device of `dataBlocks` data
+// blocks padded to a block.
+func newSuperblock(dataBlocks uint64) ([]byte, error) {
+	sb := superblock{
+		Version:       Version,
+		HashType:      1,
+		DataBlockSize: BlockSize,
+		HashBlockSize: BlockSize,
+		DataBlocks:    dataBlocks,
+		SaltSize:      saltSize,
+	}
+	copy(sb.Signature[:], signature)
+	copy(sb.Algorithm[:], Algorithm)
+	copy(sb.Salt[:], salt[:])
+
+	var b bytes.Buffer
+	if err := binary.Write(&b, binary.LittleEndian, &sb); err != nil {
+		return nil, err
+	}
+	b.Write(make([]byte, BlockSize-b.Len()))
+	return b.Bytes(), nil
+}
+
+// HashDevice returns the hash device of the data read from `r`: a superblock
+// padded to a block followed by the hash tree. The size of the data MUST be a
+// multiple of `BlockSize`.
+//
+// The hash tree is held in memory. Use `WriteHashDevice` for large data.
+func HashDevice(r io.Reader) ([]byte, error) {
+	tree, dataBlocks, err := merkleTree(r)
+	if err != nil {
+		return nil, err
+	}
+	sb, err := newSuperblock(dataBlocks)
+	if err != nil {
+		return nil, err
+	}
+	return append(sb, tree...), nil
+}
+
+// levelBlocks returns the number of blocks of each level of the hash tree of
+// `dataBlocks` data blocks, from the leaves up to the root.
+func levelBlocks(dataBlocks uint64) []uint64 {
+	var levels []uint64
+	for n := dataBlocks; ; {
+		n = (n + hashesPerBlock - 1) / hashesPerBlock
+		levels = append(levels, n)
+		if n == 1 {
+			return levels
+		}
+	}
+}
+
+// hashBlocksAt hashes the `n` blocks of `f` at `from` into the blocks of `f`
+// at `to`, `hashesPerBlock` blocks at a time.
+func hashBlocksAt(f io.ReadWriteSeeker, from, to int64, n uint64) error {
+	var (
+		b      = make([]byte, hashesPerBlock*BlockSize)
+		hashes = make([]byte, BlockSize)
+	)
+	for n > 0 {
+		count := uint64(hashesPerBlock)
+		if n < count {
+			count = n
+		}
+		if _, err := f.Seek(from, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(f, b[:count*BlockSize]); err != nil {
+			return fmt.Errorf("dmverity: failed to read blocks to hash: %s", err)
+		}
+		for i := range hashes {
+			hashes[i] = 0
+		}
+		for i := uint64(0); i < count; i++ {
+			copy(hashes[i*sha256.Size:], hashBlock(b[i*BlockSize:(i+1)*BlockSize]))
+		}
+		if _, err := f.Seek(to, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err := f.Write(hashes); err != nil {
+			return err
+		}
+		from += int64(count * BlockSize)
+		to += BlockSize
+		n -= count
+	}
+	return nil
+}
+
+// WriteHashDevice writes the hash device of the first `dataSize` bytes of `f`
+// to `f` at offset `dataSize`, which MUST be a multiple of `BlockSize`, and
+// returns the hex encoded root digest of its hash tree. Unlike `HashDevice` the
+// hash tree is built in `f` a level at a time so the memory used does not
+// depend on the size of the data. On success the offset of `f` is the end of
+// the hash device.
+//
+// The root digest is what the data is verified against, so it MUST be kept
+// apart from the image rather than read back from it.
+func WriteHashDevice(f io.ReadWriteSeeker, dataSize int64) (string, error) {
+	if dataSize <= 0 {
+		return "", errors.New("dmverity: no data")
+	}
+	if dataSize%BlockSize != 0 {
+		return "", fmt.Errorf("dmverity: data size is not a multiple of %d bytes", BlockSize)
+	}
+	dataBlocks := uint64(dataSize / BlockSize)
+	sb, err := newSuperblock(dataBlocks)
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Seek(dataSize, io.SeekStart); err != nil {
+		return "", err
+	}
+	if _, err := f.Write(sb); err != nil {
+		return "", err
+	}
+
+	// The levels are laid out from the root down so the leaves are last.
+	levels := levelBlocks(dataBlocks)
+	offsets := make([]int64, len(levels))
+	end := dataSize + BlockSize
+	for i := len(levels) - 1; i >= 0; i-- {
+		offsets[i] = end
+		end += int64(levels[i] * BlockSize)
+	}
+	from, n := int64(0), dataBlocks
+	for i := range levels {
+		if err := hashBlocksAt(f, from, offsets[i], n); err != nil {
+			return "", err
+		}
+		from, n = offsets[i], levels[i]
+	}
+	// `from` is now the single block of the root level.
+	top := make([]byte, BlockSize)
+	if _, err := f.Seek(from, io.SeekStart); err != nil {
+		return "", err
+	}
+	if _, err := io.ReadFull(f, top); err != nil {
+		return "", fmt.Errorf("dmverity: failed to read hash tree: %s", err)
+	}
+	if _, err := f.Seek(end, io.SeekStart); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hashBlock(top)), nil
+}
+
+// ReadInfo reads the dm-verity information of the ext4 image `r` from the hash
+// device appended to it. If the image has no hash device returns
+// `ErrNoHashDevice`. The root digest is not read as the image is not trusted.
+// It is returned by `WriteHashDevice` when the image is built.
+func ReadInfo(r io.ReaderAt) (*Info, error) {
+	var ext4sb format.SuperBlock
+	if err := binary.Read(io.NewSectionReader(r, 1024, 1024), binary.LittleEndian, &ext4sb); err != nil {
+		return nil, fmt.Errorf("dmverity: failed to read ext4 superblock: %s", err)
+	}
+	if ext4sb.Magic != format.SuperBlockMagic {
+		return nil, errors.New("dmverity: image is not ext4")
+	}
+	blocks := int64(ext4sb.BlocksCountHigh)<<32 | int64(ext4sb.BlocksCountLow)
+	dataSize := blocks * int64(1024<<ext4sb.LogBlockSize)
+
+	var sb superblock
+	if err := binary.Read(io.NewSectionReader(r, dataSize, BlockSize), binary.LittleEndian, &sb); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrNoHashDevice
+		}
+		return nil, err
+	}
+	if string(bytes.TrimRight(sb.Signature[:], "\x00")) != signature {
+		return nil, ErrNoHashDevice
+	}
+	algorithm := string(bytes.TrimRight(sb.Algorithm[:], "\x00"))
+	if sb.Version != Version || algorithm != Algorithm || sb.DataBlockSize != BlockSize || sb.HashBlockSize != BlockSize {
+		return nil, fmt.Errorf("dmverity: unsupported hash device version %d %s with %d/%d byte blocks", sb.Version, algorithm, sb.DataBlockSize, sb.HashBlockSize)
+	}
+	if sb.SaltSize > uint16(len(sb.Salt)) {
+		return nil, fmt.Errorf("dmverity: invalid salt size %d", sb.SaltSize)
+	}
+	if sb.DataBlocks*BlockSize != uint64(dataSize) {
+		return nil, fmt.Errorf("dmverity: hash device covers %d blocks but the image has %d", sb.DataBlocks, dataSize/BlockSize)
+	}
+	return &Info{
+		DataSize:  dataSize,
+		BlockSize: BlockSize,
+		Version:   sb.Version,
+		Algorithm: algorithm,
+		Salt:      hex.EncodeToString(sb.Salt[:sb.SaltSize]),
+	}, nil
+}
@@ -0,0 +1,177 @@
+package dmverity
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/ext4/internal/compactext4"
+)
+
+func TestMerkleTree_SingleBlock(t *testing.T) {
+	data := make([]byte, BlockSize)
+	tree, err := MerkleTree(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := make([]byte, BlockSize)
+	copy(expected, hashBlock(data))
+	if !bytes.Equal(tree, expected) {
+		t.Fatal("expected the tree to be the hash of the only block")
+	}
+}
+
+func TestMerkleTree_Levels(t *testing.T) {
+	// 129 blocks need two hash blocks, which need a third block above them.
+	data := make([]byte, 129*BlockSize)
+	for i := range data {
+		data[i] = byte(i / BlockSize)
+	}
+	tree, err := MerkleTree(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree) != 3*BlockSize {
+		t.Fatalf("expected 3 hash blocks, got: %d bytes", len(tree))
+	}
+	leaves := tree[BlockSize:]
+	for i := 0; i < 129; i++ {
+		if !bytes.Equal(leaves[i*32:(i+1)*32], hashBlock(data[i*BlockSize:(i+1)*BlockSize])) {
+			t.Fatalf("wrong hash of block %d", i)
+		}
+	}
+	if !bytes.Equal(tree[:32], hashBlock(leaves[:BlockSize])) || !bytes.Equal(tree[32:64], hashBlock(leaves[BlockSize:])) {
+		t.Fatal("expected the root level to be first")
+	}
+}
+
+func TestMerkleTree_Unaligned(t *testing.T) {
+	if _, err := MerkleTree(bytes.NewReader(make([]byte, BlockSize+1))); err == nil {
+		t.Fatal("expected error for data that is not block aligned")
+	}
+	if _, err := MerkleTree(bytes.NewReader(nil)); err == nil {
+		t.Fatal("expected error for no data")
+	}
+}
+
+// writeImage writes an ext4 image with a single file to a temporary file.
+func writeImage(t *testing.T) *os.File {
+	f, err := ioutil.TempFile("", "dmverity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := compactext4.NewWriter(f)
+	if err := w.Create("file", &compactext4.File{Mode: compactext4.S_IFREG | 0644, Size: 5}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestReadInfo(t *testing.T) {
+	f := writeImage(t)
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if _, err := ReadInfo(f); err != ErrNoHashDevice {
+		t.Fatalf("expected %v, got: %v", ErrNoHashDevice, err)
+	}
+
+	data, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	device, err := HashDevice(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write(device); err != nil {
+		t.Fatal(err)
+	}
+	// Anything after the hash device, such as a VHD footer, is ignored.
+	if _, err := f.Write(make([]byte, 512)); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := ReadInfo(f)
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if info.DataSize != int64(len(data)) {
+		t.Fatalf("expected data size %d, got: %d", len(data), info.DataSize)
+	}
+	if info.Version != Version || info.Algorithm != Algorithm || info.BlockSize != BlockSize {
+		t.Fatalf("unexpected hash format: %+v", info)
+	}
+	if info.Salt != hex.EncodeToString(salt[:]) {
+		t.Fatalf("expected salt %x, got: %s", salt, info.Salt)
+	}
+}
+
+func TestWriteHashDevice(t *testing.T) {
+	// 128*128+1 blocks need three levels.
+	for _, blocks := range []int{1, 129, 128*128 + 1} {
+		data := make([]byte, blocks*BlockSize)
+		for i := range data {
+			data[i] = byte(i / BlockSize)
+		}
+		expected, err := HashDevice(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := ioutil.TempFile("", "dmverity")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(f.Name())
+		defer f.Close()
+		if _, err := f.Write(data); err != nil {
+			t.Fatal(err)
+		}
+		root, err := WriteHashDevice(f, int64(len(data)))
+		if err != nil {
+			t.Fatalf("expected nil error, got: %v", err)
+		}
+		tree, err := MerkleTree(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := hex.EncodeToString(RootHash(tree)); root != expected {
+			t.Fatalf("expected root digest %s, got: %s", expected, root)
+		}
+		if off, _ := f.Seek(0, io.SeekCurrent); off != int64(len(data)+len(expected)) {
+			t.Fatalf("expected offset %d, got: %d", len(data)+len(expected), off)
+		}
+		actual, err := ioutil.ReadFile(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(actual[len(data):], expected) {
+			t.Fatalf("expected the hash device of %d blocks to match HashDevice", blocks)
+		}
+	}
+}
+
+func TestWriteHashDevice_Unaligned(t *testing.T) {
+	f, err := ioutil.TempFile("", "dmverity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	if _, err := WriteHashDevice(f, BlockSize+1); err == nil {
+		t.Fatal("expected error for data that is not block aligned")
+	}
+	if _, err := WriteHashDevice(f, 0); err == nil {
+		t.Fatal("expected error for no data")
+	}
+}
@@ -0,0 +1,288 @@
+package ext4reader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/Microsoft/hcsshim/ext4/internal/format"
+)
+
+const (
+	// maxExtentDepth is the maximum depth of an extent tree.
+	maxExtentDepth = 5
+	// uninitializedExtent is added to the length of extents that are
+	// allocated but not written, which read as zeroes.
+	uninitializedExtent = 32768
+	// directBlocks is the number of direct blocks in a block map.
+	directBlocks = 12
+)
+
+// extent maps `length` blocks of a file from `block` to the blocks from
+// `start`, or to zeroes if `start` is 0.
+type extent struct {
+	block  uint32
+	length uint32
+	start  uint64
+}
+
+// dataReader reads the data of an inode.
+type dataReader struct {
+	fs   *Reader
+	in   *inode
+	size int64
+	// inline is the data of an inode with inline data.
+	inline []byte
+	// extents are the extents of an inode with an extent tree, sorted by
+	// block.
+	extents []extent
+}
+
+func (fs *Reader) newDataReader(in *inode) (*dataReader, error) {
+	if in.Flags&format.InodeFlagEncrypted != 0 {
+		return nil, fmt.Errorf("ext4reader: inode %d is encrypted", in.number)
+	}
+	d := &dataReader{fs: fs, in: in, size: in.size()}
+	switch {
+	case in.Flags&format.InodeFlagInlineData != 0:
+		xattrs, err := fs.xattrs(in)
+		if err != nil {
+			return nil, err
+		}
+		d.inline = append(in.Block[:], xattrs["system.data"]...)
+		if int64(len(d.inline)) < d.size {
+			return nil, fmt.Errorf("ext4reader: inode %d has too little inline data", in.number)
+		}
+	case in.Flags&format.InodeFlagExtents != 0:
+		var err error
+		if d.extents, err = fs.readExtents(in.Block[:], -1, nil); err != nil {
+			return nil, fmt.Errorf("%s in inode %d", err, in.number)
+		}
+		sort.Slice(d.extents, func(i, j int) bool { return d.extents[i].block < d.extents[j].block })
+	}
+	return d, nil
+}
+
+// readExtents appends the extents of the extent tree node `node` of depth
+// `depth`, or of any depth if -1, to `extents`.
+func (fs *Reader) readExtents(node []byte, depth int, extents []extent) ([]extent, error) {
+	var h format.ExtentHeader
+	if err := binary.Read(bytes.NewReader(node), binary.LittleEndian, &h); err != nil {
+		return nil, err
+	}
+	if h.Magic != format.ExtentHeaderMagic || h.Depth > maxExtentDepth || (depth >= 0 && int(h.Depth) != depth) {
+		return nil, errors.New("ext4reader: invalid extent tree")
+	}
+	if 12+int(h.Entries)*12 > len(node) {
+		return nil, errors.New("ext4reader: invalid extent tree entry count")
+	}
+	for i := 0; i < int(h.Entries); i++ {
+		entry := bytes.NewReader(node[12+i*12:])
+		if h.Depth == 0 {
+			var leaf format.ExtentLeafNode
+			if err := binary.Read(entry, binary.LittleEndian, &leaf); err != nil {
+				return nil, err
+			}
+			e := extent{
+				block:  leaf.Block,
+				length: uint32(leaf.Length),
+				start:  uint64(leaf.StartHigh)<<32 | uint64(leaf.StartLow),
+			}
+			if e.length > uninitializedExtent {
+				e.length -= uninitializedExtent
+				e.start = 0
+			}
+			extents = append(extents, e)
+			continue
+		}
+		var index format.ExtentIndexNode
+		if err := binary.Read(entry, binary.LittleEndian, &index); err != nil {
+			return nil, err
+		}
+		child, err := fs.readBlock(uint64(index.LeafHigh)<<32 | uint64(index.LeafLow))
+		if err != nil {
+			return nil, err
+		}
+		if extents, err = fs.readExtents(child, int(h.Depth)-1, extents); err != nil {
+			return nil, err
+		}
+	}
+	return extents, nil
+}
+
+// mapBlock returns the block holding the block `block` of the file, or 0 if
+// it reads as zeroes, and the number of blocks mapped the same way from it.
+func (d *dataReader) mapBlock(block uint32) (uint64, uint64, error) {
+	if d.in.Flags&format.InodeFlagExtents != 0 {
+		i := sort.Search(len(d.extents), func(i int) bool {
+			return uint64(d.extents[i].block)+uint64(d.extents[i].length) > uint64(block)
+		})
+		if i == len(d.extents) {
+			return 0, 1<<32 - uint64(block), nil
+		}
+		e := d.extents[i]
+		if e.block > block {
+			return 0, uint64(e.block - block), nil
+		}
+		end := uint64(e.block) + uint64(e.length)
+		if e.start == 0 {
+			return 0, end - uint64(block), nil
+		}
+		return e.start + uint64(block-e.block), end - uint64(block), nil
+	}
+
+	// A block map has direct blocks followed by an indirect, double indirect
+	// and triple indirect block.
+	var blocks [15]uint32
+	if err := binary.Read(bytes.NewReader(d.in.Block[:]), binary.LittleEndian, &blocks); err != nil {
+		return 0, 0, err
+	}
+	if block < directBlocks {
+		return uint64(blocks[block]), 1, nil
+	}
+	perBlock := uint64(d.fs.blockSize / 4)
+	rest := uint64(block - directBlocks)
+	for level, n := 1, perBlock; level <= 3; level, n = level+1, n*perBlock {
+		if rest >= n {
+			rest -= n
+			continue
+		}
+		b := uint64(blocks[directBlocks+level-1])
+		for ; level > 0 && b != 0; level-- {
+			n /= perBlock
+			var ptr [4]byte
+			if _, err := d.fs.r.ReadAt(ptr[:], int64(b)*d.fs.blockSize+int64(rest/n)*4); err != nil {
+				return 0, 0, fmt.Errorf("ext4reader: failed to read indirect block %d: %s", b, err)
+			}
+			b = uint64(binary.LittleEndian.Uint32(ptr[:]))
+			rest %= n
+		}
+		return b, 1, nil
+	}
+	return 0, 0, fmt.Errorf("ext4reader: block %d of inode %d is out of range", block, d.in.number)
+}
+
+// ReadAt reads the data of the inode at `off`.
+func (d *dataReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("ext4reader: negative offset")
+	}
+	if off >= d.size {
+		return 0, io.EOF
+	}
+	if d.inline != nil {
+		n := copy(p, d.inline[off:d.size])
+		if n < len(p) {
+			return n, io.EOF
+		}
+		return n, nil
+	}
+	read := 0
+	for read < len(p) && off < d.size {
+		block, in := uint32(off/d.fs.blockSize), off%d.fs.blockSize
+		start, count, err := d.mapBlock(block)
+		if err != nil {
+			return read, err
+		}
+		n := int64(len(p) - read)
+		if max := int64(count)*d.fs.blockSize - in; n > max {
+			n = max
+		}
+		if n > d.size-off {
+			n = d.size - off
+		}
+		b := p[read : int64(read)+n]
+		if start == 0 {
+			for i := range b {
+				b[i] = 0
+			}
+		} else if _, err := d.fs.r.ReadAt(b, int64(start)*d.fs.blockSize+in); err != nil {
+			return read, fmt.Errorf("ext4reader: failed to read data of inode %d: %s", d.in.number, err)
+		}
+		read += len(b)
+		off += n
+	}
+	if read < len(p) {
+		return read, io.EOF
+	}
+	return read, nil
+}
+
+// xattrPrefixes are the prefixes of extended attribute names by their index.
+var xattrPrefixes = map[uint8]string{
+	1: "user.",
+	2: "system.posix_acl_access",
+	3: "system.posix_acl_default",
+	4: "trusted.",
+	6: "security.",
+	7: "system.",
+	8: "system.richacl",
+}
+
+// parseXattrs adds the extended attribute entries in `b` to `xattrs`. The
+// value offsets are relative to `values`.
+func (fs *Reader) parseXattrs(b, values []byte, xattrs map[string][]byte) error {
+	for len(b) >= 4 && binary.LittleEndian.Uint32(b) != 0 {
+		var e format.XAttrEntry
+		if err := binary.Read(bytes.NewReader(b), binary.LittleEndian, &e); err != nil {
+			return errors.New("ext4reader: invalid extended attribute entry")
+		}
+		entryLen := (16 + int(e.NameLength) + 3) &^ 3
+		if entryLen > len(b) {
+			return errors.New("ext4reader: invalid extended attribute entry")
+		}
+		name := xattrPrefixes[e.NameIndex] + string(b[16:16+int(e.NameLength)])
+		if e.ValueInum != 0 {
+			// The value is the data of an inode.
+			in, err := fs.readInode(e.ValueInum)
+			if err != nil {
+				return err
+			}
+			d, err := fs.newDataReader(in)
+			if err != nil {
+				return err
+			}
+			value := make([]byte, e.ValueSize)
+			if _, err := d.ReadAt(value, 0); err != nil && err != io.EOF {
+				return err
+			}
+			xattrs[name] = value
+		} else {
+			end := int(e.ValueOffset) + int(e.ValueSize)
+			if end > len(values) {
+				return errors.New("ext4reader: invalid extended attribute value")
+			}
+			xattrs[name] = values[e.ValueOffset:end]
+		}
+		b = b[entryLen:]
+	}
+	return nil
+}
+
+// xattrs returns the extended attributes of the inode `in`, which are stored
+// after the extra fields of the inode and in an extended attribute block.
+func (fs *Reader) xattrs(in *inode) (map[string][]byte, error) {
+	xattrs := make(map[string][]byte)
+	if block := uint64(in.XattrBlockHigh)<<32 | uint64(in.XattrBlockLow); block != 0 {
+		b, err := fs.readBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		if binary.LittleEndian.Uint32(b) != format.XAttrHeaderMagic {
+			return nil, fmt.Errorf("ext4reader: invalid extended attribute block of inode %d", in.number)
+		}
+		if err := fs.parseXattrs(b[32:], b, xattrs); err != nil {
+			return nil, err
+		}
+	}
+	if len(in.extra) >= 4 && binary.LittleEndian.Uint32(in.extra) == format.XAttrHeaderMagic {
+		entries := in.extra[4:]
+		if err := fs.parseXattrs(entries, entries, xattrs); err != nil {
+			return nil, err
+		}
+	}
+	return xattrs, nil
+}
@@ -0,0 +1,481 @@
+// Package ext4reader reads the files of an ext4 file system image, such as an
+// LCOW layer or scratch disk, on the host without mounting it in a utility VM.
+//
+// The image may be a raw ext4 image, a fixed VHD, as written by tar2ext4, or a
+// fixed or dynamic VHDX with the file system on the whole disk. The journal is
+// not replayed, so the files of a disk that is in use or was not unmounted
+// cleanly may be out of date.
+package ext4reader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Microsoft/hcsshim/ext4/internal/format"
+	"github.com/Microsoft/hcsshim/ext4/internal/vhdx"
+)
+
+// Linux file types of `File.Mode`.
+const (
+	S_IFIFO  = format.S_IFIFO
+	S_IFCHR  = format.S_IFCHR
+	S_IFDIR  = format.S_IFDIR
+	S_IFBLK  = format.S_IFBLK
+	S_IFREG  = format.S_IFREG
+	S_IFLNK  = format.S_IFLNK
+	S_IFSOCK = format.S_IFSOCK
+
+	TypeMask = format.TypeMask
+)
+
+const (
+	// supportedIncompat are the incompatible features that do not change how
+	// the files are read, or that are handled by the Reader.
+	supportedIncompat = format.IncompatFiletype | format.IncompatRecover |
+		format.IncompatExtents | format.Incompat_64Bit | format.IncompatMmp |
+		format.IncompatFlexBg | format.IncompatEaInode | format.IncompatCsumSeed |
+		format.IncompatLargedir | format.IncompatInlineData
+
+	// maxSymlinks is the number of symbolic links followed looking up a path,
+	// as on Linux.
+	maxSymlinks = 40
+	// maxLinkSize is the largest symbolic link target read.
+	maxLinkSize = 4096
+)
+
+var (
+	errNotDir       = errors.New("not a directory")
+	errNotRegular   = errors.New("not a regular file")
+	errTooManyLinks = errors.New("too many levels of symbolic links")
+
+	inodeStructSize = binary.Size(format.Inode{})
+)
+
+// File is the metadata of a file in the file system.
+type File struct {
+	Inode                       uint32
+	Linkname                    string
+	Size                        int64
+	Mode                        uint16
+	Uid, Gid                    uint32
+	Atime, Ctime, Mtime, Crtime time.Time
+	Devmajor, Devminor          uint32
+	Xattrs                      map[string][]byte
+}
+
+// IsDir returns true if the file is a directory.
+func (f *File) IsDir() bool {
+	return f.Mode&TypeMask == S_IFDIR
+}
+
+// Reader reads the files of an ext4 file system.
+type Reader struct {
+	r         io.ReaderAt
+	sb        format.SuperBlock
+	blockSize int64
+	inodeSize int64
+	descSize  int64
+	gdtOffset int64
+}
+
+// inode is an inode read from the inode table.
+type inode struct {
+	format.Inode
+	number uint32
+	// extra is the part of the on-disk inode after the extra fields, which
+	// holds the inline extended attributes.
+	extra []byte
+}
+
+func (in *inode) fileType() uint16 {
+	return in.Mode & TypeMask
+}
+
+func (in *inode) size() int64 {
+	return int64(in.SizeHigh)<<32 | int64(in.SizeLow)
+}
+
+// NewReader returns a Reader for the ext4 file system image `r`.
+func NewReader(r io.ReaderAt) (*Reader, error) {
+	if vhdx.IsVHDX(r) {
+		vr, err := vhdx.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		r = vr
+	}
+	fs := &Reader{r: r}
+	if err := binary.Read(io.NewSectionReader(r, 1024, 1024), binary.LittleEndian, &fs.sb); err != nil {
+		return nil, fmt.Errorf("ext4reader: failed to read superblock: %s", err)
+	}
+	if fs.sb.Magic != format.SuperBlockMagic {
+		return nil, errors.New("ext4reader: not an ext4 file system")
+	}
+	if unsupported := fs.sb.FeatureIncompat &^ supportedIncompat; unsupported != 0 {
+		return nil, fmt.Errorf("ext4reader: unsupported incompatible features %#x", uint32(unsupported))
+	}
+	if fs.sb.LogBlockSize > 6 || fs.sb.InodesPerGroup == 0 {
+		return nil, errors.New("ext4reader: invalid superblock")
+	}
+	fs.blockSize = 1024 << fs.sb.LogBlockSize
+	fs.inodeSize = 128
+	if fs.sb.RevisionLevel != 0 {
+		fs.inodeSize = int64(fs.sb.InodeSize)
+		if fs.inodeSize < 128 || fs.inodeSize > fs.blockSize || fs.inodeSize&(fs.inodeSize-1) != 0 {
+			return nil, fmt.Errorf("ext4reader: invalid inode size %d", fs.inodeSize)
+		}
+	}
+	fs.descSize = 32
+	if fs.sb.FeatureIncompat&format.Incompat_64Bit != 0 {
+		fs.descSize = int64(fs.sb.DescSize)
+		if fs.descSize < 64 || fs.descSize > fs.blockSize {
+			return nil, fmt.Errorf("ext4reader: invalid group descriptor size %d", fs.descSize)
+		}
+	}
+	fs.gdtOffset = (int64(fs.sb.FirstDataBlock) + 1) * fs.blockSize
+	return fs, nil
+}
+
+// readBlock reads the block `block`.
+func (fs *Reader) readBlock(block uint64) ([]byte, error) {
+	b := make([]byte, fs.blockSize)
+	if _, err := fs.r.ReadAt(b, int64(block)*fs.blockSize); err != nil {
+		return nil, fmt.Errorf("ext4reader: failed to read block %d: %s", block, err)
+	}
+	return b, nil
+}
+
+// inodeTable returns the first block of the inode table of the group `group`.
+func (fs *Reader) inodeTable(group uint32) (uint64, error) {
+	b := make([]byte, binary.Size(format.GroupDescriptor64{}))
+	if _, err := fs.r.ReadAt(b[:fs.descSize], fs.gdtOffset+int64(group)*fs.descSize); err != nil {
+		return 0, fmt.Errorf("ext4reader: failed to read group descriptor %d: %s", group, err)
+	}
+	var gd format.GroupDescriptor64
+	if err := binary.Read(bytes.NewReader(b), binary.LittleEndian, &gd); err != nil {
+		return 0, err
+	}
+	return uint64(gd.InodeTableHigh)<<32 | uint64(gd.InodeTableLow), nil
+}
+
+// readInode reads the inode `ino` from the inode table.
+func (fs *Reader) readInode(ino uint32) (*inode, error) {
+	if ino == 0 || ino > fs.sb.InodesCount {
+		return nil, fmt.Errorf("ext4reader: invalid inode %d", ino)
+	}
+	group, index := (ino-1)/fs.sb.InodesPerGroup, (ino-1)%fs.sb.InodesPerGroup
+	table, err := fs.inodeTable(group)
+	if err != nil {
+		return nil, err
+	}
+	raw := make([]byte, fs.inodeSize)
+	if _, err := fs.r.ReadAt(raw, int64(table)*fs.blockSize+int64(index)*fs.inodeSize); err != nil {
+		return nil, fmt.Errorf("ext4reader: failed to read inode %d: %s", ino, err)
+	}
+
+	// Only the extra fields covered by the extra size are valid.
+	b := make([]byte, inodeStructSize)
+	extraEnd := int64(128)
+	if fs.inodeSize > 128 {
+		extraEnd += int64(binary.LittleEndian.Uint16(raw[128:]))
+		if extraEnd > fs.inodeSize {
+			return nil, fmt.Errorf("ext4reader: inode %d has invalid extra size", ino)
+		}
+	}
+	copy(b, raw[:extraEnd])
+	in := &inode{number: ino, extra: raw[extraEnd:]}
+	if err := binary.Read(bytes.NewReader(b), binary.LittleEndian, &in.Inode); err != nil {
+		return nil, err
+	}
+	return in, nil
+}
+
+// fsTime returns the time of an inode timestamp and its extra field, which
+// holds the nanoseconds and the epoch bits extending the seconds.
+func fsTime(base, extra uint32) time.Time {
+	if base == 0 && extra == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(int32(base))+int64(extra&3)<<32, int64(extra>>2))
+}
+
+// file returns the metadata of the inode `in`.
+func (fs *Reader) file(in *inode) (*File, error) {
+	xattrs, err := fs.xattrs(in)
+	if err != nil {
+		return nil, err
+	}
+	delete(xattrs, "system.data")
+	f := &File{
+		Inode:  in.number,
+		Size:   in.size(),
+		Mode:   in.Mode,
+		Uid:    uint32(in.UidHigh)<<16 | uint32(in.Uid),
+		Gid:    uint32(in.GidHigh)<<16 | uint32(in.Gid),
+		Atime:  fsTime(in.Atime, in.AtimeExtra),
+		Ctime:  fsTime(in.Ctime, in.CtimeExtra),
+		Mtime:  fsTime(in.Mtime, in.MtimeExtra),
+		Crtime: fsTime(in.Crtime, in.CrtimeExtra),
+		Xattrs: xattrs,
+	}
+	switch in.fileType() {
+	case S_IFCHR, S_IFBLK:
+		if dev := binary.LittleEndian.Uint32(in.Block[0:]); dev != 0 {
+			f.Devmajor, f.Devminor = dev>>8&0xff, dev&0xff
+		} else {
+			dev = binary.LittleEndian.Uint32(in.Block[4:])
+			f.Devmajor, f.Devminor = dev>>8&0xfff, dev&0xff|dev>>12&0xfff00
+		}
+	case S_IFLNK:
+		if f.Linkname, err = fs.readLink(in); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// readLink returns the target of the symbolic link `in`.
+func (fs *Reader) readLink(in *inode) (string, error) {
+	size := in.size()
+	if size > maxLinkSize {
+		return "", fmt.Errorf("ext4reader: symbolic link inode %d is too long", in.number)
+	}
+	// Short targets are stored in place of the block map.
+	if in.Flags&(format.InodeFlagExtents|format.InodeFlagInlineData) == 0 && size < int64(len(in.Block)) {
+		return string(in.Block[:size]), nil
+	}
+	d, err := fs.newDataReader(in)
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, size)
+	if _, err := d.ReadAt(b, 0); err != nil && err != io.EOF {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// dirEntry is an entry of a directory other than `.` and `..`.
+type dirEntry struct {
+	name  string
+	inode uint32
+}
+
+// parseDirEntries appends the directory entries in `b` to `entries`.
+func (fs *Reader) parseDirEntries(b []byte, entries []dirEntry) ([]dirEntry, error) {
+	for len(b) >= 8 {
+		ino := binary.LittleEndian.Uint32(b[0:])
+		recLen := int(binary.LittleEndian.Uint16(b[4:]))
+		nameLen := int(b[6])
+		if fs.blockSize == 65536 && (recLen == 0 || recLen == 65535) {
+			recLen = 65536
+		}
+		if recLen < 8 || recLen > len(b) || 8+nameLen > recLen {
+			return nil, errors.New("ext4reader: invalid directory entry")
+		}
+		// Entries with no inode are unused, or hold the hash tree index or
+		// checksum of the block.
+		if name := string(b[8 : 8+nameLen]); ino != 0 && name != "." && name != ".." {
+			entries = append(entries, dirEntry{name, ino})
+		}
+		b = b[recLen:]
+	}
+	return entries, nil
+}
+
+// readDir returns the entries of the directory `in` sorted by name.
+func (fs *Reader) readDir(in *inode) ([]dirEntry, error) {
+	if in.fileType() != S_IFDIR {
+		return nil, errNotDir
+	}
+	var entries []dirEntry
+	if in.Flags&format.InodeFlagInlineData != 0 {
+		// The block map holds the parent's inode number followed by the
+		// first entries, and the rest of the entries are in an extended
+		// attribute.
+		xattrs, err := fs.xattrs(in)
+		if err != nil {
+			return nil, err
+		}
+		if entries, err = fs.parseDirEntries(in.Block[4:], entries); err != nil {
+			return nil, err
+		}
+		if entries, err = fs.parseDirEntries(xattrs["system.data"], entries); err != nil {
+			return nil, err
+		}
+	} else {
+		d, err := fs.newDataReader(in)
+		if err != nil {
+			return nil, err
+		}
+		b := make([]byte, fs.blockSize)
+		for off := int64(0); off < d.size; off += fs.blockSize {
+			if _, err := d.ReadAt(b, off); err != nil && err != io.EOF {
+				return nil, err
+			}
+			if entries, err = fs.parseDirEntries(b, entries); err != nil {
+				return nil, fmt.Errorf("%s in inode %d", err, in.number)
+			}
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+	return entries, nil
+}
+
+// lookup returns the inode of the file `name`. Symbolic links are followed
+// within the file system, and for the last element of `name` if `follow`.
+func (fs *Reader) lookup(name string, follow bool) (*inode, error) {
+	root, err := fs.readInode(format.InodeRoot)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		dirs  = []*inode{root}
+		elems = strings.Split(name, "/")
+		links int
+	)
+	for len(elems) != 0 {
+		elem := elems[0]
+		elems = elems[1:]
+		switch elem {
+		case "", ".":
+			continue
+		case "..":
+			if len(dirs) > 1 {
+				dirs = dirs[:len(dirs)-1]
+			}
+			continue
+		}
+		entries, err := fs.readDir(dirs[len(dirs)-1])
+		if err != nil {
+			return nil, &os.PathError{Op: "lookup", Path: name, Err: err}
+		}
+		i := sort.Search(len(entries), func(i int) bool { return entries[i].name >= elem })
+		if i == len(entries) || entries[i].name != elem {
+			return nil, &os.PathError{Op: "lookup", Path: name, Err: os.ErrNotExist}
+		}
+		in, err := fs.readInode(entries[i].inode)
+		if err != nil {
+			return nil, err
+		}
+		if in.fileType() == S_IFLNK && (follow || len(elems) != 0) {
+			if links++; links > maxSymlinks {
+				return nil, &os.PathError{Op: "lookup", Path: name, Err: errTooManyLinks}
+			}
+			target, err := fs.readLink(in)
+			if err != nil {
+				return nil, err
+			}
+			if path.IsAbs(target) {
+				dirs = dirs[:1]
+			}
+			elems = append(strings.Split(target, "/"), elems...)
+			continue
+		}
+		dirs = append(dirs, in)
+	}
+	return dirs[len(dirs)-1], nil
+}
+
+// Stat returns the metadata of the file `name`. If it is a symbolic link the
+// metadata of the link itself is returned.
+func (fs *Reader) Stat(name string) (*File, error) {
+	in, err := fs.lookup(name, false)
+	if err != nil {
+		return nil, err
+	}
+	return fs.file(in)
+}
+
+// ReadDir returns the names of the entries of the directory `name`, other than
+// `.` and `..`, sorted by name.
+func (fs *Reader) ReadDir(name string) ([]string, error) {
+	in, err := fs.lookup(name, true)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := fs.readDir(in)
+	if err != nil {
+		return nil, &os.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.name
+	}
+	return names, nil
+}
+
+// Open returns a reader of the contents of the regular file `name`.
+func (fs *Reader) Open(name string) (*io.SectionReader, error) {
+	in, err := fs.lookup(name, true)
+	if err != nil {
+		return nil, err
+	}
+	if in.fileType() != S_IFREG {
+		return nil, &os.PathError{Op: "open", Path: name, Err: errNotRegular}
+	}
+	d, err := fs.newDataReader(in)
+	if err != nil {
+		return nil, err
+	}
+	return io.NewSectionReader(d, 0, d.size), nil
+}
+
+// WalkFunc is called by `Walk` with the path and metadata of each file.
+// Returning an error stops the walk.
+type WalkFunc func(name string, f *File) error
+
+// Walk calls `fn` for the directory `root` and every file below it, in lexical
+// order. Symbolic links other than `root` are not followed. An error is returned
+// if a directory is reached more than once, as the walk would otherwise never
+// end on a corrupt file system with a directory cycle.
+func (fs *Reader) Walk(root string, fn WalkFunc) error {
+	in, err := fs.lookup(root, true)
+	if err != nil {
+		return err
+	}
+	return fs.walk(path.Join("/", root), in, fn, make(map[uint32]bool))
+}
+
+// walk calls `fn` for `in` and every file below it. `dirs` holds the inode
+// numbers of the directories already walked.
+func (fs *Reader) walk(name string, in *inode, fn WalkFunc, dirs map[uint32]bool) error {
+	if in.fileType() == S_IFDIR {
+		if dirs[in.number] {
+			return fmt.Errorf("ext4reader: directory inode %d at %s was already walked", in.number, name)
+		}
+		dirs[in.number] = true
+	}
+	f, err := fs.file(in)
+	if err != nil {
+		return err
+	}
+	if err := fn(name, f); err != nil {
+		return err
+	}
+	if in.fileType() != S_IFDIR {
+		return nil
+	}
+	entries, err := fs.readDir(in)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		child, err := fs.readInode(e.inode)
+		if err != nil {
+			return err
+		}
+		if err := fs.walk(path.Join(name, e.name), child, fn, dirs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
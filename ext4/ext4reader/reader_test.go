@@ -0,0 +1,340 @@
+package ext4reader
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/hcsshim/ext4/tar2ext4"
+)
+
+var (
+	testTime  = time.Unix(1500000000, 123456700)
+	largeData = bytes.Repeat([]byte("0123456789abcdef"), 100000)
+	longLink  = strings.Repeat("x/", 50) + "target"
+)
+
+type testEntry struct {
+	hdr  tar.Header
+	data []byte
+}
+
+var testEntries = []testEntry{
+	{hdr: tar.Header{Name: "dir/", Typeflag: tar.TypeDir, Mode: 0755}},
+	{hdr: tar.Header{Name: "dir/small", Typeflag: tar.TypeReg, Mode: 0644, Uid: 1000, Gid: 70000}, data: []byte("hello")},
+	{hdr: tar.Header{Name: "dir/large", Typeflag: tar.TypeReg, Mode: 04755}, data: largeData},
+	{hdr: tar.Header{Name: "dir/empty", Typeflag: tar.TypeReg, Mode: 0600}},
+	{hdr: tar.Header{Name: "dir/hardlink", Typeflag: tar.TypeLink, Linkname: "dir/small"}},
+	{hdr: tar.Header{Name: "dir/xattrs", Typeflag: tar.TypeReg, Mode: 0644, PAXRecords: map[string]string{
+		"SCHILY.xattr.user.small":   "value",
+		"SCHILY.xattr.trusted.big":  strings.Repeat("v", 500),
+		"SCHILY.xattr.security.foo": "bar",
+	}}},
+	{hdr: tar.Header{Name: "dir/.wh.deleted", Typeflag: tar.TypeReg, Mode: 0644}},
+	{hdr: tar.Header{Name: "fifo", Typeflag: tar.TypeFifo, Mode: 0644}},
+	{hdr: tar.Header{Name: "dev", Typeflag: tar.TypeChar, Mode: 0644, Devmajor: 1, Devminor: 300}},
+	{hdr: tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "dir/small"}},
+	{hdr: tar.Header{Name: "longlink", Typeflag: tar.TypeSymlink, Linkname: longLink}},
+	{hdr: tar.Header{Name: "abslink", Typeflag: tar.TypeSymlink, Linkname: "/dir"}},
+	{hdr: tar.Header{Name: "loop", Typeflag: tar.TypeSymlink, Linkname: "loop"}},
+}
+
+// writeTestImage writes an ext4 image of `testEntries` converted with
+// `options` and returns it.
+func writeTestImage(t *testing.T, options ...tar2ext4.Option) *os.File {
+	var b bytes.Buffer
+	tw := tar.NewWriter(&b)
+	for _, e := range testEntries {
+		hdr := e.hdr
+		hdr.ModTime = testTime
+		hdr.Format = tar.FormatPAX
+		hdr.Size = int64(len(e.data))
+		if err := tw.WriteHeader(&hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(e.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := ioutil.TempFile("", "ext4reader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tar2ext4.Convert(&b, f, options...); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		t.Fatal(err)
+	}
+	return f
+}
+
+func newTestReader(t *testing.T, options ...tar2ext4.Option) (*Reader, func()) {
+	f := writeTestImage(t, options...)
+	cleanup := func() {
+		f.Close()
+		os.Remove(f.Name())
+	}
+	r, err := NewReader(f)
+	if err != nil {
+		cleanup()
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	return r, cleanup
+}
+
+func readFile(t *testing.T, r *Reader, name string) []byte {
+	sr, err := r.Open(name)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", name, err)
+	}
+	b, err := ioutil.ReadAll(sr)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", name, err)
+	}
+	return b
+}
+
+func TestReader(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		options []tar2ext4.Option
+		// deleted is the name of the whiteout of dir/deleted.
+		deleted string
+	}{
+		{"Default", nil, ".wh.deleted"},
+		{"VHD", []tar2ext4.Option{tar2ext4.ConvertWhiteout, tar2ext4.AppendVhdFooter, tar2ext4.AppendDMVerity}, "deleted"},
+		{"InlineData", []tar2ext4.Option{tar2ext4.InlineData}, ".wh.deleted"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r, cleanup := newTestReader(t, test.options...)
+			defer cleanup()
+
+			names, err := r.ReadDir("/dir")
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := []string{test.deleted, "empty", "hardlink", "large", "small", "xattrs"}
+			sort.Strings(expected)
+			if !reflect.DeepEqual(names, expected) {
+				t.Fatalf("expected entries %v, got: %v", expected, names)
+			}
+
+			f, err := r.Stat("dir/small")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if f.Mode != S_IFREG|0644 || f.Size != 5 || f.Uid != 1000 || f.Gid != 70000 || !f.Mtime.Equal(testTime) {
+				t.Fatalf("unexpected metadata: %+v", f)
+			}
+			hardlink, err := r.Stat("dir/hardlink")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hardlink.Inode != f.Inode {
+				t.Fatalf("expected the hard link to be inode %d, got: %d", f.Inode, hardlink.Inode)
+			}
+
+			if b := readFile(t, r, "dir/small"); string(b) != "hello" {
+				t.Fatalf("expected hello, got: %q", b)
+			}
+			if b := readFile(t, r, "dir/large"); !bytes.Equal(b, largeData) {
+				t.Fatalf("wrong data of %d bytes read from the large file", len(b))
+			}
+			if b := readFile(t, r, "dir/empty"); len(b) != 0 {
+				t.Fatalf("expected an empty file, got: %q", b)
+			}
+
+			f, err = r.Stat("dir/xattrs")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(f.Xattrs["user.small"]) != "value" || len(f.Xattrs["trusted.big"]) != 500 || string(f.Xattrs["security.foo"]) != "bar" || len(f.Xattrs) != 3 {
+				t.Fatalf("unexpected extended attributes: %v", f.Xattrs)
+			}
+
+			f, err = r.Stat("dev")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if f.Mode&TypeMask != S_IFCHR || f.Devmajor != 1 || f.Devminor != 300 {
+				t.Fatalf("unexpected device: %+v", f)
+			}
+		})
+	}
+}
+
+func TestReader_Symlinks(t *testing.T) {
+	r, cleanup := newTestReader(t)
+	defer cleanup()
+
+	f, err := r.Stat("link")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Mode&TypeMask != S_IFLNK || f.Linkname != "dir/small" {
+		t.Fatalf("expected a symbolic link to dir/small, got: %+v", f)
+	}
+	f, err = r.Stat("longlink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Linkname != longLink {
+		t.Fatalf("expected a symbolic link to %s, got: %s", longLink, f.Linkname)
+	}
+
+	if b := readFile(t, r, "link"); string(b) != "hello" {
+		t.Fatalf("expected to read hello through the link, got: %q", b)
+	}
+	if b := readFile(t, r, "/abslink/../abslink/small"); string(b) != "hello" {
+		t.Fatalf("expected to read hello through the directory link, got: %q", b)
+	}
+	if _, err := r.Open("loop"); err == nil || !strings.Contains(err.Error(), errTooManyLinks.Error()) {
+		t.Fatalf("expected too many links error, got: %v", err)
+	}
+	if _, err := r.Stat("dir/missing"); !os.IsNotExist(err) {
+		t.Fatalf("expected not exist error, got: %v", err)
+	}
+	if _, err := r.Stat("dir/small/child"); err == nil {
+		t.Fatal("expected error looking up a file in a regular file")
+	}
+	if _, err := r.Open("dir"); err == nil {
+		t.Fatal("expected error opening a directory")
+	}
+}
+
+func TestReader_Walk(t *testing.T) {
+	r, cleanup := newTestReader(t)
+	defer cleanup()
+
+	var names []string
+	if err := r.Walk("abslink", func(name string, f *File) error {
+		names = append(names, name)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"/abslink", "/abslink/.wh.deleted", "/abslink/empty", "/abslink/hardlink", "/abslink/large", "/abslink/small", "/abslink/xattrs"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected %v, got: %v", expected, names)
+	}
+}
+
+func TestReader_Walk_DirectoryCycle(t *testing.T) {
+	f := writeTestImage(t)
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	r, err := NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := r.Stat("dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Point the entry of dir/empty back at dir.
+	b, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := bytes.Index(b, []byte("\x05\x01empty"))
+	if i < 6 {
+		t.Fatal("failed to find the directory entry of dir/empty")
+	}
+	var ino [4]byte
+	binary.LittleEndian.PutUint32(ino[:], dir.Inode)
+	if _, err := f.WriteAt(ino[:], int64(i-6)); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err = NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.Walk("/", func(string, *File) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "already walked") {
+		t.Fatalf("expected a directory cycle error, got: %v", err)
+	}
+}
+
+func TestReader_WriteTar(t *testing.T) {
+	r, cleanup := newTestReader(t)
+	defer cleanup()
+
+	var b bytes.Buffer
+	if err := r.WriteTar(&b, "/"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Converting the tar stream back must produce the same files.
+	f, err := ioutil.TempFile("", "ext4reader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	if err := tar2ext4.Convert(bytes.NewReader(b.Bytes()), f); err != nil {
+		t.Fatal(err)
+	}
+	r2, err := NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := func(r *Reader) map[string]*File {
+		m := make(map[string]*File)
+		if err := r.Walk("/", func(name string, f *File) error {
+			f.Inode = 0
+			if f.IsDir() {
+				f.Size = 0
+				f.Mtime = time.Time{}
+			}
+			m[name] = f
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	expected, actual := files(r), files(r2)
+	for name, f := range expected {
+		a := actual[name]
+		if a == nil {
+			t.Fatalf("%s is missing", name)
+		}
+		if a.Mode != f.Mode || a.Size != f.Size || a.Uid != f.Uid || a.Gid != f.Gid || a.Linkname != f.Linkname ||
+			!a.Mtime.Equal(f.Mtime) || a.Devmajor != f.Devmajor || a.Devminor != f.Devminor || !reflect.DeepEqual(a.Xattrs, f.Xattrs) {
+			t.Fatalf("expected %s to be %+v, got: %+v", name, f, a)
+		}
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %d files, got: %d", len(expected), len(actual))
+	}
+	if b := readFile(t, r2, "dir/large"); !bytes.Equal(b, largeData) {
+		t.Fatal("wrong data of the large file")
+	}
+	if f, _ := r2.Stat("dir/hardlink"); f == nil || f.Size != 5 {
+		t.Fatalf("expected the hard link to be kept, got: %+v", f)
+	}
+}
+
+func TestNewReader_NotExt4(t *testing.T) {
+	if _, err := NewReader(bytes.NewReader(make([]byte, 4096))); err == nil {
+		t.Fatal("expected error for an image that is not ext4")
+	}
+	if _, err := NewReader(bytes.NewReader(nil)); err == nil || err == io.EOF {
+		t.Fatalf("expected error for an empty image, got: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package ext4reader
+
+import (
+	"archive/tar"
+	"io"
+	"path"
+	"strings"
+)
+
+// WriteTar writes the directory `root` and every file below it to `w` as a tar
+// stream with paths relative to `root`. Files linked more than once are
+// written once followed by hard links to it, and sockets are skipped.
+func (fs *Reader) WriteTar(w io.Writer, root string) error {
+	tw := tar.NewWriter(w)
+	root = path.Join("/", root)
+	links := make(map[uint32]string)
+	err := fs.Walk(root, func(name string, f *File) error {
+		name = strings.TrimPrefix(strings.TrimPrefix(name, root), "/")
+		if name == "" {
+			name = "./"
+		}
+		hdr := &tar.Header{
+			Name:       name,
+			Mode:       int64(f.Mode &^ TypeMask),
+			Uid:        int(f.Uid),
+			Gid:        int(f.Gid),
+			ModTime:    f.Mtime,
+			AccessTime: f.Atime,
+			ChangeTime: f.Ctime,
+			Format:     tar.FormatPAX,
+		}
+		if len(f.Xattrs) != 0 {
+			hdr.PAXRecords = make(map[string]string)
+			for k, v := range f.Xattrs {
+				hdr.PAXRecords["SCHILY.xattr."+k] = string(v)
+			}
+		}
+		switch f.Mode & TypeMask {
+		case S_IFDIR:
+			hdr.Typeflag = tar.TypeDir
+			if !strings.HasSuffix(hdr.Name, "/") {
+				hdr.Name += "/"
+			}
+		case S_IFLNK:
+			hdr.Typeflag = tar.TypeSymlink
+			hdr.Linkname = f.Linkname
+		case S_IFCHR:
+			hdr.Typeflag = tar.TypeChar
+			hdr.Devmajor, hdr.Devminor = int64(f.Devmajor), int64(f.Devminor)
+		case S_IFBLK:
+			hdr.Typeflag = tar.TypeBlock
+			hdr.Devmajor, hdr.Devminor = int64(f.Devmajor), int64(f.Devminor)
+		case S_IFIFO:
+			hdr.Typeflag = tar.TypeFifo
+		case S_IFREG:
+			hdr.Typeflag = tar.TypeReg
+			hdr.Size = f.Size
+		default:
+			return nil
+		}
+		if hdr.Typeflag != tar.TypeDir {
+			if target, ok := links[f.Inode]; ok {
+				hdr.Typeflag = tar.TypeLink
+				hdr.Linkname = target
+				hdr.Size = 0
+			} else {
+				links[f.Inode] = hdr.Name
+			}
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			in, err := fs.readInode(f.Inode)
+			if err != nil {
+				return err
+			}
+			d, err := fs.newDataReader(in)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(tw, io.NewSectionReader(d, 0, d.size)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
@@ -27,6 +27,7 @@ type Writer struct {
 	err                  error
 	initialized          bool
 	supportInlineData    bool
+	sparse               bool
 	maxDiskSize          int64
 	gdBlocks             uint32
 }
@@ -104,6 +105,10 @@ const (
 	defaultMaxDiskSize = 16 * 1024 * 1024 * 1024        // 16GB
 	maxMaxDiskSize     = 16 * 1024 * 1024 * 1024 * 1024 // 16TB
 
+	// sparseZeroSize is the smallest run of zeroes that is skipped rather
+	// than written when writing a sparse file system.
+	sparseZeroSize = 64 * 1024
+
 	groupDescriptorSize      = 32 // Use the small group descriptor
 	groupsPerDescriptorBlock = blockSize / groupDescriptorSize
 
@@ -377,6 +382,22 @@ func (w *Writer) zero(n int64) (int64, error) {
 		w.err = exceededMaxSizeError{w.maxDiskSize}
 		return 0, w.err
 	}
+	if w.sparse && n >= sparseZeroSize {
+		// Skip over all but the last byte, which is written to extend the
+		// file if this is the end of the file system.
+		if w.err = w.bw.Flush(); w.err != nil {
+			return 0, w.err
+		}
+		if _, w.err = w.f.Seek(n-1, io.SeekCurrent); w.err != nil {
+			return 0, w.err
+		}
+		w.pos += n - 1
+		if w.err = w.bw.WriteByte(0); w.err != nil {
+			return n - 1, w.err
+		}
+		w.pos++
+		return n, nil
+	}
 	n, err := io.CopyN(w.bw, zero, n)
 	w.pos += n
 	w.err = err
@@ -1040,6 +1061,13 @@ func InlineData(w *Writer) {
 	w.supportInlineData = true
 }
 
+// Sparse instructs the Writer to seek over large runs of zeroes rather than
+// write them, so that they are left as holes in the file. The file MUST be
+// empty, or its skipped ranges already zero, when the Writer is created.
+func Sparse(w *Writer) {
+	w.sparse = true
+}
+
 // MaximumDiskSize instructs the writer to reserve enough metadata space for the
 // specified disk size. If not provided, then 16GB is the default.
 func MaximumDiskSize(size int64) Option {
@@ -1211,11 +1239,15 @@ func (w *Writer) Close() error {
 		return err
 	}
 
-	// Write the block descriptors
+	// Write the block descriptors. The descriptors reserved for unused groups
+	// are zero, so they are left as a hole when writing a sparse file system.
 	w.seekBlock(1)
 	if w.err != nil {
 		return w.err
 	}
+	if w.sparse {
+		gds = gds[:groups]
+	}
 	err = binary.Write(w.bw, binary.LittleEndian, gds)
 	if err != nil {
 		return err
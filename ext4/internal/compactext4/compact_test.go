@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"strings"
 	"testing"
@@ -353,3 +354,38 @@ func TestLargeDisk(t *testing.T) {
 	}
 	runTestsOnFiles(t, testFiles, MaximumDiskSize(maxMaxDiskSize))
 }
+
+func writeImage(t *testing.T, testFiles []testFile, opts ...Option) []byte {
+	image := "testfs.img"
+	imagef, err := os.Create(image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(image)
+	defer imagef.Close()
+
+	w := NewWriter(imagef, opts...)
+	for _, tf := range testFiles {
+		createTestFile(t, w, tf)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestSparse(t *testing.T) {
+	testFiles := []testFile{
+		{Path: "small", File: &File{}, Data: data},
+		{Path: "large", File: &File{}, DataSize: 1024 * 1024},
+	}
+	expected := writeImage(t, testFiles)
+	actual := writeImage(t, testFiles, Sparse)
+	if !bytes.Equal(actual, expected) {
+		t.Fatalf("expected the sparse image to match, got %d bytes instead of %d", len(actual), len(expected))
+	}
+}
@@ -0,0 +1,356 @@
+// Package vhdx reads the virtual disk of a VHDX file without the Windows
+// virtual disk APIs, so that the file systems on LCOW layer and scratch disks
+// can be inspected on any host.
+//
+// Only fixed and dynamic VHDX files are supported. Differencing VHDX files and
+// VHDX files whose log has not been replayed, because they were not closed
+// cleanly, are not.
+package vhdx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"strings"
+)
+
+const (
+	fileSignature     = "vhdxfile"
+	headerSignature   = "head"
+	regionSignature   = "regi"
+	metadataSignature = "metadata"
+
+	headerSize      = 4 * 1024
+	regionTableSize = 64 * 1024
+	mb              = 1024 * 1024
+
+	// batStateMask masks the state of a payload block in its BAT entry. The
+	// offset of the block in the file, in MB, is in the upper 44 bits.
+	batStateMask             = 0x7
+	batOffsetShift           = 20
+	payloadBlockFullyPresent = 6
+	payloadBlockPartial      = 7
+
+	metadataIsRequired = 0x4
+	fileHasParent      = 0x2
+)
+
+var (
+	headerOffsets      = []int64{64 * 1024, 128 * 1024}
+	regionTableOffsets = []int64{192 * 1024, 256 * 1024}
+
+	batRegion      = mustGUID("2DC27766-F623-4200-9D64-115E9BFD4A08")
+	metadataRegion = mustGUID("8B7CA206-4790-4B9A-B8FE-575F050F886E")
+
+	fileParametersItem     = mustGUID("CAA16737-FA36-4D43-B3B6-33F0AA44E76B")
+	virtualDiskSizeItem    = mustGUID("2FA54224-CD1B-4876-B211-5DBED83BF4B8")
+	logicalSectorSizeItem  = mustGUID("8141BF1D-A96F-4709-BA47-F233A8FAAB5F")
+	physicalSectorSizeItem = mustGUID("CDA348C7-445D-4471-9CC9-E9885251C556")
+	page83DataItem         = mustGUID("BECA12AB-B2E6-4523-93EF-C309E000C746")
+
+	crc32c = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// guid is a GUID in its on-disk, mixed-endian, layout.
+type guid [16]byte
+
+// mustGUID returns the on-disk layout of the GUID `s` in its string form.
+func mustGUID(s string) guid {
+	b, err := hex.DecodeString(strings.Replace(s, "-", "", -1))
+	if err != nil || len(b) != 16 {
+		panic("invalid GUID " + s)
+	}
+	var g guid
+	binary.LittleEndian.PutUint32(g[0:], binary.BigEndian.Uint32(b[0:]))
+	binary.LittleEndian.PutUint16(g[4:], binary.BigEndian.Uint16(b[4:]))
+	binary.LittleEndian.PutUint16(g[6:], binary.BigEndian.Uint16(b[6:]))
+	copy(g[8:], b[8:])
+	return g
+}
+
+type header struct {
+	Signature      [4]byte
+	Checksum       uint32
+	SequenceNumber uint64
+	FileWriteGUID  guid
+	DataWriteGUID  guid
+	LogGUID        guid
+	LogVersion     uint16
+	Version        uint16
+	LogLength      uint32
+	LogOffset      uint64
+}
+
+type regionTableHeader struct {
+	Signature  [4]byte
+	Checksum   uint32
+	EntryCount uint32
+	Reserved   uint32
+}
+
+type regionTableEntry struct {
+	GUID       guid
+	FileOffset uint64
+	Length     uint32
+	Required   uint32
+}
+
+type metadataTableHeader struct {
+	Signature  [8]byte
+	Reserved   uint16
+	EntryCount uint16
+	Reserved2  [20]byte
+}
+
+type metadataTableEntry struct {
+	ItemID   guid
+	Offset   uint32
+	Length   uint32
+	Flags    uint32
+	Reserved uint32
+}
+
+type fileParameters struct {
+	BlockSize uint32
+	Flags     uint32
+}
+
+// Reader reads the virtual disk of a VHDX file.
+type Reader struct {
+	r          io.ReaderAt
+	size       int64
+	blockSize  int64
+	chunkRatio int64
+	bat        []uint64
+}
+
+// IsVHDX returns true if `r` is a VHDX file.
+func IsVHDX(r io.ReaderAt) bool {
+	var b [len(fileSignature)]byte
+	if _, err := r.ReadAt(b[:], 0); err != nil {
+		return false
+	}
+	return string(b[:]) == fileSignature
+}
+
+// readChecksummed reads the `size` byte structure at `offset` of `r` into `v`
+// if its signature is `signature` and its CRC-32C checksum, at offset 4, is
+// valid.
+func readChecksummed(r io.ReaderAt, offset int64, size int, signature string, v interface{}) (bool, []byte, error) {
+	b := make([]byte, size)
+	if _, err := r.ReadAt(b, offset); err != nil {
+		return false, nil, err
+	}
+	if string(b[:len(signature)]) != signature {
+		return false, nil, nil
+	}
+	checksum := binary.LittleEndian.Uint32(b[4:])
+	binary.LittleEndian.PutUint32(b[4:], 0)
+	if crc32.Checksum(b, crc32c) != checksum {
+		return false, nil, nil
+	}
+	binary.LittleEndian.PutUint32(b[4:], checksum)
+	return true, b, binary.Read(bytes.NewReader(b), binary.LittleEndian, v)
+}
+
+// NewReader returns a Reader for the virtual disk of the VHDX file `r`.
+func NewReader(r io.ReaderAt) (*Reader, error) {
+	if !IsVHDX(r) {
+		return nil, errors.New("vhdx: not a VHDX file")
+	}
+
+	// The current header is the valid one with the highest sequence number.
+	var current *header
+	for _, offset := range headerOffsets {
+		var h header
+		ok, _, err := readChecksummed(r, offset, headerSize, headerSignature, &h)
+		if err != nil {
+			return nil, fmt.Errorf("vhdx: failed to read header: %s", err)
+		}
+		if ok && (current == nil || h.SequenceNumber > current.SequenceNumber) {
+			current = &h
+		}
+	}
+	if current == nil {
+		return nil, errors.New("vhdx: no valid header")
+	}
+	if current.Version != 1 {
+		return nil, fmt.Errorf("vhdx: unsupported version %d", current.Version)
+	}
+	if current.LogGUID != (guid{}) {
+		return nil, errors.New("vhdx: the log must be replayed, attach the disk on Windows to replay it")
+	}
+
+	var regions []regionTableEntry
+	for _, offset := range regionTableOffsets {
+		var rth regionTableHeader
+		ok, b, err := readChecksummed(r, offset, regionTableSize, regionSignature, &rth)
+		if err != nil {
+			return nil, fmt.Errorf("vhdx: failed to read region table: %s", err)
+		}
+		if !ok {
+			continue
+		}
+		if rth.EntryCount > 2047 {
+			return nil, fmt.Errorf("vhdx: invalid region table entry count %d", rth.EntryCount)
+		}
+		regions = make([]regionTableEntry, rth.EntryCount)
+		if err := binary.Read(bytes.NewReader(b[16:]), binary.LittleEndian, regions); err != nil {
+			return nil, err
+		}
+		break
+	}
+	if regions == nil {
+		return nil, errors.New("vhdx: no valid region table")
+	}
+	var bat, metadata *regionTableEntry
+	for i := range regions {
+		switch regions[i].GUID {
+		case batRegion:
+			bat = &regions[i]
+		case metadataRegion:
+			metadata = &regions[i]
+		default:
+			if regions[i].Required&1 != 0 {
+				return nil, errors.New("vhdx: unsupported required region")
+			}
+		}
+	}
+	if bat == nil || metadata == nil {
+		return nil, errors.New("vhdx: missing BAT or metadata region")
+	}
+
+	vr := &Reader{r: r}
+	if err := vr.readMetadata(metadata); err != nil {
+		return nil, err
+	}
+
+	payloadBlocks := (vr.size + vr.blockSize - 1) / vr.blockSize
+	entries := payloadBlocks
+	if payloadBlocks > 0 {
+		entries += (payloadBlocks - 1) / vr.chunkRatio
+	}
+	if entries*8 > int64(bat.Length) {
+		return nil, fmt.Errorf("vhdx: BAT of %d bytes is too small for %d entries", bat.Length, entries)
+	}
+	vr.bat = make([]uint64, entries)
+	if err := binary.Read(io.NewSectionReader(r, int64(bat.FileOffset), entries*8), binary.LittleEndian, vr.bat); err != nil {
+		return nil, fmt.Errorf("vhdx: failed to read BAT: %s", err)
+	}
+	return vr, nil
+}
+
+// readMetadata reads the virtual disk parameters from the metadata region.
+func (vr *Reader) readMetadata(region *regionTableEntry) error {
+	r := io.NewSectionReader(vr.r, int64(region.FileOffset), int64(region.Length))
+	var mth metadataTableHeader
+	if err := binary.Read(r, binary.LittleEndian, &mth); err != nil {
+		return fmt.Errorf("vhdx: failed to read metadata table: %s", err)
+	}
+	if string(mth.Signature[:]) != metadataSignature {
+		return errors.New("vhdx: invalid metadata table")
+	}
+	if mth.EntryCount > 2047 {
+		return fmt.Errorf("vhdx: invalid metadata table entry count %d", mth.EntryCount)
+	}
+	entries := make([]metadataTableEntry, mth.EntryCount)
+	if err := binary.Read(r, binary.LittleEndian, entries); err != nil {
+		return fmt.Errorf("vhdx: failed to read metadata table: %s", err)
+	}
+
+	var (
+		params            fileParameters
+		logicalSectorSize uint32
+		found             int
+	)
+	for _, e := range entries {
+		var v interface{}
+		switch e.ItemID {
+		case fileParametersItem:
+			v = &params
+		case virtualDiskSizeItem:
+			v = &vr.size
+		case logicalSectorSizeItem:
+			v = &logicalSectorSize
+		case physicalSectorSizeItem, page83DataItem:
+			continue
+		default:
+			if e.Flags&metadataIsRequired != 0 {
+				return errors.New("vhdx: unsupported required metadata item")
+			}
+			continue
+		}
+		if err := binary.Read(io.NewSectionReader(r, int64(e.Offset), int64(e.Length)), binary.LittleEndian, v); err != nil {
+			return fmt.Errorf("vhdx: failed to read metadata item: %s", err)
+		}
+		found++
+	}
+	if found != 3 {
+		return errors.New("vhdx: missing required metadata items")
+	}
+	if params.Flags&fileHasParent != 0 {
+		return errors.New("vhdx: differencing disks are not supported")
+	}
+	if params.BlockSize < mb || params.BlockSize&(params.BlockSize-1) != 0 {
+		return fmt.Errorf("vhdx: invalid block size %d", params.BlockSize)
+	}
+	if logicalSectorSize != 512 && logicalSectorSize != 4096 {
+		return fmt.Errorf("vhdx: invalid logical sector size %d", logicalSectorSize)
+	}
+	if vr.size < 0 {
+		return fmt.Errorf("vhdx: invalid virtual disk size %d", vr.size)
+	}
+	vr.blockSize = int64(params.BlockSize)
+	// Each chunk of payload blocks is followed by the BAT entry of its sector
+	// bitmap block.
+	vr.chunkRatio = (1 << 23) * int64(logicalSectorSize) / vr.blockSize
+	return nil
+}
+
+// Size returns the size of the virtual disk in bytes.
+func (vr *Reader) Size() int64 {
+	return vr.size
+}
+
+// ReadAt reads from the virtual disk at offset `off`. Blocks that are not
+// present in the file read as zeroes.
+func (vr *Reader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("vhdx: negative offset")
+	}
+	read := 0
+	for read < len(p) {
+		if off >= vr.size {
+			return read, io.EOF
+		}
+		block, in := off/vr.blockSize, off%vr.blockSize
+		n := int64(len(p) - read)
+		if n > vr.blockSize-in {
+			n = vr.blockSize - in
+		}
+		if n > vr.size-off {
+			n = vr.size - off
+		}
+		b := p[read : int64(read)+n]
+		entry := vr.bat[block+block/vr.chunkRatio]
+		switch entry & batStateMask {
+		case payloadBlockFullyPresent:
+			if _, err := vr.r.ReadAt(b, int64(entry>>batOffsetShift)*mb+in); err != nil {
+				return read, fmt.Errorf("vhdx: failed to read block %d: %s", block, err)
+			}
+		case payloadBlockPartial:
+			return read, fmt.Errorf("vhdx: block %d is partially present", block)
+		default:
+			for i := range b {
+				b[i] = 0
+			}
+		}
+		read += len(b)
+		off += n
+	}
+	return read, nil
+}
@@ -0,0 +1,183 @@
+package vhdx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"testing"
+)
+
+const (
+	testMetadataOffset = 1 * mb
+	testBATOffset      = 2 * mb
+	testPayloadOffset  = 3 * mb
+)
+
+// putChecksummed writes `v` to `b` at `offset` with the CRC-32C checksum of
+// the `size` bytes at `offset`.
+func putChecksummed(t *testing.T, b []byte, offset, size int, v interface{}) {
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.LittleEndian, v); err != nil {
+		t.Fatal(err)
+	}
+	s := b[offset : offset+size]
+	copy(s, buf.Bytes())
+	binary.LittleEndian.PutUint32(s[4:], 0)
+	binary.LittleEndian.PutUint32(s[4:], crc32.Checksum(s, crc32c))
+}
+
+func putStruct(t *testing.T, b []byte, offset int, v interface{}) {
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.LittleEndian, v); err != nil {
+		t.Fatal(err)
+	}
+	copy(b[offset:], buf.Bytes())
+}
+
+type testDisk struct {
+	size    int64
+	blocks  map[int64][]byte // payload block index to its data
+	flags   uint32           // file parameter flags
+	logGUID guid
+}
+
+// write returns a dynamic VHDX file with 1MB blocks and 512 byte sectors.
+func (d *testDisk) write(t *testing.T) []byte {
+	f := make([]byte, testPayloadOffset+len(d.blocks)*mb)
+	copy(f, fileSignature)
+
+	// Write an older header to the second slot to check the current header
+	// is the one with the highest sequence number.
+	for i, seq := range []uint64{2, 1} {
+		h := header{SequenceNumber: seq, LogGUID: d.logGUID, Version: 1}
+		copy(h.Signature[:], headerSignature)
+		if seq != 2 {
+			h.LogGUID = guid{}
+		}
+		putChecksummed(t, f, int(headerOffsets[i]), headerSize, &h)
+	}
+
+	regions := struct {
+		regionTableHeader
+		Entries [2]regionTableEntry
+	}{
+		regionTableHeader{EntryCount: 2},
+		[2]regionTableEntry{
+			{GUID: metadataRegion, FileOffset: testMetadataOffset, Length: mb, Required: 1},
+			{GUID: batRegion, FileOffset: testBATOffset, Length: mb, Required: 1},
+		},
+	}
+	copy(regions.Signature[:], regionSignature)
+	for _, offset := range regionTableOffsets {
+		putChecksummed(t, f, int(offset), regionTableSize, &regions)
+	}
+
+	metadata := struct {
+		metadataTableHeader
+		Entries [3]metadataTableEntry
+	}{
+		metadataTableHeader{EntryCount: 3},
+		[3]metadataTableEntry{
+			{ItemID: fileParametersItem, Offset: 64 * 1024, Length: 8, Flags: metadataIsRequired},
+			{ItemID: virtualDiskSizeItem, Offset: 64*1024 + 8, Length: 8, Flags: metadataIsRequired},
+			{ItemID: logicalSectorSizeItem, Offset: 64*1024 + 16, Length: 4, Flags: metadataIsRequired},
+		},
+	}
+	copy(metadata.Signature[:], metadataSignature)
+	putStruct(t, f, testMetadataOffset, &metadata)
+	putStruct(t, f, testMetadataOffset+64*1024, &fileParameters{BlockSize: mb, Flags: d.flags})
+	putStruct(t, f, testMetadataOffset+64*1024+8, d.size)
+	putStruct(t, f, testMetadataOffset+64*1024+16, uint32(512))
+
+	offset := int64(testPayloadOffset)
+	for block, data := range d.blocks {
+		chunkRatio := int64((1 << 23) * 512 / mb)
+		entry := uint64(offset/mb)<<batOffsetShift | payloadBlockFullyPresent
+		putStruct(t, f, int(testBATOffset+(block+block/chunkRatio)*8), entry)
+		copy(f[offset:], data)
+		offset += mb
+	}
+	return f
+}
+
+func pattern(n int, seed byte) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i) ^ seed
+	}
+	return b
+}
+
+func TestReadAt(t *testing.T) {
+	// Block 4096 is the first block of the second chunk, so its BAT entry
+	// follows the sector bitmap entry of the first chunk.
+	d := &testDisk{
+		size: 4097*mb + 4096,
+		blocks: map[int64][]byte{
+			1:    pattern(mb, 1),
+			4096: pattern(mb, 2),
+			4097: pattern(4096, 3),
+		},
+	}
+	r, err := NewReader(bytes.NewReader(d.write(t)))
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if r.Size() != d.size {
+		t.Fatalf("expected size %d, got: %d", d.size, r.Size())
+	}
+
+	// A read spanning an absent and a present block.
+	b := make([]byte, 2*4096)
+	if _, err := r.ReadAt(b, mb-4096); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b[:4096], make([]byte, 4096)) || !bytes.Equal(b[4096:], d.blocks[1][:4096]) {
+		t.Fatal("wrong data read across blocks 0 and 1")
+	}
+
+	if _, err := r.ReadAt(b, 4096*mb+100); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, d.blocks[4096][100:100+len(b)]) {
+		t.Fatal("wrong data read from block 4096")
+	}
+
+	// A read past the end of the disk.
+	n, err := r.ReadAt(b, 4097*mb)
+	if err != io.EOF || n != 4096 || !bytes.Equal(b[:n], d.blocks[4097]) {
+		t.Fatalf("expected %d bytes and EOF at the end of the disk, got: %d %v", 4096, n, err)
+	}
+}
+
+func TestNewReader_Invalid(t *testing.T) {
+	if _, err := NewReader(bytes.NewReader(make([]byte, 1024))); err == nil {
+		t.Fatal("expected error for a file that is not VHDX")
+	}
+
+	d := &testDisk{size: mb, logGUID: mustGUID("0F1E2D3C-4B5A-6978-8796-A5B4C3D2E1F0")}
+	if _, err := NewReader(bytes.NewReader(d.write(t))); err == nil {
+		t.Fatal("expected error for a log that must be replayed")
+	}
+
+	d = &testDisk{size: mb, flags: fileHasParent}
+	if _, err := NewReader(bytes.NewReader(d.write(t))); err == nil {
+		t.Fatal("expected error for a differencing disk")
+	}
+
+	// The second header is used if the first is corrupt.
+	d = &testDisk{size: mb, logGUID: mustGUID("0F1E2D3C-4B5A-6978-8796-A5B4C3D2E1F0")}
+	f := d.write(t)
+	f[headerOffsets[0]+100] ^= 0xff
+	if _, err := NewReader(bytes.NewReader(f)); err != nil {
+		t.Fatalf("expected nil error with a corrupt header, got: %v", err)
+	}
+}
+
+func TestMustGUID(t *testing.T) {
+	expected := guid{0x66, 0x77, 0xc2, 0x2d, 0x23, 0xf6, 0x00, 0x42, 0x9d, 0x64, 0x11, 0x5e, 0x9b, 0xfd, 0x4a, 0x08}
+	if batRegion != expected {
+		t.Fatalf("expected %x, got: %x", expected, batRegion)
+	}
+}
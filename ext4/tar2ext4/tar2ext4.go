@@ -8,12 +8,15 @@ import (
 	"path"
 	"strings"
 
+	"github.com/Microsoft/hcsshim/ext4/dmverity"
 	"github.com/Microsoft/hcsshim/ext4/internal/compactext4"
 )
 
 type params struct {
 	convertWhiteout bool
 	appendVhdFooter bool
+	appendDMVerity  bool
+	rootDigest      *string
 	ext4opts        []compactext4.Option
 }
 
@@ -32,6 +35,24 @@ func AppendVhdFooter(p *params) {
 	p.appendVhdFooter = true
 }
 
+// AppendDMVerity instructs the converter to add a dm-verity hash device to the
+// file after the file system so that a Linux guest can verify the file system
+// as it is read. The hash device is added before the VHD footer, if any.
+func AppendDMVerity(p *params) {
+	p.appendDMVerity = true
+}
+
+// DMVerityRootDigest instructs the converter to store the hex encoded root
+// digest of the dm-verity hash device added by `AppendDMVerity` in `digest`.
+// The guest verifies the file system against the root digest, so it MUST be
+// kept apart from the file, such as by the snapshotter, rather than read back
+// from it.
+func DMVerityRootDigest(digest *string) Option {
+	return func(p *params) {
+		p.rootDigest = digest
+	}
+}
+
 // InlineData instructs the converter to write small files into the inode
 // structures directly. This creates smaller images but currently is not
 // compatible with DAX.
@@ -39,6 +60,14 @@ func InlineData(p *params) {
 	p.ext4opts = append(p.ext4opts, compactext4.InlineData)
 }
 
+// Sparse instructs the converter to skip over large runs of zeroes, such as the
+// unused space up to the disk size, rather than write them. This leaves holes
+// in the output if the file system supports sparse files. The output MUST be
+// empty.
+func Sparse(p *params) {
+	p.ext4opts = append(p.ext4opts, compactext4.Sparse)
+}
+
 // MaximumDiskSize instructs the writer to limit the disk size to the specified
 // value. This also reserves enough metadata space for the specified disk size.
 // If not provided, then 16GB is the default.
@@ -160,6 +189,19 @@ func Convert(r io.Reader, w io.ReadWriteSeeker, options ...Option) error {
 	if err != nil {
 		return err
 	}
+	if p.appendDMVerity {
+		size, err := w.Seek(0, io.SeekEnd)
+		if err != nil {
+			return err
+		}
+		root, err := dmverity.WriteHashDevice(w, size)
+		if err != nil {
+			return err
+		}
+		if p.rootDigest != nil {
+			*p.rootDigest = root
+		}
+	}
 	if p.appendVhdFooter {
 		size, err := w.Seek(0, io.SeekEnd)
 		if err != nil {
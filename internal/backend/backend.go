@@ -0,0 +1,92 @@
+// Package backend maintains the set of virtualization backends that compute
+// systems can be created on.
+package backend
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Microsoft/hcsshim/internal/cow"
+	"github.com/Microsoft/hcsshim/internal/hcs"
+)
+
+// Default is the name of the backend that drives the local vmcompute service.
+// It is used when no backend is requested.
+const Default = "hcs"
+
+var (
+	backendsLock sync.RWMutex
+	backends     = make(map[string]cow.Backend)
+)
+
+func init() {
+	Register(hcsBackend{})
+}
+
+// Register makes `b` available by its name. Registering a second backend with
+// the same name replaces the first.
+func Register(b cow.Backend) {
+	backendsLock.Lock()
+	defer backendsLock.Unlock()
+	backends[b.Name()] = b
+}
+
+// Get returns the backend registered as `name`. If `name` is empty the
+// `Default` backend is returned.
+func Get(name string) (cow.Backend, error) {
+	if name == "" {
+		name = Default
+	}
+	backendsLock.RLock()
+	defer backendsLock.RUnlock()
+	b, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown vm backend '%s'", name)
+	}
+	return b, nil
+}
+
+// Names returns the sorted names of all registered backends.
+func Names() []string {
+	backendsLock.RLock()
+	defer backendsLock.RUnlock()
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OrDefault returns `b` if it is not nil, otherwise the default backend.
+func OrDefault(b cow.Backend) cow.Backend {
+	if b != nil {
+		return b
+	}
+	return hcsBackend{}
+}
+
+// hcsBackend creates compute systems using the local vmcompute service.
+type hcsBackend struct{}
+
+func (hcsBackend) Name() string {
+	return Default
+}
+
+func (hcsBackend) CreateComputeSystem(ctx context.Context, id string, document interface{}) (cow.ComputeSystem, error) {
+	system, err := hcs.CreateComputeSystemContext(ctx, id, document)
+	if err != nil {
+		return nil, err
+	}
+	return system, nil
+}
+
+func (hcsBackend) OpenComputeSystem(id string) (cow.ComputeSystem, error) {
+	system, err := hcs.OpenComputeSystem(id)
+	if err != nil {
+		return nil, err
+	}
+	return system, nil
+}
@@ -0,0 +1,53 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/cow"
+)
+
+type testBackend struct{}
+
+func (testBackend) Name() string {
+	return "test"
+}
+
+func (testBackend) CreateComputeSystem(ctx context.Context, id string, document interface{}) (cow.ComputeSystem, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (testBackend) OpenComputeSystem(id string) (cow.ComputeSystem, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestGetDefault(t *testing.T) {
+	b, err := Get("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Name() != Default {
+		t.Fatalf("expected default backend %q, got %q", Default, b.Name())
+	}
+}
+
+func TestGetUnknown(t *testing.T) {
+	if _, err := Get("does-not-exist"); err == nil {
+		t.Fatal("expected error for unknown backend")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register(testBackend{})
+	b, err := Get("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.(testBackend); !ok {
+		t.Fatalf("expected testBackend, got %T", b)
+	}
+	if OrDefault(b) != b {
+		t.Fatal("expected OrDefault to return the supplied backend")
+	}
+}
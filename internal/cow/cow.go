@@ -1,6 +1,7 @@
 package cow
 
 import (
+	"context"
 	"io"
 
 	"github.com/Microsoft/hcsshim/internal/schema1"
@@ -77,3 +78,37 @@ type Container interface {
 	// Close).
 	Wait() error
 }
+
+// ComputeSystem is the interface for a compute system, either a container or
+// a utility VM, created by a Backend.
+type ComputeSystem interface {
+	Container
+	// ExitError returns an error describing the reason the compute system
+	// terminated.
+	ExitError() error
+	// Modify sends a modify request to the compute system. The request is
+	// backend specific (typically hcsschema.ModifySettingRequest).
+	Modify(config interface{}) error
+	// Pause suspends the execution of the compute system.
+	Pause() error
+	// Resume resumes the execution of a compute system suspended by Pause.
+	Resume() error
+	// Save saves the state of the compute system. The options are backend
+	// specific (typically hcsschema.SaveOptions).
+	Save(options interface{}) error
+}
+
+// Backend is the interface for the virtualization platform that creates and
+// opens compute systems. The default backend drives the local vmcompute
+// service, but alternative backends (a remote HCS endpoint, an external VMM,
+// or a test rig) can be substituted without changes to the callers.
+type Backend interface {
+	// Name returns the name the backend is registered under.
+	Name() string
+	// CreateComputeSystem creates a compute system from the backend specific
+	// `document` but does not start it. If `ctx` is done before the create
+	// completes the compute system is not created.
+	CreateComputeSystem(ctx context.Context, id string, document interface{}) (ComputeSystem, error)
+	// OpenComputeSystem opens an existing compute system by `id`.
+	OpenComputeSystem(id string) (ComputeSystem, error)
+}
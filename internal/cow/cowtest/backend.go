@@ -0,0 +1,77 @@
+package cowtest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Microsoft/hcsshim/internal/cow"
+)
+
+// Backend is a fake cow.Backend that creates fake Containers. The zero value
+// is not usable, use NewBackend.
+type Backend struct {
+	// OnCreateComputeSystem is called by CreateComputeSystem. If nil a new
+	// Container is created with the backends OS and recorded by ID.
+	OnCreateComputeSystem func(b *Backend, id string, document interface{}) (cow.ComputeSystem, error)
+
+	name string
+	os   string
+
+	m       sync.Mutex
+	systems map[string]*Container
+}
+
+var _ = (cow.Backend)(&Backend{})
+
+// NewBackend returns a fake backend registered as `name` whose compute
+// systems report `os`.
+func NewBackend(name, os string) *Backend {
+	return &Backend{
+		name:    name,
+		os:      os,
+		systems: make(map[string]*Container),
+	}
+}
+
+// Name returns the name passed to NewBackend.
+func (b *Backend) Name() string {
+	return b.name
+}
+
+// Container returns the fake container created with `id`, or nil.
+func (b *Backend) Container(id string) *Container {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return b.systems[id]
+}
+
+// CreateComputeSystem fails with the error of `ctx` if it is done. Otherwise it
+// calls OnCreateComputeSystem if set, or creates a new Container.
+func (b *Backend) CreateComputeSystem(ctx context.Context, id string, document interface{}) (cow.ComputeSystem, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if b.OnCreateComputeSystem != nil {
+		return b.OnCreateComputeSystem(b, id, document)
+	}
+	b.m.Lock()
+	defer b.m.Unlock()
+	if _, ok := b.systems[id]; ok {
+		return nil, fmt.Errorf("compute system %s already exists", id)
+	}
+	c := NewContainer(id, b.os, false)
+	b.systems[id] = c
+	return c, nil
+}
+
+// OpenComputeSystem returns a previously created Container.
+func (b *Backend) OpenComputeSystem(id string) (cow.ComputeSystem, error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	c, ok := b.systems[id]
+	if !ok {
+		return nil, fmt.Errorf("compute system %s does not exist", id)
+	}
+	return c, nil
+}
@@ -0,0 +1,303 @@
+package cowtest
+
+import (
+	"sync"
+
+	"github.com/Microsoft/hcsshim/internal/cow"
+	"github.com/Microsoft/hcsshim/internal/schema1"
+)
+
+// Container is a fake cow.Container (and cow.ComputeSystem). The zero value is
+// not usable, use NewContainer.
+//
+// By default CreateProcess returns a new running Process with an increasing
+// pid, Start, Shutdown and Terminate succeed, and Shutdown/Terminate cause the
+// container to exit. Set the `On*` fields before handing the container to the
+// code under test to script different behaviors.
+type Container struct {
+	// OnCreateProcess is called by CreateProcess. If nil a new running
+	// Process is returned.
+	OnCreateProcess func(c *Container, config interface{}) (cow.Process, error)
+	// OnStart is called by Start. If nil Start succeeds.
+	OnStart func(c *Container) error
+	// OnShutdown is called by Shutdown. If nil the container exits.
+	OnShutdown func(c *Container) error
+	// OnTerminate is called by Terminate. If nil the container exits.
+	OnTerminate func(c *Container) error
+	// OnModify is called by Modify. If nil Modify succeeds.
+	OnModify func(c *Container, config interface{}) error
+	// OnPause is called by Pause. If nil the container is paused.
+	OnPause func(c *Container) error
+	// OnResume is called by Resume. If nil the container is resumed.
+	OnResume func(c *Container) error
+	// OnSave is called by Save. If nil Save succeeds.
+	OnSave func(c *Container, options interface{}) error
+
+	id    string
+	os    string
+	isOCI bool
+
+	m         sync.Mutex
+	nextPid   int
+	processes []*Process
+	started   bool
+	paused    bool
+	closed    bool
+	exited    chan struct{}
+	exitErr   error
+	modifies  []interface{}
+	saves     []interface{}
+}
+
+var _ = (cow.ComputeSystem)(&Container{})
+
+// NewContainer returns a created, but not started, fake container. `os` is
+// "windows" or "linux" and `isOCI` is returned from IsOCI.
+func NewContainer(id, os string, isOCI bool) *Container {
+	return &Container{
+		id:      id,
+		os:      os,
+		isOCI:   isOCI,
+		nextPid: 1,
+		exited:  make(chan struct{}),
+	}
+}
+
+// Exit causes the container to exit. Wait returns nil and ExitError returns
+// `exitErr`. Calls after the first have no effect.
+func (c *Container) Exit(exitErr error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	select {
+	case <-c.exited:
+		return
+	default:
+	}
+	c.exitErr = exitErr
+	close(c.exited)
+}
+
+// Exited returns a channel that is closed when the container exits.
+func (c *Container) Exited() <-chan struct{} {
+	return c.exited
+}
+
+// Processes returns the fake processes created by the default CreateProcess
+// behavior.
+func (c *Container) Processes() []*Process {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return append([]*Process(nil), c.processes...)
+}
+
+// Modifies returns the config passed to every call to Modify.
+func (c *Container) Modifies() []interface{} {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return append([]interface{}(nil), c.modifies...)
+}
+
+// Saves returns the options passed to every call to Save.
+func (c *Container) Saves() []interface{} {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return append([]interface{}(nil), c.saves...)
+}
+
+// Started returns true if Start has succeeded.
+func (c *Container) Started() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.started
+}
+
+// Closed returns true if Close has been called.
+func (c *Container) Closed() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.closed
+}
+
+// CreateProcess calls OnCreateProcess if set, otherwise it returns a new
+// running Process.
+func (c *Container) CreateProcess(config interface{}) (cow.Process, error) {
+	if c.OnCreateProcess != nil {
+		return c.OnCreateProcess(c, config)
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.closed {
+		return nil, ErrClosed
+	}
+	p := NewProcess(c.nextPid)
+	c.nextPid++
+	c.processes = append(c.processes, p)
+	return p, nil
+}
+
+// OpenProcess returns the running process with `pid` created by the default
+// CreateProcess behavior, as if it was opened by pid rather than created.
+func (c *Container) OpenProcess(pid int) (cow.Process, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.closed {
+		return nil, ErrClosed
+	}
+	for _, p := range c.processes {
+		if _, err := p.ExitCode(); p.Pid() == pid && err != nil {
+			return p, nil
+		}
+	}
+	return nil, ErrProcessNotFound
+}
+
+// OS returns the operating system passed to NewContainer.
+func (c *Container) OS() string {
+	return c.os
+}
+
+// IsOCI returns the value passed to NewContainer.
+func (c *Container) IsOCI() bool {
+	return c.isOCI
+}
+
+// Close marks the container closed.
+func (c *Container) Close() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.closed = true
+	return nil
+}
+
+// ID returns the container ID.
+func (c *Container) ID() string {
+	return c.id
+}
+
+// Properties returns the container ID and, if requested, the list of
+// processes created by the default CreateProcess behavior that have not
+// exited.
+func (c *Container) Properties(types ...schema1.PropertyType) (*schema1.ContainerProperties, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.closed {
+		return nil, ErrClosed
+	}
+	props := &schema1.ContainerProperties{
+		ID:    c.id,
+		State: "Created",
+	}
+	if c.started {
+		props.State = "Running"
+	}
+	for _, t := range types {
+		if t != schema1.PropertyTypeProcessList {
+			continue
+		}
+		for _, p := range c.processes {
+			if _, err := p.ExitCode(); err == nil {
+				continue
+			}
+			props.ProcessList = append(props.ProcessList, schema1.ProcessListItem{
+				ProcessId: uint32(p.Pid()),
+			})
+		}
+	}
+	return props, nil
+}
+
+// Start calls OnStart if set.
+func (c *Container) Start() error {
+	if c.OnStart != nil {
+		if err := c.OnStart(c); err != nil {
+			return err
+		}
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.started = true
+	return nil
+}
+
+// Shutdown calls OnShutdown if set, otherwise the container exits.
+func (c *Container) Shutdown() error {
+	if c.OnShutdown != nil {
+		return c.OnShutdown(c)
+	}
+	c.Exit(nil)
+	return nil
+}
+
+// Terminate calls OnTerminate if set, otherwise the container exits.
+func (c *Container) Terminate() error {
+	if c.OnTerminate != nil {
+		return c.OnTerminate(c)
+	}
+	c.Exit(nil)
+	return nil
+}
+
+// Wait waits for the container to exit.
+func (c *Container) Wait() error {
+	<-c.exited
+	return nil
+}
+
+// ExitError returns the error passed to Exit.
+func (c *Container) ExitError() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.exitErr
+}
+
+// Modify records `config` and calls OnModify if set.
+func (c *Container) Modify(config interface{}) error {
+	c.m.Lock()
+	c.modifies = append(c.modifies, config)
+	c.m.Unlock()
+	if c.OnModify != nil {
+		return c.OnModify(c, config)
+	}
+	return nil
+}
+
+// Pause calls OnPause if set, otherwise the container is paused.
+func (c *Container) Pause() error {
+	if c.OnPause != nil {
+		return c.OnPause(c)
+	}
+	c.m.Lock()
+	c.paused = true
+	c.m.Unlock()
+	return nil
+}
+
+// Resume calls OnResume if set, otherwise the container is resumed.
+func (c *Container) Resume() error {
+	if c.OnResume != nil {
+		return c.OnResume(c)
+	}
+	c.m.Lock()
+	c.paused = false
+	c.m.Unlock()
+	return nil
+}
+
+// Save records `options` and calls OnSave if set.
+func (c *Container) Save(options interface{}) error {
+	c.m.Lock()
+	c.saves = append(c.saves, options)
+	c.m.Unlock()
+	if c.OnSave != nil {
+		return c.OnSave(c, options)
+	}
+	return nil
+}
+
+// Paused returns `true` if the container was paused by the default Pause
+// behavior and not since resumed.
+func (c *Container) Paused() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.paused
+}
@@ -0,0 +1,158 @@
+package cowtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/schema1"
+)
+
+func TestProcessExit(t *testing.T) {
+	p := NewProcess(10)
+	if _, err := p.ExitCode(); err != ErrProcessNotExited {
+		t.Fatalf("expected ErrProcessNotExited, got %v", err)
+	}
+	p.ExitAfter(10*time.Millisecond, 3)
+	if err := p.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	code, err := p.ExitCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != 3 {
+		t.Fatalf("expected exit code 3, got %d", code)
+	}
+}
+
+func TestProcessDefaultKill(t *testing.T) {
+	p := NewProcess(10)
+	delivered, err := p.Kill()
+	if err != nil || !delivered {
+		t.Fatalf("expected delivered kill, got %v, %v", delivered, err)
+	}
+	if code, _ := p.ExitCode(); code != KillExitCode {
+		t.Fatalf("expected exit code %d, got %d", KillExitCode, code)
+	}
+}
+
+func TestProcessScriptedSignal(t *testing.T) {
+	p := NewProcess(10)
+	p.OnSignal = func(p *Process, options interface{}) (bool, error) {
+		return false, nil
+	}
+	delivered, err := p.Signal("SIGTERM")
+	if err != nil || delivered {
+		t.Fatalf("expected undelivered signal, got %v, %v", delivered, err)
+	}
+	if s := p.Signals(); len(s) != 1 || s[0] != "SIGTERM" {
+		t.Fatalf("unexpected recorded signals %v", s)
+	}
+	select {
+	case <-p.Exited():
+		t.Fatal("process should not have exited")
+	default:
+	}
+}
+
+func TestContainerLifecycle(t *testing.T) {
+	c := NewContainer("c", "linux", true)
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	p, err := c.CreateProcess(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	props, err := c.Properties(schema1.PropertyTypeProcessList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(props.ProcessList) != 1 || int(props.ProcessList[0].ProcessId) != p.Pid() {
+		t.Fatalf("unexpected process list %+v", props.ProcessList)
+	}
+	if err := c.Terminate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Wait(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestContainerOpenProcess(t *testing.T) {
+	c := NewContainer("c", "linux", true)
+	p, err := c.CreateProcess(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opened, err := c.OpenProcess(p.Pid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opened != p {
+		t.Fatal("expected the created process")
+	}
+	p.(*Process).Exit(0, nil)
+	if _, err := c.OpenProcess(p.Pid()); err != ErrProcessNotFound {
+		t.Fatalf("expected %v for an exited process, got %v", ErrProcessNotFound, err)
+	}
+}
+
+func TestContainerScriptedStartFailure(t *testing.T) {
+	startErr := errors.New("start failed")
+	c := NewContainer("c", "windows", false)
+	c.OnStart = func(*Container) error { return startErr }
+	if err := c.Start(); err != startErr {
+		t.Fatalf("expected %v, got %v", startErr, err)
+	}
+	if c.Started() {
+		t.Fatal("container should not be started")
+	}
+}
+
+func TestBackend_Cancelled(t *testing.T) {
+	b := NewBackend("fake", "windows")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := b.CreateComputeSystem(ctx, "a", nil); err != context.Canceled {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+	if b.Container("a") != nil {
+		t.Fatal("container should not be created")
+	}
+}
+
+func TestBackend(t *testing.T) {
+	b := NewBackend("fake", "windows")
+	if _, err := b.CreateComputeSystem(context.Background(), "a", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.CreateComputeSystem(context.Background(), "a", nil); err == nil {
+		t.Fatal("expected duplicate create to fail")
+	}
+	s, err := b.OpenComputeSystem("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.OS() != "windows" || b.Container("a") == nil {
+		t.Fatal("unexpected compute system")
+	}
+}
+
+func TestContainerPauseResume(t *testing.T) {
+	c := NewContainer("c", "windows", true)
+	if err := c.Pause(); err != nil {
+		t.Fatal(err)
+	}
+	if !c.Paused() {
+		t.Fatal("expected container to be paused")
+	}
+	if err := c.Resume(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Paused() {
+		t.Fatal("expected container to be resumed")
+	}
+}
@@ -0,0 +1,218 @@
+// Package cowtest provides in-memory implementations of the cow interfaces
+// whose behavior can be scripted by tests, so that code built on top of
+// cow.Container and cow.Process can be exercised without the HCS.
+package cowtest
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/cow"
+)
+
+// ErrProcessNotExited is returned by Process.ExitCode if the process has not
+// yet exited.
+var ErrProcessNotExited = errors.New("process has not exited")
+
+// ErrProcessNotFound is returned by Container.OpenProcess if there is no
+// running process with the pid.
+var ErrProcessNotFound = errors.New("process not found")
+
+// ErrClosed is returned by operations on a closed process or container.
+var ErrClosed = errors.New("already closed")
+
+// KillExitCode is the exit code a Process exits with when it is killed and no
+// OnKill behavior has been set.
+const KillExitCode = 137
+
+// Process is a fake cow.Process. The zero value is not usable, use
+// NewProcess.
+//
+// By default the process runs until Exit is called or it is killed, and every
+// signal is reported as delivered without changing the process state. Set the
+// `On*` fields before handing the process to the code under test to script
+// different behaviors.
+type Process struct {
+	// OnKill is called by Kill. If nil the process exits with
+	// `KillExitCode` and the kill is reported as delivered.
+	OnKill func(p *Process) (bool, error)
+	// OnSignal is called by Signal with the OS specific signal options. If
+	// nil the signal is reported as delivered and the process keeps running.
+	OnSignal func(p *Process, options interface{}) (bool, error)
+	// OnResizeConsole is called by ResizeConsole. If nil the resize succeeds.
+	OnResizeConsole func(p *Process, width, height uint16) error
+
+	pid int
+
+	stdin  io.Writer
+	stdout io.Reader
+	stderr io.Reader
+
+	m        sync.Mutex
+	exited   chan struct{}
+	exitCode int
+	waitErr  error
+	closed   bool
+	signals  []interface{}
+	kills    int
+}
+
+var _ = (cow.Process)(&Process{})
+
+// NewProcess returns a running fake process with `pid`.
+func NewProcess(pid int) *Process {
+	return &Process{
+		pid:    pid,
+		exited: make(chan struct{}),
+	}
+}
+
+// SetStdio sets the streams returned by Stdio.
+func (p *Process) SetStdio(stdin io.Writer, stdout, stderr io.Reader) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.stdin = stdin
+	p.stdout = stdout
+	p.stderr = stderr
+}
+
+// Exit causes the process to exit with `code`. Wait returns `waitErr`. Calls
+// after the first have no effect.
+func (p *Process) Exit(code int, waitErr error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	select {
+	case <-p.exited:
+		return
+	default:
+	}
+	p.exitCode = code
+	p.waitErr = waitErr
+	close(p.exited)
+}
+
+// ExitAfter causes the process to exit with `code` once `d` has elapsed.
+func (p *Process) ExitAfter(d time.Duration, code int) {
+	time.AfterFunc(d, func() {
+		p.Exit(code, nil)
+	})
+}
+
+// Exited returns a channel that is closed when the process exits.
+func (p *Process) Exited() <-chan struct{} {
+	return p.exited
+}
+
+// Signals returns the signal options passed to every call to Signal.
+func (p *Process) Signals() []interface{} {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return append([]interface{}(nil), p.signals...)
+}
+
+// Kills returns the number of calls to Kill.
+func (p *Process) Kills() int {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.kills
+}
+
+// Closed returns true if Close has been called.
+func (p *Process) Closed() bool {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.closed
+}
+
+// Close marks the process closed.
+func (p *Process) Close() error {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.closed = true
+	return nil
+}
+
+// CloseStdin closes the stdin stream if it implements io.Closer.
+func (p *Process) CloseStdin() error {
+	p.m.Lock()
+	stdin := p.stdin
+	p.m.Unlock()
+	if c, ok := stdin.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Pid returns the process ID.
+func (p *Process) Pid() int {
+	return p.pid
+}
+
+// Stdio returns the streams set by SetStdio.
+func (p *Process) Stdio() (io.Writer, io.Reader, io.Reader) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.stdin, p.stdout, p.stderr
+}
+
+// ResizeConsole calls OnResizeConsole if set.
+func (p *Process) ResizeConsole(width, height uint16) error {
+	if p.OnResizeConsole != nil {
+		return p.OnResizeConsole(p, width, height)
+	}
+	return nil
+}
+
+// Kill calls OnKill if set, otherwise it exits the process with
+// `KillExitCode`.
+func (p *Process) Kill() (bool, error) {
+	p.m.Lock()
+	p.kills++
+	closed := p.closed
+	p.m.Unlock()
+	if closed {
+		return false, ErrClosed
+	}
+	if p.OnKill != nil {
+		return p.OnKill(p)
+	}
+	p.Exit(KillExitCode, nil)
+	return true, nil
+}
+
+// Signal records `options` and calls OnSignal if set.
+func (p *Process) Signal(options interface{}) (bool, error) {
+	p.m.Lock()
+	p.signals = append(p.signals, options)
+	closed := p.closed
+	p.m.Unlock()
+	if closed {
+		return false, ErrClosed
+	}
+	if p.OnSignal != nil {
+		return p.OnSignal(p, options)
+	}
+	return true, nil
+}
+
+// Wait waits for the process to exit.
+func (p *Process) Wait() error {
+	<-p.exited
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.waitErr
+}
+
+// ExitCode returns the exit code of the process or `ErrProcessNotExited`.
+func (p *Process) ExitCode() (int, error) {
+	select {
+	case <-p.exited:
+	default:
+		return -1, ErrProcessNotExited
+	}
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.exitCode, nil
+}
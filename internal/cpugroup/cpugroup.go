@@ -0,0 +1,124 @@
+// Package cpugroup manages host CPU groups. A CPU group restricts the utility
+// VMs assigned to it to a set of host logical processors and can cap the
+// processor time they consume together, so that operators can partition the
+// host processors between pods.
+package cpugroup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Microsoft/hcsshim/internal/hcs"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/Microsoft/hcsshim/osversion"
+)
+
+// NullGroupID is the ID of the group that utility VMs that are not assigned to
+// any CPU group belong to.
+const NullGroupID = "00000000-0000-0000-0000-000000000000"
+
+// MaxCap is the cap of a CPU group that allows its utility VMs to consume all
+// of the processor time of its logical processors.
+const MaxCap = 65536
+
+var (
+	// ErrNotSupported is returned if the host does not support CPU groups.
+	ErrNotSupported = errors.New("cpu groups are not supported on this build")
+	// ErrNotFound is returned if the CPU group does not exist on the host.
+	ErrNotFound = errors.New("cpu group not found")
+)
+
+// IsSupported returns `true` if the host supports CPU groups.
+func IsSupported() bool {
+	return osversion.Get().Build >= osversion.V20H1
+}
+
+// Create creates the CPU group `id` containing the host logical processors
+// `logicalProcessors`.
+func Create(id string, logicalProcessors []uint32) error {
+	if len(logicalProcessors) == 0 {
+		return fmt.Errorf("cpu group %s must contain at least one logical processor", id)
+	}
+	return modify(hcsschema.CreateGroup, &hcsschema.CreateGroupOperation{
+		GroupId:               strings.ToLower(id),
+		LogicalProcessorCount: uint32(len(logicalProcessors)),
+		LogicalProcessors:     logicalProcessors,
+	})
+}
+
+// Delete deletes the CPU group `id`. The group must not have any utility VMs
+// assigned to it.
+func Delete(id string) error {
+	return modify(hcsschema.DeleteGroup, &hcsschema.DeleteGroupOperation{
+		GroupId: strings.ToLower(id),
+	})
+}
+
+// SetCap caps the processor time consumed by the utility VMs of the CPU group
+// `id` to `cap` of `MaxCap` of its logical processors.
+func SetCap(id string, cap uint32) error {
+	if cap == 0 || cap > MaxCap {
+		return fmt.Errorf("cpu group cap %d must be in the range 1 - %d", cap, MaxCap)
+	}
+	return modify(hcsschema.SetProperty, &hcsschema.SetPropertyOperation{
+		GroupId:       strings.ToLower(id),
+		PropertyCode:  uint32(hcsschema.CPUCapPropertyCode),
+		PropertyValue: cap,
+	})
+}
+
+// Get returns the configuration of the CPU group `id`. If the group does not
+// exist returns `ErrNotFound`.
+func Get(id string) (*hcsschema.CpuGroupConfig, error) {
+	props, err := hcs.GetServiceProperties(hcsschema.PropertyQuery{
+		PropertyTypes: []string{string(hcsschema.PTCPUGroup)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	configs, err := configsFromProperties(props)
+	if err != nil {
+		return nil, err
+	}
+	return findConfig(configs, id)
+}
+
+// modify sends the CPU group `operation` with `details` to the host compute
+// service.
+func modify(operation hcsschema.CPUGroupOperation, details interface{}) error {
+	if !IsSupported() {
+		return ErrNotSupported
+	}
+	return hcs.ModifyServiceSettings(hcsschema.ModificationRequest{
+		PropertyType: hcsschema.PTCPUGroup,
+		Settings: &hcsschema.HostProcessorModificationRequest{
+			Operation:        operation,
+			OperationDetails: details,
+		},
+	})
+}
+
+// configsFromProperties returns the CPU group configurations of the service
+// properties `props` returned for a CPU group query.
+func configsFromProperties(props *hcsschema.ServiceProperties) ([]hcsschema.CpuGroupConfig, error) {
+	if len(props.Properties) != 1 {
+		return nil, fmt.Errorf("expected 1 cpu group property, got %d", len(props.Properties))
+	}
+	var configs hcsschema.CpuGroupConfigurations
+	if err := json.Unmarshal(props.Properties[0], &configs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cpu group configurations: %s", err)
+	}
+	return configs.CpuGroups, nil
+}
+
+// findConfig returns the configuration of the CPU group `id` in `configs`.
+func findConfig(configs []hcsschema.CpuGroupConfig, id string) (*hcsschema.CpuGroupConfig, error) {
+	for i := range configs {
+		if strings.EqualFold(configs[i].GroupId, id) {
+			return &configs[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
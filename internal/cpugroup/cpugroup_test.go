@@ -0,0 +1,54 @@
+package cpugroup
+
+import (
+	"encoding/json"
+	"testing"
+
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+)
+
+func Test_configsFromProperties(t *testing.T) {
+	props := &hcsschema.ServiceProperties{
+		Properties: []json.RawMessage{
+			json.RawMessage(`{"CpuGroups":[{"GroupId":"00000000-0000-0000-0000-000000000000"},{"GroupId":"4e7b7c7a-0a3c-4d5e-9f4a-1b2c3d4e5f60","Affinity":{"LogicalProcessorCount":2,"LogicalProcessors":[0,1]}}]}`),
+		},
+	}
+	configs, err := configsFromProperties(props)
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if len(configs) != 2 {
+		t.Fatalf("expected 2 cpu groups, got: %d", len(configs))
+	}
+	if configs[1].Affinity == nil || configs[1].Affinity.LogicalProcessorCount != 2 {
+		t.Fatalf("unexpected affinity: %+v", configs[1].Affinity)
+	}
+}
+
+func Test_configsFromProperties_Invalid(t *testing.T) {
+	for _, props := range []*hcsschema.ServiceProperties{
+		{},
+		{Properties: []json.RawMessage{json.RawMessage(`[]`)}},
+	} {
+		if _, err := configsFromProperties(props); err == nil {
+			t.Fatalf("expected error for properties: %+v", props)
+		}
+	}
+}
+
+func Test_findConfig(t *testing.T) {
+	configs := []hcsschema.CpuGroupConfig{
+		{GroupId: NullGroupID},
+		{GroupId: "4e7b7c7a-0a3c-4d5e-9f4a-1b2c3d4e5f60"},
+	}
+	config, err := findConfig(configs, "4E7B7C7A-0A3C-4D5E-9F4A-1B2C3D4E5F60")
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if config != &configs[1] {
+		t.Fatalf("expected second config, got: %+v", config)
+	}
+	if _, err := findConfig(configs, "9c5b3e1a-0000-0000-0000-000000000000"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got: %v", err)
+	}
+}
@@ -21,6 +21,7 @@ type Container struct {
 	gc        *GuestConnection
 	id        string
 	notifyCh  chan struct{}
+	oomCh     chan struct{}
 	closeCh   chan struct{}
 	closeOnce sync.Once
 }
@@ -34,9 +35,10 @@ func (gc *GuestConnection) CreateContainer(ctx context.Context, cid string, cfg
 		gc:       gc,
 		id:       cid,
 		notifyCh: make(chan struct{}),
+		oomCh:    make(chan struct{}, 1),
 		closeCh:  make(chan struct{}),
 	}
-	err := gc.requestNotify(cid, c.notifyCh)
+	err := gc.requestNotify(cid, c.notifyCh, c.oomCh)
 	if err != nil {
 		return nil, err
 	}
@@ -146,6 +148,14 @@ func (c *Container) Terminate() error {
 	return c.shutdown(context.TODO(), rpcShutdownForced)
 }
 
+// OOMs returns a channel that receives a value each time the guest OOM killer
+// kills a process in the container. OOMs that occur before the previous value
+// is received are coalesced. The channel is closed when the container
+// terminates or the guest connection terminates.
+func (c *Container) OOMs() <-chan struct{} {
+	return c.oomCh
+}
+
 // Wait waits for the container to terminate (or Close to be called, or the
 // guest connection to terminate).
 func (c *Container) Wait() error {
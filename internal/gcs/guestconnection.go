@@ -55,6 +55,7 @@ func (gcc *GuestConnectionConfig) Connect(ctx context.Context) (*GuestConnection
 	gc := &GuestConnection{
 		nextPort:   firstIoChannelVsockPort,
 		notifyChs:  make(map[string]chan struct{}),
+		oomChs:     make(map[string]chan struct{}),
 		ioListenFn: gcc.IoListen,
 	}
 	gc.brdg = newBridge(gcc.Conn, gc.notify, gcc.Log)
@@ -78,6 +79,7 @@ type GuestConnection struct {
 	mu         sync.Mutex
 	nextPort   uint32
 	notifyChs  map[string]chan struct{}
+	oomChs     map[string]chan struct{}
 	caps       schema1.GuestDefinedCapabilities
 	os         string
 }
@@ -148,6 +150,15 @@ func (gc *GuestConnection) Modify(ctx context.Context, settings interface{}) err
 	return gc.brdg.RPC(ctx, rpcModifySettings, &req, &resp, false)
 }
 
+// DumpStacks requests the goroutine stacks of the GCS. It is only supported
+// by guests that declare `DumpStacksSupported`.
+func (gc *GuestConnection) DumpStacks(ctx context.Context) (string, error) {
+	req := makeRequest(nullContainerID)
+	var resp dumpStacksResponse
+	err := gc.brdg.RPC(ctx, rpcDumpStacks, &req, &resp, false)
+	return resp.GuestStacks, err
+}
+
 // Close terminates the guest connection. It is undefined to call any other
 // methods on the connection after this is called.
 func (gc *GuestConnection) Close() error {
@@ -185,7 +196,7 @@ func (gc *GuestConnection) newIoChannel() (*ioChannel, uint32, error) {
 	return newIoChannel(l), port, nil
 }
 
-func (gc *GuestConnection) requestNotify(cid string, ch chan struct{}) error {
+func (gc *GuestConnection) requestNotify(cid string, ch, oomCh chan struct{}) error {
 	gc.mu.Lock()
 	defer gc.mu.Unlock()
 	if gc.notifyChs == nil {
@@ -195,31 +206,62 @@ func (gc *GuestConnection) requestNotify(cid string, ch chan struct{}) error {
 		return fmt.Errorf("container %s already exists", cid)
 	}
 	gc.notifyChs[cid] = ch
+	gc.oomChs[cid] = oomCh
 	return nil
 }
 
 func (gc *GuestConnection) notify(ntf *containerNotification) error {
 	cid := ntf.ContainerID
+	if ntf.Type == notificationTypeOOM {
+		gc.notifyOOM(cid)
+		return nil
+	}
 	gc.mu.Lock()
 	ch := gc.notifyChs[cid]
+	oomCh := gc.oomChs[cid]
 	delete(gc.notifyChs, cid)
+	delete(gc.oomChs, cid)
 	gc.mu.Unlock()
 	if ch == nil {
 		return fmt.Errorf("container %s not found", cid)
 	}
 	logrus.WithField(logfields.ContainerID, cid).Info("container terminated in guest")
 	close(ch)
+	close(oomCh)
 	return nil
 }
 
+// notifyOOM signals the OOM channel of container `cid`. If an earlier OOM has
+// not yet been received the two are coalesced. An OOM for an unknown container
+// is logged rather than failing the bridge as it may race the container exit.
+func (gc *GuestConnection) notifyOOM(cid string) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	ch := gc.oomChs[cid]
+	if ch == nil {
+		logrus.WithField(logfields.ContainerID, cid).Warning("ignoring OOM notification for unknown container")
+		return
+	}
+	logrus.WithField(logfields.ContainerID, cid).Warning("process killed by the OOM killer in guest")
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
 func (gc *GuestConnection) clearNotifies() {
 	gc.mu.Lock()
 	chs := gc.notifyChs
+	oomChs := gc.oomChs
 	gc.notifyChs = nil
+	gc.oomChs = nil
 	gc.mu.Unlock()
 	for _, ch := range chs {
 		close(ch)
 	}
+	for _, ch := range oomChs {
+		close(ch)
+	}
 }
 
 func makeRequest(cid string) requestBase {
@@ -108,6 +108,13 @@ func simpleGcsLoop(t *testing.T, rw io.ReadWriter) error {
 			}
 		case rpcWaitForProcess:
 			// nothing
+		case rpcDumpStacks:
+			err := sendJSON(t, rw, msgType(msgTypeResponse|proc), id, &dumpStacksResponse{
+				GuestStacks: "goroutine 1 [running]:",
+			})
+			if err != nil {
+				return err
+			}
 		case rpcShutdownForced:
 			var req requestBase
 			err = json.Unmarshal(b, &req)
@@ -249,3 +256,58 @@ func TestGcsWaitProcessBridgeTerminated(t *testing.T) {
 		t.Fatal("unexpected: ", err)
 	}
 }
+
+func TestGcsNotifyOOM(t *testing.T) {
+	gc := &GuestConnection{
+		notifyChs: make(map[string]chan struct{}),
+		oomChs:    make(map[string]chan struct{}),
+	}
+	notifyCh := make(chan struct{})
+	oomCh := make(chan struct{}, 1)
+	if err := gc.requestNotify("foo", notifyCh, oomCh); err != nil {
+		t.Fatal(err)
+	}
+	ntf := &containerNotification{
+		requestBase: makeRequest("foo"),
+		Type:        notificationTypeOOM,
+	}
+	// The second OOM is coalesced with the first.
+	for i := 0; i < 2; i++ {
+		if err := gc.notify(ntf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// An OOM for an unknown container does not fail the bridge.
+	if err := gc.notify(&containerNotification{requestBase: makeRequest("bar"), Type: notificationTypeOOM}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-oomCh:
+	default:
+		t.Fatal("expected OOM to be signalled")
+	}
+	select {
+	case <-notifyCh:
+		t.Fatal("OOM must not signal container termination")
+	default:
+	}
+	if err := gc.notify(&containerNotification{requestBase: makeRequest("foo")}); err != nil {
+		t.Fatal(err)
+	}
+	<-notifyCh
+	if _, ok := <-oomCh; ok {
+		t.Fatal("expected OOM channel to be closed on termination")
+	}
+}
+
+func TestGcsDumpStacks(t *testing.T) {
+	gc := connectGcs(context.Background(), t)
+	defer gc.Close()
+	stacks, err := gc.DumpStacks(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stacks != "goroutine 1 [running]:" {
+		t.Fatalf("unexpected stacks %q", stacks)
+	}
+}
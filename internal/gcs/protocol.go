@@ -49,6 +49,7 @@ const (
 	rpcModifySettings
 	rpcNegotiateProtocol
 	rpcLifecycleNotification
+	rpcDumpStacks
 )
 
 type msgType uint32
@@ -106,6 +107,8 @@ func (typ msgType) String() string {
 		s += "NegotiateProtocol"
 	case rpcLifecycleNotification:
 		s += "LifecycleNotification"
+	case rpcDumpStacks:
+		s += "DumpStacks"
 	default:
 		s += fmt.Sprintf("%#x", uint32(typ))
 	}
@@ -163,6 +166,11 @@ type uvmConfig struct {
 	SystemType string // must be "Container"
 }
 
+// notificationTypeOOM is the `containerNotification.Type` sent when the guest
+// OOM killer kills a process in the container. Unlike every other
+// notification it does not mean that the container has terminated.
+const notificationTypeOOM = "OomEvent"
+
 type containerNotification struct {
 	requestBase
 	Type       string      // Compute.System.NotificationType
@@ -233,6 +241,11 @@ type containerModifySettings struct {
 	Request interface{}
 }
 
+type dumpStacksResponse struct {
+	responseBase
+	GuestStacks string
+}
+
 type gcsCapabilities struct {
 	SendHostCreateMessage      bool
 	SendHostStartMessage       bool
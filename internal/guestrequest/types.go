@@ -2,6 +2,7 @@ package guestrequest
 
 import (
 	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
 // Arguably, many of these (at least CombinedLayers) should have been generated
@@ -27,8 +28,27 @@ type CombinedLayers struct {
 type LCOWMappedVirtualDisk struct {
 	MountPath  string `json:"MountPath,omitempty"` // /tmp/scratch for an LCOW utility VM being used as a service VM
 	Lun        uint8  `json:"Lun,omitempty"`
-	Controller uint8  `json:"Controller,omitempty"`
+	Controller uint8  `json:"Controller,omitempty"` // Only other than 0 for a guest that advertises MultiSCSIControllerSupported
 	ReadOnly   bool   `json:"ReadOnly,omitempty"`
+	// Filesystem is the type of the filesystem to mount. If empty the guest
+	// default is used. Filesystem and Options are only sent to a guest that
+	// advertises `SCSIMountOptionsSupported`.
+	Filesystem string `json:"Filesystem,omitempty"`
+	// Options are additional mount options such as `noatime`. `discard` is
+	// only sent for an unencrypted container scratch disk to a guest that
+	// advertises `ScratchDiscardSupported`.
+	Options []string `json:"Options,omitempty"`
+	// BlockDev bind mounts the raw block device of the disk at MountPath
+	// instead of mounting its filesystem. It is only sent to a guest that
+	// advertises `BlockDeviceSupported`.
+	BlockDev bool `json:"BlockDev,omitempty"`
+	// Encrypted sets up dm-crypt on the disk with a key generated by the guest
+	// for this boot and formats it before mounting it at MountPath. The
+	// contents of the disk cannot be read once it is removed.
+	Encrypted bool `json:"Encrypted,omitempty"`
+	// VerityInfo is set for a read-only layer with a dm-verity hash device.
+	// The guest verifies the layer with dm-verity before mounting it.
+	VerityInfo *DeviceVerityInfo `json:"VerityInfo,omitempty"`
 }
 
 type WCOWMappedVirtualDisk struct {
@@ -41,12 +61,46 @@ type LCOWMappedDirectory struct {
 	Port      int32  `json:"Port,omitempty"`
 	ShareName string `json:"ShareName,omitempty"` // If empty not using ANames (not currently supported)
 	ReadOnly  bool   `json:"ReadOnly,omitempty"`
+	// Msize and CacheMode are only honored by a guest that advertises
+	// `Plan9OptionsSupported`.
+	Msize     uint32 `json:"Msize,omitempty"`     // If 0 the guest default is used
+	CacheMode string `json:"CacheMode,omitempty"` // If empty the guest default is used
 }
 
 // Read-only layers over VPMem
 type LCOWMappedVPMemDevice struct {
 	DeviceNumber uint32 `json:"DeviceNumber,omitempty"`
 	MountPath    string `json:"MountPath,omitempty"` // /tmp/pN
+	// MappingInfo is set when the layer is one of several mapped into the
+	// same VPMem device. The guest creates a linear device over the region
+	// and mounts that instead of the whole device. The HCS mapping only
+	// carries the offset so this is the only place the size of the region is
+	// passed. Only sent to a guest that advertises
+	// `VPMemMultiMappingSupported`.
+	MappingInfo *LCOWVPMemMappingInfo `json:"MappingInfo,omitempty"`
+	// VerityInfo is set for a layer with a dm-verity hash device. The guest
+	// verifies the layer with dm-verity before mounting it.
+	VerityInfo *DeviceVerityInfo `json:"VerityInfo,omitempty"`
+}
+
+// LCOWVPMemMappingInfo is the region of a multi-mapped VPMem device holding a
+// single read-only layer.
+type LCOWVPMemMappingInfo struct {
+	DeviceOffsetInBytes uint64 `json:"DeviceOffsetInBytes,omitempty"`
+	DeviceSizeInBytes   uint64 `json:"DeviceSizeInBytes,omitempty"`
+}
+
+// DeviceVerityInfo is the dm-verity information of a read-only layer whose
+// hash device is appended to the filesystem on the same device.
+type DeviceVerityInfo struct {
+	// Ext4SizeInBytes is the size of the filesystem, which is also the offset
+	// of the hash device.
+	Ext4SizeInBytes int64  `json:"Ext4SizeInBytes,omitempty"`
+	Version         uint32 `json:"Version,omitempty"`
+	Algorithm       string `json:"Algorithm,omitempty"`
+	BlockSize       uint32 `json:"BlockSize,omitempty"`
+	Salt            string `json:"Salt,omitempty"`
+	RootDigest      string `json:"RootDigest,omitempty"`
 }
 
 type LCOWNetworkAdapter struct {
@@ -72,8 +126,28 @@ const (
 	ResourceTypeNetworkNamespace  ResourceType = "NetworkNamespace"
 	ResourceTypeCombinedLayers    ResourceType = "CombinedLayers"
 	ResourceTypeVPMemDevice       ResourceType = "VPMemDevice"
+	// ResourceTypeContainerConstraints updates the resource limits of a
+	// running container.
+	ResourceTypeContainerConstraints ResourceType = "ContainerConstraints"
+	// ResourceTypeVPCIDevice notifies the guest of a device assigned to the
+	// utility VM over virtual PCI.
+	ResourceTypeVPCIDevice ResourceType = "VPCIDevice"
 )
 
+// LCOWMappedVPCIDevice identifies a device assigned to an LCOW utility VM by
+// the VMBus instance of its virtual PCI bus so the guest can wait for it to
+// be enumerated. Only sent to a guest that advertises `VPCIDevicesSupported`.
+type LCOWMappedVPCIDevice struct {
+	VMBusGUID string `json:",omitempty"`
+}
+
+// LCOWContainerConstraints are the resource limits applied to a running
+// container in an LCOW utility VM.
+type LCOWContainerConstraints struct {
+	Windows specs.WindowsResources `json:",omitempty"`
+	Linux   specs.LinuxResources   `json:",omitempty"`
+}
+
 // GuestRequest is for modify commands passed to the guest.
 type GuestRequest struct {
 	RequestType  string       `json:"RequestType,omitempty"`
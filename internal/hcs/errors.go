@@ -79,8 +79,23 @@ var (
 
 	// ErrNotSupported is an error encountered when hcs doesn't support the request
 	ErrPlatformNotSupported = errors.New("unsupported platform request")
+
+	// ErrRPCServerTooBusy is an error encountered when the compute service is too busy to complete the request
+	ErrRPCServerTooBusy = syscall.Errno(0x6bb)
+
+	// ErrRPCServerUnavailable is an error encountered when the compute service is not available, for example while it restarts
+	ErrRPCServerUnavailable = syscall.Errno(0x6ba)
 )
 
+// transientErrors are the errors caused by a condition in the compute service
+// that is expected to clear on its own. An aborted compute service process or
+// a pending operation may have partially completed the operation, so neither
+// is safe to retry.
+var transientErrors = []error{
+	ErrRPCServerTooBusy,
+	ErrRPCServerUnavailable,
+}
+
 type ErrorEvent struct {
 	Message    string `json:"Message,omitempty"`    // Fully formated error message
 	StackTrace string `json:"StackTrace,omitempty"` // Stack trace in string form
@@ -319,6 +334,27 @@ func IsOperationInvalidState(err error) bool {
 	return err == ErrVmcomputeOperationInvalidState
 }
 
+// IsTransient returns a boolean indicating whether the error is caused by a
+// condition in the compute service that is expected to clear on its own, such
+// that retrying the operation may succeed. Errors wrapped with a `Cause`
+// method are unwrapped first.
+func IsTransient(err error) bool {
+	for {
+		c, ok := err.(interface{ Cause() error })
+		if !ok {
+			break
+		}
+		err = c.Cause()
+	}
+	err = getInnerError(err)
+	for _, t := range transientErrors {
+		if err == t {
+			return true
+		}
+	}
+	return false
+}
+
 func getInnerError(err error) error {
 	switch pe := err.(type) {
 	case nil:
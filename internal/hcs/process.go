@@ -1,6 +1,7 @@
 package hcs
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"sync"
@@ -200,7 +201,7 @@ func (process *Process) Kill() (_ bool, err error) {
 func (process *Process) waitBackground() {
 	operation := "hcsshim::Process::waitBackground"
 	process.logOperationBegin(operation)
-	err := waitForNotification(process.callbackNumber, hcsNotificationProcessExited, nil)
+	err := waitForNotification(context.Background(), process.callbackNumber, hcsNotificationProcessExited, nil)
 	if err != nil {
 		err = makeProcessError(process, "Wait", err, nil)
 	}
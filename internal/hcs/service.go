@@ -0,0 +1,115 @@
+package hcs
+
+import (
+	"encoding/json"
+
+	"github.com/Microsoft/hcsshim/internal/interop"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/sirupsen/logrus"
+)
+
+// GetServiceProperties returns the properties of the host compute service
+// that match the query `q`.
+func GetServiceProperties(q hcsschema.PropertyQuery) (_ *hcsschema.ServiceProperties, err error) {
+	operation := "hcsshim::GetServiceProperties"
+	fields := logrus.Fields{}
+	logOperationBegin(
+		fields,
+		operation+" - Begin Operation")
+
+	defer func() {
+		var result string
+		if err == nil {
+			result = "Success"
+		} else {
+			result = "Error"
+		}
+
+		logOperationEnd(
+			fields,
+			operation+" - End Operation - "+result,
+			err)
+	}()
+
+	queryb, err := json.Marshal(q)
+	if err != nil {
+		return nil, err
+	}
+
+	query := string(queryb)
+
+	logrus.WithFields(fields).
+		WithField(logfields.JSON, query).
+		Debug("HCS Service Query")
+
+	var (
+		resultp     *uint16
+		propertiesp *uint16
+	)
+
+	syscallWatcher(fields, func() {
+		err = hcsGetServiceProperties(query, &propertiesp, &resultp)
+	})
+	events := processHcsResult(resultp)
+	if err != nil {
+		return nil, &HcsError{Op: operation, Err: err, Events: events}
+	}
+
+	if propertiesp == nil {
+		return nil, ErrUnexpectedValue
+	}
+	propertiesRaw := interop.ConvertAndFreeCoTaskMemBytes(propertiesp)
+	properties := &hcsschema.ServiceProperties{}
+	if err = json.Unmarshal(propertiesRaw, properties); err != nil {
+		return nil, err
+	}
+
+	return properties, nil
+}
+
+// ModifyServiceSettings modifies the settings of the host compute service
+// according to `settings`.
+func ModifyServiceSettings(settings hcsschema.ModificationRequest) (err error) {
+	operation := "hcsshim::ModifyServiceSettings"
+	fields := logrus.Fields{}
+	logOperationBegin(
+		fields,
+		operation+" - Begin Operation")
+
+	defer func() {
+		var result string
+		if err == nil {
+			result = "Success"
+		} else {
+			result = "Error"
+		}
+
+		logOperationEnd(
+			fields,
+			operation+" - End Operation - "+result,
+			err)
+	}()
+
+	settingsb, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	request := string(settingsb)
+
+	logrus.WithFields(fields).
+		WithField(logfields.JSON, request).
+		Debug("HCS Service Modify Document")
+
+	var resultp *uint16
+	syscallWatcher(fields, func() {
+		err = hcsModifyServiceSettings(request, &resultp)
+	})
+	events := processHcsResult(resultp)
+	if err != nil {
+		return &HcsError{Op: operation, Err: err, Events: events}
+	}
+
+	return nil
+}
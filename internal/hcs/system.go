@@ -1,6 +1,7 @@
 package hcs
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"os"
@@ -80,7 +81,14 @@ func (computeSystem *System) logOperationEnd(operation string, err error) {
 }
 
 // CreateComputeSystem creates a new compute system with the given configuration but does not start it.
-func CreateComputeSystem(id string, hcsDocumentInterface interface{}) (_ *System, err error) {
+func CreateComputeSystem(id string, hcsDocumentInterface interface{}) (*System, error) {
+	return CreateComputeSystemContext(context.Background(), id, hcsDocumentInterface)
+}
+
+// CreateComputeSystemContext is like CreateComputeSystem but stops waiting for
+// the create to complete and terminates the compute system if `ctx` is done
+// first.
+func CreateComputeSystemContext(ctx context.Context, id string, hcsDocumentInterface interface{}) (_ *System, err error) {
 	operation := "hcsshim::CreateComputeSystem"
 
 	computeSystem := newSystem(id)
@@ -121,9 +129,9 @@ func CreateComputeSystem(id string, hcsDocumentInterface interface{}) (_ *System
 		}
 	}
 
-	events, err := processAsyncHcsResult(createError, resultp, computeSystem.callbackNumber, hcsNotificationSystemCreateCompleted, &timeout.SystemCreate)
+	events, err := processAsyncHcsResult(ctx, createError, resultp, computeSystem.callbackNumber, hcsNotificationSystemCreateCompleted, &timeout.SystemCreate)
 	if err != nil {
-		if err == ErrTimeout {
+		if err == ErrTimeout || err == ctx.Err() {
 			// Terminate the compute system if it still exists. We're okay to
 			// ignore a failure here.
 			computeSystem.Terminate()
@@ -261,7 +269,13 @@ func GetComputeSystems(q schema1.ComputeSystemQuery) (_ []schema1.ContainerPrope
 }
 
 // Start synchronously starts the computeSystem.
-func (computeSystem *System) Start() (err error) {
+func (computeSystem *System) Start() error {
+	return computeSystem.StartContext(context.Background())
+}
+
+// StartContext is like Start but stops waiting for the start to complete if
+// `ctx` is done first. The compute system is left to the caller to terminate.
+func (computeSystem *System) StartContext(ctx context.Context) (err error) {
 	computeSystem.handleLock.RLock()
 	defer computeSystem.handleLock.RUnlock()
 
@@ -303,7 +317,7 @@ func (computeSystem *System) Start() (err error) {
 	syscallWatcher(computeSystem.logctx, func() {
 		err = hcsStartComputeSystem(computeSystem.handle, "", &resultp)
 	})
-	events, err := processAsyncHcsResult(err, resultp, computeSystem.callbackNumber, hcsNotificationSystemStartCompleted, &timeout.SystemStart)
+	events, err := processAsyncHcsResult(ctx, err, resultp, computeSystem.callbackNumber, hcsNotificationSystemStartCompleted, &timeout.SystemStart)
 	if err != nil {
 		return makeSystemError(computeSystem, "Start", "", err, events)
 	}
@@ -380,7 +394,7 @@ func (computeSystem *System) Terminate() (err error) {
 func (computeSystem *System) waitBackground() {
 	operation := "hcsshim::ComputeSystem::waitBackground"
 	computeSystem.logOperationBegin(operation)
-	err := waitForNotification(computeSystem.callbackNumber, hcsNotificationSystemExited, nil)
+	err := waitForNotification(context.Background(), computeSystem.callbackNumber, hcsNotificationSystemExited, nil)
 	switch err {
 	case nil:
 	case ErrVmcomputeUnexpectedExit:
@@ -473,7 +487,7 @@ func (computeSystem *System) Pause() (err error) {
 	syscallWatcher(computeSystem.logctx, func() {
 		err = hcsPauseComputeSystem(computeSystem.handle, "", &resultp)
 	})
-	events, err := processAsyncHcsResult(err, resultp, computeSystem.callbackNumber, hcsNotificationSystemPauseCompleted, &timeout.SystemPause)
+	events, err := processAsyncHcsResult(context.Background(), err, resultp, computeSystem.callbackNumber, hcsNotificationSystemPauseCompleted, &timeout.SystemPause)
 	if err != nil {
 		return makeSystemError(computeSystem, "Pause", "", err, events)
 	}
@@ -498,7 +512,7 @@ func (computeSystem *System) Resume() (err error) {
 	syscallWatcher(computeSystem.logctx, func() {
 		err = hcsResumeComputeSystem(computeSystem.handle, "", &resultp)
 	})
-	events, err := processAsyncHcsResult(err, resultp, computeSystem.callbackNumber, hcsNotificationSystemResumeCompleted, &timeout.SystemResume)
+	events, err := processAsyncHcsResult(context.Background(), err, resultp, computeSystem.callbackNumber, hcsNotificationSystemResumeCompleted, &timeout.SystemResume)
 	if err != nil {
 		return makeSystemError(computeSystem, "Resume", "", err, events)
 	}
@@ -506,6 +520,37 @@ func (computeSystem *System) Resume() (err error) {
 	return nil
 }
 
+// Save saves the state of the computeSystem according to `options`. Typically
+// the computeSystem must be paused first.
+func (computeSystem *System) Save(options interface{}) (err error) {
+	computeSystem.handleLock.RLock()
+	defer computeSystem.handleLock.RUnlock()
+
+	operation := "hcsshim::ComputeSystem::Save"
+	computeSystem.logOperationBegin(operation)
+	defer func() { computeSystem.logOperationEnd(operation, err) }()
+
+	if computeSystem.handle == 0 {
+		return makeSystemError(computeSystem, "Save", "", ErrAlreadyClosed, nil)
+	}
+
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+
+	var resultp *uint16
+	syscallWatcher(computeSystem.logctx, func() {
+		err = hcsSaveComputeSystem(computeSystem.handle, string(optionsJSON), &resultp)
+	})
+	events, err := processAsyncHcsResult(context.Background(), err, resultp, computeSystem.callbackNumber, hcsNotificationSystemSaveCompleted, &timeout.SystemSave)
+	if err != nil {
+		return makeSystemError(computeSystem, "Save", string(optionsJSON), err, events)
+	}
+
+	return nil
+}
+
 func (computeSystem *System) createProcess(c interface{}) (_ *Process, _ *hcsProcessInformation, err error) {
 	computeSystem.handleLock.RLock()
 	defer computeSystem.handleLock.RUnlock()
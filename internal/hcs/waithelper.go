@@ -1,21 +1,23 @@
 package hcs
 
 import (
+	"context"
 	"time"
 
+	"github.com/Microsoft/hcsshim/internal/timeout"
 	"github.com/sirupsen/logrus"
 )
 
-func processAsyncHcsResult(err error, resultp *uint16, callbackNumber uintptr, expectedNotification hcsNotification, timeout *time.Duration) ([]ErrorEvent, error) {
+func processAsyncHcsResult(ctx context.Context, err error, resultp *uint16, callbackNumber uintptr, expectedNotification hcsNotification, t *time.Duration) ([]ErrorEvent, error) {
 	events := processHcsResult(resultp)
 	if IsPending(err) {
-		return nil, waitForNotification(callbackNumber, expectedNotification, timeout)
+		return nil, waitForNotification(ctx, callbackNumber, expectedNotification, t)
 	}
 
 	return events, err
 }
 
-func waitForNotification(callbackNumber uintptr, expectedNotification hcsNotification, timeout *time.Duration) error {
+func waitForNotification(ctx context.Context, callbackNumber uintptr, expectedNotification hcsNotification, t *time.Duration) error {
 	callbackMapLock.RLock()
 	if _, ok := callbackMap[callbackNumber]; !ok {
 		callbackMapLock.RUnlock()
@@ -32,8 +34,8 @@ func waitForNotification(callbackNumber uintptr, expectedNotification hcsNotific
 	}
 
 	var c <-chan time.Time
-	if timeout != nil {
-		timer := time.NewTimer(*timeout)
+	if t != nil {
+		timer := time.NewTimer(timeout.Load(t))
 		c = timer.C
 		defer timer.Stop()
 	}
@@ -63,6 +65,8 @@ func waitForNotification(callbackNumber uintptr, expectedNotification hcsNotific
 		return ErrUnexpectedProcessAbort
 	case <-c:
 		return ErrTimeout
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 	return nil
 }
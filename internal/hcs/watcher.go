@@ -23,7 +23,7 @@ import (
 //
 
 func syscallWatcher(logContext logrus.Fields, syscallLambda func()) {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout.SyscallWatcher)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Load(&timeout.SyscallWatcher))
 	defer cancel()
 	go watchFunc(ctx, logContext)
 	syscallLambda()
@@ -34,7 +34,7 @@ func watchFunc(ctx context.Context, logContext logrus.Fields) {
 	case <-ctx.Done():
 		if ctx.Err() != context.Canceled {
 			logrus.WithFields(logContext).
-				WithField(logfields.Timeout, timeout.SyscallWatcher).
+				WithField(logfields.Timeout, timeout.Load(&timeout.SyscallWatcher)).
 				Warning("Syscall did not complete within operation timeout. This may indicate a platform issue. If it appears to be making no forward progress, obtain the stacks and see if there is a syscall stuck in the platform API for a significant length of time.")
 		}
 	}
@@ -134,6 +134,17 @@ func copyAndLog(w io.Writer, r io.Reader, log *logrus.Entry, name string) (int64
 	return n, err
 }
 
+// Attach returns a command for the running process `p` in `host` that was not
+// started by a command, such as a process opened by pid. Its IO is not
+// relayed. Wait waits for `p` to exit and closes it.
+func Attach(host cow.ProcessHost, p cow.Process) *Cmd {
+	return &Cmd{
+		Host:      host,
+		Process:   p,
+		allDoneCh: make(chan struct{}),
+	}
+}
+
 // Start starts a command. The caller must ensure that if Start succeeds,
 // Wait is eventually called to clean up resources.
 func (c *Cmd) Start() error {
@@ -247,3 +247,16 @@ func TestCmdStuckIo(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestCmdAttach(t *testing.T) {
+	host := &localProcessHost{}
+	p, err := host.CreateProcess(&hcsschema.ProcessParameters{CommandLine: "cmd /c exit /b 3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := Attach(host, p)
+	err = cmd.Wait()
+	if e, ok := err.(*ExitError); !ok || e.ExitCode() != 3 {
+		t.Fatal("expected exit code 3, got ", err)
+	}
+}
@@ -3,6 +3,7 @@
 package hcsoci
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -10,12 +11,13 @@ import (
 	"strconv"
 
 	"github.com/Microsoft/go-winio/pkg/guid"
+	"github.com/Microsoft/hcsshim/internal/backend"
 	"github.com/Microsoft/hcsshim/internal/cow"
-	"github.com/Microsoft/hcsshim/internal/hcs"
 	"github.com/Microsoft/hcsshim/internal/oci"
 	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
 	"github.com/Microsoft/hcsshim/internal/schemaversion"
 	"github.com/Microsoft/hcsshim/internal/uvm"
+	publicschemaversion "github.com/Microsoft/hcsshim/pkg/schemaversion"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
 )
@@ -34,6 +36,7 @@ type CreateOptions struct {
 	SchemaVersion    *hcsschema.Version // Requested Schema Version. Defaults to v2 for RS5, v1 for RS1..RS4
 	HostingSystem    *uvm.UtilityVM     // Utility or service VM in which the container is to be created.
 	NetworkNamespace string             // Host network namespace to use (overrides anything in the spec)
+	Backend          cow.Backend        // Backend to create a host compute system on. Defaults to the local vmcompute service. Ignored if HostingSystem is supplied.
 
 	// This is an advanced debugging parameter. It allows for diagnosibility by leaving a containers
 	// resources allocated in case of a failure. Thus you would be able to use tools such as hcsdiag
@@ -48,10 +51,12 @@ type CreateOptions struct {
 type createOptionsInternal struct {
 	*CreateOptions
 
-	actualSchemaVersion    *hcsschema.Version // Calculated based on Windows build and optional caller-supplied override
-	actualID               string             // Identifier for the container
-	actualOwner            string             // Owner for the container
+	actualSchemaVersion    *hcsschema.Version                // Calculated based on Windows build and optional caller-supplied override
+	actualDocumentShape    publicschemaversion.DocumentShape // Shape of the document passed to the HCS or the hosting system
+	actualID               string                            // Identifier for the container
+	actualOwner            string                            // Owner for the container
 	actualNetworkNamespace string
+	actualVPCIBuses        []string // VMBus GUIDs of the virtual PCI buses of the devices assigned to the container
 }
 
 // CreateContainer creates a container. It can cope with a  wide variety of
@@ -60,7 +65,13 @@ type createOptionsInternal struct {
 // case of an error. This provides support for the debugging option not to
 // release the resources on failure, so that the client can make the necessary
 // call to release resources that have been allocated as part of calling this function.
-func CreateContainer(createOptions *CreateOptions) (_ cow.Container, _ *Resources, err error) {
+func CreateContainer(createOptions *CreateOptions) (cow.Container, *Resources, error) {
+	return CreateContainerContext(context.Background(), createOptions)
+}
+
+// CreateContainerContext is like CreateContainer but stops waiting for the
+// compute system to be created if `ctx` is done first.
+func CreateContainerContext(ctx context.Context, createOptions *CreateOptions) (_ cow.Container, _ *Resources, err error) {
 	coi := &createOptionsInternal{
 		CreateOptions: createOptions,
 		actualID:      createOptions.ID,
@@ -83,16 +94,32 @@ func CreateContainer(createOptions *CreateOptions) (_ cow.Container, _ *Resource
 		return nil, nil, fmt.Errorf("Spec must be supplied")
 	}
 
+	if coi.Backend == nil && coi.HostingSystem == nil {
+		b, err := oci.ParseAnnotationsBackend(coi.Spec)
+		if err != nil {
+			return nil, nil, err
+		}
+		coi.Backend = b
+	}
+
+	requestedSchemaVersion := coi.SchemaVersion
 	if coi.HostingSystem != nil {
 		// By definition, a hosting system can only be supplied for a v2 Xenon.
-		coi.actualSchemaVersion = schemaversion.SchemaV21()
-	} else {
-		coi.actualSchemaVersion = schemaversion.DetermineSchemaVersion(coi.SchemaVersion)
+		requestedSchemaVersion = schemaversion.SchemaV21()
+	}
+	coi.actualSchemaVersion, coi.actualDocumentShape, err = schemaversion.DetermineDocumentShape(requestedSchemaVersion, publicschemaversion.Features{
+		LCOW:              coi.Spec.Linux != nil,
+		HostedInUtilityVM: coi.HostingSystem != nil,
+		NetworkNamespace:  coi.NetworkNamespace != "",
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
 	logrus.WithFields(logrus.Fields{
 		"options": fmt.Sprintf("%+v", createOptions),
 		"schema":  coi.actualSchemaVersion,
+		"shape":   coi.actualDocumentShape,
 	}).Debug("hcsshim::CreateContainer")
 
 	resources := &Resources{}
@@ -155,11 +182,17 @@ func CreateContainer(createOptions *CreateOptions) (_ cow.Container, _ *Resource
 		}
 	}
 
+	addProxyEnv(coi.Spec)
+
+	if err := allocateDevices(coi, resources); err != nil {
+		return nil, resources, err
+	}
+
 	var hcsDocument, gcsDocument interface{}
 	logrus.Debug("hcsshim::CreateContainer allocating resources")
 	if coi.Spec.Linux != nil {
-		if schemaversion.IsV10(coi.actualSchemaVersion) {
-			return nil, resources, errors.New("LCOW v1 not supported")
+		if p := oci.ParseAnnotationsCACertificates(coi.Spec); p != "" {
+			addCACertificates(coi.Spec, p)
 		}
 		logrus.Debug("hcsshim::CreateContainer allocateLinuxResources")
 		err = allocateLinuxResources(coi, resources)
@@ -173,6 +206,9 @@ func CreateContainer(createOptions *CreateOptions) (_ cow.Container, _ *Resource
 			return nil, resources, err
 		}
 	} else {
+		if oci.ParseAnnotationsCACertificates(coi.Spec) != "" {
+			return nil, resources, errors.New("CA certificates are only supported for LCOW containers")
+		}
 		err = allocateWindowsResources(coi, resources)
 		if err != nil {
 			logrus.WithError(err).Debug("failed to allocateWindowsResources")
@@ -185,16 +221,17 @@ func CreateContainer(createOptions *CreateOptions) (_ cow.Container, _ *Resource
 			return nil, resources, err
 		}
 
-		if schemaversion.IsV10(coi.actualSchemaVersion) {
+		switch coi.actualDocumentShape {
+		case publicschemaversion.ContainerConfigV1:
 			// v1 Argon or Xenon. Pass the document directly to HCS.
 			hcsDocument = v1
-		} else if coi.HostingSystem != nil {
+		case publicschemaversion.HostedSystemV2:
 			// v2 Xenon. Pass the container object to the UVM.
 			gcsDocument = &hcsschema.HostedSystem{
 				SchemaVersion: schemaversion.SchemaV21(),
 				Container:     v2,
 			}
-		} else {
+		default:
 			// v2 Argon. Pass the container object to the HCS.
 			hcsDocument = &hcsschema.ComputeSystem{
 				Owner:                             coi.actualOwner,
@@ -207,14 +244,14 @@ func CreateContainer(createOptions *CreateOptions) (_ cow.Container, _ *Resource
 
 	logrus.Debug("hcsshim::CreateContainer creating compute system")
 	if gcsDocument != nil {
-		c, err := coi.HostingSystem.CreateContainer(coi.actualID, gcsDocument)
+		c, err := coi.HostingSystem.CreateContainer(ctx, coi.actualID, gcsDocument)
 		if err != nil {
 			return nil, resources, err
 		}
 		return c, resources, nil
 	}
 
-	system, err := hcs.CreateComputeSystem(coi.actualID, hcsDocument)
+	system, err := backend.OrDefault(coi.Backend).CreateComputeSystem(ctx, coi.actualID, hcsDocument)
 	if err != nil {
 		return nil, resources, err
 	}
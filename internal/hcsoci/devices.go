@@ -0,0 +1,79 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"fmt"
+
+	"github.com/Microsoft/hcsshim/internal/oci"
+	"github.com/Microsoft/hcsshim/internal/schema1"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// deviceIDTypeClass is the `specs.WindowsDevice.IDType` of a device
+	// assigned to a WCOW container by its interface class GUID.
+	deviceIDTypeClass = "class"
+	// deviceIDTypeVPCIInstanceID is the `specs.WindowsDevice.IDType` of a
+	// device assigned to the UVM of a hypervisor isolated container over
+	// virtual PCI by its host device instance path.
+	deviceIDTypeVPCIInstanceID = "vpci-instance-id"
+	// deviceIDTypeVPCIBus is the `specs.WindowsDevice.IDType` of a device in
+	// the spec sent to an LCOW guest. The ID is the VMBus GUID of the virtual
+	// PCI bus the guest exposes to the container.
+	deviceIDTypeVPCIBus = "vpci-bus"
+)
+
+// allocateDevices assigns the virtual PCI devices requested in `coi.Spec`
+// either via `Windows.Devices` or the devices annotation to the hosting
+// system of the container.
+func allocateDevices(coi *createOptionsInternal, resources *Resources) error {
+	devices := oci.ParseAnnotationsVPCIDevices(coi.Spec)
+	if coi.Spec.Windows != nil {
+		for _, d := range coi.Spec.Windows.Devices {
+			switch d.IDType {
+			case deviceIDTypeVPCIInstanceID:
+				devices = append(devices, d.ID)
+			case deviceIDTypeClass:
+				if coi.Spec.Linux != nil {
+					return fmt.Errorf("device %s of type %q is not supported for LCOW", d.ID, d.IDType)
+				}
+			default:
+				return fmt.Errorf("device %s has unsupported type %q", d.ID, d.IDType)
+			}
+		}
+	}
+	if len(devices) == 0 {
+		return nil
+	}
+	if coi.HostingSystem == nil {
+		return fmt.Errorf("virtual PCI devices can only be assigned to hypervisor isolated containers")
+	}
+	for _, d := range devices {
+		logrus.WithField("device", d).Debug("hcsshim::allocateDevices assigning virtual PCI device")
+		vmbusGUID, err := coi.HostingSystem.AddDevice(d)
+		if err != nil {
+			return err
+		}
+		resources.vpciDevices = append(resources.vpciDevices, d)
+		coi.actualVPCIBuses = append(coi.actualVPCIBuses, vmbusGUID.String())
+	}
+	return nil
+}
+
+// classDevices returns the devices in `coi.Spec` to assign to a WCOW container
+// by interface class GUID.
+func classDevices(coi *createOptionsInternal) ([]schema1.AssignedDevice, []hcsschema.Device) {
+	var (
+		v1 []schema1.AssignedDevice
+		v2 []hcsschema.Device
+	)
+	for _, d := range coi.Spec.Windows.Devices {
+		if d.IDType == deviceIDTypeClass {
+			v1 = append(v1, schema1.AssignedDevice{InterfaceClassGUID: d.ID})
+			v2 = append(v2, hcsschema.Device{InterfaceClassGuid: d.ID})
+		}
+	}
+	return v1, v2
+}
@@ -0,0 +1,90 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/oci"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func Test_allocateDevices_None(t *testing.T) {
+	coi := &createOptionsInternal{
+		CreateOptions: &CreateOptions{Spec: &specs.Spec{Windows: &specs.Windows{}}},
+	}
+	r := &Resources{}
+	if err := allocateDevices(coi, r); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if len(r.vpciDevices) != 0 {
+		t.Fatalf("expected no devices, got: %v", r.vpciDevices)
+	}
+}
+
+func Test_allocateDevices_Class_Argon(t *testing.T) {
+	coi := &createOptionsInternal{
+		CreateOptions: &CreateOptions{
+			Spec: &specs.Spec{
+				Windows: &specs.Windows{
+					Devices: []specs.WindowsDevice{{ID: "5B45201D-F2F2-4F3B-85BB-30FF1F953599", IDType: deviceIDTypeClass}},
+				},
+			},
+		},
+	}
+	if err := allocateDevices(coi, &Resources{}); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	v1, v2 := classDevices(coi)
+	if len(v1) != 1 || len(v2) != 1 || v2[0].InterfaceClassGuid != "5B45201D-F2F2-4F3B-85BB-30FF1F953599" {
+		t.Fatalf("expected class device to be assigned, got: %+v %+v", v1, v2)
+	}
+}
+
+func Test_allocateDevices_Class_LCOW(t *testing.T) {
+	coi := &createOptionsInternal{
+		CreateOptions: &CreateOptions{
+			Spec: &specs.Spec{
+				Linux: &specs.Linux{},
+				Windows: &specs.Windows{
+					Devices: []specs.WindowsDevice{{ID: "5B45201D-F2F2-4F3B-85BB-30FF1F953599", IDType: deviceIDTypeClass}},
+				},
+			},
+		},
+	}
+	if err := allocateDevices(coi, &Resources{}); err == nil {
+		t.Fatal("expected error for class device on LCOW")
+	}
+}
+
+func Test_allocateDevices_UnknownType(t *testing.T) {
+	coi := &createOptionsInternal{
+		CreateOptions: &CreateOptions{
+			Spec: &specs.Spec{
+				Windows: &specs.Windows{
+					Devices: []specs.WindowsDevice{{ID: "foo", IDType: "bar"}},
+				},
+			},
+		},
+	}
+	if err := allocateDevices(coi, &Resources{}); err == nil {
+		t.Fatal("expected error for unknown device type")
+	}
+}
+
+func Test_allocateDevices_VPCI_NoHostingSystem(t *testing.T) {
+	coi := &createOptionsInternal{
+		CreateOptions: &CreateOptions{
+			Spec: &specs.Spec{
+				Annotations: map[string]string{oci.AnnotationContainerVPCIDevices: `PCIROOT(0)#PCI(0300)`},
+			},
+		},
+	}
+	r := &Resources{}
+	if err := allocateDevices(coi, r); err == nil {
+		t.Fatal("expected error for virtual PCI device without a hosting system")
+	}
+	if len(r.vpciDevices) != 0 {
+		t.Fatalf("expected no devices, got: %v", r.vpciDevices)
+	}
+}
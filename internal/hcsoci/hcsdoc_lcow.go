@@ -3,10 +3,17 @@
 package hcsoci
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
 
+	"github.com/Microsoft/hcsshim/internal/cow"
+	"github.com/Microsoft/hcsshim/internal/oci"
 	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
 	"github.com/Microsoft/hcsshim/internal/schemaversion"
+	"github.com/Microsoft/hcsshim/internal/timeout"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
 )
@@ -24,7 +31,7 @@ func createLCOWSpec(coi *createOptionsInternal) (*specs.Spec, error) {
 	}
 
 	// Linux containers don't care about Windows aspects of the spec except the
-	// network namespace
+	// network namespace and the virtual PCI devices assigned to the container
 	spec.Windows = nil
 	if coi.Spec.Windows != nil &&
 		coi.Spec.Windows.Network != nil &&
@@ -35,6 +42,17 @@ func createLCOWSpec(coi *createOptionsInternal) (*specs.Spec, error) {
 			},
 		}
 	}
+	if len(coi.actualVPCIBuses) > 0 {
+		if spec.Windows == nil {
+			spec.Windows = &specs.Windows{}
+		}
+		for _, b := range coi.actualVPCIBuses {
+			spec.Windows.Devices = append(spec.Windows.Devices, specs.WindowsDevice{
+				ID:     b,
+				IDType: deviceIDTypeVPCIBus,
+			})
+		}
+	}
 
 	// Hooks are not supported (they should be run in the host)
 	spec.Hooks = nil
@@ -52,6 +70,22 @@ func createLCOWSpec(coi *createOptionsInternal) (*specs.Spec, error) {
 	}
 	spec.Linux.Seccomp = nil
 
+	if oci.ParseAnnotationsLCOWInit(coi.Spec) && spec.Process != nil {
+		if coi.HostingSystem != nil {
+			if err := checkContainerInit(coi.HostingSystem); err != nil {
+				return nil, err
+			}
+		}
+		addContainerInit(spec)
+	}
+
+	if oci.ParseAnnotationsVirtualTPM(coi.Spec) {
+		if coi.HostingSystem == nil || !coi.HostingSystem.VirtualTPMEnabled() {
+			return nil, errors.New("virtual TPM requested but the utility VM has no virtual TPM")
+		}
+		addVirtualTPMDevice(spec)
+	}
+
 	// Clear any specified namespaces
 	var namespaces []specs.LinuxNamespace
 	for _, ns := range spec.Linux.Namespaces {
@@ -67,6 +101,76 @@ func createLCOWSpec(coi *createOptionsInternal) (*specs.Spec, error) {
 	return spec, nil
 }
 
+// The first TPM in the guest always uses the legacy misc device number.
+const (
+	tpmDevicePath  = "/dev/tpm0"
+	tpmDeviceMajor = 10
+	tpmDeviceMinor = 224
+)
+
+// addVirtualTPMDevice adds the virtual TPM device of the utility VM to `spec`
+// and allows access to it in the device cgroup.
+func addVirtualTPMDevice(spec *specs.Spec) {
+	mode := os.FileMode(0600)
+	uid, gid := uint32(0), uint32(0)
+	spec.Linux.Devices = append(spec.Linux.Devices, specs.LinuxDevice{
+		Path:     tpmDevicePath,
+		Type:     "c",
+		Major:    tpmDeviceMajor,
+		Minor:    tpmDeviceMinor,
+		FileMode: &mode,
+		UID:      &uid,
+		GID:      &gid,
+	})
+	if spec.Linux.Resources == nil {
+		spec.Linux.Resources = &specs.LinuxResources{}
+	}
+	major, minor := int64(tpmDeviceMajor), int64(tpmDeviceMinor)
+	spec.Linux.Resources.Devices = append(spec.Linux.Resources.Devices, specs.LinuxDeviceCgroup{
+		Allow:  true,
+		Type:   "c",
+		Major:  &major,
+		Minor:  &minor,
+		Access: "rwm",
+	})
+}
+
+const (
+	// containerInitGuestPath is the path in the UVM of the minimal init used
+	// to reap zombie processes in LCOW containers.
+	containerInitGuestPath = "/sbin/tini"
+	// containerInitPath is the path the init is mounted at in the container.
+	containerInitPath = "/dev/init"
+)
+
+// checkContainerInit returns an error if the minimal init is not present in
+// the utility VM `host`. Otherwise the container would fail to start with an
+// error from the guest that does not name the missing init.
+func checkContainerInit(host cow.ProcessHost) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Load(&timeout.ExternalCommandToComplete))
+	defer cancel()
+	cmd := CommandContext(ctx, host, "test", "-x", containerInitGuestPath)
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*ExitError); ok {
+			return fmt.Errorf("container init requested but '%s' is not present in the utility VM", containerInitGuestPath)
+		}
+		return fmt.Errorf("failed to check for container init '%s' in the utility VM: %s", containerInitGuestPath, err)
+	}
+	return nil
+}
+
+// addContainerInit runs the process in `spec` under the minimal init of the
+// utility VM so that zombie processes in the container are reaped.
+func addContainerInit(spec *specs.Spec) {
+	spec.Mounts = append(spec.Mounts, specs.Mount{
+		Destination: containerInitPath,
+		Type:        "bind",
+		Source:      containerInitGuestPath,
+		Options:     []string{"bind", "ro"},
+	})
+	spec.Process.Args = append([]string{containerInitPath, "--"}, spec.Process.Args...)
+}
+
 type linuxHostedSystem struct {
 	SchemaVersion    *hcsschema.Version
 	OciBundlePath    string
@@ -0,0 +1,112 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/cow"
+	"github.com/Microsoft/hcsshim/internal/cow/cowtest"
+	"github.com/Microsoft/hcsshim/internal/oci"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func Test_addVirtualTPMDevice(t *testing.T) {
+	spec := &specs.Spec{Linux: &specs.Linux{}}
+	addVirtualTPMDevice(spec)
+	if len(spec.Linux.Devices) != 1 || spec.Linux.Devices[0].Path != tpmDevicePath {
+		t.Fatalf("expected %s device, got: %+v", tpmDevicePath, spec.Linux.Devices)
+	}
+	if len(spec.Linux.Resources.Devices) != 1 || !spec.Linux.Resources.Devices[0].Allow {
+		t.Fatalf("expected device cgroup allow rule, got: %+v", spec.Linux.Resources.Devices)
+	}
+}
+
+func Test_createLCOWSpec_VirtualTPM_NoUVM_Error(t *testing.T) {
+	coi := &createOptionsInternal{
+		CreateOptions: &CreateOptions{
+			Spec: &specs.Spec{
+				Linux: &specs.Linux{},
+				Annotations: map[string]string{
+					oci.AnnotationContainerVirtualTPM: "true",
+				},
+			},
+		},
+	}
+	if _, err := createLCOWSpec(coi); err == nil {
+		t.Fatal("expected error for virtual TPM without a utility VM")
+	}
+}
+
+func Test_createLCOWSpec_Init(t *testing.T) {
+	coi := &createOptionsInternal{
+		CreateOptions: &CreateOptions{
+			Spec: &specs.Spec{
+				Process: &specs.Process{Args: []string{"entrypoint"}},
+				Linux:   &specs.Linux{},
+				Annotations: map[string]string{
+					oci.AnnotationContainerLCOWInit: "true",
+				},
+			},
+		},
+	}
+	spec, err := createLCOWSpec(coi)
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if len(spec.Process.Args) != 3 || spec.Process.Args[0] != containerInitPath || spec.Process.Args[2] != "entrypoint" {
+		t.Fatalf("expected process to run under init, got: %v", spec.Process.Args)
+	}
+	if len(spec.Mounts) != 1 || spec.Mounts[0].Source != containerInitGuestPath {
+		t.Fatalf("expected init mount, got: %+v", spec.Mounts)
+	}
+	if len(coi.Spec.Process.Args) != 1 {
+		t.Fatalf("original spec must not be modified, got: %v", coi.Spec.Process.Args)
+	}
+}
+
+// newInitCheckHost returns a fake utility VM whose processes exit with `code`.
+func newInitCheckHost(code int) *cowtest.Container {
+	host := cowtest.NewContainer("uvm", "linux", false)
+	host.OnCreateProcess = func(c *cowtest.Container, config interface{}) (cow.Process, error) {
+		p := cowtest.NewProcess(1)
+		p.Exit(code, nil)
+		return p, nil
+	}
+	return host
+}
+
+func Test_checkContainerInit(t *testing.T) {
+	if err := checkContainerInit(newInitCheckHost(0)); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	err := checkContainerInit(newInitCheckHost(1))
+	if err == nil || !strings.Contains(err.Error(), containerInitGuestPath) {
+		t.Fatalf("expected missing init error, got: %v", err)
+	}
+}
+
+func Test_createLCOWSpec_VPCIDevices(t *testing.T) {
+	coi := &createOptionsInternal{
+		CreateOptions: &CreateOptions{
+			Spec: &specs.Spec{
+				Linux: &specs.Linux{},
+				Windows: &specs.Windows{
+					Devices: []specs.WindowsDevice{{ID: `PCIROOT(0)#PCI(0300)`, IDType: deviceIDTypeVPCIInstanceID}},
+				},
+			},
+		},
+		actualVPCIBuses: []string{"f4d1c8e0-9a3c-4b8e-a1b2-c3d4e5f60718"},
+	}
+	spec, err := createLCOWSpec(coi)
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if spec.Windows == nil || len(spec.Windows.Devices) != 1 {
+		t.Fatalf("expected 1 device, got: %+v", spec.Windows)
+	}
+	if d := spec.Windows.Devices[0]; d.IDType != deviceIDTypeVPCIBus || d.ID != coi.actualVPCIBuses[0] {
+		t.Fatalf("expected the virtual PCI bus of the device, got: %+v", d)
+	}
+}
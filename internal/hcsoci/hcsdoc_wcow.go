@@ -288,5 +288,12 @@ func createWindowsContainerDocument(coi *createOptionsInternal) (*schema1.Contai
 	}
 	v1.MappedPipes = mpsv1
 	v2Container.MappedPipes = mpsv2
+
+	adsv1, adsv2 := classDevices(coi)
+	if len(adsv1) > 0 && osversion.Get().Build < osversion.RS5 {
+		return nil, nil, fmt.Errorf("device assignment is not supported on this version of Windows")
+	}
+	v1.AssignedDevices = adsv1
+	v2Container.AssignedDevices = adsv2
 	return v1, v2Container, nil
 }
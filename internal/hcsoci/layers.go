@@ -9,11 +9,13 @@ import (
 	"path/filepath"
 
 	"github.com/Microsoft/hcsshim/internal/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/oci"
 	"github.com/Microsoft/hcsshim/internal/ospath"
 	"github.com/Microsoft/hcsshim/internal/requesttype"
 	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
 	"github.com/Microsoft/hcsshim/internal/uvm"
 	"github.com/Microsoft/hcsshim/internal/wclayer"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -85,8 +87,8 @@ func MountContainerLayers(layerFolders []string, guestRoot string, uvm *uvm.Util
 	logrus.WithField("os", uvm.OS()).Debug("hcsshim::mountContainerLayers V2 UVM")
 
 	// 	Add each read-only layers. For Windows, this is a VSMB share with the ResourceUri ending in
-	// a GUID based on the folder path. For Linux, this is a VPMEM device, except where is over the
-	// max size supported, where we put it on SCSI instead.
+	// a GUID based on the folder path. For Linux, this is a VPMEM device or SCSI disk depending on
+	// the layer attachment policy of the utility VM.
 	//
 	//  Each layer is ref-counted so that multiple containers in the same utility VM can share them.
 	var wcowLayersAdded []string
@@ -108,35 +110,10 @@ func MountContainerLayers(layerFolders []string, guestRoot string, uvm *uvm.Util
 				wcowLayersAdded = append(wcowLayersAdded, layerPath)
 			}
 		} else {
-			uvmPath := ""
-			hostPath := filepath.Join(layerPath, "layer.vhd")
-
-			var fi os.FileInfo
-			fi, err = os.Stat(hostPath)
-			if err == nil && uint64(fi.Size()) > uvm.PMemMaxSizeBytes() {
-				// Too big for PMEM. Add on SCSI instead (at /tmp/S<C>/<L>).
-				var (
-					controller int
-					lun        int32
-				)
-				controller, lun, err = uvm.AddSCSILayer(hostPath)
-				if err == nil {
-					lcowlayersAdded = append(lcowlayersAdded,
-						lcowLayerEntry{
-							hostPath: hostPath,
-							uvmPath:  fmt.Sprintf("/tmp/S%d/%d", controller, lun),
-							scsi:     true,
-						})
-				}
-			} else {
-				_, uvmPath, err = uvm.AddVPMEM(hostPath, true) // UVM path is calculated. Will be /tmp/vN/
-				if err == nil {
-					lcowlayersAdded = append(lcowlayersAdded,
-						lcowLayerEntry{
-							hostPath: hostPath,
-							uvmPath:  uvmPath,
-						})
-				}
+			var entry lcowLayerEntry
+			entry, err = addLCOWLayer(uvm, filepath.Join(layerPath, "layer.vhd"))
+			if err == nil {
+				lcowlayersAdded = append(lcowlayersAdded, entry)
 			}
 		}
 		if err != nil {
@@ -151,7 +128,12 @@ func MountContainerLayers(layerFolders []string, guestRoot string, uvm *uvm.Util
 
 	// BUGBUG Rename guestRoot better.
 	containerScratchPathInUVM := ospath.Join(uvm.OS(), guestRoot, scratchPath)
-	_, _, err := uvm.AddSCSI(hostPath, containerScratchPathInUVM, false)
+	discard := uvm.ScratchDiscardEnabled()
+	if discard && !uvm.ScratchDiscardSupported() {
+		cleanupOnMountFailure(uvm, wcowLayersAdded, lcowlayersAdded, attachedSCSIHostPath)
+		return nil, errors.New("scratch discard is not supported by the guest")
+	}
+	_, _, err := uvm.AddSCSI(hostPath, scratchSCSIOptions(containerScratchPathInUVM, uvm.ScratchFilesystem(), uvm.ScratchEncryptionEnabled(), discard))
 	if err != nil {
 		cleanupOnMountFailure(uvm, wcowLayersAdded, lcowlayersAdded, attachedSCSIHostPath)
 		return nil, err
@@ -323,24 +305,16 @@ func UnmountContainerLayers(layerFolders []string, guestRoot string, uvm *uvm.Ut
 
 	// Remove each of the read-only layers from VPMEM (or SCSI). These's are ref-counted
 	// and only removed once the count drops to zero. This allows multiple containers to
-	// share layers. Note that SCSI is used on large layers.
+	// share layers. Layers are removed from whichever device they were attached to.
 	if uvm.OS() == "linux" && len(layerFolders) > 1 && (op&UnmountOperationVPMEM) == UnmountOperationVPMEM {
 		for _, layerPath := range layerFolders[:len(layerFolders)-1] {
 			hostPath := filepath.Join(layerPath, "layer.vhd")
-			if fi, err := os.Stat(hostPath); err != nil {
-				var e error
-				if uint64(fi.Size()) > uvm.PMemMaxSizeBytes() {
-					e = uvm.RemoveSCSI(hostPath)
+			if e := uvm.RemoveLayer(hostPath); e != nil {
+				logrus.WithError(e).Debug("remove layer failed")
+				if retError == nil {
+					retError = e
 				} else {
-					e = uvm.RemoveVPMEM(hostPath)
-				}
-				if e != nil {
-					logrus.WithError(e).Debug("remove layer failed")
-					if retError == nil {
-						retError = e
-					} else {
-						retError = errors.Wrapf(retError, e.Error())
-					}
+					retError = errors.Wrapf(retError, e.Error())
 				}
 			}
 		}
@@ -351,6 +325,68 @@ func UnmountContainerLayers(layerFolders []string, guestRoot string, uvm *uvm.Ut
 	return retError
 }
 
+// addLCOWLayer attaches the read-only layer VHD at `hostPath` to the LCOW
+// utility VM `vm` as its layer attachment policy requires.
+func addLCOWLayer(vm *uvm.UtilityVM, hostPath string) (lcowLayerEntry, error) {
+	if vm.LayerAttachmentPolicy() != uvm.LayerAttachmentPolicySCSI {
+		fi, err := os.Stat(hostPath)
+		if err != nil {
+			return lcowLayerEntry{}, err
+		}
+		// Layers too big for PMEM go on SCSI instead.
+		if uint64(fi.Size()) <= vm.PMemMaxSizeBytes() {
+			_, uvmPath, err := vm.AddVPMEM(hostPath, true) // UVM path is calculated. Will be /tmp/pN
+			if err == nil {
+				return lcowLayerEntry{hostPath: hostPath, uvmPath: uvmPath}, nil
+			}
+			if err != uvm.ErrNoFreeVPMEMLocations {
+				return lcowLayerEntry{}, err
+			}
+			logrus.WithField("host-path", hostPath).Debug("hcsshim::mountContainerLayers no free VPMEM locations, adding layer on SCSI")
+		}
+	}
+	controller, lun, err := vm.AddSCSILayer(hostPath) // UVM path will be /tmp/S<C>/<L>
+	if err != nil {
+		return lcowLayerEntry{}, err
+	}
+	return lcowLayerEntry{
+		hostPath: hostPath,
+		uvmPath:  fmt.Sprintf("/tmp/S%d/%d", controller, lun),
+		scsi:     true,
+	}, nil
+}
+
+// setLayerRootDigests records the dm-verity root digests of the read-only
+// layers of the container `s` in its annotations with the utility VM `vm` so
+// that the layers are verified against them.
+func setLayerRootDigests(vm *uvm.UtilityVM, s *specs.Spec) error {
+	digests := oci.ParseAnnotationsLayerRootDigests(s)
+	if vm == nil || len(digests) == 0 {
+		return nil
+	}
+	layers := s.Windows.LayerFolders[:len(s.Windows.LayerFolders)-1]
+	if len(digests) != len(layers) {
+		return fmt.Errorf("expected %d layer root digests, got %d", len(layers), len(digests))
+	}
+	for i, layer := range layers {
+		if err := vm.SetLayerRootDigest(filepath.Join(layer, "layer.vhd"), digests[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scratchSCSIOptions returns the options used to attach a container scratch
+// disk formatted with `filesystem` mounted at `uvmPath` in the utility VM,
+// encrypted if `encrypted` and with discard if `discard`.
+func scratchSCSIOptions(uvmPath, filesystem string, encrypted, discard bool) *uvm.SCSIOptions {
+	options := &uvm.SCSIOptions{UVMPath: uvmPath, Filesystem: filesystem, Encrypted: encrypted}
+	if discard {
+		options.MountOptions = []string{"discard"}
+	}
+	return options
+}
+
 func cleanupOnMountFailure(uvm *uvm.UtilityVM, wcowLayers []string, lcowLayers []lcowLayerEntry, scratchHostPath string) {
 	for _, wl := range wcowLayers {
 		if err := uvm.RemoveVSMB(wl); err != nil {
@@ -387,3 +423,22 @@ func computeV2Layers(vm *uvm.UtilityVM, paths []string) (layers []hcsschema.Laye
 	}
 	return layers, nil
 }
+
+// setScratchQoS applies the SCSI QoS annotations of the container to its
+// scratch disk attached to the utility VM. Process isolated containers have no
+// utility VM so this is a no-op.
+func setScratchQoS(coi *createOptionsInternal) error {
+	if coi.HostingSystem == nil {
+		return nil
+	}
+	qos := oci.ParseAnnotationsSCSIQoS(coi.Spec)
+	if qos == nil {
+		return nil
+	}
+	layerFolders := coi.Spec.Windows.LayerFolders
+	hostPath := filepath.Join(layerFolders[len(layerFolders)-1], "sandbox.vhdx")
+	if err := coi.HostingSystem.UpdateSCSIQoS(hostPath, *qos); err != nil {
+		return fmt.Errorf("failed to set container scratch QoS: %s", err)
+	}
+	return nil
+}
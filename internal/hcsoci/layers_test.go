@@ -0,0 +1,56 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/oci"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func Test_scratchSCSIOptions(t *testing.T) {
+	options := scratchSCSIOptions("/run/scratch", "", false, false)
+	if options.UVMPath != "/run/scratch" || options.Filesystem != "" || options.Encrypted || len(options.MountOptions) != 0 {
+		t.Fatalf("expected a plain scratch mount, got: %+v", options)
+	}
+	options = scratchSCSIOptions("/run/scratch", "xfs", true, false)
+	if options.Filesystem != "xfs" || !options.Encrypted || len(options.MountOptions) != 0 {
+		t.Fatalf("expected an encrypted xfs scratch mount, got: %+v", options)
+	}
+	if err := options.Validate(); err != nil {
+		t.Fatalf("expected valid options, got: %v", err)
+	}
+	options = scratchSCSIOptions("/run/scratch", "", false, true)
+	if options.Encrypted || len(options.MountOptions) != 1 || options.MountOptions[0] != "discard" {
+		t.Fatalf("expected a scratch mount with discard, got: %+v", options)
+	}
+	if err := options.Validate(); err != nil {
+		t.Fatalf("expected valid options, got: %v", err)
+	}
+}
+
+func Test_setLayerRootDigests(t *testing.T) {
+	digest := strings.Repeat("ab", 32)
+	s := &specs.Spec{
+		Windows:     &specs.Windows{LayerFolders: []string{`C:\layer1`, `C:\layer2`, `C:\scratch`}},
+		Annotations: map[string]string{oci.AnnotationContainerLayerRootDigests: digest},
+	}
+	vm := &uvm.UtilityVM{}
+	if err := setLayerRootDigests(vm, s); err == nil {
+		t.Fatal("expected error for a root digest missing for a layer")
+	}
+	s.Annotations[oci.AnnotationContainerLayerRootDigests] = digest + "," + digest
+	if err := setLayerRootDigests(vm, s); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	// The layers are verified against the digests already set.
+	if err := vm.SetLayerRootDigest(`C:\layer2\layer.vhd`, strings.Repeat("cd", 32)); err == nil {
+		t.Fatal("expected the root digest of the layer to be set")
+	}
+	if err := setLayerRootDigests(nil, s); err != nil {
+		t.Fatalf("expected nil error without a utility VM, got: %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// mountBlockDevOption is the OCI mount option that exposes a disk mount to an
+// LCOW container as an unformatted raw block device at the mount destination
+// rather than mounting the filesystem on the disk.
+const mountBlockDevOption = "blockdev"
+
+// mountBlockDev returns `true` if `m` has the block device option and removes
+// the option from `m.Options`.
+func mountBlockDev(m *specs.Mount) bool {
+	blockDev := false
+	var options []string
+	for _, o := range m.Options {
+		if strings.ToLower(o) == mountBlockDevOption {
+			blockDev = true
+			continue
+		}
+		options = append(options, o)
+	}
+	m.Options = options
+	return blockDev
+}
+
+// physicalDiskPath returns the host path of the disk of a physical disk mount
+// with `source`. The source is either a path such as `\\.\PHYSICALDRIVE1` or
+// just the number of the disk.
+func physicalDiskPath(source string) string {
+	if n, err := strconv.ParseUint(source, 10, 32); err == nil {
+		return uvm.PhysicalDiskPath(uint32(n))
+	}
+	return source
+}
@@ -0,0 +1,34 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"testing"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func Test_mountBlockDev(t *testing.T) {
+	m := &specs.Mount{Type: "physical-disk", Options: []string{"ro", "BlockDev"}}
+	if !mountBlockDev(m) {
+		t.Fatal("expected block device option to be found")
+	}
+	if len(m.Options) != 1 || m.Options[0] != "ro" {
+		t.Fatalf("expected block device option to be removed, got: %v", m.Options)
+	}
+	if mountBlockDev(m) {
+		t.Fatal("expected no block device option")
+	}
+}
+
+func Test_physicalDiskPath(t *testing.T) {
+	for _, tc := range []struct{ source, expected string }{
+		{"3", `\\.\PHYSICALDRIVE3`},
+		{`\\.\PHYSICALDRIVE1`, `\\.\PHYSICALDRIVE1`},
+		{"-1", "-1"},
+	} {
+		if p := physicalDiskPath(tc.source); p != tc.expected {
+			t.Fatalf("expected %q for %q, got: %q", tc.expected, tc.source, p)
+		}
+	}
+}
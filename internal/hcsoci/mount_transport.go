@@ -0,0 +1,54 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"fmt"
+	"strings"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// mountTransportOption is the OCI mount option prefix that selects how a mount
+// is shared into a utility VM. For example `transport=scsi` on a bind mount of
+// a VHD attaches the VHD over SCSI rather than sharing the file.
+const mountTransportOption = "transport="
+
+// The transports a mount can be shared into a utility VM with.
+const (
+	mountTransportPlan9 = "plan9"
+	mountTransportVSMB  = "vsmb"
+	mountTransportSCSI  = "scsi"
+)
+
+// mountTransport returns the transport selected by the transport option of `m`
+// and removes the option from `m.Options`. Disk mounts are always attached
+// over SCSI. If a bind mount does not select a transport `def` is returned.
+func mountTransport(m *specs.Mount, def string) (string, error) {
+	transport := ""
+	var options []string
+	for _, o := range m.Options {
+		if strings.HasPrefix(strings.ToLower(o), mountTransportOption) {
+			transport = strings.ToLower(o[len(mountTransportOption):])
+			continue
+		}
+		options = append(options, o)
+	}
+	m.Options = options
+
+	switch m.Type {
+	case "physical-disk", "virtual-disk", "vhd-set":
+		if transport != "" && transport != mountTransportSCSI {
+			return "", fmt.Errorf("invalid transport '%s' for %s mount %+v", transport, m.Type, *m)
+		}
+		return mountTransportSCSI, nil
+	}
+	switch transport {
+	case "":
+		return def, nil
+	case mountTransportPlan9, mountTransportVSMB, mountTransportSCSI:
+		return transport, nil
+	default:
+		return "", fmt.Errorf("invalid transport '%s' for mount %+v", transport, *m)
+	}
+}
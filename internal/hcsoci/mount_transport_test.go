@@ -0,0 +1,56 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"testing"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func Test_mountTransport_Default(t *testing.T) {
+	m := &specs.Mount{Type: "bind", Options: []string{"ro"}}
+	transport, err := mountTransport(m, mountTransportPlan9)
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if transport != mountTransportPlan9 {
+		t.Fatalf("expected '%s', got: '%s'", mountTransportPlan9, transport)
+	}
+}
+
+func Test_mountTransport_Bind(t *testing.T) {
+	m := &specs.Mount{Type: "bind", Options: []string{"ro", "Transport=SCSI"}}
+	transport, err := mountTransport(m, mountTransportPlan9)
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if transport != mountTransportSCSI {
+		t.Fatalf("expected '%s', got: '%s'", mountTransportSCSI, transport)
+	}
+	if len(m.Options) != 1 || m.Options[0] != "ro" {
+		t.Fatalf("expected transport option to be removed, got: %v", m.Options)
+	}
+}
+
+func Test_mountTransport_Invalid(t *testing.T) {
+	m := &specs.Mount{Type: "bind", Options: []string{"transport=nfs"}}
+	if _, err := mountTransport(m, mountTransportPlan9); err == nil {
+		t.Fatal("expected error for unknown transport")
+	}
+}
+
+func Test_mountTransport_Disk(t *testing.T) {
+	m := &specs.Mount{Type: "virtual-disk"}
+	transport, err := mountTransport(m, mountTransportVSMB)
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if transport != mountTransportSCSI {
+		t.Fatalf("expected '%s', got: '%s'", mountTransportSCSI, transport)
+	}
+	m = &specs.Mount{Type: "physical-disk", Options: []string{"transport=plan9"}}
+	if _, err := mountTransport(m, mountTransportVSMB); err == nil {
+		t.Fatal("expected error for plan9 disk mount")
+	}
+}
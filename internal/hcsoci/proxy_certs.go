@@ -0,0 +1,84 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"strings"
+
+	"github.com/Microsoft/hcsshim/internal/oci"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// caCertificatesPath is the path the CA certificates bundle is mounted at in
+// an LCOW container.
+const caCertificatesPath = "/etc/hcsshim/ca-certificates.crt"
+
+// setEnvIfUnset sets `key` to `value` in the environment of `p` unless it is
+// already set. Windows environment keys are matched case insensitively.
+func setEnvIfUnset(p *specs.Process, linux bool, key, value string) {
+	for _, e := range p.Env {
+		k := strings.SplitN(e, "=", 2)[0]
+		if k == key || (!linux && strings.EqualFold(k, key)) {
+			return
+		}
+	}
+	p.Env = append(p.Env, key+"="+value)
+}
+
+// AddEnv sets each `key=value` in `env` in the environment of `p` unless the key
+// is already set. `linux` is `true` if `p` runs in an LCOW container.
+func AddEnv(p *specs.Process, linux bool, env []string) {
+	for _, e := range env {
+		kv := strings.SplitN(e, "=", 2)
+		setEnvIfUnset(p, linux, kv[0], kv[1])
+	}
+}
+
+// proxyEnv returns the proxy environment from the annotations on `spec`. Linux
+// tools commonly only read the lower case proxy variables so for LCOW both
+// cases are returned.
+func proxyEnv(spec *specs.Spec) []string {
+	var env []string
+	for _, e := range oci.ParseAnnotationsProxyEnv(spec) {
+		env = append(env, e)
+		if spec.Linux != nil {
+			kv := strings.SplitN(e, "=", 2)
+			env = append(env, strings.ToLower(kv[0])+"="+kv[1])
+		}
+	}
+	return env
+}
+
+// addProxyEnv sets the proxy environment from the annotations on `spec` in its
+// process.
+func addProxyEnv(spec *specs.Spec) {
+	if spec.Process == nil {
+		return
+	}
+	AddEnv(spec.Process, spec.Linux != nil, proxyEnv(spec))
+}
+
+// ExecEnv returns the environment that the proxy and CA certificate
+// annotations on `spec` set in the init process of the container created from
+// `spec`. It must be set with `AddEnv` in every exec in the container as well.
+func ExecEnv(spec *specs.Spec) []string {
+	env := proxyEnv(spec)
+	if spec.Linux != nil && oci.ParseAnnotationsCACertificates(spec) != "" {
+		env = append(env, "SSL_CERT_FILE="+caCertificatesPath)
+	}
+	return env
+}
+
+// addCACertificates mounts the CA certificates bundle at `hostPath` read only
+// into the LCOW container in `spec` and points `SSL_CERT_FILE` at it.
+func addCACertificates(spec *specs.Spec, hostPath string) {
+	spec.Mounts = append(spec.Mounts, specs.Mount{
+		Destination: caCertificatesPath,
+		Type:        "bind",
+		Source:      hostPath,
+		Options:     []string{"ro"},
+	})
+	if spec.Process != nil {
+		setEnvIfUnset(spec.Process, true, "SSL_CERT_FILE", caCertificatesPath)
+	}
+}
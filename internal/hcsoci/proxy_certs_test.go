@@ -0,0 +1,81 @@
+// +build windows
+
+package hcsoci
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/oci"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func Test_addProxyEnv_LCOW(t *testing.T) {
+	spec := &specs.Spec{
+		Linux:   &specs.Linux{},
+		Process: &specs.Process{Env: []string{"PATH=/bin", "https_proxy=http://mine"}},
+		Annotations: map[string]string{
+			oci.AnnotationContainerHTTPSProxy: "http://proxy:3128",
+		},
+	}
+	addProxyEnv(spec)
+	expected := []string{"PATH=/bin", "https_proxy=http://mine", "HTTPS_PROXY=http://proxy:3128"}
+	if !reflect.DeepEqual(spec.Process.Env, expected) {
+		t.Fatalf("expected %v, got: %v", expected, spec.Process.Env)
+	}
+}
+
+func Test_addProxyEnv_WCOW(t *testing.T) {
+	spec := &specs.Spec{
+		Windows: &specs.Windows{},
+		Process: &specs.Process{Env: []string{"No_Proxy=localhost"}},
+		Annotations: map[string]string{
+			oci.AnnotationContainerHTTPProxy: "http://proxy:3128",
+			oci.AnnotationContainerNoProxy:   ".corp",
+		},
+	}
+	addProxyEnv(spec)
+	expected := []string{"No_Proxy=localhost", "HTTP_PROXY=http://proxy:3128"}
+	if !reflect.DeepEqual(spec.Process.Env, expected) {
+		t.Fatalf("expected %v, got: %v", expected, spec.Process.Env)
+	}
+}
+
+func Test_addCACertificates(t *testing.T) {
+	spec := &specs.Spec{
+		Linux:   &specs.Linux{},
+		Process: &specs.Process{},
+	}
+	addCACertificates(spec, `C:\certs\ca.pem`)
+	if len(spec.Mounts) != 1 ||
+		spec.Mounts[0].Destination != caCertificatesPath ||
+		spec.Mounts[0].Source != `C:\certs\ca.pem` ||
+		!reflect.DeepEqual(spec.Mounts[0].Options, []string{"ro"}) {
+		t.Fatalf("unexpected mounts: %+v", spec.Mounts)
+	}
+	if !reflect.DeepEqual(spec.Process.Env, []string{"SSL_CERT_FILE=" + caCertificatesPath}) {
+		t.Fatalf("unexpected environment: %v", spec.Process.Env)
+	}
+}
+
+func Test_ExecEnv(t *testing.T) {
+	spec := &specs.Spec{
+		Linux: &specs.Linux{},
+		Annotations: map[string]string{
+			oci.AnnotationContainerHTTPProxy:      "http://proxy:3128",
+			oci.AnnotationContainerCACertificates: `C:\certs\ca.pem`,
+		},
+	}
+	env := ExecEnv(spec)
+	expected := []string{"HTTP_PROXY=http://proxy:3128", "http_proxy=http://proxy:3128", "SSL_CERT_FILE=" + caCertificatesPath}
+	if !reflect.DeepEqual(env, expected) {
+		t.Fatalf("expected %v, got: %v", expected, env)
+	}
+
+	p := &specs.Process{Env: []string{"http_proxy=http://mine"}}
+	AddEnv(p, true, env)
+	expected = []string{"http_proxy=http://mine", "HTTP_PROXY=http://proxy:3128", "SSL_CERT_FILE=" + caCertificatesPath}
+	if !reflect.DeepEqual(p.Env, expected) {
+		t.Fatalf("expected %v, got: %v", expected, p.Env)
+	}
+}
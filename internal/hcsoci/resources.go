@@ -54,6 +54,49 @@ type Resources struct {
 	// scsiMounts is an array of the host-paths mounted into a utility VM to
 	// support scsi device passthrough.
 	scsiMounts []string
+
+	// vpciDevices is an array of the host device instance paths assigned to a
+	// utility VM over virtual PCI on behalf of the container.
+	vpciDevices []string
+}
+
+// AttachedDevice is a device or share attached to a utility VM on behalf of a
+// container.
+type AttachedDevice struct {
+	// Type is one of "vsmb", "plan9", "scsi" or "vpci".
+	Type string
+	// Path is the host path for "vsmb" and "scsi", the utility VM path for
+	// "plan9" and the host device instance path for "vpci".
+	Path string
+}
+
+// AttachedDevices returns the devices and shares attached to the utility VM
+// for the container.
+func (r *Resources) AttachedDevices() []AttachedDevice {
+	var devices []AttachedDevice
+	for _, m := range r.vsmbMounts {
+		devices = append(devices, AttachedDevice{Type: "vsmb", Path: m})
+	}
+	for _, m := range r.plan9Mounts {
+		devices = append(devices, AttachedDevice{Type: "plan9", Path: m.UVMPath()})
+	}
+	for _, m := range r.scsiMounts {
+		devices = append(devices, AttachedDevice{Type: "scsi", Path: m})
+	}
+	for _, d := range r.vpciDevices {
+		devices = append(devices, AttachedDevice{Type: "vpci", Path: d})
+	}
+	return devices
+}
+
+// NetworkNamespace returns the network namespace of the container.
+func (r *Resources) NetworkNamespace() string {
+	return r.netNS
+}
+
+// NetworkEndpoints returns the network endpoints used by the container.
+func (r *Resources) NetworkEndpoints() []string {
+	return r.networkEndpoints
 }
 
 // TODO: Method on the resources?
@@ -123,6 +166,14 @@ func ReleaseResources(r *Resources, vm *uvm.UtilityVM, all bool) error {
 			}
 			r.scsiMounts = nil
 		}
+
+		for len(r.vpciDevices) != 0 {
+			device := r.vpciDevices[len(r.vpciDevices)-1]
+			if err := vm.RemoveDevice(device); err != nil {
+				return err
+			}
+			r.vpciDevices = r.vpciDevices[:len(r.vpciDevices)-1]
+		}
 	}
 
 	return nil
@@ -13,6 +13,8 @@ import (
 	"strings"
 
 	"github.com/Microsoft/hcsshim/internal/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/oci"
+	"github.com/Microsoft/hcsshim/internal/uvm"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
 )
@@ -20,12 +22,25 @@ import (
 const rootfsPath = "rootfs"
 const mountPathPrefix = "m"
 
+// LCOWRootfsPathInUVM returns the path of the merged root file system of the
+// LCOW container in its utility VM. Returns "" if the container is not hosted
+// in a utility VM.
+func (r *Resources) LCOWRootfsPathInUVM() string {
+	if r.containerRootInUVM == "" {
+		return ""
+	}
+	return path.Join(r.containerRootInUVM, rootfsPath)
+}
+
 func allocateLinuxResources(coi *createOptionsInternal, resources *Resources) error {
 	if coi.Spec.Root == nil {
 		coi.Spec.Root = &specs.Root{}
 	}
 	if coi.Spec.Windows != nil && len(coi.Spec.Windows.LayerFolders) > 0 {
 		logrus.Debug("hcsshim::allocateLinuxResources mounting storage")
+		if err := setLayerRootDigests(coi.HostingSystem, coi.Spec); err != nil {
+			return err
+		}
 		mcl, err := MountContainerLayers(coi.Spec.Windows.LayerFolders, resources.containerRootInUVM, coi.HostingSystem)
 		if err != nil {
 			return fmt.Errorf("failed to mount container storage: %s", err)
@@ -36,12 +51,15 @@ func allocateLinuxResources(coi *createOptionsInternal, resources *Resources) er
 			coi.Spec.Root.Path = mcl.(guestrequest.CombinedLayers).ContainerRootPath // v2 Xenon LCOW
 		}
 		resources.layers = coi.Spec.Windows.LayerFolders
+		if err := setScratchQoS(coi); err != nil {
+			return err
+		}
 	} else if coi.Spec.Root.Path != "" {
 		// This is the "Plan 9" root filesystem.
 		// TODO: We need a test for this. Ask @jstarks how you can even lay this out on Windows.
 		hostPath := coi.Spec.Root.Path
 		uvmPathForContainersFileSystem := path.Join(resources.containerRootInUVM, rootfsPath)
-		share, err := coi.HostingSystem.AddPlan9(hostPath, uvmPathForContainersFileSystem, coi.Spec.Root.Readonly, false, nil)
+		share, err := coi.HostingSystem.AddPlan9WithOptions(hostPath, uvmPathForContainersFileSystem, coi.Spec.Root.Readonly, false, nil, oci.ParseAnnotationsPlan9Options(coi.Spec))
 		if err != nil {
 			return fmt.Errorf("adding plan9 root: %s", err)
 		}
@@ -56,6 +74,7 @@ func allocateLinuxResources(coi *createOptionsInternal, resources *Resources) er
 		case "bind":
 		case "physical-disk":
 		case "virtual-disk":
+		case "vhd-set":
 		default:
 			// Unknown mount type
 			continue
@@ -68,6 +87,15 @@ func allocateLinuxResources(coi *createOptionsInternal, resources *Resources) er
 			hostPath := mount.Source
 			uvmPathForShare := path.Join(resources.containerRootInUVM, mountPathPrefix+strconv.Itoa(i))
 			uvmPathForFile := uvmPathForShare
+			transport, err := mountTransport(&coi.Spec.Mounts[i], mountTransportPlan9)
+			if err != nil {
+				return err
+			}
+			blockDev := mountBlockDev(&coi.Spec.Mounts[i])
+			if blockDev && transport != mountTransportSCSI {
+				return fmt.Errorf("block device option requires a disk mount %+v", mount)
+			}
+			mount = coi.Spec.Mounts[i]
 
 			readOnly := false
 			for _, o := range mount.Options {
@@ -79,41 +107,54 @@ func allocateLinuxResources(coi *createOptionsInternal, resources *Resources) er
 			log := logrus.WithField("mount", fmt.Sprintf("%+v", mount))
 			if mount.Type == "physical-disk" {
 				log.Debug("hcsshim::allocateLinuxResources Hot-adding SCSI physical disk for OCI mount")
-				_, _, err := coi.HostingSystem.AddSCSIPhysicalDisk(hostPath, uvmPathForShare, readOnly)
+				hostPath = physicalDiskPath(hostPath)
+				_, _, err := coi.HostingSystem.AddSCSIPhysicalDisk(hostPath, &uvm.SCSIOptions{UVMPath: uvmPathForShare, Owner: coi.actualID, ReadOnly: readOnly, BlockDev: blockDev})
 				if err != nil {
 					return fmt.Errorf("adding SCSI physical disk mount %+v: %s", mount, err)
 				}
+				if qos := oci.ParseAnnotationsSCSIQoS(coi.Spec); qos != nil {
+					if err := coi.HostingSystem.UpdateSCSIQoS(hostPath, *qos); err != nil {
+						return fmt.Errorf("setting SCSI physical disk QoS %+v: %s", mount, err)
+					}
+				}
 				resources.scsiMounts = append(resources.scsiMounts, hostPath)
-				coi.Spec.Mounts[i].Type = "none"
-			} else if mount.Type == "virtual-disk" {
+				coi.Spec.Mounts[i].Type = scsiMountType(blockDev)
+			} else if mount.Type == "vhd-set" {
+				log.Debug("hcsshim::allocateLinuxResources Hot-adding SCSI VHD set for OCI mount")
+				_, _, err := coi.HostingSystem.AddSCSIVHDSet(hostPath, &uvm.SCSIOptions{UVMPath: uvmPathForShare, Owner: coi.actualID, ReadOnly: readOnly, QoS: oci.ParseAnnotationsSCSIQoS(coi.Spec), BlockDev: blockDev})
+				if err != nil {
+					return fmt.Errorf("adding SCSI VHD set mount %+v: %s", mount, err)
+				}
+				resources.scsiMounts = append(resources.scsiMounts, hostPath)
+				coi.Spec.Mounts[i].Type = scsiMountType(blockDev)
+			} else if transport == mountTransportSCSI {
 				log.Debug("hcsshim::allocateLinuxResources Hot-adding SCSI virtual disk for OCI mount")
-				_, _, err := coi.HostingSystem.AddSCSI(hostPath, uvmPathForShare, readOnly)
+				_, _, err := coi.HostingSystem.AddSCSI(hostPath, &uvm.SCSIOptions{UVMPath: uvmPathForShare, Owner: coi.actualID, ReadOnly: readOnly, QoS: oci.ParseAnnotationsSCSIQoS(coi.Spec), BlockDev: blockDev})
 				if err != nil {
 					return fmt.Errorf("adding SCSI virtual disk mount %+v: %s", mount, err)
 				}
 				resources.scsiMounts = append(resources.scsiMounts, hostPath)
-				coi.Spec.Mounts[i].Type = "none"
+				coi.Spec.Mounts[i].Type = scsiMountType(blockDev)
+			} else if transport == mountTransportVSMB {
+				return fmt.Errorf("vsmb is not supported for LCOW mount %+v", mount)
 			} else {
 				st, err := os.Stat(hostPath)
 				if err != nil {
 					return fmt.Errorf("could not open bind mount target: %s", err)
 				}
-				restrictAccess := false
-				var allowedNames []string
-				if !st.IsDir() {
-					// Map the containing directory in, but restrict the share to a single
-					// file.
-					var fileName string
-					hostPath, fileName = path.Split(hostPath)
-					allowedNames = append(allowedNames, fileName)
-					restrictAccess = true
-					uvmPathForFile = path.Join(uvmPathForShare, fileName)
-				}
 				log.Debug("hcsshim::allocateLinuxResources Hot-adding Plan9 for OCI mount")
-				share, err := coi.HostingSystem.AddPlan9(hostPath, uvmPathForShare, readOnly, restrictAccess, allowedNames)
+				var share *uvm.Plan9Share
+				if st.IsDir() {
+					share, err = coi.HostingSystem.AddPlan9WithOptions(hostPath, uvmPathForShare, readOnly, false, nil, oci.ParseAnnotationsPlan9Options(coi.Spec))
+				} else {
+					// Only the file is visible in the share so the rest of its
+					// directory is not exposed to the container.
+					share, err = coi.HostingSystem.AddPlan9File(hostPath, uvmPathForShare, readOnly, oci.ParseAnnotationsPlan9Options(coi.Spec))
+				}
 				if err != nil {
 					return fmt.Errorf("adding plan9 mount %+v: %s", mount, err)
 				}
+				uvmPathForFile = share.UVMFilePath()
 				resources.plan9Mounts = append(resources.plan9Mounts, share)
 			}
 			coi.Spec.Mounts[i].Source = uvmPathForFile
@@ -122,3 +163,13 @@ func allocateLinuxResources(coi *createOptionsInternal, resources *Resources) er
 
 	return nil
 }
+
+// scsiMountType returns the type of the container mount of a disk attached to
+// the utility VM over SCSI. A raw block device is bind mounted into the
+// container as it is a device node rather than a filesystem.
+func scsiMountType(blockDev bool) string {
+	if blockDev {
+		return "bind"
+	}
+	return "none"
+}
@@ -11,13 +11,26 @@ import (
 	"strings"
 
 	"github.com/Microsoft/hcsshim/internal/guestrequest"
-	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/Microsoft/hcsshim/internal/oci"
 	"github.com/Microsoft/hcsshim/internal/schemaversion"
+	"github.com/Microsoft/hcsshim/internal/uvm"
 	"github.com/Microsoft/hcsshim/internal/wclayer"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
 )
 
+// HostResources returns the resources held on the host by the process
+// isolated WCOW container `s` that was created by an earlier caller which no
+// longer tracks them: its mounted layers. A network namespace created for the
+// container is not known so it is not released.
+func HostResources(s *specs.Spec) *Resources {
+	r := &Resources{}
+	if s.Windows != nil && (s.Root == nil || s.Root.Path == "") {
+		r.layers = s.Windows.LayerFolders
+	}
+	return r
+}
+
 func allocateWindowsResources(coi *createOptionsInternal, resources *Resources) error {
 	if coi.Spec == nil || coi.Spec.Windows == nil || coi.Spec.Windows.LayerFolders == nil {
 		return fmt.Errorf("field 'Spec.Windows.Layerfolders' is not populated")
@@ -60,6 +73,9 @@ func allocateWindowsResources(coi *createOptionsInternal, resources *Resources)
 			coi.Spec.Root.Path = mcl.(guestrequest.CombinedLayers).ContainerRootPath // v2 Xenon WCOW
 		}
 		resources.layers = coi.Spec.Windows.LayerFolders
+		if err := setScratchQoS(coi); err != nil {
+			return err
+		}
 	}
 
 	// Validate each of the mounts. If this is a V2 Xenon, we have to add them as
@@ -73,12 +89,21 @@ func allocateWindowsResources(coi *createOptionsInternal, resources *Resources)
 		case "":
 		case "physical-disk":
 		case "virtual-disk":
+		case "vhd-set":
 		default:
 			return fmt.Errorf("invalid OCI spec - Type '%s' not supported", mount.Type)
 		}
 
 		if coi.HostingSystem != nil && schemaversion.IsV21(coi.actualSchemaVersion) {
 			uvmPath := fmt.Sprintf("C:\\%s\\%d", coi.actualID, i)
+			transport, err := mountTransport(&coi.Spec.Mounts[i], mountTransportVSMB)
+			if err != nil {
+				return err
+			}
+			if mountBlockDev(&coi.Spec.Mounts[i]) {
+				return fmt.Errorf("block device mounts are not supported for WCOW mount %+v", mount)
+			}
+			mount = coi.Spec.Mounts[i]
 
 			readOnly := false
 			for _, o := range mount.Options {
@@ -90,31 +115,40 @@ func allocateWindowsResources(coi *createOptionsInternal, resources *Resources)
 			log := logrus.WithField("mount", fmt.Sprintf("%+v", mount))
 			if mount.Type == "physical-disk" {
 				log.Debug("hcsshim::allocateWindowsResources Hot-adding SCSI physical disk for OCI mount")
-				_, _, err := coi.HostingSystem.AddSCSIPhysicalDisk(mount.Source, uvmPath, readOnly)
+				coi.Spec.Mounts[i].Source = physicalDiskPath(mount.Source)
+				mount = coi.Spec.Mounts[i]
+				_, _, err := coi.HostingSystem.AddSCSIPhysicalDisk(mount.Source, &uvm.SCSIOptions{UVMPath: uvmPath, Owner: coi.actualID, ReadOnly: readOnly})
 				if err != nil {
 					return fmt.Errorf("adding SCSI physical disk mount %+v: %s", mount, err)
 				}
+				if qos := oci.ParseAnnotationsSCSIQoS(coi.Spec); qos != nil {
+					if err := coi.HostingSystem.UpdateSCSIQoS(mount.Source, *qos); err != nil {
+						return fmt.Errorf("setting SCSI physical disk QoS %+v: %s", mount, err)
+					}
+				}
 				coi.Spec.Mounts[i].Type = ""
 				resources.scsiMounts = append(resources.scsiMounts, mount.Source)
-			} else if mount.Type == "virtual-disk" {
+			} else if mount.Type == "vhd-set" {
+				log.Debug("hcsshim::allocateWindowsResources Hot-adding SCSI VHD set for OCI mount")
+				_, _, err := coi.HostingSystem.AddSCSIVHDSet(mount.Source, &uvm.SCSIOptions{UVMPath: uvmPath, Owner: coi.actualID, ReadOnly: readOnly, QoS: oci.ParseAnnotationsSCSIQoS(coi.Spec)})
+				if err != nil {
+					return fmt.Errorf("adding SCSI VHD set mount %+v: %s", mount, err)
+				}
+				coi.Spec.Mounts[i].Type = ""
+				resources.scsiMounts = append(resources.scsiMounts, mount.Source)
+			} else if transport == mountTransportSCSI {
 				log.Debug("hcsshim::allocateWindowsResources Hot-adding SCSI virtual disk for OCI mount")
-				_, _, err := coi.HostingSystem.AddSCSI(mount.Source, uvmPath, readOnly)
+				_, _, err := coi.HostingSystem.AddSCSI(mount.Source, &uvm.SCSIOptions{UVMPath: uvmPath, Owner: coi.actualID, ReadOnly: readOnly, QoS: oci.ParseAnnotationsSCSIQoS(coi.Spec)})
 				if err != nil {
 					return fmt.Errorf("adding SCSI virtual disk mount %+v: %s", mount, err)
 				}
 				coi.Spec.Mounts[i].Type = ""
 				resources.scsiMounts = append(resources.scsiMounts, mount.Source)
+			} else if transport == mountTransportPlan9 {
+				return fmt.Errorf("plan9 is not supported for WCOW mount %+v", mount)
 			} else {
 				log.Debug("hcsshim::allocateWindowsResources Hot-adding VSMB share for OCI mount")
-				options := &hcsschema.VirtualSmbShareOptions{}
-				if readOnly {
-					options.ReadOnly = true
-					options.CacheIo = true
-					options.ShareRead = true
-					options.ForceLevelIIOplocks = true
-					break
-				}
-
+				options := oci.ParseAnnotationsVSMBOptions(coi.Spec, readOnly)
 				err := coi.HostingSystem.AddVSMB(mount.Source, "", options)
 				if err != nil {
 					return fmt.Errorf("failed to add VSMB share to utility VM for mount %+v: %s", mount, err)
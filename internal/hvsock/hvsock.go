@@ -0,0 +1,177 @@
+// Package hvsock connects to HvSocket services listening in a Hyper-V VM.
+//
+// go-winio only supports listening on HvSocket services so connecting is
+// implemented here with synchronous socket calls.
+package hvsock
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+	"unsafe"
+
+	winio "github.com/Microsoft/go-winio"
+	"github.com/Microsoft/go-winio/pkg/guid"
+	"golang.org/x/sys/windows"
+)
+
+//go:generate go run ../../mksyscall_windows.go -output zsyscall_windows.go hvsock.go
+
+//sys connect(s windows.Handle, name unsafe.Pointer, namelen int32) (err error) [failretval==socketError] = ws2_32.connect
+
+const (
+	socketError = ^uintptr(0)
+
+	afHvSock      = 34 // AF_HYPERV
+	hvProtocolRaw = 1  // HV_PROTOCOL_RAW
+)
+
+// errDeadlinesNotSupported is returned when setting a deadline on an HvSocket
+// connection.
+var errDeadlinesNotSupported = errors.New("hvsock: deadlines are not supported")
+
+// rawAddr is a SOCKADDR_HV.
+type rawAddr struct {
+	Family    uint16
+	_         uint16
+	VMID      guid.GUID
+	ServiceID guid.GUID
+}
+
+func newRawAddr(addr *winio.HvsockAddr) rawAddr {
+	return rawAddr{
+		Family:    afHvSock,
+		VMID:      addr.VMID,
+		ServiceID: addr.ServiceID,
+	}
+}
+
+// Conn is a connection to an HvSocket service.
+type Conn struct {
+	s      windows.Handle
+	remote winio.HvsockAddr
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+var _ net.Conn = &Conn{}
+
+// Dial connects to the HvSocket service at `addr`. If `ctx` is done before the
+// connection is made the attempt is cancelled.
+func Dial(ctx context.Context, addr *winio.HvsockAddr) (_ *Conn, err error) {
+	s, err := windows.Socket(afHvSock, windows.SOCK_STREAM, hvProtocolRaw)
+	if err != nil {
+		return nil, &net.OpError{Op: "socket", Net: "hvsock", Addr: addr, Err: err}
+	}
+	raw := newRawAddr(addr)
+	ch := make(chan error, 1)
+	go func() {
+		ch <- connect(s, unsafe.Pointer(&raw), int32(unsafe.Sizeof(raw)))
+	}()
+	select {
+	case err = <-ch:
+	case <-ctx.Done():
+		// Closing the socket cancels the pending connect.
+		windows.Closesocket(s)
+		<-ch
+		return nil, &net.OpError{Op: "dial", Net: "hvsock", Addr: addr, Err: ctx.Err()}
+	}
+	if err != nil {
+		windows.Closesocket(s)
+		return nil, &net.OpError{Op: "dial", Net: "hvsock", Addr: addr, Err: err}
+	}
+	return &Conn{s: s, remote: *addr}, nil
+}
+
+func (c *Conn) opErr(op string, err error) error {
+	return &net.OpError{Op: op, Net: "hvsock", Addr: &c.remote, Err: err}
+}
+
+// Read reads from the connection. It returns `io.EOF` once the service has
+// closed its side of the connection.
+func (c *Conn) Read(b []byte) (int, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+	buf := windows.WSABuf{Len: uint32(len(b)), Buf: &b[0]}
+	var n, flags uint32
+	if err := windows.WSARecv(c.s, &buf, 1, &n, &flags, nil, nil); err != nil {
+		return 0, c.opErr("read", err)
+	}
+	if n == 0 {
+		return 0, io.EOF
+	}
+	return int(n), nil
+}
+
+// Write writes `b` to the connection.
+func (c *Conn) Write(b []byte) (int, error) {
+	t := 0
+	for len(b) > 0 {
+		buf := windows.WSABuf{Len: uint32(len(b)), Buf: &b[0]}
+		var n uint32
+		if err := windows.WSASend(c.s, &buf, 1, &n, 0, nil, nil); err != nil {
+			return t, c.opErr("write", err)
+		}
+		t += int(n)
+		b = b[n:]
+	}
+	return t, nil
+}
+
+// Close closes the connection, unblocking any pending reads and writes.
+func (c *Conn) Close() error {
+	c.closeOnce.Do(func() {
+		if err := windows.Closesocket(c.s); err != nil {
+			c.closeErr = c.opErr("close", err)
+		}
+	})
+	return c.closeErr
+}
+
+// CloseRead shuts down the read end of the connection.
+func (c *Conn) CloseRead() error {
+	if err := windows.Shutdown(c.s, windows.SHUT_RD); err != nil {
+		return c.opErr("close", err)
+	}
+	return nil
+}
+
+// CloseWrite shuts down the write end of the connection, signalling EOF to
+// the service.
+func (c *Conn) CloseWrite() error {
+	if err := windows.Shutdown(c.s, windows.SHUT_WR); err != nil {
+		return c.opErr("close", err)
+	}
+	return nil
+}
+
+// LocalAddr returns the local address of the connection. The VM and service
+// of the local side are not known.
+func (c *Conn) LocalAddr() net.Addr {
+	return &winio.HvsockAddr{}
+}
+
+// RemoteAddr returns the address of the service.
+func (c *Conn) RemoteAddr() net.Addr {
+	return &c.remote
+}
+
+// SetDeadline is not supported.
+func (c *Conn) SetDeadline(t time.Time) error {
+	return errDeadlinesNotSupported
+}
+
+// SetReadDeadline is not supported.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return errDeadlinesNotSupported
+}
+
+// SetWriteDeadline is not supported.
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	return errDeadlinesNotSupported
+}
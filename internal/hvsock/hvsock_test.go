@@ -0,0 +1,25 @@
+package hvsock
+
+import (
+	"testing"
+	"unsafe"
+
+	winio "github.com/Microsoft/go-winio"
+	"github.com/Microsoft/go-winio/pkg/guid"
+)
+
+func Test_newRawAddr(t *testing.T) {
+	vmID, err := guid.NewV4()
+	if err != nil {
+		t.Fatal(err)
+	}
+	serviceID := winio.VsockServiceID(109)
+	raw := newRawAddr(&winio.HvsockAddr{VMID: vmID, ServiceID: serviceID})
+	// SOCKADDR_HV is a family, a reserved field and two GUIDs.
+	if size := unsafe.Sizeof(raw); size != 36 {
+		t.Fatalf("expected a 36 byte address, got %d", size)
+	}
+	if raw.Family != afHvSock || raw.VMID != vmID || raw.ServiceID != serviceID {
+		t.Fatalf("unexpected address: %+v", raw)
+	}
+}
@@ -0,0 +1,339 @@
+// Package jobobject provides access to the job object of a Windows process
+// isolated container, and to job objects of processes within it, to apply
+// resource limits to them.
+package jobobject
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf16"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+//go:generate go run ../../mksyscall_windows.go -output zsyscall_windows.go jobobject.go
+
+//sys createJobObject(sa *windows.SecurityAttributes, name *uint16) (handle windows.Handle, err error) = kernel32.CreateJobObjectW
+//sys ntOpenJobObject(handle *windows.Handle, desiredAccess uint32, oa *objectAttributes) (status uint32) = ntdll.NtOpenJobObject
+//sys ntSetInformationJobObject(job windows.Handle, infoClass uint32, info uintptr, length uint32) (status uint32) = ntdll.NtSetInformationJobObject
+//sys rtlNtStatusToDosError(status uint32) (winerr error) = ntdll.RtlNtStatusToDosErrorNoTeb
+//sys assignProcessToJobObject(job windows.Handle, process windows.Handle) (err error) = kernel32.AssignProcessToJobObject
+//sys queryInformationJobObject(job windows.Handle, infoClass uint32, info uintptr, length uint32, returnLength *uint32) (err error) = kernel32.QueryInformationJobObject
+//sys setInformationJobObject(job windows.Handle, infoClass uint32, info uintptr, length uint32) (err error) = kernel32.SetInformationJobObject
+//sys setIoRateControlInformationJobObject(job windows.Handle, info *jobObjectIoRateControlInformation) (ret uint32, err error) = kernel32.SetIoRateControlInformationJobObject
+
+const (
+	jobObjectQuery         = 0x0004
+	jobObjectSetAttributes = 0x0010
+
+	jobObjectExtendedLimitInformationClass  = 9
+	jobObjectCPURateControlInformationClass = 15
+	jobObjectFreezeInformationClass         = 18
+
+	jobObjectLimitWorkingSet    = 0x00000001
+	jobObjectLimitActiveProcess = 0x00000008
+	jobObjectLimitJobMemory     = 0x00000200
+
+	processSetQuota  = 0x0100
+	processTerminate = 0x0001
+
+	jobObjectCPURateControlEnable      = 0x1
+	jobObjectCPURateControlWeightBased = 0x2
+	jobObjectCPURateControlHardCap     = 0x4
+
+	jobObjectIoRateControlEnable = 0x1
+
+	jobObjectFreezeOperation = 0x1
+
+	statusNotImplemented    = 0xC0000002
+	statusInvalidInfoClass  = 0xC0000003
+	statusNotSupported      = 0xC00000BB
+	statusObjectNameInvalid = 0xC0000033
+
+	// minWorkingSet is the minimum working set of each process in the job
+	// when a maximum working set is applied. Windows requires both.
+	minWorkingSet = 1024 * 1024
+)
+
+// CPURateMax is the CPU rate of a job that may use all processors.
+const CPURateMax = 10000
+
+// ErrNotSupported is returned by `Freeze` and `Thaw` if the operating system
+// rejects freezing job objects.
+var ErrNotSupported = errors.New("job object freeze is not supported")
+
+type objectAttributes struct {
+	Length             uintptr
+	RootDirectory      uintptr
+	ObjectName         *unicodeString
+	Attributes         uintptr
+	SecurityDescriptor uintptr
+	SecurityQoS        uintptr
+}
+
+type unicodeString struct {
+	Length        uint16
+	MaximumLength uint16
+	Buffer        *uint16
+}
+
+type jobObjectBasicLimitInformation struct {
+	PerProcessUserTimeLimit int64
+	PerJobUserTimeLimit     int64
+	LimitFlags              uint32
+	MinimumWorkingSetSize   uintptr
+	MaximumWorkingSetSize   uintptr
+	ActiveProcessLimit      uint32
+	Affinity                uintptr
+	PriorityClass           uint32
+	SchedulingClass         uint32
+}
+
+type ioCounters struct {
+	ReadOperationCount  uint64
+	WriteOperationCount uint64
+	OtherOperationCount uint64
+	ReadTransferCount   uint64
+	WriteTransferCount  uint64
+	OtherTransferCount  uint64
+}
+
+type jobObjectExtendedLimitInformation struct {
+	BasicLimitInformation jobObjectBasicLimitInformation
+	IoInfo                ioCounters
+	ProcessMemoryLimit    uintptr
+	JobMemoryLimit        uintptr
+	PeakProcessMemoryUsed uintptr
+	PeakJobMemoryUsed     uintptr
+}
+
+type jobObjectCPURateControlInformation struct {
+	ControlFlags uint32
+	Value        uint32
+}
+
+type jobObjectIoRateControlInformation struct {
+	MaxIops         int64
+	MaxBandwidth    int64
+	ReservationIops int64
+	VolumeName      *uint16
+	BaseIoSize      uint32
+	ControlFlags    uint32
+}
+
+type jobObjectWakeFilter struct {
+	HighEdgeFilter uint32
+	LowEdgeFilter  uint32
+}
+
+type jobObjectFreezeInformation struct {
+	Flags      uint32
+	Freeze     uint8
+	Swap       uint8
+	Reserved0  [2]uint8
+	WakeFilter jobObjectWakeFilter
+}
+
+// Limits are the resource limits of a job. A zero value leaves the limit
+// unchanged.
+type Limits struct {
+	// CPURate is the hard cap of CPU time the job may use in 1/100ths of a
+	// percent of all processors. It MUST be in the range 1 to `CPURateMax`.
+	CPURate uint32
+	// CPUWeight is the relative weight of the job in the range 1 to 9. It is
+	// ignored if `CPURate` is set.
+	CPUWeight uint32
+	// MaxWorkingSet is the maximum working set in bytes of each process in
+	// the job.
+	MaxWorkingSet uint64
+	// MaxCommit is the maximum memory in bytes committed by all processes in
+	// the job.
+	MaxCommit uint64
+	// MaxProcesses is the maximum number of active processes in the job.
+	MaxProcesses uint32
+	// MaxIops is the maximum number of IO operations per second across all
+	// volumes.
+	MaxIops int64
+	// MaxBandwidth is the maximum IO bandwidth in bytes per second across
+	// all volumes.
+	MaxBandwidth int64
+}
+
+// JobObject is an open handle to a job object.
+type JobObject struct {
+	name   string
+	handle windows.Handle
+}
+
+// SiloName returns the name of the job object of the process isolated
+// container with `id`. It is a path in the object manager namespace rather
+// than a name under `BaseNamedObjects`, so it is opened with `Open`.
+func SiloName(id string) string {
+	return `\Container_` + id
+}
+
+// Create creates a new unnamed job object. The job object is destroyed once
+// its handle is closed and all of its processes have exited.
+func Create() (*JobObject, error) {
+	h, err := createJobObject(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job object: %s", err)
+	}
+	return &JobObject{handle: h}, nil
+}
+
+// Open opens the existing job object `name` to query and set its limits.
+// `name` is the full path of the job object in the object manager namespace,
+// such as the one returned by `SiloName`.
+func Open(name string) (*JobObject, error) {
+	n := utf16.Encode([]rune(name))
+	if len(n) == 0 || len(n) > 32767 {
+		return nil, fmt.Errorf("failed to open job object '%s': %s", name, rtlNtStatusToDosError(statusObjectNameInvalid))
+	}
+	us := unicodeString{
+		Length:        uint16(len(n) * 2),
+		MaximumLength: uint16(len(n) * 2),
+		Buffer:        &n[0],
+	}
+	oa := objectAttributes{ObjectName: &us}
+	oa.Length = unsafe.Sizeof(oa)
+	var h windows.Handle
+	if status := ntOpenJobObject(&h, jobObjectQuery|jobObjectSetAttributes, &oa); status != 0 {
+		return nil, fmt.Errorf("failed to open job object '%s': %s", name, rtlNtStatusToDosError(status))
+	}
+	return &JobObject{name: name, handle: h}, nil
+}
+
+// Close closes the handle to the job object. The job object itself is not
+// affected.
+func (j *JobObject) Close() error {
+	return windows.CloseHandle(j.handle)
+}
+
+// Assign assigns the process `pid` to the job object. Child processes
+// created by the process after this call are also in the job object.
+func (j *JobObject) Assign(pid uint32) error {
+	p, err := windows.OpenProcess(processSetQuota|processTerminate, false, pid)
+	if err != nil {
+		return fmt.Errorf("failed to open process %d: %s", pid, err)
+	}
+	defer windows.CloseHandle(p)
+	if err := assignProcessToJobObject(j.handle, p); err != nil {
+		return fmt.Errorf("failed to assign process %d to job object '%s': %s", pid, j.name, err)
+	}
+	return nil
+}
+
+// SetLimits applies all non zero limits in `l` to the job object.
+func (j *JobObject) SetLimits(l Limits) error {
+	if l.CPURate != 0 || l.CPUWeight != 0 {
+		if err := j.setCPURate(l.CPURate, l.CPUWeight); err != nil {
+			return err
+		}
+	}
+	if l.MaxWorkingSet != 0 || l.MaxCommit != 0 || l.MaxProcesses != 0 {
+		if err := j.setExtendedLimits(l.MaxWorkingSet, l.MaxCommit, l.MaxProcesses); err != nil {
+			return err
+		}
+	}
+	if l.MaxIops != 0 || l.MaxBandwidth != 0 {
+		if err := j.setIORate(l.MaxIops, l.MaxBandwidth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (j *JobObject) setCPURate(rate, weight uint32) error {
+	info := jobObjectCPURateControlInformation{ControlFlags: jobObjectCPURateControlEnable}
+	if rate != 0 {
+		if rate > CPURateMax {
+			return fmt.Errorf("cpu rate %d exceeds maximum %d", rate, CPURateMax)
+		}
+		info.ControlFlags |= jobObjectCPURateControlHardCap
+		info.Value = rate
+	} else {
+		if weight > 9 {
+			return fmt.Errorf("cpu weight %d exceeds maximum 9", weight)
+		}
+		info.ControlFlags |= jobObjectCPURateControlWeightBased
+		info.Value = weight
+	}
+	if err := setInformationJobObject(j.handle, jobObjectCPURateControlInformationClass, uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info))); err != nil {
+		return fmt.Errorf("failed to set cpu rate on job object '%s': %s", j.name, err)
+	}
+	return nil
+}
+
+// setExtendedLimits applies the non zero limits to the job object. The
+// extended limits are set together so the current limits are queried first to
+// leave the others unchanged.
+func (j *JobObject) setExtendedLimits(maxWorkingSet, maxCommit uint64, maxProcesses uint32) error {
+	var info jobObjectExtendedLimitInformation
+	if err := queryInformationJobObject(j.handle, jobObjectExtendedLimitInformationClass, uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info)), nil); err != nil {
+		return fmt.Errorf("failed to query limits of job object '%s': %s", j.name, err)
+	}
+	if maxWorkingSet != 0 {
+		min := uint64(minWorkingSet)
+		if maxWorkingSet < min {
+			min = maxWorkingSet
+		}
+		info.BasicLimitInformation.LimitFlags |= jobObjectLimitWorkingSet
+		info.BasicLimitInformation.MinimumWorkingSetSize = uintptr(min)
+		info.BasicLimitInformation.MaximumWorkingSetSize = uintptr(maxWorkingSet)
+	}
+	if maxCommit != 0 {
+		info.BasicLimitInformation.LimitFlags |= jobObjectLimitJobMemory
+		info.JobMemoryLimit = uintptr(maxCommit)
+	}
+	if maxProcesses != 0 {
+		info.BasicLimitInformation.LimitFlags |= jobObjectLimitActiveProcess
+		info.BasicLimitInformation.ActiveProcessLimit = maxProcesses
+	}
+	if err := setInformationJobObject(j.handle, jobObjectExtendedLimitInformationClass, uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info))); err != nil {
+		return fmt.Errorf("failed to set limits on job object '%s': %s", j.name, err)
+	}
+	return nil
+}
+
+func (j *JobObject) setIORate(maxIops, maxBandwidth int64) error {
+	info := jobObjectIoRateControlInformation{
+		MaxIops:      maxIops,
+		MaxBandwidth: maxBandwidth,
+		ControlFlags: jobObjectIoRateControlEnable,
+	}
+	if _, err := setIoRateControlInformationJobObject(j.handle, &info); err != nil {
+		return fmt.Errorf("failed to set io rate on job object '%s': %s", j.name, err)
+	}
+	return nil
+}
+
+// Freeze suspends all threads of all processes in the job object. Processes
+// started in the job while it is frozen are also suspended. `ErrNotSupported`
+// is returned if the operating system does not support it.
+func (j *JobObject) Freeze() error {
+	return j.setFreeze(true)
+}
+
+// Thaw resumes all threads of the job object suspended by `Freeze`.
+func (j *JobObject) Thaw() error {
+	return j.setFreeze(false)
+}
+
+// setFreeze freezes or thaws the job object. Freezing is only available
+// through the native API.
+func (j *JobObject) setFreeze(freeze bool) error {
+	info := jobObjectFreezeInformation{Flags: jobObjectFreezeOperation}
+	if freeze {
+		info.Freeze = 1
+	}
+	switch status := ntSetInformationJobObject(j.handle, jobObjectFreezeInformationClass, uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info))); status {
+	case 0:
+		return nil
+	case statusNotImplemented, statusInvalidInfoClass, statusNotSupported:
+		return ErrNotSupported
+	default:
+		return fmt.Errorf("failed to set freeze=%t on job object '%s': %s", freeze, j.name, rtlNtStatusToDosError(status))
+	}
+}
@@ -0,0 +1,50 @@
+package lcow
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/Microsoft/hcsshim/internal/hcsoci"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/sirupsen/logrus"
+)
+
+// exportRootfsArgs returns the command line of the process in the utility VM
+// that writes the directory `rootfsPath` to its stdout as a tar stream.
+func exportRootfsArgs(rootfsPath string) ([]string, error) {
+	if !path.IsAbs(rootfsPath) {
+		return nil, fmt.Errorf("root file system path '%s' must be absolute", rootfsPath)
+	}
+	return []string{"tar", "-c", "-f", "-", "-C", rootfsPath, "."}, nil
+}
+
+// ExportRootfs writes the merged root file system of a running container at
+// `rootfsPath` in the utility VM to `w` as a tar stream. The root file system
+// is read as it is so files the container changes during the export may be
+// captured part way through.
+func ExportRootfs(ctx context.Context, lcowUVM *uvm.UtilityVM, rootfsPath string, w io.Writer) error {
+	if lcowUVM == nil {
+		return errors.New("no uvm")
+	}
+	if lcowUVM.OS() != "linux" {
+		return errors.New("lcow::ExportRootfs requires a linux utility VM to operate")
+	}
+	args, err := exportRootfsArgs(rootfsPath)
+	if err != nil {
+		return err
+	}
+
+	logrus.WithField("rootfs", rootfsPath).Debug("lcow::ExportRootfs")
+	cmd := hcsoci.CommandContext(ctx, lcowUVM, args[0], args[1:]...)
+	var stderr bytes.Buffer
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to export root file system %s: %s%s", rootfsPath, err, formatStderr(&stderr))
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package lcow
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_exportRootfsArgs(t *testing.T) {
+	args, err := exportRootfsArgs("/run/gcs/c/abc/rootfs")
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	expected := []string{"tar", "-c", "-f", "-", "-C", "/run/gcs/c/abc/rootfs", "."}
+	if !reflect.DeepEqual(args, expected) {
+		t.Fatalf("expected %v, got: %v", expected, args)
+	}
+}
+
+func Test_exportRootfsArgs_Relative(t *testing.T) {
+	if _, err := exportRootfsArgs("rootfs"); err == nil {
+		t.Fatal("expected error for a relative root file system path")
+	}
+}
@@ -0,0 +1,36 @@
+package lcow
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Microsoft/hcsshim/ext4/tar2ext4"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateLayerVHD converts the OCI layer tar stream `r` into an ext4 formatted
+// fixed VHD at `destFile` suitable for attaching read-only to an LCOW utility
+// VM. OCI whiteouts are converted to overlay whiteouts and a dm-verity hash
+// device is appended so that the layer can be attached to utility VMs with
+// layer integrity enabled. Returns the root digest of the hash device that the
+// layer is verified against. If the conversion fails `destFile` is removed.
+func CreateLayerVHD(r io.Reader, destFile string) (rootDigest string, err error) {
+	logrus.WithField("dest", destFile).Debug("lcow::CreateLayerVHD")
+
+	f, err := os.Create(destFile)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(destFile)
+		}
+	}()
+
+	if err := tar2ext4.Convert(r, f, tar2ext4.ConvertWhiteout, tar2ext4.AppendDMVerity, tar2ext4.DMVerityRootDigest(&rootDigest), tar2ext4.AppendVhdFooter); err != nil {
+		return "", fmt.Errorf("failed to convert layer to %s: %s", destFile, err)
+	}
+	return rootDigest, f.Close()
+}
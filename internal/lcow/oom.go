@@ -0,0 +1,110 @@
+package lcow
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Microsoft/hcsshim/internal/hcsoci"
+	"github.com/Microsoft/hcsshim/internal/timeout"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+)
+
+// OOMKind describes why the kernel OOM killer killed a process.
+type OOMKind string
+
+const (
+	// OOMKindNone means the process was not killed by the OOM killer.
+	OOMKindNone OOMKind = ""
+	// OOMKindCgroup means the process exceeded the memory limit of its
+	// container.
+	OOMKindCgroup OOMKind = "cgroup"
+	// OOMKindSystem means the utility VM itself ran out of memory.
+	OOMKindSystem OOMKind = "system"
+)
+
+// ContainerCgroup returns the cgroup the GCS places the processes of the
+// container with `id` in.
+func ContainerCgroup(id string) string {
+	return "/containers/" + id
+}
+
+// OOMKilled inspects the kernel log of `lcowUVM` and returns how the process
+// with `pid` (in the utility VM pid namespace) in `cgroup` was killed by the
+// OOM killer, if at all.
+//
+// An OOM kill is only attributed to the process if the killed task was in
+// `cgroup`, so that a kill of an unrelated process that was earlier assigned
+// the same pid is not reported. Callers should prefer the OOM notifications of
+// guests that send them.
+func OOMKilled(ctx context.Context, lcowUVM *uvm.UtilityVM, cgroup string, pid int) (OOMKind, error) {
+	if lcowUVM == nil || lcowUVM.OS() != "linux" {
+		return OOMKindNone, fmt.Errorf("lcow::OOMKilled requires a linux utility VM to operate")
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout.Load(&timeout.ExternalCommandToComplete))
+	defer cancel()
+	cmd := hcsoci.CommandContext(ctx, lcowUVM, "dmesg")
+	out, err := cmd.Output()
+	if err != nil {
+		return OOMKindNone, fmt.Errorf("failed to `%+v` in utility VM: %s", cmd.Spec.Args, err)
+	}
+	return parseOOMKilled(out, cgroup, pid), nil
+}
+
+// parseOOMKilled searches the kernel log `klog` for the OOM killer killing
+// `pid` in `cgroup`. Each OOM report starts with the task that invoked the
+// killer and logs the cgroup of the victim and which limit was hit before
+// the victim is killed.
+func parseOOMKilled(klog []byte, cgroup string, pid int) OOMKind {
+	killed := fmt.Sprintf("Killed process %d ", pid)
+	kind := OOMKindNone
+	memcg := false
+	inCgroup := false
+	s := bufio.NewScanner(bytes.NewReader(klog))
+	for s.Scan() {
+		line := s.Text()
+		if strings.Contains(line, "invoked oom-killer") {
+			memcg, inCgroup = false, false
+		}
+		if c, ok := parseOOMTaskCgroup(line); ok {
+			inCgroup = c == cgroup || strings.HasPrefix(c, cgroup+"/")
+		}
+		switch {
+		case strings.Contains(line, "Memory cgroup out of memory"):
+			memcg = true
+		case strings.Contains(line, "Out of memory"):
+			memcg = false
+		}
+		if inCgroup && strings.Contains(line, killed) {
+			if memcg {
+				kind = OOMKindCgroup
+			} else {
+				kind = OOMKindSystem
+			}
+		}
+	}
+	return kind
+}
+
+// parseOOMTaskCgroup returns the memory cgroup of the victim of an OOM kill if
+// `line` reports it. Newer kernels log it in the `oom-kill:` summary line,
+// older ones in a `Task in <cgroup> killed` line.
+func parseOOMTaskCgroup(line string) (string, bool) {
+	if i := strings.Index(line, "oom-kill:"); i >= 0 {
+		for _, field := range strings.Split(line[i+len("oom-kill:"):], ",") {
+			if strings.HasPrefix(field, "task_memcg=") {
+				return strings.TrimPrefix(field, "task_memcg="), true
+			}
+		}
+		return "", false
+	}
+	if i := strings.Index(line, "Task in "); i >= 0 {
+		rest := line[i+len("Task in "):]
+		if j := strings.Index(rest, " killed"); j >= 0 {
+			return rest[:j], true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,77 @@
+package lcow
+
+import "testing"
+
+func Test_ParseOOMKilled(t *testing.T) {
+	const cgroup = "/containers/c1"
+	tests := []struct {
+		name string
+		klog string
+		pid  int
+		kind OOMKind
+	}{
+		{
+			name: "NotKilled",
+			klog: "[    0.000000] Linux version 4.19\n",
+			pid:  42,
+			kind: OOMKindNone,
+		},
+		{
+			name: "Cgroup",
+			klog: "[   10.0] app invoked oom-killer: gfp_mask=0x6000c0(GFP_KERNEL), order=0\n" +
+				"[   10.1] Task in /containers/c1 killed as a result of limit of /containers/c1\n" +
+				"[   10.1] Memory cgroup out of memory: Kill process 42 (app) score 999 or sacrifice child\n" +
+				"[   10.2] Killed process 42 (app) total-vm:1000kB, anon-rss:900kB\n",
+			pid:  42,
+			kind: OOMKindCgroup,
+		},
+		{
+			name: "CgroupSingleLine",
+			klog: "[   10.0] app invoked oom-killer: gfp_mask=0x6000c0(GFP_KERNEL), order=0\n" +
+				"[   10.1] oom-kill:constraint=CONSTRAINT_MEMCG,nodemask=(null),cpuset=c1,mems_allowed=0,oom_memcg=/containers/c1,task_memcg=/containers/c1,task=app,pid=42,uid=0\n" +
+				"[   10.1] Memory cgroup out of memory: Killed process 42 (app) total-vm:1000kB\n",
+			pid:  42,
+			kind: OOMKindCgroup,
+		},
+		{
+			name: "System",
+			klog: "[   10.0] app invoked oom-killer: gfp_mask=0x6000c0(GFP_KERNEL), order=0\n" +
+				"[   10.1] oom-kill:constraint=CONSTRAINT_NONE,nodemask=(null),cpuset=c1,mems_allowed=0,global_oom,task_memcg=/containers/c1,task=app,pid=42,uid=0\n" +
+				"[   10.1] Out of memory: Kill process 42 (app) score 999 or sacrifice child\n" +
+				"[   10.2] Killed process 42 (app) total-vm:1000kB, anon-rss:900kB\n",
+			pid:  42,
+			kind: OOMKindSystem,
+		},
+		{
+			name: "OtherPid",
+			klog: "[   10.0] app invoked oom-killer: gfp_mask=0x6000c0(GFP_KERNEL), order=0\n" +
+				"[   10.1] oom-kill:constraint=CONSTRAINT_NONE,nodemask=(null),cpuset=c1,mems_allowed=0,global_oom,task_memcg=/containers/c1,task=app,pid=420,uid=0\n" +
+				"[   10.1] Out of memory: Kill process 420 (app) score 999 or sacrifice child\n" +
+				"[   10.2] Killed process 420 (app) total-vm:1000kB, anon-rss:900kB\n",
+			pid:  42,
+			kind: OOMKindNone,
+		},
+		{
+			name: "ReusedPidOtherCgroup",
+			klog: "[   10.0] app invoked oom-killer: gfp_mask=0x6000c0(GFP_KERNEL), order=0\n" +
+				"[   10.1] oom-kill:constraint=CONSTRAINT_MEMCG,nodemask=(null),cpuset=c2,mems_allowed=0,oom_memcg=/containers/c2,task_memcg=/containers/c2,task=app,pid=42,uid=0\n" +
+				"[   10.1] Memory cgroup out of memory: Killed process 42 (app) total-vm:1000kB\n",
+			pid:  42,
+			kind: OOMKindNone,
+		},
+		{
+			name: "NoCgroupReported",
+			klog: "[   10.1] Out of memory: Kill process 42 (app) score 999 or sacrifice child\n" +
+				"[   10.2] Killed process 42 (app) total-vm:1000kB, anon-rss:900kB\n",
+			pid:  42,
+			kind: OOMKindNone,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if kind := parseOOMKilled([]byte(test.klog), cgroup, test.pid); kind != test.kind {
+				t.Fatalf("expected '%s', got '%s'", test.kind, kind)
+			}
+		})
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Microsoft/go-winio/vhd"
@@ -17,6 +20,102 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// Filesystem is a filesystem a disk can be formatted with.
+type Filesystem string
+
+const (
+	// FilesystemExt4 formats a disk with `mkfs.ext4`.
+	FilesystemExt4 Filesystem = "ext4"
+	// FilesystemXfs formats a disk with `mkfs.xfs`. A container scratch
+	// formatted with it MUST be attached to a utility VM created with
+	// `OptionsLCOW.ScratchFilesystem` set to it.
+	FilesystemXfs Filesystem = "xfs"
+)
+
+// FormatOptions are the options for formatting a disk in a utility VM. A nil
+// `*FormatOptions` formats the disk as ext4 without a journal.
+type FormatOptions struct {
+	// Filesystem is the filesystem to format the disk with. Defaults to
+	// `FilesystemExt4`.
+	Filesystem Filesystem
+	// InodeRatio, if not `0`, creates an inode for every this many bytes of
+	// the disk. Only supported for ext4.
+	InodeRatio uint32
+	// EnableJournal creates the ext4 filesystem with a journal. xfs is always
+	// journaled.
+	EnableJournal bool
+	// MkfsOptions are additional arguments passed to mkfs before the device.
+	MkfsOptions []string
+	// CacheDir, if not empty, is the scratch cache directory
+	// `CreateScratchWithOptions` copies the scratch disk from, or seeds it to,
+	// in place of its cache file. The file used is returned by
+	// `ScratchCacheFile` so that every size and filesystem benefits from the
+	// cache. Ignored when formatting an existing disk.
+	CacheDir string
+}
+
+// isDefault returns `true` if `opts` formats a disk the same as a nil
+// `*FormatOptions`.
+func (opts *FormatOptions) isDefault() bool {
+	return opts == nil ||
+		(opts.Filesystem == "" || opts.Filesystem == FilesystemExt4) &&
+			opts.InodeRatio == 0 &&
+			!opts.EnableJournal &&
+			len(opts.MkfsOptions) == 0
+}
+
+// ScratchCacheFile returns the file in the scratch cache directory `cacheDir`
+// holding the scratch disks of `sizeGB` formatted with `opts`, or "" if such
+// scratch disks are not cached. Only scratch disks formatted with the defaults
+// of a supported filesystem are cached.
+func ScratchCacheFile(cacheDir string, sizeGB uint32, opts *FormatOptions) string {
+	if cacheDir == "" {
+		return ""
+	}
+	fs := FilesystemExt4
+	if opts != nil {
+		if opts.InodeRatio != 0 || opts.EnableJournal || len(opts.MkfsOptions) != 0 {
+			return ""
+		}
+		switch opts.Filesystem {
+		case "":
+		case FilesystemExt4, FilesystemXfs:
+			fs = opts.Filesystem
+		default:
+			return ""
+		}
+	}
+	return filepath.Join(cacheDir, fmt.Sprintf("scratch-%dGB-%s.vhdx", sizeGB, fs))
+}
+
+// mkfsArgs returns the command line formatting `device` with `opts`.
+func mkfsArgs(opts *FormatOptions, device string) ([]string, error) {
+	if opts == nil {
+		opts = &FormatOptions{}
+	}
+	var args []string
+	switch opts.Filesystem {
+	case "", FilesystemExt4:
+		features := "^has_journal,sparse_super2,^resize_inode"
+		if opts.EnableJournal {
+			features = "has_journal,sparse_super2,^resize_inode"
+		}
+		args = []string{"mkfs.ext4", "-q", "-E", "lazy_itable_init=0,nodiscard", "-O", features}
+		if opts.InodeRatio != 0 {
+			args = append(args, "-i", strconv.FormatUint(uint64(opts.InodeRatio), 10))
+		}
+	case FilesystemXfs:
+		if opts.InodeRatio != 0 {
+			return nil, errors.New("an inode ratio is not supported for xfs")
+		}
+		args = []string{"mkfs.xfs", "-q", "-K"}
+	default:
+		return nil, fmt.Errorf("unsupported filesystem '%s'", opts.Filesystem)
+	}
+	args = append(args, opts.MkfsOptions...)
+	return append(args, device), nil
+}
+
 // CreateScratch uses a utility VM to create an empty scratch disk of a
 // requested size. It has a caching capability. If the cacheFile exists, and the
 // request is for a default size, a copy of that is made to the target. If the
@@ -24,6 +123,30 @@ import (
 // to create target. It is the responsibility of the caller to synchronise
 // simultaneous attempts to create the cache file.
 func CreateScratch(lcowUVM *uvm.UtilityVM, destFile string, sizeGB uint32, cacheFile string) error {
+	if sizeGB != DefaultScratchSizeGB {
+		cacheFile = ""
+	}
+	return createScratch(lcowUVM, destFile, sizeGB, cacheFile, nil)
+}
+
+// CreateScratchWithOptions is `CreateScratch` formatting the scratch disk with
+// `opts`. `cacheFile` is only used if `opts` is the default ext4 format. If
+// `opts.CacheDir` is set the scratch disk is cached in it instead. It is the
+// responsibility of the caller to synchronise simultaneous attempts to create
+// the same cache file.
+func CreateScratchWithOptions(lcowUVM *uvm.UtilityVM, destFile string, sizeGB uint32, cacheFile string, opts *FormatOptions) error {
+	if opts != nil && opts.CacheDir != "" {
+		cacheFile = ScratchCacheFile(opts.CacheDir, sizeGB, opts)
+	} else if sizeGB != DefaultScratchSizeGB || !opts.isDefault() {
+		cacheFile = ""
+	}
+	return createScratch(lcowUVM, destFile, sizeGB, cacheFile, opts)
+}
+
+// createScratch is the implementation of `CreateScratch` and
+// `CreateScratchWithOptions`. If `cacheFile` is not empty it holds scratch
+// disks of `sizeGB` formatted with `opts`.
+func createScratch(lcowUVM *uvm.UtilityVM, destFile string, sizeGB uint32, cacheFile string, opts *FormatOptions) error {
 	if lcowUVM == nil {
 		return fmt.Errorf("no uvm")
 	}
@@ -36,10 +159,11 @@ func CreateScratch(lcowUVM *uvm.UtilityVM, destFile string, sizeGB uint32, cache
 		"dest":   destFile,
 		"sizeGB": sizeGB,
 		"cache":  cacheFile,
+		"format": fmt.Sprintf("%+v", opts),
 	}).Debug("lcow::CreateScratch opts")
 
-	// Retrieve from cache if the default size and already on disk
-	if cacheFile != "" && sizeGB == DefaultScratchSizeGB {
+	// Retrieve from cache if already on disk
+	if cacheFile != "" {
 		if _, err := os.Stat(cacheFile); err == nil {
 			if err := copyfile.CopyFile(cacheFile, destFile, false); err != nil {
 				return fmt.Errorf("failed to copy cached file '%s' to '%s': %s", cacheFile, destFile, err)
@@ -57,7 +181,44 @@ func CreateScratch(lcowUVM *uvm.UtilityVM, destFile string, sizeGB uint32, cache
 		return fmt.Errorf("failed to create VHDx %s: %s", destFile, err)
 	}
 
-	controller, lun, err := lcowUVM.AddSCSI(destFile, "", false) // No destination as not formatted
+	if err := FormatDiskWithOptions(lcowUVM, destFile, opts); err != nil {
+		return err
+	}
+
+	// Populate the cache.
+	if cacheFile != "" {
+		if err := copyfile.CopyFile(destFile, cacheFile, true); err != nil {
+			return fmt.Errorf("failed to seed cache '%s' from '%s': %s", destFile, cacheFile, err)
+		}
+	}
+
+	logrus.WithField("dest", destFile).Debug("lcow::CreateScratch created (non-cache)")
+	return nil
+}
+
+// FormatDisk formats the existing VHD(x) at `destFile` as ext4 using a
+// utility VM. The disk is hot-added to the utility VM for the duration of the
+// format and is not attached when FormatDisk returns.
+func FormatDisk(lcowUVM *uvm.UtilityVM, destFile string) error {
+	return FormatDiskWithOptions(lcowUVM, destFile, nil)
+}
+
+// FormatDiskWithOptions is `FormatDisk` formatting the disk with `opts`.
+func FormatDiskWithOptions(lcowUVM *uvm.UtilityVM, destFile string, opts *FormatOptions) error {
+	if lcowUVM == nil {
+		return fmt.Errorf("no uvm")
+	}
+
+	if lcowUVM.OS() != "linux" {
+		return errors.New("lcow::FormatDisk requires a linux utility VM to operate")
+	}
+
+	// Validate the options before attaching the disk.
+	if _, err := mkfsArgs(opts, ""); err != nil {
+		return err
+	}
+
+	controller, lun, err := lcowUVM.AddSCSI(destFile, nil) // No destination as not formatted
 	if err != nil {
 		return err
 	}
@@ -72,65 +233,73 @@ func CreateScratch(lcowUVM *uvm.UtilityVM, destFile string, sizeGB uint32, cache
 		"dest":       destFile,
 		"controller": controller,
 		"lun":        lun,
-	}).Debug("lcow::CreateScratch device attached")
+	}).Debug("lcow::FormatDisk device attached")
 
 	// Validate /sys/bus/scsi/devices/C:0:0:L exists as a directory
 	devicePath := fmt.Sprintf("/sys/bus/scsi/devices/%d:0:0:%d/block", controller, lun)
-	testdCtx, cancel := context.WithTimeout(context.TODO(), timeout.TestDRetryLoop)
+	testdCtx, cancel := context.WithTimeout(context.TODO(), timeout.Load(&timeout.TestDRetryLoop))
 	defer cancel()
 	for {
 		cmd := hcsoci.CommandContext(testdCtx, lcowUVM, "test", "-d", devicePath)
+		var testdStderr bytes.Buffer
+		cmd.Stderr = &testdStderr
 		err := cmd.Run()
 		if err == nil {
 			break
 		}
 		if _, ok := err.(*hcsoci.ExitError); !ok {
-			return fmt.Errorf("failed to run %+v following hot-add %s to utility VM: %s", cmd.Spec.Args, destFile, err)
+			return fmt.Errorf("failed to run %+v following hot-add %s to utility VM: %s%s", cmd.Spec.Args, destFile, err, formatStderr(&testdStderr))
 		}
 		time.Sleep(time.Millisecond * 10)
 	}
 	cancel()
 
 	// Get the device from under the block subdirectory by doing a simple ls. This will come back as (eg) `sda`
-	lsCtx, cancel := context.WithTimeout(context.TODO(), timeout.ExternalCommandToStart)
+	lsCtx, cancel := context.WithTimeout(context.TODO(), timeout.Load(&timeout.ExternalCommandToStart))
 	cmd := hcsoci.CommandContext(lsCtx, lcowUVM, "ls", devicePath)
+	var lsStderr bytes.Buffer
+	cmd.Stderr = &lsStderr
 	lsOutput, err := cmd.Output()
 	cancel()
 	if err != nil {
-		return fmt.Errorf("failed to `%+v` following hot-add %s to utility VM: %s", cmd.Spec.Args, destFile, err)
+		return fmt.Errorf("failed to `%+v` following hot-add %s to utility VM: %s%s", cmd.Spec.Args, destFile, err, formatStderr(&lsStderr))
 	}
 	device := fmt.Sprintf(`/dev/%s`, bytes.TrimSpace(lsOutput))
 	logrus.WithFields(logrus.Fields{
 		"dest":   destFile,
 		"device": device,
-	}).Debug("lcow::CreateScratch device guest location")
+	}).Debug("lcow::FormatDisk device guest location")
 
-	// Format it ext4
-	mkfsCtx, cancel := context.WithTimeout(context.TODO(), timeout.ExternalCommandToStart)
-	cmd = hcsoci.CommandContext(mkfsCtx, lcowUVM, "mkfs.ext4", "-q", "-E", "lazy_itable_init=0,nodiscard", "-O", `^has_journal,sparse_super2,^resize_inode`, device)
+	// Format it
+	args, err := mkfsArgs(opts, device)
+	if err != nil {
+		return err
+	}
+	mkfsCtx, cancel := context.WithTimeout(context.TODO(), timeout.Load(&timeout.ExternalCommandToStart))
+	cmd = hcsoci.CommandContext(mkfsCtx, lcowUVM, args[0], args[1:]...)
 	var mkfsStderr bytes.Buffer
 	cmd.Stderr = &mkfsStderr
 	err = cmd.Run()
 	cancel()
 	if err != nil {
-		return fmt.Errorf("failed to `%+v` following hot-add %s to utility VM: %s", cmd.Spec.Args, destFile, err)
+		return fmt.Errorf("failed to `%+v` following hot-add %s to utility VM: %s%s", cmd.Spec.Args, destFile, err, formatStderr(&mkfsStderr))
 	}
 
-	// Hot-Remove before we copy it
+	// Hot-Remove before the caller uses it
 	removeSCSI = false
 	if err := lcowUVM.RemoveSCSI(destFile); err != nil {
 		return fmt.Errorf("failed to hot-remove: %s", err)
 	}
+	return nil
+}
 
-	// Populate the cache.
-	if cacheFile != "" && (sizeGB == DefaultScratchSizeGB) {
-		if err := copyfile.CopyFile(destFile, cacheFile, true); err != nil {
-			return fmt.Errorf("failed to seed cache '%s' from '%s': %s", destFile, cacheFile, err)
-		}
+// formatStderr returns the captured stderr of a failed utility VM command
+// formatted for appending to an error, or "" if nothing was captured.
+func formatStderr(stderr *bytes.Buffer) string {
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return ": " + msg
 	}
-
-	logrus.WithField("dest", destFile).Debug("lcow::CreateScratch created (non-cache)")
-	return nil
+	return ""
 }
 
 func waitForProcess(p cow.Process) (int, error) {
@@ -139,7 +308,7 @@ func waitForProcess(p cow.Process) (int, error) {
 		ch <- p.Wait()
 	}()
 
-	t := time.NewTimer(timeout.ExternalCommandToComplete)
+	t := time.NewTimer(timeout.Load(&timeout.ExternalCommandToComplete))
 	select {
 	case <-ch:
 		t.Stop()
@@ -0,0 +1,111 @@
+package lcow
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_FormatStderr(t *testing.T) {
+	if s := formatStderr(&bytes.Buffer{}); s != "" {
+		t.Fatalf("expected empty string for no stderr, got '%s'", s)
+	}
+	stderr := bytes.NewBufferString("mkfs.ext4: No such file or directory\n")
+	if s := formatStderr(stderr); s != ": mkfs.ext4: No such file or directory" {
+		t.Fatalf("unexpected formatted stderr '%s'", s)
+	}
+}
+
+func Test_MkfsArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     *FormatOptions
+		expected []string
+	}{
+		{
+			name:     "Default",
+			expected: []string{"mkfs.ext4", "-q", "-E", "lazy_itable_init=0,nodiscard", "-O", "^has_journal,sparse_super2,^resize_inode", "/dev/sda"},
+		},
+		{
+			name:     "Ext4",
+			opts:     &FormatOptions{Filesystem: FilesystemExt4, InodeRatio: 65536, EnableJournal: true, MkfsOptions: []string{"-m", "0"}},
+			expected: []string{"mkfs.ext4", "-q", "-E", "lazy_itable_init=0,nodiscard", "-O", "has_journal,sparse_super2,^resize_inode", "-i", "65536", "-m", "0", "/dev/sda"},
+		},
+		{
+			name:     "Xfs",
+			opts:     &FormatOptions{Filesystem: FilesystemXfs, MkfsOptions: []string{"-m", "reflink=1"}},
+			expected: []string{"mkfs.xfs", "-q", "-K", "-m", "reflink=1", "/dev/sda"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			args, err := mkfsArgs(test.opts, "/dev/sda")
+			if err != nil {
+				t.Fatalf("expected nil error, got: %v", err)
+			}
+			if !reflect.DeepEqual(args, test.expected) {
+				t.Fatalf("expected %v, got: %v", test.expected, args)
+			}
+		})
+	}
+}
+
+func Test_MkfsArgs_Invalid(t *testing.T) {
+	for _, opts := range []*FormatOptions{
+		{Filesystem: "btrfs"},
+		{Filesystem: FilesystemXfs, InodeRatio: 65536},
+	} {
+		if _, err := mkfsArgs(opts, "/dev/sda"); err == nil {
+			t.Fatalf("expected error for %+v", opts)
+		}
+	}
+}
+
+func Test_ScratchCacheFile(t *testing.T) {
+	if f := ScratchCacheFile("", DefaultScratchSizeGB, nil); f != "" {
+		t.Fatalf("expected no cache file without a cache directory, got: %s", f)
+	}
+	dir := filepath.Join("c:", "cache")
+	tests := []struct {
+		sizeGB   uint32
+		opts     *FormatOptions
+		expected string
+	}{
+		{sizeGB: DefaultScratchSizeGB, expected: "scratch-20GB-ext4.vhdx"},
+		{sizeGB: 50, opts: &FormatOptions{Filesystem: FilesystemExt4}, expected: "scratch-50GB-ext4.vhdx"},
+		{sizeGB: 50, opts: &FormatOptions{Filesystem: FilesystemXfs}, expected: "scratch-50GB-xfs.vhdx"},
+	}
+	for _, test := range tests {
+		if f := ScratchCacheFile(dir, test.sizeGB, test.opts); f != filepath.Join(dir, test.expected) {
+			t.Fatalf("expected cache file %s for %dGB %+v, got: %s", test.expected, test.sizeGB, test.opts, f)
+		}
+	}
+	for _, opts := range []*FormatOptions{
+		{Filesystem: "btrfs"},
+		{InodeRatio: 4096},
+		{EnableJournal: true},
+		{MkfsOptions: []string{"-m", "0"}},
+	} {
+		if f := ScratchCacheFile(dir, DefaultScratchSizeGB, opts); f != "" {
+			t.Fatalf("expected %+v not to be cached, got: %s", opts, f)
+		}
+	}
+}
+
+func Test_FormatOptions_IsDefault(t *testing.T) {
+	var opts *FormatOptions
+	if !opts.isDefault() || !(&FormatOptions{Filesystem: FilesystemExt4, CacheDir: "c:\\cache"}).isDefault() {
+		t.Fatal("expected the default ext4 format to be default")
+	}
+	for _, opts := range []*FormatOptions{
+		{Filesystem: FilesystemXfs},
+		{InodeRatio: 4096},
+		{EnableJournal: true},
+		{MkfsOptions: []string{"-m", "0"}},
+	} {
+		if opts.isDefault() {
+			t.Fatalf("expected %+v not to be default", opts)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package lcow
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Microsoft/hcsshim/internal/copyfile"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/sirupsen/logrus"
+)
+
+// scratchPoolTempDir is the directory of a scratch pool that disks are
+// formatted in before they are ready.
+const scratchPoolTempDir = "tmp"
+
+// ScratchPool keeps pre-formatted scratch disks ready on disk so that creating
+// a scratch disk does not wait for a utility VM to format it. The disks are
+// pooled per size class, which is the size and filesystem of the disk, and
+// only for the formats cached by `ScratchCacheFile`. The pool is refilled in
+// the background whenever a disk of a size class is taken from it.
+//
+// Ready disks are left in the pool directory when the pool is closed and are
+// used by the next pool of the same directory.
+type ScratchPool struct {
+	dir   string
+	count int
+
+	// create creates the scratch disk `destFile` of `sizeGB` formatted with
+	// `opts`.
+	create func(destFile string, sizeGB uint32, opts *FormatOptions) error
+
+	m       sync.Mutex
+	ready   map[string][]string // The ready disks of each size class keyed by `ScratchCacheFile`
+	filling map[string]bool     // The size classes being refilled
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// NewScratchPool returns a pool keeping `count` scratch disks of each size
+// class ready in `dir`, formatted by the utility VM `lcowUVM`. The utility VM
+// MUST NOT be closed before the pool.
+func NewScratchPool(lcowUVM *uvm.UtilityVM, dir string, count int) (*ScratchPool, error) {
+	if lcowUVM == nil {
+		return nil, errors.New("no uvm")
+	}
+	if lcowUVM.OS() != "linux" {
+		return nil, errors.New("lcow::NewScratchPool requires a linux utility VM to operate")
+	}
+	return newScratchPool(dir, count, func(destFile string, sizeGB uint32, opts *FormatOptions) error {
+		return createScratch(lcowUVM, destFile, sizeGB, "", opts)
+	})
+}
+
+func newScratchPool(dir string, count int, create func(string, uint32, *FormatOptions) error) (*ScratchPool, error) {
+	if dir == "" {
+		return nil, errors.New("a scratch pool requires a directory")
+	}
+	if count <= 0 {
+		return nil, errors.New("a scratch pool must keep at least one disk ready")
+	}
+	// Disks being formatted when a previous pool was closed are incomplete.
+	tempDir := filepath.Join(dir, scratchPoolTempDir)
+	if err := os.RemoveAll(tempDir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(tempDir, 0700); err != nil {
+		return nil, err
+	}
+	return &ScratchPool{
+		dir:     dir,
+		count:   count,
+		create:  create,
+		ready:   make(map[string][]string),
+		filling: make(map[string]bool),
+	}, nil
+}
+
+// Prime fills the size class of `sizeGB` formatted with `opts` in the
+// background so that the first scratch disks created of it are ready.
+func (p *ScratchPool) Prime(sizeGB uint32, opts *FormatOptions) error {
+	class := ScratchCacheFile(p.dir, sizeGB, opts)
+	if class == "" {
+		return errors.New("scratch disks with non-default format options cannot be pooled")
+	}
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.loadL(class)
+	p.fillL(class, sizeGB, opts)
+	return nil
+}
+
+// CreateScratch creates the scratch disk `destFile` of `sizeGB` formatted with
+// `opts` by moving a ready disk of its size class from the pool. If the pool
+// has none the disk is created as by `CreateScratchWithOptions`.
+func (p *ScratchPool) CreateScratch(destFile string, sizeGB uint32, opts *FormatOptions) error {
+	class := ScratchCacheFile(p.dir, sizeGB, opts)
+	if class == "" {
+		return p.create(destFile, sizeGB, opts)
+	}
+
+	var disk string
+	p.m.Lock()
+	p.loadL(class)
+	if n := len(p.ready[class]); n > 0 {
+		disk = p.ready[class][n-1]
+		p.ready[class] = p.ready[class][:n-1]
+	}
+	p.fillL(class, sizeGB, opts)
+	p.m.Unlock()
+
+	if disk != "" {
+		err := moveFile(disk, destFile)
+		if err == nil {
+			logrus.WithFields(logrus.Fields{
+				"dest": destFile,
+				"pool": disk,
+			}).Debug("lcow::ScratchPool::CreateScratch moved from pool")
+			return nil
+		}
+		logrus.WithFields(logrus.Fields{
+			"dest":          destFile,
+			"pool":          disk,
+			logrus.ErrorKey: err,
+		}).Warning("lcow::ScratchPool::CreateScratch failed to move from pool")
+	}
+	return p.create(destFile, sizeGB, opts)
+}
+
+// Close stops refilling the pool and waits for the disks being formatted.
+func (p *ScratchPool) Close() error {
+	p.m.Lock()
+	p.closed = true
+	p.m.Unlock()
+	p.wg.Wait()
+	return nil
+}
+
+// loadL loads the disks of the size class `class` left ready by a previous
+// pool the first time the size class is used. Lock must be held when calling
+// this function.
+func (p *ScratchPool) loadL(class string) {
+	if _, ok := p.ready[class]; ok {
+		return
+	}
+	disks, _ := filepath.Glob(strings.TrimSuffix(class, ".vhdx") + "-*.vhdx")
+	p.ready[class] = disks
+}
+
+// fillL starts filling the size class `class` in the background unless it is
+// already being filled. Lock must be held when calling this function.
+func (p *ScratchPool) fillL(class string, sizeGB uint32, opts *FormatOptions) {
+	if p.closed || p.filling[class] || len(p.ready[class]) >= p.count {
+		return
+	}
+	p.filling[class] = true
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		for {
+			p.m.Lock()
+			if p.closed || len(p.ready[class]) >= p.count {
+				p.filling[class] = false
+				p.m.Unlock()
+				return
+			}
+			p.m.Unlock()
+
+			disk, err := p.provision(class, sizeGB, opts)
+
+			p.m.Lock()
+			if err != nil {
+				// Stop rather than retrying a failing format in a loop. The
+				// next disk taken from the size class retries.
+				p.filling[class] = false
+				p.m.Unlock()
+				logrus.WithFields(logrus.Fields{
+					"pool":          class,
+					logrus.ErrorKey: err,
+				}).Error("lcow::ScratchPool failed to provision scratch disk")
+				return
+			}
+			p.ready[class] = append(p.ready[class], disk)
+			p.m.Unlock()
+		}
+	}()
+}
+
+// provision formats a new disk of the size class `class` and returns its path
+// once it is ready.
+func (p *ScratchPool) provision(class string, sizeGB uint32, opts *FormatOptions) (string, error) {
+	f, err := ioutil.TempFile(filepath.Join(p.dir, scratchPoolTempDir), strings.TrimSuffix(filepath.Base(class), ".vhdx")+"-*.vhdx")
+	if err != nil {
+		return "", err
+	}
+	temp := f.Name()
+	f.Close()
+	// The name is reserved. The disk itself must be created in its place.
+	if err := os.Remove(temp); err != nil {
+		return "", err
+	}
+	if err := p.create(temp, sizeGB, opts); err != nil {
+		os.Remove(temp)
+		return "", err
+	}
+	disk := filepath.Join(p.dir, filepath.Base(temp))
+	if err := os.Rename(temp, disk); err != nil {
+		os.Remove(temp)
+		return "", err
+	}
+	return disk, nil
+}
+
+// moveFile moves `src` to `dst`, copying it if it cannot be renamed such as
+// when `dst` is on another volume.
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	if err := copyfile.CopyFile(src, dst, true); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
@@ -0,0 +1,168 @@
+package lcow
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestScratchPool returns a pool of `dir` whose disks are created by
+// writing their size class and which counts the disks created in `created`.
+func newTestScratchPool(t *testing.T, dir string, count int, created *int32) *ScratchPool {
+	p, err := newScratchPool(dir, count, func(destFile string, sizeGB uint32, opts *FormatOptions) error {
+		atomic.AddInt32(created, 1)
+		return ioutil.WriteFile(destFile, []byte(ScratchCacheFile(dir, sizeGB, opts)), 0600)
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	return p
+}
+
+// waitReady waits for the size class `class` of `p` to have `n` ready disks.
+func waitReady(t *testing.T, p *ScratchPool, class string, n int) {
+	for start := time.Now(); time.Since(start) < 10*time.Second; time.Sleep(time.Millisecond) {
+		p.m.Lock()
+		ready, filling := len(p.ready[class]), p.filling[class]
+		p.m.Unlock()
+		if ready == n && !filling {
+			return
+		}
+	}
+	t.Fatalf("timed out waiting for %d ready disks of %s", n, class)
+}
+
+func Test_ScratchPool(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scratchpool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var created int32
+	p := newTestScratchPool(t, dir, 2, &created)
+	defer p.Close()
+	opts := &FormatOptions{Filesystem: FilesystemXfs}
+	class := ScratchCacheFile(dir, 50, opts)
+	if err := p.Prime(50, opts); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	waitReady(t, p, class, 2)
+
+	dest := filepath.Join(dir, "sandbox.vhdx")
+	if err := p.CreateScratch(dest, 50, opts); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if b, err := ioutil.ReadFile(dest); err != nil || string(b) != class {
+		t.Fatalf("expected a disk of %s, got: %q %v", class, b, err)
+	}
+	// The disk taken is replaced in the background.
+	waitReady(t, p, class, 2)
+	if n := atomic.LoadInt32(&created); n != 3 {
+		t.Fatalf("expected 3 disks created, got: %d", n)
+	}
+}
+
+func Test_ScratchPool_Empty(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scratchpool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var created int32
+	p := newTestScratchPool(t, dir, 1, &created)
+	defer p.Close()
+	dest := filepath.Join(dir, "sandbox.vhdx")
+	if err := p.CreateScratch(dest, DefaultScratchSizeGB, nil); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if _, err := os.Stat(dest); err != nil {
+		t.Fatalf("expected the disk to be created, got: %v", err)
+	}
+	waitReady(t, p, ScratchCacheFile(dir, DefaultScratchSizeGB, nil), 1)
+}
+
+func Test_ScratchPool_NotPooled(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scratchpool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var created int32
+	p := newTestScratchPool(t, dir, 1, &created)
+	defer p.Close()
+	opts := &FormatOptions{EnableJournal: true}
+	if err := p.Prime(DefaultScratchSizeGB, opts); err == nil {
+		t.Fatal("expected error priming a non-default format")
+	}
+	if err := p.CreateScratch(filepath.Join(dir, "sandbox.vhdx"), DefaultScratchSizeGB, opts); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	p.Close()
+	if n := atomic.LoadInt32(&created); n != 1 {
+		t.Fatalf("expected only the requested disk to be created, got: %d", n)
+	}
+}
+
+func Test_ScratchPool_Reload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scratchpool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var created int32
+	p := newTestScratchPool(t, dir, 1, &created)
+	class := ScratchCacheFile(dir, DefaultScratchSizeGB, nil)
+	if err := p.Prime(DefaultScratchSizeGB, nil); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	waitReady(t, p, class, 1)
+	p.Close()
+
+	// The next pool of the directory uses the disk left ready.
+	p = newTestScratchPool(t, dir, 1, &created)
+	defer p.Close()
+	if err := p.CreateScratch(filepath.Join(dir, "sandbox.vhdx"), DefaultScratchSizeGB, nil); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	waitReady(t, p, class, 1)
+	if n := atomic.LoadInt32(&created); n != 2 {
+		t.Fatalf("expected 2 disks created, got: %d", n)
+	}
+}
+
+func Test_ScratchPool_CreateError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scratchpool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var created int32
+	p, err := newScratchPool(dir, 2, func(string, uint32, *FormatOptions) error {
+		atomic.AddInt32(&created, 1)
+		return errors.New("format failed")
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	defer p.Close()
+	if err := p.Prime(DefaultScratchSizeGB, nil); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	// A failing format is not retried until the size class is used again.
+	waitReady(t, p, ScratchCacheFile(dir, DefaultScratchSizeGB, nil), 0)
+	if n := atomic.LoadInt32(&created); n != 1 {
+		t.Fatalf("expected a single attempt, got: %d", n)
+	}
+	if files, _ := ioutil.ReadDir(filepath.Join(dir, scratchPoolTempDir)); len(files) != 0 {
+		t.Fatalf("expected no incomplete disks, got: %d", len(files))
+	}
+}
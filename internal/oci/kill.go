@@ -0,0 +1,103 @@
+package oci
+
+import (
+	"time"
+
+	"github.com/Microsoft/hcsshim/internal/signals"
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+const (
+	// AnnotationKillSignal is the signal that triggers the kill escalation
+	// policy for all execs in the container. If omitted (or 0) any signal
+	// sent to an exec triggers the policy.
+	AnnotationKillSignal = "io.microsoft.container.kill.signal"
+	// AnnotationKillSignalTimeoutInMs is the amount of time after delivering
+	// the kill signal that the shim waits for the exec to exit before
+	// escalating. If omitted (or 0) the kill escalation policy is disabled and
+	// the signal is delivered once.
+	AnnotationKillSignalTimeoutInMs = "io.microsoft.container.kill.signaltimeoutms"
+	// AnnotationKillEscalationSignal is the signal sent to an exec that did not
+	// exit within `AnnotationKillSignalTimeoutInMs` of the kill signal. For
+	// example SIGKILL (9) for SIGTERM->SIGKILL semantics. If omitted (or 0) the
+	// shim forcibly terminates the exec directly.
+	AnnotationKillEscalationSignal = "io.microsoft.container.kill.escalationsignal"
+	// AnnotationKillEscalationTimeoutInMs is the amount of time after
+	// delivering the escalation signal that the shim waits for the exec to exit
+	// before forcibly terminating it. If omitted (or 0) defaults to
+	// `DefaultKillEscalationTimeout`.
+	AnnotationKillEscalationTimeoutInMs = "io.microsoft.container.kill.escalationtimeoutms"
+	// AnnotationProcessStopTimeout is the amount of time in seconds an exec is
+	// given to exit after a stop signal before it is forcibly terminated. It
+	// overrides the shim-wide process stop timeout for the container and is
+	// ignored if `AnnotationKillSignalTimeoutInMs` is set.
+	AnnotationProcessStopTimeout = "io.microsoft.container.processstoptimeout"
+)
+
+// DefaultKillEscalationTimeout is the amount of time after delivering the
+// escalation signal that the shim waits before forcibly terminating an exec if
+// `AnnotationKillEscalationTimeoutInMs` is not set.
+const DefaultKillEscalationTimeout = time.Second * 5
+
+// KillPolicy describes how the shim escalates a signal sent to an exec that
+// does not cause it to exit.
+//
+// When `Signal` is sent the shim waits `SignalTimeout` for the exec to exit.
+// If it has not, `EscalationSignal` is sent (if set) and the shim waits
+// `EscalationTimeout`. If the exec has still not exited it is forcibly
+// terminated.
+type KillPolicy struct {
+	// Signal is the signal that triggers the policy. 0 means any signal.
+	Signal uint32
+	// SignalTimeout is the time to wait after `Signal` before escalating. 0
+	// disables the policy.
+	SignalTimeout time.Duration
+	// EscalationSignal is the signal to send on escalation. 0 means skip
+	// straight to terminate.
+	EscalationSignal uint32
+	// EscalationTimeout is the time to wait after `EscalationSignal` before
+	// terminating.
+	EscalationTimeout time.Duration
+	// StopSignalsOnly limits a policy without a `Signal` to the signals that
+	// stop a process, rather than any signal.
+	StopSignalsOnly bool
+}
+
+// Enabled returns `true` if the policy escalates at all.
+func (kp KillPolicy) Enabled() bool {
+	return kp.SignalTimeout > 0
+}
+
+// Applies returns `true` if sending `signal` to an exec of a Windows container
+// if `isWCOW`, or of a Linux container if not, should trigger escalation.
+func (kp KillPolicy) Applies(signal uint32, isWCOW bool) bool {
+	if !kp.Enabled() {
+		return false
+	}
+	if kp.Signal != 0 {
+		return kp.Signal == signal
+	}
+	return !kp.StopSignalsOnly || signals.IsStop(int(signal), isWCOW)
+}
+
+// ParseAnnotationsKillPolicy searches `s.Annotations` for the kill escalation
+// annotations and returns the resulting policy. If none are found the returned
+// policy is disabled.
+func ParseAnnotationsKillPolicy(s *specs.Spec) KillPolicy {
+	kp := KillPolicy{
+		Signal:            parseAnnotationsUint32(s.Annotations, AnnotationKillSignal, 0),
+		SignalTimeout:     time.Duration(parseAnnotationsUint32(s.Annotations, AnnotationKillSignalTimeoutInMs, 0)) * time.Millisecond,
+		EscalationSignal:  parseAnnotationsUint32(s.Annotations, AnnotationKillEscalationSignal, 0),
+		EscalationTimeout: time.Duration(parseAnnotationsUint32(s.Annotations, AnnotationKillEscalationTimeoutInMs, 0)) * time.Millisecond,
+	}
+	if kp.EscalationTimeout == 0 {
+		kp.EscalationTimeout = DefaultKillEscalationTimeout
+	}
+	return kp
+}
+
+// ParseAnnotationsProcessStopTimeout searches `s.Annotations` for the process
+// stop timeout. If not found returns `0`.
+func ParseAnnotationsProcessStopTimeout(s *specs.Spec) time.Duration {
+	return time.Duration(parseAnnotationsUint32(s.Annotations, AnnotationProcessStopTimeout, 0)) * time.Second
+}
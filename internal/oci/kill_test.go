@@ -0,0 +1,87 @@
+package oci
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func Test_ParseAnnotationsKillPolicy_Default(t *testing.T) {
+	kp := ParseAnnotationsKillPolicy(&specs.Spec{})
+	if kp.Enabled() {
+		t.Fatal("expected policy to be disabled by default")
+	}
+	if kp.Applies(15, false) {
+		t.Fatal("disabled policy should not apply to any signal")
+	}
+}
+
+func Test_ParseAnnotationsKillPolicy_TermThenKill(t *testing.T) {
+	s := &specs.Spec{
+		Annotations: map[string]string{
+			AnnotationKillSignal:            "15",
+			AnnotationKillSignalTimeoutInMs: "30000",
+			AnnotationKillEscalationSignal:  "9",
+		},
+	}
+	kp := ParseAnnotationsKillPolicy(s)
+	expected := KillPolicy{
+		Signal:            15,
+		SignalTimeout:     30 * time.Second,
+		EscalationSignal:  9,
+		EscalationTimeout: DefaultKillEscalationTimeout,
+	}
+	if kp != expected {
+		t.Fatalf("expected %+v, got %+v", expected, kp)
+	}
+	if !kp.Applies(15, false) {
+		t.Fatal("policy should apply to SIGTERM")
+	}
+	if kp.Applies(1, false) {
+		t.Fatal("policy should not apply to SIGHUP")
+	}
+}
+
+func Test_ParseAnnotationsKillPolicy_AnySignal(t *testing.T) {
+	s := &specs.Spec{
+		Annotations: map[string]string{
+			AnnotationKillSignalTimeoutInMs:     "100",
+			AnnotationKillEscalationTimeoutInMs: "200",
+		},
+	}
+	kp := ParseAnnotationsKillPolicy(s)
+	if !kp.Applies(1, false) || !kp.Applies(9, false) {
+		t.Fatal("policy without a signal should apply to any signal")
+	}
+	if kp.EscalationTimeout != 200*time.Millisecond {
+		t.Fatalf("expected escalation timeout 200ms, got %s", kp.EscalationTimeout)
+	}
+}
+
+func Test_KillPolicy_StopSignalsOnly(t *testing.T) {
+	kp := KillPolicy{SignalTimeout: time.Second, StopSignalsOnly: true}
+	if !kp.Applies(15, false) || !kp.Applies(2, false) || !kp.Applies(6, true) {
+		t.Fatal("policy should apply to stop signals")
+	}
+	if kp.Applies(1, false) || kp.Applies(10, false) {
+		t.Fatal("policy should not apply to SIGHUP or SIGUSR1")
+	}
+	if kp.Applies(1, true) {
+		t.Fatal("policy should not apply to CTRLBREAK")
+	}
+}
+
+func Test_ParseAnnotationsProcessStopTimeout(t *testing.T) {
+	if d := ParseAnnotationsProcessStopTimeout(&specs.Spec{}); d != 0 {
+		t.Fatalf("expected no timeout, got %s", d)
+	}
+	s := &specs.Spec{
+		Annotations: map[string]string{
+			AnnotationProcessStopTimeout: "45",
+		},
+	}
+	if d := ParseAnnotationsProcessStopTimeout(s); d != 45*time.Second {
+		t.Fatalf("expected 45s, got %s", d)
+	}
+}
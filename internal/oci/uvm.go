@@ -2,11 +2,17 @@ package oci
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	runhcsopts "github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/options"
+	"github.com/Microsoft/hcsshim/internal/backend"
+	"github.com/Microsoft/hcsshim/internal/cow"
+	"github.com/Microsoft/hcsshim/internal/jobobject"
 	"github.com/Microsoft/hcsshim/internal/logfields"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
 	"github.com/Microsoft/hcsshim/internal/uvm"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
@@ -60,6 +66,103 @@ const (
 	// `WindowsPodSandboxConfig` for setting this correctly. It should not be
 	// used via OCI runtimes and rather use `spec.Windows.Resources.CPU.Shares`.
 	AnnotationContainerProcessorWeight = "io.microsoft.container.processor.weight"
+	// AnnotationContainerMaxExecs limits the number of concurrently running
+	// execs in the container, not including the init process. If omitted (or
+	// 0) there is no limit.
+	AnnotationContainerMaxExecs = "io.microsoft.container.exec.maxcount"
+	// AnnotationContainerExecProcessorMaximum limits the CPU time of the
+	// process tree of each exec, not including the init process, in 1/100ths
+	// of a percent of all host processors in the range 1 to 10000. Only
+	// supported for process isolated WCOW.
+	AnnotationContainerExecProcessorMaximum = "io.microsoft.container.exec.processor.maximum"
+	// AnnotationContainerExecMemoryCommitLimitInMB limits the memory committed
+	// by the process tree of each exec, not including the init process. Only
+	// supported for process isolated WCOW.
+	AnnotationContainerExecMemoryCommitLimitInMB = "io.microsoft.container.exec.memory.commitlimitinmb"
+	// AnnotationContainerExecProcessCount limits the number of active
+	// processes in the process tree of each exec, not including the init
+	// process. Only supported for process isolated WCOW.
+	AnnotationContainerExecProcessCount = "io.microsoft.container.exec.processcount"
+	// AnnotationContainerExecDrainTimeoutInMs is the amount of time after the
+	// init process exits that the shim waits for running execs to exit before
+	// the container is shut down, killing them. If omitted (or 0) the
+	// container is shut down as soon as the init process exits. Only
+	// supported for WCOW.
+	AnnotationContainerExecDrainTimeoutInMs = "io.microsoft.container.exec.draintimeoutms"
+	// AnnotationContainerSCSIQoSBandwidthMaximum limits the bandwidth in bytes
+	// per second of each SCSI disk attached to the UVM for the container. This
+	// applies to the container scratch and any disk mounts.
+	AnnotationContainerSCSIQoSBandwidthMaximum = "io.microsoft.container.storage.scsi.qos.bandwidthmaximum"
+	// AnnotationContainerSCSIQoSIopsMaximum limits the IOPS of each SCSI disk
+	// attached to the UVM for the container. This applies to the container
+	// scratch and any disk mounts.
+	AnnotationContainerSCSIQoSIopsMaximum = "io.microsoft.container.storage.scsi.qos.iopsmaximum"
+	// AnnotationContainerPlan9Msize sets the maximum 9p message size in bytes
+	// of the Plan9 shares added to an LCOW UVM for the container.
+	AnnotationContainerPlan9Msize = "io.microsoft.container.storage.plan9.msize"
+	// AnnotationContainerPlan9CacheMode sets the guest cache mode of the Plan9
+	// shares added to an LCOW UVM for the container. One of `none`, `loose`,
+	// `fscache` or `mmap`.
+	AnnotationContainerPlan9CacheMode = "io.microsoft.container.storage.plan9.cachemode"
+	// AnnotationContainerPlan9SecurityModel sets the security model of the
+	// Plan9 shares added to an LCOW UVM for the container. One of `mapped` or
+	// `none`.
+	AnnotationContainerPlan9SecurityModel = "io.microsoft.container.storage.plan9.securitymodel"
+	// AnnotationContainerVSMBNoDirectmap disables direct mapping of files in
+	// the VSMB shares of the mounts of a WCOW container. Direct mapping causes
+	// file locking problems for some workloads.
+	AnnotationContainerVSMBNoDirectmap = "io.microsoft.container.storage.vsmb.nodirectmap"
+	// AnnotationContainerVSMBPseudoOplocks enables pseudo-oplocks on the VSMB
+	// shares of the mounts of a WCOW container.
+	AnnotationContainerVSMBPseudoOplocks = "io.microsoft.container.storage.vsmb.pseudooplocks"
+	// AnnotationContainerVSMBTakeBackupPrivilege acquires the backup privilege
+	// when opening files in the VSMB shares of the mounts of a WCOW container.
+	AnnotationContainerVSMBTakeBackupPrivilege = "io.microsoft.container.storage.vsmb.takebackupprivilege"
+	// AnnotationContainerVSMBCacheIO overrides whether opens in the VSMB
+	// shares of the mounts of a WCOW container use cached I/O. Defaults to
+	// `true` for read-only mounts.
+	AnnotationContainerVSMBCacheIO = "io.microsoft.container.storage.vsmb.cacheio"
+	// AnnotationContainerVSMBShareRead overrides whether exclusive access to
+	// files in the VSMB shares of the mounts of a WCOW container is converted
+	// to shared read access. Defaults to `true` for read-only mounts.
+	AnnotationContainerVSMBShareRead = "io.microsoft.container.storage.vsmb.shareread"
+	// AnnotationContainerVirtualTPM exposes the virtual TPM of the UVM to the
+	// container as `/dev/tpm0`. The UVM MUST be created with a virtual TPM.
+	// Only supported for LCOW.
+	AnnotationContainerVirtualTPM = "io.microsoft.container.devices.virtualtpm"
+	// AnnotationContainerLCOWInit runs the init process of an LCOW container
+	// under a minimal init provided by the UVM that reaps zombie processes.
+	AnnotationContainerLCOWInit = "io.microsoft.container.lcow.init"
+	// AnnotationContainerEventLogChannels is a comma separated list of event
+	// log channels, such as `Application,System`, in a WCOW container whose
+	// entries are forwarded to the shim log.
+	AnnotationContainerEventLogChannels = "io.microsoft.container.eventlog.channels"
+	// AnnotationContainerVPCIDevices is a comma separated list of host device
+	// instance paths to assign to the UVM of a hypervisor isolated container
+	// over virtual PCI. This is the only way to request a device for an LCOW
+	// container as the Windows section of its spec is not otherwise used.
+	AnnotationContainerVPCIDevices = "io.microsoft.container.devices.vpci"
+	// AnnotationContainerLayerRootDigests is a comma separated list of the
+	// dm-verity root digests returned when the read-only layers of an LCOW
+	// container were converted, in the order of its layer folders. The layers
+	// are verified against them in a UVM with layer integrity enabled, so
+	// they MUST come from a trusted source such as the snapshotter.
+	AnnotationContainerLayerRootDigests = "io.microsoft.container.storage.layer.rootdigests"
+	// AnnotationContainerHTTPProxy sets the `HTTP_PROXY` environment of the
+	// container process if it is not already set.
+	AnnotationContainerHTTPProxy = "io.microsoft.container.proxy.http"
+	// AnnotationContainerHTTPSProxy sets the `HTTPS_PROXY` environment of the
+	// container process if it is not already set.
+	AnnotationContainerHTTPSProxy = "io.microsoft.container.proxy.https"
+	// AnnotationContainerNoProxy sets the `NO_PROXY` environment of the
+	// container process if it is not already set.
+	AnnotationContainerNoProxy = "io.microsoft.container.proxy.noproxy"
+	// AnnotationContainerCACertificates is the host path of a PEM bundle of CA
+	// certificates to trust in an LCOW container. The bundle is mounted read
+	// only into the container and `SSL_CERT_FILE` is set to its path, so it
+	// replaces the default trust store of the image and should contain every
+	// CA to be trusted.
+	AnnotationContainerCACertificates = "io.microsoft.container.cacertificates"
 	// AnnotationContainerStorageQoSBandwidthMaximum overrides the container
 	// storage bandwidth per second set via the OCI spec.
 	//
@@ -113,6 +216,92 @@ const (
 	annotationBootFilesRootPath          = "io.microsoft.virtualmachine.lcow.bootfilesrootpath"
 	annotationStorageQoSBandwidthMaximum = "io.microsoft.virtualmachine.storageqos.bandwidthmaximum"
 	annotationStorageQoSIopsMaximum      = "io.microsoft.virtualmachine.storageqos.iopsmaximum"
+	// annotationVMBackend selects the registered virtualization backend that
+	// the compute systems for the spec are created on. If omitted the local
+	// vmcompute service is used.
+	annotationVMBackend = "io.microsoft.virtualmachine.backend"
+	// annotationVirtualTPM attaches a virtual TPM to the UVM. The TPM state is
+	// transient.
+	annotationVirtualTPM = "io.microsoft.virtualmachine.devices.virtualtpm.enabled"
+	// annotationConsole attaches the serial console of an LCOW UVM to the
+	// named pipe returned by `uvm.ConsolePipePath`. The console can then be
+	// accessed via shimdiag even if the GCS is unresponsive.
+	//
+	// Note: When the console is attached the UVM is not terminated on a
+	// kernel panic so that it can be inspected.
+	annotationConsole = "io.microsoft.virtualmachine.lcow.console"
+	// annotationVPMemMultiMapping maps multiple read-only layers of an LCOW
+	// UVM into each VPMem device at different offsets so that deep images do
+	// not exhaust the VPMem devices.
+	annotationVPMemMultiMapping = "io.microsoft.virtualmachine.devices.virtualpmem.multimapping"
+	// annotationCPUGroupID assigns the UVM to the host CPU group with this ID.
+	annotationCPUGroupID = "io.microsoft.virtualmachine.cpugroup.id"
+	// annotationCPUGroupLogicalProcessors is a comma separated list of host
+	// logical processors. If set the CPU group `annotationCPUGroupID` is
+	// created with these processors for the UVM and deleted with it.
+	annotationCPUGroupLogicalProcessors = "io.microsoft.virtualmachine.cpugroup.logicalprocessors"
+	// annotationCPUGroupCap caps the processor time of the CPU group created
+	// via `annotationCPUGroupLogicalProcessors`. The cap allows values 1 -
+	// 65,536 where 65,536 means all of the processor time of the group.
+	annotationCPUGroupCap = "io.microsoft.virtualmachine.cpugroup.cap"
+	// annotationSaveAsTemplate saves the WCOW UVM of a pod as a template once
+	// it has started. The pod cannot run any workload containers but its UVM
+	// can be cloned via `annotationTemplateID`.
+	annotationSaveAsTemplate = "io.microsoft.virtualmachine.saveastemplate"
+	// annotationTemplateID creates the WCOW UVM of a pod as a clone of the
+	// template UVM with this ID rather than booting it.
+	annotationTemplateID = "io.microsoft.virtualmachine.templateid"
+	// annotationRestoreStateFile creates the WCOW UVM of a pod by restoring
+	// the state saved to this file rather than booting it.
+	annotationRestoreStateFile = "io.microsoft.virtualmachine.restorestatefile"
+	// annotationKernelBootOptions are additional options appended to the
+	// kernel command line of an LCOW UVM, such as `console=` or cgroup
+	// options.
+	annotationKernelBootOptions = "io.microsoft.virtualmachine.lcow.kernelbootoptions"
+	// annotationKernelDirectBoot boots an LCOW UVM directly into its kernel
+	// rather than via UEFI. Defaults to `true` on builds that support it.
+	annotationKernelDirectBoot = "io.microsoft.virtualmachine.lcow.kerneldirectboot"
+	// annotationNumaNodeCount exposes this many virtual NUMA nodes to the UVM
+	// rather than a flat topology. Requires physically backed memory via
+	// `annotationAllowOvercommit=false`.
+	annotationNumaNodeCount = "io.microsoft.virtualmachine.computetopology.numa.nodecount"
+	// annotationNumaMemorySizePerNodeInMB is the maximum memory of each
+	// virtual NUMA node set via `annotationNumaNodeCount`.
+	annotationNumaMemorySizePerNodeInMB = "io.microsoft.virtualmachine.computetopology.numa.memorysizepernodeinmb"
+	// annotationSCSIControllerCount is the number of SCSI controllers of the
+	// UVM, each of which can attach 64 disks. Defaults to 1.
+	annotationSCSIControllerCount = "io.microsoft.virtualmachine.devices.scsi.controllercount"
+	// annotationEnableHotHint lets the guest hint which of its memory is in
+	// use so that it is backed first. Defaults to `true` for WCOW. Ignored
+	// for physically backed memory.
+	annotationEnableHotHint = "io.microsoft.virtualmachine.computetopology.memory.enablehothint"
+	// annotationEnableColdHint lets the guest hint which of its memory is
+	// free so that it can be trimmed. Ignored for physically backed memory.
+	annotationEnableColdHint = "io.microsoft.virtualmachine.computetopology.memory.enablecoldhint"
+	// annotationEncryptedScratch encrypts the scratch disks of the containers
+	// in an LCOW UVM with dm-crypt using a key generated by the guest for each
+	// boot, so that their writable data is encrypted at rest without host
+	// disk encryption.
+	annotationEncryptedScratch = "io.microsoft.virtualmachine.storage.scratch.encrypted"
+	// annotationLayerIntegrity verifies the read-only layers attached to an
+	// LCOW UVM with dm-verity in the guest. The layers MUST have been
+	// converted with a dm-verity hash device and the containers MUST set
+	// `AnnotationContainerLayerRootDigests`.
+	annotationLayerIntegrity = "io.microsoft.virtualmachine.storage.layer.integrity"
+	// annotationScratchDiscard mounts the scratch disks of the containers in
+	// an LCOW UVM with `discard` so that deleted container data shrinks the
+	// scratch VHDX on the host. It cannot be used with
+	// `annotationEncryptedScratch`.
+	annotationScratchDiscard = "io.microsoft.virtualmachine.storage.scratch.discard"
+	// annotationScratchFilesystem is the filesystem, such as `xfs`, the
+	// scratch disks of the containers in an LCOW UVM were formatted with so
+	// that the guest mounts them as such.
+	annotationScratchFilesystem = "io.microsoft.virtualmachine.storage.scratch.filesystem"
+	// annotationLayerAttachmentPolicy controls how the read-only container
+	// layers are attached to the UVM. LCOW supports `vpmem`, which falls back
+	// to SCSI, and `scsi`. WCOW layers are always shared over VSMB and it
+	// MUST NOT be set.
+	annotationLayerAttachmentPolicy = "io.microsoft.virtualmachine.storage.layer.attachmentpolicy"
 )
 
 // parseAnnotationsBool searches `a` for `key` and if found verifies that the
@@ -220,6 +409,142 @@ func ParseAnnotationsStorageBps(s *specs.Spec, annotation string, def int32) int
 	return def
 }
 
+// ParseAnnotationsSCSIQoS searches `s.Annotations` for the SCSI QoS
+// annotations. If neither is set returns `nil`.
+func ParseAnnotationsSCSIQoS(s *specs.Spec) *hcsschema.StorageQoS {
+	qos := &hcsschema.StorageQoS{
+		IopsMaximum:      parseAnnotationsInt32(s.Annotations, AnnotationContainerSCSIQoSIopsMaximum, 0),
+		BandwidthMaximum: parseAnnotationsInt32(s.Annotations, AnnotationContainerSCSIQoSBandwidthMaximum, 0),
+	}
+	if qos.IopsMaximum == 0 && qos.BandwidthMaximum == 0 {
+		return nil
+	}
+	return qos
+}
+
+// ParseAnnotationsPlan9Options searches `s.Annotations` for the Plan9
+// annotations. If none are set returns `nil`.
+func ParseAnnotationsPlan9Options(s *specs.Spec) *uvm.Plan9Options {
+	o := &uvm.Plan9Options{
+		Msize:         parseAnnotationsUint32(s.Annotations, AnnotationContainerPlan9Msize, 0),
+		CacheMode:     parseAnnotationsString(s.Annotations, AnnotationContainerPlan9CacheMode, ""),
+		SecurityModel: parseAnnotationsString(s.Annotations, AnnotationContainerPlan9SecurityModel, ""),
+	}
+	if *o == (uvm.Plan9Options{}) {
+		return nil
+	}
+	return o
+}
+
+// ParseAnnotationsVSMBOptions returns the options to share a mount of a WCOW
+// container over VSMB. The defaults for `readOnly` are overridden by any of the
+// VSMB annotations found in `s.Annotations`.
+func ParseAnnotationsVSMBOptions(s *specs.Spec, readOnly bool) *hcsschema.VirtualSmbShareOptions {
+	o := uvm.DefaultVSMBOptions(readOnly)
+	o.NoDirectmap = parseAnnotationsBool(s.Annotations, AnnotationContainerVSMBNoDirectmap, o.NoDirectmap)
+	o.PseudoOplocks = parseAnnotationsBool(s.Annotations, AnnotationContainerVSMBPseudoOplocks, o.PseudoOplocks)
+	o.TakeBackupPrivilege = parseAnnotationsBool(s.Annotations, AnnotationContainerVSMBTakeBackupPrivilege, o.TakeBackupPrivilege)
+	o.CacheIo = parseAnnotationsBool(s.Annotations, AnnotationContainerVSMBCacheIO, o.CacheIo)
+	o.ShareRead = parseAnnotationsBool(s.Annotations, AnnotationContainerVSMBShareRead, o.ShareRead)
+	return o
+}
+
+// ParseAnnotationsVirtualTPM searches `s.Annotations` for the container virtual
+// TPM annotation. If not found returns `false`.
+func ParseAnnotationsVirtualTPM(s *specs.Spec) bool {
+	return parseAnnotationsBool(s.Annotations, AnnotationContainerVirtualTPM, false)
+}
+
+// ParseAnnotationsLCOWInit searches `s.Annotations` for the LCOW init
+// annotation. If not found returns `false`.
+func ParseAnnotationsLCOWInit(s *specs.Spec) bool {
+	return parseAnnotationsBool(s.Annotations, AnnotationContainerLCOWInit, false)
+}
+
+// ParseAnnotationsProxyEnv searches `s.Annotations` for the proxy annotations
+// and returns the environment they set in `KEY=value` form. If none are set
+// returns `nil`.
+func ParseAnnotationsProxyEnv(s *specs.Spec) []string {
+	var env []string
+	for _, p := range []struct{ key, annotation string }{
+		{"HTTP_PROXY", AnnotationContainerHTTPProxy},
+		{"HTTPS_PROXY", AnnotationContainerHTTPSProxy},
+		{"NO_PROXY", AnnotationContainerNoProxy},
+	} {
+		if v := parseAnnotationsString(s.Annotations, p.annotation, ""); v != "" {
+			env = append(env, p.key+"="+v)
+		}
+	}
+	return env
+}
+
+// ParseAnnotationsCACertificates searches `s.Annotations` for the CA
+// certificates annotation. If not found returns "".
+func ParseAnnotationsCACertificates(s *specs.Spec) string {
+	return parseAnnotationsString(s.Annotations, AnnotationContainerCACertificates, "")
+}
+
+// ParseAnnotationsEventLogChannels searches `s.Annotations` for the event log
+// channels annotation. If not found returns `nil`.
+func ParseAnnotationsEventLogChannels(s *specs.Spec) []string {
+	var channels []string
+	for _, c := range strings.Split(parseAnnotationsString(s.Annotations, AnnotationContainerEventLogChannels, ""), ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			channels = append(channels, c)
+		}
+	}
+	return channels
+}
+
+// ParseAnnotationsVPCIDevices searches `s.Annotations` for the virtual PCI
+// devices annotation. If not found returns `nil`.
+func ParseAnnotationsVPCIDevices(s *specs.Spec) []string {
+	var devices []string
+	for _, d := range strings.Split(parseAnnotationsString(s.Annotations, AnnotationContainerVPCIDevices, ""), ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			devices = append(devices, d)
+		}
+	}
+	return devices
+}
+
+// ParseAnnotationsLayerRootDigests searches `s.Annotations` for the layer root
+// digests annotation. If not found returns `nil`.
+func ParseAnnotationsLayerRootDigests(s *specs.Spec) []string {
+	var digests []string
+	for _, d := range strings.Split(parseAnnotationsString(s.Annotations, AnnotationContainerLayerRootDigests, ""), ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			digests = append(digests, d)
+		}
+	}
+	return digests
+}
+
+// parseAnnotationsCPUGroup searches `a` for the CPU group annotations and
+// applies them to `opts`.
+func parseAnnotationsCPUGroup(a map[string]string, opts *uvm.Options) error {
+	opts.CPUGroupID = parseAnnotationsString(a, annotationCPUGroupID, opts.CPUGroupID)
+	opts.CPUGroupCap = parseAnnotationsUint32(a, annotationCPUGroupCap, opts.CPUGroupCap)
+	if v := parseAnnotationsString(a, annotationCPUGroupLogicalProcessors, ""); v != "" {
+		var lps []uint32
+		for _, p := range strings.Split(v, ",") {
+			lp, err := strconv.ParseUint(strings.TrimSpace(p), 10, 32)
+			if err != nil {
+				return fmt.Errorf("annotation '%s' must be a comma separated list of logical processors: %s", annotationCPUGroupLogicalProcessors, err)
+			}
+			lps = append(lps, uint32(lp))
+		}
+		opts.CPUGroupLogicalProcessors = lps
+	}
+	return nil
+}
+
+// ParseAnnotationsSaveAsTemplate searches `s.Annotations` for the save as
+// template annotation. If not found returns `false`.
+func ParseAnnotationsSaveAsTemplate(s *specs.Spec) bool {
+	return parseAnnotationsBool(s.Annotations, annotationSaveAsTemplate, false)
+}
+
 // ParseAnnotationsMemory searches `s.Annotations` for the memory annotation. If
 // not found searches `s` for the Windows memory section. If neither are found
 // returns `def`.
@@ -258,6 +583,24 @@ func parseAnnotationsPreferredRootFSType(a map[string]string, key string, def uv
 	return def
 }
 
+// parseAnnotationsInt32 searches `a` for `key` and if found verifies that the
+// value is a 32 bit signed integer. If `key` is not found returns `def`.
+func parseAnnotationsInt32(a map[string]string, key string, def int32) int32 {
+	if v, ok := a[key]; ok {
+		counti, err := strconv.ParseInt(v, 10, 32)
+		if err == nil {
+			return int32(counti)
+		}
+		logrus.WithFields(logrus.Fields{
+			logfields.OCIAnnotation: key,
+			logfields.Value:         v,
+			logfields.ExpectedType:  logfields.Int32,
+			logrus.ErrorKey:         err,
+		}).Warning("annotation could not be parsed")
+	}
+	return def
+}
+
 // parseAnnotationsUint32 searches `a` for `key` and if found verifies that the
 // value is a 32 bit unsigned integer. If `key` is not found returns `def`.
 func parseAnnotationsUint32(a map[string]string, key string, def uint32) uint32 {
@@ -303,24 +646,75 @@ func parseAnnotationsString(a map[string]string, key string, def string) string
 	return def
 }
 
+// ParseAnnotationsBackend searches `s.Annotations` for the VM backend
+// annotation and returns the registered backend it names. If the annotation is
+// not found returns the default backend.
+func ParseAnnotationsBackend(s *specs.Spec) (cow.Backend, error) {
+	return backend.Get(parseAnnotationsString(s.Annotations, annotationVMBackend, ""))
+}
+
+// ParseAnnotationsMaxExecs searches `s.Annotations` for the max execs
+// annotation. If not found returns 0 meaning no limit.
+func ParseAnnotationsMaxExecs(s *specs.Spec) uint32 {
+	return parseAnnotationsUint32(s.Annotations, AnnotationContainerMaxExecs, 0)
+}
+
+// ParseAnnotationsExecLimits searches `s.Annotations` for the exec limit
+// annotations and returns the job object limits to apply to the process tree
+// of each exec. Limits that are not found are left zero meaning no limit.
+func ParseAnnotationsExecLimits(s *specs.Spec) jobobject.Limits {
+	limits := jobobject.Limits{
+		CPURate:      parseAnnotationsUint32(s.Annotations, AnnotationContainerExecProcessorMaximum, 0),
+		MaxCommit:    parseAnnotationsUint64(s.Annotations, AnnotationContainerExecMemoryCommitLimitInMB, 0) * 1024 * 1024,
+		MaxProcesses: parseAnnotationsUint32(s.Annotations, AnnotationContainerExecProcessCount, 0),
+	}
+	if limits.CPURate > jobobject.CPURateMax {
+		limits.CPURate = jobobject.CPURateMax
+	}
+	return limits
+}
+
+// ParseAnnotationsExecDrainTimeout searches `s.Annotations` for the exec
+// drain timeout annotation. If not found returns 0 meaning execs are not
+// drained.
+func ParseAnnotationsExecDrainTimeout(s *specs.Spec) time.Duration {
+	return time.Duration(parseAnnotationsUint32(s.Annotations, AnnotationContainerExecDrainTimeoutInMs, 0)) * time.Millisecond
+}
+
 // SpecToUVMCreateOpts parses `s` and returns either `*uvm.OptionsLCOW` or
 // `*uvm.OptionsWCOW`.
 func SpecToUVMCreateOpts(s *specs.Spec, id, owner string) (interface{}, error) {
 	if !IsIsolated(s) {
 		return nil, errors.New("cannot create UVM opts for non-isolated spec")
 	}
+	b, err := ParseAnnotationsBackend(s)
+	if err != nil {
+		return nil, err
+	}
 	if IsLCOW(s) {
 		lopts := uvm.NewDefaultOptionsLCOW(id, owner)
+		lopts.Backend = b
 		lopts.MemorySizeInMB = ParseAnnotationsMemory(s, annotationMemorySizeInMB, lopts.MemorySizeInMB)
 		lopts.AllowOvercommit = parseAnnotationsBool(s.Annotations, annotationAllowOvercommit, lopts.AllowOvercommit)
 		lopts.EnableDeferredCommit = parseAnnotationsBool(s.Annotations, annotationEnableDeferredCommit, lopts.EnableDeferredCommit)
+		lopts.EnableHotHint = parseAnnotationsBool(s.Annotations, annotationEnableHotHint, lopts.EnableHotHint)
+		lopts.EnableColdHint = parseAnnotationsBool(s.Annotations, annotationEnableColdHint, lopts.EnableColdHint)
 		lopts.ProcessorCount = ParseAnnotationsCPUCount(s, annotationProcessorCount, lopts.ProcessorCount)
 		lopts.ProcessorLimit = ParseAnnotationsCPULimit(s, annotationProcessorLimit, lopts.ProcessorLimit)
 		lopts.ProcessorWeight = ParseAnnotationsCPUWeight(s, annotationProcessorWeight, lopts.ProcessorWeight)
+		lopts.NumaNodeCount = parseAnnotationsUint32(s.Annotations, annotationNumaNodeCount, lopts.NumaNodeCount)
+		lopts.NumaMemorySizePerNodeInMB = parseAnnotationsUint64(s.Annotations, annotationNumaMemorySizePerNodeInMB, lopts.NumaMemorySizePerNodeInMB)
+		lopts.SCSIControllerCount = parseAnnotationsUint32(s.Annotations, annotationSCSIControllerCount, lopts.SCSIControllerCount)
+		lopts.LayerAttachmentPolicy = uvm.LayerAttachmentPolicy(parseAnnotationsString(s.Annotations, annotationLayerAttachmentPolicy, string(lopts.LayerAttachmentPolicy)))
 		lopts.VPMemDeviceCount = parseAnnotationsUint32(s.Annotations, annotationVPMemCount, lopts.VPMemDeviceCount)
 		lopts.VPMemSizeBytes = parseAnnotationsUint64(s.Annotations, annotationVPMemSize, lopts.VPMemSizeBytes)
+		lopts.VPMemMultiMapping = parseAnnotationsBool(s.Annotations, annotationVPMemMultiMapping, lopts.VPMemMultiMapping)
 		lopts.StorageQoSBandwidthMaximum = ParseAnnotationsStorageBps(s, annotationStorageQoSBandwidthMaximum, lopts.StorageQoSBandwidthMaximum)
 		lopts.StorageQoSIopsMaximum = ParseAnnotationsStorageIops(s, annotationStorageQoSIopsMaximum, lopts.StorageQoSIopsMaximum)
+		// The default boot files depend on the boot method and the files
+		// present in the boot files path.
+		lopts.KernelDirect = parseAnnotationsBool(s.Annotations, annotationKernelDirectBoot, lopts.KernelDirect)
+		lopts.UpdateBootFilesPath(parseAnnotationsString(s.Annotations, annotationBootFilesRootPath, lopts.BootFilesPath))
 		lopts.PreferredRootFSType = parseAnnotationsPreferredRootFSType(s.Annotations, annotationPreferredRootFSType, lopts.PreferredRootFSType)
 		switch lopts.PreferredRootFSType {
 		case uvm.PreferredRootFSTypeInitRd:
@@ -328,18 +722,52 @@ func SpecToUVMCreateOpts(s *specs.Spec, id, owner string) (interface{}, error) {
 		case uvm.PreferredRootFSTypeVHD:
 			lopts.RootFSFile = uvm.VhdFile
 		}
-		lopts.BootFilesPath = parseAnnotationsString(s.Annotations, annotationBootFilesRootPath, lopts.BootFilesPath)
+		lopts.KernelBootOptions = parseAnnotationsString(s.Annotations, annotationKernelBootOptions, lopts.KernelBootOptions)
+		lopts.EnableVirtualTPM = parseAnnotationsBool(s.Annotations, annotationVirtualTPM, lopts.EnableVirtualTPM)
+		lopts.EnableScratchEncryption = parseAnnotationsBool(s.Annotations, annotationEncryptedScratch, lopts.EnableScratchEncryption)
+		lopts.EnableLayerIntegrity = parseAnnotationsBool(s.Annotations, annotationLayerIntegrity, lopts.EnableLayerIntegrity)
+		lopts.EnableScratchDiscard = parseAnnotationsBool(s.Annotations, annotationScratchDiscard, lopts.EnableScratchDiscard)
+		lopts.ScratchFilesystem = parseAnnotationsString(s.Annotations, annotationScratchFilesystem, lopts.ScratchFilesystem)
+		if parseAnnotationsBool(s.Annotations, annotationConsole, false) {
+			lopts.ConsolePipe = uvm.ConsolePipePath(id)
+		}
+		if err := parseAnnotationsCPUGroup(s.Annotations, lopts.Options); err != nil {
+			return nil, err
+		}
+		for _, a := range []string{annotationTemplateID, annotationRestoreStateFile} {
+			if _, ok := s.Annotations[a]; ok {
+				return nil, fmt.Errorf("annotation '%s' is only supported for WCOW", a)
+			}
+		}
 		return lopts, nil
 	} else if IsWCOW(s) {
 		wopts := uvm.NewDefaultOptionsWCOW(id, owner)
+		wopts.Backend = b
 		wopts.MemorySizeInMB = ParseAnnotationsMemory(s, annotationMemorySizeInMB, wopts.MemorySizeInMB)
 		wopts.AllowOvercommit = parseAnnotationsBool(s.Annotations, annotationAllowOvercommit, wopts.AllowOvercommit)
 		wopts.EnableDeferredCommit = parseAnnotationsBool(s.Annotations, annotationEnableDeferredCommit, wopts.EnableDeferredCommit)
+		wopts.EnableHotHint = parseAnnotationsBool(s.Annotations, annotationEnableHotHint, wopts.EnableHotHint)
+		wopts.EnableColdHint = parseAnnotationsBool(s.Annotations, annotationEnableColdHint, wopts.EnableColdHint)
 		wopts.ProcessorCount = ParseAnnotationsCPUCount(s, annotationProcessorCount, wopts.ProcessorCount)
 		wopts.ProcessorLimit = ParseAnnotationsCPULimit(s, annotationProcessorLimit, wopts.ProcessorLimit)
 		wopts.ProcessorWeight = ParseAnnotationsCPUWeight(s, annotationProcessorWeight, wopts.ProcessorWeight)
+		wopts.NumaNodeCount = parseAnnotationsUint32(s.Annotations, annotationNumaNodeCount, wopts.NumaNodeCount)
+		wopts.NumaMemorySizePerNodeInMB = parseAnnotationsUint64(s.Annotations, annotationNumaMemorySizePerNodeInMB, wopts.NumaMemorySizePerNodeInMB)
+		wopts.SCSIControllerCount = parseAnnotationsUint32(s.Annotations, annotationSCSIControllerCount, wopts.SCSIControllerCount)
+		wopts.LayerAttachmentPolicy = uvm.LayerAttachmentPolicy(parseAnnotationsString(s.Annotations, annotationLayerAttachmentPolicy, string(wopts.LayerAttachmentPolicy)))
 		wopts.StorageQoSBandwidthMaximum = ParseAnnotationsStorageBps(s, annotationStorageQoSBandwidthMaximum, wopts.StorageQoSBandwidthMaximum)
 		wopts.StorageQoSIopsMaximum = ParseAnnotationsStorageIops(s, annotationStorageQoSIopsMaximum, wopts.StorageQoSIopsMaximum)
+		wopts.EnableVirtualTPM = parseAnnotationsBool(s.Annotations, annotationVirtualTPM, wopts.EnableVirtualTPM)
+		if err := parseAnnotationsCPUGroup(s.Annotations, wopts.Options); err != nil {
+			return nil, err
+		}
+		for _, a := range []string{annotationEncryptedScratch, annotationLayerIntegrity, annotationScratchDiscard, annotationScratchFilesystem} {
+			if _, ok := s.Annotations[a]; ok {
+				return nil, fmt.Errorf("annotation '%s' is only supported for LCOW", a)
+			}
+		}
+		wopts.TemplateID = parseAnnotationsString(s.Annotations, annotationTemplateID, wopts.TemplateID)
+		wopts.SaveStateFilePath = parseAnnotationsString(s.Annotations, annotationRestoreStateFile, wopts.SaveStateFilePath)
 		return wopts, nil
 	}
 	return nil, errors.New("cannot create UVM opts spec is not LCOW or WCOW")
@@ -348,9 +776,60 @@ func SpecToUVMCreateOpts(s *specs.Spec, id, owner string) (interface{}, error) {
 // UpdateSpecFromOptions sets extra annotations on the OCI spec based on the
 // `opts` struct.
 func UpdateSpecFromOptions(s specs.Spec, opts *runhcsopts.Options) specs.Spec {
+	// Filter first so the annotations set from `opts` are always honored.
+	if opts != nil && len(opts.AllowedAnnotations) > 0 {
+		filterAnnotations(s.Annotations, opts.AllowedAnnotations)
+	}
+
+	if opts != nil &&
+		opts.SandboxIsolation == runhcsopts.Options_HYPERVISOR &&
+		IsWCOW(&s) &&
+		s.Windows.HyperV == nil {
+		s.Windows.HyperV = &specs.WindowsHyperV{}
+	}
+
 	if opts != nil && opts.BootFilesRootPath != "" {
 		s.Annotations[annotationBootFilesRootPath] = opts.BootFilesRootPath
 	}
 
+	if opts != nil && opts.MaxExecsPerTask > 0 {
+		if _, ok := s.Annotations[AnnotationContainerMaxExecs]; !ok {
+			s.Annotations[AnnotationContainerMaxExecs] = strconv.FormatInt(int64(opts.MaxExecsPerTask), 10)
+		}
+	}
+
+	if opts != nil && opts.VmBackend != "" {
+		if _, ok := s.Annotations[annotationVMBackend]; !ok {
+			s.Annotations[annotationVMBackend] = opts.VmBackend
+		}
+	}
+
 	return s
 }
+
+// microsoftAnnotationPrefix is the prefix of the annotations subject to
+// `filterAnnotations`.
+const microsoftAnnotationPrefix = "io.microsoft."
+
+// filterAnnotations removes every `io.microsoft.*` annotation from `a` that is
+// not in `allowed`. An entry in `allowed` ending in `*` matches every
+// annotation with that prefix.
+func filterAnnotations(a map[string]string, allowed []string) {
+	for k := range a {
+		if !strings.HasPrefix(k, microsoftAnnotationPrefix) {
+			continue
+		}
+		ok := false
+		for _, pattern := range allowed {
+			if pattern == k ||
+				(strings.HasSuffix(pattern, "*") && strings.HasPrefix(k, strings.TrimSuffix(pattern, "*"))) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			logrus.WithField(logfields.OCIAnnotation, k).Warning("removing annotation not in the allowed annotations")
+			delete(a, k)
+		}
+	}
+}
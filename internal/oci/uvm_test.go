@@ -0,0 +1,625 @@
+package oci
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	runhcsopts "github.com/Microsoft/hcsshim/cmd/containerd-shim-runhcs-v1/options"
+	"github.com/Microsoft/hcsshim/internal/jobobject"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func Test_ParseAnnotationsSCSIQoS_None(t *testing.T) {
+	s := &specs.Spec{}
+	if qos := ParseAnnotationsSCSIQoS(s); qos != nil {
+		t.Fatalf("expected nil QoS, got: %+v", qos)
+	}
+}
+
+func Test_ParseAnnotationsSCSIQoS_Valid(t *testing.T) {
+	s := &specs.Spec{
+		Annotations: map[string]string{
+			AnnotationContainerSCSIQoSIopsMaximum:      "100",
+			AnnotationContainerSCSIQoSBandwidthMaximum: "1048576",
+		},
+	}
+	qos := ParseAnnotationsSCSIQoS(s)
+	if qos == nil || qos.IopsMaximum != 100 || qos.BandwidthMaximum != 1048576 {
+		t.Fatalf("unexpected QoS: %+v", qos)
+	}
+}
+
+func Test_ParseAnnotationsSCSIQoS_Invalid(t *testing.T) {
+	s := &specs.Spec{
+		Annotations: map[string]string{
+			AnnotationContainerSCSIQoSIopsMaximum: "invalid",
+		},
+	}
+	if qos := ParseAnnotationsSCSIQoS(s); qos != nil {
+		t.Fatalf("expected nil QoS, got: %+v", qos)
+	}
+}
+
+func Test_ParseAnnotationsSCSIQoS_Overflow(t *testing.T) {
+	s := &specs.Spec{
+		Annotations: map[string]string{
+			AnnotationContainerSCSIQoSIopsMaximum:      "100",
+			AnnotationContainerSCSIQoSBandwidthMaximum: "4294967296",
+		},
+	}
+	qos := ParseAnnotationsSCSIQoS(s)
+	if qos == nil || qos.IopsMaximum != 100 || qos.BandwidthMaximum != 0 {
+		t.Fatalf("expected overflowing bandwidth to be ignored, got: %+v", qos)
+	}
+}
+
+func Test_ParseAnnotationsPlan9Options_None(t *testing.T) {
+	s := &specs.Spec{}
+	if o := ParseAnnotationsPlan9Options(s); o != nil {
+		t.Fatalf("expected nil options, got: %+v", o)
+	}
+}
+
+func Test_ParseAnnotationsPlan9Options_Valid(t *testing.T) {
+	s := &specs.Spec{
+		Annotations: map[string]string{
+			AnnotationContainerPlan9Msize:         "262144",
+			AnnotationContainerPlan9CacheMode:     "loose",
+			AnnotationContainerPlan9SecurityModel: "none",
+		},
+	}
+	o := ParseAnnotationsPlan9Options(s)
+	if o == nil || o.Msize != 262144 || o.CacheMode != "loose" || o.SecurityModel != "none" {
+		t.Fatalf("unexpected options: %+v", o)
+	}
+}
+
+func Test_ParseAnnotationsVSMBOptions_Defaults(t *testing.T) {
+	s := &specs.Spec{}
+	o := ParseAnnotationsVSMBOptions(s, false)
+	if *o != (hcsschema.VirtualSmbShareOptions{}) {
+		t.Fatalf("expected default read-write options, got: %+v", o)
+	}
+	o = ParseAnnotationsVSMBOptions(s, true)
+	if !o.ReadOnly || !o.CacheIo || !o.ShareRead || o.NoDirectmap {
+		t.Fatalf("expected default read-only options, got: %+v", o)
+	}
+}
+
+func Test_ParseAnnotationsVSMBOptions_Overrides(t *testing.T) {
+	s := &specs.Spec{
+		Annotations: map[string]string{
+			AnnotationContainerVSMBNoDirectmap:         "true",
+			AnnotationContainerVSMBPseudoOplocks:       "true",
+			AnnotationContainerVSMBTakeBackupPrivilege: "true",
+			AnnotationContainerVSMBCacheIO:             "false",
+		},
+	}
+	o := ParseAnnotationsVSMBOptions(s, true)
+	if !o.ReadOnly || !o.NoDirectmap || !o.PseudoOplocks || !o.TakeBackupPrivilege || o.CacheIo || !o.ShareRead {
+		t.Fatalf("unexpected options: %+v", o)
+	}
+}
+
+func Test_SpecToUVMCreateOpts_VirtualTPM(t *testing.T) {
+	s := &specs.Spec{
+		Linux: &specs.Linux{},
+		Windows: &specs.Windows{
+			HyperV: &specs.WindowsHyperV{},
+		},
+		Annotations: map[string]string{
+			annotationVirtualTPM: "true",
+		},
+	}
+	opts, err := SpecToUVMCreateOpts(s, t.Name(), "")
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if !opts.(*uvm.OptionsLCOW).EnableVirtualTPM {
+		t.Fatal("expected virtual TPM to be enabled")
+	}
+}
+
+func Test_ParseAnnotationsVirtualTPM(t *testing.T) {
+	s := &specs.Spec{}
+	if ParseAnnotationsVirtualTPM(s) {
+		t.Fatal("expected virtual TPM to be disabled by default")
+	}
+	s.Annotations = map[string]string{AnnotationContainerVirtualTPM: "true"}
+	if !ParseAnnotationsVirtualTPM(s) {
+		t.Fatal("expected virtual TPM to be enabled")
+	}
+}
+
+func Test_SpecToUVMCreateOpts_Console(t *testing.T) {
+	s := &specs.Spec{
+		Linux: &specs.Linux{},
+		Annotations: map[string]string{
+			annotationConsole: "true",
+		},
+	}
+	opts, err := SpecToUVMCreateOpts(s, t.Name(), "")
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if p := opts.(*uvm.OptionsLCOW).ConsolePipe; p != uvm.ConsolePipePath(t.Name()) {
+		t.Fatalf("expected console pipe '%s', got: '%s'", uvm.ConsolePipePath(t.Name()), p)
+	}
+}
+
+func Test_SpecToUVMCreateOpts_TemplateID(t *testing.T) {
+	s := &specs.Spec{
+		Windows: &specs.Windows{
+			HyperV: &specs.WindowsHyperV{},
+		},
+		Annotations: map[string]string{
+			annotationTemplateID: "template@vm",
+		},
+	}
+	opts, err := SpecToUVMCreateOpts(s, t.Name(), "")
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if id := opts.(*uvm.OptionsWCOW).TemplateID; id != "template@vm" {
+		t.Fatalf("expected template 'template@vm', got: '%s'", id)
+	}
+}
+
+func Test_SpecToUVMCreateOpts_TemplateID_LCOW_Error(t *testing.T) {
+	s := &specs.Spec{
+		Linux: &specs.Linux{},
+		Annotations: map[string]string{
+			annotationTemplateID: "template@vm",
+		},
+	}
+	if _, err := SpecToUVMCreateOpts(s, t.Name(), ""); err == nil {
+		t.Fatal("expected error for an LCOW clone")
+	}
+}
+
+func Test_SpecToUVMCreateOpts_KernelBootOptions(t *testing.T) {
+	s := &specs.Spec{
+		Linux: &specs.Linux{},
+		Annotations: map[string]string{
+			annotationKernelBootOptions: "cgroup_no_v1=all debug",
+		},
+	}
+	opts, err := SpecToUVMCreateOpts(s, t.Name(), "")
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if o := opts.(*uvm.OptionsLCOW).KernelBootOptions; o != "cgroup_no_v1=all debug" {
+		t.Fatalf("expected kernel boot options 'cgroup_no_v1=all debug', got: '%s'", o)
+	}
+}
+
+func Test_SpecToUVMCreateOpts_Numa(t *testing.T) {
+	annotations := map[string]string{
+		annotationAllowOvercommit:           "false",
+		annotationNumaNodeCount:             "2",
+		annotationNumaMemorySizePerNodeInMB: "4096",
+	}
+	for _, s := range []*specs.Spec{
+		{Linux: &specs.Linux{}, Annotations: annotations},
+		{Windows: &specs.Windows{HyperV: &specs.WindowsHyperV{}}, Annotations: annotations},
+	} {
+		opts, err := SpecToUVMCreateOpts(s, t.Name(), "")
+		if err != nil {
+			t.Fatalf("expected nil error, got: %v", err)
+		}
+		options := uvmOptions(opts)
+		if options.NumaNodeCount != 2 || options.NumaMemorySizePerNodeInMB != 4096 {
+			t.Fatalf("unexpected NUMA options: %d nodes of %dMB", options.NumaNodeCount, options.NumaMemorySizePerNodeInMB)
+		}
+	}
+}
+
+func Test_SpecToUVMCreateOpts_SCSIControllerCount(t *testing.T) {
+	for _, s := range []*specs.Spec{
+		{Linux: &specs.Linux{}},
+		{Windows: &specs.Windows{HyperV: &specs.WindowsHyperV{}}},
+	} {
+		opts, err := SpecToUVMCreateOpts(s, t.Name(), "")
+		if err != nil {
+			t.Fatalf("expected nil error, got: %v", err)
+		}
+		if count := uvmOptions(opts).SCSIControllerCount; count != 1 {
+			t.Fatalf("expected 1 SCSI controller by default, got: %d", count)
+		}
+		s.Annotations = map[string]string{annotationSCSIControllerCount: "4"}
+		opts, err = SpecToUVMCreateOpts(s, t.Name(), "")
+		if err != nil {
+			t.Fatalf("expected nil error, got: %v", err)
+		}
+		if count := uvmOptions(opts).SCSIControllerCount; count != 4 {
+			t.Fatalf("expected 4 SCSI controllers, got: %d", count)
+		}
+	}
+}
+
+func Test_SpecToUVMCreateOpts_MemoryHints(t *testing.T) {
+	for _, s := range []*specs.Spec{
+		{Linux: &specs.Linux{}},
+		{Windows: &specs.Windows{HyperV: &specs.WindowsHyperV{}}},
+	} {
+		opts, err := SpecToUVMCreateOpts(s, t.Name(), "")
+		if err != nil {
+			t.Fatalf("expected nil error, got: %v", err)
+		}
+		options := uvmOptions(opts)
+		if expected := s.Windows != nil; options.EnableHotHint != expected || options.EnableColdHint {
+			t.Fatalf("expected hot hint %t and no cold hint by default, got: %t/%t", expected, options.EnableHotHint, options.EnableColdHint)
+		}
+		s.Annotations = map[string]string{
+			annotationEnableHotHint:  "false",
+			annotationEnableColdHint: "true",
+		}
+		opts, err = SpecToUVMCreateOpts(s, t.Name(), "")
+		if err != nil {
+			t.Fatalf("expected nil error, got: %v", err)
+		}
+		options = uvmOptions(opts)
+		if options.EnableHotHint || !options.EnableColdHint {
+			t.Fatalf("expected only the cold hint, got: %t/%t", options.EnableHotHint, options.EnableColdHint)
+		}
+	}
+}
+
+func Test_SpecToUVMCreateOpts_EncryptedScratch(t *testing.T) {
+	s := &specs.Spec{
+		Linux:       &specs.Linux{},
+		Annotations: map[string]string{annotationEncryptedScratch: "true"},
+	}
+	opts, err := SpecToUVMCreateOpts(s, t.Name(), "")
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if !opts.(*uvm.OptionsLCOW).EnableScratchEncryption {
+		t.Fatal("expected scratch encryption to be enabled")
+	}
+
+	s = &specs.Spec{
+		Windows:     &specs.Windows{HyperV: &specs.WindowsHyperV{}},
+		Annotations: map[string]string{annotationEncryptedScratch: "true"},
+	}
+	if _, err := SpecToUVMCreateOpts(s, t.Name(), ""); err == nil {
+		t.Fatal("expected error for encrypted scratch on WCOW")
+	}
+}
+
+func Test_SpecToUVMCreateOpts_LayerIntegrity(t *testing.T) {
+	s := &specs.Spec{
+		Linux:       &specs.Linux{},
+		Annotations: map[string]string{annotationLayerIntegrity: "true"},
+	}
+	opts, err := SpecToUVMCreateOpts(s, t.Name(), "")
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if !opts.(*uvm.OptionsLCOW).EnableLayerIntegrity {
+		t.Fatal("expected layer integrity to be enabled")
+	}
+
+	s = &specs.Spec{
+		Windows:     &specs.Windows{HyperV: &specs.WindowsHyperV{}},
+		Annotations: map[string]string{annotationLayerIntegrity: "true"},
+	}
+	if _, err := SpecToUVMCreateOpts(s, t.Name(), ""); err == nil {
+		t.Fatal("expected error for layer integrity on WCOW")
+	}
+}
+
+func Test_SpecToUVMCreateOpts_ScratchDiscard(t *testing.T) {
+	s := &specs.Spec{
+		Linux:       &specs.Linux{},
+		Annotations: map[string]string{annotationScratchDiscard: "true"},
+	}
+	opts, err := SpecToUVMCreateOpts(s, t.Name(), "")
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if !opts.(*uvm.OptionsLCOW).EnableScratchDiscard {
+		t.Fatal("expected scratch discard to be enabled")
+	}
+
+	s = &specs.Spec{
+		Windows:     &specs.Windows{HyperV: &specs.WindowsHyperV{}},
+		Annotations: map[string]string{annotationScratchDiscard: "true"},
+	}
+	if _, err := SpecToUVMCreateOpts(s, t.Name(), ""); err == nil {
+		t.Fatal("expected error for scratch discard on WCOW")
+	}
+}
+
+func Test_SpecToUVMCreateOpts_ScratchFilesystem(t *testing.T) {
+	s := &specs.Spec{
+		Linux:       &specs.Linux{},
+		Annotations: map[string]string{annotationScratchFilesystem: "xfs"},
+	}
+	opts, err := SpecToUVMCreateOpts(s, t.Name(), "")
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if fs := opts.(*uvm.OptionsLCOW).ScratchFilesystem; fs != "xfs" {
+		t.Fatalf("expected scratch filesystem 'xfs', got: '%s'", fs)
+	}
+
+	s = &specs.Spec{
+		Windows:     &specs.Windows{HyperV: &specs.WindowsHyperV{}},
+		Annotations: map[string]string{annotationScratchFilesystem: "xfs"},
+	}
+	if _, err := SpecToUVMCreateOpts(s, t.Name(), ""); err == nil {
+		t.Fatal("expected error for scratch filesystem on WCOW")
+	}
+}
+
+func Test_SpecToUVMCreateOpts_LayerAttachmentPolicy(t *testing.T) {
+	s := &specs.Spec{
+		Linux:       &specs.Linux{},
+		Annotations: map[string]string{annotationLayerAttachmentPolicy: "scsi"},
+	}
+	opts, err := SpecToUVMCreateOpts(s, t.Name(), "")
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if p := uvmOptions(opts).LayerAttachmentPolicy; p != uvm.LayerAttachmentPolicySCSI {
+		t.Fatalf("expected layer attachment policy %s, got: %s", uvm.LayerAttachmentPolicySCSI, p)
+	}
+}
+
+// uvmOptions returns the options common to the LCOW or WCOW options `opts`.
+func uvmOptions(opts interface{}) *uvm.Options {
+	switch o := opts.(type) {
+	case *uvm.OptionsLCOW:
+		return o.Options
+	case *uvm.OptionsWCOW:
+		return o.Options
+	}
+	return nil
+}
+
+func Test_SpecToUVMCreateOpts_BootFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bootfiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, f := range []string{uvm.VhdFile, uvm.UncompressedKernelFile} {
+		if err := ioutil.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		kernelDirect bool
+		kernelFile   string
+		rootFSFile   string
+	}{
+		{
+			name:         "DirectBoot",
+			annotations:  map[string]string{annotationKernelDirectBoot: "true"},
+			kernelDirect: true,
+			kernelFile:   uvm.UncompressedKernelFile,
+			rootFSFile:   uvm.VhdFile,
+		},
+		{
+			name:         "UEFI",
+			annotations:  map[string]string{annotationKernelDirectBoot: "false"},
+			kernelDirect: false,
+			kernelFile:   uvm.KernelFile,
+			rootFSFile:   uvm.VhdFile,
+		},
+		{
+			name:         "InitRd",
+			annotations:  map[string]string{annotationKernelDirectBoot: "false", annotationPreferredRootFSType: "initrd"},
+			kernelDirect: false,
+			kernelFile:   uvm.KernelFile,
+			rootFSFile:   uvm.InitrdFile,
+		},
+	}
+	for _, test := range tests {
+		test.annotations[annotationBootFilesRootPath] = dir
+		s := &specs.Spec{
+			Linux:       &specs.Linux{},
+			Annotations: test.annotations,
+		}
+		opts, err := SpecToUVMCreateOpts(s, t.Name(), "")
+		if err != nil {
+			t.Fatalf("%s: expected nil error, got: %v", test.name, err)
+		}
+		lopts := opts.(*uvm.OptionsLCOW)
+		if lopts.BootFilesPath != dir || lopts.KernelDirect != test.kernelDirect || lopts.KernelFile != test.kernelFile || lopts.RootFSFile != test.rootFSFile {
+			t.Fatalf("%s: unexpected boot files: %s %t %s %s", test.name, lopts.BootFilesPath, lopts.KernelDirect, lopts.KernelFile, lopts.RootFSFile)
+		}
+	}
+}
+
+func Test_SpecToUVMCreateOpts_RestoreStateFile(t *testing.T) {
+	s := &specs.Spec{
+		Windows: &specs.Windows{
+			HyperV: &specs.WindowsHyperV{},
+		},
+		Annotations: map[string]string{
+			annotationRestoreStateFile: `c:\saved\uvm.vmrs`,
+		},
+	}
+	opts, err := SpecToUVMCreateOpts(s, t.Name(), "")
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if p := opts.(*uvm.OptionsWCOW).SaveStateFilePath; p != `c:\saved\uvm.vmrs` {
+		t.Fatalf("expected saved state 'c:\\saved\\uvm.vmrs', got: '%s'", p)
+	}
+}
+
+func Test_SpecToUVMCreateOpts_RestoreStateFile_LCOW_Error(t *testing.T) {
+	s := &specs.Spec{
+		Linux: &specs.Linux{},
+		Annotations: map[string]string{
+			annotationRestoreStateFile: `c:\saved\uvm.vmrs`,
+		},
+	}
+	if _, err := SpecToUVMCreateOpts(s, t.Name(), ""); err == nil {
+		t.Fatal("expected error for an LCOW restore")
+	}
+}
+
+func Test_ParseAnnotationsEventLogChannels(t *testing.T) {
+	s := &specs.Spec{}
+	if c := ParseAnnotationsEventLogChannels(s); c != nil {
+		t.Fatalf("expected no channels by default, got: %v", c)
+	}
+	s.Annotations = map[string]string{AnnotationContainerEventLogChannels: " Application, ,System ,"}
+	c := ParseAnnotationsEventLogChannels(s)
+	if !reflect.DeepEqual(c, []string{"Application", "System"}) {
+		t.Fatalf("expected [Application System], got: %v", c)
+	}
+}
+
+func Test_ParseAnnotationsVPCIDevices(t *testing.T) {
+	s := &specs.Spec{}
+	if d := ParseAnnotationsVPCIDevices(s); d != nil {
+		t.Fatalf("expected no devices by default, got: %v", d)
+	}
+	s.Annotations = map[string]string{AnnotationContainerVPCIDevices: `PCI\VEN_10DE&DEV_1EB8\1, PCIROOT(0)#PCI(0300),`}
+	d := ParseAnnotationsVPCIDevices(s)
+	expected := []string{`PCI\VEN_10DE&DEV_1EB8\1`, `PCIROOT(0)#PCI(0300)`}
+	if !reflect.DeepEqual(d, expected) {
+		t.Fatalf("expected %v, got: %v", expected, d)
+	}
+}
+
+func Test_ParseAnnotationsLayerRootDigests(t *testing.T) {
+	s := &specs.Spec{}
+	if d := ParseAnnotationsLayerRootDigests(s); d != nil {
+		t.Fatalf("expected no root digests by default, got: %v", d)
+	}
+	s.Annotations = map[string]string{AnnotationContainerLayerRootDigests: "ab, cd"}
+	if d := ParseAnnotationsLayerRootDigests(s); !reflect.DeepEqual(d, []string{"ab", "cd"}) {
+		t.Fatalf("expected [ab cd], got: %v", d)
+	}
+}
+
+func Test_parseAnnotationsCPUGroup(t *testing.T) {
+	opts := &uvm.Options{}
+	a := map[string]string{
+		annotationCPUGroupID:                "4e7b7c7a-0a3c-4d5e-9f4a-1b2c3d4e5f60",
+		annotationCPUGroupLogicalProcessors: "2, 3,4",
+		annotationCPUGroupCap:               "32768",
+	}
+	if err := parseAnnotationsCPUGroup(a, opts); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if opts.CPUGroupID != a[annotationCPUGroupID] {
+		t.Fatalf("expected cpu group %s, got: %s", a[annotationCPUGroupID], opts.CPUGroupID)
+	}
+	if !reflect.DeepEqual(opts.CPUGroupLogicalProcessors, []uint32{2, 3, 4}) {
+		t.Fatalf("expected logical processors [2 3 4], got: %v", opts.CPUGroupLogicalProcessors)
+	}
+	if opts.CPUGroupCap != 32768 {
+		t.Fatalf("expected cap 32768, got: %d", opts.CPUGroupCap)
+	}
+}
+
+func Test_parseAnnotationsCPUGroup_InvalidLogicalProcessors(t *testing.T) {
+	a := map[string]string{annotationCPUGroupLogicalProcessors: "0,one"}
+	if err := parseAnnotationsCPUGroup(a, &uvm.Options{}); err == nil {
+		t.Fatal("expected error for invalid logical processors")
+	}
+}
+
+func Test_ParseAnnotationsExecLimits(t *testing.T) {
+	s := &specs.Spec{}
+	if l := ParseAnnotationsExecLimits(s); l != (jobobject.Limits{}) {
+		t.Fatalf("expected no limits by default, got: %+v", l)
+	}
+	s.Annotations = map[string]string{
+		AnnotationContainerExecProcessorMaximum:      "20000",
+		AnnotationContainerExecMemoryCommitLimitInMB: "512",
+		AnnotationContainerExecProcessCount:          "16",
+	}
+	expected := jobobject.Limits{
+		CPURate:      jobobject.CPURateMax,
+		MaxCommit:    512 * 1024 * 1024,
+		MaxProcesses: 16,
+	}
+	if l := ParseAnnotationsExecLimits(s); l != expected {
+		t.Fatalf("expected %+v, got: %+v", expected, l)
+	}
+}
+
+func Test_ParseAnnotationsExecDrainTimeout(t *testing.T) {
+	s := &specs.Spec{}
+	if d := ParseAnnotationsExecDrainTimeout(s); d != 0 {
+		t.Fatalf("expected no drain timeout by default, got: %v", d)
+	}
+	s.Annotations = map[string]string{AnnotationContainerExecDrainTimeoutInMs: "1500"}
+	if d := ParseAnnotationsExecDrainTimeout(s); d != 1500*time.Millisecond {
+		t.Fatalf("expected 1.5s, got: %v", d)
+	}
+}
+
+func Test_ParseAnnotationsProxyEnv(t *testing.T) {
+	s := &specs.Spec{}
+	if env := ParseAnnotationsProxyEnv(s); env != nil {
+		t.Fatalf("expected no proxy environment by default, got: %v", env)
+	}
+	s.Annotations = map[string]string{
+		AnnotationContainerHTTPSProxy: "http://proxy:3128",
+		AnnotationContainerNoProxy:    "localhost,.corp",
+	}
+	env := ParseAnnotationsProxyEnv(s)
+	expected := []string{"HTTPS_PROXY=http://proxy:3128", "NO_PROXY=localhost,.corp"}
+	if !reflect.DeepEqual(env, expected) {
+		t.Fatalf("expected %v, got: %v", expected, env)
+	}
+}
+
+func Test_UpdateSpecFromOptions_AllowedAnnotations(t *testing.T) {
+	s := specs.Spec{
+		Annotations: map[string]string{
+			AnnotationContainerMaxExecs:         "1",
+			AnnotationContainerVirtualTPM:       "true",
+			annotationMemorySizeInMB:            "2048",
+			"io.kubernetes.cri.sandbox-id":      "abc",
+			AnnotationContainerEventLogChannels: "Application",
+		},
+	}
+	opts := &runhcsopts.Options{
+		VmBackend: "remote",
+		AllowedAnnotations: []string{
+			AnnotationContainerVirtualTPM,
+			"io.microsoft.virtualmachine.computetopology.*",
+		},
+	}
+	s = UpdateSpecFromOptions(s, opts)
+	expected := map[string]string{
+		AnnotationContainerVirtualTPM:  "true",
+		annotationMemorySizeInMB:       "2048",
+		"io.kubernetes.cri.sandbox-id": "abc",
+		annotationVMBackend:            "remote",
+	}
+	if !reflect.DeepEqual(s.Annotations, expected) {
+		t.Fatalf("expected %v, got: %v", expected, s.Annotations)
+	}
+}
+
+func Test_UpdateSpecFromOptions_SandboxIsolation(t *testing.T) {
+	opts := &runhcsopts.Options{SandboxIsolation: runhcsopts.Options_HYPERVISOR}
+	s := UpdateSpecFromOptions(specs.Spec{Windows: &specs.Windows{}, Annotations: map[string]string{}}, opts)
+	if s.Windows.HyperV == nil {
+		t.Fatal("expected WCOW spec to be hypervisor isolated")
+	}
+	s = UpdateSpecFromOptions(specs.Spec{Windows: &specs.Windows{}, Annotations: map[string]string{}}, &runhcsopts.Options{})
+	if s.Windows.HyperV != nil {
+		t.Fatal("expected WCOW spec to remain process isolated")
+	}
+}
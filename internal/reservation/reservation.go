@@ -0,0 +1,266 @@
+// Package reservation coordinates the memory and processor reservations of
+// utility VMs across every shim on the host, so that a create that would
+// oversubscribe the host fails fast rather than causing host paging.
+//
+// Each reservation is recorded as a file in a host-wide directory that is
+// only read or written while holding a global named mutex. Reservations of
+// processes that have exited are discarded, so a crashed shim does not leak
+// its reservation.
+package reservation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/Microsoft/go-winio"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/windows"
+)
+
+//go:generate go run ../../mksyscall_windows.go -output zsyscall_windows.go reservation.go
+
+//sys createMutex(sa *windows.SecurityAttributes, initialOwner bool, name *uint16) (handle windows.Handle, err error) = kernel32.CreateMutexW
+//sys releaseMutex(mutex windows.Handle) (err error) = kernel32.ReleaseMutex
+//sys globalMemoryStatusEx(buffer *memoryStatusEx) (err error) = kernel32.GlobalMemoryStatusEx
+
+const (
+	// mutexName is the global mutex that MUST be held to read or write the
+	// reservations directory.
+	mutexName = `Global\hcsshim-reservations`
+
+	// mutexSDDL grants only administrators and local system access to the
+	// mutex so that other users cannot hold it.
+	mutexSDDL = "D:P(A;;GA;;;BA)(A;;GA;;;SY)"
+
+	processQueryLimitedInformation = 0x1000
+	stillActive                    = 259
+)
+
+// lockTimeout is the time waited to acquire the reservations mutex. Holders
+// only read and write a few small files, so not acquiring it in this time
+// means a holder is hung.
+var lockTimeout = 30 * time.Second
+
+// DefaultDirectory is the directory reservations are recorded in if none is
+// specified.
+var DefaultDirectory = filepath.Join(os.Getenv("ProgramData"), "Microsoft", "hcsshim", "reservations")
+
+type memoryStatusEx struct {
+	Length               uint32
+	MemoryLoad           uint32
+	TotalPhys            uint64
+	AvailPhys            uint64
+	TotalPageFile        uint64
+	AvailPageFile        uint64
+	TotalVirtual         uint64
+	AvailVirtual         uint64
+	AvailExtendedVirtual uint64
+}
+
+// Limits are the host-wide totals that the reservations of all utility VMs
+// may not exceed. A limit of `0` is unlimited.
+type Limits struct {
+	MemoryInMB     uint64
+	ProcessorCount uint32
+}
+
+// Request is the reservation of a single utility VM.
+type Request struct {
+	ID             string `json:"id"`
+	MemoryInMB     uint64 `json:"memoryInMB"`
+	ProcessorCount uint32 `json:"processorCount"`
+}
+
+// record is a reservation as stored in the reservations directory.
+type record struct {
+	Request
+	Pid int `json:"pid"`
+}
+
+// ExhaustedError is returned by `Reserve` when the reservation would exceed
+// the host limits.
+type ExhaustedError struct {
+	// Request is the reservation that was refused.
+	Request Request
+	// Reserved is the total of the existing reservations.
+	Reserved Limits
+	// Limits are the host limits that would be exceeded.
+	Limits Limits
+}
+
+func (e *ExhaustedError) Error() string {
+	return fmt.Sprintf("reserving %d MB and %d processors for '%s' would exceed the host limits of %d MB and %d processors with %d MB and %d processors already reserved",
+		e.Request.MemoryInMB,
+		e.Request.ProcessorCount,
+		e.Request.ID,
+		e.Limits.MemoryInMB,
+		e.Limits.ProcessorCount,
+		e.Reserved.MemoryInMB,
+		e.Reserved.ProcessorCount)
+}
+
+// Reservation is a reservation recorded by `Reserve`. It MUST be released via
+// `Release` when the utility VM is closed.
+type Reservation struct {
+	path string
+}
+
+// Release removes the reservation.
+func (r *Reservation) Release() error {
+	if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// TotalPhysicalMemoryInMB returns the physical memory of the host.
+func TotalPhysicalMemoryInMB() (uint64, error) {
+	m := memoryStatusEx{}
+	m.Length = uint32(unsafe.Sizeof(m))
+	if err := globalMemoryStatusEx(&m); err != nil {
+		return 0, err
+	}
+	return m.TotalPhys / 1024 / 1024, nil
+}
+
+// Reserve records the reservation `r` in `dir` for the calling process if the
+// total of all reservations on the host, including `r`, is within `l`. If not
+// returns an `*ExhaustedError`. A previous reservation with the same ID is
+// replaced.
+//
+// If `dir` is "" `DefaultDirectory` is used.
+func Reserve(dir string, r Request, l Limits) (_ *Reservation, err error) {
+	if dir == "" {
+		dir = DefaultDirectory
+	}
+	unlock, err := lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	reserved, err := readRecords(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLimits(reserved, r, l); err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(&record{Request: r, Pid: os.Getpid()})
+	if err != nil {
+		return nil, err
+	}
+	p := filepath.Join(dir, r.ID+".json")
+	if err := ioutil.WriteFile(p, b, 0600); err != nil {
+		return nil, err
+	}
+	return &Reservation{path: p}, nil
+}
+
+// checkLimits returns an `*ExhaustedError` if the total of `reserved` and `r`
+// exceeds `l`. Any existing reservation with the ID of `r` is not counted as
+// `r` replaces it.
+func checkLimits(reserved []Request, r Request, l Limits) error {
+	var total Limits
+	for _, res := range reserved {
+		if res.ID == r.ID {
+			continue
+		}
+		total.MemoryInMB += res.MemoryInMB
+		total.ProcessorCount += res.ProcessorCount
+	}
+	if (l.MemoryInMB != 0 && total.MemoryInMB+r.MemoryInMB > l.MemoryInMB) ||
+		(l.ProcessorCount != 0 && total.ProcessorCount+r.ProcessorCount > l.ProcessorCount) {
+		return &ExhaustedError{Request: r, Reserved: total, Limits: l}
+	}
+	return nil
+}
+
+// readRecords returns the live reservations in `dir` and removes those whose
+// process has exited or that cannot be read. It is the callers responsibility
+// to hold the reservations mutex.
+func readRecords(dir string) ([]Request, error) {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var reserved []Request
+	for _, fi := range fis {
+		if fi.IsDir() || !strings.HasSuffix(fi.Name(), ".json") {
+			continue
+		}
+		p := filepath.Join(dir, fi.Name())
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		var rec record
+		if err := json.Unmarshal(b, &rec); err != nil || !processAlive(rec.Pid) {
+			logrus.WithField("path", p).Debug("removing stale utility VM reservation")
+			os.Remove(p)
+			continue
+		}
+		reserved = append(reserved, rec.Request)
+	}
+	return reserved, nil
+}
+
+// processAlive returns `true` if the process `pid` is running. A process that
+// cannot be queried for lack of access is assumed to be running.
+func processAlive(pid int) bool {
+	h, err := windows.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return err == windows.ERROR_ACCESS_DENIED
+	}
+	defer windows.CloseHandle(h)
+	var code uint32
+	if err := windows.GetExitCodeProcess(h, &code); err != nil {
+		return true
+	}
+	return code == stillActive
+}
+
+// lock acquires the reservations mutex and returns the func that releases it.
+// Fails if the mutex cannot be acquired within `lockTimeout`.
+func lock() (func(), error) {
+	name, err := windows.UTF16PtrFromString(mutexName)
+	if err != nil {
+		return nil, err
+	}
+	sd, err := winio.SddlToSecurityDescriptor(mutexSDDL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get security descriptor for mutex '%s': %s", mutexName, err)
+	}
+	var sa windows.SecurityAttributes
+	sa.Length = uint32(unsafe.Sizeof(sa))
+	sa.SecurityDescriptor = uintptr(unsafe.Pointer(&sd[0]))
+	h, err := createMutex(&sa, false, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mutex '%s': %s", mutexName, err)
+	}
+	// An abandoned mutex is still acquired. The reservations directory is
+	// consistent regardless as each reservation is a single file.
+	e, err := windows.WaitForSingleObject(h, uint32(lockTimeout/time.Millisecond))
+	switch e {
+	case windows.WAIT_OBJECT_0, windows.WAIT_ABANDONED:
+	case uint32(windows.WAIT_TIMEOUT):
+		windows.CloseHandle(h)
+		return nil, fmt.Errorf("timed out after %s acquiring mutex '%s'", lockTimeout, mutexName)
+	default:
+		windows.CloseHandle(h)
+		return nil, fmt.Errorf("failed to acquire mutex '%s': %s", mutexName, err)
+	}
+	return func() {
+		releaseMutex(h)
+		windows.CloseHandle(h)
+	}, nil
+}
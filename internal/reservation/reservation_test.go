@@ -0,0 +1,164 @@
+package reservation
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_checkLimits(t *testing.T) {
+	reserved := []Request{
+		{ID: "a", MemoryInMB: 1024, ProcessorCount: 2},
+		{ID: "b", MemoryInMB: 2048, ProcessorCount: 2},
+	}
+	tests := []struct {
+		r        Request
+		l        Limits
+		expected bool
+	}{
+		{Request{ID: "c", MemoryInMB: 1024, ProcessorCount: 2}, Limits{}, true},
+		{Request{ID: "c", MemoryInMB: 1024, ProcessorCount: 2}, Limits{MemoryInMB: 4096, ProcessorCount: 6}, true},
+		{Request{ID: "c", MemoryInMB: 1025, ProcessorCount: 2}, Limits{MemoryInMB: 4096}, false},
+		{Request{ID: "c", MemoryInMB: 1, ProcessorCount: 3}, Limits{ProcessorCount: 6}, false},
+		// Replacing "b" does not count its previous reservation.
+		{Request{ID: "b", MemoryInMB: 3072, ProcessorCount: 4}, Limits{MemoryInMB: 4096, ProcessorCount: 6}, true},
+	}
+	for _, test := range tests {
+		err := checkLimits(reserved, test.r, test.l)
+		if test.expected && err != nil {
+			t.Fatalf("%+v within %+v: expected nil error, got: %v", test.r, test.l, err)
+		}
+		if !test.expected {
+			e, ok := err.(*ExhaustedError)
+			if !ok {
+				t.Fatalf("%+v within %+v: expected *ExhaustedError, got: %v", test.r, test.l, err)
+			}
+			if e.Reserved != (Limits{MemoryInMB: 3072, ProcessorCount: 4}) {
+				t.Fatalf("expected reserved totals of the existing reservations, got: %+v", e.Reserved)
+			}
+		}
+	}
+}
+
+func Test_Reserve(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	l := Limits{MemoryInMB: 2048}
+	r1, err := Reserve(dir, Request{ID: "r1", MemoryInMB: 1024}, l)
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if _, err := Reserve(dir, Request{ID: "r2", MemoryInMB: 1025}, l); err == nil {
+		t.Fatal("expected reservation over the limit to fail")
+	} else if _, ok := err.(*ExhaustedError); !ok {
+		t.Fatalf("expected *ExhaustedError, got: %v", err)
+	}
+	if err := r1.Release(); err != nil {
+		t.Fatalf("failed to release: %v", err)
+	}
+	r2, err := Reserve(dir, Request{ID: "r2", MemoryInMB: 1025}, l)
+	if err != nil {
+		t.Fatalf("expected nil error after release, got: %v", err)
+	}
+	r2.Release()
+}
+
+func Test_Reserve_SameID(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	l := Limits{MemoryInMB: 2048}
+	if _, err := Reserve(dir, Request{ID: "r1", MemoryInMB: 1024}, l); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	// Reserving the same ID again replaces the previous reservation.
+	r, err := Reserve(dir, Request{ID: "r1", MemoryInMB: 2048}, l)
+	if err != nil {
+		t.Fatalf("expected nil error reserving the same ID again, got: %v", err)
+	}
+	defer r.Release()
+	reserved, err := readRecords(dir)
+	if err != nil {
+		t.Fatalf("failed to read reservations: %v", err)
+	}
+	if len(reserved) != 1 || reserved[0].MemoryInMB != 2048 {
+		t.Fatalf("expected only the replacing reservation, got: %+v", reserved)
+	}
+}
+
+func Test_Reserve_RemovesStale(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Pid 0 is never a process that can be queried so the record is stale.
+	b, _ := json.Marshal(&record{Request: Request{ID: "stale", MemoryInMB: 2048}, Pid: 0})
+	stale := filepath.Join(dir, "stale.json")
+	if err := ioutil.WriteFile(stale, b, 0600); err != nil {
+		t.Fatalf("failed to write record: %v", err)
+	}
+	r, err := Reserve(dir, Request{ID: "new", MemoryInMB: 2048}, Limits{MemoryInMB: 2048})
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	defer r.Release()
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Fatalf("expected stale record to be removed, got: %v", err)
+	}
+}
+
+func Test_lock_Timeout(t *testing.T) {
+	defer func(d time.Duration) { lockTimeout = d }(lockTimeout)
+	lockTimeout = 100 * time.Millisecond
+
+	// A mutex is owned by a thread and may be reacquired by it, so hold it on
+	// a separate locked thread.
+	held := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		defer close(done)
+		unlock, err := lock()
+		if err != nil {
+			t.Errorf("failed to acquire lock: %v", err)
+			close(held)
+			return
+		}
+		close(held)
+		<-release
+		unlock()
+	}()
+	<-held
+
+	errs := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		unlock, err := lock()
+		if err == nil {
+			unlock()
+		}
+		errs <- err
+	}()
+	if err := <-errs; err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout error, got: %v", err)
+	}
+	close(release)
+	<-done
+}
@@ -122,6 +122,7 @@ const (
 	PropertyTypeProcessList                    = "ProcessList"       // V1 and V2
 	PropertyTypeMappedVirtualDisk              = "MappedVirtualDisk" // Not supported in V2 schema call
 	PropertyTypeGuestConnection                = "GuestConnection"   // V1 and V2. Nil return from HCS before RS5
+	PropertyTypeCgroupMetrics                  = "CgroupMetrics"     // LCOW guest only
 )
 
 type PropertyQuery struct {
@@ -148,6 +149,7 @@ type ContainerProperties struct {
 	ProcessList                  []ProcessListItem                   `json:",omitempty"`
 	MappedVirtualDiskControllers map[int]MappedVirtualDiskController `json:",omitempty"`
 	GuestConnectionInfo          GuestConnectionInfo                 `json:",omitempty"`
+	CgroupMetrics                *json.RawMessage                    `json:",omitempty"` // containerd cgroups v1 Metrics of an LCOW container
 }
 
 // MemoryStats holds the memory statistics for a container
@@ -216,6 +218,19 @@ type MappedVirtualDiskController struct {
 type GuestDefinedCapabilities struct {
 	NamespaceAddRequestSupported bool `json:",omitempty"`
 	SignalProcessSupported       bool `json:",omitempty"`
+	DumpStacksSupported          bool `json:",omitempty"`
+	EncryptedScratchSupported    bool `json:",omitempty"`
+	LayerIntegritySupported      bool `json:",omitempty"`
+	BlockDeviceSupported         bool `json:",omitempty"`
+	Plan9OptionsSupported        bool `json:",omitempty"`
+	CgroupMetricsSupported       bool `json:",omitempty"`
+	OOMNotificationsSupported    bool `json:",omitempty"`
+	VPCIDevicesSupported         bool `json:",omitempty"`
+	VPMemMultiMappingSupported   bool `json:",omitempty"`
+	SCSIMountOptionsSupported    bool `json:",omitempty"`
+	ProcessorHotAddSupported     bool `json:",omitempty"`
+	MultiSCSIControllerSupported bool `json:",omitempty"`
+	ScratchDiscardSupported      bool `json:",omitempty"`
 }
 
 // GuestConnectionInfo is the structure of an iterm return by a GuestConnection call on a utility VM
@@ -27,4 +27,6 @@ type Attachment struct {
 	CaptureIoAttributionContext bool `json:"CaptureIoAttributionContext,omitempty"`
 
 	ReadOnly bool `json:"ReadOnly,omitempty"`
+
+	StorageQoS *StorageQoS `json:"StorageQoS,omitempty"`
 }
@@ -0,0 +1,17 @@
+/*
+ * HCS API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 2.1
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package hcsschema
+
+// Structure used to request a modification of the settings of the service
+type ModificationRequest struct {
+	PropertyType PropertyType `json:"PropertyType,omitempty"`
+
+	Settings interface{} `json:"Settings,omitempty"`
+}
@@ -0,0 +1,18 @@
+/*
+ * HCS API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 2.1
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package hcsschema
+
+// The virtual NUMA topology exposed to the guest
+type Numa struct {
+	VirtualNodeCount uint8 `json:"VirtualNodeCount,omitempty"`
+
+	// The maximum memory in MB of a virtual NUMA node
+	MaxSizePerNode uint64 `json:"MaxSizePerNode,omitempty"`
+}
@@ -17,4 +17,6 @@ type Processor2 struct {
 	Weight int32 `json:"Weight,omitempty"`
 
 	ExposeVirtualizationExtensions bool `json:"ExposeVirtualizationExtensions,omitempty"`
+
+	CpuGroup *CpuGroup `json:"CpuGroup,omitempty"`
 }
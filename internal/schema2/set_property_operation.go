@@ -0,0 +1,19 @@
+/*
+ * HCS API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 2.1
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package hcsschema
+
+// Set properties operation settings
+type SetPropertyOperation struct {
+	GroupId string `json:"GroupId,omitempty"`
+
+	PropertyCode uint32 `json:"PropertyCode,omitempty"`
+
+	PropertyValue uint32 `json:"PropertyValue,omitempty"`
+}
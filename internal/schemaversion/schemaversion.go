@@ -4,10 +4,10 @@ package schemaversion
 
 import (
 	"encoding/json"
-	"fmt"
 
 	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
 	"github.com/Microsoft/hcsshim/osversion"
+	"github.com/Microsoft/hcsshim/pkg/schemaversion"
 	"github.com/sirupsen/logrus"
 )
 
@@ -23,16 +23,7 @@ func SchemaV21() *hcsschema.Version {
 
 // isSupported determines if a given schema version is supported
 func IsSupported(sv *hcsschema.Version) error {
-	if IsV10(sv) {
-		return nil
-	}
-	if IsV21(sv) {
-		if osversion.Get().Build < osversion.RS5 {
-			return fmt.Errorf("unsupported on this Windows build")
-		}
-		return nil
-	}
-	return fmt.Errorf("unknown schema version %s", String(sv))
+	return schemaversion.IsSupported(osversion.Get().Build, toPublic(sv))
 }
 
 // IsV10 determines if a given schema version object is 1.0. This was the only thing
@@ -66,16 +57,33 @@ func String(sv *hcsschema.Version) string {
 // DetermineSchemaVersion works out what schema version to use based on build and
 // requested option.
 func DetermineSchemaVersion(requestedSV *hcsschema.Version) *hcsschema.Version {
-	sv := SchemaV10()
-	if osversion.Get().Build >= osversion.RS5 {
-		sv = SchemaV21()
-	}
+	build := osversion.Get().Build
+	v := schemaversion.Default(build)
 	if requestedSV != nil {
-		if err := IsSupported(requestedSV); err == nil {
-			sv = requestedSV
+		if err := schemaversion.IsSupported(build, toPublic(requestedSV)); err == nil {
+			v = toPublic(requestedSV)
 		} else {
 			logrus.WithField("schemaVersion", requestedSV).Warn("Ignoring unsupported requested schema version")
 		}
 	}
-	return sv
+	return &hcsschema.Version{Major: v.Major, Minor: v.Minor}
+}
+
+// DetermineDocumentShape works out what schema version and document shape to
+// use to create a compute system with `features`, based on build and
+// requested option. An unsupported requested version is ignored as it is by
+// DetermineSchemaVersion, but an error is returned if the resulting version
+// cannot provide `features`.
+func DetermineDocumentShape(requestedSV *hcsschema.Version, features schemaversion.Features) (*hcsschema.Version, schemaversion.DocumentShape, error) {
+	sv := toPublic(DetermineSchemaVersion(requestedSV))
+	v, shape, err := schemaversion.Determine(osversion.Get().Build, &sv, features)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &hcsschema.Version{Major: v.Major, Minor: v.Minor}, shape, nil
+}
+
+// toPublic converts `sv` to the public schema version type.
+func toPublic(sv *hcsschema.Version) schemaversion.Version {
+	return schemaversion.Version{Major: sv.Major, Minor: sv.Minor}
 }
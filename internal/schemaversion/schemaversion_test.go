@@ -6,6 +6,7 @@ import (
 
 	"github.com/Microsoft/hcsshim/internal/schema2"
 	"github.com/Microsoft/hcsshim/osversion"
+	"github.com/Microsoft/hcsshim/pkg/schemaversion"
 	_ "github.com/Microsoft/hcsshim/test/functional/manifest"
 	"github.com/sirupsen/logrus"
 )
@@ -61,3 +62,30 @@ func TestDetermineSchemaVersion(t *testing.T) {
 		}
 	}
 }
+
+func TestDetermineDocumentShape(t *testing.T) {
+	if osversion.Get().Build < osversion.RS5 {
+		if _, _, err := DetermineDocumentShape(nil, schemaversion.Features{LCOW: true}); err == nil {
+			t.Fatalf("expected LCOW to be rejected")
+		}
+		return
+	}
+
+	sv, shape, err := DetermineDocumentShape(nil, schemaversion.Features{HostedInUtilityVM: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsV21(sv) || shape != schemaversion.HostedSystemV2 {
+		t.Fatalf("expected v2 hosted system, got %s %s", String(sv), shape)
+	}
+	sv, shape, err = DetermineDocumentShape(SchemaV10(), schemaversion.Features{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsV10(sv) || shape != schemaversion.ContainerConfigV1 {
+		t.Fatalf("expected v1 container config, got %s %s", String(sv), shape)
+	}
+	if _, _, err := DetermineDocumentShape(SchemaV10(), schemaversion.Features{LCOW: true}); err == nil {
+		t.Fatalf("expected LCOW to be rejected for a requested v1 schema")
+	}
+}
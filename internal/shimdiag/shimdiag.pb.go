@@ -148,6 +148,7 @@ var xxx_messageInfo_StacksRequest proto.InternalMessageInfo
 
 type StacksResponse struct {
 	Stacks               string   `protobuf:"bytes,1,opt,name=stacks,proto3" json:"stacks,omitempty"`
+	GuestStacks          string   `protobuf:"bytes,2,opt,name=guest_stacks,json=guestStacks,proto3" json:"guest_stacks,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -185,399 +186,5978 @@ func (m *StacksResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_StacksResponse proto.InternalMessageInfo
 
-func init() {
-	proto.RegisterType((*ExecProcessRequest)(nil), "containerd.runhcs.v1.diag.ExecProcessRequest")
-	proto.RegisterType((*ExecProcessResponse)(nil), "containerd.runhcs.v1.diag.ExecProcessResponse")
-	proto.RegisterType((*StacksRequest)(nil), "containerd.runhcs.v1.diag.StacksRequest")
-	proto.RegisterType((*StacksResponse)(nil), "containerd.runhcs.v1.diag.StacksResponse")
+type TaskStateRequest struct {
+	TaskID               string   `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	ExecID               string   `protobuf:"bytes,2,opt,name=exec_id,json=execId,proto3" json:"exec_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func init() {
-	proto.RegisterFile("github.com/Microsoft/hcsshim/internal/shimdiag/shimdiag.proto", fileDescriptor_c7933dc6ffbb8784)
+func (m *TaskStateRequest) Reset()      { *m = TaskStateRequest{} }
+func (*TaskStateRequest) ProtoMessage() {}
+func (*TaskStateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{4}
 }
-
-var fileDescriptor_c7933dc6ffbb8784 = []byte{
-	// 391 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0xcf, 0x8b, 0xd4, 0x30,
-	0x18, 0x9d, 0xb8, 0x3b, 0x63, 0x27, 0xe0, 0x0a, 0x71, 0x91, 0x58, 0xa1, 0x94, 0x9e, 0xea, 0xc1,
-	0x14, 0xd7, 0x83, 0x07, 0xf1, 0xe2, 0x0f, 0xd0, 0x83, 0xa0, 0xdd, 0x8b, 0x78, 0x91, 0x6c, 0x1a,
-	0xdb, 0xb0, 0xdb, 0x7c, 0x63, 0x92, 0xea, 0x1c, 0xfd, 0x63, 0xfc, 0x63, 0xe6, 0xe8, 0xd1, 0x93,
-	0x38, 0xfd, 0x4b, 0x24, 0x69, 0x3b, 0x30, 0x88, 0x32, 0x9e, 0xf2, 0xde, 0xe3, 0x3d, 0xde, 0x97,
-	0x8f, 0x0f, 0x3f, 0xa9, 0x95, 0x6b, 0xba, 0x0b, 0x26, 0xa0, 0x2d, 0x5e, 0x2b, 0x61, 0xc0, 0xc2,
-	0x47, 0x57, 0x34, 0xc2, 0xda, 0x46, 0xb5, 0x85, 0xd2, 0x4e, 0x1a, 0xcd, 0xaf, 0x0a, 0xcf, 0x2a,
-	0xc5, 0xeb, 0x1d, 0x60, 0x2b, 0x03, 0x0e, 0xc8, 0x1d, 0x01, 0xda, 0x71, 0xa5, 0xa5, 0xa9, 0x98,
-	0xe9, 0x74, 0x23, 0x2c, 0xfb, 0xfc, 0x80, 0x79, 0x43, 0x7c, 0x5a, 0x43, 0x0d, 0xc1, 0x55, 0x78,
-	0x34, 0x04, 0xb2, 0x6f, 0x08, 0x93, 0x17, 0x6b, 0x29, 0xde, 0x18, 0x10, 0xd2, 0xda, 0x52, 0x7e,
-	0xea, 0xa4, 0x75, 0x84, 0xe0, 0x63, 0x6e, 0x6a, 0x4b, 0x51, 0x7a, 0x94, 0x2f, 0xcb, 0x80, 0x09,
-	0xc5, 0xd7, 0xbf, 0x80, 0xb9, 0xac, 0x94, 0xa1, 0xd7, 0x52, 0x94, 0x2f, 0xcb, 0x89, 0x92, 0x18,
-	0x47, 0x4e, 0x9a, 0x56, 0x69, 0x7e, 0x45, 0x8f, 0x52, 0x94, 0x47, 0xe5, 0x8e, 0x93, 0x53, 0x3c,
-	0xb7, 0xae, 0x52, 0x9a, 0x1e, 0x87, 0xcc, 0x40, 0xc8, 0x6d, 0xbc, 0xb0, 0xae, 0x82, 0xce, 0xd1,
-	0x79, 0x90, 0x47, 0x36, 0xea, 0xd2, 0x18, 0xba, 0xd8, 0xe9, 0xd2, 0x98, 0xec, 0x0c, 0xdf, 0xda,
-	0x9b, 0xd2, 0xae, 0x40, 0x5b, 0x49, 0xee, 0xe2, 0xa5, 0x5c, 0x2b, 0xf7, 0x41, 0x40, 0x25, 0x29,
-	0x4a, 0x51, 0x3e, 0x2f, 0x23, 0x2f, 0x3c, 0x83, 0x4a, 0x66, 0x37, 0xf1, 0x8d, 0x73, 0xc7, 0xc5,
-	0xe5, 0xf4, 0xa9, 0x2c, 0xc7, 0x27, 0x93, 0x30, 0xe6, 0x43, 0x9d, 0x57, 0x42, 0x38, 0xd4, 0x79,
-	0x76, 0xf6, 0x13, 0xe1, 0xe8, 0xbc, 0x51, 0xed, 0x73, 0xc5, 0x6b, 0x02, 0xf8, 0xc4, 0xbf, 0xbe,
-	0xff, 0x95, 0x7e, 0x09, 0xd6, 0x91, 0xfb, 0xec, 0xaf, 0x6b, 0x66, 0x7f, 0x2e, 0x33, 0x66, 0x87,
-	0xda, 0xc7, 0xa9, 0x38, 0xc6, 0xbe, 0x70, 0x98, 0x95, 0xe4, 0xff, 0x48, 0xef, 0xfd, 0x2f, 0xbe,
-	0x77, 0x80, 0x73, 0xa8, 0x78, 0xfa, 0x76, 0xb3, 0x4d, 0x66, 0x3f, 0xb6, 0xc9, 0xec, 0x6b, 0x9f,
-	0xa0, 0x4d, 0x9f, 0xa0, 0xef, 0x7d, 0x82, 0x7e, 0xf5, 0x09, 0x7a, 0xff, 0xe8, 0xff, 0x0e, 0xf0,
-	0xf1, 0x04, 0xde, 0xcd, 0x2e, 0x16, 0xe1, 0xa4, 0x1e, 0xfe, 0x0e, 0x00, 0x00, 0xff, 0xff, 0x96,
-	0xdc, 0x49, 0x53, 0xc4, 0x02, 0x00, 0x00,
+func (m *TaskStateRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-func (m *ExecProcessRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *TaskStateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TaskStateRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return dAtA[:n], nil
+}
+func (m *TaskStateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TaskStateRequest.Merge(m, src)
+}
+func (m *TaskStateRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *TaskStateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TaskStateRequest.DiscardUnknown(m)
 }
 
-func (m *ExecProcessRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Args) > 0 {
-		for _, s := range m.Args {
-			dAtA[i] = 0xa
-			i++
-			l = len(s)
-			for l >= 1<<7 {
-				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
-				l >>= 7
-				i++
-			}
-			dAtA[i] = uint8(l)
-			i++
-			i += copy(dAtA[i:], s)
-		}
-	}
-	if len(m.Workdir) > 0 {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Workdir)))
-		i += copy(dAtA[i:], m.Workdir)
-	}
-	if m.Terminal {
-		dAtA[i] = 0x18
-		i++
-		if m.Terminal {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
+var xxx_messageInfo_TaskStateRequest proto.InternalMessageInfo
+
+type TaskStateResponse struct {
+	TaskID                            string            `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	ExecID                            string            `protobuf:"bytes,2,opt,name=exec_id,json=execId,proto3" json:"exec_id,omitempty"`
+	Pid                               uint32            `protobuf:"varint,3,opt,name=pid,proto3" json:"pid,omitempty"`
+	Status                            string            `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	ExitStatus                        uint32            `protobuf:"varint,5,opt,name=exit_status,json=exitStatus,proto3" json:"exit_status,omitempty"`
+	MemoryUsageCommitBytes            uint64            `protobuf:"varint,6,opt,name=memory_usage_commit_bytes,json=memoryUsageCommitBytes,proto3" json:"memory_usage_commit_bytes,omitempty"`
+	MemoryUsagePrivateWorkingSetBytes uint64            `protobuf:"varint,7,opt,name=memory_usage_private_working_set_bytes,json=memoryUsagePrivateWorkingSetBytes,proto3" json:"memory_usage_private_working_set_bytes,omitempty"`
+	ProcessorTotalRuntimeNs           uint64            `protobuf:"varint,8,opt,name=processor_total_runtime_ns,json=processorTotalRuntimeNs,proto3" json:"processor_total_runtime_ns,omitempty"`
+	Devices                           []*AttachedDevice `protobuf:"bytes,9,rep,name=devices,proto3" json:"devices,omitempty"`
+	NetworkNamespace                  string            `protobuf:"bytes,10,opt,name=network_namespace,json=networkNamespace,proto3" json:"network_namespace,omitempty"`
+	EndpointIDs                       []string          `protobuf:"bytes,11,rep,name=endpoint_ids,json=endpointIds,proto3" json:"endpoint_ids,omitempty"`
+	HostID                            string            `protobuf:"bytes,12,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
+	XXX_NoUnkeyedLiteral              struct{}          `json:"-"`
+	XXX_unrecognized                  []byte            `json:"-"`
+	XXX_sizecache                     int32             `json:"-"`
+}
+
+func (m *TaskStateResponse) Reset()      { *m = TaskStateResponse{} }
+func (*TaskStateResponse) ProtoMessage() {}
+func (*TaskStateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{5}
+}
+func (m *TaskStateResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TaskStateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TaskStateResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
 		}
-		i++
-	}
-	if len(m.Stdin) > 0 {
-		dAtA[i] = 0x22
-		i++
-		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Stdin)))
-		i += copy(dAtA[i:], m.Stdin)
-	}
-	if len(m.Stdout) > 0 {
-		dAtA[i] = 0x2a
-		i++
-		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Stdout)))
-		i += copy(dAtA[i:], m.Stdout)
-	}
-	if len(m.Stderr) > 0 {
-		dAtA[i] = 0x32
-		i++
-		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Stderr)))
-		i += copy(dAtA[i:], m.Stderr)
-	}
-	if m.XXX_unrecognized != nil {
-		i += copy(dAtA[i:], m.XXX_unrecognized)
+		return b[:n], nil
 	}
-	return i, nil
+}
+func (m *TaskStateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TaskStateResponse.Merge(m, src)
+}
+func (m *TaskStateResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *TaskStateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TaskStateResponse.DiscardUnknown(m)
 }
 
-func (m *ExecProcessResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+var xxx_messageInfo_TaskStateResponse proto.InternalMessageInfo
+
+type AttachedDevice struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Path                 string   `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ExecProcessResponse) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.ExitCode != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintShimdiag(dAtA, i, uint64(m.ExitCode))
-	}
-	if m.XXX_unrecognized != nil {
-		i += copy(dAtA[i:], m.XXX_unrecognized)
+func (m *AttachedDevice) Reset()      { *m = AttachedDevice{} }
+func (*AttachedDevice) ProtoMessage() {}
+func (*AttachedDevice) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{6}
+}
+func (m *AttachedDevice) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AttachedDevice) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AttachedDevice.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return i, nil
+}
+func (m *AttachedDevice) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AttachedDevice.Merge(m, src)
+}
+func (m *AttachedDevice) XXX_Size() int {
+	return m.Size()
+}
+func (m *AttachedDevice) XXX_DiscardUnknown() {
+	xxx_messageInfo_AttachedDevice.DiscardUnknown(m)
 }
 
-func (m *StacksRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+var xxx_messageInfo_AttachedDevice proto.InternalMessageInfo
+
+type ReloadConfigRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *StacksRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.XXX_unrecognized != nil {
-		i += copy(dAtA[i:], m.XXX_unrecognized)
+func (m *ReloadConfigRequest) Reset()      { *m = ReloadConfigRequest{} }
+func (*ReloadConfigRequest) ProtoMessage() {}
+func (*ReloadConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{7}
+}
+func (m *ReloadConfigRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ReloadConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ReloadConfigRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return i, nil
+}
+func (m *ReloadConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReloadConfigRequest.Merge(m, src)
+}
+func (m *ReloadConfigRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ReloadConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReloadConfigRequest.DiscardUnknown(m)
 }
 
-func (m *StacksResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+var xxx_messageInfo_ReloadConfigRequest proto.InternalMessageInfo
+
+type ReloadConfigResponse struct {
+	ConfigPath           string   `protobuf:"bytes,1,opt,name=config_path,json=configPath,proto3" json:"config_path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *StacksResponse) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Stacks) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Stacks)))
-		i += copy(dAtA[i:], m.Stacks)
-	}
-	if m.XXX_unrecognized != nil {
-		i += copy(dAtA[i:], m.XXX_unrecognized)
+func (m *ReloadConfigResponse) Reset()      { *m = ReloadConfigResponse{} }
+func (*ReloadConfigResponse) ProtoMessage() {}
+func (*ReloadConfigResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{8}
+}
+func (m *ReloadConfigResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ReloadConfigResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ReloadConfigResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return i, nil
+}
+func (m *ReloadConfigResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReloadConfigResponse.Merge(m, src)
+}
+func (m *ReloadConfigResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ReloadConfigResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReloadConfigResponse.DiscardUnknown(m)
 }
 
-func encodeVarintShimdiag(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
-	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+var xxx_messageInfo_ReloadConfigResponse proto.InternalMessageInfo
+
+type LastCrashRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
-func (m *ExecProcessRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Args) > 0 {
-		for _, s := range m.Args {
-			l = len(s)
-			n += 1 + l + sovShimdiag(uint64(l))
+
+func (m *LastCrashRequest) Reset()      { *m = LastCrashRequest{} }
+func (*LastCrashRequest) ProtoMessage() {}
+func (*LastCrashRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{9}
+}
+func (m *LastCrashRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LastCrashRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LastCrashRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	l = len(m.Workdir)
-	if l > 0 {
-		n += 1 + l + sovShimdiag(uint64(l))
-	}
-	if m.Terminal {
-		n += 2
-	}
-	l = len(m.Stdin)
-	if l > 0 {
-		n += 1 + l + sovShimdiag(uint64(l))
-	}
-	l = len(m.Stdout)
-	if l > 0 {
-		n += 1 + l + sovShimdiag(uint64(l))
-	}
-	l = len(m.Stderr)
-	if l > 0 {
-		n += 1 + l + sovShimdiag(uint64(l))
-	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
-	}
-	return n
+}
+func (m *LastCrashRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LastCrashRequest.Merge(m, src)
+}
+func (m *LastCrashRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *LastCrashRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LastCrashRequest.DiscardUnknown(m)
 }
 
-func (m *ExecProcessResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.ExitCode != 0 {
-		n += 1 + sovShimdiag(uint64(m.ExitCode))
-	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
-	}
-	return n
+var xxx_messageInfo_LastCrashRequest proto.InternalMessageInfo
+
+type LastCrashResponse struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Report               string   `protobuf:"bytes,2,opt,name=report,proto3" json:"report,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *StacksRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+func (m *LastCrashResponse) Reset()      { *m = LastCrashResponse{} }
+func (*LastCrashResponse) ProtoMessage() {}
+func (*LastCrashResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{10}
+}
+func (m *LastCrashResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LastCrashResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LastCrashResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return n
+}
+func (m *LastCrashResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LastCrashResponse.Merge(m, src)
+}
+func (m *LastCrashResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *LastCrashResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LastCrashResponse.DiscardUnknown(m)
 }
 
-func (m *StacksResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Stacks)
-	if l > 0 {
-		n += 1 + l + sovShimdiag(uint64(l))
-	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
-	}
-	return n
+var xxx_messageInfo_LastCrashResponse proto.InternalMessageInfo
+
+type ConsoleRequest struct {
+	Stdin                string   `protobuf:"bytes,1,opt,name=stdin,proto3" json:"stdin,omitempty"`
+	Stdout               string   `protobuf:"bytes,2,opt,name=stdout,proto3" json:"stdout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func sovShimdiag(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
+func (m *ConsoleRequest) Reset()      { *m = ConsoleRequest{} }
+func (*ConsoleRequest) ProtoMessage() {}
+func (*ConsoleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{11}
+}
+func (m *ConsoleRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ConsoleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ConsoleRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return n
 }
-func sozShimdiag(x uint64) (n int) {
-	return sovShimdiag(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+func (m *ConsoleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConsoleRequest.Merge(m, src)
 }
-func (this *ExecProcessRequest) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&ExecProcessRequest{`,
-		`Args:` + fmt.Sprintf("%v", this.Args) + `,`,
-		`Workdir:` + fmt.Sprintf("%v", this.Workdir) + `,`,
-		`Terminal:` + fmt.Sprintf("%v", this.Terminal) + `,`,
-		`Stdin:` + fmt.Sprintf("%v", this.Stdin) + `,`,
-		`Stdout:` + fmt.Sprintf("%v", this.Stdout) + `,`,
-		`Stderr:` + fmt.Sprintf("%v", this.Stderr) + `,`,
-		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
-		`}`,
-	}, "")
-	return s
+func (m *ConsoleRequest) XXX_Size() int {
+	return m.Size()
 }
-func (this *ExecProcessResponse) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&ExecProcessResponse{`,
-		`ExitCode:` + fmt.Sprintf("%v", this.ExitCode) + `,`,
-		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
-		`}`,
-	}, "")
-	return s
+func (m *ConsoleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConsoleRequest.DiscardUnknown(m)
 }
-func (this *StacksRequest) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&StacksRequest{`,
-		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
-		`}`,
-	}, "")
-	return s
+
+var xxx_messageInfo_ConsoleRequest proto.InternalMessageInfo
+
+type ConsoleResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
-func (this *StacksResponse) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&StacksResponse{`,
-		`Stacks:` + fmt.Sprintf("%v", this.Stacks) + `,`,
-		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
-		`}`,
-	}, "")
-	return s
+
+func (m *ConsoleResponse) Reset()      { *m = ConsoleResponse{} }
+func (*ConsoleResponse) ProtoMessage() {}
+func (*ConsoleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{12}
 }
-func valueToStringShimdiag(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+func (m *ConsoleResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ConsoleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ConsoleResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+}
+func (m *ConsoleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConsoleResponse.Merge(m, src)
+}
+func (m *ConsoleResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ConsoleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConsoleResponse.DiscardUnknown(m)
 }
 
-type ShimDiagService interface {
-	DiagExecInHost(ctx context.Context, req *ExecProcessRequest) (*ExecProcessResponse, error)
-	DiagStacks(ctx context.Context, req *StacksRequest) (*StacksResponse, error)
+var xxx_messageInfo_ConsoleResponse proto.InternalMessageInfo
+
+type TasksRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func RegisterShimDiagService(srv *github_com_containerd_ttrpc.Server, svc ShimDiagService) {
-	srv.Register("containerd.runhcs.v1.diag.ShimDiag", map[string]github_com_containerd_ttrpc.Method{
-		"DiagExecInHost": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
-			var req ExecProcessRequest
-			if err := unmarshal(&req); err != nil {
-				return nil, err
-			}
-			return svc.DiagExecInHost(ctx, &req)
-		},
-		"DiagStacks": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
-			var req StacksRequest
-			if err := unmarshal(&req); err != nil {
-				return nil, err
-			}
-			return svc.DiagStacks(ctx, &req)
-		},
-	})
+func (m *TasksRequest) Reset()      { *m = TasksRequest{} }
+func (*TasksRequest) ProtoMessage() {}
+func (*TasksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{13}
+}
+func (m *TasksRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TasksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TasksRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TasksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TasksRequest.Merge(m, src)
+}
+func (m *TasksRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *TasksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TasksRequest.DiscardUnknown(m)
 }
 
-type shimDiagClient struct {
-	client *github_com_containerd_ttrpc.Client
+var xxx_messageInfo_TasksRequest proto.InternalMessageInfo
+
+type TasksResponse struct {
+	Execs                []*TaskStateResponse `protobuf:"bytes,1,rep,name=execs,proto3" json:"execs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
-func NewShimDiagClient(client *github_com_containerd_ttrpc.Client) ShimDiagService {
-	return &shimDiagClient{
-		client: client,
+func (m *TasksResponse) Reset()      { *m = TasksResponse{} }
+func (*TasksResponse) ProtoMessage() {}
+func (*TasksResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{14}
+}
+func (m *TasksResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TasksResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TasksResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
 }
+func (m *TasksResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TasksResponse.Merge(m, src)
+}
+func (m *TasksResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *TasksResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TasksResponse.DiscardUnknown(m)
+}
 
-func (c *shimDiagClient) DiagExecInHost(ctx context.Context, req *ExecProcessRequest) (*ExecProcessResponse, error) {
-	var resp ExecProcessResponse
-	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagExecInHost", req, &resp); err != nil {
-		return nil, err
-	}
-	return &resp, nil
+var xxx_messageInfo_TasksResponse proto.InternalMessageInfo
+
+type ShareRequest struct {
+	HostPath             string   `protobuf:"bytes,1,opt,name=host_path,json=hostPath,proto3" json:"host_path,omitempty"`
+	UvmPath              string   `protobuf:"bytes,2,opt,name=uvm_path,json=uvmPath,proto3" json:"uvm_path,omitempty"`
+	ReadOnly             bool     `protobuf:"varint,3,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (c *shimDiagClient) DiagStacks(ctx context.Context, req *StacksRequest) (*StacksResponse, error) {
-	var resp StacksResponse
-	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagStacks", req, &resp); err != nil {
-		return nil, err
-	}
-	return &resp, nil
+func (m *ShareRequest) Reset()      { *m = ShareRequest{} }
+func (*ShareRequest) ProtoMessage() {}
+func (*ShareRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{15}
+}
+func (m *ShareRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ShareRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ShareRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ShareRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShareRequest.Merge(m, src)
+}
+func (m *ShareRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ShareRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShareRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShareRequest proto.InternalMessageInfo
+
+type ShareResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ShareResponse) Reset()      { *m = ShareResponse{} }
+func (*ShareResponse) ProtoMessage() {}
+func (*ShareResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{16}
+}
+func (m *ShareResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ShareResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ShareResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ShareResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShareResponse.Merge(m, src)
+}
+func (m *ShareResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ShareResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShareResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShareResponse proto.InternalMessageInfo
+
+type PprofRequest struct {
+	// One of heap, goroutine, mutex or cpu.
+	Profile string `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	// The sampling duration of the mutex and cpu profiles. Defaults to 30.
+	DurationSeconds      uint32   `protobuf:"varint,2,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PprofRequest) Reset()      { *m = PprofRequest{} }
+func (*PprofRequest) ProtoMessage() {}
+func (*PprofRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{17}
+}
+func (m *PprofRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PprofRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PprofRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PprofRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PprofRequest.Merge(m, src)
+}
+func (m *PprofRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *PprofRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PprofRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PprofRequest proto.InternalMessageInfo
+
+type PprofResponse struct {
+	Profile              []byte   `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PprofResponse) Reset()      { *m = PprofResponse{} }
+func (*PprofResponse) ProtoMessage() {}
+func (*PprofResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{18}
+}
+func (m *PprofResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PprofResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PprofResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PprofResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PprofResponse.Merge(m, src)
+}
+func (m *PprofResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *PprofResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PprofResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PprofResponse proto.InternalMessageInfo
+
+type ExportRootfsRequest struct {
+	TaskID string `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// The named pipe the tar stream of the root file system is written to.
+	Stdout               string   `protobuf:"bytes,2,opt,name=stdout,proto3" json:"stdout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportRootfsRequest) Reset()      { *m = ExportRootfsRequest{} }
+func (*ExportRootfsRequest) ProtoMessage() {}
+func (*ExportRootfsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{19}
+}
+func (m *ExportRootfsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExportRootfsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExportRootfsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExportRootfsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportRootfsRequest.Merge(m, src)
+}
+func (m *ExportRootfsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExportRootfsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportRootfsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportRootfsRequest proto.InternalMessageInfo
+
+type ExportRootfsResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportRootfsResponse) Reset()      { *m = ExportRootfsResponse{} }
+func (*ExportRootfsResponse) ProtoMessage() {}
+func (*ExportRootfsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{20}
+}
+func (m *ExportRootfsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExportRootfsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExportRootfsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExportRootfsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportRootfsResponse.Merge(m, src)
+}
+func (m *ExportRootfsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExportRootfsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportRootfsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportRootfsResponse proto.InternalMessageInfo
+
+// The features supported by a shim and its task. It is returned JSON encoded as
+// the `version` of the containerd `ConnectResponse`.
+type ShimCapabilities struct {
+	Version   string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	GitCommit string `protobuf:"bytes,2,opt,name=git_commit,json=gitCommit,proto3" json:"git_commit,omitempty"`
+	OsBuild   uint32 `protobuf:"varint,3,opt,name=os_build,json=osBuild,proto3" json:"os_build,omitempty"`
+	// Not set if the task has not been created.
+	Task                 *TaskCapabilities `protobuf:"bytes,4,opt,name=task,proto3" json:"task,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ShimCapabilities) Reset()      { *m = ShimCapabilities{} }
+func (*ShimCapabilities) ProtoMessage() {}
+func (*ShimCapabilities) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{21}
+}
+func (m *ShimCapabilities) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ShimCapabilities) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ShimCapabilities.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ShimCapabilities) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShimCapabilities.Merge(m, src)
+}
+func (m *ShimCapabilities) XXX_Size() int {
+	return m.Size()
+}
+func (m *ShimCapabilities) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShimCapabilities.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShimCapabilities proto.InternalMessageInfo
+
+type TaskCapabilities struct {
+	// Either "windows" or "linux".
+	Os       string `protobuf:"bytes,1,opt,name=os,proto3" json:"os,omitempty"`
+	Isolated bool   `protobuf:"varint,2,opt,name=isolated,proto3" json:"isolated,omitempty"`
+	// Signals other than a terminate or kill are delivered.
+	Signals bool `protobuf:"varint,3,opt,name=signals,proto3" json:"signals,omitempty"`
+	Pause   bool `protobuf:"varint,4,opt,name=pause,proto3" json:"pause,omitempty"`
+	Stats   bool `protobuf:"varint,5,opt,name=stats,proto3" json:"stats,omitempty"`
+	Update  bool `protobuf:"varint,6,opt,name=update,proto3" json:"update,omitempty"`
+	// The protocol version of the GCS in the host UVM if `isolated`.
+	GcsProtocol          uint32   `protobuf:"varint,7,opt,name=gcs_protocol,json=gcsProtocol,proto3" json:"gcs_protocol,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TaskCapabilities) Reset()      { *m = TaskCapabilities{} }
+func (*TaskCapabilities) ProtoMessage() {}
+func (*TaskCapabilities) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{22}
+}
+func (m *TaskCapabilities) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TaskCapabilities) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TaskCapabilities.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TaskCapabilities) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TaskCapabilities.Merge(m, src)
+}
+func (m *TaskCapabilities) XXX_Size() int {
+	return m.Size()
+}
+func (m *TaskCapabilities) XXX_DiscardUnknown() {
+	xxx_messageInfo_TaskCapabilities.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TaskCapabilities proto.InternalMessageInfo
+
+type EndpointRequest struct {
+	TaskID string `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// The HNS endpoint to hot add to, or remove from, the network namespace
+	// of the task in its utility VM.
+	EndpointID           string   `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	Remove               bool     `protobuf:"varint,3,opt,name=remove,proto3" json:"remove,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EndpointRequest) Reset()      { *m = EndpointRequest{} }
+func (*EndpointRequest) ProtoMessage() {}
+func (*EndpointRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{23}
+}
+func (m *EndpointRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *EndpointRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_EndpointRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *EndpointRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EndpointRequest.Merge(m, src)
+}
+func (m *EndpointRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *EndpointRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EndpointRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EndpointRequest proto.InternalMessageInfo
+
+type EndpointResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EndpointResponse) Reset()      { *m = EndpointResponse{} }
+func (*EndpointResponse) ProtoMessage() {}
+func (*EndpointResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{24}
+}
+func (m *EndpointResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *EndpointResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_EndpointResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *EndpointResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EndpointResponse.Merge(m, src)
+}
+func (m *EndpointResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *EndpointResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EndpointResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EndpointResponse proto.InternalMessageInfo
+
+type CPUGroupRequest struct {
+	TaskID string `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// The existing host CPU group to assign the utility VM of the task to. The
+	// null group ID removes it from its current CPU group.
+	CPUGroupID           string   `protobuf:"bytes,2,opt,name=cpu_group_id,json=cpuGroupId,proto3" json:"cpu_group_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CPUGroupRequest) Reset()      { *m = CPUGroupRequest{} }
+func (*CPUGroupRequest) ProtoMessage() {}
+func (*CPUGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{25}
+}
+func (m *CPUGroupRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CPUGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CPUGroupRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CPUGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CPUGroupRequest.Merge(m, src)
+}
+func (m *CPUGroupRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *CPUGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CPUGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CPUGroupRequest proto.InternalMessageInfo
+
+type CPUGroupResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CPUGroupResponse) Reset()      { *m = CPUGroupResponse{} }
+func (*CPUGroupResponse) ProtoMessage() {}
+func (*CPUGroupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{26}
+}
+func (m *CPUGroupResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CPUGroupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CPUGroupResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CPUGroupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CPUGroupResponse.Merge(m, src)
+}
+func (m *CPUGroupResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *CPUGroupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CPUGroupResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CPUGroupResponse proto.InternalMessageInfo
+
+type SaveUVMRequest struct {
+	TaskID string `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// The file the state of the utility VM of the task is saved to.
+	Path                 string   `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SaveUVMRequest) Reset()      { *m = SaveUVMRequest{} }
+func (*SaveUVMRequest) ProtoMessage() {}
+func (*SaveUVMRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{27}
+}
+func (m *SaveUVMRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SaveUVMRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SaveUVMRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SaveUVMRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SaveUVMRequest.Merge(m, src)
+}
+func (m *SaveUVMRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *SaveUVMRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SaveUVMRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SaveUVMRequest proto.InternalMessageInfo
+
+type SaveUVMResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SaveUVMResponse) Reset()      { *m = SaveUVMResponse{} }
+func (*SaveUVMResponse) ProtoMessage() {}
+func (*SaveUVMResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c7933dc6ffbb8784, []int{28}
+}
+func (m *SaveUVMResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SaveUVMResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SaveUVMResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SaveUVMResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SaveUVMResponse.Merge(m, src)
+}
+func (m *SaveUVMResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *SaveUVMResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SaveUVMResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SaveUVMResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*ExecProcessRequest)(nil), "containerd.runhcs.v1.diag.ExecProcessRequest")
+	proto.RegisterType((*ExecProcessResponse)(nil), "containerd.runhcs.v1.diag.ExecProcessResponse")
+	proto.RegisterType((*StacksRequest)(nil), "containerd.runhcs.v1.diag.StacksRequest")
+	proto.RegisterType((*StacksResponse)(nil), "containerd.runhcs.v1.diag.StacksResponse")
+	proto.RegisterType((*TaskStateRequest)(nil), "containerd.runhcs.v1.diag.TaskStateRequest")
+	proto.RegisterType((*TaskStateResponse)(nil), "containerd.runhcs.v1.diag.TaskStateResponse")
+	proto.RegisterType((*AttachedDevice)(nil), "containerd.runhcs.v1.diag.AttachedDevice")
+	proto.RegisterType((*ReloadConfigRequest)(nil), "containerd.runhcs.v1.diag.ReloadConfigRequest")
+	proto.RegisterType((*ReloadConfigResponse)(nil), "containerd.runhcs.v1.diag.ReloadConfigResponse")
+	proto.RegisterType((*LastCrashRequest)(nil), "containerd.runhcs.v1.diag.LastCrashRequest")
+	proto.RegisterType((*LastCrashResponse)(nil), "containerd.runhcs.v1.diag.LastCrashResponse")
+	proto.RegisterType((*ConsoleRequest)(nil), "containerd.runhcs.v1.diag.ConsoleRequest")
+	proto.RegisterType((*ConsoleResponse)(nil), "containerd.runhcs.v1.diag.ConsoleResponse")
+	proto.RegisterType((*TasksRequest)(nil), "containerd.runhcs.v1.diag.TasksRequest")
+	proto.RegisterType((*TasksResponse)(nil), "containerd.runhcs.v1.diag.TasksResponse")
+	proto.RegisterType((*ShareRequest)(nil), "containerd.runhcs.v1.diag.ShareRequest")
+	proto.RegisterType((*ShareResponse)(nil), "containerd.runhcs.v1.diag.ShareResponse")
+	proto.RegisterType((*PprofRequest)(nil), "containerd.runhcs.v1.diag.PprofRequest")
+	proto.RegisterType((*PprofResponse)(nil), "containerd.runhcs.v1.diag.PprofResponse")
+	proto.RegisterType((*ExportRootfsRequest)(nil), "containerd.runhcs.v1.diag.ExportRootfsRequest")
+	proto.RegisterType((*ExportRootfsResponse)(nil), "containerd.runhcs.v1.diag.ExportRootfsResponse")
+	proto.RegisterType((*ShimCapabilities)(nil), "containerd.runhcs.v1.diag.ShimCapabilities")
+	proto.RegisterType((*TaskCapabilities)(nil), "containerd.runhcs.v1.diag.TaskCapabilities")
+	proto.RegisterType((*EndpointRequest)(nil), "containerd.runhcs.v1.diag.EndpointRequest")
+	proto.RegisterType((*EndpointResponse)(nil), "containerd.runhcs.v1.diag.EndpointResponse")
+	proto.RegisterType((*CPUGroupRequest)(nil), "containerd.runhcs.v1.diag.CPUGroupRequest")
+	proto.RegisterType((*CPUGroupResponse)(nil), "containerd.runhcs.v1.diag.CPUGroupResponse")
+	proto.RegisterType((*SaveUVMRequest)(nil), "containerd.runhcs.v1.diag.SaveUVMRequest")
+	proto.RegisterType((*SaveUVMResponse)(nil), "containerd.runhcs.v1.diag.SaveUVMResponse")
+}
+
+func init() {
+	proto.RegisterFile("github.com/Microsoft/hcsshim/internal/shimdiag/shimdiag.proto", fileDescriptor_c7933dc6ffbb8784)
+}
+
+var fileDescriptor_c7933dc6ffbb8784 = []byte{
+	// 1435 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x57, 0x5f, 0x6f, 0x1b, 0x37,
+	0x12, 0x8f, 0xfc, 0x57, 0x1a, 0xc9, 0xb2, 0xcc, 0xf8, 0x72, 0x1b, 0x05, 0x67, 0x3b, 0x7b, 0xc0,
+	0x9d, 0x1c, 0xe7, 0xa4, 0x3b, 0xdf, 0x43, 0xee, 0x10, 0xb4, 0x41, 0x2d, 0x07, 0xad, 0xd1, 0x26,
+	0x75, 0x56, 0x49, 0x5b, 0x14, 0x45, 0x17, 0xf4, 0x2e, 0xbd, 0x62, 0x2d, 0x2d, 0xb7, 0x4b, 0xae,
+	0x1a, 0x3f, 0xb5, 0x1f, 0xa6, 0x40, 0xbf, 0x45, 0xfb, 0x9a, 0xc7, 0x3e, 0xf6, 0xc9, 0x68, 0xfc,
+	0x49, 0x8a, 0x21, 0xb9, 0x6b, 0xc9, 0x49, 0x64, 0x19, 0xe8, 0x93, 0x38, 0xc3, 0xdf, 0xfc, 0x21,
+	0x67, 0xf8, 0x9b, 0x15, 0xbc, 0x17, 0x71, 0xd5, 0xcf, 0x8e, 0xda, 0x81, 0x18, 0x76, 0x9e, 0xf0,
+	0x20, 0x15, 0x52, 0x1c, 0xab, 0x4e, 0x3f, 0x90, 0xb2, 0xcf, 0x87, 0x1d, 0x1e, 0x2b, 0x96, 0xc6,
+	0x74, 0xd0, 0x41, 0x29, 0xe4, 0x34, 0x2a, 0x16, 0xed, 0x24, 0x15, 0x4a, 0x90, 0xdb, 0x81, 0x88,
+	0x15, 0xe5, 0x31, 0x4b, 0xc3, 0x76, 0x9a, 0xc5, 0xfd, 0x40, 0xb6, 0x47, 0xff, 0x69, 0x23, 0xa0,
+	0xb9, 0x1e, 0x89, 0x48, 0x68, 0x54, 0x07, 0x57, 0xc6, 0xc0, 0xfd, 0xb1, 0x04, 0xe4, 0xf1, 0x4b,
+	0x16, 0x1c, 0xa6, 0x22, 0x60, 0x52, 0x7a, 0xec, 0xdb, 0x8c, 0x49, 0x45, 0x08, 0x2c, 0xd0, 0x34,
+	0x92, 0x4e, 0x69, 0x6b, 0xbe, 0x55, 0xf1, 0xf4, 0x9a, 0x38, 0xb0, 0xfc, 0x9d, 0x48, 0x4f, 0x42,
+	0x9e, 0x3a, 0x73, 0x5b, 0xa5, 0x56, 0xc5, 0xcb, 0x45, 0xd2, 0x84, 0xb2, 0x62, 0xe9, 0x90, 0xc7,
+	0x74, 0xe0, 0xcc, 0x6f, 0x95, 0x5a, 0x65, 0xaf, 0x90, 0xc9, 0x3a, 0x2c, 0x4a, 0x15, 0xf2, 0xd8,
+	0x59, 0xd0, 0x36, 0x46, 0x20, 0xb7, 0x60, 0x49, 0xaa, 0x50, 0x64, 0xca, 0x59, 0xd4, 0x6a, 0x2b,
+	0x59, 0x3d, 0x4b, 0x53, 0x67, 0xa9, 0xd0, 0xb3, 0x34, 0x75, 0x77, 0xe1, 0xe6, 0x44, 0x96, 0x32,
+	0x11, 0xb1, 0x64, 0xe4, 0x0e, 0x54, 0xd8, 0x4b, 0xae, 0xfc, 0x40, 0x84, 0xcc, 0x29, 0x6d, 0x95,
+	0x5a, 0x8b, 0x5e, 0x19, 0x15, 0x5d, 0x11, 0x32, 0x77, 0x15, 0x56, 0x7a, 0x8a, 0x06, 0x27, 0xf9,
+	0xa1, 0xdc, 0x8f, 0xa1, 0x9e, 0x2b, 0xac, 0xbd, 0x0e, 0x87, 0x1a, 0xa7, 0x94, 0x87, 0x43, 0x89,
+	0xdc, 0x85, 0x5a, 0x84, 0x26, 0xbe, 0xdd, 0x35, 0xe7, 0xad, 0x6a, 0x9d, 0x71, 0xe1, 0x7e, 0x05,
+	0x8d, 0xe7, 0x54, 0x9e, 0xf4, 0x14, 0x55, 0x2c, 0xbf, 0xb5, 0xbf, 0xc3, 0xb2, 0xa2, 0xf2, 0xc4,
+	0xe7, 0xa1, 0xf1, 0xb7, 0x07, 0xe7, 0x67, 0x9b, 0x4b, 0x08, 0x3b, 0xd8, 0xf7, 0x96, 0x70, 0xeb,
+	0x20, 0x44, 0x10, 0x7b, 0xc9, 0x02, 0x04, 0xcd, 0x5d, 0x80, 0xf0, 0x74, 0x08, 0xc2, 0xad, 0x83,
+	0xd0, 0xfd, 0x79, 0x01, 0xd6, 0xc6, 0xdc, 0xdb, 0x74, 0xff, 0x34, 0xff, 0xa4, 0x01, 0xf3, 0x09,
+	0x0f, 0x75, 0xb1, 0x56, 0x3c, 0x5c, 0xda, 0xab, 0x50, 0x99, 0xb4, 0x85, 0xb2, 0x12, 0xd9, 0x84,
+	0xaa, 0xbe, 0x62, 0xbb, 0xb9, 0xa8, 0x2d, 0x00, 0x55, 0x3d, 0x03, 0xf8, 0x3f, 0xdc, 0x1e, 0xb2,
+	0xa1, 0x48, 0x4f, 0xfd, 0x4c, 0xd2, 0x88, 0xf9, 0x81, 0x18, 0x0e, 0xb9, 0xf2, 0x8f, 0x4e, 0x15,
+	0x93, 0xba, 0x8a, 0x0b, 0xde, 0x2d, 0x03, 0x78, 0x81, 0xfb, 0x5d, 0xbd, 0xbd, 0x87, 0xbb, 0xe4,
+	0x19, 0xfc, 0x63, 0xc2, 0x34, 0x49, 0xf9, 0x88, 0x2a, 0xe6, 0x63, 0x5f, 0xf1, 0x38, 0xf2, 0x25,
+	0xcb, 0xfd, 0x2c, 0x6b, 0x3f, 0x77, 0xc7, 0xfc, 0x1c, 0x1a, 0xec, 0xe7, 0x06, 0xda, 0x63, 0xd6,
+	0xe5, 0x43, 0x68, 0x26, 0xa6, 0x49, 0x44, 0xea, 0x2b, 0xa1, 0xe8, 0xc0, 0x4f, 0xb3, 0x58, 0xf1,
+	0x21, 0xf3, 0x63, 0xe9, 0x94, 0xb5, 0x9b, 0xbf, 0x16, 0x88, 0xe7, 0x08, 0xf0, 0xcc, 0xfe, 0x53,
+	0x49, 0xba, 0xb0, 0x1c, 0xb2, 0x11, 0x0f, 0x98, 0x74, 0x2a, 0x5b, 0xf3, 0xad, 0xea, 0xee, 0x76,
+	0xfb, 0x9d, 0xef, 0xa9, 0xfd, 0x81, 0x52, 0x34, 0xe8, 0xb3, 0x70, 0x5f, 0x5b, 0x78, 0xb9, 0x25,
+	0xd9, 0x81, 0xb5, 0x98, 0x29, 0x3c, 0x82, 0x1f, 0xd3, 0x21, 0x93, 0x09, 0x0d, 0x98, 0x03, 0xfa,
+	0x4e, 0x1b, 0x76, 0xe3, 0x69, 0xae, 0x27, 0xbb, 0x50, 0x63, 0x71, 0x98, 0x08, 0x1e, 0x2b, 0x9f,
+	0x87, 0xd2, 0xa9, 0xe2, 0x7b, 0xdb, 0x5b, 0x3d, 0x3f, 0xdb, 0xac, 0x3e, 0xb6, 0xfa, 0x83, 0x7d,
+	0xe9, 0x55, 0x73, 0xd0, 0x41, 0x28, 0xb1, 0xc0, 0x7d, 0x21, 0x11, 0xef, 0xd4, 0x2e, 0x0a, 0xfc,
+	0x91, 0x90, 0x0a, 0x0b, 0x8c, 0x5b, 0x07, 0xa1, 0xfb, 0x3f, 0xa8, 0x4f, 0x26, 0x88, 0x4f, 0x5a,
+	0x9d, 0x26, 0xcc, 0x76, 0xba, 0x5e, 0xa3, 0x2e, 0xa1, 0xaa, 0x6f, 0xfb, 0x5b, 0xaf, 0xdd, 0xbf,
+	0xc0, 0x4d, 0x8f, 0x0d, 0x04, 0x0d, 0xbb, 0x22, 0x3e, 0xe6, 0x51, 0xfe, 0x78, 0x1e, 0xc0, 0xfa,
+	0xa4, 0xda, 0xf6, 0xe4, 0x26, 0x54, 0x03, 0xad, 0xf1, 0xb5, 0x27, 0xe3, 0x1d, 0x8c, 0xea, 0x10,
+	0xfd, 0x11, 0x68, 0x7c, 0x42, 0xa5, 0xea, 0xa6, 0x54, 0xf6, 0x73, 0x67, 0x8f, 0x60, 0x6d, 0x4c,
+	0x67, 0x3d, 0xe5, 0xc9, 0x94, 0x2e, 0x92, 0xc1, 0xae, 0x4c, 0x59, 0x22, 0x52, 0x65, 0x53, 0xb4,
+	0x92, 0xfb, 0x3e, 0xd4, 0xbb, 0x22, 0x96, 0x62, 0x50, 0xbc, 0xbd, 0x82, 0x67, 0x4a, 0x6f, 0xe7,
+	0x99, 0xb9, 0x71, 0x9e, 0x71, 0xd7, 0x60, 0xb5, 0xb0, 0x37, 0xe1, 0xdd, 0x3a, 0xd4, 0xf0, 0x25,
+	0x15, 0x6c, 0xd1, 0x83, 0x15, 0x2b, 0xdb, 0xfc, 0xf6, 0x60, 0x11, 0x5f, 0x8f, 0x21, 0xc5, 0xea,
+	0xee, 0xfd, 0x29, 0xbd, 0xf1, 0xc6, 0xd3, 0xf5, 0x8c, 0xa9, 0x1b, 0x40, 0xad, 0xd7, 0xa7, 0x69,
+	0x91, 0xf5, 0x1d, 0xa8, 0xe8, 0x5a, 0x8e, 0x1d, 0xbc, 0x8c, 0x0a, 0xbc, 0x39, 0x72, 0x1b, 0xca,
+	0xd9, 0x68, 0xe8, 0x8f, 0x55, 0x68, 0x39, 0x1b, 0x0d, 0xf5, 0xd6, 0x1d, 0xa8, 0xa4, 0x8c, 0x86,
+	0xbe, 0x88, 0x07, 0xa7, 0x39, 0xe5, 0xa2, 0xe2, 0xd3, 0x78, 0x70, 0xaa, 0x89, 0xcf, 0x04, 0xb1,
+	0x47, 0xeb, 0x41, 0xed, 0x30, 0x49, 0xc5, 0x71, 0x1e, 0xd5, 0x81, 0x65, 0x14, 0xf9, 0x20, 0xef,
+	0x86, 0x5c, 0x24, 0xdb, 0xd0, 0x08, 0xb3, 0x94, 0x2a, 0x2e, 0x62, 0x5f, 0xb2, 0x40, 0xc4, 0xa1,
+	0x21, 0xbf, 0x15, 0x6f, 0x35, 0xd7, 0xf7, 0x8c, 0xda, 0xdd, 0x86, 0x15, 0xeb, 0xd4, 0xde, 0xcf,
+	0x25, 0xaf, 0xb5, 0xc2, 0xab, 0xeb, 0x21, 0x7b, 0x63, 0xdd, 0x3c, 0x21, 0xd4, 0xb1, 0xbc, 0x16,
+	0x5d, 0xbe, 0xab, 0x82, 0xb7, 0x60, 0x7d, 0xd2, 0xa7, 0x3d, 0xeb, 0x4f, 0x25, 0x68, 0xf4, 0xfa,
+	0x7c, 0xd8, 0xa5, 0x09, 0x3d, 0xe2, 0x03, 0xae, 0x38, 0xd3, 0xa3, 0x6b, 0xc4, 0x52, 0xc9, 0x45,
+	0xde, 0x1e, 0xb9, 0x48, 0xfe, 0x06, 0x10, 0xe9, 0x01, 0x82, 0xac, 0x64, 0x43, 0x54, 0x22, 0x9c,
+	0x20, 0xa8, 0xc0, 0x12, 0x08, 0xe9, 0x1f, 0x65, 0x7c, 0x90, 0x93, 0xe5, 0xb2, 0x90, 0x7b, 0x28,
+	0x92, 0x47, 0xb0, 0x80, 0x29, 0x6a, 0xba, 0xac, 0xee, 0xee, 0x5c, 0xd1, 0x0d, 0xe3, 0xe9, 0x78,
+	0xda, 0xd0, 0xfd, 0xa5, 0x04, 0x8d, 0xcb, 0x5b, 0xa4, 0x0e, 0x73, 0x22, 0x9f, 0x46, 0x73, 0x42,
+	0xe2, 0x68, 0xe5, 0x52, 0x0c, 0xa8, 0x62, 0x86, 0xce, 0xcb, 0x5e, 0x21, 0xe3, 0xa9, 0x24, 0x8f,
+	0x62, 0x3a, 0x90, 0xb6, 0x05, 0x72, 0x11, 0x1f, 0x43, 0x42, 0x33, 0xc9, 0x74, 0x72, 0x65, 0xcf,
+	0x08, 0xe6, 0x89, 0x50, 0x65, 0x48, 0xbc, 0xec, 0x19, 0x01, 0x2f, 0x38, 0x4b, 0x42, 0xaa, 0x98,
+	0x26, 0xeb, 0xb2, 0x67, 0x25, 0x3d, 0x03, 0x03, 0xe9, 0xeb, 0xcf, 0x84, 0x40, 0x0c, 0x34, 0x05,
+	0xaf, 0x78, 0xd5, 0x28, 0x90, 0x87, 0x56, 0xe5, 0x7e, 0x0f, 0xab, 0x39, 0x4b, 0x5d, 0xab, 0xa6,
+	0x1d, 0xa8, 0x8e, 0xb1, 0x9e, 0x1d, 0x53, 0xf5, 0xf3, 0xb3, 0x4d, 0xb8, 0x20, 0x3d, 0x0f, 0x2e,
+	0x38, 0xcf, 0xd0, 0xc0, 0x50, 0x8c, 0x98, 0x3d, 0xa8, 0x95, 0x90, 0x5b, 0x2e, 0x12, 0xb0, 0x0d,
+	0xd0, 0x87, 0xd5, 0xee, 0xe1, 0x8b, 0x0f, 0x53, 0x91, 0x25, 0xd7, 0x4a, 0xea, 0xdf, 0x50, 0x0b,
+	0x92, 0xcc, 0x8f, 0xd0, 0xf0, 0x52, 0x56, 0xb9, 0x3f, 0xcc, 0x2a, 0x48, 0x32, 0xb3, 0x0e, 0x31,
+	0xfa, 0x45, 0x24, 0x1b, 0xfd, 0x00, 0xea, 0x3d, 0x3a, 0x62, 0x2f, 0x3e, 0x7b, 0x72, 0xad, 0xe0,
+	0x6f, 0x23, 0xe2, 0x35, 0x58, 0x2d, 0x5c, 0x19, 0xef, 0xbb, 0x67, 0x00, 0x65, 0x6c, 0xee, 0x7d,
+	0x4e, 0x23, 0x22, 0xa0, 0x8e, 0xbf, 0x7a, 0xb2, 0xc7, 0x48, 0xff, 0xe4, 0x5f, 0x53, 0x9a, 0xf0,
+	0xcd, 0x8f, 0xbc, 0x66, 0x7b, 0x56, 0xb8, 0x7d, 0xe0, 0x14, 0x00, 0x03, 0x9a, 0x0f, 0x20, 0xd2,
+	0x9a, 0x62, 0x3d, 0xf1, 0xdd, 0xd5, 0xdc, 0x9e, 0x01, 0x69, 0x43, 0x7c, 0x03, 0x2b, 0x18, 0xa2,
+	0xe0, 0x4f, 0xb2, 0x33, 0x1b, 0xcb, 0x9a, 0x40, 0xd7, 0xa2, 0x64, 0x22, 0xa1, 0x81, 0xb1, 0xc6,
+	0xa7, 0x1a, 0x99, 0x76, 0x25, 0x6f, 0x99, 0x8a, 0xcd, 0xce, 0xcc, 0xf8, 0xc9, 0x03, 0x16, 0xd3,
+	0x6f, 0xea, 0x01, 0x2f, 0xcf, 0xcd, 0xe6, 0xfd, 0xd9, 0xc0, 0x36, 0x56, 0x08, 0x55, 0x8c, 0x65,
+	0x07, 0x1d, 0x99, 0x56, 0x86, 0xc9, 0x61, 0xda, 0xbc, 0x37, 0x0b, 0xd4, 0x46, 0xf9, 0x1a, 0x2a,
+	0x79, 0xc9, 0x24, 0xf9, 0xe7, 0x15, 0x15, 0x28, 0x7a, 0xa2, 0x75, 0x35, 0x70, 0xd2, 0xbf, 0x9e,
+	0x68, 0x53, 0xfd, 0x8f, 0x0f, 0xd6, 0x66, 0xeb, 0x6a, 0xe0, 0xa4, 0x7f, 0x3d, 0xcb, 0xa6, 0xfa,
+	0x1f, 0x1f, 0xa1, 0xcd, 0xd6, 0xd5, 0xc0, 0xc9, 0x36, 0x1b, 0x1f, 0x56, 0x64, 0xfa, 0xcb, 0x7b,
+	0x63, 0x52, 0x36, 0x3b, 0x33, 0xe3, 0x6d, 0xd0, 0x08, 0x6a, 0x3a, 0xa8, 0x25, 0x47, 0x32, 0xad,
+	0xa0, 0x97, 0x28, 0xbc, 0xb9, 0x33, 0x13, 0x76, 0x32, 0x50, 0xce, 0x83, 0x53, 0x03, 0x5d, 0xa2,
+	0xe5, 0xe6, 0xce, 0x4c, 0xd8, 0xc9, 0x66, 0xb6, 0x8c, 0x38, 0xb5, 0x99, 0x27, 0x09, 0xb8, 0x79,
+	0x6f, 0x16, 0xa8, 0x89, 0xb2, 0xf7, 0xec, 0xd5, 0xeb, 0x8d, 0x1b, 0xbf, 0xbd, 0xde, 0xb8, 0xf1,
+	0xc3, 0xf9, 0x46, 0xe9, 0xd5, 0xf9, 0x46, 0xe9, 0xd7, 0xf3, 0x8d, 0xd2, 0xef, 0xe7, 0x1b, 0xa5,
+	0x2f, 0x1f, 0x5c, 0xef, 0x8f, 0xf9, 0xc3, 0x7c, 0xf1, 0xc5, 0x8d, 0xa3, 0x25, 0x3d, 0x43, 0xff,
+	0xfb, 0xc7, 0x00, 0x05, 0x8a, 0x70, 0x47, 0xdc, 0x0f, 0x00, 0x00,
+}
+
+func (m *ExecProcessRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExecProcessRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Args) > 0 {
+		for _, s := range m.Args {
+			dAtA[i] = 0xa
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.Workdir) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Workdir)))
+		i += copy(dAtA[i:], m.Workdir)
+	}
+	if m.Terminal {
+		dAtA[i] = 0x18
+		i++
+		if m.Terminal {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if len(m.Stdin) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Stdin)))
+		i += copy(dAtA[i:], m.Stdin)
+	}
+	if len(m.Stdout) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Stdout)))
+		i += copy(dAtA[i:], m.Stdout)
+	}
+	if len(m.Stderr) > 0 {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Stderr)))
+		i += copy(dAtA[i:], m.Stderr)
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *ExecProcessResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExecProcessResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.ExitCode != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.ExitCode))
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *StacksRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StacksRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *StacksResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StacksResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Stacks) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Stacks)))
+		i += copy(dAtA[i:], m.Stacks)
+	}
+	if len(m.GuestStacks) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.GuestStacks)))
+		i += copy(dAtA[i:], m.GuestStacks)
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *TaskStateRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TaskStateRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.TaskID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.TaskID)))
+		i += copy(dAtA[i:], m.TaskID)
+	}
+	if len(m.ExecID) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.ExecID)))
+		i += copy(dAtA[i:], m.ExecID)
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *TaskStateResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TaskStateResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.TaskID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.TaskID)))
+		i += copy(dAtA[i:], m.TaskID)
+	}
+	if len(m.ExecID) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.ExecID)))
+		i += copy(dAtA[i:], m.ExecID)
+	}
+	if m.Pid != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.Pid))
+	}
+	if len(m.Status) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Status)))
+		i += copy(dAtA[i:], m.Status)
+	}
+	if m.ExitStatus != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.ExitStatus))
+	}
+	if m.MemoryUsageCommitBytes != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.MemoryUsageCommitBytes))
+	}
+	if m.MemoryUsagePrivateWorkingSetBytes != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.MemoryUsagePrivateWorkingSetBytes))
+	}
+	if m.ProcessorTotalRuntimeNs != 0 {
+		dAtA[i] = 0x40
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.ProcessorTotalRuntimeNs))
+	}
+	if len(m.Devices) > 0 {
+		for _, msg := range m.Devices {
+			dAtA[i] = 0x4a
+			i++
+			i = encodeVarintShimdiag(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.NetworkNamespace) > 0 {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.NetworkNamespace)))
+		i += copy(dAtA[i:], m.NetworkNamespace)
+	}
+	if len(m.EndpointIDs) > 0 {
+		for _, s := range m.EndpointIDs {
+			dAtA[i] = 0x5a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.HostID) > 0 {
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.HostID)))
+		i += copy(dAtA[i:], m.HostID)
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *AttachedDevice) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AttachedDevice) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Type) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Type)))
+		i += copy(dAtA[i:], m.Type)
+	}
+	if len(m.Path) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Path)))
+		i += copy(dAtA[i:], m.Path)
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *ReloadConfigRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReloadConfigRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *ReloadConfigResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReloadConfigResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.ConfigPath) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.ConfigPath)))
+		i += copy(dAtA[i:], m.ConfigPath)
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *LastCrashRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LastCrashRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *LastCrashResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LastCrashResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Path) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Path)))
+		i += copy(dAtA[i:], m.Path)
+	}
+	if len(m.Report) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Report)))
+		i += copy(dAtA[i:], m.Report)
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *ConsoleRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ConsoleRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Stdin) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Stdin)))
+		i += copy(dAtA[i:], m.Stdin)
+	}
+	if len(m.Stdout) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Stdout)))
+		i += copy(dAtA[i:], m.Stdout)
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *ConsoleResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ConsoleResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *TasksRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TasksRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *TasksResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TasksResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Execs) > 0 {
+		for _, msg := range m.Execs {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintShimdiag(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *ShareRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ShareRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.HostPath) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.HostPath)))
+		i += copy(dAtA[i:], m.HostPath)
+	}
+	if len(m.UvmPath) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.UvmPath)))
+		i += copy(dAtA[i:], m.UvmPath)
+	}
+	if m.ReadOnly {
+		dAtA[i] = 0x18
+		i++
+		if m.ReadOnly {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *ShareResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ShareResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *PprofRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PprofRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Profile) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Profile)))
+		i += copy(dAtA[i:], m.Profile)
+	}
+	if m.DurationSeconds != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.DurationSeconds))
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *PprofResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PprofResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Profile) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Profile)))
+		i += copy(dAtA[i:], m.Profile)
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *ExportRootfsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExportRootfsRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.TaskID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.TaskID)))
+		i += copy(dAtA[i:], m.TaskID)
+	}
+	if len(m.Stdout) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Stdout)))
+		i += copy(dAtA[i:], m.Stdout)
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *ExportRootfsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExportRootfsResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *ShimCapabilities) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ShimCapabilities) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Version) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Version)))
+		i += copy(dAtA[i:], m.Version)
+	}
+	if len(m.GitCommit) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.GitCommit)))
+		i += copy(dAtA[i:], m.GitCommit)
+	}
+	if m.OsBuild != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.OsBuild))
+	}
+	if m.Task != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.Task.Size()))
+		n1, err := m.Task.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *TaskCapabilities) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TaskCapabilities) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Os) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Os)))
+		i += copy(dAtA[i:], m.Os)
+	}
+	if m.Isolated {
+		dAtA[i] = 0x10
+		i++
+		if m.Isolated {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Signals {
+		dAtA[i] = 0x18
+		i++
+		if m.Signals {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Pause {
+		dAtA[i] = 0x20
+		i++
+		if m.Pause {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Stats {
+		dAtA[i] = 0x28
+		i++
+		if m.Stats {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Update {
+		dAtA[i] = 0x30
+		i++
+		if m.Update {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.GcsProtocol != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(m.GcsProtocol))
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *EndpointRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *EndpointRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.TaskID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.TaskID)))
+		i += copy(dAtA[i:], m.TaskID)
+	}
+	if len(m.EndpointID) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.EndpointID)))
+		i += copy(dAtA[i:], m.EndpointID)
+	}
+	if m.Remove {
+		dAtA[i] = 0x18
+		i++
+		if m.Remove {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *EndpointResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *EndpointResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *CPUGroupRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CPUGroupRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.TaskID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.TaskID)))
+		i += copy(dAtA[i:], m.TaskID)
+	}
+	if len(m.CPUGroupID) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.CPUGroupID)))
+		i += copy(dAtA[i:], m.CPUGroupID)
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *CPUGroupResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CPUGroupResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *SaveUVMRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SaveUVMRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.TaskID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.TaskID)))
+		i += copy(dAtA[i:], m.TaskID)
+	}
+	if len(m.Path) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintShimdiag(dAtA, i, uint64(len(m.Path)))
+		i += copy(dAtA[i:], m.Path)
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *SaveUVMResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SaveUVMResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func encodeVarintShimdiag(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *ExecProcessRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Args) > 0 {
+		for _, s := range m.Args {
+			l = len(s)
+			n += 1 + l + sovShimdiag(uint64(l))
+		}
+	}
+	l = len(m.Workdir)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.Terminal {
+		n += 2
+	}
+	l = len(m.Stdin)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.Stdout)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.Stderr)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ExecProcessResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ExitCode != 0 {
+		n += 1 + sovShimdiag(uint64(m.ExitCode))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *StacksRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *StacksResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Stacks)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.GuestStacks)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *TaskStateRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TaskID)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.ExecID)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *TaskStateResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TaskID)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.ExecID)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.Pid != 0 {
+		n += 1 + sovShimdiag(uint64(m.Pid))
+	}
+	l = len(m.Status)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.ExitStatus != 0 {
+		n += 1 + sovShimdiag(uint64(m.ExitStatus))
+	}
+	if m.MemoryUsageCommitBytes != 0 {
+		n += 1 + sovShimdiag(uint64(m.MemoryUsageCommitBytes))
+	}
+	if m.MemoryUsagePrivateWorkingSetBytes != 0 {
+		n += 1 + sovShimdiag(uint64(m.MemoryUsagePrivateWorkingSetBytes))
+	}
+	if m.ProcessorTotalRuntimeNs != 0 {
+		n += 1 + sovShimdiag(uint64(m.ProcessorTotalRuntimeNs))
+	}
+	if len(m.Devices) > 0 {
+		for _, e := range m.Devices {
+			l = e.Size()
+			n += 1 + l + sovShimdiag(uint64(l))
+		}
+	}
+	l = len(m.NetworkNamespace)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if len(m.EndpointIDs) > 0 {
+		for _, s := range m.EndpointIDs {
+			l = len(s)
+			n += 1 + l + sovShimdiag(uint64(l))
+		}
+	}
+	l = len(m.HostID)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *AttachedDevice) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Type)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ReloadConfigRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ReloadConfigResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ConfigPath)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *LastCrashRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *LastCrashResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.Report)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ConsoleRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Stdin)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.Stdout)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ConsoleResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *TasksRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *TasksResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Execs) > 0 {
+		for _, e := range m.Execs {
+			l = e.Size()
+			n += 1 + l + sovShimdiag(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ShareRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.HostPath)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.UvmPath)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.ReadOnly {
+		n += 2
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ShareResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *PprofRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Profile)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.DurationSeconds != 0 {
+		n += 1 + sovShimdiag(uint64(m.DurationSeconds))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *PprofResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Profile)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ExportRootfsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TaskID)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.Stdout)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ExportRootfsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ShimCapabilities) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Version)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.GitCommit)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.OsBuild != 0 {
+		n += 1 + sovShimdiag(uint64(m.OsBuild))
+	}
+	if m.Task != nil {
+		l = m.Task.Size()
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *TaskCapabilities) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Os)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.Isolated {
+		n += 2
+	}
+	if m.Signals {
+		n += 2
+	}
+	if m.Pause {
+		n += 2
+	}
+	if m.Stats {
+		n += 2
+	}
+	if m.Update {
+		n += 2
+	}
+	if m.GcsProtocol != 0 {
+		n += 1 + sovShimdiag(uint64(m.GcsProtocol))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *EndpointRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TaskID)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.EndpointID)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.Remove {
+		n += 2
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *EndpointResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *CPUGroupRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TaskID)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.CPUGroupID)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *CPUGroupResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *SaveUVMRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TaskID)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovShimdiag(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *SaveUVMResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func sovShimdiag(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozShimdiag(x uint64) (n int) {
+	return sovShimdiag(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *ExecProcessRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ExecProcessRequest{`,
+		`Args:` + fmt.Sprintf("%v", this.Args) + `,`,
+		`Workdir:` + fmt.Sprintf("%v", this.Workdir) + `,`,
+		`Terminal:` + fmt.Sprintf("%v", this.Terminal) + `,`,
+		`Stdin:` + fmt.Sprintf("%v", this.Stdin) + `,`,
+		`Stdout:` + fmt.Sprintf("%v", this.Stdout) + `,`,
+		`Stderr:` + fmt.Sprintf("%v", this.Stderr) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ExecProcessResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ExecProcessResponse{`,
+		`ExitCode:` + fmt.Sprintf("%v", this.ExitCode) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StacksRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StacksRequest{`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StacksResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StacksResponse{`,
+		`Stacks:` + fmt.Sprintf("%v", this.Stacks) + `,`,
+		`GuestStacks:` + fmt.Sprintf("%v", this.GuestStacks) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TaskStateRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TaskStateRequest{`,
+		`TaskID:` + fmt.Sprintf("%v", this.TaskID) + `,`,
+		`ExecID:` + fmt.Sprintf("%v", this.ExecID) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TaskStateResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TaskStateResponse{`,
+		`TaskID:` + fmt.Sprintf("%v", this.TaskID) + `,`,
+		`ExecID:` + fmt.Sprintf("%v", this.ExecID) + `,`,
+		`Pid:` + fmt.Sprintf("%v", this.Pid) + `,`,
+		`Status:` + fmt.Sprintf("%v", this.Status) + `,`,
+		`ExitStatus:` + fmt.Sprintf("%v", this.ExitStatus) + `,`,
+		`MemoryUsageCommitBytes:` + fmt.Sprintf("%v", this.MemoryUsageCommitBytes) + `,`,
+		`MemoryUsagePrivateWorkingSetBytes:` + fmt.Sprintf("%v", this.MemoryUsagePrivateWorkingSetBytes) + `,`,
+		`ProcessorTotalRuntimeNs:` + fmt.Sprintf("%v", this.ProcessorTotalRuntimeNs) + `,`,
+		`Devices:` + strings.Replace(fmt.Sprintf("%v", this.Devices), "AttachedDevice", "AttachedDevice", 1) + `,`,
+		`NetworkNamespace:` + fmt.Sprintf("%v", this.NetworkNamespace) + `,`,
+		`EndpointIDs:` + fmt.Sprintf("%v", this.EndpointIDs) + `,`,
+		`HostID:` + fmt.Sprintf("%v", this.HostID) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *AttachedDevice) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&AttachedDevice{`,
+		`Type:` + fmt.Sprintf("%v", this.Type) + `,`,
+		`Path:` + fmt.Sprintf("%v", this.Path) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ReloadConfigRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ReloadConfigRequest{`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ReloadConfigResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ReloadConfigResponse{`,
+		`ConfigPath:` + fmt.Sprintf("%v", this.ConfigPath) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LastCrashRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LastCrashRequest{`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LastCrashResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LastCrashResponse{`,
+		`Path:` + fmt.Sprintf("%v", this.Path) + `,`,
+		`Report:` + fmt.Sprintf("%v", this.Report) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ConsoleRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ConsoleRequest{`,
+		`Stdin:` + fmt.Sprintf("%v", this.Stdin) + `,`,
+		`Stdout:` + fmt.Sprintf("%v", this.Stdout) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ConsoleResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ConsoleResponse{`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TasksRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TasksRequest{`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TasksResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TasksResponse{`,
+		`Execs:` + strings.Replace(fmt.Sprintf("%v", this.Execs), "TaskStateResponse", "TaskStateResponse", 1) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ShareRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ShareRequest{`,
+		`HostPath:` + fmt.Sprintf("%v", this.HostPath) + `,`,
+		`UvmPath:` + fmt.Sprintf("%v", this.UvmPath) + `,`,
+		`ReadOnly:` + fmt.Sprintf("%v", this.ReadOnly) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ShareResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ShareResponse{`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PprofRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PprofRequest{`,
+		`Profile:` + fmt.Sprintf("%v", this.Profile) + `,`,
+		`DurationSeconds:` + fmt.Sprintf("%v", this.DurationSeconds) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PprofResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PprofResponse{`,
+		`Profile:` + fmt.Sprintf("%v", this.Profile) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ExportRootfsRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ExportRootfsRequest{`,
+		`TaskID:` + fmt.Sprintf("%v", this.TaskID) + `,`,
+		`Stdout:` + fmt.Sprintf("%v", this.Stdout) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ExportRootfsResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ExportRootfsResponse{`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ShimCapabilities) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ShimCapabilities{`,
+		`Version:` + fmt.Sprintf("%v", this.Version) + `,`,
+		`GitCommit:` + fmt.Sprintf("%v", this.GitCommit) + `,`,
+		`OsBuild:` + fmt.Sprintf("%v", this.OsBuild) + `,`,
+		`Task:` + strings.Replace(fmt.Sprintf("%v", this.Task), "TaskCapabilities", "TaskCapabilities", 1) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TaskCapabilities) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TaskCapabilities{`,
+		`Os:` + fmt.Sprintf("%v", this.Os) + `,`,
+		`Isolated:` + fmt.Sprintf("%v", this.Isolated) + `,`,
+		`Signals:` + fmt.Sprintf("%v", this.Signals) + `,`,
+		`Pause:` + fmt.Sprintf("%v", this.Pause) + `,`,
+		`Stats:` + fmt.Sprintf("%v", this.Stats) + `,`,
+		`Update:` + fmt.Sprintf("%v", this.Update) + `,`,
+		`GcsProtocol:` + fmt.Sprintf("%v", this.GcsProtocol) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *EndpointRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&EndpointRequest{`,
+		`TaskID:` + fmt.Sprintf("%v", this.TaskID) + `,`,
+		`EndpointID:` + fmt.Sprintf("%v", this.EndpointID) + `,`,
+		`Remove:` + fmt.Sprintf("%v", this.Remove) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *EndpointResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&EndpointResponse{`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *CPUGroupRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&CPUGroupRequest{`,
+		`TaskID:` + fmt.Sprintf("%v", this.TaskID) + `,`,
+		`CPUGroupID:` + fmt.Sprintf("%v", this.CPUGroupID) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *CPUGroupResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&CPUGroupResponse{`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SaveUVMRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SaveUVMRequest{`,
+		`TaskID:` + fmt.Sprintf("%v", this.TaskID) + `,`,
+		`Path:` + fmt.Sprintf("%v", this.Path) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SaveUVMResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SaveUVMResponse{`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringShimdiag(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+
+type ShimDiagService interface {
+	DiagExecInHost(ctx context.Context, req *ExecProcessRequest) (*ExecProcessResponse, error)
+	DiagStacks(ctx context.Context, req *StacksRequest) (*StacksResponse, error)
+	DiagTaskState(ctx context.Context, req *TaskStateRequest) (*TaskStateResponse, error)
+	DiagReloadConfig(ctx context.Context, req *ReloadConfigRequest) (*ReloadConfigResponse, error)
+	DiagLastCrash(ctx context.Context, req *LastCrashRequest) (*LastCrashResponse, error)
+	DiagConsole(ctx context.Context, req *ConsoleRequest) (*ConsoleResponse, error)
+	DiagTasks(ctx context.Context, req *TasksRequest) (*TasksResponse, error)
+	DiagShare(ctx context.Context, req *ShareRequest) (*ShareResponse, error)
+	DiagPprof(ctx context.Context, req *PprofRequest) (*PprofResponse, error)
+	DiagExportRootfs(ctx context.Context, req *ExportRootfsRequest) (*ExportRootfsResponse, error)
+	DiagEndpoint(ctx context.Context, req *EndpointRequest) (*EndpointResponse, error)
+	DiagCPUGroup(ctx context.Context, req *CPUGroupRequest) (*CPUGroupResponse, error)
+	DiagSaveUVM(ctx context.Context, req *SaveUVMRequest) (*SaveUVMResponse, error)
+}
+
+func RegisterShimDiagService(srv *github_com_containerd_ttrpc.Server, svc ShimDiagService) {
+	srv.Register("containerd.runhcs.v1.diag.ShimDiag", map[string]github_com_containerd_ttrpc.Method{
+		"DiagExecInHost": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req ExecProcessRequest
+			if err := unmarshal(&req); err != nil {
+				return nil, err
+			}
+			return svc.DiagExecInHost(ctx, &req)
+		},
+		"DiagStacks": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req StacksRequest
+			if err := unmarshal(&req); err != nil {
+				return nil, err
+			}
+			return svc.DiagStacks(ctx, &req)
+		},
+		"DiagTaskState": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req TaskStateRequest
+			if err := unmarshal(&req); err != nil {
+				return nil, err
+			}
+			return svc.DiagTaskState(ctx, &req)
+		},
+		"DiagReloadConfig": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req ReloadConfigRequest
+			if err := unmarshal(&req); err != nil {
+				return nil, err
+			}
+			return svc.DiagReloadConfig(ctx, &req)
+		},
+		"DiagLastCrash": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req LastCrashRequest
+			if err := unmarshal(&req); err != nil {
+				return nil, err
+			}
+			return svc.DiagLastCrash(ctx, &req)
+		},
+		"DiagConsole": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req ConsoleRequest
+			if err := unmarshal(&req); err != nil {
+				return nil, err
+			}
+			return svc.DiagConsole(ctx, &req)
+		},
+		"DiagTasks": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req TasksRequest
+			if err := unmarshal(&req); err != nil {
+				return nil, err
+			}
+			return svc.DiagTasks(ctx, &req)
+		},
+		"DiagShare": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req ShareRequest
+			if err := unmarshal(&req); err != nil {
+				return nil, err
+			}
+			return svc.DiagShare(ctx, &req)
+		},
+		"DiagPprof": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req PprofRequest
+			if err := unmarshal(&req); err != nil {
+				return nil, err
+			}
+			return svc.DiagPprof(ctx, &req)
+		},
+		"DiagExportRootfs": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req ExportRootfsRequest
+			if err := unmarshal(&req); err != nil {
+				return nil, err
+			}
+			return svc.DiagExportRootfs(ctx, &req)
+		},
+		"DiagEndpoint": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req EndpointRequest
+			if err := unmarshal(&req); err != nil {
+				return nil, err
+			}
+			return svc.DiagEndpoint(ctx, &req)
+		},
+		"DiagCPUGroup": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req CPUGroupRequest
+			if err := unmarshal(&req); err != nil {
+				return nil, err
+			}
+			return svc.DiagCPUGroup(ctx, &req)
+		},
+		"DiagSaveUVM": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req SaveUVMRequest
+			if err := unmarshal(&req); err != nil {
+				return nil, err
+			}
+			return svc.DiagSaveUVM(ctx, &req)
+		},
+	})
+}
+
+type shimDiagClient struct {
+	client *github_com_containerd_ttrpc.Client
+}
+
+func NewShimDiagClient(client *github_com_containerd_ttrpc.Client) ShimDiagService {
+	return &shimDiagClient{
+		client: client,
+	}
+}
+
+func (c *shimDiagClient) DiagExecInHost(ctx context.Context, req *ExecProcessRequest) (*ExecProcessResponse, error) {
+	var resp ExecProcessResponse
+	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagExecInHost", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *shimDiagClient) DiagStacks(ctx context.Context, req *StacksRequest) (*StacksResponse, error) {
+	var resp StacksResponse
+	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagStacks", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *shimDiagClient) DiagTaskState(ctx context.Context, req *TaskStateRequest) (*TaskStateResponse, error) {
+	var resp TaskStateResponse
+	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagTaskState", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *shimDiagClient) DiagReloadConfig(ctx context.Context, req *ReloadConfigRequest) (*ReloadConfigResponse, error) {
+	var resp ReloadConfigResponse
+	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagReloadConfig", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *shimDiagClient) DiagLastCrash(ctx context.Context, req *LastCrashRequest) (*LastCrashResponse, error) {
+	var resp LastCrashResponse
+	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagLastCrash", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *shimDiagClient) DiagConsole(ctx context.Context, req *ConsoleRequest) (*ConsoleResponse, error) {
+	var resp ConsoleResponse
+	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagConsole", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *shimDiagClient) DiagTasks(ctx context.Context, req *TasksRequest) (*TasksResponse, error) {
+	var resp TasksResponse
+	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagTasks", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *shimDiagClient) DiagShare(ctx context.Context, req *ShareRequest) (*ShareResponse, error) {
+	var resp ShareResponse
+	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagShare", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *shimDiagClient) DiagPprof(ctx context.Context, req *PprofRequest) (*PprofResponse, error) {
+	var resp PprofResponse
+	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagPprof", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *shimDiagClient) DiagExportRootfs(ctx context.Context, req *ExportRootfsRequest) (*ExportRootfsResponse, error) {
+	var resp ExportRootfsResponse
+	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagExportRootfs", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *shimDiagClient) DiagEndpoint(ctx context.Context, req *EndpointRequest) (*EndpointResponse, error) {
+	var resp EndpointResponse
+	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagEndpoint", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *shimDiagClient) DiagCPUGroup(ctx context.Context, req *CPUGroupRequest) (*CPUGroupResponse, error) {
+	var resp CPUGroupResponse
+	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagCPUGroup", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *shimDiagClient) DiagSaveUVM(ctx context.Context, req *SaveUVMRequest) (*SaveUVMResponse, error) {
+	var resp SaveUVMResponse
+	if err := c.client.Call(ctx, "containerd.runhcs.v1.diag.ShimDiag", "DiagSaveUVM", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+func (m *ExecProcessRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExecProcessRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExecProcessRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Args", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Args = append(m.Args, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Workdir", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Workdir = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Terminal", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Terminal = bool(v != 0)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stdin", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Stdin = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stdout", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Stdout = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stderr", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Stderr = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ExecProcessResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExecProcessResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExecProcessResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExitCode", wireType)
+			}
+			m.ExitCode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ExitCode |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StacksRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StacksRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StacksRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StacksResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StacksResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StacksResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stacks", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Stacks = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GuestStacks", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GuestStacks = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TaskStateRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TaskStateRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TaskStateRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TaskID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TaskID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExecID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExecID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TaskStateResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TaskStateResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TaskStateResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TaskID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TaskID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExecID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExecID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pid", wireType)
+			}
+			m.Pid = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Pid |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Status = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExitStatus", wireType)
+			}
+			m.ExitStatus = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ExitStatus |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoryUsageCommitBytes", wireType)
+			}
+			m.MemoryUsageCommitBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MemoryUsageCommitBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoryUsagePrivateWorkingSetBytes", wireType)
+			}
+			m.MemoryUsagePrivateWorkingSetBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MemoryUsagePrivateWorkingSetBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProcessorTotalRuntimeNs", wireType)
+			}
+			m.ProcessorTotalRuntimeNs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ProcessorTotalRuntimeNs |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Devices", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Devices = append(m.Devices, &AttachedDevice{})
+			if err := m.Devices[len(m.Devices)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NetworkNamespace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NetworkNamespace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EndpointIDs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EndpointIDs = append(m.EndpointIDs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HostID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HostID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AttachedDevice) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AttachedDevice: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AttachedDevice: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Type = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Path = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReloadConfigRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReloadConfigRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReloadConfigRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReloadConfigResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReloadConfigResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReloadConfigResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConfigPath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ConfigPath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LastCrashRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LastCrashRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LastCrashRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LastCrashResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LastCrashResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LastCrashResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Path = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Report", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Report = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ConsoleRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ConsoleRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ConsoleRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stdin", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Stdin = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stdout", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Stdout = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ConsoleResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ConsoleResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ConsoleResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TasksRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TasksRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TasksRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TasksResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TasksResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TasksResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Execs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Execs = append(m.Execs, &TaskStateResponse{})
+			if err := m.Execs[len(m.Execs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ShareRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ShareRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ShareRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HostPath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HostPath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UvmPath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.UvmPath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadOnly", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ReadOnly = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ShareResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ShareResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ShareResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PprofRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PprofRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PprofRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Profile", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Profile = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationSeconds", wireType)
+			}
+			m.DurationSeconds = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DurationSeconds |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PprofResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PprofResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PprofResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Profile", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Profile = append(m.Profile[:0], dAtA[iNdEx:postIndex]...)
+			if m.Profile == nil {
+				m.Profile = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ExportRootfsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExportRootfsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExportRootfsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TaskID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TaskID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stdout", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Stdout = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ExportRootfsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExportRootfsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExportRootfsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ShimCapabilities) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ShimCapabilities: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ShimCapabilities: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Version = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GitCommit", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GitCommit = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OsBuild", wireType)
+			}
+			m.OsBuild = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.OsBuild |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Task", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Task == nil {
+				m.Task = &TaskCapabilities{}
+			}
+			if err := m.Task.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TaskCapabilities) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TaskCapabilities: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TaskCapabilities: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Os", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Os = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Isolated", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Isolated = bool(v != 0)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signals", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Signals = bool(v != 0)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pause", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Pause = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stats", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Stats = bool(v != 0)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Update", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Update = bool(v != 0)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GcsProtocol", wireType)
+			}
+			m.GcsProtocol = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.GcsProtocol |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
 }
-func (m *ExecProcessRequest) Unmarshal(dAtA []byte) error {
+func (m *EndpointRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -600,15 +6180,15 @@ func (m *ExecProcessRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ExecProcessRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: EndpointRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ExecProcessRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: EndpointRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Args", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TaskID", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -636,11 +6216,11 @@ func (m *ExecProcessRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Args = append(m.Args, string(dAtA[iNdEx:postIndex]))
+			m.TaskID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Workdir", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field EndpointID", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -668,11 +6248,11 @@ func (m *ExecProcessRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Workdir = string(dAtA[iNdEx:postIndex])
+			m.EndpointID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Terminal", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Remove", wireType)
 			}
 			var v int
 			for shift := uint(0); ; shift += 7 {
@@ -689,42 +6269,118 @@ func (m *ExecProcessRequest) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-			m.Terminal = bool(v != 0)
-		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Stdin", wireType)
+			m.Remove = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowShimdiag
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			if skippy < 0 {
+				return ErrInvalidLengthShimdiag
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if (iNdEx + skippy) < 0 {
 				return ErrInvalidLengthShimdiag
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *EndpointResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: EndpointResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: EndpointResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipShimdiag(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
 				return ErrInvalidLengthShimdiag
 			}
-			if postIndex > l {
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Stdin = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 5:
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CPUGroupRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowShimdiag
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CPUGroupRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CPUGroupRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Stdout", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TaskID", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -752,11 +6408,11 @@ func (m *ExecProcessRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Stdout = string(dAtA[iNdEx:postIndex])
+			m.TaskID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 6:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Stderr", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field CPUGroupID", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -784,7 +6440,7 @@ func (m *ExecProcessRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Stderr = string(dAtA[iNdEx:postIndex])
+			m.CPUGroupID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -811,7 +6467,7 @@ func (m *ExecProcessRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ExecProcessResponse) Unmarshal(dAtA []byte) error {
+func (m *CPUGroupResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -834,31 +6490,12 @@ func (m *ExecProcessResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ExecProcessResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: CPUGroupResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ExecProcessResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CPUGroupResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ExitCode", wireType)
-			}
-			m.ExitCode = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowShimdiag
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.ExitCode |= int32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipShimdiag(dAtA[iNdEx:])
@@ -884,7 +6521,7 @@ func (m *ExecProcessResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *StacksRequest) Unmarshal(dAtA []byte) error {
+func (m *SaveUVMRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -907,12 +6544,76 @@ func (m *StacksRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: StacksRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: SaveUVMRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: StacksRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SaveUVMRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TaskID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TaskID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowShimdiag
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthShimdiag
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Path = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipShimdiag(dAtA[iNdEx:])
@@ -938,7 +6639,7 @@ func (m *StacksRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *StacksResponse) Unmarshal(dAtA []byte) error {
+func (m *SaveUVMResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -961,44 +6662,12 @@ func (m *StacksResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: StacksResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: SaveUVMResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: StacksResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SaveUVMResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Stacks", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowShimdiag
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthShimdiag
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthShimdiag
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Stacks = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipShimdiag(dAtA[iNdEx:])
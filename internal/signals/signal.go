@@ -199,3 +199,27 @@ func ValidateWCOW(signal int, signalsSupported bool) (*guestrequest.SignalProces
 		return &guestrequest.SignalProcessOptionsWCOW{Signal: signalString}, nil
 	}
 }
+
+// IsKill returns `true` if `signal` is SIGKILL. SIGKILL is never delivered as
+// a signal and is always translated to a forced terminate of the process.
+func IsKill(signal int) bool {
+	return signal == sigKill
+}
+
+// IsStop returns `true` if `signal` is sent to stop a process rather than to
+// notify it. These are SIGINT, SIGTERM and SIGKILL for LCOW, and CTRL_C,
+// CTRL_SHUTDOWN, SIGTERM and SIGKILL for WCOW.
+func IsStop(signal int, isWCOW bool) bool {
+	if isWCOW {
+		switch signal {
+		case ctrlC, ctrlShutdown, sigTerm, sigKill:
+			return true
+		}
+		return false
+	}
+	switch signal {
+	case sigInt, sigTerm, sigKill:
+		return true
+	}
+	return false
+}
@@ -310,3 +310,35 @@ func Test_ValidateWCOW_SignalsSupported(t *testing.T) {
 		}
 	}
 }
+
+func Test_IsKill(t *testing.T) {
+	if !IsKill(sigKill) {
+		t.Fatal("SIGKILL should be a kill")
+	}
+	if IsKill(sigTerm) || IsKill(ctrlShutdown) {
+		t.Fatal("SIGTERM and CTRLSHUTDOWN should not be a kill")
+	}
+}
+
+func Test_IsStop(t *testing.T) {
+	for _, signal := range []int{sigInt, sigTerm, sigKill} {
+		if !IsStop(signal, false) {
+			t.Fatalf("LCOW signal %d should be a stop signal", signal)
+		}
+	}
+	for _, signal := range []int{ctrlC, ctrlShutdown, sigTerm, sigKill} {
+		if !IsStop(signal, true) {
+			t.Fatalf("WCOW signal %d should be a stop signal", signal)
+		}
+	}
+	// SIGHUP, SIGUSR1 and SIGABRT on LCOW and CTRL_BREAK on WCOW notify the
+	// process.
+	for _, signal := range []int{0x1, 0xa, 0x6} {
+		if IsStop(signal, false) {
+			t.Fatalf("LCOW signal %d should not be a stop signal", signal)
+		}
+	}
+	if IsStop(ctrlBreak, true) {
+		t.Fatal("CTRLBREAK should not be a stop signal")
+	}
+}
@@ -1,6 +1,7 @@
 package signals
 
 const (
+	sigInt  = 0x2
 	sigKill = 0x9
 	sigTerm = 0xf
 )
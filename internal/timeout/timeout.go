@@ -3,6 +3,7 @@ package timeout
 import (
 	"os"
 	"strconv"
+	"sync/atomic"
 	"time"
 )
 
@@ -15,7 +16,9 @@ var (
 	defaultTimeoutTestdRetry = 5 * time.Second
 )
 
-// External variables for HCSShim consumers to use.
+// External variables for HCSShim consumers to use. A timeout that may be
+// changed while other goroutines are using it MUST be read with Load and
+// written with Store.
 var (
 	// SystemCreate is the timeout for creating a compute system
 	SystemCreate time.Duration = defaultTimeout
@@ -29,6 +32,9 @@ var (
 	// SystemResume is the timeout for resuming a compute system
 	SystemResume time.Duration = defaultTimeout
 
+	// SystemSave is the timeout for saving a compute system
+	SystemSave time.Duration = defaultTimeout
+
 	// SyscallWatcher is the timeout before warning of a potential stuck platform syscall.
 	SyscallWatcher time.Duration = defaultTimeout
 
@@ -51,6 +57,7 @@ func init() {
 	SystemStart = durationFromEnvironment("HCSSHIM_TIMEOUT_SYSTEMSTART", SystemStart)
 	SystemPause = durationFromEnvironment("HCSSHIM_TIMEOUT_SYSTEMPAUSE", SystemPause)
 	SystemResume = durationFromEnvironment("HCSSHIM_TIMEOUT_SYSTEMRESUME", SystemResume)
+	SystemSave = durationFromEnvironment("HCSSHIM_TIMEOUT_SYSTEMSAVE", SystemSave)
 	SyscallWatcher = durationFromEnvironment("HCSSHIM_TIMEOUT_SYSCALLWATCHER", SyscallWatcher)
 	Tar2VHD = durationFromEnvironment("HCSSHIM_TIMEOUT_TAR2VHD", Tar2VHD)
 	ExternalCommandToStart = durationFromEnvironment("HCSSHIM_TIMEOUT_EXTERNALCOMMANDSTART", ExternalCommandToStart)
@@ -58,6 +65,17 @@ func init() {
 	TestDRetryLoop = durationFromEnvironment("HCSSHIM_TIMEOUT_TESTDRETRYLOOP", TestDRetryLoop)
 }
 
+// Load atomically reads the timeout `t`, one of the variables of this package.
+func Load(t *time.Duration) time.Duration {
+	return time.Duration(atomic.LoadInt64((*int64)(t)))
+}
+
+// Store atomically sets the timeout `t`, one of the variables of this
+// package, to `d`.
+func Store(t *time.Duration, d time.Duration) {
+	atomic.StoreInt64((*int64)(t), int64(d))
+}
+
 func durationFromEnvironment(env string, defaultValue time.Duration) time.Duration {
 	envTimeout := os.Getenv(env)
 	if len(envTimeout) > 0 {
@@ -1,6 +1,9 @@
 package uvm
 
-import "github.com/Microsoft/hcsshim/internal/schema1"
+import (
+	"github.com/Microsoft/hcsshim/internal/schema1"
+	"github.com/Microsoft/hcsshim/osversion"
+)
 
 // SignalProcessSupported returns `true` if the guest supports the capability to
 // signal a process.
@@ -10,6 +13,94 @@ func (uvm *UtilityVM) SignalProcessSupported() bool {
 	return uvm.guestCaps.SignalProcessSupported
 }
 
+// DumpStacksSupported returns `true` if the guest supports dumping the stacks
+// of the GCS.
+func (uvm *UtilityVM) DumpStacksSupported() bool {
+	return uvm.gc != nil && uvm.guestCaps.DumpStacksSupported
+}
+
+// EncryptedScratchSupported returns `true` if the guest supports encrypting
+// the SCSI disks mounted into it with dm-crypt.
+func (uvm *UtilityVM) EncryptedScratchSupported() bool {
+	return uvm.guestCaps.EncryptedScratchSupported
+}
+
+// LayerIntegritySupported returns `true` if the guest supports verifying the
+// read-only layers attached to it with dm-verity.
+func (uvm *UtilityVM) LayerIntegritySupported() bool {
+	return uvm.guestCaps.LayerIntegritySupported
+}
+
+// BlockDeviceSupported returns `true` if the guest supports exposing the SCSI
+// disks attached to it as raw block devices.
+func (uvm *UtilityVM) BlockDeviceSupported() bool {
+	return uvm.guestCaps.BlockDeviceSupported
+}
+
+// SCSIMountOptionsSupported returns `true` if the guest honors the filesystem
+// type and mount options of the SCSI disks mounted into it.
+func (uvm *UtilityVM) SCSIMountOptionsSupported() bool {
+	return uvm.guestCaps.SCSIMountOptionsSupported
+}
+
+// ScratchDiscardSupported returns `true` if the guest mounts the scratch disks
+// of the containers in it with `discard` when asked to.
+func (uvm *UtilityVM) ScratchDiscardSupported() bool {
+	return uvm.guestCaps.ScratchDiscardSupported
+}
+
+// MultiSCSIControllerSupported returns `true` if the Linux guest can use
+// the disks attached to any SCSI controller rather than only the first.
+func (uvm *UtilityVM) MultiSCSIControllerSupported() bool {
+	return uvm.guestCaps.MultiSCSIControllerSupported
+}
+
+// Plan9OptionsSupported returns `true` if the guest honors the message size
+// and cache mode of the Plan9 shares mounted into it.
+func (uvm *UtilityVM) Plan9OptionsSupported() bool {
+	return uvm.guestCaps.Plan9OptionsSupported
+}
+
+// CgroupMetricsSupported returns `true` if the guest reports the cgroup
+// metrics of the containers running in it.
+func (uvm *UtilityVM) CgroupMetricsSupported() bool {
+	return uvm.guestCaps.CgroupMetricsSupported
+}
+
+// OOMNotificationsSupported returns `true` if the guest notifies the host when
+// the OOM killer kills a process in a container.
+func (uvm *UtilityVM) OOMNotificationsSupported() bool {
+	return uvm.guestCaps.OOMNotificationsSupported
+}
+
+// VPCIDevicesSupported returns `true` if the guest can expose the devices
+// assigned to it over virtual PCI to the containers running in it.
+func (uvm *UtilityVM) VPCIDevicesSupported() bool {
+	return uvm.guestCaps.VPCIDevicesSupported
+}
+
+// VPMemMultiMappingSupported returns `true` if the guest honors the mapping
+// info of a layer mapped into a VPMem device shared with other layers.
+func (uvm *UtilityVM) VPMemMultiMappingSupported() bool {
+	return uvm.guestCaps.VPMemMultiMappingSupported
+}
+
+// ProcessorHotAddSupported returns `true` if vCPUs can be added to and removed
+// from the running utility VM.
+//
+// This support was added for LCOW on 20H1+ hosts. The guest must also bring
+// the vCPUs online and offline as they are added and removed.
+func (uvm *UtilityVM) ProcessorHotAddSupported() bool {
+	return uvm.operatingSystem == "linux" &&
+		uvm.guestCaps.ProcessorHotAddSupported &&
+		osversion.Get().Build >= osversion.V20H1
+}
+
+// Protocol returns the protocol version negotiated with the GCS.
+func (uvm *UtilityVM) Protocol() uint32 {
+	return uvm.protocol
+}
+
 // Capabilities returns the protocol version and the guest defined capabilities.
 // This should only be used for testing.
 func (uvm *UtilityVM) Capabilities() (uint32, schema1.GuestDefinedCapabilities) {
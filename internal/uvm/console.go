@@ -0,0 +1,281 @@
+package uvm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Microsoft/go-winio"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/sirupsen/logrus"
+)
+
+// consoleDialTimeout is the time waited to connect to the serial console of a
+// started utility VM.
+const consoleDialTimeout = 10 * time.Second
+
+// crashDumpTailSize is the number of bytes of the most recent console output
+// of a Linux utility VM written as its guest crash dump.
+const crashDumpTailSize = 256 * 1024
+
+// consoleClientBufferSize is the number of reads of console output buffered
+// for an attached client. Output is dropped for a client that falls further
+// behind so that it cannot stall the capture.
+const consoleClientBufferSize = 64
+
+// kernelPanicMarker is written to the console by the Linux kernel when it
+// panics.
+var kernelPanicMarker = []byte("Kernel panic")
+
+// consoleCapture owns the connection to the serial console of a utility VM
+// and relays its output to a log file and, while attached, to a client. The
+// console pipe accepts a single connection so clients attach through it.
+type consoleCapture struct {
+	conn     io.ReadWriteCloser
+	file     *os.File
+	dumpPath string
+	done     chan struct{}
+
+	// tail and panicked are only accessed by the relay.
+	tail     []byte
+	panicked bool
+
+	m        sync.Mutex
+	attached chan []byte
+}
+
+// newConsoleCapture starts relaying the output of the console `conn` to the
+// file at `logPath` if not empty. The output is appended if the file exists.
+//
+// If `dumpPath` is not empty and the Linux kernel panics, the most recent
+// output is written to the file at `dumpPath` once the console is closed.
+func newConsoleCapture(conn io.ReadWriteCloser, logPath, dumpPath string) (*consoleCapture, error) {
+	c := &consoleCapture{
+		conn:     conn,
+		dumpPath: dumpPath,
+		done:     make(chan struct{}),
+	}
+	if logPath != "" {
+		f, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, err
+		}
+		c.file = f
+	}
+	go c.relay()
+	return c, nil
+}
+
+// relay copies the console output until the console is closed.
+func (c *consoleCapture) relay() {
+	defer close(c.done)
+	b := make([]byte, 4096)
+	for {
+		n, err := c.conn.Read(b)
+		if n > 0 {
+			if c.file != nil {
+				c.file.Write(b[:n])
+			}
+			if c.dumpPath != "" {
+				c.record(b[:n])
+			}
+			c.m.Lock()
+			if c.attached != nil {
+				select {
+				case c.attached <- append([]byte(nil), b[:n]...):
+				default:
+				}
+			}
+			c.m.Unlock()
+		}
+		if err != nil {
+			if c.panicked {
+				c.writeCrashDump()
+			}
+			return
+		}
+	}
+}
+
+// record appends `b` to the most recent console output kept for the crash
+// dump and notes if it shows a kernel panic.
+func (c *consoleCapture) record(b []byte) {
+	// The marker may span the previous and this read.
+	start := len(c.tail) - len(kernelPanicMarker)
+	if start < 0 {
+		start = 0
+	}
+	c.tail = append(c.tail, b...)
+	if !c.panicked && bytes.Contains(c.tail[start:], kernelPanicMarker) {
+		c.panicked = true
+	}
+	if len(c.tail) > crashDumpTailSize {
+		c.tail = c.tail[len(c.tail)-crashDumpTailSize:]
+	}
+}
+
+// writeCrashDump writes the most recent console output to `c.dumpPath`.
+func (c *consoleCapture) writeCrashDump() {
+	if err := ioutil.WriteFile(c.dumpPath, c.tail, 0644); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"path":          c.dumpPath,
+			logrus.ErrorKey: err,
+		}).Warning("failed to write the guest crash dump")
+	}
+}
+
+// attach relays the console output to `w` in addition to the log file until
+// `detach` is called. Only one client can be attached at a time. The output is
+// written to `w` in the background so a slow client does not stall the
+// capture, and is dropped for `w` if it falls too far behind or fails.
+func (c *consoleCapture) attach(w io.Writer) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.attached != nil {
+		return errors.New("a client is already attached to the serial console")
+	}
+	ch := make(chan []byte, consoleClientBufferSize)
+	c.attached = ch
+	go func() {
+		for b := range ch {
+			if _, err := w.Write(b); err != nil {
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// detach stops relaying the console output to the attached client.
+func (c *consoleCapture) detach() {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.attached != nil {
+		close(c.attached)
+		c.attached = nil
+	}
+}
+
+// Write writes `b` to the console input.
+func (c *consoleCapture) Write(b []byte) (int, error) {
+	return c.conn.Write(b)
+}
+
+// close closes the console connection and the log file once all output has
+// been written to it. If `removeLog` the log file is removed.
+func (c *consoleCapture) close(removeLog bool) error {
+	err := c.conn.Close()
+	<-c.done
+	if c.file != nil {
+		if cerr := c.file.Close(); err == nil {
+			err = cerr
+		}
+		if removeLog {
+			if rerr := os.Remove(c.file.Name()); err == nil {
+				err = rerr
+			}
+		}
+	}
+	return err
+}
+
+// startConsoleCapture connects to the serial console of the started utility
+// VM and captures its output to `uvm.consoleLogPath` and, for LCOW, to the
+// guest crash dump at `uvm.crashDumpPath`.
+func (uvm *UtilityVM) startConsoleCapture() error {
+	timeout := consoleDialTimeout
+	conn, err := winio.DialPipe(uvm.consolePipe, &timeout)
+	if err != nil {
+		return err
+	}
+	dumpPath := ""
+	if uvm.operatingSystem == "linux" {
+		dumpPath = uvm.crashDumpPath
+	}
+	c, err := newConsoleCapture(conn, uvm.consoleLogPath, dumpPath)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	uvm.m.Lock()
+	uvm.console = c
+	uvm.m.Unlock()
+	logrus.WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"path":          uvm.consoleLogPath,
+		"dumpPath":      dumpPath,
+	}).Debug("capturing utility VM serial console")
+	return nil
+}
+
+// getConsole returns the capture of the serial console of the utility VM, or
+// nil if it is not captured.
+func (uvm *UtilityVM) getConsole() *consoleCapture {
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+	return uvm.console
+}
+
+// closeConsole stops capturing the serial console of the utility VM, if it is
+// captured, and removes the log file if `removeLog`. The capture is only
+// forgotten once closed so that `CaptureGuestCrashDump` waits for a crash dump
+// that is still being written.
+func (uvm *UtilityVM) closeConsole(removeLog bool) {
+	c := uvm.getConsole()
+	if c == nil {
+		return
+	}
+	c.close(removeLog)
+	uvm.m.Lock()
+	uvm.console = nil
+	uvm.m.Unlock()
+}
+
+// AttachConsole relays the serial console of the utility VM to `stdout`, and
+// `stdin` to it if not nil, until `stdin` is closed, the console is closed or
+// `ctx` is done. If the console is captured to a file it keeps being captured.
+func (uvm *UtilityVM) AttachConsole(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+	if uvm.consolePipe == "" {
+		return errors.New("the utility VM has no serial console")
+	}
+	done := make(chan struct{}, 2)
+	var con io.Writer
+	if console := uvm.getConsole(); console != nil {
+		if err := console.attach(stdout); err != nil {
+			return err
+		}
+		defer console.detach()
+		con = console
+		go func() {
+			<-console.done
+			done <- struct{}{}
+		}()
+	} else {
+		c, err := winio.DialPipe(uvm.consolePipe, nil)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+		con = c
+		go func() {
+			io.Copy(stdout, c)
+			done <- struct{}{}
+		}()
+	}
+	if stdin != nil {
+		go func() {
+			io.Copy(con, stdin)
+			done <- struct{}{}
+		}()
+	}
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	return nil
+}
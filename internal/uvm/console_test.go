@@ -0,0 +1,192 @@
+package uvm
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer that is safe to write and read concurrently.
+type syncBuffer struct {
+	m sync.Mutex
+	b bytes.Buffer
+}
+
+func (s *syncBuffer) Write(b []byte) (int, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.b.Write(b)
+}
+
+func (s *syncBuffer) String() string {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.b.String()
+}
+
+func waitForOutput(t *testing.T, output func() string, expected string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for output() != expected {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for output %q, got %q", expected, output())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func Test_consoleCapture(t *testing.T) {
+	dir, err := ioutil.TempDir("", "console")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "console.log")
+
+	vm, guest := net.Pipe()
+	c, err := newConsoleCapture(vm, path, "")
+	if err != nil {
+		t.Fatalf("failed to capture console: %s", err)
+	}
+	if _, err := guest.Write([]byte("boot\n")); err != nil {
+		t.Fatal(err)
+	}
+	waitForOutput(t, func() string {
+		b, _ := ioutil.ReadFile(path)
+		return string(b)
+	}, "boot\n")
+
+	var attached syncBuffer
+	if err := c.attach(&attached); err != nil {
+		t.Fatalf("failed to attach: %s", err)
+	}
+	if err := c.attach(&syncBuffer{}); err == nil {
+		t.Fatal("expected a second attach to fail")
+	}
+	if _, err := guest.Write([]byte("panic\n")); err != nil {
+		t.Fatal(err)
+	}
+	waitForOutput(t, attached.String, "panic\n")
+	c.detach()
+
+	// Input from the attached client reaches the console.
+	go c.Write([]byte("sh\n"))
+	b := make([]byte, 3)
+	if _, err := guest.Read(b); err != nil || string(b) != "sh\n" {
+		t.Fatalf("expected console input 'sh\\n', got %q %v", b, err)
+	}
+
+	guest.Close()
+	if err := c.close(false); err != nil {
+		t.Fatalf("failed to close capture: %s", err)
+	}
+	log, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(log) != "boot\npanic\n" {
+		t.Fatalf("expected the whole output to be captured, got %q", log)
+	}
+}
+
+func Test_consoleCapture_CrashDump(t *testing.T) {
+	dir, err := ioutil.TempDir("", "console")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, test := range []struct {
+		name   string
+		output []string
+		dump   string
+	}{
+		{name: "Panic", output: []string{"boot\n", "Kernel pa", "nic - not syncing\n"}, dump: "boot\nKernel panic - not syncing\n"},
+		{name: "NoPanic", output: []string{"boot\n", "shutdown\n"}},
+	} {
+		path := filepath.Join(dir, test.name+".dmp")
+		vm, guest := net.Pipe()
+		c, err := newConsoleCapture(vm, "", path)
+		if err != nil {
+			t.Fatalf("%s: failed to capture console: %s", test.name, err)
+		}
+		for _, o := range test.output {
+			if _, err := guest.Write([]byte(o)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		guest.Close()
+		if err := c.close(false); err != nil {
+			t.Fatalf("%s: failed to close capture: %s", test.name, err)
+		}
+		dump, err := ioutil.ReadFile(path)
+		if test.dump == "" {
+			if !os.IsNotExist(err) {
+				t.Fatalf("%s: expected no crash dump, got %q %v", test.name, dump, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(dump) != test.dump {
+			t.Fatalf("%s: expected crash dump %q, got %q", test.name, test.dump, dump)
+		}
+	}
+}
+
+// blockingWriter blocks every write until `release` is closed.
+type blockingWriter struct {
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(b []byte) (int, error) {
+	<-w.release
+	return len(b), nil
+}
+
+func Test_consoleCapture_SlowClient(t *testing.T) {
+	dir, err := ioutil.TempDir("", "console")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "console.log")
+
+	vm, guest := net.Pipe()
+	c, err := newConsoleCapture(vm, path, "")
+	if err != nil {
+		t.Fatalf("failed to capture console: %s", err)
+	}
+	w := &blockingWriter{release: make(chan struct{})}
+	defer close(w.release)
+	if err := c.attach(w); err != nil {
+		t.Fatalf("failed to attach: %s", err)
+	}
+
+	// The client never keeps up but the output is still captured.
+	var expected bytes.Buffer
+	for i := 0; i < consoleClientBufferSize*2; i++ {
+		if _, err := guest.Write([]byte("x")); err != nil {
+			t.Fatal(err)
+		}
+		expected.WriteString("x")
+	}
+	waitForOutput(t, func() string {
+		b, _ := ioutil.ReadFile(path)
+		return string(b)
+	}, expected.String())
+	c.detach()
+
+	guest.Close()
+	if err := c.close(true); err != nil {
+		t.Fatalf("failed to close capture: %s", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected the console log to be removed: %v", err)
+	}
+}
@@ -14,6 +14,14 @@ const (
 	// DefaultVPMemSizeBytes is the default size of a VPMem device if the create request
 	// doesn't specify.
 	DefaultVPMemSizeBytes = 4 * 1024 * 1024 * 1024 // 4GB
+
+	// MaxSCSIControllerCount is the maximum number of SCSI controllers of a
+	// utility VM.
+	MaxSCSIControllerCount = 4
+
+	// SCSILUNsPerController is the number of disks that may be attached to
+	// each SCSI controller of a utility VM.
+	SCSILUNsPerController = 64
 )
 
 var errNotSupported = fmt.Errorf("not supported")
@@ -0,0 +1,108 @@
+package uvm
+
+import (
+	"errors"
+
+	"github.com/Microsoft/hcsshim/internal/cpugroup"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/Microsoft/hcsshim/internal/requesttype"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/sirupsen/logrus"
+)
+
+const cpuGroupResourcePath = "VirtualMachine/ComputeTopology/Processor/CpuGroup"
+
+// validateCPUGroupOptions verifies that the CPU group settings of `opts` are
+// consistent.
+func validateCPUGroupOptions(opts *Options) error {
+	if opts.CPUGroupID == "" {
+		if len(opts.CPUGroupLogicalProcessors) > 0 || opts.CPUGroupCap != 0 {
+			return errors.New("cpu group logical processors and cap require a cpu group ID")
+		}
+		return nil
+	}
+	if opts.CPUGroupCap != 0 && len(opts.CPUGroupLogicalProcessors) == 0 {
+		return errors.New("cpu group cap can only be set on a cpu group created for the utility VM")
+	}
+	if !cpugroup.IsSupported() {
+		return cpugroup.ErrNotSupported
+	}
+	return nil
+}
+
+// setupCPUGroup creates the CPU group of `opts` if it is created for the
+// utility VM and assigns `processor` of the utility VM to it.
+func (uvm *UtilityVM) setupCPUGroup(opts *Options, processor *hcsschema.Processor2) error {
+	if err := validateCPUGroupOptions(opts); err != nil {
+		return err
+	}
+	if opts.CPUGroupID == "" {
+		return nil
+	}
+	if len(opts.CPUGroupLogicalProcessors) > 0 {
+		if err := cpugroup.Create(opts.CPUGroupID, opts.CPUGroupLogicalProcessors); err != nil {
+			return err
+		}
+		uvm.ownedCPUGroup = opts.CPUGroupID
+		if opts.CPUGroupCap != 0 {
+			if err := cpugroup.SetCap(opts.CPUGroupID, opts.CPUGroupCap); err != nil {
+				return err
+			}
+		}
+	}
+	processor.CpuGroup = &hcsschema.CpuGroup{Id: opts.CPUGroupID}
+	uvm.cpuGroupID = opts.CPUGroupID
+	return nil
+}
+
+// CPUGroupID returns the ID of the CPU group the utility VM is assigned to. If
+// the utility VM is not assigned to a CPU group returns "".
+func (uvm *UtilityVM) CPUGroupID() string {
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+	return uvm.cpuGroupID
+}
+
+// SetCPUGroup assigns the running utility VM to the existing CPU group `id`.
+// Assigning the utility VM to `cpugroup.NullGroupID` removes it from its
+// current CPU group.
+func (uvm *UtilityVM) SetCPUGroup(id string) (err error) {
+	op := "uvm::SetCPUGroup"
+	log := logrus.WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"cpuGroupID":    id,
+	})
+	log.Debug(op + " - Begin Operation")
+	defer func() {
+		if err != nil {
+			log.Data[logrus.ErrorKey] = err
+			log.Error(op + " - End Operation - Error")
+		} else {
+			log.Debug(op + " - End Operation - Success")
+		}
+	}()
+
+	if id == "" {
+		return errors.New("cpu group ID must be set")
+	}
+	if !cpugroup.IsSupported() {
+		return cpugroup.ErrNotSupported
+	}
+
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+
+	modification := &hcsschema.ModifySettingRequest{
+		RequestType:  requesttype.Update,
+		Settings:     &hcsschema.CpuGroup{Id: id},
+		ResourcePath: cpuGroupResourcePath,
+	}
+	if err := uvm.Modify(modification); err != nil {
+		return err
+	}
+	if id == cpugroup.NullGroupID {
+		id = ""
+	}
+	uvm.cpuGroupID = id
+	return nil
+}
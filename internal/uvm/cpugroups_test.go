@@ -0,0 +1,20 @@
+package uvm
+
+import (
+	"testing"
+)
+
+func Test_validateCPUGroupOptions(t *testing.T) {
+	if err := validateCPUGroupOptions(&Options{}); err != nil {
+		t.Fatalf("expected nil error without a cpu group, got: %v", err)
+	}
+	for _, opts := range []*Options{
+		{CPUGroupLogicalProcessors: []uint32{0, 1}},
+		{CPUGroupCap: 100},
+		{CPUGroupID: "4e7b7c7a-0a3c-4d5e-9f4a-1b2c3d4e5f60", CPUGroupCap: 100},
+	} {
+		if err := validateCPUGroupOptions(opts); err == nil {
+			t.Fatalf("expected error for options: %+v", opts)
+		}
+	}
+}
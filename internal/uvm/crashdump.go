@@ -0,0 +1,77 @@
+package uvm
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrNoGuestCrashDump is returned by `CaptureGuestCrashDump` if the guest has
+// not written a crash dump.
+var ErrNoGuestCrashDump = errors.New("the utility VM guest has not written a crash dump")
+
+// removeStaleCrashDump removes the guest crash dump at `path` left by a
+// previous utility VM so that it is not mistaken for a dump of this one.
+func removeStaleCrashDump(path string) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// CaptureGuestCrashDump copies the crash dump written by the guest when it
+// crashed to the file `destPath`. It returns `ErrNoGuestCrashDump` if the
+// guest has not crashed. The utility VM MUST have been created with
+// `GuestCrashDumpPath` set.
+func (uvm *UtilityVM) CaptureGuestCrashDump(destPath string) (err error) {
+	op := "uvm::CaptureGuestCrashDump"
+	log := logrus.WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"path":          destPath,
+	})
+	log.Debug(op + " - Begin Operation")
+	defer func() {
+		if err != nil {
+			log.Data[logrus.ErrorKey] = err
+			log.Error(op + " - End Operation - Error")
+		} else {
+			log.Debug(op + " - End Operation - Success")
+		}
+	}()
+
+	if uvm.crashDumpPath == "" {
+		return errors.New("the utility VM was not created to write guest crash dumps")
+	}
+	if console := uvm.getConsole(); console != nil && uvm.exitCh != nil {
+		// A Linux crash dump is written once the console of the exited
+		// utility VM has been drained.
+		select {
+		case <-uvm.exitCh:
+			<-console.done
+		default:
+		}
+	}
+	src, err := os.Open(uvm.crashDumpPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrNoGuestCrashDump
+		}
+		return err
+	}
+	defer src.Close()
+	dst, err := os.Create(destPath)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
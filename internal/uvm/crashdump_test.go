@@ -0,0 +1,91 @@
+package uvm
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_CaptureGuestCrashDump(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crashdump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	vm := &UtilityVM{operatingSystem: "windows", crashDumpPath: filepath.Join(dir, "guest.dmp")}
+	dest := filepath.Join(dir, "captured.dmp")
+
+	if err := vm.CaptureGuestCrashDump(dest); err != ErrNoGuestCrashDump {
+		t.Fatalf("expected ErrNoGuestCrashDump, got: %v", err)
+	}
+	if err := ioutil.WriteFile(vm.crashDumpPath, []byte("dump"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.CaptureGuestCrashDump(dest); err != nil {
+		t.Fatalf("failed to capture crash dump: %s", err)
+	}
+	if b, err := ioutil.ReadFile(dest); err != nil || string(b) != "dump" {
+		t.Fatalf("expected the crash dump to be copied, got %q %v", b, err)
+	}
+
+	if err := (&UtilityVM{operatingSystem: "windows"}).CaptureGuestCrashDump(dest); err == nil {
+		t.Fatal("expected error without a guest crash dump path")
+	}
+}
+
+func Test_CaptureGuestCrashDump_LCOW_ConcurrentClose(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crashdump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	vm := &UtilityVM{operatingSystem: "linux", crashDumpPath: filepath.Join(dir, "guest.log")}
+	conn, guest := net.Pipe()
+	c, err := newConsoleCapture(conn, "", vm.crashDumpPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.console = c
+	vm.exitCh = make(chan struct{})
+	close(vm.exitCh)
+
+	if _, err := guest.Write([]byte("Kernel panic\n")); err != nil {
+		t.Fatal(err)
+	}
+	guest.Close()
+	closed := make(chan struct{})
+	go func() {
+		vm.closeConsole(false)
+		close(closed)
+	}()
+	dest := filepath.Join(dir, "captured.log")
+	if err := vm.CaptureGuestCrashDump(dest); err != nil {
+		t.Fatalf("failed to capture crash dump: %s", err)
+	}
+	<-closed
+	if b, err := ioutil.ReadFile(dest); err != nil || string(b) != "Kernel panic\n" {
+		t.Fatalf("expected the console crash dump to be copied, got %q %v", b, err)
+	}
+}
+
+func Test_removeStaleCrashDump(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crashdump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "guest.dmp")
+	if err := ioutil.WriteFile(path, []byte("stale"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := removeStaleCrashDump(path); err != nil {
+			t.Fatalf("failed to remove stale crash dump: %s", err)
+		}
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected the stale crash dump to be removed, got: %v", err)
+	}
+}
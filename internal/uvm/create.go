@@ -8,8 +8,10 @@ import (
 	"runtime"
 
 	"github.com/Microsoft/hcsshim/internal/cow"
-	"github.com/Microsoft/hcsshim/internal/hcs"
+	"github.com/Microsoft/hcsshim/internal/cpugroup"
 	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/Microsoft/hcsshim/internal/reservation"
+	"github.com/Microsoft/hcsshim/internal/schema1"
 	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
 	"github.com/Microsoft/hcsshim/internal/schemaversion"
 	"github.com/sirupsen/logrus"
@@ -30,9 +32,18 @@ type Options struct {
 	AllowOvercommit bool
 
 	// Memory for UVM. Defaults to false. For virtual memory with deferred
-	// commit, set to true.
+	// commit, set to true. Requires `AllowOvercommit`.
 	EnableDeferredCommit bool
 
+	// EnableHotHint lets the guest hint to the host which of its memory is in
+	// use so that it is backed first. Defaults to true for WCOW. Ignored for
+	// physically backed memory.
+	EnableHotHint bool
+
+	// EnableColdHint lets the guest hint to the host which of its memory is
+	// free so that it can be trimmed. Ignored for physically backed memory.
+	EnableColdHint bool
+
 	// ProcessorCount sets the number of vCPU's. If `0` will default to platform
 	// default.
 	ProcessorCount int32
@@ -56,6 +67,81 @@ type Options struct {
 	// ExternalGuestConnection sets whether the guest RPC connection is performed
 	// internally by the OS platform or externally by this package.
 	ExternalGuestConnection bool
+
+	// EnableVirtualTPM attaches a virtual TPM to the UVM. The TPM state is
+	// transient and is lost when the UVM is shut down.
+	EnableVirtualTPM bool
+
+	// SCSIControllerCount is the number of SCSI controllers of the UVM, each
+	// of which can attach `SCSILUNsPerController` disks. Defaults to 1. LCOW
+	// UVMs may have none. WCOW UVMs MUST have at least one for the scratch and
+	// only disks on the first controller can be mapped into the guest.
+	SCSIControllerCount uint32
+
+	// CPUGroupID assigns the UVM to the host CPU group with this ID. If empty
+	// the UVM is not assigned to a CPU group.
+	CPUGroupID string
+
+	// CPUGroupLogicalProcessors, if set, creates the CPU group `CPUGroupID`
+	// with these host logical processors before creating the UVM. The group is
+	// deleted when the UVM is closed.
+	CPUGroupLogicalProcessors []uint32
+
+	// CPUGroupCap caps the processor time of the CPU group created via
+	// `CPUGroupLogicalProcessors` to this many of `cpugroup.MaxCap`. If `0`
+	// the group is not capped.
+	CPUGroupCap uint32
+
+	// NumaNodeCount, if not `0`, exposes this many virtual NUMA nodes to the
+	// UVM rather than a flat topology. The processors and memory of the UVM
+	// are split across the nodes, so there can be no more nodes than vCPU's.
+	// Virtual NUMA requires physically backed memory so `AllowOvercommit`
+	// MUST be false, and is only supported on Windows Server 2022 and newer
+	// hosts.
+	NumaNodeCount uint32
+
+	// NumaMemorySizePerNodeInMB, if not `0`, is the maximum memory of each
+	// virtual NUMA node. The nodes MUST be able to hold all of the UVM memory.
+	// If `0` the platform splits the memory evenly. Requires `NumaNodeCount`.
+	NumaMemorySizePerNodeInMB uint64
+
+	// ConsoleLogPath, if set, captures the output of the serial console of the
+	// UVM to this host file from when the UVM is started until it is closed.
+	// The console pipe of the UVM MUST be set. A captured LCOW console is not
+	// used for debugging: no shell is started on it and the UVM is still
+	// terminated on a kernel panic. The file is removed when the UVM is closed
+	// while running and kept if it failed to start or exited on its own.
+	ConsoleLogPath string
+
+	// GuestCrashDumpPath, if set, writes a dump of the guest kernel to this
+	// host file if the guest crashes, replacing any existing file. For WCOW
+	// the platform writes a memory dump of the guest. For LCOW the kernel
+	// output leading up to the panic is written from the serial console, whose
+	// console pipe MUST be set and which is then captured as if
+	// `ConsoleLogPath` was set.
+	GuestCrashDumpPath string
+
+	// LayerAttachmentPolicy controls how the read-only container layers are
+	// attached to the UVM. Defaults to VPMem for LCOW. WCOW layers are always
+	// shared over VSMB and only the default is supported.
+	LayerAttachmentPolicy LayerAttachmentPolicy
+
+	// HvSocketServiceTable, if set, registers these HvSocket services keyed by
+	// their service GUID with the UVM when it is created, so that only the host
+	// processes allowed by their security descriptors can bind or connect to
+	// them. The registrations last for the life of the UVM.
+	HvSocketServiceTable map[string]hcsschema.HvSocketServiceConfig
+
+	// ReservationLimits, if set, records the memory and processors of the UVM
+	// in the host-wide reservations shared by all shims before creating it. If
+	// the reservations would exceed these limits the create fails with a
+	// `*reservation.ExhaustedError`.
+	ReservationLimits *reservation.Limits `json:"-"`
+
+	// Backend is the virtualization backend the utility VM and any compute
+	// systems hosted in it are created on. If `nil` defaults to the local
+	// vmcompute service.
+	Backend cow.Backend `json:"-"`
 }
 
 // newDefaultOptions returns the default base options for WCOW and LCOW.
@@ -71,6 +157,7 @@ func newDefaultOptions(id, owner string) *Options {
 		AllowOvercommit:      true,
 		EnableDeferredCommit: false,
 		ProcessorCount:       defaultProcessorCount(),
+		SCSIControllerCount:  1,
 	}
 
 	if opts.Owner == "" {
@@ -85,14 +172,76 @@ func (uvm *UtilityVM) ID() string {
 	return uvm.hcsSystem.ID()
 }
 
+// VirtualTPMEnabled returns `true` if a virtual TPM is attached to the utility
+// VM.
+func (uvm *UtilityVM) VirtualTPMEnabled() bool {
+	return uvm.virtualTPM
+}
+
+// ConsolePipe returns the named pipe of the serial console of the utility VM.
+// If the utility VM has no serial console returns "".
+func (uvm *UtilityVM) ConsolePipe() string {
+	return uvm.consolePipe
+}
+
+// enableVirtualTPM attaches a virtual TPM to `vm`. The empty guest state
+// initializes the TPM with transient in-memory state.
+func enableVirtualTPM(vm *hcsschema.VirtualMachine) {
+	vm.SecuritySettings = &hcsschema.SecuritySettings{EnableTpm: true}
+	vm.GuestState = &hcsschema.GuestState{}
+}
+
 // OS returns the operating system of the utility VM.
 func (uvm *UtilityVM) OS() string {
 	return uvm.operatingSystem
 }
 
+// reserve records the host-wide reservation of the memory and processors of
+// the utility VM if `limits` is set.
+func (uvm *UtilityVM) reserve(limits *reservation.Limits, memorySizeInMB int32) error {
+	if limits == nil {
+		return nil
+	}
+	r, err := reservation.Reserve("", reservation.Request{
+		ID:             uvm.id,
+		MemoryInMB:     uint64(memorySizeInMB),
+		ProcessorCount: uint32(uvm.processorCount),
+	}, *limits)
+	if err != nil {
+		return err
+	}
+	uvm.reservation = r
+	uvm.reserveLimits = *limits
+	return nil
+}
+
+// updateReservation replaces the host-wide reservation of the utility VM, if
+// any, with one of `memorySizeInMB` and `processorCount`. If `check` the new
+// reservation must be within the limits the utility VM was reserved with. It
+// is the callers responsibility to hold `uvm.m`.
+func (uvm *UtilityVM) updateReservation(memorySizeInMB, processorCount int32, check bool) error {
+	if uvm.reservation == nil {
+		return nil
+	}
+	var limits reservation.Limits
+	if check {
+		limits = uvm.reserveLimits
+	}
+	r, err := reservation.Reserve("", reservation.Request{
+		ID:             uvm.id,
+		MemoryInMB:     uint64(memorySizeInMB),
+		ProcessorCount: uint32(processorCount),
+	}, limits)
+	if err != nil {
+		return err
+	}
+	uvm.reservation = r
+	return nil
+}
+
 func (uvm *UtilityVM) create(doc interface{}) error {
 	uvm.exitCh = make(chan struct{})
-	system, err := hcs.CreateComputeSystem(uvm.id, doc)
+	system, err := uvm.backend.CreateComputeSystem(context.Background(), uvm.id, doc)
 	if err != nil {
 		return err
 	}
@@ -119,6 +268,61 @@ func (uvm *UtilityVM) create(doc interface{}) error {
 	return nil
 }
 
+// open opens the existing compute system of the utility VM.
+func (uvm *UtilityVM) open() error {
+	system, err := uvm.backend.OpenComputeSystem(uvm.id)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if system != nil {
+			system.Close()
+		}
+	}()
+
+	// Cache the VM ID of the utility VM.
+	properties, err := system.Properties()
+	if err != nil {
+		return err
+	}
+	uvm.runtimeID = properties.RuntimeID
+	uvm.hcsSystem = system
+	system = nil
+
+	logrus.WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"runtime-id":    uvm.runtimeID,
+	}).Debug("opened utility VM")
+	return nil
+}
+
+// Detach releases the handle to the utility VM and the connections this
+// process holds to it without terminating it, so that it keeps running for a
+// process that has opened it via `OpenLCOW`. Resources owned by this process
+// on behalf of the utility VM, such as its CPU group or reservation, are not
+// released.
+func (uvm *UtilityVM) Detach() error {
+	if uvm.gc != nil {
+		uvm.gc.Close()
+	}
+	uvm.closeConsole(false)
+	if uvm.gcListener != nil {
+		uvm.gcListener.Close()
+	}
+	if uvm.outputListener != nil {
+		close(uvm.outputProcessingDone)
+		uvm.outputListener.Close()
+		uvm.outputListener = nil
+	}
+	if uvm.outputProcessingCancel != nil {
+		uvm.outputProcessingCancel()
+	}
+	if uvm.hcsSystem != nil {
+		return uvm.hcsSystem.Close()
+	}
+	return nil
+}
+
 // Close terminates and releases resources associated with the utility VM.
 func (uvm *UtilityVM) Close() (err error) {
 	op := "uvm::Close"
@@ -135,6 +339,16 @@ func (uvm *UtilityVM) Close() (err error) {
 		}
 	}()
 
+	// The console log is only kept to diagnose a utility VM that failed to
+	// start or exited on its own, not one that is closed while running.
+	running := false
+	if uvm.exitCh != nil {
+		select {
+		case <-uvm.exitCh:
+		default:
+			running = true
+		}
+	}
 	if uvm.hcsSystem != nil {
 		uvm.hcsSystem.Terminate()
 		uvm.Wait()
@@ -142,6 +356,7 @@ func (uvm *UtilityVM) Close() (err error) {
 	if uvm.gc != nil {
 		uvm.gc.Close()
 	}
+	uvm.closeConsole(running)
 	if uvm.gcListener != nil {
 		uvm.gcListener.Close()
 	}
@@ -154,16 +369,35 @@ func (uvm *UtilityVM) Close() (err error) {
 		uvm.outputListener.Close()
 		uvm.outputListener = nil
 	}
+	if uvm.isTemplate {
+		if err := removeTemplateConfig(uvm.id); err != nil {
+			log.WithError(err).Warning("failed to remove utility VM template config")
+		}
+		uvm.isTemplate = false
+	}
+	if uvm.ownedCPUGroup != "" {
+		if err := cpugroup.Delete(uvm.ownedCPUGroup); err != nil {
+			log.WithError(err).Warning("failed to delete utility VM cpu group")
+		}
+		uvm.ownedCPUGroup = ""
+	}
+	if uvm.reservation != nil {
+		if err := uvm.reservation.Release(); err != nil {
+			log.WithError(err).Warning("failed to release utility VM reservation")
+		}
+		uvm.reservation = nil
+	}
 	if uvm.hcsSystem != nil {
 		return uvm.hcsSystem.Close()
 	}
 	return nil
 }
 
-// CreateContainer creates a container in the utility VM.
-func (uvm *UtilityVM) CreateContainer(id string, settings interface{}) (cow.Container, error) {
+// CreateContainer creates a container in the utility VM. If `ctx` is done
+// before the create completes the container is not created.
+func (uvm *UtilityVM) CreateContainer(ctx context.Context, id string, settings interface{}) (cow.Container, error) {
 	if uvm.gc != nil {
-		c, err := uvm.gc.CreateContainer(context.TODO(), id, settings)
+		c, err := uvm.gc.CreateContainer(ctx, id, settings)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create container %s: %s", id, err)
 		}
@@ -176,7 +410,7 @@ func (uvm *UtilityVM) CreateContainer(id string, settings interface{}) (cow.Cont
 		ShouldTerminateOnLastHandleClosed: true,
 		HostedSystem:                      settings,
 	}
-	c, err := hcs.CreateComputeSystem(id, &doc)
+	c, err := uvm.backend.CreateComputeSystem(ctx, id, &doc)
 	if err != nil {
 		return nil, err
 	}
@@ -207,6 +441,26 @@ func (uvm *UtilityVM) ExitError() error {
 	return uvm.hcsSystem.ExitError()
 }
 
+// Statistics returns the runtime statistics of the utility VM as seen from the
+// host.
+func (uvm *UtilityVM) Statistics() (*schema1.Statistics, error) {
+	props, err := uvm.hcsSystem.Properties(schema1.PropertyTypeStatistics)
+	if err != nil {
+		return nil, err
+	}
+	return &props.Statistics, nil
+}
+
+// Pause suspends the utility VM and every container running in it.
+func (uvm *UtilityVM) Pause() error {
+	return uvm.hcsSystem.Pause()
+}
+
+// Resume resumes a utility VM suspended by Pause.
+func (uvm *UtilityVM) Resume() error {
+	return uvm.hcsSystem.Resume()
+}
+
 func defaultProcessorCount() int32 {
 	if runtime.NumCPU() == 1 {
 		return 1
@@ -232,9 +486,42 @@ func (uvm *UtilityVM) normalizeProcessorCount(requested int32) {
 
 // ProcessorCount returns the number of processors actually assigned to the UVM.
 func (uvm *UtilityVM) ProcessorCount() int32 {
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
 	return uvm.processorCount
 }
 
+// ScratchEncryptionEnabled returns `true` if the scratch disks of the
+// containers in the UVM are encrypted.
+func (uvm *UtilityVM) ScratchEncryptionEnabled() bool {
+	return uvm.encryptScratch
+}
+
+// ScratchDiscardEnabled returns `true` if the scratch disks of the containers
+// in the UVM are mounted with discard.
+func (uvm *UtilityVM) ScratchDiscardEnabled() bool {
+	return uvm.discardScratch
+}
+
+// ScratchFilesystem returns the filesystem the scratch disks of the containers
+// in the UVM are formatted with, or "" for the guest default.
+func (uvm *UtilityVM) ScratchFilesystem() string {
+	return uvm.scratchFs
+}
+
+// LayerIntegrityEnabled returns `true` if the read-only layers attached to the
+// UVM are verified with dm-verity.
+func (uvm *UtilityVM) LayerIntegrityEnabled() bool {
+	return uvm.layerIntegrity
+}
+
+// MemorySizeInMB returns the memory actually assigned to the UVM.
+func (uvm *UtilityVM) MemorySizeInMB() int32 {
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+	return uvm.memorySizeInMB
+}
+
 func (uvm *UtilityVM) normalizeMemorySize(requested int32) int32 {
 	actual := (requested + 1) &^ 1 // align up to an even number
 	if requested != actual {
@@ -6,12 +6,15 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/Microsoft/hcsshim/internal/gcs"
 
 	"github.com/Microsoft/go-winio"
 	"github.com/Microsoft/go-winio/pkg/guid"
+	"github.com/Microsoft/hcsshim/internal/backend"
 	"github.com/Microsoft/hcsshim/internal/logfields"
 	"github.com/Microsoft/hcsshim/internal/mergemaps"
 	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
@@ -44,6 +47,12 @@ const (
 	UncompressedKernelFile = "vmlinux"
 )
 
+// ConsolePipePath returns the default named pipe path of the serial console of
+// the utility VM `id`.
+func ConsolePipePath(id string) string {
+	return `\\.\pipe\` + id + `-console`
+}
+
 // OptionsLCOW are the set of options passed to CreateLCOW() to create a utility vm.
 type OptionsLCOW struct {
 	*Options
@@ -55,7 +64,6 @@ type OptionsLCOW struct {
 	KernelBootOptions     string              // Additional boot options for the kernel
 	EnableGraphicsConsole bool                // If true, enable a graphics console for the utility VM
 	ConsolePipe           string              // The named pipe path to use for the serial console.  eg \\.\pipe\vmpipe
-	SCSIControllerCount   uint32              // The number of SCSI controllers. Defaults to 1. Currently we only support 0 or 1.
 	UseGuestConnection    bool                // Whether the HCS should connect to the UVM's GCS. Defaults to true
 	ExecCommandLine       string              // The command line to exec from init. Defaults to GCS
 	ForwardStdout         bool                // Whether stdout will be forwarded from the executed program. Defaults to false
@@ -63,7 +71,36 @@ type OptionsLCOW struct {
 	OutputHandler         OutputHandler       `json:"-"` // Controls how output received over HVSocket from the UVM is handled. Defaults to parsing output as logrus messages
 	VPMemDeviceCount      uint32              // Number of VPMem devices. Defaults to `DefaultVPMEMCount`. Limit at 128. If booting UVM from VHD, device 0 is taken.
 	VPMemSizeBytes        uint64              // Size of the VPMem devices. Defaults to `DefaultVPMemSizeBytes`.
+	VPMemMultiMapping     bool                // If true, multiple read-only layers are mapped into each VPMem device at different offsets. Defaults to false.
 	PreferredRootFSType   PreferredRootFSType // If `KernelFile` is `InitrdFile` use `PreferredRootFSTypeInitRd`. If `KernelFile` is `VhdFile` use `PreferredRootFSTypeVHD`
+
+	// EnableScratchEncryption encrypts the scratch disks of the containers in
+	// the utility VM with dm-crypt using a key generated by the guest for each
+	// boot, so that their writable data is encrypted at rest. The scratch
+	// disks are formatted by the guest when they are mounted so their
+	// contents do not persist. The guest MUST support encrypted scratch disks.
+	EnableScratchEncryption bool
+
+	// EnableLayerIntegrity verifies the read-only layers attached to the
+	// utility VM with dm-verity in the guest so that a layer tampered with on
+	// the host fails to read. Each layer MUST have a dm-verity hash device
+	// appended by tar2ext4, its root digest MUST be set with
+	// `SetLayerRootDigest` before it is attached and the guest MUST support
+	// it.
+	EnableLayerIntegrity bool
+
+	// EnableScratchDiscard mounts the scratch disks of the containers in the
+	// utility VM with `discard` so that the guest unmaps the blocks of deleted
+	// files and the dynamic VHDX of the scratch disk shrinks on the host. The
+	// guest MUST support it and it cannot be used with
+	// `EnableScratchEncryption` as dm-crypt does not pass discards through.
+	EnableScratchDiscard bool
+
+	// ScratchFilesystem is the filesystem the scratch disks of the containers
+	// in the utility VM are formatted with, such as `xfs`, so that the guest
+	// mounts them as such. If empty the guest default, `ext4`, is used. The
+	// guest MUST support SCSI mount options if set.
+	ScratchFilesystem string
 }
 
 // defaultLCOWOSBootFilesPath returns the default path used to locate the LCOW
@@ -97,7 +134,6 @@ func NewDefaultOptionsLCOW(id, owner string) *OptionsLCOW {
 		KernelBootOptions:     "",
 		EnableGraphicsConsole: false,
 		ConsolePipe:           "",
-		SCSIControllerCount:   1,
 		UseGuestConnection:    true,
 		ExecCommandLine:       fmt.Sprintf("/bin/gcs -v4 -log-format json -loglevel %s", logrus.StandardLogger().Level.String()),
 		ForwardStdout:         false,
@@ -107,14 +143,29 @@ func NewDefaultOptionsLCOW(id, owner string) *OptionsLCOW {
 		VPMemSizeBytes:        DefaultVPMemSizeBytes,
 		PreferredRootFSType:   PreferredRootFSTypeInitRd,
 	}
+	opts.UpdateBootFilesPath(opts.BootFilesPath)
+	return opts
+}
 
+// UpdateBootFilesPath sets `BootFilesPath` to `path` and selects the default
+// boot files found in it for the boot method in `KernelDirect`. If `path` has a
+// subdirectory named after the host architecture, such as `arm64`, the boot
+// files are taken from it instead. The root file system is `VhdFile` if
+// present, otherwise `InitrdFile`. The kernel is `KernelFile` unless booting
+// with `KernelDirect` and `UncompressedKernelFile` is present.
+func (opts *OptionsLCOW) UpdateBootFilesPath(path string) {
+	opts.BootFilesPath = archBootFilesPath(path, runtime.GOARCH)
 	if _, err := os.Stat(filepath.Join(opts.BootFilesPath, VhdFile)); err == nil {
 		// We have a rootfs.vhd in the boot files path. Use it over an initrd.img
 		opts.RootFSFile = VhdFile
 		opts.PreferredRootFSType = PreferredRootFSTypeVHD
+	} else {
+		opts.RootFSFile = InitrdFile
+		opts.PreferredRootFSType = PreferredRootFSTypeInitRd
 	}
 
-	if kernelDirectSupported {
+	opts.KernelFile = KernelFile
+	if opts.KernelDirect {
 		// KernelDirect supports uncompressed kernel if the kernel is present.
 		// Default to uncompressed if on box. NOTE: If `kernel` is already
 		// uncompressed and simply named 'kernel' it will still be used
@@ -123,7 +174,35 @@ func NewDefaultOptionsLCOW(id, owner string) *OptionsLCOW {
 			opts.KernelFile = UncompressedKernelFile
 		}
 	}
-	return opts
+}
+
+// newLCOWUtilityVM returns the in-memory state of the LCOW utility VM
+// described by `opts` before its compute system is created or opened.
+func newLCOWUtilityVM(opts *OptionsLCOW) *UtilityVM {
+	uvm := &UtilityVM{
+		id:                  opts.ID,
+		owner:               opts.Owner,
+		operatingSystem:     "linux",
+		backend:             backend.OrDefault(opts.Backend),
+		scsiControllerCount: opts.SCSIControllerCount,
+		vpmemMaxCount:       opts.VPMemDeviceCount,
+		vpmemMaxSizeBytes:   opts.VPMemSizeBytes,
+		vpmemMultiMapping:   opts.VPMemMultiMapping,
+		virtualTPM:          opts.EnableVirtualTPM,
+		consolePipe:         opts.ConsolePipe,
+		consoleLogPath:      opts.ConsoleLogPath,
+		crashDumpPath:       opts.GuestCrashDumpPath,
+		encryptScratch:      opts.EnableScratchEncryption,
+		layerIntegrity:      opts.EnableLayerIntegrity,
+		discardScratch:      opts.EnableScratchDiscard,
+		scratchFs:           opts.ScratchFilesystem,
+		layerPolicy:         opts.LayerAttachmentPolicy,
+	}
+
+	// To maintain compatability with Docker we need to automatically downgrade
+	// a user CPU count if the setting is not possible.
+	uvm.normalizeProcessorCount(opts.ProcessorCount)
+	return uvm
 }
 
 // CreateLCOW creates an HCS compute system representing a utility VM.
@@ -155,26 +234,16 @@ func CreateLCOW(opts *OptionsLCOW) (_ *UtilityVM, err error) {
 		opts.OutputHandler = parseLogrus(opts.ID)
 	}
 
-	uvm := &UtilityVM{
-		id:                  opts.ID,
-		owner:               opts.Owner,
-		operatingSystem:     "linux",
-		scsiControllerCount: opts.SCSIControllerCount,
-		vpmemMaxCount:       opts.VPMemDeviceCount,
-		vpmemMaxSizeBytes:   opts.VPMemSizeBytes,
-	}
+	uvm := newLCOWUtilityVM(opts)
 	defer func() {
 		if err != nil {
 			uvm.Close()
 		}
 	}()
 
-	// To maintain compatability with Docker we need to automatically downgrade
-	// a user CPU count if the setting is not possible.
-	uvm.normalizeProcessorCount(opts.ProcessorCount)
-
 	// Align the requested memory size.
 	memorySizeInMB := uvm.normalizeMemorySize(opts.MemorySizeInMB)
+	uvm.memorySizeInMB = memorySizeInMB
 
 	kernelFullPath := filepath.Join(opts.BootFilesPath, opts.KernelFile)
 	if _, err := os.Stat(kernelFullPath); os.IsNotExist(err) {
@@ -185,8 +254,23 @@ func CreateLCOW(opts *OptionsLCOW) (_ *UtilityVM, err error) {
 		return nil, fmt.Errorf("boot file: '%s' not found", rootfsFullPath)
 	}
 
-	if opts.SCSIControllerCount > 1 {
-		return nil, fmt.Errorf("SCSI controller count must be 0 or 1") // Future extension here for up to 4
+	if opts.ConsoleLogPath != "" && opts.ConsolePipe == "" {
+		return nil, fmt.Errorf("capturing the serial console requires a console pipe")
+	}
+	if opts.GuestCrashDumpPath != "" && opts.ConsolePipe == "" {
+		return nil, fmt.Errorf("writing the guest crash dump requires a console pipe")
+	}
+	if opts.EnableScratchDiscard && opts.EnableScratchEncryption {
+		return nil, fmt.Errorf("scratch discard cannot be enabled for encrypted scratch disks")
+	}
+	if err := removeStaleCrashDump(opts.GuestCrashDumpPath); err != nil {
+		return nil, fmt.Errorf("failed to remove stale guest crash dump: %s", err)
+	}
+	if opts.SCSIControllerCount > MaxSCSIControllerCount {
+		return nil, fmt.Errorf("SCSI controller count cannot be greater than %d", MaxSCSIControllerCount)
+	}
+	if err := validateLayerAttachmentPolicy(uvm.operatingSystem, opts.LayerAttachmentPolicy); err != nil {
+		return nil, err
 	}
 	if opts.VPMemDeviceCount > MaxVPMEMCount {
 		return nil, fmt.Errorf("vpmem device count cannot be greater than %d", MaxVPMEMCount)
@@ -203,6 +287,13 @@ func CreateLCOW(opts *OptionsLCOW) (_ *UtilityVM, err error) {
 	if opts.KernelDirect && osversion.Get().Build < 18286 {
 		return nil, fmt.Errorf("KernelDirectBoot is not support on builds older than 18286")
 	}
+	if !opts.KernelDirect && opts.KernelFile == UncompressedKernelFile {
+		return nil, fmt.Errorf("the uncompressed kernel '%s' can only be booted with KernelDirect", UncompressedKernelFile)
+	}
+	arch, err := hostLCOWArch()
+	if err != nil {
+		return nil, err
+	}
 
 	doc := &hcsschema.ComputeSystem{
 		Owner:                             uvm.owner,
@@ -213,9 +304,7 @@ func CreateLCOW(opts *OptionsLCOW) (_ *UtilityVM, err error) {
 			Chipset:     &hcsschema.Chipset{},
 			ComputeTopology: &hcsschema.Topology{
 				Memory: &hcsschema.Memory2{
-					SizeInMB:             memorySizeInMB,
-					AllowOvercommit:      opts.AllowOvercommit,
-					EnableDeferredCommit: opts.EnableDeferredCommit,
+					SizeInMB: memorySizeInMB,
 				},
 				Processor: &hcsschema.Processor2{
 					Count:  uvm.processorCount,
@@ -244,6 +333,10 @@ func CreateLCOW(opts *OptionsLCOW) (_ *UtilityVM, err error) {
 		}
 	}
 
+	if opts.EnableVirtualTPM {
+		enableVirtualTPM(doc.VirtualMachine)
+	}
+
 	if opts.UseGuestConnection && !opts.ExternalGuestConnection {
 		doc.VirtualMachine.GuestConnection = &hcsschema.GuestConnection{
 			UseVsock:            true,
@@ -252,11 +345,11 @@ func CreateLCOW(opts *OptionsLCOW) (_ *UtilityVM, err error) {
 	}
 
 	if uvm.scsiControllerCount > 0 {
-		// TODO: JTERRY75 - this should enumerate scsicount and add an entry per value.
-		doc.VirtualMachine.Devices.Scsi = map[string]hcsschema.Scsi{
-			"0": {
+		doc.VirtualMachine.Devices.Scsi = make(map[string]hcsschema.Scsi)
+		for i := 0; i < int(uvm.scsiControllerCount); i++ {
+			doc.VirtualMachine.Devices.Scsi[strconv.Itoa(i)] = hcsschema.Scsi{
 				Attachments: make(map[string]hcsschema.Attachment),
-			},
+			}
 		}
 	}
 	if uvm.vpmemMaxCount > 0 {
@@ -276,7 +369,7 @@ func CreateLCOW(opts *OptionsLCOW) (_ *UtilityVM, err error) {
 		// Support for VPMem VHD(X) booting rather than initrd..
 		kernelArgs = "root=/dev/pmem0 ro rootwait init=/init"
 		imageFormat := "Vhd1"
-		if strings.ToLower(filepath.Ext(opts.RootFSFile)) == "vhdx" {
+		if strings.ToLower(filepath.Ext(opts.RootFSFile)) == ".vhdx" {
 			imageFormat = "Vhdx"
 		}
 		doc.VirtualMachine.Devices.VirtualPMem.Devices = map[string]hcsschema.VirtualPMemDevice{
@@ -296,15 +389,16 @@ func CreateLCOW(opts *OptionsLCOW) (_ *UtilityVM, err error) {
 
 	vmDebugging := false
 	if opts.ConsolePipe != "" {
-		vmDebugging = true
-		kernelArgs += " 8250_core.nr_uarts=1 8250_core.skip_txen_test=1 console=ttyS0,115200"
+		// A captured console is only for diagnostics.
+		vmDebugging = opts.ConsoleLogPath == "" && opts.GuestCrashDumpPath == ""
+		kernelArgs += arch.consoleArgs
 		doc.VirtualMachine.Devices.ComPorts = map[string]hcsschema.ComPort{
 			"0": { // Which is actually COM1
 				NamedPipe: opts.ConsolePipe,
 			},
 		}
 	} else {
-		kernelArgs += " 8250_core.nr_uarts=0"
+		kernelArgs += arch.noConsoleArgs
 	}
 
 	if opts.EnableGraphicsConsole {
@@ -343,7 +437,7 @@ func CreateLCOW(opts *OptionsLCOW) (_ *UtilityVM, err error) {
 		initArgs = `sh -c "` + initArgs + ` & exec sh"`
 	}
 
-	kernelArgs += ` pci=off brd.rd_nr=0 pmtmr=0 -- ` + initArgs
+	kernelArgs += arch.platformArgs + ` -- ` + initArgs
 
 	if !opts.KernelDirect {
 		doc.VirtualMachine.Chipset.Uefi = &hcsschema.Uefi{
@@ -364,11 +458,28 @@ func CreateLCOW(opts *OptionsLCOW) (_ *UtilityVM, err error) {
 		}
 	}
 
+	if err := setupMemory(opts.Options, doc.VirtualMachine.ComputeTopology.Memory); err != nil {
+		return nil, err
+	}
+	if err := setupNuma(opts.Options, doc.VirtualMachine.ComputeTopology, osversion.Get().Build); err != nil {
+		return nil, err
+	}
+	if err := uvm.setupCPUGroup(opts.Options, doc.VirtualMachine.ComputeTopology.Processor); err != nil {
+		return nil, err
+	}
+	if err := uvm.setupHvSocketServices(opts.Options, doc.VirtualMachine.Devices.HvSocket.HvSocketConfig); err != nil {
+		return nil, err
+	}
+
 	fullDoc, err := mergemaps.MergeJSON(doc, ([]byte)(opts.AdditionHCSDocumentJSON))
 	if err != nil {
 		return nil, fmt.Errorf("failed to merge additional JSON '%s': %s", opts.AdditionHCSDocumentJSON, err)
 	}
 
+	if err := uvm.reserve(opts.ReservationLimits, memorySizeInMB); err != nil {
+		return nil, err
+	}
+
 	err = uvm.create(fullDoc)
 	if err != nil {
 		return nil, err
@@ -396,6 +507,69 @@ func CreateLCOW(opts *OptionsLCOW) (_ *UtilityVM, err error) {
 	return uvm, nil
 }
 
+// OpenLCOW opens the running LCOW utility VM `opts.ID` that was created and
+// started by another process, such as a pool of pre-booted utility VMs. `opts`
+// MUST be the options the utility VM was created with as the state of its
+// devices is derived from them, except for `opts.MemorySizeInMB` which MUST be
+// the memory actually assigned to the utility VM. Only utility VMs whose guest
+// connection is managed by the HCS can be opened.
+func OpenLCOW(opts *OptionsLCOW) (_ *UtilityVM, err error) {
+	op := "uvm::OpenLCOW"
+	log := logrus.WithFields(logrus.Fields{
+		logfields.UVMID: opts.ID,
+	})
+	log.Debug(op + " - Begin Operation")
+	defer func() {
+		if err != nil {
+			log.Data[logrus.ErrorKey] = err
+			log.Error(op + " - End Operation - Error")
+		} else {
+			log.Debug(op + " - End Operation - Success")
+		}
+	}()
+
+	if opts.ID == "" {
+		return nil, fmt.Errorf("the ID of the utility VM to open must be specified")
+	}
+	if !opts.UseGuestConnection || opts.ExternalGuestConnection {
+		return nil, fmt.Errorf("opening a utility VM requires the guest connection of the HCS")
+	}
+
+	uvm := newLCOWUtilityVM(opts)
+	defer func() {
+		if err != nil {
+			uvm.Detach()
+		}
+	}()
+
+	if opts.PreferredRootFSType == PreferredRootFSTypeVHD {
+		uvm.vpmemDevices[0] = vpmemInfo{
+			hostPath: opts.RootFSFile,
+			uvmPath:  "/",
+			refCount: 1,
+		}
+	}
+	// The memory of a running utility VM is not part of its properties so it
+	// is reported by the creator of the utility VM in `opts.MemorySizeInMB`.
+	uvm.memorySizeInMB = opts.MemorySizeInMB
+	// The CPU group, if any, is owned by the creator of the utility VM.
+	uvm.cpuGroupID = opts.CPUGroupID
+	// The HvSocket services were registered by the creator of the utility VM
+	// with the same options.
+	if err := uvm.setupHvSocketServices(opts.Options, &hcsschema.HvSocketSystemConfig{}); err != nil {
+		return nil, err
+	}
+
+	if err := uvm.open(); err != nil {
+		return nil, err
+	}
+	uvm.waitForExit()
+	if err := uvm.cacheGuestConnectionProperties(); err != nil {
+		return nil, err
+	}
+	return uvm, nil
+}
+
 func (uvm *UtilityVM) listenVsock(port uint32) (net.Listener, error) {
 	return winio.ListenHvsock(&winio.HvsockAddr{
 		VMID:      uvm.runtimeID,
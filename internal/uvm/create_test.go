@@ -1,6 +1,9 @@
 package uvm
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -23,3 +26,108 @@ func TestCreateWCOWBadLayerFolders(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestCreateBadSCSIControllerCount(t *testing.T) {
+	lopts := NewDefaultOptionsLCOW(t.Name(), "")
+	lopts.SCSIControllerCount = MaxSCSIControllerCount + 1
+	// Validated after the boot files so they must exist.
+	dir, err := ioutil.TempDir("", "bootfiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, f := range []string{KernelFile, InitrdFile} {
+		if err := ioutil.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lopts.UpdateBootFilesPath(dir)
+	if _, err := CreateLCOW(lopts); err == nil || err.Error() != `SCSI controller count cannot be greater than 4` {
+		t.Fatal(err)
+	}
+
+	for _, count := range []uint32{0, MaxSCSIControllerCount + 1} {
+		wopts := NewDefaultOptionsWCOW(t.Name(), "")
+		wopts.SCSIControllerCount = count
+		if _, err := CreateWCOW(wopts); err == nil || err.Error() != `SCSI controller count must be between 1 and 4` {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCreateLCOW_ScratchDiscardEncrypted(t *testing.T) {
+	lopts := NewDefaultOptionsLCOW(t.Name(), "")
+	lopts.EnableScratchDiscard = true
+	lopts.EnableScratchEncryption = true
+	// Validated after the boot files so they must exist.
+	dir, err := ioutil.TempDir("", "bootfiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, f := range []string{KernelFile, InitrdFile} {
+		if err := ioutil.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lopts.UpdateBootFilesPath(dir)
+	if _, err := CreateLCOW(lopts); err == nil || err.Error() != `scratch discard cannot be enabled for encrypted scratch disks` {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateBootFilesPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bootfiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	opts := &OptionsLCOW{Options: newDefaultOptions(t.Name(), "")}
+	opts.UpdateBootFilesPath(dir)
+	if opts.BootFilesPath != dir || opts.RootFSFile != InitrdFile || opts.PreferredRootFSType != PreferredRootFSTypeInitRd || opts.KernelFile != KernelFile {
+		t.Fatalf("expected initrd and compressed kernel in an empty path, got %+v", opts)
+	}
+
+	for _, f := range []string{VhdFile, UncompressedKernelFile} {
+		if err := ioutil.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	opts.UpdateBootFilesPath(dir)
+	if opts.RootFSFile != VhdFile || opts.PreferredRootFSType != PreferredRootFSTypeVHD {
+		t.Fatalf("expected the VHD root file system, got %s", opts.RootFSFile)
+	}
+	if opts.KernelFile != KernelFile {
+		t.Fatalf("expected the compressed kernel for a UEFI boot, got %s", opts.KernelFile)
+	}
+	opts.KernelDirect = true
+	opts.UpdateBootFilesPath(dir)
+	if opts.KernelFile != UncompressedKernelFile {
+		t.Fatalf("expected the uncompressed kernel for a direct boot, got %s", opts.KernelFile)
+	}
+}
+
+func TestCreateLCOWUncompressedKernelRequiresKernelDirect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bootfiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, f := range []string{InitrdFile, UncompressedKernelFile} {
+		if err := ioutil.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := NewDefaultOptionsLCOW(t.Name(), "")
+	opts.KernelDirect = false
+	opts.BootFilesPath = dir
+	opts.KernelFile = UncompressedKernelFile
+	opts.RootFSFile = InitrdFile
+	opts.PreferredRootFSType = PreferredRootFSTypeInitRd
+	_, err = CreateLCOW(opts)
+	if err == nil || err.Error() != `the uncompressed kernel 'vmlinux' can only be booted with KernelDirect` {
+		t.Fatal(err)
+	}
+}
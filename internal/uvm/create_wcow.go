@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/Microsoft/go-winio"
 	"github.com/Microsoft/go-winio/pkg/guid"
+	"github.com/Microsoft/hcsshim/internal/backend"
 	"github.com/Microsoft/hcsshim/internal/gcs"
 	"github.com/Microsoft/hcsshim/internal/logfields"
 	"github.com/Microsoft/hcsshim/internal/mergemaps"
@@ -14,6 +16,7 @@ import (
 	"github.com/Microsoft/hcsshim/internal/schemaversion"
 	"github.com/Microsoft/hcsshim/internal/uvmfolder"
 	"github.com/Microsoft/hcsshim/internal/wcow"
+	"github.com/Microsoft/hcsshim/osversion"
 	"github.com/sirupsen/logrus"
 )
 
@@ -22,6 +25,23 @@ type OptionsWCOW struct {
 	*Options
 
 	LayerFolders []string // Set of folders for base layers and scratch. Ordered from top most read-only through base read-only layer, followed by scratch
+
+	// TemplateID, if set, creates the UVM as a clone of the UVM with this ID
+	// saved via `SaveAsTemplate`. The clone starts from the saved state of the
+	// template rather than booting and MUST be created with the same options as
+	// the template. The scratch of the template is copied for the clone.
+	TemplateID string
+
+	// SaveStateFilePath, if set, creates the UVM by restoring the state saved
+	// to this file via `Save` rather than booting. The UVM MUST be created with
+	// the same options and layer folders as the saved UVM as it resumes from
+	// its scratch. The VSMB shares of the saved UVM are restored from
+	// `<SaveStateFilePath>.json`.
+	SaveStateFilePath string
+
+	// ConsolePipe, if set, attaches the serial console (COM1) of the UVM to
+	// this named pipe. eg \\.\pipe\vmpipe
+	ConsolePipe string
 }
 
 // NewDefaultOptionsWCOW creates the default options for a bootable version of
@@ -32,9 +52,11 @@ type OptionsWCOW struct {
 // `owner` the owner of the compute system. If not passed will use the
 // executable files name.
 func NewDefaultOptionsWCOW(id, owner string) *OptionsWCOW {
-	return &OptionsWCOW{
+	opts := &OptionsWCOW{
 		Options: newDefaultOptions(id, owner),
 	}
+	opts.EnableHotHint = true
+	return opts
 }
 
 // CreateWCOW creates an HCS compute system representing a utility VM.
@@ -69,8 +91,14 @@ func CreateWCOW(opts *OptionsWCOW) (_ *UtilityVM, err error) {
 		id:                  opts.ID,
 		owner:               opts.Owner,
 		operatingSystem:     "windows",
-		scsiControllerCount: 1,
+		backend:             backend.OrDefault(opts.Backend),
+		scsiControllerCount: opts.SCSIControllerCount,
 		vsmbShares:          make(map[string]*vsmbShare),
+		virtualTPM:          opts.EnableVirtualTPM,
+		consolePipe:         opts.ConsolePipe,
+		consoleLogPath:      opts.ConsoleLogPath,
+		crashDumpPath:       opts.GuestCrashDumpPath,
+		layerPolicy:         opts.LayerAttachmentPolicy,
 	}
 	defer func() {
 		if err != nil {
@@ -84,6 +112,28 @@ func CreateWCOW(opts *OptionsWCOW) (_ *UtilityVM, err error) {
 
 	// Align the requested memory size.
 	memorySizeInMB := uvm.normalizeMemorySize(opts.MemorySizeInMB)
+	uvm.memorySizeInMB = memorySizeInMB
+
+	if opts.SCSIControllerCount == 0 || opts.SCSIControllerCount > MaxSCSIControllerCount {
+		return nil, fmt.Errorf("SCSI controller count must be between 1 and %d", MaxSCSIControllerCount)
+	}
+	if opts.ConsoleLogPath != "" && opts.ConsolePipe == "" {
+		return nil, fmt.Errorf("capturing the serial console requires a console pipe")
+	}
+	if err := validateLayerAttachmentPolicy(uvm.operatingSystem, opts.LayerAttachmentPolicy); err != nil {
+		return nil, err
+	}
+	if opts.SaveStateFilePath != "" {
+		if opts.TemplateID != "" {
+			return nil, fmt.Errorf("a clone cannot be restored from a saved state")
+		}
+		if opts.ExternalGuestConnection {
+			return nil, fmt.Errorf("a utility VM with an external guest connection cannot be restored from a saved state")
+		}
+	}
+	if err := removeStaleCrashDump(opts.GuestCrashDumpPath); err != nil {
+		return nil, fmt.Errorf("failed to remove stale guest crash dump: %s", err)
+	}
 
 	if len(opts.LayerFolders) < 2 {
 		return nil, fmt.Errorf("at least 2 LayerFolders must be supplied")
@@ -112,7 +162,23 @@ func CreateWCOW(opts *OptionsWCOW) (_ *UtilityVM, err error) {
 
 	// Create sandbox.vhdx in the scratch folder based on the template, granting the correct permissions to it
 	scratchPath := filepath.Join(scratchFolder, "sandbox.vhdx")
-	if _, err := os.Stat(scratchPath); os.IsNotExist(err) {
+	if opts.TemplateID != "" {
+		// A clone must start from the scratch of its template as it was when
+		// the template was saved.
+		config, err := LoadTemplateConfig(opts.TemplateID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load template '%s': %s", opts.TemplateID, err)
+		}
+		if err := wcow.CloneUVMScratch(config.ScratchPath, scratchFolder, uvm.id); err != nil {
+			return nil, fmt.Errorf("failed to clone scratch: %s", err)
+		}
+		uvm.templateID = opts.TemplateID
+	} else if opts.SaveStateFilePath != "" {
+		// A restored UVM resumes from the scratch it was saved with.
+		if _, err := os.Stat(scratchPath); err != nil {
+			return nil, fmt.Errorf("failed to find scratch of the saved utility VM: %s", err)
+		}
+	} else if _, err := os.Stat(scratchPath); os.IsNotExist(err) {
 		if err := wcow.CreateUVMScratch(uvmFolder, scratchFolder, uvm.id); err != nil {
 			return nil, fmt.Errorf("failed to create scratch: %s", err)
 		}
@@ -134,11 +200,7 @@ func CreateWCOW(opts *OptionsWCOW) (_ *UtilityVM, err error) {
 			},
 			ComputeTopology: &hcsschema.Topology{
 				Memory: &hcsschema.Memory2{
-					SizeInMB:        memorySizeInMB,
-					AllowOvercommit: opts.AllowOvercommit,
-					// EnableHotHint is not compatible with physical.
-					EnableHotHint:        opts.AllowOvercommit,
-					EnableDeferredCommit: opts.EnableDeferredCommit,
+					SizeInMB: memorySizeInMB,
 				},
 				Processor: &hcsschema.Processor2{
 					Count:  uvm.processorCount,
@@ -188,6 +250,19 @@ func CreateWCOW(opts *OptionsWCOW) (_ *UtilityVM, err error) {
 		doc.VirtualMachine.GuestConnection = &hcsschema.GuestConnection{}
 	}
 
+	if opts.TemplateID != "" {
+		doc.VirtualMachine.RestoreState = &hcsschema.RestoreState{
+			TemplateSystemId: opts.TemplateID,
+		}
+	} else if opts.SaveStateFilePath != "" {
+		doc.VirtualMachine.RestoreState = &hcsschema.RestoreState{
+			SaveStateFilePath: opts.SaveStateFilePath,
+		}
+		if err := uvm.restoreVSMBShares(opts.SaveStateFilePath, doc); err != nil {
+			return nil, err
+		}
+	}
+
 	// Handle StorageQoS if set
 	if opts.StorageQoSBandwidthMaximum > 0 || opts.StorageQoSIopsMaximum > 0 {
 		doc.VirtualMachine.StorageQoS = &hcsschema.StorageQoS{
@@ -196,13 +271,55 @@ func CreateWCOW(opts *OptionsWCOW) (_ *UtilityVM, err error) {
 		}
 	}
 
+	if opts.EnableVirtualTPM {
+		enableVirtualTPM(doc.VirtualMachine)
+	}
+
+	if opts.ConsolePipe != "" {
+		doc.VirtualMachine.Devices.ComPorts = map[string]hcsschema.ComPort{
+			"0": { // Which is actually COM1
+				NamedPipe: opts.ConsolePipe,
+			},
+		}
+	}
+
+	if opts.GuestCrashDumpPath != "" {
+		doc.VirtualMachine.Devices.GuestCrashReporting = &hcsschema.GuestCrashReporting{
+			WindowsCrashSettings: &hcsschema.WindowsCrashReporting{
+				DumpFileName: opts.GuestCrashDumpPath,
+			},
+		}
+	}
+
+	for i := 1; i < int(uvm.scsiControllerCount); i++ {
+		doc.VirtualMachine.Devices.Scsi[strconv.Itoa(i)] = hcsschema.Scsi{
+			Attachments: make(map[string]hcsschema.Attachment),
+		}
+	}
 	uvm.scsiLocations[0][0].hostPath = doc.VirtualMachine.Devices.Scsi["0"].Attachments["0"].Path
 
+	if err := setupMemory(opts.Options, doc.VirtualMachine.ComputeTopology.Memory); err != nil {
+		return nil, err
+	}
+	if err := setupNuma(opts.Options, doc.VirtualMachine.ComputeTopology, osversion.Get().Build); err != nil {
+		return nil, err
+	}
+	if err := uvm.setupCPUGroup(opts.Options, doc.VirtualMachine.ComputeTopology.Processor); err != nil {
+		return nil, err
+	}
+	if err := uvm.setupHvSocketServices(opts.Options, doc.VirtualMachine.Devices.HvSocket.HvSocketConfig); err != nil {
+		return nil, err
+	}
+
 	fullDoc, err := mergemaps.MergeJSON(doc, ([]byte)(opts.AdditionHCSDocumentJSON))
 	if err != nil {
 		return nil, fmt.Errorf("failed to merge additional JSON '%s': %s", opts.AdditionHCSDocumentJSON, err)
 	}
 
+	if err := uvm.reserve(opts.ReservationLimits, memorySizeInMB); err != nil {
+		return nil, err
+	}
+
 	err = uvm.create(fullDoc)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,201 @@
+package uvm
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/Microsoft/go-winio"
+	"github.com/Microsoft/go-winio/pkg/guid"
+	"github.com/Microsoft/hcsshim/internal/hvsock"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/Microsoft/hcsshim/internal/requesttype"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	hvSocketServiceResourcePathFormat = "VirtualMachine/Devices/HvSocket/HvSocketConfig/ServiceTable/%s"
+
+	// hvSocketServiceSecurityDescriptor allows administrators and SYSTEM to
+	// bind and connect to a registered HvSocket service.
+	hvSocketServiceSecurityDescriptor = "D:P(A;;FA;;;SY)(A;;FA;;;BA)"
+)
+
+// hvSocketListener is an HvSocket listener that releases its service
+// registration when closed.
+type hvSocketListener struct {
+	*winio.HvsockListener
+	release func() error
+}
+
+func (l *hvSocketListener) Close() error {
+	err := l.HvsockListener.Close()
+	if rerr := l.release(); err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// hvSocketConn is an HvSocket connection that releases its service
+// registration when closed.
+type hvSocketConn struct {
+	*hvsock.Conn
+	release func() error
+}
+
+func (c *hvSocketConn) Close() error {
+	err := c.Conn.Close()
+	if rerr := c.release(); err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// ListenHvSocket registers the HvSocket service `serviceID` with the utility
+// VM and listens on it for connections from the guest. The registration is
+// released when the listener is closed.
+func (uvm *UtilityVM) ListenHvSocket(serviceID guid.GUID) (net.Listener, error) {
+	release, err := uvm.addHvSocketService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	l, err := winio.ListenHvsock(&winio.HvsockAddr{
+		VMID:      uvm.runtimeID,
+		ServiceID: serviceID,
+	})
+	if err != nil {
+		release()
+		return nil, err
+	}
+	return &hvSocketListener{HvsockListener: l, release: release}, nil
+}
+
+// DialHvSocket registers the HvSocket service `serviceID` with the utility VM
+// and connects to the guest listening on it. The registration is released
+// when the connection is closed.
+func (uvm *UtilityVM) DialHvSocket(ctx context.Context, serviceID guid.GUID) (net.Conn, error) {
+	release, err := uvm.addHvSocketService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	c, err := hvsock.Dial(ctx, &winio.HvsockAddr{
+		VMID:      uvm.runtimeID,
+		ServiceID: serviceID,
+	})
+	if err != nil {
+		release()
+		return nil, err
+	}
+	return &hvSocketConn{Conn: c, release: release}, nil
+}
+
+// setupHvSocketServices validates the HvSocket services of `opts` and
+// registers them in the service table of `config`. The registrations are
+// referenced for the life of the utility VM so that they are neither
+// replaced nor removed by `ListenHvSocket` or `DialHvSocket`.
+func (uvm *UtilityVM) setupHvSocketServices(opts *Options, config *hcsschema.HvSocketSystemConfig) error {
+	for id, service := range opts.HvSocketServiceTable {
+		serviceID, err := guid.FromString(id)
+		if err != nil {
+			return fmt.Errorf("invalid HvSocket service ID '%s': %s", id, err)
+		}
+		if config.ServiceTable == nil {
+			config.ServiceTable = make(map[string]hcsschema.HvSocketServiceConfig)
+		}
+		config.ServiceTable[serviceID.String()] = service
+		if uvm.hvSocketServices == nil {
+			uvm.hvSocketServices = make(map[guid.GUID]uint32)
+		}
+		uvm.hvSocketServices[serviceID] = 1
+	}
+	return nil
+}
+
+// addHvSocketService adds a reference to the registration of the HvSocket
+// service `serviceID` in the service table of the utility VM, registering it
+// on the first reference. It returns the function releasing the reference,
+// which removes the registration on the last.
+func (uvm *UtilityVM) addHvSocketService(serviceID guid.GUID) (_ func() error, err error) {
+	op := "uvm::addHvSocketService"
+	log := logrus.WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"service-id":    serviceID.String(),
+	})
+	log.Debug(op + " - Begin Operation")
+	defer func() {
+		if err != nil {
+			log.Data[logrus.ErrorKey] = err
+			log.Error(op + " - End Operation - Error")
+		} else {
+			log.Debug(op + " - End Operation - Success")
+		}
+	}()
+
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+
+	if uvm.hvSocketServices[serviceID] == 0 {
+		modification := &hcsschema.ModifySettingRequest{
+			RequestType:  requesttype.Update,
+			ResourcePath: fmt.Sprintf(hvSocketServiceResourcePathFormat, serviceID),
+			Settings: hcsschema.HvSocketServiceConfig{
+				BindSecurityDescriptor:    hvSocketServiceSecurityDescriptor,
+				ConnectSecurityDescriptor: hvSocketServiceSecurityDescriptor,
+			},
+		}
+		if err := uvm.Modify(modification); err != nil {
+			return nil, fmt.Errorf("failed to register HvSocket service %s with utility VM %s: %s", serviceID, uvm.id, err)
+		}
+		if uvm.hvSocketServices == nil {
+			uvm.hvSocketServices = make(map[guid.GUID]uint32)
+		}
+	}
+	uvm.hvSocketServices[serviceID]++
+
+	var once sync.Once
+	return func() (err error) {
+		once.Do(func() {
+			err = uvm.removeHvSocketService(serviceID)
+		})
+		return err
+	}, nil
+}
+
+// removeHvSocketService releases a reference to the registration of the
+// HvSocket service `serviceID`, removing it from the service table of the
+// utility VM when the last reference is released.
+func (uvm *UtilityVM) removeHvSocketService(serviceID guid.GUID) (err error) {
+	op := "uvm::removeHvSocketService"
+	log := logrus.WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"service-id":    serviceID.String(),
+	})
+	log.Debug(op + " - Begin Operation")
+	defer func() {
+		if err != nil {
+			log.Data[logrus.ErrorKey] = err
+			log.Error(op + " - End Operation - Error")
+		} else {
+			log.Debug(op + " - End Operation - Success")
+		}
+	}()
+
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+
+	if uvm.hvSocketServices[serviceID] > 1 {
+		uvm.hvSocketServices[serviceID]--
+		return nil
+	}
+	modification := &hcsschema.ModifySettingRequest{
+		RequestType:  requesttype.Remove,
+		ResourcePath: fmt.Sprintf(hvSocketServiceResourcePathFormat, serviceID),
+	}
+	if err := uvm.Modify(modification); err != nil {
+		return fmt.Errorf("failed to remove HvSocket service %s from utility VM %s: %s", serviceID, uvm.id, err)
+	}
+	delete(uvm.hvSocketServices, serviceID)
+	return nil
+}
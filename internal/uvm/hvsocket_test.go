@@ -0,0 +1,100 @@
+package uvm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Microsoft/go-winio/pkg/guid"
+	"github.com/Microsoft/hcsshim/internal/cow/cowtest"
+	"github.com/Microsoft/hcsshim/internal/requesttype"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+)
+
+func Test_HvSocketService_RefCount(t *testing.T) {
+	c := cowtest.NewContainer("uvm", "linux", false)
+	vm := &UtilityVM{operatingSystem: "linux", hcsSystem: c}
+	serviceID, err := guid.NewV4()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release1, err := vm.addHvSocketService(serviceID)
+	if err != nil {
+		t.Fatalf("failed to register service: %s", err)
+	}
+	release2, err := vm.addHvSocketService(serviceID)
+	if err != nil {
+		t.Fatalf("failed to register service: %s", err)
+	}
+	modifies := c.Modifies()
+	if len(modifies) != 1 {
+		t.Fatalf("expected the service to be registered once, got %d modifies", len(modifies))
+	}
+	add := modifies[0].(*hcsschema.ModifySettingRequest)
+	if add.RequestType != requesttype.Update || add.ResourcePath != fmt.Sprintf(hvSocketServiceResourcePathFormat, serviceID) {
+		t.Fatalf("unexpected registration: %+v", add)
+	}
+
+	if err := release1(); err != nil {
+		t.Fatalf("failed to release service: %s", err)
+	}
+	// Releasing the same reference again is a no-op.
+	if err := release1(); err != nil {
+		t.Fatalf("failed to release service: %s", err)
+	}
+	if len(c.Modifies()) != 1 {
+		t.Fatal("expected the service to stay registered while referenced")
+	}
+	if err := release2(); err != nil {
+		t.Fatalf("failed to release service: %s", err)
+	}
+	modifies = c.Modifies()
+	if len(modifies) != 2 || modifies[1].(*hcsschema.ModifySettingRequest).RequestType != requesttype.Remove {
+		t.Fatalf("expected the service to be removed on the last release, got: %+v", modifies)
+	}
+	if len(vm.hvSocketServices) != 0 {
+		t.Fatalf("expected no registered services, got: %v", vm.hvSocketServices)
+	}
+}
+
+func Test_setupHvSocketServices(t *testing.T) {
+	c := cowtest.NewContainer("uvm", "windows", false)
+	vm := &UtilityVM{operatingSystem: "windows", hcsSystem: c}
+	serviceID, err := guid.NewV4()
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := hcsschema.HvSocketServiceConfig{
+		BindSecurityDescriptor:    "D:P(A;;FA;;;SY)",
+		ConnectSecurityDescriptor: "D:P(A;;FA;;;SY)",
+	}
+	opts := &Options{
+		HvSocketServiceTable: map[string]hcsschema.HvSocketServiceConfig{
+			serviceID.String(): service,
+		},
+	}
+	config := &hcsschema.HvSocketSystemConfig{}
+	if err := vm.setupHvSocketServices(opts, config); err != nil {
+		t.Fatalf("failed to setup services: %s", err)
+	}
+	if config.ServiceTable[serviceID.String()] != service {
+		t.Fatalf("expected the service to be registered, got: %+v", config.ServiceTable)
+	}
+
+	// A service registered at creation is neither replaced nor removed.
+	release, err := vm.addHvSocketService(serviceID)
+	if err != nil {
+		t.Fatalf("failed to register service: %s", err)
+	}
+	if err := release(); err != nil {
+		t.Fatalf("failed to release service: %s", err)
+	}
+	if len(c.Modifies()) != 0 {
+		t.Fatalf("expected the service registered at creation to be kept, got: %+v", c.Modifies())
+	}
+
+	opts.HvSocketServiceTable = map[string]hcsschema.HvSocketServiceConfig{"not-a-guid": service}
+	if err := vm.setupHvSocketServices(opts, &hcsschema.HvSocketSystemConfig{}); err == nil {
+		t.Fatal("expected error for an invalid service ID")
+	}
+}
@@ -0,0 +1,60 @@
+package uvm
+
+import "fmt"
+
+// LayerAttachmentPolicy controls how the read-only container layers are
+// attached to an LCOW utility VM. WCOW layers are always shared over VSMB so
+// only `LayerAttachmentPolicyDefault` is supported for WCOW.
+type LayerAttachmentPolicy string
+
+const (
+	// LayerAttachmentPolicyDefault uses `LayerAttachmentPolicyVPMem` for LCOW.
+	LayerAttachmentPolicyDefault LayerAttachmentPolicy = ""
+	// LayerAttachmentPolicyVPMem attaches each LCOW layer to a VPMem device,
+	// falling back to SCSI for layers too large for a device or once every
+	// device is in use.
+	LayerAttachmentPolicyVPMem LayerAttachmentPolicy = "vpmem"
+	// LayerAttachmentPolicySCSI attaches every LCOW layer to SCSI.
+	LayerAttachmentPolicySCSI LayerAttachmentPolicy = "scsi"
+)
+
+// validateLayerAttachmentPolicy returns an error if `policy` is not supported
+// for a utility VM running `operatingSystem`.
+func validateLayerAttachmentPolicy(operatingSystem string, policy LayerAttachmentPolicy) error {
+	switch policy {
+	case LayerAttachmentPolicyDefault:
+		return nil
+	case LayerAttachmentPolicyVPMem, LayerAttachmentPolicySCSI:
+		if operatingSystem == "linux" {
+			return nil
+		}
+	default:
+		return fmt.Errorf("unknown layer attachment policy '%s'", policy)
+	}
+	return fmt.Errorf("layer attachment policy '%s' is not supported for %s utility VMs", policy, operatingSystem)
+}
+
+// LayerAttachmentPolicy returns how the read-only container layers are
+// attached to the utility VM. This is always `LayerAttachmentPolicyDefault`
+// for WCOW.
+func (uvm *UtilityVM) LayerAttachmentPolicy() LayerAttachmentPolicy {
+	if uvm.layerPolicy != LayerAttachmentPolicyDefault || uvm.operatingSystem == "windows" {
+		return uvm.layerPolicy
+	}
+	return LayerAttachmentPolicyVPMem
+}
+
+// RemoveLayer removes the read-only layer at `hostPath` from the VPMem device
+// or SCSI disk it was attached to by `AddVPMEM` or `AddSCSILayer`. LCOW only.
+func (uvm *UtilityVM) RemoveLayer(hostPath string) error {
+	if uvm.operatingSystem != "linux" {
+		return errNotSupported
+	}
+	uvm.m.Lock()
+	_, _, _, err := uvm.findSCSIAttachment(hostPath)
+	uvm.m.Unlock()
+	if err == nil {
+		return uvm.RemoveSCSI(hostPath)
+	}
+	return uvm.RemoveVPMEM(hostPath)
+}
@@ -0,0 +1,76 @@
+package uvm
+
+import (
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/cow/cowtest"
+	"github.com/Microsoft/hcsshim/internal/guestrequest"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+)
+
+func Test_validateLayerAttachmentPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		os     string
+		policy LayerAttachmentPolicy
+		valid  bool
+	}{
+		{"linux", LayerAttachmentPolicyDefault, true},
+		{"linux", LayerAttachmentPolicyVPMem, true},
+		{"linux", LayerAttachmentPolicySCSI, true},
+		{"linux", LayerAttachmentPolicy("vsmb"), false},
+		{"linux", "plan9", false},
+		{"windows", LayerAttachmentPolicyDefault, true},
+		{"windows", LayerAttachmentPolicy("vsmb"), false},
+		{"windows", LayerAttachmentPolicyVPMem, false},
+		{"windows", LayerAttachmentPolicySCSI, false},
+	} {
+		err := validateLayerAttachmentPolicy(tc.os, tc.policy)
+		if tc.valid && err != nil {
+			t.Fatalf("expected %s policy '%s' to be valid, got: %v", tc.os, tc.policy, err)
+		}
+		if !tc.valid && err == nil {
+			t.Fatalf("expected %s policy '%s' to be invalid", tc.os, tc.policy)
+		}
+	}
+}
+
+func Test_LayerAttachmentPolicy_Default(t *testing.T) {
+	if p := (&UtilityVM{operatingSystem: "linux"}).LayerAttachmentPolicy(); p != LayerAttachmentPolicyVPMem {
+		t.Fatalf("expected LCOW default %s, got: %s", LayerAttachmentPolicyVPMem, p)
+	}
+	if p := (&UtilityVM{operatingSystem: "windows"}).LayerAttachmentPolicy(); p != LayerAttachmentPolicyDefault {
+		t.Fatalf("expected WCOW default %s, got: %s", LayerAttachmentPolicyDefault, p)
+	}
+	if p := (&UtilityVM{operatingSystem: "linux", layerPolicy: LayerAttachmentPolicySCSI}).LayerAttachmentPolicy(); p != LayerAttachmentPolicySCSI {
+		t.Fatalf("expected %s, got: %s", LayerAttachmentPolicySCSI, p)
+	}
+}
+
+func Test_RemoveLayer_RemovesFromAttachedDevice(t *testing.T) {
+	c := cowtest.NewContainer("uvm", "linux", false)
+	uvm := &UtilityVM{operatingSystem: "linux", hcsSystem: c, scsiControllerCount: 1, vpmemMaxCount: DefaultVPMEMCount}
+	uvm.vpmemDevices[0] = vpmemInfo{hostPath: `C:\layer0\layer.vhd`, uvmPath: "/tmp/p0", refCount: 1}
+	uvm.scsiLocations[0][1] = scsiInfo{hostPath: `C:\layer1\layer.vhd`, uvmPath: "/tmp/S0/1", isLayer: true, refCount: 1}
+
+	if err := uvm.RemoveLayer(`C:\layer1\layer.vhd`); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if err := uvm.RemoveLayer(`C:\layer0\layer.vhd`); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	modifies := c.Modifies()
+	if len(modifies) != 2 {
+		t.Fatalf("expected 2 modifies, got: %d", len(modifies))
+	}
+	request := modifies[0].(*hcsschema.ModifySettingRequest).GuestRequest.(guestrequest.GuestRequest)
+	if disk := request.Settings.(guestrequest.LCOWMappedVirtualDisk); disk.Lun != 1 {
+		t.Fatalf("expected the SCSI layer to be removed, got: %+v", disk)
+	}
+	request = modifies[1].(*hcsschema.ModifySettingRequest).GuestRequest.(guestrequest.GuestRequest)
+	if device := request.Settings.(guestrequest.LCOWMappedVPMemDevice); device.DeviceNumber != 0 {
+		t.Fatalf("expected the VPMem layer to be removed, got: %+v", device)
+	}
+	if err := uvm.RemoveLayer(`C:\layer2\layer.vhd`); err == nil {
+		t.Fatal("expected an error removing a layer that is not attached")
+	}
+}
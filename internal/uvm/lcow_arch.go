@@ -0,0 +1,75 @@
+package uvm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/Microsoft/hcsshim/osversion"
+)
+
+// lcowArch describes the architecture specific parts of booting an LCOW
+// utility VM. A utility VM always has the same architecture as the host.
+type lcowArch struct {
+	// name is the `GOARCH` name of the architecture.
+	name string
+	// minBuild is the minimum host build that supports LCOW on this
+	// architecture.
+	minBuild uint16
+	// consoleArgs are the kernel args to enable the serial console on COM1.
+	consoleArgs string
+	// noConsoleArgs are the kernel args used when there is no serial console.
+	noConsoleArgs string
+	// platformArgs are the kernel args that disable devices that the utility
+	// VM does not expose.
+	platformArgs string
+}
+
+var lcowArchs = map[string]lcowArch{
+	"amd64": {
+		name:          "amd64",
+		minBuild:      osversion.RS5,
+		consoleArgs:   " 8250_core.nr_uarts=1 8250_core.skip_txen_test=1 console=ttyS0,115200",
+		noConsoleArgs: " 8250_core.nr_uarts=0",
+		platformArgs:  " pci=off brd.rd_nr=0 pmtmr=0",
+	},
+	"arm64": {
+		// The arm64 utility VM exposes a PL011 UART rather than an 8250 and
+		// has no ACPI PM timer.
+		name:         "arm64",
+		minBuild:     osversion.V19H1,
+		consoleArgs:  " console=ttyAMA0,115200",
+		platformArgs: " pci=off brd.rd_nr=0",
+	},
+}
+
+// getLCOWArch returns the boot settings for an LCOW utility VM of `arch` and
+// validates that the host build supports it.
+func getLCOWArch(arch string, build uint16) (lcowArch, error) {
+	a, ok := lcowArchs[arch]
+	if !ok {
+		return lcowArch{}, fmt.Errorf("LCOW is not supported on architecture '%s'", arch)
+	}
+	if build < a.minBuild {
+		return lcowArch{}, fmt.Errorf("LCOW on architecture '%s' is not supported on builds older than %d", arch, a.minBuild)
+	}
+	return a, nil
+}
+
+// hostLCOWArch returns the boot settings for an LCOW utility VM on this host.
+func hostLCOWArch() (lcowArch, error) {
+	return getLCOWArch(runtime.GOARCH, osversion.Get().Build)
+}
+
+// archBootFilesPath returns the directory holding the LCOW boot files for
+// `arch` in `path`. A single install can ship the boot files of several
+// architectures in subdirectories named after each, otherwise `path` holds the
+// boot files of the host architecture.
+func archBootFilesPath(path, arch string) string {
+	archPath := filepath.Join(path, arch)
+	if fi, err := os.Stat(archPath); err == nil && fi.IsDir() {
+		return archPath
+	}
+	return path
+}
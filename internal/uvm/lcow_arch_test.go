@@ -0,0 +1,64 @@
+package uvm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/osversion"
+)
+
+func Test_getLCOWArch_amd64(t *testing.T) {
+	a, err := getLCOWArch("amd64", osversion.RS5)
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if !strings.Contains(a.consoleArgs, "ttyS0") || !strings.Contains(a.platformArgs, "pmtmr=0") {
+		t.Fatalf("unexpected amd64 args: %+v", a)
+	}
+}
+
+func Test_getLCOWArch_arm64(t *testing.T) {
+	a, err := getLCOWArch("arm64", osversion.V19H1)
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if !strings.Contains(a.consoleArgs, "ttyAMA0") || strings.Contains(a.platformArgs, "pmtmr") || strings.Contains(a.noConsoleArgs, "8250") {
+		t.Fatalf("unexpected arm64 args: %+v", a)
+	}
+}
+
+func Test_getLCOWArch_arm64_OldBuild(t *testing.T) {
+	if _, err := getLCOWArch("arm64", osversion.RS5); err == nil {
+		t.Fatal("expected error for arm64 on RS5")
+	}
+}
+
+func Test_getLCOWArch_Unsupported(t *testing.T) {
+	if _, err := getLCOWArch("386", osversion.V19H1); err == nil {
+		t.Fatal("expected error for 386")
+	}
+}
+
+func Test_archBootFilesPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bootfiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if p := archBootFilesPath(dir, "arm64"); p != dir {
+		t.Fatalf("expected %s without an arch subdirectory, got %s", dir, p)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "arm64"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if p := archBootFilesPath(dir, "arm64"); p != filepath.Join(dir, "arm64") {
+		t.Fatalf("expected the arm64 subdirectory, got %s", p)
+	}
+	if p := archBootFilesPath(dir, "amd64"); p != dir {
+		t.Fatalf("expected %s for amd64, got %s", dir, p)
+	}
+}
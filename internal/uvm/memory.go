@@ -0,0 +1,21 @@
+package uvm
+
+import (
+	"errors"
+
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+)
+
+// setupMemory validates the memory backing settings of `opts` and sets them
+// on `memory`. The hot and cold hints are only set for virtually backed memory
+// as they are not compatible with physically backed memory.
+func setupMemory(opts *Options, memory *hcsschema.Memory2) error {
+	if !opts.AllowOvercommit && opts.EnableDeferredCommit {
+		return errors.New("deferred commit requires virtually backed memory")
+	}
+	memory.AllowOvercommit = opts.AllowOvercommit
+	memory.EnableDeferredCommit = opts.EnableDeferredCommit
+	memory.EnableHotHint = opts.AllowOvercommit && opts.EnableHotHint
+	memory.EnableColdHint = opts.AllowOvercommit && opts.EnableColdHint
+	return nil
+}
@@ -0,0 +1,43 @@
+package uvm
+
+import (
+	"testing"
+
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+)
+
+func Test_setupMemory_Virtual(t *testing.T) {
+	opts := &Options{AllowOvercommit: true, EnableDeferredCommit: true, EnableHotHint: true, EnableColdHint: true}
+	memory := &hcsschema.Memory2{SizeInMB: 1024}
+	if err := setupMemory(opts, memory); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	expected := hcsschema.Memory2{
+		SizeInMB:             1024,
+		AllowOvercommit:      true,
+		EnableDeferredCommit: true,
+		EnableHotHint:        true,
+		EnableColdHint:       true,
+	}
+	if *memory != expected {
+		t.Fatalf("expected %+v, got: %+v", expected, *memory)
+	}
+}
+
+func Test_setupMemory_Physical(t *testing.T) {
+	opts := &Options{EnableHotHint: true, EnableColdHint: true}
+	memory := &hcsschema.Memory2{SizeInMB: 1024}
+	if err := setupMemory(opts, memory); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if *memory != (hcsschema.Memory2{SizeInMB: 1024}) {
+		t.Fatalf("expected physically backed memory without hints, got: %+v", *memory)
+	}
+}
+
+func Test_setupMemory_PhysicalDeferredCommit(t *testing.T) {
+	opts := &Options{EnableDeferredCommit: true}
+	if err := setupMemory(opts, &hcsschema.Memory2{}); err == nil {
+		t.Fatal("expected error for deferred commit of physically backed memory")
+	}
+}
@@ -23,6 +23,12 @@ var (
 	// ErrNetNSNotFound is an error indicating the guest UVM does not have a
 	// network namespace by this id.
 	ErrNetNSNotFound = errors.New("network namespace not found")
+	// ErrEndpointAlreadyAttached is an error indicating the network namespace
+	// in the guest UVM already has an endpoint by this id.
+	ErrEndpointAlreadyAttached = errors.New("endpoint already added")
+	// ErrEndpointNotFound is an error indicating the network namespace in the
+	// guest UVM does not have an endpoint by this id.
+	ErrEndpointNotFound = errors.New("endpoint not found")
 )
 
 // AddNetNS adds network namespace inside the guest.
@@ -111,22 +117,70 @@ func (uvm *UtilityVM) AddEndpointsToNS(id string, endpoints []*hns.HNSEndpoint)
 
 	for _, endpoint := range endpoints {
 		if _, ok := ns.nics[endpoint.Id]; !ok {
-			nicID, err := guid.NewV4()
-			if err != nil {
-				return err
-			}
-			if err := uvm.addNIC(nicID, endpoint); err != nil {
+			if err := uvm.addEndpointToNS(ns, endpoint); err != nil {
 				return err
 			}
-			ns.nics[endpoint.Id] = &nicInfo{
-				ID:       nicID,
-				Endpoint: endpoint,
-			}
 		}
 	}
 	return nil
 }
 
+// AddEndpoint hot adds `endpoint` to the network namespace matching `id`. This
+// is safe to call after containers are already running in the namespace and
+// is used to attach secondary interfaces to a live pod.
+//
+// If no network namespace matches `id` returns `ErrNetNSNotFound`. If the
+// namespace already has an endpoint matching `endpoint.Id` returns
+// `ErrEndpointAlreadyAttached`.
+func (uvm *UtilityVM) AddEndpoint(id string, endpoint *hns.HNSEndpoint) (err error) {
+	op := "uvm::AddEndpoint"
+	log := logrus.WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"netns-id":      id,
+		"endpoint-id":   endpoint.Id,
+	})
+	log.Debug(op + " - Begin Operation")
+	defer func() {
+		if err != nil {
+			log.Data[logrus.ErrorKey] = err
+			log.Error(op + " - End Operation - Error")
+		} else {
+			log.Debug(op + " - End Operation - Success")
+		}
+	}()
+
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+
+	ns, ok := uvm.namespaces[id]
+	if !ok {
+		return ErrNetNSNotFound
+	}
+	if _, ok := ns.nics[endpoint.Id]; ok {
+		return ErrEndpointAlreadyAttached
+	}
+	return uvm.addEndpointToNS(ns, endpoint)
+}
+
+// addEndpointToNS adds a NIC for `endpoint` to the guest and records it in
+// `ns`.
+//
+// The caller MUST hold `uvm.m`.
+func (uvm *UtilityVM) addEndpointToNS(ns *namespaceInfo, endpoint *hns.HNSEndpoint) error {
+	nicID, err := guid.NewV4()
+	if err != nil {
+		return err
+	}
+	if err := uvm.addNIC(nicID, endpoint); err != nil {
+		return err
+	}
+	ns.nics[endpoint.Id] = &nicInfo{
+		ID:       nicID,
+		Endpoint: endpoint,
+	}
+	return nil
+}
+
 // RemoveNetNS removes the namespace from the uvm and all remaining endpoints in
 // the namespace.
 //
@@ -220,6 +274,48 @@ func (uvm *UtilityVM) RemoveEndpointsFromNS(id string, endpoints []*hns.HNSEndpo
 	return nil
 }
 
+// RemoveEndpoint hot removes the endpoint matching `endpointID` from the
+// network namespace matching `id` while leaving the namespace and any other
+// endpoints in it in place.
+//
+// If no network namespace matches `id` returns `ErrNetNSNotFound`. If the
+// namespace has no endpoint matching `endpointID` returns
+// `ErrEndpointNotFound`.
+func (uvm *UtilityVM) RemoveEndpoint(id, endpointID string) (err error) {
+	op := "uvm::RemoveEndpoint"
+	log := logrus.WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"netns-id":      id,
+		"endpoint-id":   endpointID,
+	})
+	log.Debug(op + " - Begin Operation")
+	defer func() {
+		if err != nil {
+			log.Data[logrus.ErrorKey] = err
+			log.Error(op + " - End Operation - Error")
+		} else {
+			log.Debug(op + " - End Operation - Success")
+		}
+	}()
+
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+
+	ns, ok := uvm.namespaces[id]
+	if !ok {
+		return ErrNetNSNotFound
+	}
+	ninfo, ok := ns.nics[endpointID]
+	if !ok || ninfo == nil {
+		return ErrEndpointNotFound
+	}
+	if err := uvm.removeNIC(ninfo.ID, ninfo.Endpoint); err != nil {
+		return err
+	}
+	delete(ns.nics, endpointID)
+	return nil
+}
+
 // IsNetworkNamespaceSupported returns bool value specifying if network namespace is supported inside the guest
 func (uvm *UtilityVM) isNetworkNamespaceSupported() bool {
 	return uvm.guestCaps.NamespaceAddRequestSupported
@@ -333,7 +429,7 @@ func (uvm *UtilityVM) removeNIC(id guid.GUID, endpoint *hns.HNSEndpoint) error {
 				RequestType:  requesttype.Remove,
 				Settings: &guestrequest.LCOWNetworkAdapter{
 					NamespaceID: endpoint.Namespace.ID,
-					ID:          endpoint.Id,
+					ID:          id.String(),
 				},
 			}
 		}
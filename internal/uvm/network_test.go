@@ -0,0 +1,101 @@
+package uvm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/cow/cowtest"
+	"github.com/Microsoft/hcsshim/internal/hns"
+	"github.com/Microsoft/hcsshim/internal/requesttype"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+)
+
+func newNetworkTestUVM() (*UtilityVM, *cowtest.Container) {
+	c := cowtest.NewContainer("uvm", "linux", false)
+	vm := &UtilityVM{
+		operatingSystem: "linux",
+		hcsSystem:       c,
+		namespaces: map[string]*namespaceInfo{
+			"ns": {nics: make(map[string]*nicInfo)},
+		},
+	}
+	return vm, c
+}
+
+func Test_AddEndpoint_NetNSNotFound(t *testing.T) {
+	vm, c := newNetworkTestUVM()
+	if err := vm.AddEndpoint("missing", &hns.HNSEndpoint{Id: "ep"}); err != ErrNetNSNotFound {
+		t.Fatalf("expected: %v, got: %v", ErrNetNSNotFound, err)
+	}
+	if len(c.Modifies()) != 0 {
+		t.Fatal("expected no modify for a missing namespace")
+	}
+}
+
+func Test_AddEndpoint_Success(t *testing.T) {
+	vm, c := newNetworkTestUVM()
+	if err := vm.AddEndpoint("ns", &hns.HNSEndpoint{Id: "ep", MacAddress: "00-15-5D-00-00-01"}); err != nil {
+		t.Fatalf("failed to add endpoint: %s", err)
+	}
+	if _, ok := vm.namespaces["ns"].nics["ep"]; !ok {
+		t.Fatal("expected the endpoint to be recorded in the namespace")
+	}
+	modifies := c.Modifies()
+	if len(modifies) != 1 {
+		t.Fatalf("expected 1 modify, got %d", len(modifies))
+	}
+	add := modifies[0].(*hcsschema.ModifySettingRequest)
+	if add.RequestType != requesttype.Add || add.Settings.(hcsschema.NetworkAdapter).EndpointId != "ep" {
+		t.Fatalf("unexpected request: %+v", add)
+	}
+
+	if err := vm.AddEndpoint("ns", &hns.HNSEndpoint{Id: "ep"}); err != ErrEndpointAlreadyAttached {
+		t.Fatalf("expected: %v, got: %v", ErrEndpointAlreadyAttached, err)
+	}
+	if len(c.Modifies()) != 1 {
+		t.Fatal("expected no modify for an attached endpoint")
+	}
+}
+
+func Test_AddEndpoint_ModifyFailed_NotRecorded(t *testing.T) {
+	vm, c := newNetworkTestUVM()
+	c.OnModify = func(*cowtest.Container, interface{}) error {
+		return errors.New("modify failed")
+	}
+	if err := vm.AddEndpoint("ns", &hns.HNSEndpoint{Id: "ep"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, ok := vm.namespaces["ns"].nics["ep"]; ok {
+		t.Fatal("expected the endpoint not to be recorded")
+	}
+}
+
+func Test_RemoveEndpoint_NotFound(t *testing.T) {
+	vm, _ := newNetworkTestUVM()
+	if err := vm.RemoveEndpoint("missing", "ep"); err != ErrNetNSNotFound {
+		t.Fatalf("expected: %v, got: %v", ErrNetNSNotFound, err)
+	}
+	if err := vm.RemoveEndpoint("ns", "ep"); err != ErrEndpointNotFound {
+		t.Fatalf("expected: %v, got: %v", ErrEndpointNotFound, err)
+	}
+}
+
+func Test_RemoveEndpoint_Success(t *testing.T) {
+	vm, c := newNetworkTestUVM()
+	if err := vm.AddEndpoint("ns", &hns.HNSEndpoint{Id: "ep"}); err != nil {
+		t.Fatalf("failed to add endpoint: %s", err)
+	}
+	if err := vm.RemoveEndpoint("ns", "ep"); err != nil {
+		t.Fatalf("failed to remove endpoint: %s", err)
+	}
+	if _, ok := vm.namespaces["ns"].nics["ep"]; ok {
+		t.Fatal("expected the endpoint to be removed from the namespace")
+	}
+	modifies := c.Modifies()
+	if len(modifies) != 2 {
+		t.Fatalf("expected 2 modifies, got %d", len(modifies))
+	}
+	if remove := modifies[1].(*hcsschema.ModifySettingRequest); remove.RequestType != requesttype.Remove {
+		t.Fatalf("unexpected request: %+v", remove)
+	}
+}
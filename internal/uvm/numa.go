@@ -0,0 +1,48 @@
+package uvm
+
+import (
+	"errors"
+	"fmt"
+
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/Microsoft/hcsshim/osversion"
+)
+
+// MaxNumaNodeCount is the maximum number of virtual NUMA nodes of a utility VM.
+const MaxNumaNodeCount = 64
+
+// minNumaBuild is the oldest host build whose HCS honors the virtual NUMA
+// topology of a utility VM. Older builds silently ignore it.
+const minNumaBuild = osversion.V21H2Server
+
+// setupNuma validates the virtual NUMA settings of `opts` against the
+// processors and memory of `topology` and the host `build`, and sets its
+// virtual NUMA topology.
+func setupNuma(opts *Options, topology *hcsschema.Topology, build uint16) error {
+	if opts.NumaNodeCount == 0 {
+		if opts.NumaMemorySizePerNodeInMB != 0 {
+			return errors.New("the virtual NUMA node memory size requires a virtual NUMA node count")
+		}
+		return nil
+	}
+	if build < minNumaBuild {
+		return fmt.Errorf("virtual NUMA is not supported on builds older than %d", minNumaBuild)
+	}
+	if opts.AllowOvercommit {
+		return errors.New("virtual NUMA requires physically backed memory")
+	}
+	if opts.NumaNodeCount > MaxNumaNodeCount {
+		return fmt.Errorf("virtual NUMA node count cannot be greater than %d", MaxNumaNodeCount)
+	}
+	if opts.NumaNodeCount > uint32(topology.Processor.Count) {
+		return fmt.Errorf("virtual NUMA node count %d cannot be greater than the processor count %d", opts.NumaNodeCount, topology.Processor.Count)
+	}
+	if size := opts.NumaMemorySizePerNodeInMB; size != 0 && uint64(opts.NumaNodeCount)*size < uint64(topology.Memory.SizeInMB) {
+		return fmt.Errorf("%d virtual NUMA nodes of %dMB cannot hold %dMB of memory", opts.NumaNodeCount, size, topology.Memory.SizeInMB)
+	}
+	topology.Numa = &hcsschema.Numa{
+		VirtualNodeCount: uint8(opts.NumaNodeCount),
+		MaxSizePerNode:   opts.NumaMemorySizePerNodeInMB,
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package uvm
+
+import (
+	"testing"
+
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/Microsoft/hcsshim/osversion"
+)
+
+func newTestTopology() *hcsschema.Topology {
+	return &hcsschema.Topology{
+		Memory:    &hcsschema.Memory2{SizeInMB: 8192},
+		Processor: &hcsschema.Processor2{Count: 4},
+	}
+}
+
+func Test_setupNuma(t *testing.T) {
+	topology := newTestTopology()
+	if err := setupNuma(&Options{}, topology, osversion.RS5); err != nil || topology.Numa != nil {
+		t.Fatalf("expected a flat topology, got %+v %v", topology.Numa, err)
+	}
+
+	opts := &Options{NumaNodeCount: 2, NumaMemorySizePerNodeInMB: 4096}
+	if err := setupNuma(opts, topology, minNumaBuild); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if topology.Numa == nil || topology.Numa.VirtualNodeCount != 2 || topology.Numa.MaxSizePerNode != 4096 {
+		t.Fatalf("unexpected NUMA topology: %+v", topology.Numa)
+	}
+}
+
+func Test_setupNuma_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *Options
+	}{
+		{name: "SizeWithoutCount", opts: &Options{NumaMemorySizePerNodeInMB: 4096}},
+		{name: "Overcommit", opts: &Options{NumaNodeCount: 2, AllowOvercommit: true}},
+		{name: "TooManyNodes", opts: &Options{NumaNodeCount: MaxNumaNodeCount + 1}},
+		{name: "MoreNodesThanProcessors", opts: &Options{NumaNodeCount: 8}},
+		{name: "NodesTooSmall", opts: &Options{NumaNodeCount: 2, NumaMemorySizePerNodeInMB: 2048}},
+	}
+	for _, test := range tests {
+		topology := newTestTopology()
+		if err := setupNuma(test.opts, topology, minNumaBuild); err == nil {
+			t.Fatalf("%s: expected error", test.name)
+		}
+		if topology.Numa != nil {
+			t.Fatalf("%s: expected a flat topology, got %+v", test.name, topology.Numa)
+		}
+	}
+}
+
+func Test_setupNuma_OldBuild(t *testing.T) {
+	topology := newTestTopology()
+	opts := &Options{NumaNodeCount: 2}
+	if err := setupNuma(opts, topology, minNumaBuild-1); err == nil {
+		t.Fatal("expected error on a build without virtual NUMA")
+	}
+	if topology.Numa != nil {
+		t.Fatalf("expected a flat topology, got %+v", topology.Numa)
+	}
+}
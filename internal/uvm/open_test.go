@@ -0,0 +1,79 @@
+package uvm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/cow/cowtest"
+)
+
+func newOpenTestOptions(b *cowtest.Backend) *OptionsLCOW {
+	opts := &OptionsLCOW{
+		Options:             newDefaultOptions("pooled", "test"),
+		UseGuestConnection:  true,
+		VPMemDeviceCount:    DefaultVPMEMCount,
+		PreferredRootFSType: PreferredRootFSTypeVHD,
+		RootFSFile:          VhdFile,
+	}
+	opts.Backend = b
+	return opts
+}
+
+func Test_OpenLCOW(t *testing.T) {
+	b := cowtest.NewBackend("test", "linux")
+	if _, err := b.CreateComputeSystem(context.Background(), "pooled", nil); err != nil {
+		t.Fatal(err)
+	}
+	vm, err := OpenLCOW(newOpenTestOptions(b))
+	if err != nil {
+		t.Fatalf("failed to open utility VM: %s", err)
+	}
+	if vm.ID() != "pooled" || vm.OS() != "linux" {
+		t.Fatalf("unexpected utility VM %s (%s)", vm.ID(), vm.OS())
+	}
+	if vm.vpmemDevices[0].uvmPath != "/" {
+		t.Fatal("expected the root file system to occupy VPMem device 0")
+	}
+	if err := vm.Detach(); err != nil {
+		t.Fatalf("failed to detach utility VM: %s", err)
+	}
+	c := b.Container("pooled")
+	if !c.Closed() {
+		t.Fatal("expected the handle to the utility VM to be closed")
+	}
+	select {
+	case <-c.Exited():
+		t.Fatal("expected the utility VM to keep running after detach")
+	default:
+	}
+}
+
+func Test_OpenLCOW_Invalid(t *testing.T) {
+	b := cowtest.NewBackend("test", "linux")
+	if _, err := b.CreateComputeSystem(context.Background(), "pooled", nil); err != nil {
+		t.Fatal(err)
+	}
+	noID := newOpenTestOptions(b)
+	noID.ID = ""
+	external := newOpenTestOptions(b)
+	external.ExternalGuestConnection = true
+	noGuestConnection := newOpenTestOptions(b)
+	noGuestConnection.UseGuestConnection = false
+	missing := newOpenTestOptions(b)
+	missing.ID = "missing"
+
+	tests := []struct {
+		name string
+		opts *OptionsLCOW
+	}{
+		{name: "NoID", opts: noID},
+		{name: "ExternalGuestConnection", opts: external},
+		{name: "NoGuestConnection", opts: noGuestConnection},
+		{name: "Missing", opts: missing},
+	}
+	for _, test := range tests {
+		if _, err := OpenLCOW(test.opts); err == nil {
+			t.Fatalf("%s: expected error", test.name)
+		}
+	}
+}
@@ -3,6 +3,9 @@ package uvm
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path"
+	"path/filepath"
 	"strconv"
 
 	"github.com/Microsoft/hcsshim/internal/guestrequest"
@@ -15,13 +18,101 @@ import (
 
 type Plan9Share struct {
 	name, uvmPath string
+	// fileName is the name of the shared file for a share created with
+	// AddPlan9File. It is empty for a directory share.
+	fileName string
+}
+
+// UVMPath returns the path of the share in the utility VM.
+func (p *Plan9Share) UVMPath() string {
+	return p.uvmPath
+}
+
+// UVMFilePath returns the path of the shared file in the utility VM for a
+// share created with AddPlan9File. For a directory share it is the same as
+// UVMPath.
+func (p *Plan9Share) UVMFilePath() string {
+	if p.fileName == "" {
+		return p.uvmPath
+	}
+	return path.Join(p.uvmPath, p.fileName)
 }
 
 const plan9Port = 564
 
-// AddPlan9 adds a Plan9 share to a utility VM.
-func (uvm *UtilityVM) AddPlan9(hostPath string, uvmPath string, readOnly bool, restrict bool, allowedNames []string) (_ *Plan9Share, err error) {
-	op := "uvm::AddPlan9"
+const (
+	// Plan9MinMsize is the smallest 9p message size that can be negotiated.
+	Plan9MinMsize = 4096
+	// Plan9MaxMsize is the largest 9p message size supported by the vsock
+	// transport.
+	Plan9MaxMsize = 512 * 1024
+)
+
+// Plan9 guest cache modes. See the `cache` option of the Linux 9p filesystem.
+const (
+	Plan9CacheModeNone    = "none"
+	Plan9CacheModeLoose   = "loose"
+	Plan9CacheModeFSCache = "fscache"
+	Plan9CacheModeMmap    = "mmap"
+)
+
+// Plan9 security models.
+const (
+	// Plan9SecurityModelMapped stores Linux file metadata (ownership, mode
+	// and special files) on the host alongside the shared files. This is the
+	// default.
+	Plan9SecurityModelMapped = "mapped"
+	// Plan9SecurityModelNone exposes the host files without Linux metadata.
+	// This avoids the metadata lookups on every file access at the cost of
+	// all files being owned by root with a fixed mode.
+	Plan9SecurityModelNone = "none"
+)
+
+// Plan9Options are the performance tunables of a Plan9 share. The zero value
+// uses the defaults.
+type Plan9Options struct {
+	// Msize is the maximum 9p message size in bytes negotiated by the guest.
+	// Larger values reduce the number of round trips for large reads and
+	// writes. If `0` the guest default is used.
+	Msize uint32
+	// CacheMode is the guest cache mode of the share. If empty the guest
+	// default is used.
+	CacheMode string
+	// SecurityModel is the security model of the share. If empty
+	// `Plan9SecurityModelMapped` is used.
+	SecurityModel string
+}
+
+// Validate returns an error if any of the options in `o` are invalid.
+func (o *Plan9Options) Validate() error {
+	if o.Msize != 0 && (o.Msize < Plan9MinMsize || o.Msize > Plan9MaxMsize) {
+		return fmt.Errorf("plan9 msize %d must be between %d and %d", o.Msize, Plan9MinMsize, Plan9MaxMsize)
+	}
+	switch o.CacheMode {
+	case "", Plan9CacheModeNone, Plan9CacheModeLoose, Plan9CacheModeFSCache, Plan9CacheModeMmap:
+	default:
+		return fmt.Errorf("invalid plan9 cache mode '%s'", o.CacheMode)
+	}
+	switch o.SecurityModel {
+	case "", Plan9SecurityModelMapped, Plan9SecurityModelNone:
+	default:
+		return fmt.Errorf("invalid plan9 security model '%s'", o.SecurityModel)
+	}
+	return nil
+}
+
+// AddPlan9 adds a Plan9 share to a utility VM with the default options.
+func (uvm *UtilityVM) AddPlan9(hostPath string, uvmPath string, readOnly bool, restrict bool, allowedNames []string) (*Plan9Share, error) {
+	return uvm.AddPlan9WithOptions(hostPath, uvmPath, readOnly, restrict, allowedNames, nil)
+}
+
+// AddPlan9WithOptions adds a Plan9 share to a utility VM tuned by `options`.
+// If `options` is nil the defaults are used.
+func (uvm *UtilityVM) AddPlan9WithOptions(hostPath string, uvmPath string, readOnly bool, restrict bool, allowedNames []string, options *Plan9Options) (_ *Plan9Share, err error) {
+	op := "uvm::AddPlan9WithOptions"
+	if options == nil {
+		options = &Plan9Options{}
+	}
 	log := logrus.WithFields(logrus.Fields{
 		logfields.UVMID: uvm.id,
 		"host-path":     hostPath,
@@ -29,6 +120,7 @@ func (uvm *UtilityVM) AddPlan9(hostPath string, uvmPath string, readOnly bool, r
 		"readOnly":      readOnly,
 		"restrict":      restrict,
 		"allowedNames":  allowedNames,
+		"options":       fmt.Sprintf("%+v", *options),
 	})
 	log.Debug(op + " - Begin Operation")
 	defer func() {
@@ -49,6 +141,14 @@ func (uvm *UtilityVM) AddPlan9(hostPath string, uvmPath string, readOnly bool, r
 	if uvmPath == "" {
 		return nil, fmt.Errorf("uvmPath must be passed to AddPlan9")
 	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	// The security model is applied by the host, the other options by the
+	// guest when it mounts the share.
+	if (options.Msize != 0 || options.CacheMode != "") && !uvm.Plan9OptionsSupported() {
+		return nil, errors.New("plan9 msize and cache mode are not supported by the guest")
+	}
 
 	// TODO: JTERRY75 - These are marked private in the schema. For now use them
 	// but when there are public variants we need to switch to them.
@@ -62,7 +162,10 @@ func (uvm *UtilityVM) AddPlan9(hostPath string, uvmPath string, readOnly bool, r
 	// TODO: JTERRY75 - `shareFlagsCaseSensitive` only works if the Windows
 	// `hostPath` supports case sensitivity. We need to detect this case before
 	// forwarding this flag in all cases.
-	flags := shareFlagsLinuxMetadata // | shareFlagsCaseSensitive
+	var flags int32 // | shareFlagsCaseSensitive
+	if options.SecurityModel != Plan9SecurityModelNone {
+		flags |= shareFlagsLinuxMetadata
+	}
 	if readOnly {
 		flags |= shareFlagsReadOnly
 	}
@@ -95,6 +198,8 @@ func (uvm *UtilityVM) AddPlan9(hostPath string, uvmPath string, readOnly bool, r
 				ShareName: name,
 				Port:      plan9Port,
 				ReadOnly:  readOnly,
+				Msize:     options.Msize,
+				CacheMode: options.CacheMode,
 			},
 		},
 	}
@@ -107,6 +212,31 @@ func (uvm *UtilityVM) AddPlan9(hostPath string, uvmPath string, readOnly bool, r
 	return share, nil
 }
 
+// AddPlan9File shares the single file at `hostPath` with a utility VM tuned by
+// `options`. Plan9 can only share directories so the parent directory of
+// `hostPath` is shared restricted to just the file and mounted at `uvmPath`.
+// The file is then available in the utility VM at `Plan9Share.UVMFilePath`.
+// If `options` is nil the defaults are used.
+//
+// This is used to mount individual files such as Kubernetes secrets and
+// config maps into a container without exposing the rest of their directory.
+func (uvm *UtilityVM) AddPlan9File(hostPath string, uvmPath string, readOnly bool, options *Plan9Options) (*Plan9Share, error) {
+	fi, err := os.Stat(hostPath)
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		return nil, fmt.Errorf("%s is a directory", hostPath)
+	}
+	dir, name := filepath.Split(hostPath)
+	share, err := uvm.AddPlan9WithOptions(dir, uvmPath, readOnly, true, []string{name}, options)
+	if err != nil {
+		return nil, err
+	}
+	share.fileName = name
+	return share, nil
+}
+
 // RemovePlan9 removes a Plan9 share from a utility VM. Each Plan9 share is ref-counted
 // and only actually removed when the ref-count drops to zero.
 func (uvm *UtilityVM) RemovePlan9(share *Plan9Share) (err error) {
@@ -0,0 +1,70 @@
+package uvm
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/schema1"
+)
+
+func Test_Plan9Options_Validate(t *testing.T) {
+	valid := []Plan9Options{
+		{},
+		{Msize: Plan9MinMsize, CacheMode: Plan9CacheModeLoose},
+		{Msize: Plan9MaxMsize, SecurityModel: Plan9SecurityModelNone},
+	}
+	for _, o := range valid {
+		if err := o.Validate(); err != nil {
+			t.Fatalf("expected nil error for %+v, got: %v", o, err)
+		}
+	}
+	invalid := []Plan9Options{
+		{Msize: Plan9MinMsize - 1},
+		{Msize: Plan9MaxMsize + 1},
+		{CacheMode: "always"},
+		{SecurityModel: "passthrough"},
+	}
+	for _, o := range invalid {
+		if err := o.Validate(); err == nil {
+			t.Fatalf("expected error for %+v", o)
+		}
+	}
+}
+
+func Test_Plan9Share_UVMFilePath(t *testing.T) {
+	dir := &Plan9Share{name: "0", uvmPath: "/run/gcs/c/1/m0"}
+	if p := dir.UVMFilePath(); p != "/run/gcs/c/1/m0" {
+		t.Fatalf("expected directory share path, got: %s", p)
+	}
+	file := &Plan9Share{name: "1", uvmPath: "/run/gcs/c/1/m1", fileName: "token"}
+	if p := file.UVMFilePath(); p != "/run/gcs/c/1/m1/token" {
+		t.Fatalf("expected shared file path, got: %s", p)
+	}
+}
+
+func Test_AddPlan9File_Directory(t *testing.T) {
+	uvm := &UtilityVM{operatingSystem: "linux"}
+	if _, err := uvm.AddPlan9File(os.TempDir(), "/run/gcs/c/1/m0", true, nil); err == nil {
+		t.Fatal("expected error sharing a directory as a file")
+	}
+}
+
+func Test_AddPlan9WithOptions_GuestUnsupported(t *testing.T) {
+	uvm := &UtilityVM{operatingSystem: "linux"}
+	for _, o := range []*Plan9Options{{Msize: Plan9MaxMsize}, {CacheMode: Plan9CacheModeLoose}} {
+		if _, err := uvm.AddPlan9WithOptions(os.TempDir(), "/run/gcs/c/1/m0", true, false, nil, o); err == nil {
+			t.Fatalf("expected error for %+v without guest support", *o)
+		}
+	}
+}
+
+func Test_Plan9OptionsSupported(t *testing.T) {
+	uvm := &UtilityVM{operatingSystem: "linux"}
+	if uvm.Plan9OptionsSupported() {
+		t.Fatal("expected no support without the guest capability")
+	}
+	uvm.guestCaps = schema1.GuestDefinedCapabilities{Plan9OptionsSupported: true}
+	if !uvm.Plan9OptionsSupported() {
+		t.Fatal("expected support with the guest capability")
+	}
+}
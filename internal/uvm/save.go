@@ -0,0 +1,197 @@
+package uvm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/sirupsen/logrus"
+)
+
+// saveTypeToFile saves the state of a compute system to a file that it can be
+// restored from.
+const saveTypeToFile = "ToFile"
+
+// savedStateConfig is the state of a utility VM saved to a file that is needed
+// to restore it but is not part of the saved state of the HCS. It is persisted
+// next to the saved state so that the utility VM can be restored on any host
+// sharing the storage.
+type savedStateConfig struct {
+	// VSMBCounter is the counter the names of the VSMB shares were generated
+	// from.
+	VSMBCounter uint64
+	// VSMBShares are the VSMB shares added to the utility VM after it was
+	// created.
+	VSMBShares []savedVSMBShare
+}
+
+// savedVSMBShare is a VSMB share of a saved utility VM.
+type savedVSMBShare struct {
+	HostPath string
+	Name     string
+	Options  *hcsschema.VirtualSmbShareOptions
+}
+
+// savedStateConfigPath returns the path of the config of the utility VM saved
+// to `path`.
+func savedStateConfigPath(path string) string {
+	return path + ".json"
+}
+
+// loadSavedStateConfig loads the config of the utility VM saved to `path`.
+func loadSavedStateConfig(path string) (*savedStateConfig, error) {
+	b, err := ioutil.ReadFile(savedStateConfigPath(path))
+	if err != nil {
+		return nil, err
+	}
+	var config savedStateConfig
+	if err := json.Unmarshal(b, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse saved state config: %s", err)
+	}
+	return &config, nil
+}
+
+// storeSavedStateConfig persists `config` of the utility VM saved to `path`.
+func storeSavedStateConfig(path string, config *savedStateConfig) error {
+	b, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(savedStateConfigPath(path), b, 0600)
+}
+
+// Save pauses the running utility VM and saves its state to the file `path`.
+// The utility VM is left paused and is expected to be closed afterwards. It is
+// restored by creating it with `OptionsWCOW.SaveStateFilePath` set to `path`
+// and the same options, including the layer folders whose scratch it resumes
+// from, on this or another host sharing the storage. If the utility VM could
+// not be saved it is resumed.
+//
+// Only Windows utility VMs can be saved. The VSMB shares added to the utility
+// VM are saved to `<path>.json` and added to the restored utility VM, so their
+// host paths MUST be reachable from the host restoring it. SCSI disks other
+// than the scratch, vPCI devices and network namespaces are not supported as
+// they are specific to the host, and the utility VM cannot be saved once any
+// of them have been added to it.
+func (uvm *UtilityVM) Save(path string) (err error) {
+	op := "uvm::Save"
+	log := logrus.WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"path":          path,
+	})
+	log.Debug(op + " - Begin Operation")
+	defer func() {
+		if err != nil {
+			log.Data[logrus.ErrorKey] = err
+			log.Error(op + " - End Operation - Error")
+		} else {
+			log.Debug(op + " - End Operation - Success")
+		}
+	}()
+
+	if uvm.operatingSystem != "windows" {
+		return errNotSupported
+	}
+	if path == "" {
+		return errors.New("the path to save the utility VM to must be specified")
+	}
+	if uvm.templateID != "" {
+		return errors.New("a clone cannot be saved")
+	}
+	if uvm.gc != nil {
+		return errors.New("a utility VM with an external guest connection cannot be saved")
+	}
+
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+
+	if uvm.isTemplate {
+		return errors.New("a template cannot be saved")
+	}
+	if err := uvm.checkSaveableL(); err != nil {
+		return err
+	}
+	config := &savedStateConfig{VSMBCounter: uvm.vsmbCounter}
+	for hostPath, share := range uvm.vsmbShares {
+		config.VSMBShares = append(config.VSMBShares, savedVSMBShare{
+			HostPath: hostPath,
+			Name:     share.name,
+			Options:  share.options,
+		})
+	}
+	if err := storeSavedStateConfig(path, config); err != nil {
+		return fmt.Errorf("failed to store saved state config: %s", err)
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(savedStateConfigPath(path))
+		}
+	}()
+	if err := uvm.hcsSystem.Pause(); err != nil {
+		return err
+	}
+	if err := uvm.hcsSystem.Save(hcsschema.SaveOptions{
+		SaveType:          saveTypeToFile,
+		SaveStateFilePath: path,
+	}); err != nil {
+		// The utility VM is still usable if it was not saved.
+		if rerr := uvm.hcsSystem.Resume(); rerr != nil {
+			log.WithError(rerr).Warning("failed to resume utility VM")
+		}
+		return err
+	}
+	return nil
+}
+
+// checkSaveableL returns an error if devices or network namespaces that cannot
+// be restored have been added to the Windows utility VM since it was created.
+// It is the callers responsibility to hold `uvm.m`.
+func (uvm *UtilityVM) checkSaveableL() error {
+	for i := range uvm.scsiLocations {
+		for j := range uvm.scsiLocations[i] {
+			// The scratch is attached to SCSI 0:0 on create.
+			if i == 0 && j == 0 {
+				continue
+			}
+			if uvm.scsiLocations[i][j].hostPath != "" {
+				return errors.New("a utility VM with added SCSI disks cannot be saved")
+			}
+		}
+	}
+	if len(uvm.vpciDevices) > 0 {
+		return errors.New("a utility VM with assigned devices cannot be saved")
+	}
+	if len(uvm.namespaces) > 0 {
+		return errors.New("a utility VM with network namespaces cannot be saved as their endpoints are specific to the host")
+	}
+	return nil
+}
+
+// restoreVSMBShares adds the VSMB shares of the utility VM saved to `path` to
+// `doc` and records them as added to the utility VM. The shares are referenced
+// by the containers of the restored guest so they are not removed until the
+// utility VM is closed.
+func (uvm *UtilityVM) restoreVSMBShares(path string, doc *hcsschema.ComputeSystem) error {
+	config, err := loadSavedStateConfig(path)
+	if err != nil {
+		return fmt.Errorf("failed to load saved state config: %s", err)
+	}
+	uvm.vsmbCounter = config.VSMBCounter
+	for _, share := range config.VSMBShares {
+		doc.VirtualMachine.Devices.VirtualSmb.Shares = append(doc.VirtualMachine.Devices.VirtualSmb.Shares, hcsschema.VirtualSmbShare{
+			Name:    share.Name,
+			Path:    share.HostPath,
+			Options: share.Options,
+		})
+		uvm.vsmbShares[share.HostPath] = &vsmbShare{
+			refCount: 1,
+			name:     share.Name,
+			options:  share.Options,
+		}
+	}
+	return nil
+}
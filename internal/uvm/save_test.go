@@ -0,0 +1,140 @@
+package uvm
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/cow/cowtest"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+)
+
+func newSaveTestPath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "uvmsave")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, "uvm.vmrs"), func() { os.RemoveAll(dir) }
+}
+
+func Test_Save(t *testing.T) {
+	path, cleanup := newSaveTestPath(t)
+	defer cleanup()
+
+	vm := &UtilityVM{operatingSystem: "windows"}
+	vm.scsiLocations[0][0].hostPath = `c:\scratch\sandbox.vhdx`
+	c := cowtest.NewContainer("uvm", vm.operatingSystem, false)
+	vm.hcsSystem = c
+
+	if err := vm.Save(path); err != nil {
+		t.Fatalf("failed to save: %s", err)
+	}
+	if !c.Paused() {
+		t.Fatal("expected the utility VM to be paused")
+	}
+	saves := c.Saves()
+	if len(saves) != 1 {
+		t.Fatalf("expected 1 save, got %d", len(saves))
+	}
+	options := saves[0].(hcsschema.SaveOptions)
+	if options.SaveType != saveTypeToFile || options.SaveStateFilePath != path {
+		t.Fatalf("unexpected save options: %+v", options)
+	}
+}
+
+func Test_Save_VSMB_Restored(t *testing.T) {
+	path, cleanup := newSaveTestPath(t)
+	defer cleanup()
+
+	options := DefaultVSMBOptions(true)
+	vm := &UtilityVM{
+		operatingSystem: "windows",
+		hcsSystem:       cowtest.NewContainer("uvm", "windows", false),
+		vsmbCounter:     2,
+		vsmbShares: map[string]*vsmbShare{
+			`c:\layer`: {refCount: 2, name: "s2", options: options},
+		},
+	}
+	if err := vm.Save(path); err != nil {
+		t.Fatalf("failed to save: %s", err)
+	}
+
+	restored := &UtilityVM{operatingSystem: "windows", vsmbShares: make(map[string]*vsmbShare)}
+	doc := &hcsschema.ComputeSystem{
+		VirtualMachine: &hcsschema.VirtualMachine{
+			Devices: &hcsschema.Devices{
+				VirtualSmb: &hcsschema.VirtualSmb{
+					Shares: []hcsschema.VirtualSmbShare{{Name: "os"}},
+				},
+			},
+		},
+	}
+	if err := restored.restoreVSMBShares(path, doc); err != nil {
+		t.Fatalf("failed to restore VSMB shares: %s", err)
+	}
+	shares := doc.VirtualMachine.Devices.VirtualSmb.Shares
+	if len(shares) != 2 || shares[1].Name != "s2" || shares[1].Path != `c:\layer` || !shares[1].Options.ReadOnly {
+		t.Fatalf("unexpected restored shares: %+v", shares)
+	}
+	if restored.vsmbCounter != 2 {
+		t.Fatalf("expected VSMB counter 2, got %d", restored.vsmbCounter)
+	}
+	share, ok := restored.vsmbShares[`c:\layer`]
+	if !ok || share.name != "s2" || share.refCount != 1 {
+		t.Fatalf("unexpected restored share: %+v", share)
+	}
+}
+
+func Test_Save_SaveFailed_Resumes(t *testing.T) {
+	path, cleanup := newSaveTestPath(t)
+	defer cleanup()
+
+	c := cowtest.NewContainer("uvm", "windows", false)
+	c.OnSave = func(*cowtest.Container, interface{}) error {
+		return errors.New("save failed")
+	}
+	vm := &UtilityVM{operatingSystem: "windows", hcsSystem: c}
+	if err := vm.Save(path); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(c.Saves()) != 1 {
+		t.Fatalf("expected 1 save, got %d", len(c.Saves()))
+	}
+	if c.Paused() {
+		t.Fatal("expected the utility VM to be resumed")
+	}
+	if _, err := os.Stat(savedStateConfigPath(path)); !os.IsNotExist(err) {
+		t.Fatalf("expected the saved state config to be removed: %v", err)
+	}
+}
+
+func Test_Save_Invalid(t *testing.T) {
+	withSCSI := &UtilityVM{operatingSystem: "windows"}
+	withSCSI.scsiLocations[0][1].hostPath = `c:\disk.vhdx`
+
+	tests := []struct {
+		name string
+		uvm  *UtilityVM
+		path string
+	}{
+		{name: "LCOW", uvm: &UtilityVM{operatingSystem: "linux"}, path: "uvm.vmrs"},
+		{name: "NoPath", uvm: &UtilityVM{operatingSystem: "windows"}},
+		{name: "Clone", uvm: &UtilityVM{operatingSystem: "windows", templateID: "template"}, path: "uvm.vmrs"},
+		{name: "Template", uvm: &UtilityVM{operatingSystem: "windows", isTemplate: true}, path: "uvm.vmrs"},
+		{name: "SCSI", uvm: withSCSI, path: "uvm.vmrs"},
+		{name: "VPCI", uvm: &UtilityVM{operatingSystem: "windows", vpciDevices: map[string]*vpciDevice{"device": {}}}, path: "uvm.vmrs"},
+		{name: "Network", uvm: &UtilityVM{operatingSystem: "windows", namespaces: map[string]*namespaceInfo{"ns": {}}}, path: "uvm.vmrs"},
+	}
+	for _, test := range tests {
+		c := cowtest.NewContainer(test.name, test.uvm.operatingSystem, false)
+		test.uvm.hcsSystem = c
+		if err := test.uvm.Save(test.path); err == nil {
+			t.Fatalf("%s: expected error", test.name)
+		}
+		if c.Paused() || len(c.Saves()) != 0 {
+			t.Fatalf("%s: expected the utility VM not to be paused or saved", test.name)
+		}
+	}
+}
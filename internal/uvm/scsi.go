@@ -2,6 +2,8 @@ package uvm
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/Microsoft/hcsshim/internal/guestrequest"
 	"github.com/Microsoft/hcsshim/internal/logfields"
@@ -20,12 +22,136 @@ var (
 	ErrSCSILayerWCOWUnsupported = fmt.Errorf("SCSI attached layers are not supported for WCOW")
 )
 
+// SCSIAttachment describes a disk attached to a SCSI location of a utility
+// VM.
+type SCSIAttachment struct {
+	Controller int
+	LUN        int32
+	HostPath   string
+	// UVMPath is the path the disk is mounted at in the utility VM. It is ""
+	// for attach-only disks.
+	UVMPath string
+	// IsLayer is `true` if the disk is a shared read-only LCOW layer. RefCount
+	// is the number of containers using it.
+	IsLayer  bool
+	RefCount uint32
+	// Owner is `SCSIOptions.Owner` of the attachment. It is "" for shared
+	// layers and disks attached without one.
+	Owner string
+}
+
+// SCSIExhaustedError is returned when a disk cannot be attached because every
+// SCSI location of the utility VM is in use. Its cause is
+// `ErrNoAvailableLocation`.
+type SCSIExhaustedError struct {
+	// Attachments are the disks attached at the time of the failure.
+	Attachments []SCSIAttachment
+}
+
+func (e *SCSIExhaustedError) Error() string {
+	attachments := make([]string, len(e.Attachments))
+	for i, a := range e.Attachments {
+		attachments[i] = fmt.Sprintf("%d:%d=%s", a.Controller, a.LUN, a.HostPath)
+		if a.UVMPath != "" {
+			attachments[i] += "->" + a.UVMPath
+		}
+		if a.Owner != "" {
+			attachments[i] += " (owner " + a.Owner + ")"
+		}
+		if a.IsLayer {
+			attachments[i] += fmt.Sprintf(" (layer, %d refs)", a.RefCount)
+		}
+	}
+	return fmt.Sprintf("%s: all %d SCSI locations in use: %s", ErrNoAvailableLocation, len(e.Attachments), strings.Join(attachments, ", "))
+}
+
+// Cause returns `ErrNoAvailableLocation`.
+func (e *SCSIExhaustedError) Cause() error {
+	return ErrNoAvailableLocation
+}
+
+// Unwrap returns `ErrNoAvailableLocation`.
+func (e *SCSIExhaustedError) Unwrap() error {
+	return ErrNoAvailableLocation
+}
+
+// SCSIAttachments returns the disks attached to the SCSI controllers of the
+// utility VM in controller and LUN order.
+func (uvm *UtilityVM) SCSIAttachments() []SCSIAttachment {
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+	return uvm.scsiAttachments()
+}
+
+// AvailableSCSILocations returns the number of SCSI locations of the utility
+// VM that are free to attach a disk mapped into the guest to, so that callers
+// can fail fast or fall back to another means of sharing storage when none are
+// left.
+func (uvm *UtilityVM) AvailableSCSILocations() int {
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+	available := 0
+	for controller := 0; controller < uvm.guestSCSIControllerCount(true) && controller < len(uvm.scsiLocations); controller++ {
+		for _, si := range uvm.scsiLocations[controller] {
+			if si.hostPath == "" {
+				available++
+			}
+		}
+	}
+	return available
+}
+
+// guestSCSIControllerCount returns the number of SCSI controllers of the
+// utility VM that can hold disks used by the guest, which are mapped into it if
+// `mapped`. The WCOW guest identifies a mapped disk by its LUN alone and a
+// Linux guest that does not advertise `MultiSCSIControllerSupported` only
+// scans the first controller, so they can only use the first controller.
+func (uvm *UtilityVM) guestSCSIControllerCount(mapped bool) int {
+	count := int(uvm.scsiControllerCount)
+	if count > 1 {
+		switch {
+		case uvm.operatingSystem == "windows" && mapped:
+			return 1
+		case uvm.operatingSystem == "linux" && !uvm.MultiSCSIControllerSupported():
+			return 1
+		}
+	}
+	return count
+}
+
+// scsiAttachments is the implementation of `SCSIAttachments`. Lock must be held
+// when calling this function.
+func (uvm *UtilityVM) scsiAttachments() []SCSIAttachment {
+	var attachments []SCSIAttachment
+	for controller, luns := range uvm.scsiLocations {
+		for lun, si := range luns {
+			if si.hostPath != "" {
+				attachments = append(attachments, SCSIAttachment{
+					Controller: controller,
+					LUN:        int32(lun),
+					HostPath:   si.hostPath,
+					UVMPath:    si.uvmPath,
+					IsLayer:    si.isLayer,
+					RefCount:   si.refCount,
+					Owner:      si.owner,
+				})
+			}
+		}
+	}
+	return attachments
+}
+
 // allocateSCSI finds the next available slot on the
 // SCSI controllers associated with a utility VM to use.
 // Lock must be held when calling this function
+//
+// The lowest free LUN on the lowest controller is always allocated so that a
+// location freed by `deallocateSCSI` is the next one reused. Only the
+// controllers configured for the utility VM are considered. If every location
+// is in use returns a `*SCSIExhaustedError`.
 func (uvm *UtilityVM) allocateSCSI(hostPath string, uvmPath string, isLayer bool) (int, int32, error) {
-	for controller, luns := range uvm.scsiLocations {
-		for lun, si := range luns {
+	for controller := 0; controller < int(uvm.scsiControllerCount) && controller < len(uvm.scsiLocations); controller++ {
+		for lun, si := range uvm.scsiLocations[controller] {
 			if si.hostPath == "" {
 				uvm.scsiLocations[controller][lun].hostPath = hostPath
 				uvm.scsiLocations[controller][lun].uvmPath = uvmPath
@@ -47,7 +173,7 @@ func (uvm *UtilityVM) allocateSCSI(hostPath string, uvmPath string, isLayer bool
 			}
 		}
 	}
-	return -1, -1, ErrNoAvailableLocation
+	return -1, -1, &SCSIExhaustedError{Attachments: uvm.scsiAttachments()}
 }
 
 func (uvm *UtilityVM) deallocateSCSI(controller int, lun int32) {
@@ -89,6 +215,72 @@ func (uvm *UtilityVM) findSCSIAttachment(findThisHostPath string) (int, int32, s
 	return -1, -1, "", ErrNotAttached
 }
 
+// SCSIOptions are the options used to attach a disk to a utility VM over SCSI.
+// The zero value attaches the disk read-write without mounting it in the
+// guest.
+type SCSIOptions struct {
+	// UVMPath is the path the disk is mounted at in the utility VM. If empty
+	// the disk is only attached, for example so that it can be formatted by
+	// the caller.
+	UVMPath string
+	// ReadOnly attaches the disk read only.
+	ReadOnly bool
+	// Filesystem is the type of the filesystem on the disk such as `ext4` or
+	// `xfs`. If empty the guest default is used. LCOW only.
+	Filesystem string
+	// MountOptions are additional options such as `noatime` to mount the disk
+	// with in the guest. LCOW only.
+	MountOptions []string
+	// QoS limits the IOPS and bandwidth of the attachment so that a single
+	// disk cannot saturate the storage of the host. If `nil` the attachment is
+	// unlimited.
+	QoS *hcsschema.StorageQoS
+	// BlockDev exposes the unformatted disk as a raw block device at
+	// `UVMPath` rather than mounting its filesystem. LCOW only and the guest
+	// MUST support it.
+	BlockDev bool
+	// Owner identifies who the disk is attached for, such as the ID of a
+	// container, in `SCSIAttachments` and `*SCSIExhaustedError`. It is
+	// ignored for a disk that is already attached.
+	Owner string
+	// Encrypted sets up dm-crypt on the disk with a key generated by the guest
+	// for this boot and formats it before mounting it at `UVMPath`, so its
+	// existing contents are lost. LCOW only and the guest MUST support it.
+	Encrypted bool
+
+	// verityInfo is sent to the guest to verify a read-only layer with, if
+	// not `nil`.
+	verityInfo *guestrequest.DeviceVerityInfo
+}
+
+// Validate returns an error if `o` cannot be used to attach a disk.
+func (o *SCSIOptions) Validate() error {
+	if o.UVMPath == "" && (o.Filesystem != "" || len(o.MountOptions) > 0 || o.BlockDev) {
+		return fmt.Errorf("scsi filesystem, mount and block device options require a utility VM path")
+	}
+	if o.BlockDev && (o.Filesystem != "" || len(o.MountOptions) > 0) {
+		return fmt.Errorf("scsi filesystem and mount options cannot be used with a block device")
+	}
+	if o.Encrypted && (o.UVMPath == "" || o.ReadOnly || o.BlockDev) {
+		return fmt.Errorf("scsi encryption requires a writable disk mounted at a utility VM path")
+	}
+	if o.Encrypted {
+		for _, opt := range o.MountOptions {
+			// dm-crypt does not pass discards through to the disk.
+			if opt == "discard" {
+				return fmt.Errorf("scsi encryption cannot be used with the discard mount option")
+			}
+		}
+	}
+	return nil
+}
+
+// PhysicalDiskPath returns the host path of the physical disk `diskNumber`
+// for use with AddSCSIPhysicalDisk.
+func PhysicalDiskPath(diskNumber uint32) string {
+	return fmt.Sprintf(`\\.\PHYSICALDRIVE%d`, diskNumber)
+}
+
 // AddSCSI adds a SCSI disk to a utility VM at the next available location. This
 // function should be called for a RW/scratch layer or a passthrough vhd/vhdx.
 // For read-only layers on LCOW as an alternate to PMEM for large layers, use
@@ -96,16 +288,17 @@ func (uvm *UtilityVM) findSCSIAttachment(findThisHostPath string) (int, int32, s
 //
 // `hostPath` is required and must point to a vhd/vhdx path.
 //
-// `uvmPath` is optional.
-//
-// `readOnly` set to `true` if the vhd/vhdx should be attached read only.
-func (uvm *UtilityVM) AddSCSI(hostPath string, uvmPath string, readOnly bool) (_ int, _ int32, err error) {
+// `options` are optional. If `nil` the disk is attached read-write without a
+// guest mount.
+func (uvm *UtilityVM) AddSCSI(hostPath string, options *SCSIOptions) (_ int, _ int32, err error) {
 	op := "uvm::AddSCSI"
+	if options == nil {
+		options = &SCSIOptions{}
+	}
 	log := logrus.WithFields(logrus.Fields{
 		logfields.UVMID: uvm.id,
 		"host-path":     hostPath,
-		"uvm-path":      uvmPath,
-		"readOnly":      readOnly,
+		"options":       fmt.Sprintf("%+v", options),
 	})
 	log.Debug(op + " - Begin Operation")
 	defer func() {
@@ -117,7 +310,7 @@ func (uvm *UtilityVM) AddSCSI(hostPath string, uvmPath string, readOnly bool) (_
 		}
 	}()
 
-	return uvm.addSCSIActual(hostPath, uvmPath, "VirtualDisk", false, readOnly)
+	return uvm.addSCSIActual(hostPath, "VirtualDisk", false, options)
 }
 
 // AddSCSIPhysicalDisk attaches a physical disk from the host directly to the
@@ -125,16 +318,17 @@ func (uvm *UtilityVM) AddSCSI(hostPath string, uvmPath string, readOnly bool) (_
 //
 // `hostPath` is required and `likely` start's with `\\.\PHYSICALDRIVE`.
 //
-// `uvmPath` is optional if a guest mount is not requested.
-//
-// `readOnly` set to `true` if the physical disk should be attached read only.
-func (uvm *UtilityVM) AddSCSIPhysicalDisk(hostPath, uvmPath string, readOnly bool) (_ int, _ int32, err error) {
+// `options` are optional. If `nil` the disk is attached read-write without a
+// guest mount.
+func (uvm *UtilityVM) AddSCSIPhysicalDisk(hostPath string, options *SCSIOptions) (_ int, _ int32, err error) {
 	op := "uvm::AddSCSIPhysicalDisk"
+	if options == nil {
+		options = &SCSIOptions{}
+	}
 	log := logrus.WithFields(logrus.Fields{
 		logfields.UVMID: uvm.id,
 		"host-path":     hostPath,
-		"uvm-path":      uvmPath,
-		"readOnly":      readOnly,
+		"options":       fmt.Sprintf("%+v", options),
 	})
 	log.Debug(op + " - Begin Operation")
 	defer func() {
@@ -146,7 +340,40 @@ func (uvm *UtilityVM) AddSCSIPhysicalDisk(hostPath, uvmPath string, readOnly boo
 		}
 	}()
 
-	return uvm.addSCSIActual(hostPath, uvmPath, "PassThru", false, readOnly)
+	return uvm.addSCSIActual(hostPath, "PassThru", false, options)
+}
+
+// AddSCSIVHDSet attaches the shared VHD set (.vhds) at `hostPath` to the
+// utility VM at the next available location. Unlike a vhd/vhdx a VHD set may
+// be attached to several utility VMs at the same time, for example by the
+// members of a guest cluster.
+//
+// `options` are optional. If `nil` the disk is attached read-write without a
+// guest mount.
+func (uvm *UtilityVM) AddSCSIVHDSet(hostPath string, options *SCSIOptions) (_ int, _ int32, err error) {
+	op := "uvm::AddSCSIVHDSet"
+	if options == nil {
+		options = &SCSIOptions{}
+	}
+	log := logrus.WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"host-path":     hostPath,
+		"options":       fmt.Sprintf("%+v", options),
+	})
+	log.Debug(op + " - Begin Operation")
+	defer func() {
+		if err != nil {
+			log.Data[logrus.ErrorKey] = err
+			log.Error(op + " - End Operation - Error")
+		} else {
+			log.Debug(op + " - End Operation - Success")
+		}
+	}()
+
+	if !strings.EqualFold(filepath.Ext(hostPath), ".vhds") {
+		return -1, -1, fmt.Errorf("%s is not a VHD set", hostPath)
+	}
+	return uvm.addSCSIActual(hostPath, "VirtualDisk", false, options)
 }
 
 // AddSCSILayer adds a read-only layer disk to a utility VM at the next available
@@ -172,33 +399,52 @@ func (uvm *UtilityVM) AddSCSILayer(hostPath string) (_ int, _ int32, err error)
 		return -1, -1, ErrSCSILayerWCOWUnsupported
 	}
 
-	return uvm.addSCSIActual(hostPath, "", "VirtualDisk", true, true)
+	verityInfo, err := uvm.layerVerityInfo(hostPath)
+	if err != nil {
+		return -1, -1, err
+	}
+	return uvm.addSCSIActual(hostPath, "VirtualDisk", true, &SCSIOptions{ReadOnly: true, verityInfo: verityInfo})
 }
 
-// addSCSIActual is the implementation behind the external functions AddSCSI and
-// AddSCSILayer.
+// addSCSIActual is the implementation behind the external functions AddSCSI,
+// AddSCSIPhysicalDisk and AddSCSILayer.
 //
 // We are in control of everything ourselves. Hence we have ref- counting and
 // so-on tracking what SCSI locations are available or used.
 //
 // `hostPath` is required and may be a vhd/vhdx or physical disk path.
 //
-// `uvmPath` is optional, and `must` be empty for layers. If `!isLayer` and
-// `uvmPath` is empty no guest modify will take place.
-//
 // `attachmentType` is required and `must` be `VirtualDisk` for vhd/vhdx
 // attachments and `PassThru` for physical disk.
 //
 // `isLayer` indicates that this is a read-only (LCOW) layer VHD. This parameter
 // `must not` be used for Windows.
 //
-// `readOnly` indicates the attachment should be added read only.
+// `options` is required. `options.UVMPath` `must` be empty for layers. If
+// `!isLayer` and `options.UVMPath` is empty no guest modify will take place.
 //
 // Returns the controller ID (0..3) and LUN (0..63) where the disk is attached.
-func (uvm *UtilityVM) addSCSIActual(hostPath, uvmPath, attachmentType string, isLayer, readOnly bool) (int, int32, error) {
+func (uvm *UtilityVM) addSCSIActual(hostPath, attachmentType string, isLayer bool, options *SCSIOptions) (int, int32, error) {
 	if uvm.scsiControllerCount == 0 {
 		return -1, -1, ErrNoSCSIControllers
 	}
+	if err := options.Validate(); err != nil {
+		return -1, -1, err
+	}
+	if uvm.operatingSystem == "windows" && (options.Filesystem != "" || len(options.MountOptions) > 0 || options.BlockDev || options.Encrypted) {
+		return -1, -1, fmt.Errorf("scsi filesystem, mount, block device and encryption options are not supported for WCOW: %s", errNotSupported)
+	}
+	if (options.Filesystem != "" || len(options.MountOptions) > 0) && !uvm.SCSIMountOptionsSupported() {
+		return -1, -1, fmt.Errorf("scsi filesystem and mount options are not supported by the guest: %s", errNotSupported)
+	}
+	if options.BlockDev && !uvm.BlockDeviceSupported() {
+		return -1, -1, fmt.Errorf("scsi block devices are not supported by the guest: %s", errNotSupported)
+	}
+	if options.Encrypted && !uvm.EncryptedScratchSupported() {
+		return -1, -1, fmt.Errorf("scsi encryption is not supported by the guest: %s", errNotSupported)
+	}
+	uvmPath := options.UVMPath
+	readOnly := options.ReadOnly
 
 	// Ensure the utility VM has access
 	if !isLayer {
@@ -234,6 +480,11 @@ func (uvm *UtilityVM) addSCSIActual(hostPath, uvmPath, attachmentType string, is
 		return -1, -1, err
 	}
 
+	uvm.scsiLocations[controller][lun].attachmentType = attachmentType
+	uvm.scsiLocations[controller][lun].readOnly = readOnly
+	uvm.scsiLocations[controller][lun].encrypted = options.Encrypted
+	uvm.scsiLocations[controller][lun].owner = options.Owner
+
 	// Auto-generate the UVM path for LCOW layers
 	if isLayer {
 		uvmPath = fmt.Sprintf("/tmp/S%d/%d", controller, lun)
@@ -242,8 +493,7 @@ func (uvm *UtilityVM) addSCSIActual(hostPath, uvmPath, attachmentType string, is
 	// See comment higher up. Now safe to release the lock.
 	uvm.m.Unlock()
 
-	// Note: Can remove this check post-RS5 if multiple controllers are supported
-	if controller > 0 {
+	if controller >= uvm.guestSCSIControllerCount(uvmPath != "") {
 		uvm.deallocateSCSI(controller, lun)
 		return -1, -1, ErrTooManyAttachments
 	}
@@ -251,9 +501,10 @@ func (uvm *UtilityVM) addSCSIActual(hostPath, uvmPath, attachmentType string, is
 	SCSIModification := &hcsschema.ModifySettingRequest{
 		RequestType: requesttype.Add,
 		Settings: hcsschema.Attachment{
-			Path:     hostPath,
-			Type_:    attachmentType,
-			ReadOnly: readOnly,
+			Path:       hostPath,
+			Type_:      attachmentType,
+			ReadOnly:   readOnly,
+			StorageQoS: options.QoS,
 		},
 		ResourcePath: fmt.Sprintf("VirtualMachine/Devices/Scsi/%d/Attachments/%d", controller, lun),
 	}
@@ -277,6 +528,11 @@ func (uvm *UtilityVM) addSCSIActual(hostPath, uvmPath, attachmentType string, is
 					Lun:        uint8(lun),
 					Controller: uint8(controller),
 					ReadOnly:   readOnly,
+					Filesystem: options.Filesystem,
+					Options:    options.MountOptions,
+					BlockDev:   options.BlockDev,
+					Encrypted:  options.Encrypted,
+					VerityInfo: options.verityInfo,
 				},
 			}
 		}
@@ -366,6 +622,7 @@ func (uvm *UtilityVM) removeSCSI(hostPath string, uvmPath string, controller int
 				MountPath:  uvmPath, // May be blank in attach-only
 				Lun:        uint8(lun),
 				Controller: uint8(controller),
+				Encrypted:  uvm.scsiLocations[controller][lun].encrypted,
 			},
 		}
 	}
@@ -377,6 +634,46 @@ func (uvm *UtilityVM) removeSCSI(hostPath string, uvmPath string, controller int
 	return nil
 }
 
+// UpdateSCSIQoS updates the IOPS and bandwidth limits of the SCSI disk
+// attached at `hostPath` to `qos`. A zero value in `qos` removes that limit.
+//
+// If `hostPath` is not attached returns `ErrNotAttached`.
+func (uvm *UtilityVM) UpdateSCSIQoS(hostPath string, qos hcsschema.StorageQoS) (err error) {
+	op := "uvm::UpdateSCSIQoS"
+	log := logrus.WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"host-path":     hostPath,
+		"qos":           fmt.Sprintf("%+v", qos),
+	})
+	log.Debug(op + " - Begin Operation")
+	defer func() {
+		if err != nil {
+			log.Data[logrus.ErrorKey] = err
+			log.Error(op + " - End Operation - Error")
+		} else {
+			log.Debug(op + " - End Operation - Success")
+		}
+	}()
+
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+	controller, lun, _, err := uvm.findSCSIAttachment(hostPath)
+	if err != nil {
+		return err
+	}
+	si := uvm.scsiLocations[controller][lun]
+	return uvm.Modify(&hcsschema.ModifySettingRequest{
+		RequestType: requesttype.Update,
+		Settings: hcsschema.Attachment{
+			Path:       hostPath,
+			Type_:      si.attachmentType,
+			ReadOnly:   si.readOnly,
+			StorageQoS: &qos,
+		},
+		ResourcePath: fmt.Sprintf("VirtualMachine/Devices/Scsi/%d/Attachments/%d", controller, lun),
+	})
+}
+
 // GetScsiUvmPath returns the guest mounted path of a SCSI drive.
 //
 // If `hostPath` is not mounted returns `ErrNotAttached`.
@@ -0,0 +1,225 @@
+package uvm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/cow/cowtest"
+	"github.com/Microsoft/hcsshim/internal/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/schema1"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/pkg/errors"
+)
+
+func Test_allocateSCSI_Exhausted(t *testing.T) {
+	uvm := &UtilityVM{scsiControllerCount: 1}
+	for i := 0; i < len(uvm.scsiLocations[0]); i++ {
+		controller, lun, err := uvm.allocateSCSI(fmt.Sprintf(`C:\disk%d.vhdx`, i), "", false)
+		if err != nil {
+			t.Fatalf("expected nil error allocating LUN %d, got: %v", i, err)
+		}
+		if controller != 0 || lun != int32(i) {
+			t.Fatalf("expected location 0:%d, got: %d:%d", i, controller, lun)
+		}
+	}
+	uvm.scsiLocations[0][5].owner = "container5"
+	_, _, err := uvm.allocateSCSI(`C:\extra.vhdx`, "", false)
+	e, ok := err.(*SCSIExhaustedError)
+	if !ok {
+		t.Fatalf("expected *SCSIExhaustedError, got: %v", err)
+	}
+	if errors.Cause(err) != ErrNoAvailableLocation {
+		t.Fatalf("expected cause: %v, got: %v", ErrNoAvailableLocation, errors.Cause(err))
+	}
+	if len(e.Attachments) != len(uvm.scsiLocations[0]) || e.Attachments[5].HostPath != `C:\disk5.vhdx` || e.Attachments[5].Owner != "container5" {
+		t.Fatalf("expected every attachment to be listed with its owner, got: %+v", e.Attachments)
+	}
+	if !strings.Contains(err.Error(), `0:5=C:\disk5.vhdx (owner container5)`) {
+		t.Fatalf("expected the owner in the error, got: %v", err)
+	}
+}
+
+func Test_allocateSCSI_ReusesLowestFree(t *testing.T) {
+	uvm := &UtilityVM{scsiControllerCount: 1}
+	for i := 0; i < 4; i++ {
+		if _, _, err := uvm.allocateSCSI(fmt.Sprintf(`C:\disk%d.vhdx`, i), "", false); err != nil {
+			t.Fatalf("expected nil error, got: %v", err)
+		}
+	}
+	uvm.deallocateSCSI(0, 2)
+	uvm.deallocateSCSI(0, 1)
+	for _, expected := range []int32{1, 2, 4} {
+		_, lun, err := uvm.allocateSCSI(fmt.Sprintf(`C:\new%d.vhdx`, expected), "", false)
+		if err != nil {
+			t.Fatalf("expected nil error, got: %v", err)
+		}
+		if lun != expected {
+			t.Fatalf("expected LUN %d, got: %d", expected, lun)
+		}
+	}
+	if n := len(uvm.SCSIAttachments()); n != 5 {
+		t.Fatalf("expected 5 attachments, got: %d", n)
+	}
+}
+
+func Test_allocateSCSI_MultipleControllers(t *testing.T) {
+	uvm := &UtilityVM{scsiControllerCount: 2}
+	for i := 0; i < SCSILUNsPerController; i++ {
+		if _, _, err := uvm.allocateSCSI(fmt.Sprintf(`C:\disk%d.vhdx`, i), "", false); err != nil {
+			t.Fatalf("expected nil error, got: %v", err)
+		}
+	}
+	controller, lun, err := uvm.allocateSCSI(`C:\extra.vhdx`, "", false)
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if controller != 1 || lun != 0 {
+		t.Fatalf("expected location 1:0 once the first controller is full, got: %d:%d", controller, lun)
+	}
+}
+
+func Test_AvailableSCSILocations(t *testing.T) {
+	uvm := &UtilityVM{
+		operatingSystem:     "linux",
+		scsiControllerCount: 2,
+		guestCaps:           schema1.GuestDefinedCapabilities{MultiSCSIControllerSupported: true},
+	}
+	if available := uvm.AvailableSCSILocations(); available != 2*SCSILUNsPerController {
+		t.Fatalf("expected %d available locations, got: %d", 2*SCSILUNsPerController, available)
+	}
+	for i := 0; i < 3; i++ {
+		if _, _, err := uvm.allocateSCSI(fmt.Sprintf(`C:\disk%d.vhdx`, i), "", false); err != nil {
+			t.Fatalf("expected nil error, got: %v", err)
+		}
+	}
+	uvm.deallocateSCSI(0, 1)
+	if available := uvm.AvailableSCSILocations(); available != 2*SCSILUNsPerController-2 {
+		t.Fatalf("expected %d available locations, got: %d", 2*SCSILUNsPerController-2, available)
+	}
+	if available := (&UtilityVM{}).AvailableSCSILocations(); available != 0 {
+		t.Fatalf("expected no available locations without controllers, got: %d", available)
+	}
+}
+
+func Test_AvailableSCSILocations_FirstControllerOnly(t *testing.T) {
+	for _, uvm := range []*UtilityVM{
+		{operatingSystem: "windows", scsiControllerCount: 2},
+		{operatingSystem: "linux", scsiControllerCount: 2},
+	} {
+		if available := uvm.AvailableSCSILocations(); available != SCSILUNsPerController {
+			t.Fatalf("%s: expected %d available locations, got: %d", uvm.operatingSystem, SCSILUNsPerController, available)
+		}
+	}
+}
+
+func Test_AddSCSILayer_SecondControllerNotSupported(t *testing.T) {
+	uvm := &UtilityVM{operatingSystem: "linux", scsiControllerCount: 2}
+	for lun := range uvm.scsiLocations[0] {
+		uvm.scsiLocations[0][lun].hostPath = fmt.Sprintf(`C:\disk%d.vhdx`, lun)
+	}
+	if _, _, err := uvm.AddSCSILayer(`C:\layer.vhdx`); err != ErrTooManyAttachments {
+		t.Fatalf("expected error: %v, got: %v", ErrTooManyAttachments, err)
+	}
+	if si := uvm.scsiLocations[1][0]; si.hostPath != "" {
+		t.Fatalf("expected the second controller to be released, got: %+v", si)
+	}
+}
+
+func Test_SCSIOptions_Validate(t *testing.T) {
+	for _, o := range []SCSIOptions{
+		{},
+		{ReadOnly: true},
+		{UVMPath: "/run/scratch", Filesystem: "xfs", MountOptions: []string{"noatime"}},
+		{UVMPath: "/run/disk", BlockDev: true},
+		{UVMPath: "/run/scratch", Encrypted: true},
+	} {
+		if err := o.Validate(); err != nil {
+			t.Fatalf("expected nil error for %+v, got: %v", o, err)
+		}
+	}
+	for _, o := range []SCSIOptions{
+		{Filesystem: "xfs"},
+		{MountOptions: []string{"noatime"}},
+		{BlockDev: true},
+	} {
+		if err := o.Validate(); err == nil {
+			t.Fatalf("expected error for %+v without a utility VM path", o)
+		}
+	}
+	o := SCSIOptions{UVMPath: "/run/disk", BlockDev: true, Filesystem: "ext4"}
+	if err := o.Validate(); err == nil {
+		t.Fatal("expected error for a filesystem on a block device")
+	}
+	for _, o := range []SCSIOptions{
+		{Encrypted: true},
+		{UVMPath: "/run/scratch", Encrypted: true, ReadOnly: true},
+		{UVMPath: "/run/disk", Encrypted: true, BlockDev: true},
+		{UVMPath: "/run/scratch", Encrypted: true, MountOptions: []string{"discard"}},
+	} {
+		if err := o.Validate(); err == nil {
+			t.Fatalf("expected error for encrypted %+v", o)
+		}
+	}
+}
+
+func Test_AddSCSI_EncryptedNotSupported(t *testing.T) {
+	uvm := &UtilityVM{operatingSystem: "linux", scsiControllerCount: 1}
+	_, _, err := uvm.AddSCSI(`C:\scratch.vhdx`, &SCSIOptions{UVMPath: "/run/scratch", Encrypted: true})
+	if err == nil || !strings.Contains(err.Error(), errNotSupported.Error()) {
+		t.Fatalf("expected not supported error, got: %v", err)
+	}
+	if n := len(uvm.SCSIAttachments()); n != 0 {
+		t.Fatalf("expected no attachments, got: %d", n)
+	}
+}
+
+func Test_AddSCSI_MountOptionsNotSupported(t *testing.T) {
+	for _, o := range []*SCSIOptions{
+		{UVMPath: "/run/disk", Filesystem: "xfs"},
+		{UVMPath: "/run/disk", MountOptions: []string{"noatime"}},
+	} {
+		uvm := &UtilityVM{operatingSystem: "linux", scsiControllerCount: 1}
+		_, _, err := uvm.AddSCSI(`C:\disk.vhdx`, o)
+		if err == nil || !strings.Contains(err.Error(), errNotSupported.Error()) {
+			t.Fatalf("expected not supported error for %+v, got: %v", *o, err)
+		}
+		if n := len(uvm.SCSIAttachments()); n != 0 {
+			t.Fatalf("expected no attachments, got: %d", n)
+		}
+	}
+}
+
+func Test_AddSCSI_BlockDevNotSupported(t *testing.T) {
+	uvm := &UtilityVM{operatingSystem: "linux", scsiControllerCount: 1}
+	_, _, err := uvm.AddSCSI(`C:\disk.vhdx`, &SCSIOptions{UVMPath: "/run/disk", BlockDev: true})
+	if err == nil || !strings.Contains(err.Error(), errNotSupported.Error()) {
+		t.Fatalf("expected not supported error, got: %v", err)
+	}
+	if n := len(uvm.SCSIAttachments()); n != 0 {
+		t.Fatalf("expected no attachments, got: %d", n)
+	}
+}
+
+func Test_removeSCSI_Encrypted(t *testing.T) {
+	c := cowtest.NewContainer("uvm", "linux", false)
+	uvm := &UtilityVM{operatingSystem: "linux", hcsSystem: c, scsiControllerCount: 1}
+	uvm.scsiLocations[0][1] = scsiInfo{hostPath: `C:\scratch.vhdx`, uvmPath: "/run/scratch", encrypted: true}
+	if err := uvm.removeSCSI(`C:\scratch.vhdx`, "/run/scratch", 0, 1); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	modifies := c.Modifies()
+	if len(modifies) != 1 {
+		t.Fatalf("expected 1 modify, got: %d", len(modifies))
+	}
+	request := modifies[0].(*hcsschema.ModifySettingRequest).GuestRequest.(guestrequest.GuestRequest)
+	if disk := request.Settings.(guestrequest.LCOWMappedVirtualDisk); !disk.Encrypted || disk.Lun != 1 {
+		t.Fatalf("expected the encrypted disk to be removed, got: %+v", disk)
+	}
+}
+
+func Test_PhysicalDiskPath(t *testing.T) {
+	if p := PhysicalDiskPath(2); p != `\\.\PHYSICALDRIVE2` {
+		t.Fatalf("expected physical drive 2 path, got: %s", p)
+	}
+}
@@ -0,0 +1,84 @@
+package uvm
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Microsoft/hcsshim/internal/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/Microsoft/hcsshim/internal/requesttype"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/sirupsen/logrus"
+)
+
+// Share shares `hostPath` into the utility VM at `uvmPath`. A Windows utility
+// VM is given a VSMB share that is mapped to `uvmPath` in the guest. A Linux
+// utility VM is given a Plan9 share mounted at `uvmPath`. A Plan9 share is
+// always mounted on a directory so sharing a single file into a Linux utility
+// VM is not supported. Use AddPlan9File and bind mount the file in the guest
+// instead.
+//
+// Shares are not tracked for removal and remain until the utility VM exits.
+func (uvm *UtilityVM) Share(hostPath, uvmPath string, readOnly bool) (err error) {
+	op := "uvm::Share"
+	log := logrus.WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"host-path":     hostPath,
+		"uvm-path":      uvmPath,
+		"readOnly":      readOnly,
+	})
+	log.Debug(op + " - Begin Operation")
+	defer func() {
+		if err != nil {
+			log.Data[logrus.ErrorKey] = err
+			log.Error(op + " - End Operation - Error")
+		} else {
+			log.Debug(op + " - End Operation - Success")
+		}
+	}()
+
+	if hostPath == "" || uvmPath == "" {
+		return fmt.Errorf("hostPath and uvmPath must be passed to Share")
+	}
+	if uvm.operatingSystem == "windows" {
+		return uvm.shareVSMB(hostPath, uvmPath, readOnly)
+	}
+
+	fi, err := os.Stat(hostPath)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("%s is not a directory", hostPath)
+	}
+	_, err = uvm.AddPlan9(hostPath, uvmPath, readOnly, false, nil)
+	return err
+}
+
+// shareVSMB adds a VSMB share for `hostPath` and maps it to `uvmPath` in the
+// Windows guest.
+func (uvm *UtilityVM) shareVSMB(hostPath, uvmPath string, readOnly bool) (err error) {
+	if err := uvm.AddVSMB(hostPath, "", DefaultVSMBOptions(readOnly)); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			uvm.RemoveVSMB(hostPath)
+		}
+	}()
+	sharePath, err := uvm.GetVSMBUvmPath(hostPath)
+	if err != nil {
+		return err
+	}
+	return uvm.Modify(&hcsschema.ModifySettingRequest{
+		GuestRequest: guestrequest.GuestRequest{
+			ResourceType: guestrequest.ResourceTypeMappedDirectory,
+			RequestType:  requesttype.Add,
+			Settings: hcsschema.MappedDirectory{
+				HostPath:      sharePath,
+				ContainerPath: uvmPath,
+				ReadOnly:      readOnly,
+			},
+		},
+	})
+}
@@ -0,0 +1,15 @@
+package uvm
+
+import (
+	"context"
+)
+
+// DumpStacks returns the goroutine stacks of the GCS in the utility VM.
+//
+// If the guest does not support dumping its stacks returns `errNotSupported`.
+func (uvm *UtilityVM) DumpStacks(ctx context.Context) (string, error) {
+	if !uvm.DumpStacksSupported() {
+		return "", errNotSupported
+	}
+	return uvm.gc.DumpStacks(ctx)
+}
@@ -136,9 +136,19 @@ func processOutput(ctx context.Context, l net.Listener, doneChan chan struct{},
 }
 
 // Start synchronously starts the utility VM.
-func (uvm *UtilityVM) Start() (err error) {
-	ctx, cancel := context.WithTimeout(context.TODO(), 2*time.Minute)
-	defer cancel()
+func (uvm *UtilityVM) Start() error {
+	return uvm.StartContext(context.Background())
+}
+
+// contextStarter is implemented by compute systems whose start can be
+// abandoned when a context is done.
+type contextStarter interface {
+	StartContext(ctx context.Context) error
+}
+
+// StartContext is like Start but stops waiting for the utility VM to start,
+// and terminates it, if `ctx` is done first.
+func (uvm *UtilityVM) StartContext(ctx context.Context) (err error) {
 	op := "uvm::Start"
 	log := logrus.WithFields(logrus.Fields{
 		logfields.UVMID: uvm.id,
@@ -159,26 +169,34 @@ func (uvm *UtilityVM) Start() (err error) {
 		uvm.outputProcessingCancel = cancel
 		uvm.outputListener = nil
 	}
-	err = uvm.hcsSystem.Start()
+	if cs, ok := uvm.hcsSystem.(contextStarter); ok {
+		err = cs.StartContext(ctx)
+	} else {
+		err = uvm.hcsSystem.Start()
+	}
 	if err != nil {
+		if ctx.Err() != nil {
+			uvm.hcsSystem.Terminate()
+			uvm.hcsSystem.Wait()
+		}
 		return err
 	}
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
 	defer func() {
 		if err != nil {
 			uvm.hcsSystem.Terminate()
 			uvm.hcsSystem.Wait()
 		}
 	}()
-	// Start waiting on the utility VM.
-	uvm.exitCh = make(chan struct{})
-	go func() {
-		err := uvm.hcsSystem.Wait()
-		if err == nil {
-			err = uvm.hcsSystem.ExitError()
+	uvm.waitForExit()
+	if uvm.consoleLogPath != "" || (uvm.crashDumpPath != "" && uvm.operatingSystem == "linux") {
+		// The console is only for diagnostics so failing to capture it does
+		// not fail the start.
+		if err := uvm.startConsoleCapture(); err != nil {
+			log.WithError(err).Warning("failed to capture utility VM serial console")
 		}
-		uvm.exitErr = err
-		close(uvm.exitCh)
-	}()
+	}
 	if uvm.gcListener != nil {
 		// Accept the GCS connection.
 		conn, err := uvm.acceptAndClose(ctx, uvm.gcListener)
@@ -199,17 +217,39 @@ func (uvm *UtilityVM) Start() (err error) {
 		uvm.guestCaps = *uvm.gc.Capabilities()
 		uvm.protocol = uvm.gc.Protocol()
 	} else {
-		// Cache the guest connection properties.
-		properties, err := uvm.hcsSystem.Properties(schema1.PropertyTypeGuestConnection)
-		if err != nil {
+		if err := uvm.cacheGuestConnectionProperties(); err != nil {
 			return err
 		}
-		uvm.guestCaps = properties.GuestConnectionInfo.GuestDefinedCapabilities
-		uvm.protocol = properties.GuestConnectionInfo.ProtocolVersion
 	}
 	return nil
 }
 
+// waitForExit starts waiting on the utility VM in the background. `exitCh` is
+// closed once the utility VM has exited.
+func (uvm *UtilityVM) waitForExit() {
+	uvm.exitCh = make(chan struct{})
+	go func() {
+		err := uvm.hcsSystem.Wait()
+		if err == nil {
+			err = uvm.hcsSystem.ExitError()
+		}
+		uvm.exitErr = err
+		close(uvm.exitCh)
+	}()
+}
+
+// cacheGuestConnectionProperties caches the properties of the guest
+// connection managed by the HCS.
+func (uvm *UtilityVM) cacheGuestConnectionProperties() error {
+	properties, err := uvm.hcsSystem.Properties(schema1.PropertyTypeGuestConnection)
+	if err != nil {
+		return err
+	}
+	uvm.guestCaps = properties.GuestConnectionInfo.GuestDefinedCapabilities
+	uvm.protocol = properties.GuestConnectionInfo.ProtocolVersion
+	return nil
+}
+
 // acceptAndClose accepts a connection and then closes a listener. If the
 // context becomes done or the utility VM terminates, the operation will be
 // cancelled (but the listener will still be closed).
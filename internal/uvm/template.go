@@ -0,0 +1,145 @@
+package uvm
+
+import (
+	"errors"
+
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/Microsoft/hcsshim/internal/regstate"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	templateRoot = "uvmtemplates"
+	templateKey  = "cfg"
+
+	// saveTypeAsTemplate saves a compute system as a template that other
+	// compute systems can be cloned from.
+	saveTypeAsTemplate = "AsTemplate"
+)
+
+// TemplateConfig is the state of a utility VM saved as a template that is
+// needed to create clones of it. It is persisted in the registry so that the
+// clones can be created by other processes than the one owning the template.
+type TemplateConfig struct {
+	// ScratchPath is the path of the scratch of the template that the scratch
+	// of every clone is copied from.
+	ScratchPath string
+}
+
+// LoadTemplateConfig loads the config of the template utility VM `id`. If not
+// found returns `regstate.NotFoundError`.
+func LoadTemplateConfig(id string) (*TemplateConfig, error) {
+	sk, err := regstate.Open(templateRoot, false)
+	if err != nil {
+		return nil, err
+	}
+	defer sk.Close()
+
+	var config TemplateConfig
+	if err := sk.Get(id, templateKey, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// storeTemplateConfig persists `config` of the template utility VM `id`.
+func storeTemplateConfig(id string, config *TemplateConfig) error {
+	sk, err := regstate.Open(templateRoot, false)
+	if err != nil {
+		return err
+	}
+	defer sk.Close()
+
+	return sk.Create(id, templateKey, config)
+}
+
+// removeTemplateConfig removes the persisted config of the template utility
+// VM `id`. If the config is not found returns no error.
+func removeTemplateConfig(id string) error {
+	sk, err := regstate.Open(templateRoot, false)
+	if err != nil {
+		if regstate.IsNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+	defer sk.Close()
+
+	if err := sk.Remove(id); err != nil && !regstate.IsNotFoundError(err) {
+		return err
+	}
+	return nil
+}
+
+// IsTemplate returns `true` if the utility VM was saved as a template.
+func (uvm *UtilityVM) IsTemplate() bool {
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+	return uvm.isTemplate
+}
+
+// TemplateID returns the ID of the template the utility VM was cloned from. If
+// the utility VM is not a clone returns "".
+func (uvm *UtilityVM) TemplateID() string {
+	return uvm.templateID
+}
+
+// SaveAsTemplate pauses the running utility VM and saves it as a template that
+// clones can be created from via `OptionsWCOW.TemplateID`. The utility VM
+// cannot host any containers afterwards and MUST NOT be closed while any of
+// its clones are running.
+//
+// Only Windows utility VMs without any network namespaces can be saved as a
+// template as the clones would otherwise share the network adapters of the
+// template.
+func (uvm *UtilityVM) SaveAsTemplate() (err error) {
+	op := "uvm::SaveAsTemplate"
+	log := logrus.WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+	})
+	log.Debug(op + " - Begin Operation")
+	defer func() {
+		if err != nil {
+			log.Data[logrus.ErrorKey] = err
+			log.Error(op + " - End Operation - Error")
+		} else {
+			log.Debug(op + " - End Operation - Success")
+		}
+	}()
+
+	if uvm.operatingSystem != "windows" {
+		return errNotSupported
+	}
+	if uvm.templateID != "" {
+		return errors.New("a clone cannot be saved as a template")
+	}
+
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+
+	if uvm.isTemplate {
+		return errors.New("utility VM is already saved as a template")
+	}
+	if len(uvm.namespaces) > 0 {
+		return errors.New("a utility VM with network namespaces cannot be saved as a template")
+	}
+	if err := uvm.hcsSystem.Pause(); err != nil {
+		return err
+	}
+	if err := uvm.hcsSystem.Save(hcsschema.SaveOptions{SaveType: saveTypeAsTemplate}); err != nil {
+		// The utility VM is still usable if it was not saved.
+		if rerr := uvm.hcsSystem.Resume(); rerr != nil {
+			log.WithError(rerr).Warning("failed to resume utility VM")
+		}
+		return err
+	}
+	config := &TemplateConfig{
+		ScratchPath: uvm.scsiLocations[0][0].hostPath,
+	}
+	if err := storeTemplateConfig(uvm.id, config); err != nil {
+		return err
+	}
+	uvm.isTemplate = true
+	return nil
+}
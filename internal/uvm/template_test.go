@@ -0,0 +1,66 @@
+package uvm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/cow/cowtest"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+)
+
+func Test_SaveAsTemplate_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		uvm  *UtilityVM
+	}{
+		{name: "LCOW", uvm: &UtilityVM{operatingSystem: "linux"}},
+		{name: "Clone", uvm: &UtilityVM{operatingSystem: "windows", templateID: "template"}},
+		{name: "Template", uvm: &UtilityVM{operatingSystem: "windows", isTemplate: true}},
+		{name: "Network", uvm: &UtilityVM{operatingSystem: "windows", namespaces: map[string]*namespaceInfo{"ns": {}}}},
+	}
+	for _, test := range tests {
+		c := cowtest.NewContainer(test.name, test.uvm.operatingSystem, false)
+		test.uvm.hcsSystem = c
+		if err := test.uvm.SaveAsTemplate(); err == nil {
+			t.Fatalf("%s: expected error", test.name)
+		}
+		if c.Paused() || len(c.Saves()) != 0 {
+			t.Fatalf("%s: expected the utility VM not to be paused or saved", test.name)
+		}
+	}
+}
+
+func Test_SaveAsTemplate_PauseFailed(t *testing.T) {
+	c := cowtest.NewContainer("uvm", "windows", false)
+	c.OnPause = func(*cowtest.Container) error {
+		return errors.New("pause failed")
+	}
+	uvm := &UtilityVM{operatingSystem: "windows", hcsSystem: c}
+	if err := uvm.SaveAsTemplate(); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(c.Saves()) != 0 || uvm.isTemplate {
+		t.Fatal("expected the utility VM not to be saved")
+	}
+}
+
+func Test_SaveAsTemplate_SaveFailed_Resumes(t *testing.T) {
+	c := cowtest.NewContainer("uvm", "windows", false)
+	c.OnSave = func(*cowtest.Container, interface{}) error {
+		return errors.New("save failed")
+	}
+	uvm := &UtilityVM{operatingSystem: "windows", hcsSystem: c}
+	if err := uvm.SaveAsTemplate(); err == nil {
+		t.Fatal("expected error")
+	}
+	saves := c.Saves()
+	if len(saves) != 1 || saves[0].(hcsschema.SaveOptions).SaveType != saveTypeAsTemplate {
+		t.Fatalf("expected the utility VM to be saved as a template, got: %+v", saves)
+	}
+	if c.Paused() {
+		t.Fatal("expected the utility VM to be resumed")
+	}
+	if uvm.isTemplate {
+		t.Fatal("expected the utility VM not to be a template")
+	}
+}
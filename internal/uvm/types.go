@@ -8,10 +8,12 @@ import (
 	"sync"
 
 	"github.com/Microsoft/go-winio/pkg/guid"
+	"github.com/Microsoft/hcsshim/internal/cow"
 	"github.com/Microsoft/hcsshim/internal/gcs"
-	"github.com/Microsoft/hcsshim/internal/hcs"
 	"github.com/Microsoft/hcsshim/internal/hns"
+	"github.com/Microsoft/hcsshim/internal/reservation"
 	"github.com/Microsoft/hcsshim/internal/schema1"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
 )
 
 //                    | WCOW | LCOW
@@ -24,6 +26,7 @@ import (
 type vsmbShare struct {
 	refCount     uint32
 	name         string
+	options      *hcsschema.VirtualSmbShareOptions
 	guestRequest interface{}
 }
 
@@ -33,6 +36,18 @@ type scsiInfo struct {
 	hostPath string
 	uvmPath  string
 
+	// attachmentType and readOnly are the settings the disk was attached
+	// with. They are resent when the QoS of the attachment is updated.
+	attachmentType string
+	readOnly       bool
+
+	// encrypted is `true` if the guest set up dm-crypt on the disk when it
+	// was mounted.
+	encrypted bool
+
+	// owner is `SCSIOptions.Owner` of the attachment.
+	owner string
+
 	// While most VHDs attached to SCSI are scratch spaces, in the case of LCOW
 	// when the size is over the size possible to attach to PMEM, we use SCSI for
 	// read-only layers. As RO layers are shared, we perform ref-counting.
@@ -46,6 +61,28 @@ type vpmemInfo struct {
 	hostPath string
 	uvmPath  string
 	refCount uint32
+
+	// mappings are the read-only layers sharing the device when VPMem
+	// multi-mapping is enabled, sorted by offset. `hostPath` is empty for a
+	// multi-mapped device.
+	mappings []*vpmemMapping
+}
+
+// vpmemMapping is an internal structure used for ref-counting a read-only
+// layer mapped into a region of a VPMem device shared with other layers.
+type vpmemMapping struct {
+	hostPath string
+	uvmPath  string
+	offset   uint64
+	size     uint64
+	refCount uint32
+}
+
+// vpciDevice is an internal structure used for ref-counting devices assigned
+// to a utility VM over virtual PCI.
+type vpciDevice struct {
+	vmbusGUID guid.GUID
+	refCount  uint32
 }
 
 type nicInfo struct {
@@ -63,11 +100,30 @@ type UtilityVM struct {
 	runtimeID       guid.GUID            // Hyper-V VM ID
 	owner           string               // Owner for the utility VM (user supplied or generated)
 	operatingSystem string               // "windows" or "linux"
-	hcsSystem       *hcs.System          // The handle to the compute system
+	backend         cow.Backend          // The backend the compute system is created on
+	hcsSystem       cow.ComputeSystem    // The handle to the compute system
 	gcListener      net.Listener         // The GCS connection listener
 	gc              *gcs.GuestConnection // The GCS connection
 	processorCount  int32
-	m               sync.Mutex // Lock for adding/removing devices
+	memorySizeInMB  int32                    // The memory assigned to the UVM
+	virtualTPM      bool                     // `true` if a virtual TPM is attached
+	consolePipe     string                   // The named pipe of the serial console. "" if none
+	consoleLogPath  string                   // The file the serial console is captured to. "" if none
+	console         *consoleCapture          // The capture of the serial console. nil if not captured
+	crashDumpPath   string                   // The file the guest crash dump is written to. "" if none
+	reservation     *reservation.Reservation // The host-wide reservation. nil if none
+	reserveLimits   reservation.Limits       // The limits `reservation` was made within
+	cpuGroupID      string                   // The CPU group the UVM is assigned to. "" if none
+	ownedCPUGroup   string                   // The CPU group created for the UVM and deleted on close. "" if none
+	isTemplate      bool                     // `true` if the UVM was saved as a template
+	encryptScratch  bool                     // `true` if container scratch disks are encrypted in the guest
+	layerIntegrity  bool                     // `true` if read-only layers are verified with dm-verity in the guest
+	layerDigests    map[string]string        // The trusted dm-verity root digests of read-only layers keyed by host path
+	discardScratch  bool                     // `true` if container scratch disks are mounted with discard in the guest
+	scratchFs       string                   // The filesystem of container scratch disks. "" for the guest default
+	layerPolicy     LayerAttachmentPolicy    // How read-only container layers are attached. The default depends on the guest OS
+	templateID      string                   // The ID of the template the UVM was cloned from. "" if none
+	m               sync.Mutex               // Lock for adding/removing devices
 
 	exitErr error
 	exitCh  chan struct{}
@@ -92,16 +148,25 @@ type UtilityVM struct {
 	vpmemDevices      [MaxVPMEMCount]vpmemInfo // Limited by ACPI size.
 	vpmemMaxCount     uint32                   // Actual number of VPMem devices
 	vpmemMaxSizeBytes uint64                   // Actual size of VPMem devices
+	vpmemMultiMapping bool                     // If true, read-only layers share VPMem devices at different offsets
 
 	// SCSI devices that are mapped into a Windows or Linux utility VM
-	scsiLocations       [4][64]scsiInfo // Hyper-V supports 4 controllers, 64 slots per controller. Limited to 1 controller for now though.
-	scsiControllerCount uint32          // Number of SCSI controllers in the utility VM
+	scsiLocations       [MaxSCSIControllerCount][SCSILUNsPerController]scsiInfo // Hyper-V supports 4 controllers, 64 slots per controller.
+	scsiControllerCount uint32                                                  // Number of SCSI controllers in the utility VM
 
 	// Plan9 are directories mapped into a Linux utility VM
 	plan9Counter uint64 // Each newly-added plan9 share has a counter used as its ID in the ResourceURI and for the name
 
 	namespaces map[string]*namespaceInfo
 
+	// vpciDevices are the devices assigned to the utility VM over virtual PCI
+	// keyed by their device instance path on the host.
+	vpciDevices map[string]*vpciDevice
+
+	// hvSocketServices are the reference counts of the HvSocket services
+	// registered in the service table of the utility VM.
+	hvSocketServices map[guid.GUID]uint32
+
 	outputListener         net.Listener
 	outputProcessingDone   chan struct{}
 	outputHandler          OutputHandler
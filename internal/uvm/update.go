@@ -0,0 +1,180 @@
+package uvm
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/Microsoft/hcsshim/internal/requesttype"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	memoryResourcePath    = "VirtualMachine/ComputeTopology/Memory/SizeInMB"
+	processorResourcePath = "VirtualMachine/ComputeTopology/Processor"
+)
+
+// UpdateMemory changes the amount of memory assigned to a running utility VM.
+// `sizeInMB` is aligned up to the next 2MB boundary in the same way as at
+// create time. If the utility VM has a host-wide reservation it is updated to
+// the new size, and growing fails with a `*reservation.ExhaustedError` if that
+// exceeds the reservation limits.
+func (uvm *UtilityVM) UpdateMemory(sizeInMB int32) (err error) {
+	op := "uvm::UpdateMemory"
+	log := logrus.WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"sizeInMB":      sizeInMB,
+	})
+	log.Debug(op + " - Begin Operation")
+	defer func() {
+		if err != nil {
+			log.Data[logrus.ErrorKey] = err
+			log.Error(op + " - End Operation - Error")
+		} else {
+			log.Debug(op + " - End Operation - Success")
+		}
+	}()
+
+	if sizeInMB <= 0 {
+		return fmt.Errorf("invalid memory size %d", sizeInMB)
+	}
+
+	actual := uvm.normalizeMemorySize(sizeInMB)
+
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+
+	// Growing the memory must fit in the host-wide reservations before it is
+	// added. Shrinking it only releases reservation once it is removed.
+	grow := actual > uvm.memorySizeInMB
+	if grow {
+		if err := uvm.updateReservation(actual, uvm.processorCount, true); err != nil {
+			return err
+		}
+	}
+	modification := &hcsschema.ModifySettingRequest{
+		RequestType:  requesttype.Update,
+		Settings:     uint64(actual),
+		ResourcePath: memoryResourcePath,
+	}
+	if err := uvm.Modify(modification); err != nil {
+		if grow {
+			if rerr := uvm.updateReservation(uvm.memorySizeInMB, uvm.processorCount, false); rerr != nil {
+				log.WithError(rerr).Warning("failed to restore utility VM reservation")
+			}
+		}
+		return err
+	}
+	uvm.memorySizeInMB = actual
+	if !grow {
+		if err := uvm.updateReservation(actual, uvm.processorCount, false); err != nil {
+			log.WithError(err).Warning("failed to update utility VM reservation")
+		}
+	}
+	return nil
+}
+
+// UpdateProcessor changes the vCPU limit and weight of a running utility VM.
+// A value of `0` leaves the corresponding setting unchanged. The vCPU count is
+// changed via `UpdateProcessorCount`.
+func (uvm *UtilityVM) UpdateProcessor(limit, weight int32) (err error) {
+	op := "uvm::UpdateProcessor"
+	log := logrus.WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"limit":         limit,
+		"weight":        weight,
+	})
+	log.Debug(op + " - Begin Operation")
+	defer func() {
+		if err != nil {
+			log.Data[logrus.ErrorKey] = err
+			log.Error(op + " - End Operation - Error")
+		} else {
+			log.Debug(op + " - End Operation - Success")
+		}
+	}()
+
+	if limit < 0 || weight < 0 {
+		return fmt.Errorf("invalid processor limit %d or weight %d", limit, weight)
+	}
+	if limit == 0 && weight == 0 {
+		return nil
+	}
+
+	modification := &hcsschema.ModifySettingRequest{
+		RequestType: requesttype.Update,
+		Settings: hcsschema.Processor2{
+			Limit:  limit,
+			Weight: weight,
+		},
+		ResourcePath: processorResourcePath,
+	}
+	return uvm.Modify(modification)
+}
+
+// UpdateProcessorCount hot adds or removes vCPUs of a running utility VM so
+// that it has `count` vCPUs. This is only supported if
+// `ProcessorHotAddSupported` returns `true`. If the utility VM has a host-wide
+// reservation it is updated to the new count, and adding vCPUs fails with a
+// `*reservation.ExhaustedError` if that exceeds the reservation limits.
+func (uvm *UtilityVM) UpdateProcessorCount(count int32) (err error) {
+	op := "uvm::UpdateProcessorCount"
+	log := logrus.WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"count":         count,
+	})
+	log.Debug(op + " - Begin Operation")
+	defer func() {
+		if err != nil {
+			log.Data[logrus.ErrorKey] = err
+			log.Error(op + " - End Operation - Error")
+		} else {
+			log.Debug(op + " - End Operation - Success")
+		}
+	}()
+
+	if !uvm.ProcessorHotAddSupported() {
+		return errNotSupported
+	}
+	if hostCount := int32(runtime.NumCPU()); count <= 0 || count > hostCount {
+		return fmt.Errorf("invalid processor count %d, must be in the range 1 - %d", count, hostCount)
+	}
+
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+
+	if count == uvm.processorCount {
+		return nil
+	}
+	// Adding vCPUs must fit in the host-wide reservations before they are
+	// added. Removing them only releases reservation once they are removed.
+	grow := count > uvm.processorCount
+	if grow {
+		if err := uvm.updateReservation(uvm.memorySizeInMB, count, true); err != nil {
+			return err
+		}
+	}
+	modification := &hcsschema.ModifySettingRequest{
+		RequestType: requesttype.Update,
+		Settings: hcsschema.Processor2{
+			Count: count,
+		},
+		ResourcePath: processorResourcePath,
+	}
+	if err := uvm.Modify(modification); err != nil {
+		if grow {
+			if rerr := uvm.updateReservation(uvm.memorySizeInMB, uvm.processorCount, false); rerr != nil {
+				log.WithError(rerr).Warning("failed to restore utility VM reservation")
+			}
+		}
+		return err
+	}
+	uvm.processorCount = count
+	if !grow {
+		if err := uvm.updateReservation(uvm.memorySizeInMB, count, false); err != nil {
+			log.WithError(err).Warning("failed to update utility VM reservation")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package uvm
+
+import (
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/cow/cowtest"
+)
+
+func Test_UpdateProcessorCount_GuestUnsupported(t *testing.T) {
+	c := cowtest.NewContainer("uvm", "linux", false)
+	uvm := &UtilityVM{operatingSystem: "linux", hcsSystem: c, processorCount: 1}
+	if err := uvm.UpdateProcessorCount(2); err != errNotSupported {
+		t.Fatalf("expected: %v, got: %v", errNotSupported, err)
+	}
+	if len(c.Modifies()) != 0 {
+		t.Fatal("expected no modify without guest support")
+	}
+	if uvm.processorCount != 1 {
+		t.Fatalf("expected processor count 1, got: %d", uvm.processorCount)
+	}
+}
@@ -0,0 +1,69 @@
+package uvm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/Microsoft/hcsshim/ext4/dmverity"
+	"github.com/Microsoft/hcsshim/internal/guestrequest"
+)
+
+// SetLayerRootDigest records `rootDigest`, the hex encoded dm-verity root
+// digest returned when the read-only layer at `hostPath` was converted, as the
+// digest the guest verifies the layer against. With layer integrity enabled a
+// layer is only attached once its root digest is recorded. The root digest of
+// a layer cannot change for the lifetime of the utility VM.
+func (uvm *UtilityVM) SetLayerRootDigest(hostPath, rootDigest string) error {
+	if b, err := hex.DecodeString(rootDigest); err != nil || len(b) != sha256.Size {
+		return fmt.Errorf("invalid dm-verity root digest '%s' of layer %s", rootDigest, hostPath)
+	}
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+	if existing, ok := uvm.layerDigests[hostPath]; ok && existing != rootDigest {
+		return fmt.Errorf("layer %s is already verified against root digest %s, not %s", hostPath, existing, rootDigest)
+	}
+	if uvm.layerDigests == nil {
+		uvm.layerDigests = make(map[string]string)
+	}
+	uvm.layerDigests[hostPath] = rootDigest
+	return nil
+}
+
+// layerVerityInfo returns the dm-verity information the guest verifies the
+// read-only layer at `hostPath` with, or `nil` if layer integrity is not
+// enabled for the utility VM. The hash device geometry is read from the layer
+// but the root digest is the trusted one recorded by `SetLayerRootDigest`, so
+// a layer whose data and hash tree were both tampered with fails to verify.
+func (uvm *UtilityVM) layerVerityInfo(hostPath string) (*guestrequest.DeviceVerityInfo, error) {
+	if !uvm.layerIntegrity {
+		return nil, nil
+	}
+	if !uvm.LayerIntegritySupported() {
+		return nil, fmt.Errorf("layer integrity verification is not supported by the guest: %s", errNotSupported)
+	}
+	uvm.m.Lock()
+	rootDigest := uvm.layerDigests[hostPath]
+	uvm.m.Unlock()
+	if rootDigest == "" {
+		return nil, fmt.Errorf("no dm-verity root digest was set for layer %s", hostPath)
+	}
+	f, err := os.Open(hostPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := dmverity.ReadInfo(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dm-verity hash device of layer %s: %s", hostPath, err)
+	}
+	return &guestrequest.DeviceVerityInfo{
+		Ext4SizeInBytes: info.DataSize,
+		Version:         info.Version,
+		Algorithm:       info.Algorithm,
+		BlockSize:       info.BlockSize,
+		Salt:            info.Salt,
+		RootDigest:      rootDigest,
+	}, nil
+}
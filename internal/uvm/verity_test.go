@@ -0,0 +1,175 @@
+package uvm
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/ext4/dmverity"
+	"github.com/Microsoft/hcsshim/ext4/tar2ext4"
+	"github.com/Microsoft/hcsshim/internal/cow/cowtest"
+	"github.com/Microsoft/hcsshim/internal/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/schema1"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+)
+
+// testRootDigest is a well formed root digest that no test layer has.
+var testRootDigest = strings.Repeat("ab", 32)
+
+// writeTestLayer writes a layer VHD with a single file, with a dm-verity hash
+// device if `verity`, and returns its path and root digest.
+func writeTestLayer(t *testing.T, verity bool) (string, string) {
+	var b bytes.Buffer
+	tw := tar.NewWriter(&b)
+	if err := tw.WriteHeader(&tar.Header{Name: "file", Mode: 0644, Size: 5, Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := ioutil.TempFile("", "layer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var rootDigest string
+	opts := []tar2ext4.Option{tar2ext4.AppendVhdFooter}
+	if verity {
+		opts = append(opts, tar2ext4.AppendDMVerity, tar2ext4.DMVerityRootDigest(&rootDigest))
+	}
+	if err := tar2ext4.Convert(&b, f, opts...); err != nil {
+		os.Remove(f.Name())
+		t.Fatal(err)
+	}
+	return f.Name(), rootDigest
+}
+
+func Test_SetLayerRootDigest(t *testing.T) {
+	uvm := &UtilityVM{operatingSystem: "linux"}
+	for _, digest := range []string{"", "not hex", "abcd"} {
+		if err := uvm.SetLayerRootDigest(`C:\layer.vhd`, digest); err == nil {
+			t.Fatalf("expected error for root digest '%s'", digest)
+		}
+	}
+	if err := uvm.SetLayerRootDigest(`C:\layer.vhd`, testRootDigest); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if err := uvm.SetLayerRootDigest(`C:\layer.vhd`, testRootDigest); err != nil {
+		t.Fatalf("expected nil error setting the same root digest, got: %v", err)
+	}
+	if err := uvm.SetLayerRootDigest(`C:\layer.vhd`, strings.Repeat("cd", 32)); err == nil {
+		t.Fatal("expected error changing the root digest of a layer")
+	}
+}
+
+func Test_layerVerityInfo_Disabled(t *testing.T) {
+	uvm := &UtilityVM{operatingSystem: "linux"}
+	info, err := uvm.layerVerityInfo(`C:\layer.vhd`)
+	if err != nil || info != nil {
+		t.Fatalf("expected no verity info, got: %+v %v", info, err)
+	}
+}
+
+func Test_layerVerityInfo_NotSupported(t *testing.T) {
+	uvm := &UtilityVM{operatingSystem: "linux", layerIntegrity: true}
+	_, err := uvm.layerVerityInfo(`C:\layer.vhd`)
+	if err == nil || !strings.Contains(err.Error(), errNotSupported.Error()) {
+		t.Fatalf("expected not supported error, got: %v", err)
+	}
+}
+
+func Test_layerVerityInfo(t *testing.T) {
+	layer, rootDigest := writeTestLayer(t, true)
+	defer os.Remove(layer)
+
+	uvm := &UtilityVM{
+		operatingSystem: "linux",
+		layerIntegrity:  true,
+		guestCaps:       schema1.GuestDefinedCapabilities{LayerIntegritySupported: true},
+	}
+	if err := uvm.SetLayerRootDigest(layer, rootDigest); err != nil {
+		t.Fatal(err)
+	}
+	info, err := uvm.layerVerityInfo(layer)
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	f, err := os.Open(layer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	expected, err := dmverity.ReadInfo(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.RootDigest != rootDigest || info.Ext4SizeInBytes != expected.DataSize {
+		t.Fatalf("expected verity info of %+v with root digest %s, got: %+v", expected, rootDigest, info)
+	}
+}
+
+func Test_layerVerityInfo_NoRootDigest(t *testing.T) {
+	layer, _ := writeTestLayer(t, true)
+	defer os.Remove(layer)
+
+	uvm := &UtilityVM{
+		operatingSystem: "linux",
+		layerIntegrity:  true,
+		guestCaps:       schema1.GuestDefinedCapabilities{LayerIntegritySupported: true},
+	}
+	if _, err := uvm.layerVerityInfo(layer); err == nil {
+		t.Fatal("expected error for a layer without a root digest")
+	}
+}
+
+func Test_layerVerityInfo_NoHashDevice(t *testing.T) {
+	layer, _ := writeTestLayer(t, false)
+	defer os.Remove(layer)
+
+	uvm := &UtilityVM{
+		operatingSystem: "linux",
+		layerIntegrity:  true,
+		guestCaps:       schema1.GuestDefinedCapabilities{LayerIntegritySupported: true},
+	}
+	if err := uvm.SetLayerRootDigest(layer, testRootDigest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uvm.layerVerityInfo(layer); err == nil {
+		t.Fatal("expected error for a layer without a hash device")
+	}
+}
+
+func Test_AddVPMEM_LayerIntegrity(t *testing.T) {
+	layer, rootDigest := writeTestLayer(t, true)
+	defer os.Remove(layer)
+
+	c := cowtest.NewContainer("uvm", "linux", false)
+	uvm := &UtilityVM{
+		operatingSystem: "linux",
+		hcsSystem:       c,
+		vpmemMaxCount:   DefaultVPMEMCount,
+		layerIntegrity:  true,
+		guestCaps:       schema1.GuestDefinedCapabilities{LayerIntegritySupported: true},
+	}
+	if err := uvm.SetLayerRootDigest(layer, rootDigest); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := uvm.AddVPMEM(layer, true); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	modifies := c.Modifies()
+	if len(modifies) != 1 {
+		t.Fatalf("expected 1 modify, got: %d", len(modifies))
+	}
+	request := modifies[0].(*hcsschema.ModifySettingRequest).GuestRequest.(guestrequest.GuestRequest)
+	if device := request.Settings.(guestrequest.LCOWMappedVPMemDevice); device.VerityInfo == nil || device.VerityInfo.RootDigest != rootDigest {
+		t.Fatalf("expected the layer to be verified, got: %+v", device)
+	}
+}
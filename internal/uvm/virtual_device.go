@@ -0,0 +1,134 @@
+package uvm
+
+import (
+	"fmt"
+
+	"github.com/Microsoft/go-winio/pkg/guid"
+	"github.com/Microsoft/hcsshim/internal/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/Microsoft/hcsshim/internal/requesttype"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/sirupsen/logrus"
+)
+
+const vpciResourcePathFormat = "VirtualMachine/Devices/VirtualPci/%s"
+
+// AddDevice assigns the host device at `deviceInstancePath` to the utility VM
+// using Discrete Device Assignment. This is used for GPUs and other PCIe
+// devices. Devices are ref-counted so multiple containers in the utility VM
+// can share the same assignment.
+//
+// Returns the VMBus instance GUID of the virtual PCI bus the device is exposed
+// on in the guest. LCOW guests must advertise `VPCIDevicesSupported`.
+func (uvm *UtilityVM) AddDevice(deviceInstancePath string) (_ guid.GUID, err error) {
+	op := "uvm::AddDevice"
+	log := logrus.WithFields(logrus.Fields{
+		logfields.UVMID:        uvm.id,
+		"device-instance-path": deviceInstancePath,
+	})
+	log.Debug(op + " - Begin Operation")
+	defer func() {
+		if err != nil {
+			log.Data[logrus.ErrorKey] = err
+			log.Error(op + " - End Operation - Error")
+		} else {
+			log.Debug(op + " - End Operation - Success")
+		}
+	}()
+
+	if deviceInstancePath == "" {
+		return guid.GUID{}, fmt.Errorf("device instance path must be specified")
+	}
+	if uvm.operatingSystem == "linux" && !uvm.VPCIDevicesSupported() {
+		return guid.GUID{}, fmt.Errorf("utility VM %s guest does not support virtual PCI devices", uvm.id)
+	}
+
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+
+	if d, ok := uvm.vpciDevices[deviceInstancePath]; ok {
+		d.refCount++
+		return d.vmbusGUID, nil
+	}
+
+	vmbusGUID, err := guid.NewV4()
+	if err != nil {
+		return guid.GUID{}, err
+	}
+	modification := &hcsschema.ModifySettingRequest{
+		RequestType:  requesttype.Add,
+		ResourcePath: fmt.Sprintf(vpciResourcePathFormat, vmbusGUID),
+		Settings: hcsschema.VirtualPciDevice{
+			Functions: []hcsschema.VirtualPciFunction{
+				{
+					DeviceInstancePath: deviceInstancePath,
+				},
+			},
+		},
+	}
+	if uvm.operatingSystem == "linux" {
+		// Windows guests enumerate the device on their own. LCOW has to be told
+		// to wait for the bus to arrive before containers can use the device.
+		modification.GuestRequest = guestrequest.GuestRequest{
+			ResourceType: guestrequest.ResourceTypeVPCIDevice,
+			RequestType:  requesttype.Add,
+			Settings: guestrequest.LCOWMappedVPCIDevice{
+				VMBusGUID: vmbusGUID.String(),
+			},
+		}
+	}
+	if err := uvm.Modify(modification); err != nil {
+		return guid.GUID{}, fmt.Errorf("failed to assign device %s to utility VM %s: %s", deviceInstancePath, uvm.id, err)
+	}
+
+	if uvm.vpciDevices == nil {
+		uvm.vpciDevices = make(map[string]*vpciDevice)
+	}
+	uvm.vpciDevices[deviceInstancePath] = &vpciDevice{
+		vmbusGUID: vmbusGUID,
+		refCount:  1,
+	}
+	return vmbusGUID, nil
+}
+
+// RemoveDevice releases a reference to the device at `deviceInstancePath`
+// previously assigned with AddDevice. The device is removed from the utility
+// VM when the last reference is released.
+func (uvm *UtilityVM) RemoveDevice(deviceInstancePath string) (err error) {
+	op := "uvm::RemoveDevice"
+	log := logrus.WithFields(logrus.Fields{
+		logfields.UVMID:        uvm.id,
+		"device-instance-path": deviceInstancePath,
+	})
+	log.Debug(op + " - Begin Operation")
+	defer func() {
+		if err != nil {
+			log.Data[logrus.ErrorKey] = err
+			log.Error(op + " - End Operation - Error")
+		} else {
+			log.Debug(op + " - End Operation - Success")
+		}
+	}()
+
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+
+	d, ok := uvm.vpciDevices[deviceInstancePath]
+	if !ok {
+		return fmt.Errorf("device %s is not assigned to utility VM %s", deviceInstancePath, uvm.id)
+	}
+	if d.refCount > 1 {
+		d.refCount--
+		return nil
+	}
+
+	modification := &hcsschema.ModifySettingRequest{
+		RequestType:  requesttype.Remove,
+		ResourcePath: fmt.Sprintf(vpciResourcePathFormat, d.vmbusGUID),
+	}
+	if err := uvm.Modify(modification); err != nil {
+		return fmt.Errorf("failed to remove device %s from utility VM %s: %s", deviceInstancePath, uvm.id, err)
+	}
+	delete(uvm.vpciDevices, deviceInstancePath)
+	return nil
+}
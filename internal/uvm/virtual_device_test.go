@@ -0,0 +1,63 @@
+package uvm
+
+import (
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/cow/cowtest"
+	"github.com/Microsoft/hcsshim/internal/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/schema1"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+)
+
+func Test_AddDevice_LCOW_GuestUnsupported(t *testing.T) {
+	c := cowtest.NewContainer("uvm", "linux", false)
+	vm := &UtilityVM{operatingSystem: "linux", hcsSystem: c}
+	if _, err := vm.AddDevice(`PCIROOT(0)#PCI(0300)`); err == nil {
+		t.Fatal("expected error without guest support")
+	}
+	if len(c.Modifies()) != 0 {
+		t.Fatal("expected no modify without guest support")
+	}
+}
+
+func Test_AddDevice_LCOW_RefCount(t *testing.T) {
+	c := cowtest.NewContainer("uvm", "linux", false)
+	vm := &UtilityVM{
+		operatingSystem: "linux",
+		hcsSystem:       c,
+		guestCaps:       schema1.GuestDefinedCapabilities{VPCIDevicesSupported: true},
+	}
+	g1, err := vm.AddDevice(`PCIROOT(0)#PCI(0300)`)
+	if err != nil {
+		t.Fatalf("failed to add device: %s", err)
+	}
+	g2, err := vm.AddDevice(`PCIROOT(0)#PCI(0300)`)
+	if err != nil {
+		t.Fatalf("failed to add device: %s", err)
+	}
+	if g1 != g2 {
+		t.Fatalf("expected the same bus, got: %s and %s", g1, g2)
+	}
+	modifies := c.Modifies()
+	if len(modifies) != 1 {
+		t.Fatalf("expected the device to be assigned once, got %d modifies", len(modifies))
+	}
+	add := modifies[0].(*hcsschema.ModifySettingRequest)
+	gr, ok := add.GuestRequest.(guestrequest.GuestRequest)
+	if !ok || gr.Settings.(guestrequest.LCOWMappedVPCIDevice).VMBusGUID != g1.String() {
+		t.Fatalf("expected guest request for the bus, got: %+v", add.GuestRequest)
+	}
+
+	if err := vm.RemoveDevice(`PCIROOT(0)#PCI(0300)`); err != nil {
+		t.Fatalf("failed to remove device: %s", err)
+	}
+	if len(c.Modifies()) != 1 {
+		t.Fatal("expected the device to stay assigned while referenced")
+	}
+	if err := vm.RemoveDevice(`PCIROOT(0)#PCI(0300)`); err != nil {
+		t.Fatalf("failed to remove device: %s", err)
+	}
+	if len(c.Modifies()) != 2 {
+		t.Fatal("expected the device to be removed")
+	}
+}
@@ -10,11 +10,15 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// ErrNoFreeVPMEMLocations is returned by AddVPMEM if every VPMem device of the
+// utility VM is in use.
+var ErrNoFreeVPMEMLocations = fmt.Errorf("no free VPMEM locations")
+
 // allocateVPMEM finds the next available VPMem slot. The lock MUST be held
 // when calling this function.
 func (uvm *UtilityVM) allocateVPMEM(hostPath string) (uint32, error) {
 	for index, vi := range uvm.vpmemDevices {
-		if vi.hostPath == "" {
+		if vi.hostPath == "" && len(vi.mappings) == 0 {
 			vi.hostPath = hostPath
 			logrus.WithFields(logrus.Fields{
 				logfields.UVMID: uvm.id,
@@ -26,7 +30,7 @@ func (uvm *UtilityVM) allocateVPMEM(hostPath string) (uint32, error) {
 			return uint32(index), nil
 		}
 	}
-	return 0, fmt.Errorf("no free VPMEM locations")
+	return 0, ErrNoFreeVPMEMLocations
 }
 
 func (uvm *UtilityVM) deallocateVPMEM(deviceNumber uint32) error {
@@ -68,6 +72,12 @@ func (uvm *UtilityVM) findVPMEMDevice(findThisHostPath string) (uint32, string,
 //
 // Returns the location(0..MaxVPMEM-1) where the device is attached, and if exposed,
 // the utility VM path which will be /tmp/p<location>//
+//
+// If VPMem multi-mapping is enabled for the utility VM and the guest supports
+// it an exposed disk is instead mapped into a device shared with other
+// read-only layers and the utility VM path will be /tmp/p<location>-<offset>.
+// A guest that does not support it would mount the whole device so each disk
+// takes its own device instead.
 func (uvm *UtilityVM) AddVPMEM(hostPath string, expose bool) (_ uint32, _ string, err error) {
 	op := "uvm::AddVPMEM"
 	log := logrus.WithFields(logrus.Fields{
@@ -89,9 +99,20 @@ func (uvm *UtilityVM) AddVPMEM(hostPath string, expose bool) (_ uint32, _ string
 		return 0, "", errNotSupported
 	}
 
+	var verityInfo *guestrequest.DeviceVerityInfo
+	if expose {
+		if verityInfo, err = uvm.layerVerityInfo(hostPath); err != nil {
+			return 0, "", err
+		}
+	}
+
 	uvm.m.Lock()
 	defer uvm.m.Unlock()
 
+	if uvm.vpmemMultiMapping && expose && uvm.VPMemMultiMappingSupported() {
+		return uvm.addVPMEMMapped(hostPath, verityInfo)
+	}
+
 	var deviceNumber uint32
 	uvmPath := ""
 
@@ -121,6 +142,7 @@ func (uvm *UtilityVM) AddVPMEM(hostPath string, expose bool) (_ uint32, _ string
 				Settings: guestrequest.LCOWMappedVPMemDevice{
 					DeviceNumber: deviceNumber,
 					MountPath:    uvmPath,
+					VerityInfo:   verityInfo,
 				},
 			}
 		}
@@ -173,6 +195,13 @@ func (uvm *UtilityVM) RemoveVPMEM(hostPath string) (err error) {
 	uvm.m.Lock()
 	defer uvm.m.Unlock()
 
+	if deviceNumber, m := uvm.findVPMEMMapping(hostPath); m != nil {
+		if err := uvm.removeVPMEMMapped(deviceNumber, m); err != nil {
+			return fmt.Errorf("failed to remove VPMEM mapping %s from utility VM %s: %s", hostPath, uvm.id, err)
+		}
+		return nil
+	}
+
 	// Make sure is actually attached
 	deviceNumber, uvmPath, err := uvm.findVPMEMDevice(hostPath)
 	if err != nil {
@@ -213,6 +242,20 @@ func (uvm *UtilityVM) removeVPMEM(hostPath string, uvmPath string, deviceNumber
 
 }
 
+// VPMEMDevicesInUse returns the number of VPMem devices of the utility VM that
+// hold a disk, including multi-mapped devices shared by several layers.
+func (uvm *UtilityVM) VPMEMDevicesInUse() int {
+	uvm.m.Lock()
+	defer uvm.m.Unlock()
+	inUse := 0
+	for _, vi := range uvm.vpmemDevices {
+		if vi.hostPath != "" || len(vi.mappings) != 0 {
+			inUse++
+		}
+	}
+	return inUse
+}
+
 // PMemMaxSizeBytes returns the maximum size of a PMEM layer (LCOW)
 func (uvm *UtilityVM) PMemMaxSizeBytes() uint64 {
 	return uvm.vpmemMaxSizeBytes
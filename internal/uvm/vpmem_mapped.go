@@ -0,0 +1,230 @@
+package uvm
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Microsoft/hcsshim/internal/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/logfields"
+	"github.com/Microsoft/hcsshim/internal/requesttype"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	"github.com/sirupsen/logrus"
+)
+
+// vpmemMappingAlignment is the alignment of the offset and size of each layer
+// mapped into a multi-mapped VPMem device.
+const vpmemMappingAlignment = 4096
+
+// alignVPMEMMapping rounds `size` up to `vpmemMappingAlignment`.
+func alignVPMEMMapping(size uint64) uint64 {
+	return (size + vpmemMappingAlignment - 1) &^ (vpmemMappingAlignment - 1)
+}
+
+// findVPMEMMappingOffset returns the lowest offset in a device of
+// `deviceSize` bytes that is not used by `mappings` and can hold `size`
+// bytes. `mappings` MUST be sorted by offset.
+func findVPMEMMappingOffset(mappings []*vpmemMapping, deviceSize, size uint64) (uint64, bool) {
+	var offset uint64
+	for _, m := range mappings {
+		if m.offset-offset >= size {
+			return offset, true
+		}
+		offset = m.offset + m.size
+	}
+	if deviceSize-offset >= size {
+		return offset, true
+	}
+	return 0, false
+}
+
+// insertVPMEMMapping adds `m` to `mappings` keeping them sorted by offset.
+func insertVPMEMMapping(mappings []*vpmemMapping, m *vpmemMapping) []*vpmemMapping {
+	i := 0
+	for i < len(mappings) && mappings[i].offset < m.offset {
+		i++
+	}
+	mappings = append(mappings, nil)
+	copy(mappings[i+1:], mappings[i:])
+	mappings[i] = m
+	return mappings
+}
+
+// findVPMEMMapping returns the device number and mapping of `hostPath` if it
+// is mapped into a multi-mapped VPMem device.
+//
+// The lock MUST be held when calling this function.
+func (uvm *UtilityVM) findVPMEMMapping(hostPath string) (uint32, *vpmemMapping) {
+	for deviceNumber, vi := range uvm.vpmemDevices {
+		for _, m := range vi.mappings {
+			if m.hostPath == hostPath {
+				return uint32(deviceNumber), m
+			}
+		}
+	}
+	return 0, nil
+}
+
+// addVPMEMMapped maps the read-only layer at `hostPath` into the first VPMem
+// device with enough free space, hot adding a new device if none has any.
+// `verityInfo` is sent to the guest to verify the layer with, if not `nil`.
+//
+// The lock MUST be held when calling this function.
+func (uvm *UtilityVM) addVPMEMMapped(hostPath string, verityInfo *guestrequest.DeviceVerityInfo) (uint32, string, error) {
+	if deviceNumber, m := uvm.findVPMEMMapping(hostPath); m != nil {
+		m.refCount++
+		return deviceNumber, m.uvmPath, nil
+	}
+
+	fi, err := os.Stat(hostPath)
+	if err != nil {
+		return 0, "", err
+	}
+	size := alignVPMEMMapping(uint64(fi.Size()))
+	if size > uvm.vpmemMaxSizeBytes {
+		return 0, "", fmt.Errorf("%s is too large to map into a VPMEM device", hostPath)
+	}
+
+	var (
+		deviceNumber uint32
+		offset       uint64
+		found        bool
+	)
+	for i := uint32(0); i < uvm.vpmemMaxCount; i++ {
+		if len(uvm.vpmemDevices[i].mappings) == 0 {
+			continue
+		}
+		if offset, found = findVPMEMMappingOffset(uvm.vpmemDevices[i].mappings, uvm.vpmemMaxSizeBytes, size); found {
+			deviceNumber = i
+			break
+		}
+	}
+	newDevice := !found
+	if newDevice {
+		if deviceNumber, err = uvm.allocateVPMEM(hostPath); err != nil {
+			return 0, "", err
+		}
+		if deviceNumber >= uvm.vpmemMaxCount {
+			return 0, "", ErrNoFreeVPMEMLocations
+		}
+		modification := &hcsschema.ModifySettingRequest{
+			RequestType: requesttype.Add,
+			Settings: hcsschema.VirtualPMemDevice{
+				ReadOnly:    true,
+				ImageFormat: "Vhd1",
+			},
+			ResourcePath: fmt.Sprintf("VirtualMachine/Devices/VirtualPMem/Devices/%d", deviceNumber),
+		}
+		if err := uvm.Modify(modification); err != nil {
+			return 0, "", fmt.Errorf("uvm::AddVPMEM: failed to add multi-mapped VPMEM device: %s", err)
+		}
+		offset = 0
+	}
+
+	uvmPath := fmt.Sprintf("/tmp/p%d-%d", deviceNumber, offset)
+	modification := &hcsschema.ModifySettingRequest{
+		RequestType: requesttype.Add,
+		Settings: hcsschema.VirtualPMemMapping{
+			HostPath:    hostPath,
+			ImageFormat: "Vhd1",
+		},
+		ResourcePath: fmt.Sprintf("VirtualMachine/Devices/VirtualPMem/Devices/%d/Mappings/%d", deviceNumber, offset),
+		GuestRequest: guestrequest.GuestRequest{
+			ResourceType: guestrequest.ResourceTypeVPMemDevice,
+			RequestType:  requesttype.Add,
+			Settings: guestrequest.LCOWMappedVPMemDevice{
+				DeviceNumber: deviceNumber,
+				MountPath:    uvmPath,
+				MappingInfo: &guestrequest.LCOWVPMemMappingInfo{
+					DeviceOffsetInBytes: offset,
+					DeviceSizeInBytes:   size,
+				},
+				VerityInfo: verityInfo,
+			},
+		},
+	}
+	if err := uvm.Modify(modification); err != nil {
+		if newDevice {
+			if err := uvm.removeVPMEMMappedDevice(deviceNumber); err != nil {
+				logrus.WithError(err).Warn("Possibly leaked vpmemdevice on error removal path")
+			}
+		}
+		return 0, "", fmt.Errorf("uvm::AddVPMEM: failed to map %s into VPMEM device %d: %s", hostPath, deviceNumber, err)
+	}
+
+	m := &vpmemMapping{
+		hostPath: hostPath,
+		uvmPath:  uvmPath,
+		offset:   offset,
+		size:     size,
+		refCount: 1,
+	}
+	uvm.vpmemDevices[deviceNumber].mappings = insertVPMEMMapping(uvm.vpmemDevices[deviceNumber].mappings, m)
+	logrus.WithFields(logrus.Fields{
+		logfields.UVMID: uvm.id,
+		"host-path":     hostPath,
+		"uvm-path":      uvmPath,
+		"deviceNumber":  deviceNumber,
+		"offset":        offset,
+		"size":          size,
+	}).Debug("uvm::addVPMEMMapped")
+	return deviceNumber, uvmPath, nil
+}
+
+// removeVPMEMMapped releases a reference to the mapping `m` of
+// `deviceNumber`. The device itself is removed once it has no mappings left.
+//
+// The lock MUST be held when calling this function.
+func (uvm *UtilityVM) removeVPMEMMapped(deviceNumber uint32, m *vpmemMapping) error {
+	if m.refCount > 1 {
+		m.refCount--
+		return nil
+	}
+
+	modification := &hcsschema.ModifySettingRequest{
+		RequestType:  requesttype.Remove,
+		ResourcePath: fmt.Sprintf("VirtualMachine/Devices/VirtualPMem/Devices/%d/Mappings/%d", deviceNumber, m.offset),
+		GuestRequest: guestrequest.GuestRequest{
+			ResourceType: guestrequest.ResourceTypeVPMemDevice,
+			RequestType:  requesttype.Remove,
+			Settings: guestrequest.LCOWMappedVPMemDevice{
+				DeviceNumber: deviceNumber,
+				MountPath:    m.uvmPath,
+				MappingInfo: &guestrequest.LCOWVPMemMappingInfo{
+					DeviceOffsetInBytes: m.offset,
+					DeviceSizeInBytes:   m.size,
+				},
+			},
+		},
+	}
+	if err := uvm.Modify(modification); err != nil {
+		return err
+	}
+
+	vi := &uvm.vpmemDevices[deviceNumber]
+	for i, vm := range vi.mappings {
+		if vm == m {
+			vi.mappings = append(vi.mappings[:i], vi.mappings[i+1:]...)
+			break
+		}
+	}
+	if len(vi.mappings) == 0 {
+		return uvm.removeVPMEMMappedDevice(deviceNumber)
+	}
+	return nil
+}
+
+// removeVPMEMMappedDevice hot removes the now empty multi-mapped VPMem device
+// `deviceNumber` and frees its slot.
+//
+// The lock MUST be held when calling this function.
+func (uvm *UtilityVM) removeVPMEMMappedDevice(deviceNumber uint32) error {
+	modification := &hcsschema.ModifySettingRequest{
+		RequestType:  requesttype.Remove,
+		ResourcePath: fmt.Sprintf("VirtualMachine/Devices/VirtualPMem/Devices/%d", deviceNumber),
+	}
+	if err := uvm.Modify(modification); err != nil {
+		return err
+	}
+	uvm.vpmemDevices[deviceNumber] = vpmemInfo{}
+	return nil
+}
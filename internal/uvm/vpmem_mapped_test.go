@@ -0,0 +1,170 @@
+package uvm
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/cow/cowtest"
+	"github.com/Microsoft/hcsshim/internal/guestrequest"
+	"github.com/Microsoft/hcsshim/internal/schema1"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+)
+
+func Test_alignVPMEMMapping(t *testing.T) {
+	for _, tc := range []struct{ size, expected uint64 }{
+		{0, 0},
+		{1, 4096},
+		{4096, 4096},
+		{4097, 8192},
+	} {
+		if a := alignVPMEMMapping(tc.size); a != tc.expected {
+			t.Fatalf("expected %d aligned to %d, got: %d", tc.size, tc.expected, a)
+		}
+	}
+}
+
+func Test_findVPMEMMappingOffset_Empty(t *testing.T) {
+	offset, ok := findVPMEMMappingOffset(nil, 16384, 8192)
+	if !ok || offset != 0 {
+		t.Fatalf("expected offset 0, got: %d, %v", offset, ok)
+	}
+}
+
+func Test_findVPMEMMappingOffset_FirstFit(t *testing.T) {
+	mappings := []*vpmemMapping{
+		{offset: 0, size: 4096},
+		{offset: 8192, size: 4096},
+		{offset: 20480, size: 4096},
+	}
+	// The hole at 4096 is too small for 8192 bytes but the one at 12288 fits.
+	offset, ok := findVPMEMMappingOffset(mappings, 32768, 8192)
+	if !ok || offset != 12288 {
+		t.Fatalf("expected offset 12288, got: %d, %v", offset, ok)
+	}
+	offset, ok = findVPMEMMappingOffset(mappings, 32768, 4096)
+	if !ok || offset != 4096 {
+		t.Fatalf("expected offset 4096, got: %d, %v", offset, ok)
+	}
+	offset, ok = findVPMEMMappingOffset(mappings, 40960, 12288)
+	if !ok || offset != 24576 {
+		t.Fatalf("expected offset 24576, got: %d, %v", offset, ok)
+	}
+}
+
+func Test_findVPMEMMappingOffset_Full(t *testing.T) {
+	mappings := []*vpmemMapping{
+		{offset: 0, size: 8192},
+		{offset: 12288, size: 4096},
+	}
+	if offset, ok := findVPMEMMappingOffset(mappings, 16384, 8192); ok {
+		t.Fatalf("expected no space, got offset: %d", offset)
+	}
+}
+
+func Test_insertVPMEMMapping_Sorted(t *testing.T) {
+	var mappings []*vpmemMapping
+	for _, o := range []uint64{8192, 0, 4096, 12288} {
+		mappings = insertVPMEMMapping(mappings, &vpmemMapping{offset: o, size: 4096})
+	}
+	for i, m := range mappings {
+		if m.offset != uint64(i)*4096 {
+			t.Fatalf("expected mapping %d at offset %d, got: %d", i, i*4096, m.offset)
+		}
+	}
+}
+
+func Test_allocateVPMEM_SkipsMultiMappedDevices(t *testing.T) {
+	uvm := &UtilityVM{vpmemMaxCount: DefaultVPMEMCount}
+	uvm.vpmemDevices[0].mappings = []*vpmemMapping{{hostPath: `C:\layer0.vhd`, refCount: 1}}
+	deviceNumber, err := uvm.allocateVPMEM(`C:\layer1.vhd`)
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if deviceNumber != 1 {
+		t.Fatalf("expected device 1, got: %d", deviceNumber)
+	}
+	if n, m := uvm.findVPMEMMapping(`C:\layer0.vhd`); m == nil || n != 0 {
+		t.Fatalf("expected mapping on device 0, got: %d, %+v", n, m)
+	}
+}
+
+func writeTestVPMEMLayer(t *testing.T, size int64) string {
+	f, err := ioutil.TempFile("", "layer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := f.Truncate(size); err != nil {
+		os.Remove(f.Name())
+		t.Fatal(err)
+	}
+	return f.Name()
+}
+
+func newVPMEMMappedTestUVM(supported bool) (*UtilityVM, *cowtest.Container) {
+	c := cowtest.NewContainer("uvm", "linux", false)
+	return &UtilityVM{
+		operatingSystem:   "linux",
+		hcsSystem:         c,
+		vpmemMaxCount:     DefaultVPMEMCount,
+		vpmemMaxSizeBytes: DefaultVPMemSizeBytes,
+		vpmemMultiMapping: true,
+		guestCaps:         schema1.GuestDefinedCapabilities{VPMemMultiMappingSupported: supported},
+	}, c
+}
+
+func Test_AddVPMEM_MultiMapping_GuestRequest(t *testing.T) {
+	layer0 := writeTestVPMEMLayer(t, 5000)
+	defer os.Remove(layer0)
+	layer1 := writeTestVPMEMLayer(t, 4096)
+	defer os.Remove(layer1)
+
+	uvm, c := newVPMEMMappedTestUVM(true)
+	if _, _, err := uvm.AddVPMEM(layer0, true); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	deviceNumber, uvmPath, err := uvm.AddVPMEM(layer1, true)
+	if err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	if deviceNumber != 0 || uvmPath != "/tmp/p0-8192" {
+		t.Fatalf("expected second layer after the first in device 0, got: %d, %s", deviceNumber, uvmPath)
+	}
+	// The device is added once, then each layer is mapped into it.
+	modifies := c.Modifies()
+	if len(modifies) != 3 {
+		t.Fatalf("expected 3 modifies, got: %d", len(modifies))
+	}
+	mapping := modifies[2].(*hcsschema.ModifySettingRequest)
+	if mapping.ResourcePath != "VirtualMachine/Devices/VirtualPMem/Devices/0/Mappings/8192" {
+		t.Fatalf("unexpected resource path: %s", mapping.ResourcePath)
+	}
+	device := mapping.GuestRequest.(guestrequest.GuestRequest).Settings.(guestrequest.LCOWMappedVPMemDevice)
+	if device.DeviceNumber != 0 || device.MountPath != uvmPath {
+		t.Fatalf("unexpected guest request: %+v", device)
+	}
+	if device.MappingInfo == nil || device.MappingInfo.DeviceOffsetInBytes != 8192 || device.MappingInfo.DeviceSizeInBytes != 4096 {
+		t.Fatalf("expected the region of the layer in the device, got: %+v", device.MappingInfo)
+	}
+}
+
+func Test_AddVPMEM_MultiMapping_GuestUnsupported(t *testing.T) {
+	layer := writeTestVPMEMLayer(t, 4096)
+	defer os.Remove(layer)
+
+	uvm, c := newVPMEMMappedTestUVM(false)
+	if _, uvmPath, err := uvm.AddVPMEM(layer, true); err != nil || uvmPath != "/tmp/p0" {
+		t.Fatalf("expected the layer to take a whole device, got: %s, %v", uvmPath, err)
+	}
+	if _, m := uvm.findVPMEMMapping(layer); m != nil {
+		t.Fatal("expected no mapping without guest support")
+	}
+	modifies := c.Modifies()
+	if len(modifies) != 1 {
+		t.Fatalf("expected 1 modify, got: %d", len(modifies))
+	}
+	if device := modifies[0].(*hcsschema.ModifySettingRequest).GuestRequest.(guestrequest.GuestRequest).Settings.(guestrequest.LCOWMappedVPMemDevice); device.MappingInfo != nil {
+		t.Fatalf("expected no mapping info, got: %+v", device.MappingInfo)
+	}
+}
@@ -29,6 +29,20 @@ func (share *vsmbShare) GuestPath() string {
 	return `\\?\VMSMB\VSMB-{dcc079ae-60ba-4d07-847c-3493609c0870}\` + share.name
 }
 
+// DefaultVSMBOptions returns the default options used to share a host
+// directory with a Windows utility VM. Read-only shares use cached I/O and
+// shared read access as their contents cannot change under the guest.
+func DefaultVSMBOptions(readOnly bool) *hcsschema.VirtualSmbShareOptions {
+	options := &hcsschema.VirtualSmbShareOptions{}
+	if readOnly {
+		options.ReadOnly = true
+		options.CacheIo = true
+		options.ShareRead = true
+		options.ForceLevelIIOplocks = true
+	}
+	return options
+}
+
 // AddVSMB adds a VSMB share to a Windows utility VM. Each VSMB share is ref-counted and
 // only added if it isn't already. This is used for read-only layers, mapped directories
 // to a container, and for mapped pipes.
@@ -77,6 +91,7 @@ func (uvm *UtilityVM) AddVSMB(hostPath string, guestRequest interface{}, options
 		}
 		share = &vsmbShare{
 			name:         shareName,
+			options:      options,
 			guestRequest: guestRequest,
 		}
 		uvm.vsmbShares[hostPath] = share
@@ -0,0 +1,170 @@
+package uvmpool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AckTimeout is the time the server waits for a client to open a claimed
+// utility VM before terminating it.
+const AckTimeout = 2 * time.Minute
+
+// The claim protocol is a single exchange of JSON messages per connection:
+// the client sends a claimRequest, the server answers with a claimResponse
+// and, if a utility VM was claimed, the client sends a claimAck once it has
+// opened the utility VM. Only then does the server release its handle.
+type claimRequest struct {
+	Key string
+}
+
+type claimResponse struct {
+	ID             string `json:",omitempty"`
+	MemorySizeInMB int32  `json:",omitempty"`
+	Error          string `json:",omitempty"`
+}
+
+type claimAck struct {
+	Opened bool
+}
+
+// Server hands out the utility VMs of a set of pools to clients.
+type Server struct {
+	pools map[string]*Pool
+
+	m        sync.Mutex
+	listener net.Listener
+	closed   bool
+}
+
+// NewServer returns a server for `pools` indexed by their key.
+func NewServer(pools map[string]*Pool) *Server {
+	return &Server{pools: pools}
+}
+
+// Serve accepts client connections on `l` until the server is closed.
+func (s *Server) Serve(l net.Listener) error {
+	s.m.Lock()
+	if s.closed {
+		s.m.Unlock()
+		return ErrClosed
+	}
+	s.listener = l
+	s.m.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.m.Lock()
+			closed := s.closed
+			s.m.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		go s.handle(conn)
+	}
+}
+
+// Close stops accepting client connections. It does not close the pools.
+func (s *Server) Close() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.listener != nil {
+		return s.listener.Close()
+	}
+	return nil
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	log := logrus.WithField("remote", conn.RemoteAddr().String())
+
+	conn.SetDeadline(time.Now().Add(AckTimeout))
+	var req claimRequest
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		log.WithError(err).Warning("uvmpool: failed to read claim request")
+		return
+	}
+	log = log.WithField("key", req.Key)
+
+	var resp claimResponse
+	var item Item
+	if p, ok := s.pools[req.Key]; !ok {
+		resp.Error = "no pool for the utility VM configuration"
+	} else if i, err := p.Claim(); err != nil {
+		resp.Error = err.Error()
+	} else if i != nil {
+		item = i
+		resp.ID = item.ID()
+		resp.MemorySizeInMB = item.MemorySizeInMB()
+	}
+	if err := json.NewEncoder(conn).Encode(&resp); err != nil {
+		log.WithError(err).Warning("uvmpool: failed to write claim response")
+		if item != nil {
+			item.Close()
+		}
+		return
+	}
+	if item == nil {
+		return
+	}
+
+	log = log.WithField("id", item.ID())
+	var ack claimAck
+	if err := json.NewDecoder(conn).Decode(&ack); err != nil || !ack.Opened {
+		log.WithError(err).Warning("uvmpool: claimed utility VM was not opened, terminating it")
+		item.Close()
+		return
+	}
+	if err := item.Detach(); err != nil {
+		log.WithError(err).Warning("uvmpool: failed to detach claimed utility VM")
+		return
+	}
+	log.Info("uvmpool: utility VM claimed")
+}
+
+// Claim claims a utility VM from the pool for `key` over `conn` and calls
+// `open` with its ID and the memory assigned to it. The server releases the utility VM once `open` has
+// succeeded, or terminates it otherwise. Returns false if the pool had no
+// utility VM ready, in which case `open` is not called.
+func Claim(conn net.Conn, key string, open func(id string, memorySizeInMB int32) error) (bool, error) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(AckTimeout))
+
+	if err := json.NewEncoder(conn).Encode(&claimRequest{Key: key}); err != nil {
+		return false, fmt.Errorf("uvmpool: failed to send claim request: %s", err)
+	}
+	var resp claimResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return false, fmt.Errorf("uvmpool: failed to read claim response: %s", err)
+	}
+	if resp.Error != "" {
+		return false, errors.New("uvmpool: " + resp.Error)
+	}
+	if resp.ID == "" {
+		return false, nil
+	}
+
+	openErr := open(resp.ID, resp.MemorySizeInMB)
+	ack := claimAck{Opened: openErr == nil}
+	if err := json.NewEncoder(conn).Encode(&ack); err != nil && openErr == nil {
+		// The server terminates the utility VM if it does not receive the
+		// acknowledgement, so it cannot be used.
+		return false, fmt.Errorf("uvmpool: failed to acknowledge claim: %s", err)
+	}
+	if openErr != nil {
+		return false, openErr
+	}
+	return true, nil
+}
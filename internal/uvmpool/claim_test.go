@@ -0,0 +1,105 @@
+package uvmpool
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func newTestServer(t *testing.T, f *testFactory) (*Server, *Pool) {
+	p := New(Config{Size: 1, Create: f.Create})
+	waitFor(t, "pool to fill", func() bool { return p.Len() == 1 })
+	return NewServer(map[string]*Pool{"key": p}), p
+}
+
+func claimFromServer(s *Server, key string, open func(id string, memorySizeInMB int32) error) (bool, error) {
+	client, server := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		s.handle(server)
+		close(done)
+	}()
+	claimed, err := Claim(client, key, open)
+	<-done
+	return claimed, err
+}
+
+func Test_Claim(t *testing.T) {
+	f := &testFactory{}
+	s, p := newTestServer(t, f)
+	defer p.Close()
+
+	var (
+		opened       string
+		openedMemory int32
+	)
+	claimed, err := claimFromServer(s, "key", func(id string, memorySizeInMB int32) error {
+		opened = id
+		openedMemory = memorySizeInMB
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to claim: %s", err)
+	}
+	if !claimed || opened != "item0" {
+		t.Fatalf("expected to claim and open item0, got %t %q", claimed, opened)
+	}
+	if openedMemory != testItemMemorySizeInMB {
+		t.Fatalf("expected to open with %dMB of memory, got %d", testItemMemorySizeInMB, openedMemory)
+	}
+	item := f.Created()[0]
+	if !item.Detached() || item.Closed() {
+		t.Fatal("expected the claimed item to be detached and not closed")
+	}
+}
+
+func Test_Claim_OpenFailureTerminates(t *testing.T) {
+	f := &testFactory{}
+	s, p := newTestServer(t, f)
+	defer p.Close()
+
+	openErr := errors.New("failed to open")
+	claimed, err := claimFromServer(s, "key", func(id string, memorySizeInMB int32) error {
+		return openErr
+	})
+	if err != openErr || claimed {
+		t.Fatalf("expected open error, got %t %v", claimed, err)
+	}
+	item := f.Created()[0]
+	if item.Detached() || !item.Closed() {
+		t.Fatal("expected the claimed item to be closed and not detached")
+	}
+}
+
+func Test_Claim_Empty(t *testing.T) {
+	f := &testFactory{}
+	s, p := newTestServer(t, f)
+	defer p.Close()
+	// Drain the pool and stop it from being replenished.
+	f.SetFailing(true)
+	if item, _ := p.Claim(); item == nil {
+		t.Fatal("expected an item")
+	}
+
+	claimed, err := claimFromServer(s, "key", func(id string, memorySizeInMB int32) error {
+		t.Fatal("unexpected open of an empty pool")
+		return nil
+	})
+	if err != nil || claimed {
+		t.Fatalf("expected no claim, got %t %v", claimed, err)
+	}
+}
+
+func Test_Claim_UnknownKey(t *testing.T) {
+	f := &testFactory{}
+	s, p := newTestServer(t, f)
+	defer p.Close()
+
+	claimed, err := claimFromServer(s, "other", func(id string, memorySizeInMB int32) error {
+		t.Fatal("unexpected open of an unknown pool")
+		return nil
+	})
+	if err == nil || claimed {
+		t.Fatalf("expected error, got %t %v", claimed, err)
+	}
+}
@@ -0,0 +1,60 @@
+package uvmpool
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"github.com/Microsoft/hcsshim/internal/backend"
+	"github.com/Microsoft/hcsshim/internal/uvm"
+)
+
+// LCOWKey returns the key identifying the pool of LCOW utility VMs that
+// `opts` can claim from. Options that are unique to each utility VM, such as
+// its ID and owner, are not part of the key.
+func LCOWKey(opts *uvm.OptionsLCOW) (string, error) {
+	if opts.Options == nil {
+		return "", errors.New("uvmpool: missing utility VM options")
+	}
+	o := *opts
+	oo := *opts.Options
+	oo.ID = ""
+	oo.Owner = ""
+	o.Options = &oo
+	// The backend is not serialized with the options.
+	b, err := json.Marshal(&struct {
+		Backend string
+		Options *uvm.OptionsLCOW
+	}{
+		Backend: backend.OrDefault(opts.Backend).Name(),
+		Options: &o,
+	})
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:]), nil
+}
+
+// ValidateLCOW returns an error if utility VMs created with `opts` cannot be
+// pooled, that is handed over to another process via `uvm.OpenLCOW`.
+//
+// The output of the guest is forwarded over a connection the guest makes once
+// at boot to the process that created the utility VM, so it cannot follow a
+// pooled utility VM to the process that claims it.
+func ValidateLCOW(opts *uvm.OptionsLCOW) error {
+	switch {
+	case !opts.UseGuestConnection || opts.ExternalGuestConnection:
+		return errors.New("uvmpool: pooled utility VMs require the guest connection of the HCS")
+	case opts.ForwardStdout || opts.ForwardStderr:
+		return errors.New("uvmpool: pooled utility VMs cannot forward the output of the guest")
+	case opts.ConsolePipe != "":
+		return errors.New("uvmpool: pooled utility VMs cannot have a console pipe")
+	case len(opts.CPUGroupLogicalProcessors) > 0:
+		return errors.New("uvmpool: pooled utility VMs cannot create their own CPU group")
+	case opts.ReservationLimits != nil:
+		return errors.New("uvmpool: pooled utility VMs cannot reserve host resources")
+	}
+	return nil
+}
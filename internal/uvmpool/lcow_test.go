@@ -0,0 +1,64 @@
+package uvmpool
+
+import (
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/uvm"
+)
+
+func Test_LCOWKey(t *testing.T) {
+	a := uvm.NewDefaultOptionsLCOW("a", "shim")
+	b := uvm.NewDefaultOptionsLCOW("b", "pool")
+	keyA, err := LCOWKey(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyB, err := LCOWKey(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyA != keyB {
+		t.Fatal("expected the ID and owner not to be part of the key")
+	}
+	if a.ID != "a" || a.Owner != "shim" {
+		t.Fatal("expected the options not to be modified")
+	}
+
+	b.MemorySizeInMB *= 2
+	keyB, err = LCOWKey(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyA == keyB {
+		t.Fatal("expected options with different memory to have different keys")
+	}
+}
+
+func newPoolableOptionsLCOW() *uvm.OptionsLCOW {
+	opts := uvm.NewDefaultOptionsLCOW("", "")
+	opts.ForwardStderr = false
+	return opts
+}
+
+func Test_ValidateLCOW(t *testing.T) {
+	if err := ValidateLCOW(newPoolableOptionsLCOW()); err != nil {
+		t.Fatalf("expected the default options without output forwarding to be valid: %s", err)
+	}
+	tests := []struct {
+		name   string
+		modify func(*uvm.OptionsLCOW)
+	}{
+		{name: "ExternalGuestConnection", modify: func(o *uvm.OptionsLCOW) { o.ExternalGuestConnection = true }},
+		{name: "ConsolePipe", modify: func(o *uvm.OptionsLCOW) { o.ConsolePipe = uvm.ConsolePipePath("a") }},
+		{name: "CPUGroup", modify: func(o *uvm.OptionsLCOW) { o.CPUGroupLogicalProcessors = []uint32{0} }},
+		{name: "ForwardStdout", modify: func(o *uvm.OptionsLCOW) { o.ForwardStdout = true }},
+		{name: "ForwardStderr", modify: func(o *uvm.OptionsLCOW) { o.ForwardStderr = true }},
+	}
+	for _, test := range tests {
+		opts := newPoolableOptionsLCOW()
+		test.modify(opts)
+		if err := ValidateLCOW(opts); err == nil {
+			t.Fatalf("%s: expected error", test.name)
+		}
+	}
+}
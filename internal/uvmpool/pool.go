@@ -0,0 +1,209 @@
+// Package uvmpool keeps pools of pre-booted utility VMs so that pods can
+// claim a running utility VM rather than cold-boot one.
+package uvmpool
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultRetryInterval is the time a pool waits before booting another item
+// after booting an item failed.
+const DefaultRetryInterval = 10 * time.Second
+
+// ErrClosed is returned when claiming from a closed pool.
+var ErrClosed = errors.New("the pool is closed")
+
+// Item is a pre-booted utility VM held by a pool.
+type Item interface {
+	// ID returns the ID of the utility VM.
+	ID() string
+	// MemorySizeInMB returns the memory assigned to the utility VM.
+	MemorySizeInMB() int32
+	// Close terminates the utility VM.
+	Close() error
+	// Detach releases the utility VM to the process that claimed it without
+	// terminating it.
+	Detach() error
+}
+
+// Config is the configuration of a pool.
+type Config struct {
+	// Size is the number of items the pool keeps ready to be claimed.
+	Size int
+	// IdleTimeout is the time after the last claim after which the ready
+	// items are reaped. The pool is replenished again on the next claim. If 0
+	// items are never reaped.
+	IdleTimeout time.Duration
+	// RetryInterval is the time to wait before booting another item after
+	// booting an item failed. Defaults to `DefaultRetryInterval`.
+	RetryInterval time.Duration
+	// Create boots a new item.
+	Create func() (Item, error)
+}
+
+// Pool keeps up to `Config.Size` items ready to be claimed and replenishes
+// itself in the background as items are claimed.
+type Pool struct {
+	config Config
+
+	m            sync.Mutex
+	items        []Item
+	lastClaim    time.Time
+	idle         bool
+	closed       bool
+	wake         chan struct{}
+	done         chan struct{}
+	replenishing sync.WaitGroup
+}
+
+// New returns a pool for `config` and starts filling it in the background.
+func New(config Config) *Pool {
+	if config.RetryInterval == 0 {
+		config.RetryInterval = DefaultRetryInterval
+	}
+	p := &Pool{
+		config:    config,
+		lastClaim: time.Now(),
+		wake:      make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+	p.replenishing.Add(1)
+	go p.replenish()
+	return p
+}
+
+// Claim removes a ready item from the pool and returns it. If no item is
+// ready returns nil, in which case the caller is expected to boot its own.
+// Either way the pool is replenished in the background.
+func (p *Pool) Claim() (Item, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.closed {
+		return nil, ErrClosed
+	}
+	p.lastClaim = time.Now()
+	p.idle = false
+	p.signal()
+	if len(p.items) == 0 {
+		return nil, nil
+	}
+	item := p.items[0]
+	p.items = p.items[1:]
+	return item, nil
+}
+
+// Len returns the number of items ready to be claimed.
+func (p *Pool) Len() int {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return len(p.items)
+}
+
+// Close stops replenishing the pool and closes the items that are ready.
+func (p *Pool) Close() error {
+	p.m.Lock()
+	if p.closed {
+		p.m.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.done)
+	p.m.Unlock()
+
+	p.replenishing.Wait()
+	p.m.Lock()
+	items := p.items
+	p.items = nil
+	p.m.Unlock()
+	return closeItems(items)
+}
+
+// signal wakes the replenishing goroutine. Must be called with `p.m` held.
+func (p *Pool) signal() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// replenish boots items until the pool is full, and reaps the ready items once
+// the pool has been idle for `IdleTimeout`.
+func (p *Pool) replenish() {
+	defer p.replenishing.Done()
+	for {
+		wait := p.reconcile()
+		var timer <-chan time.Time
+		if wait > 0 {
+			timer = time.After(wait)
+		}
+		select {
+		case <-p.done:
+			return
+		case <-p.wake:
+		case <-timer:
+		}
+	}
+}
+
+// reconcile boots at most one item or reaps the idle items. It returns how
+// long to wait before reconciling again, or 0 to wait until signaled.
+func (p *Pool) reconcile() time.Duration {
+	p.m.Lock()
+	if p.closed {
+		p.m.Unlock()
+		return 0
+	}
+	var untilIdle time.Duration
+	if p.config.IdleTimeout > 0 && !p.idle {
+		untilIdle = p.config.IdleTimeout - time.Since(p.lastClaim)
+		if untilIdle <= 0 {
+			p.idle = true
+			items := p.items
+			p.items = nil
+			p.m.Unlock()
+			logrus.WithField("count", len(items)).Debug("uvmpool: reaping idle utility VMs")
+			if err := closeItems(items); err != nil {
+				logrus.WithError(err).Warning("uvmpool: failed to close idle utility VM")
+			}
+			return 0
+		}
+	}
+	if p.idle || len(p.items) >= p.config.Size {
+		p.m.Unlock()
+		return untilIdle
+	}
+	p.m.Unlock()
+
+	item, err := p.config.Create()
+	if err != nil {
+		logrus.WithError(err).Warning("uvmpool: failed to boot utility VM")
+		return p.config.RetryInterval
+	}
+
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.closed {
+		item.Close()
+		return 0
+	}
+	p.items = append(p.items, item)
+	logrus.WithField("id", item.ID()).Debug("uvmpool: utility VM ready")
+	// Keep booting until the pool is full.
+	p.signal()
+	return untilIdle
+}
+
+// closeItems closes all `items` and returns the first error.
+func closeItems(items []Item) error {
+	var firstErr error
+	for _, item := range items {
+		if err := item.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
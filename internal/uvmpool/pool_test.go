@@ -0,0 +1,163 @@
+package uvmpool
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testItemMemorySizeInMB = 1024
+
+type testItem struct {
+	id string
+
+	m        sync.Mutex
+	closed   bool
+	detached bool
+}
+
+func (i *testItem) ID() string {
+	return i.id
+}
+
+func (i *testItem) MemorySizeInMB() int32 {
+	return testItemMemorySizeInMB
+}
+
+func (i *testItem) Close() error {
+	i.m.Lock()
+	defer i.m.Unlock()
+	i.closed = true
+	return nil
+}
+
+func (i *testItem) Detach() error {
+	i.m.Lock()
+	defer i.m.Unlock()
+	i.detached = true
+	return nil
+}
+
+func (i *testItem) Detached() bool {
+	i.m.Lock()
+	defer i.m.Unlock()
+	return i.detached
+}
+
+func (i *testItem) Closed() bool {
+	i.m.Lock()
+	defer i.m.Unlock()
+	return i.closed
+}
+
+type testFactory struct {
+	m       sync.Mutex
+	items   []*testItem
+	failing bool
+}
+
+func (f *testFactory) Create() (Item, error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	if f.failing {
+		return nil, errors.New("failed to boot")
+	}
+	item := &testItem{id: fmt.Sprintf("item%d", len(f.items))}
+	f.items = append(f.items, item)
+	return item, nil
+}
+
+func (f *testFactory) Created() []*testItem {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return append([]*testItem(nil), f.items...)
+}
+
+func (f *testFactory) SetFailing(failing bool) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.failing = failing
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func Test_Pool_FillsAndReplenishes(t *testing.T) {
+	f := &testFactory{}
+	p := New(Config{Size: 2, Create: f.Create})
+	defer p.Close()
+
+	waitFor(t, "pool to fill", func() bool { return p.Len() == 2 })
+	item, err := p.Claim()
+	if err != nil {
+		t.Fatalf("failed to claim: %s", err)
+	}
+	if item == nil || item.ID() != "item0" {
+		t.Fatalf("expected to claim item0, got %v", item)
+	}
+	waitFor(t, "pool to replenish", func() bool { return len(f.Created()) == 3 && p.Len() == 2 })
+}
+
+func Test_Pool_ClaimEmpty(t *testing.T) {
+	f := &testFactory{failing: true}
+	p := New(Config{Size: 1, RetryInterval: time.Millisecond, Create: f.Create})
+	defer p.Close()
+
+	item, err := p.Claim()
+	if err != nil {
+		t.Fatalf("failed to claim: %s", err)
+	}
+	if item != nil {
+		t.Fatalf("expected no item from an empty pool, got %s", item.ID())
+	}
+	f.SetFailing(false)
+	waitFor(t, "pool to recover after boot failures", func() bool { return p.Len() == 1 })
+}
+
+func Test_Pool_IdleTimeoutReaps(t *testing.T) {
+	f := &testFactory{}
+	p := New(Config{Size: 1, IdleTimeout: 50 * time.Millisecond, Create: f.Create})
+	defer p.Close()
+
+	waitFor(t, "pool to fill", func() bool { return p.Len() == 1 })
+	waitFor(t, "pool to reap", func() bool { return p.Len() == 0 })
+	if !f.Created()[0].Closed() {
+		t.Fatal("expected the reaped item to be closed")
+	}
+	// Stay idle until the next claim.
+	time.Sleep(20 * time.Millisecond)
+	if n := len(f.Created()); n != 1 {
+		t.Fatalf("expected an idle pool not to boot items, booted %d", n)
+	}
+	if item, _ := p.Claim(); item != nil {
+		t.Fatalf("expected no item from an idle pool, got %s", item.ID())
+	}
+	waitFor(t, "pool to refill after a claim", func() bool { return p.Len() == 1 })
+}
+
+func Test_Pool_Close(t *testing.T) {
+	f := &testFactory{}
+	p := New(Config{Size: 2, Create: f.Create})
+
+	waitFor(t, "pool to fill", func() bool { return p.Len() == 2 })
+	if err := p.Close(); err != nil {
+		t.Fatalf("failed to close pool: %s", err)
+	}
+	for _, item := range f.Created() {
+		if !item.Closed() {
+			t.Fatalf("expected %s to be closed", item.ID())
+		}
+	}
+	if _, err := p.Claim(); err != ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
@@ -27,3 +27,27 @@ func CreateUVMScratch(imagePath, destDirectory, vmID string) error {
 	}
 	return nil
 }
+
+// CloneUVMScratch is a helper to create the scratch of a Windows utility VM
+// cloned from a template as a differencing disk of the scratch
+// `templateScratch` of the template, with permissions to the specified VM ID
+// in a specified directory. The template scratch is never written to so it
+// MUST NOT be removed while any clone is running.
+func CloneUVMScratch(templateScratch, destDirectory, vmID string) error {
+	targetScratch := filepath.Join(destDirectory, "sandbox.vhdx")
+	logrus.WithFields(logrus.Fields{
+		"target": targetScratch,
+		"parent": templateScratch,
+	}).Debug("uvm::CloneUVMScratch")
+	if err := createDiffVhdx(targetScratch, templateScratch); err != nil {
+		return err
+	}
+	// The clone reads the unchanged blocks from the template scratch.
+	for _, p := range []string{templateScratch, targetScratch} {
+		if err := wclayer.GrantVmAccess(vmID, p); err != nil {
+			os.Remove(targetScratch)
+			return err
+		}
+	}
+	return nil
+}
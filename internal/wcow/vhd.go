@@ -0,0 +1,65 @@
+package wcow
+
+import (
+	"syscall"
+)
+
+//go:generate go run ../../mksyscall_windows.go -output zsyscall_windows.go vhd.go
+
+//sys createVirtualDisk(virtualStorageType *virtualStorageType, path string, virtualDiskAccessMask uint32, securityDescriptor *uintptr, flags uint32, providerSpecificFlags uint32, parameters *createVirtualDiskParameters, o *syscall.Overlapped, handle *syscall.Handle) (err error) [failretval != 0] = VirtDisk.CreateVirtualDisk
+
+type virtualStorageType struct {
+	DeviceID uint32
+	VendorID [16]byte
+}
+
+type createVersion2 struct {
+	UniqueID                 [16]byte // GUID
+	MaximumSize              uint64
+	BlockSizeInBytes         uint32
+	SectorSizeInBytes        uint32
+	ParentPath               *uint16 // string
+	SourcePath               *uint16 // string
+	OpenFlags                uint32
+	ParentVirtualStorageType virtualStorageType
+	SourceVirtualStorageType virtualStorageType
+	ResiliencyGUID           [16]byte // GUID
+}
+
+type createVirtualDiskParameters struct {
+	Version  uint32 // Must always be set to 2
+	Version2 createVersion2
+}
+
+// createDiffVhdx creates the differencing VHDX `path` backed by the VHDX
+// `parentPath`. Writes go to `path` so `parentPath` is never modified and can
+// back any number of differencing disks.
+func createDiffVhdx(path, parentPath string) error {
+	parent, err := syscall.UTF16PtrFromString(parentPath)
+	if err != nil {
+		return err
+	}
+	var (
+		defaultType virtualStorageType
+		handle      syscall.Handle
+	)
+	parameters := createVirtualDiskParameters{
+		Version: 2,
+		Version2: createVersion2{
+			ParentPath: parent,
+		},
+	}
+	if err := createVirtualDisk(
+		&defaultType,
+		path,
+		0,
+		nil,
+		0,
+		0,
+		&parameters,
+		nil,
+		&handle); err != nil {
+		return err
+	}
+	return syscall.CloseHandle(handle)
+}
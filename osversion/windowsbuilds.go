@@ -20,4 +20,15 @@ const (
 	// RS5 (version 1809, codename "Redstone 5") corresponds to Windows Server
 	// 2019 (ltsc2019), and Windows 10 (October 2018 Update).
 	RS5 = 17763
+
+	// V19H1 (version 1903) corresponds to Windows Server 1903 (semi-annual
+	// channel) and Windows 10 (May 2019 Update).
+	V19H1 = 18362
+
+	// V20H1 (version 2004) corresponds to Windows Server 2004 (semi-annual
+	// channel) and Windows 10 (May 2020 Update).
+	V20H1 = 19041
+
+	// V21H2Server corresponds to Windows Server 2022 (ltsc2022).
+	V21H2Server = 20348
 )
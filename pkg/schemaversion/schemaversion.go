@@ -0,0 +1,130 @@
+// Package schemaversion determines which HCS schema version, and which shape
+// of compute system document, to use for a given Windows build and set of
+// requested features.
+package schemaversion
+
+import (
+	"fmt"
+
+	"github.com/Microsoft/hcsshim/osversion"
+)
+
+// Version is an HCS schema version.
+type Version struct {
+	Major int32
+	Minor int32
+}
+
+var (
+	// V10 is the v1.0 schema. This was the only schema supported in RS1..RS4.
+	// It lives on in RS5 but will be deprecated in a future release.
+	V10 = Version{Major: 1, Minor: 0}
+
+	// V21 is the v2.1 schema. Recommended for RS5 onwards.
+	V21 = Version{Major: 2, Minor: 1}
+)
+
+// String returns the version in the form "Major.Minor".
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// DocumentShape is the form of the document that must be passed to create a
+// compute system for a given schema version and set of features.
+type DocumentShape int
+
+const (
+	// ContainerConfigV1 is a v1 ContainerConfig passed directly to the HCS.
+	// Used for both process and hypervisor isolated containers.
+	ContainerConfigV1 DocumentShape = iota
+	// ComputeSystemV2 is a v2 ComputeSystem document wrapping the container
+	// passed directly to the HCS.
+	ComputeSystemV2
+	// HostedSystemV2 is a v2 HostedSystem document passed to a utility VM
+	// that hosts the container.
+	HostedSystemV2
+)
+
+// String returns the name of the document shape.
+func (s DocumentShape) String() string {
+	switch s {
+	case ContainerConfigV1:
+		return "ContainerConfigV1"
+	case ComputeSystemV2:
+		return "ComputeSystemV2"
+	case HostedSystemV2:
+		return "HostedSystemV2"
+	}
+	return fmt.Sprintf("DocumentShape(%d)", int(s))
+}
+
+// Features are the features requested of a compute system that influence
+// which schema version can be used.
+type Features struct {
+	// LCOW requests a Linux container. Requires v2.1.
+	LCOW bool
+	// HostedInUtilityVM requests that the container be created inside a
+	// utility VM managed by the caller rather than by the HCS. Requires v2.1.
+	HostedInUtilityVM bool
+	// NetworkNamespace requests that the container join an HNS network
+	// namespace. Requires v2.1.
+	NetworkNamespace bool
+}
+
+// requiresV21 returns true if any of the features are only available in the
+// v2.1 schema.
+func (f Features) requiresV21() bool {
+	return f.LCOW || f.HostedInUtilityVM || f.NetworkNamespace
+}
+
+// IsSupported returns nil if `v` is supported on Windows `build`.
+func IsSupported(build uint16, v Version) error {
+	switch v {
+	case V10:
+		return nil
+	case V21:
+		if build < osversion.RS5 {
+			return fmt.Errorf("schema version %s is unsupported on Windows build %d", v, build)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown schema version %s", v)
+}
+
+// Default returns the preferred schema version for Windows `build`.
+func Default(build uint16) Version {
+	if build >= osversion.RS5 {
+		return V21
+	}
+	return V10
+}
+
+// Determine returns the schema version and document shape to use to create a
+// compute system with `features` on Windows `build`.
+//
+// If `requested` is not nil it is used in preference to the default for the
+// build, and an error is returned if it is not supported on the build or
+// cannot provide `features`.
+func Determine(build uint16, requested *Version, features Features) (Version, DocumentShape, error) {
+	v := Default(build)
+	if requested != nil {
+		if err := IsSupported(build, *requested); err != nil {
+			return Version{}, 0, err
+		}
+		v = *requested
+	}
+	if features.requiresV21() && v != V21 {
+		if err := IsSupported(build, V21); err != nil {
+			return Version{}, 0, fmt.Errorf("requested features require schema version %s: %s", V21, err)
+		}
+		return Version{}, 0, fmt.Errorf("requested features require schema version %s, not %s", V21, v)
+	}
+	switch {
+	case v == V10:
+		return v, ContainerConfigV1, nil
+	case features.HostedInUtilityVM:
+		return v, HostedSystemV2, nil
+	default:
+		return v, ComputeSystemV2, nil
+	}
+}
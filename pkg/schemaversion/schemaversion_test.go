@@ -0,0 +1,50 @@
+package schemaversion
+
+import (
+	"testing"
+
+	"github.com/Microsoft/hcsshim/osversion"
+)
+
+func TestDetermine(t *testing.T) {
+	v10 := V10
+	v21 := V21
+	unknown := Version{Major: 3}
+
+	tests := []struct {
+		name      string
+		build     uint16
+		requested *Version
+		features  Features
+		version   Version
+		shape     DocumentShape
+		err       bool
+	}{
+		{name: "RS4 default", build: osversion.RS4, version: V10, shape: ContainerConfigV1},
+		{name: "RS5 default", build: osversion.RS5, version: V21, shape: ComputeSystemV2},
+		{name: "RS5 requested v1", build: osversion.RS5, requested: &v10, version: V10, shape: ContainerConfigV1},
+		{name: "RS4 requested v2", build: osversion.RS4, requested: &v21, err: true},
+		{name: "unknown requested", build: osversion.RS5, requested: &unknown, err: true},
+		{name: "RS5 hosted", build: osversion.RS5, features: Features{HostedInUtilityVM: true}, version: V21, shape: HostedSystemV2},
+		{name: "RS5 LCOW", build: osversion.RS5, features: Features{LCOW: true}, version: V21, shape: ComputeSystemV2},
+		{name: "RS4 LCOW", build: osversion.RS4, features: Features{LCOW: true}, err: true},
+		{name: "RS5 v1 with network namespace", build: osversion.RS5, requested: &v10, features: Features{NetworkNamespace: true}, err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v, shape, err := Determine(test.build, test.requested, test.features)
+			if test.err {
+				if err == nil {
+					t.Fatalf("expected error, got %s %s", v, shape)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v != test.version || shape != test.shape {
+				t.Fatalf("expected %s %s, got %s %s", test.version, test.shape, v, shape)
+			}
+		})
+	}
+}
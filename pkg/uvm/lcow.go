@@ -0,0 +1,124 @@
+package uvm
+
+import (
+	"io"
+
+	"github.com/Microsoft/hcsshim/internal/lcow"
+)
+
+// DefaultScratchSizeGB is the size of the default LCOW scratch disk in GB.
+const DefaultScratchSizeGB = lcow.DefaultScratchSizeGB
+
+// CreateScratch uses the LCOW utility VM to create an empty ext4 formatted
+// scratch VHDX of `sizeGB` at `destFile`.
+//
+// If `cacheFile` is not empty and `sizeGB` is `DefaultScratchSizeGB` the
+// scratch is copied from `cacheFile` when it exists, and `cacheFile` is
+// seeded from the newly created scratch when it does not. It is the
+// responsibility of the caller to synchronise simultaneous attempts to create
+// the cache file.
+func (u *UtilityVM) CreateScratch(destFile string, sizeGB uint32, cacheFile string) error {
+	return lcow.CreateScratch(u.vm, destFile, sizeGB, cacheFile)
+}
+
+// FormatOptions are the options for formatting a disk with an LCOW utility VM.
+type FormatOptions struct {
+	// Filesystem is the filesystem to format the disk with, either "ext4" or
+	// "xfs". Defaults to "ext4". An xfs container scratch is only mounted as
+	// such by a utility VM created with the
+	// `io.microsoft.virtualmachine.storage.scratch.filesystem` annotation set
+	// to "xfs".
+	Filesystem string
+	// InodeRatio, if not `0`, creates an inode for every this many bytes of
+	// the disk. Only supported for ext4.
+	InodeRatio uint32
+	// EnableJournal creates the ext4 filesystem with a journal. xfs is always
+	// journaled.
+	EnableJournal bool
+	// MkfsOptions are additional arguments passed to mkfs.
+	MkfsOptions []string
+	// CacheDir, if not empty, is the directory `CreateScratchWithOptions`
+	// caches scratch disks of every size in, keyed by their size and
+	// filesystem, in place of its cache file. Scratch disks formatted with
+	// non-default filesystem options are not cached. Ignored by `FormatDisk`.
+	CacheDir string
+}
+
+func (opts *FormatOptions) toInternal() *lcow.FormatOptions {
+	if opts == nil {
+		return nil
+	}
+	return &lcow.FormatOptions{
+		Filesystem:    lcow.Filesystem(opts.Filesystem),
+		InodeRatio:    opts.InodeRatio,
+		EnableJournal: opts.EnableJournal,
+		MkfsOptions:   opts.MkfsOptions,
+		CacheDir:      opts.CacheDir,
+	}
+}
+
+// CreateScratchWithOptions is `CreateScratch` formatting the scratch with
+// `opts`. `cacheFile` is only used if `opts` is the default ext4 format and
+// `opts.CacheDir` is not set.
+func (u *UtilityVM) CreateScratchWithOptions(destFile string, sizeGB uint32, cacheFile string, opts *FormatOptions) error {
+	return lcow.CreateScratchWithOptions(u.vm, destFile, sizeGB, cacheFile, opts.toInternal())
+}
+
+// FormatDisk uses the LCOW utility VM to format the existing VHD(x) at
+// `hostPath` with `opts`. The disk must not already be attached to the utility
+// VM.
+func (u *UtilityVM) FormatDisk(hostPath string, opts *FormatOptions) error {
+	return lcow.FormatDiskWithOptions(u.vm, hostPath, opts.toInternal())
+}
+
+// FormatExt4 uses the LCOW utility VM to format the existing VHD(x) at
+// `hostPath` as ext4. The disk must not already be attached to the utility VM.
+func (u *UtilityVM) FormatExt4(hostPath string) error {
+	return lcow.FormatDisk(u.vm, hostPath)
+}
+
+// CreateLayerVHD converts the OCI layer tar stream `r` into an ext4 formatted
+// VHD at `destFile` that can be attached read-only to an LCOW utility VM. No
+// utility VM is required. Returns the dm-verity root digest of the layer that
+// the caller MUST keep and pass in the
+// `io.microsoft.container.storage.layer.rootdigests` annotation of the
+// containers using the layer for it to be verified.
+func CreateLayerVHD(r io.Reader, destFile string) (string, error) {
+	return lcow.CreateLayerVHD(r, destFile)
+}
+
+// ScratchPool keeps pre-formatted LCOW scratch disks ready on disk so that
+// creating a scratch disk does not wait for the utility VM to format it.
+type ScratchPool struct {
+	p *lcow.ScratchPool
+}
+
+// NewScratchPool returns a pool keeping `count` scratch disks of each size and
+// filesystem ready in `dir`, formatted by the LCOW utility VM. The utility VM
+// must not be closed before the pool.
+func (u *UtilityVM) NewScratchPool(dir string, count int) (*ScratchPool, error) {
+	p, err := lcow.NewScratchPool(u.vm, dir, count)
+	if err != nil {
+		return nil, err
+	}
+	return &ScratchPool{p: p}, nil
+}
+
+// Prime fills the pool of scratch disks of `sizeGB` formatted with `opts` in
+// the background.
+func (p *ScratchPool) Prime(sizeGB uint32, opts *FormatOptions) error {
+	return p.p.Prime(sizeGB, opts.toInternal())
+}
+
+// CreateScratch creates the scratch disk `destFile` of `sizeGB` formatted with
+// `opts` from the pool, or formats it if the pool has none ready. Scratch
+// disks formatted with non-default filesystem options are never pooled.
+func (p *ScratchPool) CreateScratch(destFile string, sizeGB uint32, opts *FormatOptions) error {
+	return p.p.CreateScratch(destFile, sizeGB, opts.toInternal())
+}
+
+// Close stops refilling the pool. Ready disks are left for the next pool of
+// the same directory.
+func (p *ScratchPool) Close() error {
+	return p.p.Close()
+}
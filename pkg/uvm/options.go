@@ -0,0 +1,312 @@
+package uvm
+
+import (
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	iuvm "github.com/Microsoft/hcsshim/internal/uvm"
+)
+
+// RootFSType is the type of root file system an LCOW utility VM boots from.
+type RootFSType int
+
+const (
+	// RootFSTypeInitRd boots the utility VM from an initrd image.
+	RootFSTypeInitRd RootFSType = iota
+	// RootFSTypeVHD boots the utility VM from a VHD attached over VPMem.
+	RootFSTypeVHD
+)
+
+// HvSocketServiceConfig is the security of an HvSocket service of a utility VM.
+type HvSocketServiceConfig struct {
+	// BindSecurityDescriptor is the SDDL string of the host processes allowed
+	// to bind to the service. If empty the default of the utility VM is used.
+	BindSecurityDescriptor string
+
+	// ConnectSecurityDescriptor is the SDDL string of the host processes
+	// allowed to connect to the service. If empty the default of the utility
+	// VM is used.
+	ConnectSecurityDescriptor string
+
+	// AllowWildcardBinds allows wildcard binds to the service.
+	AllowWildcardBinds bool
+}
+
+// Options are the set of options common to creating both LCOW and WCOW
+// utility VMs.
+type Options struct {
+	// ID is the identifier for the utility VM. If empty a GUID is generated.
+	ID string
+
+	// Owner is the owner of the utility VM. If empty defaults to the calling
+	// executables name.
+	Owner string
+
+	// MemorySizeInMB sets the utility VM memory. If `0` will default to the
+	// platform default.
+	MemorySizeInMB int32
+
+	// AllowOvercommit backs the utility VM memory with virtual memory. Set to
+	// false for physically backed memory.
+	AllowOvercommit bool
+
+	// EnableDeferredCommit enables deferred commit of virtual memory. Only
+	// valid when `AllowOvercommit` is true.
+	EnableDeferredCommit bool
+
+	// EnableHotHint lets the guest hint which of its memory is in use so that
+	// it is backed first. Ignored for physically backed memory.
+	EnableHotHint bool
+
+	// EnableColdHint lets the guest hint which of its memory is free so that
+	// it can be trimmed. Ignored for physically backed memory.
+	EnableColdHint bool
+
+	// ProcessorCount sets the number of vCPU's. If `0` will default to the
+	// platform default.
+	ProcessorCount int32
+
+	// ProcessorLimit sets the maximum percentage of each vCPU the utility VM
+	// can consume. If `0` will default to the platform default.
+	ProcessorLimit int32
+
+	// ProcessorWeight sets the relative weight of these vCPU's vs another
+	// utility VM's when scheduling. If `0` will default to the platform
+	// default.
+	ProcessorWeight int32
+
+	// StorageQoSIopsMaximum sets the maximum number of Iops. If `0` will
+	// default to the platform default.
+	StorageQoSIopsMaximum int32
+
+	// StorageQoSBandwidthMaximum sets the maximum number of bytes per second.
+	// If `0` will default to the platform default.
+	StorageQoSBandwidthMaximum int32
+
+	// NumaNodeCount, if not `0`, exposes this many virtual NUMA nodes to the
+	// utility VM rather than a flat topology. There can be no more nodes than
+	// vCPU's and `AllowOvercommit` MUST be false. Only supported on Windows
+	// Server 2022 and newer hosts.
+	NumaNodeCount uint32
+
+	// NumaMemorySizePerNodeInMB, if not `0`, is the maximum memory of each
+	// virtual NUMA node. If `0` the memory is split evenly across the nodes.
+	NumaMemorySizePerNodeInMB uint64
+
+	// SCSIControllerCount is the number of SCSI controllers of the utility VM,
+	// each of which can attach 64 disks. Defaults to 1. WCOW utility VMs MUST
+	// have at least one.
+	SCSIControllerCount uint32
+
+	// ConsoleLogPath, if set, captures the output of the serial console of the
+	// utility VM to this host file. The console pipe MUST be set. The file is
+	// removed when the utility VM is closed while running and kept if it
+	// failed to start or exited on its own.
+	ConsoleLogPath string
+
+	// GuestCrashDumpPath, if set, writes a dump of the guest kernel to this
+	// host file if the guest crashes. For LCOW the console pipe MUST be set.
+	GuestCrashDumpPath string
+
+	// HvSocketServiceTable, if set, registers these HvSocket services keyed by
+	// their service GUID with the utility VM when it is created, so that only
+	// the host processes allowed by their security descriptors can bind or
+	// connect to them.
+	HvSocketServiceTable map[string]HvSocketServiceConfig
+}
+
+// OptionsLCOW are the set of options passed to CreateLCOW.
+type OptionsLCOW struct {
+	Options
+
+	// BootFilesPath is the folder in which the kernel and root file system
+	// reside.
+	BootFilesPath string
+
+	// KernelFile is the file name under `BootFilesPath` of the kernel.
+	KernelFile string
+
+	// KernelDirect skips UEFI and boots directly into `KernelFile`.
+	KernelDirect bool
+
+	// RootFSFile is the file name under `BootFilesPath` of the root file
+	// system.
+	RootFSFile string
+
+	// RootFSType is the type of `RootFSFile`.
+	RootFSType RootFSType
+
+	// KernelBootOptions are additional boot options for the kernel.
+	KernelBootOptions string
+
+	// ConsolePipe is the named pipe path to use for the serial console. eg
+	// \\.\pipe\vmpipe
+	ConsolePipe string
+
+	// VPMemDeviceCount is the number of VPMem devices. If booting from a VHD
+	// device 0 is taken.
+	VPMemDeviceCount uint32
+
+	// VPMemSizeBytes is the maximum size of each VPMem device.
+	VPMemSizeBytes uint64
+}
+
+// OptionsWCOW are the set of options passed to CreateWCOW.
+type OptionsWCOW struct {
+	Options
+
+	// LayerFolders is the set of folders for the base layers and scratch.
+	// Ordered from top most read-only through base read-only layer, followed
+	// by scratch.
+	LayerFolders []string
+
+	// SaveStateFilePath, if set, creates the utility VM by restoring the state
+	// saved to this file via `UtilityVM.Save` rather than booting. The utility
+	// VM MUST be created with the same options and layer folders as the saved
+	// utility VM.
+	SaveStateFilePath string
+
+	// ConsolePipe is the named pipe path to use for the serial console. eg
+	// \\.\pipe\vmpipe
+	ConsolePipe string
+}
+
+// NewDefaultOptionsLCOW creates the default options for a bootable LCOW
+// utility VM.
+//
+// `id` the ID of the utility VM. If not passed will generate a new GUID.
+//
+// `owner` the owner of the utility VM. If not passed will use the executable
+// files name.
+func NewDefaultOptionsLCOW(id, owner string) *OptionsLCOW {
+	iopts := iuvm.NewDefaultOptionsLCOW(id, owner)
+	opts := &OptionsLCOW{
+		Options:           optionsFromInternal(iopts.Options),
+		BootFilesPath:     iopts.BootFilesPath,
+		KernelFile:        iopts.KernelFile,
+		KernelDirect:      iopts.KernelDirect,
+		RootFSFile:        iopts.RootFSFile,
+		RootFSType:        RootFSTypeInitRd,
+		KernelBootOptions: iopts.KernelBootOptions,
+		ConsolePipe:       iopts.ConsolePipe,
+		VPMemDeviceCount:  iopts.VPMemDeviceCount,
+		VPMemSizeBytes:    iopts.VPMemSizeBytes,
+	}
+	if iopts.PreferredRootFSType == iuvm.PreferredRootFSTypeVHD {
+		opts.RootFSType = RootFSTypeVHD
+	}
+	return opts
+}
+
+// NewDefaultOptionsWCOW creates the default options for a bootable WCOW
+// utility VM. The caller `MUST` set `LayerFolders` on the returned value.
+//
+// `id` the ID of the utility VM. If not passed will generate a new GUID.
+//
+// `owner` the owner of the utility VM. If not passed will use the executable
+// files name.
+func NewDefaultOptionsWCOW(id, owner string) *OptionsWCOW {
+	iopts := iuvm.NewDefaultOptionsWCOW(id, owner)
+	return &OptionsWCOW{
+		Options: optionsFromInternal(iopts.Options),
+	}
+}
+
+func optionsFromInternal(iopts *iuvm.Options) Options {
+	return Options{
+		ID:                         iopts.ID,
+		Owner:                      iopts.Owner,
+		MemorySizeInMB:             iopts.MemorySizeInMB,
+		AllowOvercommit:            iopts.AllowOvercommit,
+		EnableDeferredCommit:       iopts.EnableDeferredCommit,
+		EnableHotHint:              iopts.EnableHotHint,
+		EnableColdHint:             iopts.EnableColdHint,
+		ProcessorCount:             iopts.ProcessorCount,
+		ProcessorLimit:             iopts.ProcessorLimit,
+		ProcessorWeight:            iopts.ProcessorWeight,
+		StorageQoSIopsMaximum:      iopts.StorageQoSIopsMaximum,
+		StorageQoSBandwidthMaximum: iopts.StorageQoSBandwidthMaximum,
+		NumaNodeCount:              iopts.NumaNodeCount,
+		NumaMemorySizePerNodeInMB:  iopts.NumaMemorySizePerNodeInMB,
+		SCSIControllerCount:        iopts.SCSIControllerCount,
+		ConsoleLogPath:             iopts.ConsoleLogPath,
+		GuestCrashDumpPath:         iopts.GuestCrashDumpPath,
+	}
+}
+
+// applyTo copies the common options onto `iopts`.
+func (opts *Options) applyTo(iopts *iuvm.Options) {
+	iopts.ID = opts.ID
+	if opts.Owner != "" {
+		iopts.Owner = opts.Owner
+	}
+	iopts.MemorySizeInMB = opts.MemorySizeInMB
+	iopts.AllowOvercommit = opts.AllowOvercommit
+	iopts.EnableDeferredCommit = opts.EnableDeferredCommit
+	iopts.EnableHotHint = opts.EnableHotHint
+	iopts.EnableColdHint = opts.EnableColdHint
+	iopts.ProcessorCount = opts.ProcessorCount
+	iopts.ProcessorLimit = opts.ProcessorLimit
+	iopts.ProcessorWeight = opts.ProcessorWeight
+	iopts.StorageQoSIopsMaximum = opts.StorageQoSIopsMaximum
+	iopts.StorageQoSBandwidthMaximum = opts.StorageQoSBandwidthMaximum
+	iopts.NumaNodeCount = opts.NumaNodeCount
+	iopts.NumaMemorySizePerNodeInMB = opts.NumaMemorySizePerNodeInMB
+	iopts.SCSIControllerCount = opts.SCSIControllerCount
+	iopts.ConsoleLogPath = opts.ConsoleLogPath
+	iopts.GuestCrashDumpPath = opts.GuestCrashDumpPath
+	for id, service := range opts.HvSocketServiceTable {
+		if iopts.HvSocketServiceTable == nil {
+			iopts.HvSocketServiceTable = make(map[string]hcsschema.HvSocketServiceConfig)
+		}
+		iopts.HvSocketServiceTable[id] = hcsschema.HvSocketServiceConfig{
+			BindSecurityDescriptor:    service.BindSecurityDescriptor,
+			ConnectSecurityDescriptor: service.ConnectSecurityDescriptor,
+			AllowWildcardBinds:        service.AllowWildcardBinds,
+		}
+	}
+}
+
+// toInternal converts `opts` to the internal LCOW options, keeping the
+// internal defaults for any settings that are not part of the public surface.
+func (opts *OptionsLCOW) toInternal() *iuvm.OptionsLCOW {
+	iopts := iuvm.NewDefaultOptionsLCOW(opts.ID, opts.Owner)
+	opts.Options.applyTo(iopts.Options)
+	iopts.BootFilesPath = opts.BootFilesPath
+	iopts.KernelFile = opts.KernelFile
+	iopts.KernelDirect = opts.KernelDirect
+	iopts.RootFSFile = opts.RootFSFile
+	switch opts.RootFSType {
+	case RootFSTypeVHD:
+		iopts.PreferredRootFSType = iuvm.PreferredRootFSTypeVHD
+	default:
+		iopts.PreferredRootFSType = iuvm.PreferredRootFSTypeInitRd
+	}
+	iopts.KernelBootOptions = opts.KernelBootOptions
+	iopts.ConsolePipe = opts.ConsolePipe
+	iopts.VPMemDeviceCount = opts.VPMemDeviceCount
+	iopts.VPMemSizeBytes = opts.VPMemSizeBytes
+	return iopts
+}
+
+// toInternal converts `opts` to the internal WCOW options.
+func (opts *OptionsWCOW) toInternal() *iuvm.OptionsWCOW {
+	iopts := iuvm.NewDefaultOptionsWCOW(opts.ID, opts.Owner)
+	opts.Options.applyTo(iopts.Options)
+	iopts.LayerFolders = opts.LayerFolders
+	iopts.SaveStateFilePath = opts.SaveStateFilePath
+	iopts.ConsolePipe = opts.ConsolePipe
+	return iopts
+}
+
+// UpdateBootFilesPath sets `BootFilesPath` to `path` and selects the default
+// boot files found in it for the boot method in `KernelDirect`.
+func (opts *OptionsLCOW) UpdateBootFilesPath(path string) {
+	iopts := &iuvm.OptionsLCOW{KernelDirect: opts.KernelDirect}
+	iopts.UpdateBootFilesPath(path)
+	opts.BootFilesPath = iopts.BootFilesPath
+	opts.KernelFile = iopts.KernelFile
+	opts.RootFSFile = iopts.RootFSFile
+	opts.RootFSType = RootFSTypeInitRd
+	if iopts.PreferredRootFSType == iuvm.PreferredRootFSTypeVHD {
+		opts.RootFSType = RootFSTypeVHD
+	}
+}
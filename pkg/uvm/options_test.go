@@ -0,0 +1,77 @@
+package uvm
+
+import (
+	"testing"
+
+	iuvm "github.com/Microsoft/hcsshim/internal/uvm"
+)
+
+func TestOptionsLCOWToInternal(t *testing.T) {
+	opts := NewDefaultOptionsLCOW(t.Name(), "owner")
+	opts.MemorySizeInMB = 2048
+	opts.ProcessorLimit = 5000
+	opts.RootFSFile = iuvm.VhdFile
+	opts.RootFSType = RootFSTypeVHD
+
+	iopts := opts.toInternal()
+	if iopts.ID != t.Name() || iopts.Owner != "owner" {
+		t.Fatalf("unexpected id/owner %q/%q", iopts.ID, iopts.Owner)
+	}
+	if iopts.MemorySizeInMB != 2048 {
+		t.Fatalf("expected memory 2048, got %d", iopts.MemorySizeInMB)
+	}
+	if iopts.ProcessorLimit != 5000 {
+		t.Fatalf("expected processor limit 5000, got %d", iopts.ProcessorLimit)
+	}
+	if iopts.RootFSFile != iuvm.VhdFile || iopts.PreferredRootFSType != iuvm.PreferredRootFSTypeVHD {
+		t.Fatalf("unexpected root fs %q/%v", iopts.RootFSFile, iopts.PreferredRootFSType)
+	}
+	if !iopts.UseGuestConnection {
+		t.Fatal("expected internal default UseGuestConnection to be preserved")
+	}
+}
+
+func TestOptionsWCOWToInternal(t *testing.T) {
+	opts := NewDefaultOptionsWCOW(t.Name(), "")
+	opts.LayerFolders = []string{`c:\layer`, `c:\scratch`}
+	opts.SaveStateFilePath = `c:\saved\uvm.vmrs`
+	opts.ConsolePipe = `\\.\pipe\vmpipe`
+	opts.ConsoleLogPath = `c:\logs\console.log`
+	opts.GuestCrashDumpPath = `c:\dumps\guest.dmp`
+	opts.NumaNodeCount = 2
+	opts.SCSIControllerCount = 2
+	opts.NumaMemorySizePerNodeInMB = 4096
+	opts.EnableColdHint = true
+	opts.HvSocketServiceTable = map[string]HvSocketServiceConfig{
+		"0c0a2a5f-8f5e-4a1a-9d5f-3f6a6c1e2b3d": {ConnectSecurityDescriptor: "D:P(A;;FA;;;SY)"},
+	}
+
+	iopts := opts.toInternal()
+	if iopts.Owner == "" {
+		t.Fatal("expected default owner to be set")
+	}
+	if len(iopts.LayerFolders) != 2 {
+		t.Fatalf("expected 2 layer folders, got %d", len(iopts.LayerFolders))
+	}
+	if iopts.SaveStateFilePath != opts.SaveStateFilePath {
+		t.Fatalf("expected saved state %s, got %s", opts.SaveStateFilePath, iopts.SaveStateFilePath)
+	}
+	if iopts.ConsolePipe != opts.ConsolePipe || iopts.ConsoleLogPath != opts.ConsoleLogPath {
+		t.Fatalf("expected console %s captured to %s, got %s captured to %s", opts.ConsolePipe, opts.ConsoleLogPath, iopts.ConsolePipe, iopts.ConsoleLogPath)
+	}
+	if iopts.SCSIControllerCount != opts.SCSIControllerCount {
+		t.Fatalf("expected %d SCSI controllers, got %d", opts.SCSIControllerCount, iopts.SCSIControllerCount)
+	}
+	if iopts.NumaNodeCount != opts.NumaNodeCount || iopts.NumaMemorySizePerNodeInMB != opts.NumaMemorySizePerNodeInMB {
+		t.Fatalf("expected %d NUMA nodes of %dMB, got %d of %dMB", opts.NumaNodeCount, opts.NumaMemorySizePerNodeInMB, iopts.NumaNodeCount, iopts.NumaMemorySizePerNodeInMB)
+	}
+	if !iopts.EnableHotHint || !iopts.EnableColdHint {
+		t.Fatalf("expected the default hot hint and the cold hint to be enabled, got %t/%t", iopts.EnableHotHint, iopts.EnableColdHint)
+	}
+	if iopts.GuestCrashDumpPath != opts.GuestCrashDumpPath {
+		t.Fatalf("expected guest crash dump %s, got %s", opts.GuestCrashDumpPath, iopts.GuestCrashDumpPath)
+	}
+	if service := iopts.HvSocketServiceTable["0c0a2a5f-8f5e-4a1a-9d5f-3f6a6c1e2b3d"]; service.ConnectSecurityDescriptor != "D:P(A;;FA;;;SY)" {
+		t.Fatalf("expected the HvSocket service to be configured, got %+v", iopts.HvSocketServiceTable)
+	}
+}
@@ -0,0 +1,244 @@
+// Package uvm provides a supported subset of the utility VM lifecycle for
+// consumers outside of hcsshim.
+//
+// Unlike the internal implementation this package follows semantic
+// versioning: exported types and functions will not change in a breaking way
+// within a major version of the module.
+package uvm
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/Microsoft/go-winio/pkg/guid"
+	hcsschema "github.com/Microsoft/hcsshim/internal/schema2"
+	iuvm "github.com/Microsoft/hcsshim/internal/uvm"
+)
+
+var (
+	// ErrNotAttached is returned when a resource is removed from, or queried
+	// on, a utility VM that it was never added to.
+	ErrNotAttached = iuvm.ErrNotAttached
+
+	// ErrNoAvailableLocation is the cause of the error returned when no SCSI
+	// location is free to attach a disk. The error lists the current
+	// attachments and their owners.
+	ErrNoAvailableLocation = iuvm.ErrNoAvailableLocation
+
+	// ErrNoGuestCrashDump is returned by `CaptureGuestCrashDump` if the guest
+	// has not crashed.
+	ErrNoGuestCrashDump = iuvm.ErrNoGuestCrashDump
+
+	// ErrNotSupported is returned when an operation is not supported on the
+	// operating system of the utility VM.
+	ErrNotSupported = errors.New("not supported")
+)
+
+// UtilityVM is a lightweight virtual machine hosting containers.
+type UtilityVM struct {
+	vm *iuvm.UtilityVM
+}
+
+// VSMBOptions are the set of options used when sharing a host directory with
+// a WCOW utility VM.
+type VSMBOptions struct {
+	// ReadOnly exposes the share read only to the utility VM.
+	ReadOnly bool
+
+	// CacheIO uses cached I/O for all opens on the share.
+	CacheIO bool
+
+	// ShareRead converts exclusive access to shared read access.
+	ShareRead bool
+
+	// NoDirectmap disables direct mapping of files on the share. Direct
+	// mapping causes file locking problems for some workloads.
+	NoDirectmap bool
+
+	// PseudoOplocks enables pseudo-oplocks on the share.
+	PseudoOplocks bool
+
+	// TakeBackupPrivilege acquires the backup privilege when opening files on
+	// the share.
+	TakeBackupPrivilege bool
+}
+
+// CreateLCOW creates a Linux utility VM. The utility VM must be started with
+// Start before it can be used.
+func CreateLCOW(opts *OptionsLCOW) (*UtilityVM, error) {
+	vm, err := iuvm.CreateLCOW(opts.toInternal())
+	if err != nil {
+		return nil, err
+	}
+	return &UtilityVM{vm: vm}, nil
+}
+
+// CreateWCOW creates a Windows utility VM. The utility VM must be started
+// with Start before it can be used.
+func CreateWCOW(opts *OptionsWCOW) (*UtilityVM, error) {
+	vm, err := iuvm.CreateWCOW(opts.toInternal())
+	if err != nil {
+		return nil, err
+	}
+	return &UtilityVM{vm: vm}, nil
+}
+
+// ID returns the ID of the utility VM.
+func (u *UtilityVM) ID() string {
+	return u.vm.ID()
+}
+
+// OS returns the operating system of the utility VM. Either "linux" or
+// "windows".
+func (u *UtilityVM) OS() string {
+	return u.vm.OS()
+}
+
+// Start boots the utility VM.
+func (u *UtilityVM) Start() error {
+	return u.vm.Start()
+}
+
+// Wait waits for the utility VM to exit and returns any error that occurred
+// during shutdown.
+func (u *UtilityVM) Wait() error {
+	return u.vm.Wait()
+}
+
+// Terminate requests that the utility VM be terminated. Use Wait to wait for
+// the termination to complete.
+func (u *UtilityVM) Terminate() error {
+	return u.vm.Terminate()
+}
+
+// Close terminates and releases all resources associated with the utility VM.
+func (u *UtilityVM) Close() error {
+	return u.vm.Close()
+}
+
+// AddSCSI attaches the VHD at `hostPath` to the utility VM. For LCOW the disk
+// is mounted in the guest at `uvmPath` when it is not empty. The returned
+// controller and LUN identify the attachment location.
+func (u *UtilityVM) AddSCSI(hostPath, uvmPath string, readOnly bool) (controller int, lun int32, err error) {
+	return u.vm.AddSCSI(hostPath, &iuvm.SCSIOptions{UVMPath: uvmPath, ReadOnly: readOnly})
+}
+
+// RemoveSCSI detaches the VHD at `hostPath` from the utility VM.
+func (u *UtilityVM) RemoveSCSI(hostPath string) error {
+	return u.vm.RemoveSCSI(hostPath)
+}
+
+// SCSIGuestPath returns the path in the utility VM at which the VHD at
+// `hostPath` is mounted.
+func (u *UtilityVM) SCSIGuestPath(hostPath string) (string, error) {
+	return u.vm.GetScsiUvmPath(hostPath)
+}
+
+// AddVSMB shares the host directory at `hostPath` with a WCOW utility VM.
+// Shares are reference counted so adding the same `hostPath` more than once
+// requires a matching number of calls to RemoveVSMB.
+func (u *UtilityVM) AddVSMB(hostPath string, opts *VSMBOptions) error {
+	if u.vm.OS() != "windows" {
+		return ErrNotSupported
+	}
+	options := &hcsschema.VirtualSmbShareOptions{}
+	if opts != nil {
+		options.ReadOnly = opts.ReadOnly
+		options.CacheIo = opts.CacheIO
+		options.ShareRead = opts.ShareRead
+		options.NoDirectmap = opts.NoDirectmap
+		options.PseudoOplocks = opts.PseudoOplocks
+		options.TakeBackupPrivilege = opts.TakeBackupPrivilege
+	}
+	return u.vm.AddVSMB(hostPath, nil, options)
+}
+
+// RemoveVSMB removes a reference to the share of `hostPath` from the utility
+// VM, removing the share when the last reference is released.
+func (u *UtilityVM) RemoveVSMB(hostPath string) error {
+	if u.vm.OS() != "windows" {
+		return ErrNotSupported
+	}
+	return u.vm.RemoveVSMB(hostPath)
+}
+
+// VSMBGuestPath returns the path in the utility VM at which the share of
+// `hostPath` is accessible.
+func (u *UtilityVM) VSMBGuestPath(hostPath string) (string, error) {
+	if u.vm.OS() != "windows" {
+		return "", ErrNotSupported
+	}
+	return u.vm.GetVSMBUvmPath(hostPath)
+}
+
+// ProcessorCount returns the number of vCPU's actually assigned to the
+// utility VM.
+func (u *UtilityVM) ProcessorCount() int32 {
+	return u.vm.ProcessorCount()
+}
+
+// MemorySizeInMB returns the memory actually assigned to the utility VM.
+func (u *UtilityVM) MemorySizeInMB() int32 {
+	return u.vm.MemorySizeInMB()
+}
+
+// UpdateMemory changes the memory assigned to a running utility VM.
+func (u *UtilityVM) UpdateMemory(sizeInMB int32) error {
+	return u.vm.UpdateMemory(sizeInMB)
+}
+
+// UpdateProcessor changes the vCPU limit and weight of a running utility VM.
+// A value of `0` leaves the corresponding setting unchanged.
+func (u *UtilityVM) UpdateProcessor(limit, weight int32) error {
+	return u.vm.UpdateProcessor(limit, weight)
+}
+
+// UpdateProcessorCount hot adds or removes vCPUs of a running utility VM so
+// that it has `count` vCPUs. This is only supported on builds where
+// `ProcessorHotAddSupported` returns `true`.
+func (u *UtilityVM) UpdateProcessorCount(count int32) error {
+	return u.vm.UpdateProcessorCount(count)
+}
+
+// ProcessorHotAddSupported returns `true` if vCPUs can be added to and removed
+// from the running utility VM.
+func (u *UtilityVM) ProcessorHotAddSupported() bool {
+	return u.vm.ProcessorHotAddSupported()
+}
+
+// Save pauses the running Windows utility VM and saves its state to the file
+// `path`. The utility VM is left paused and is expected to be closed
+// afterwards. It is restored by creating it with
+// `OptionsWCOW.SaveStateFilePath` set to `path`.
+func (u *UtilityVM) Save(path string) error {
+	return u.vm.Save(path)
+}
+
+// CaptureGuestCrashDump copies the crash dump written by the guest when it
+// crashed to the file `destPath`. The utility VM MUST have been created with
+// `GuestCrashDumpPath` set.
+func (u *UtilityVM) CaptureGuestCrashDump(destPath string) error {
+	return u.vm.CaptureGuestCrashDump(destPath)
+}
+
+// ListenHvSocket registers the HvSocket service `serviceID` with the utility
+// VM and listens on it for connections from the guest. The registration is
+// released when the listener is closed.
+func (u *UtilityVM) ListenHvSocket(serviceID guid.GUID) (net.Listener, error) {
+	return u.vm.ListenHvSocket(serviceID)
+}
+
+// DialHvSocket registers the HvSocket service `serviceID` with the utility VM
+// and connects to the guest listening on it. The registration is released
+// when the connection is closed.
+func (u *UtilityVM) DialHvSocket(ctx context.Context, serviceID guid.GUID) (net.Conn, error) {
+	return u.vm.DialHvSocket(ctx, serviceID)
+}
+
+// AvailableSCSILocations returns the number of SCSI locations of the utility
+// VM that are free to attach a disk to, so that callers can fail fast or fall
+// back to another means of sharing storage when none are left.
+func (u *UtilityVM) AvailableSCSILocations() int {
+	return u.vm.AvailableSCSILocations()
+}
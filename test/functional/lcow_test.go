@@ -141,7 +141,7 @@ func TestLCOWSimplePodScenario(t *testing.T) {
 	if err := lcow.CreateScratch(lcowUVM, uvmScratchFile, lcow.DefaultScratchSizeGB, cacheFile); err != nil {
 		t.Fatal(err)
 	}
-	if _, _, err := lcowUVM.AddSCSI(uvmScratchFile, `/tmp/scratch`, false); err != nil {
+	if _, _, err := lcowUVM.AddSCSI(uvmScratchFile, &uvm.SCSIOptions{UVMPath: `/tmp/scratch`}); err != nil {
 		t.Fatal(err)
 	}
 
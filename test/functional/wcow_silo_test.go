@@ -0,0 +1,96 @@
+// +build functional wcow
+
+package functional
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Microsoft/hcsshim/internal/cow"
+	"github.com/Microsoft/hcsshim/internal/hcsoci"
+	"github.com/Microsoft/hcsshim/internal/jobobject"
+	"github.com/Microsoft/hcsshim/internal/schemaversion"
+	"github.com/Microsoft/hcsshim/internal/wclayer"
+	"github.com/Microsoft/hcsshim/osversion"
+	testutilities "github.com/Microsoft/hcsshim/test/functional/utilities"
+)
+
+// startArgonSilo starts a process isolated container `id` and returns it
+// along with a function that stops it and releases its resources.
+func startArgonSilo(t *testing.T, id string) (cow.Container, func()) {
+	imageLayers := testutilities.LayerFolders(t, imageName)
+	scratchDir := testutilities.CreateTempDir(t)
+	if err := wclayer.CreateScratchLayer(scratchDir, imageLayers); err != nil {
+		os.RemoveAll(scratchDir)
+		t.Fatalf("failed to create argon scratch layer: %s", err)
+	}
+	hostRWSharedDirectory, hostROSharedDirectory := createTestMounts(t)
+	cleanupDirs := func() {
+		os.RemoveAll(scratchDir)
+		os.RemoveAll(hostRWSharedDirectory)
+		os.RemoveAll(hostROSharedDirectory)
+	}
+
+	spec := generateWCOWOciTestSpec(t, imageLayers, scratchDir, hostRWSharedDirectory, hostROSharedDirectory)
+	c, resources, err := hcsoci.CreateContainer(
+		&hcsoci.CreateOptions{
+			ID:            id,
+			SchemaVersion: schemaversion.SchemaV21(),
+			Spec:          spec,
+		})
+	if err != nil {
+		cleanupDirs()
+		t.Fatal(err)
+	}
+	cleanup := func() {
+		c.Terminate()
+		c.Close()
+		hcsoci.ReleaseResources(resources, nil, true)
+		cleanupDirs()
+	}
+	if err := c.Start(); err != nil {
+		cleanup()
+		t.Fatalf("Failed start: %s", err)
+	}
+	return c, cleanup
+}
+
+// Opens the silo of a running process isolated container by the name the
+// shim uses to update its resources.
+func TestWCOWArgonSiloJobObject(t *testing.T) {
+	testutilities.RequiresBuild(t, osversion.RS5)
+	_, cleanup := startArgonSilo(t, "argonSilo")
+	defer cleanup()
+
+	job, err := jobobject.Open(jobobject.SiloName("argonSilo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer job.Close()
+	if err := job.SetLimits(jobobject.Limits{CPURate: jobobject.CPURateMax / 2}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Freezes and thaws the silo of a running process isolated container as the
+// shim does to pause and resume it.
+func TestWCOWArgonSiloFreeze(t *testing.T) {
+	testutilities.RequiresBuild(t, osversion.RS5)
+	_, cleanup := startArgonSilo(t, "argonSiloFreeze")
+	defer cleanup()
+
+	job, err := jobobject.Open(jobobject.SiloName("argonSiloFreeze"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer job.Close()
+	if err := job.Freeze(); err != nil {
+		if err == jobobject.ErrNotSupported {
+			t.Skip(err)
+		}
+		t.Fatal(err)
+	}
+	if err := job.Thaw(); err != nil {
+		t.Fatal(err)
+	}
+}